@@ -5,6 +5,7 @@ import numpy as np
 import noise
 import json
 from create_npc import handle_generate_npc
+from create_pc import handle_generate_pc, PCGenerationUnavailable
 from create_encounter import handle_generate_encounter
 from loot_generator import handle_generate_items
 
@@ -57,6 +58,18 @@ def generate_npc_api():
     
     return jsonify(npc_data)
 
+@app.route('/generate-pc', methods=['POST'])
+def generate_pc_api():
+    data = request.json
+
+    # O backend completa a ficha (espaços de magia) e, com 503, monta o PC sozinho
+    try:
+        pc_data = handle_generate_pc(data)
+    except PCGenerationUnavailable as e:
+        return jsonify({"error": str(e)}), 503
+
+    return jsonify(pc_data)
+
 @app.route('/generate-encounter', methods=['POST'])
 def generate_encounter_api():
     data = request.json
@@ -0,0 +1,32 @@
+#!/usr/bin/env python3
+from create_npc import handle_generate_npc
+
+
+class PCGenerationUnavailable(Exception):
+    """A API do D&D não respondeu e só o gerador fallback de NPCs estaria disponível."""
+
+
+def handle_generate_pc(request_data):
+    """Gera a ficha de um PC para o backend.
+
+    A montagem (raça, classe, atributos, equipamento e magias) é a mesma dos NPCs, mas um PC
+    não pode sair do gerador fallback de NPCs: a ficha "(Fallback)" tem equipamento e
+    características fictícios. Nesse caso levanta PCGenerationUnavailable para que o backend
+    monte a ficha com as próprias regras. Os modificadores ficam de fora, pois o backend os
+    recalcula, e o dono da ficha é repassado em player_name.
+    """
+    data = dict(request_data or {})
+    data["level"] = min(max(int(data.get("level") or 1), 1), 20)
+
+    # Raça, classe e antecedente só valem no modo manual; fora dele o sorteio é livre
+    if not data.get("manual"):
+        for key in ("race", "class", "background"):
+            data.pop(key, None)
+
+    pc = handle_generate_npc(data)
+    if pc.get("name", "").endswith("(Fallback)"):
+        raise PCGenerationUnavailable("D&D API unavailable")
+
+    pc.pop("modifiers", None)
+    pc["player_name"] = data.get("player_name", "")
+    return pc
@@ -256,6 +256,7 @@ type Class struct {
 	SavingThrows  []struct {
 		Name string `json:"name"`
 	} `json:"saving_throws"`
+	ProficiencyChoices  json.RawMessage `json:"proficiency_choices"`
 	Spellcasting        json.RawMessage `json:"spellcasting"`
 	SpellcastingAbility struct {
 		Name string `json:"name"`
@@ -1175,6 +1176,14 @@ func importClasses(ctx context.Context, client *DNDClient, dbClient *db.Postgres
 			continue
 		}
 
+		// class_levels no detalhe da classe é só o link; a progressão vem de /levels
+		var levels json.RawMessage
+		if err := client.get("/classes/"+item.Index+"/levels", &levels); err != nil {
+			log.Printf("⚠️ Failed to fetch levels for class %s: %v", item.Index, err)
+		} else {
+			class.ClassLevels = levels
+		}
+
 		if err := insertClass(ctx, dbClient, &class); err != nil {
 			log.Printf("⚠️ Failed to insert class %s: %v", item.Index, err)
 			continue
@@ -1201,6 +1210,13 @@ func insertClass(ctx context.Context, dbClient *db.PostgresDB, class *Class) err
 		}
 	}
 
+	proficiencyChoicesJSON := []byte("[]")
+	if len(class.ProficiencyChoices) > 0 {
+		if json.Valid(class.ProficiencyChoices) {
+			proficiencyChoicesJSON = class.ProficiencyChoices
+		}
+	}
+
 	spellcastingJSON := []byte("{}")
 	if len(class.Spellcasting) > 0 {
 		if json.Valid(class.Spellcasting) {
@@ -1217,17 +1233,19 @@ func insertClass(ctx context.Context, dbClient *db.PostgresDB, class *Class) err
 
 	query := `
 		INSERT INTO dnd_classes (
-			api_index, name, hit_die, proficiencies, saving_throws, spellcasting, spellcasting_ability, class_levels
+			api_index, name, hit_die, proficiencies, saving_throws, proficiency_choices, spellcasting, spellcasting_ability, class_levels
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8
+			$1, $2, $3, $4, $5, $6, $7, $8, $9
 		) ON CONFLICT (api_index) DO UPDATE SET
 			name = EXCLUDED.name,
+			proficiency_choices = EXCLUDED.proficiency_choices,
+			class_levels = EXCLUDED.class_levels,
 			updated_at = CURRENT_TIMESTAMP
 	`
 
 	_, err := dbClient.DB.ExecContext(ctx, query,
 		class.Index, class.Name, class.HitDie, proficienciesJSON, pq.Array(savingThrows),
-		spellcastingJSON, class.SpellcastingAbility.Name, classLevelsJSON,
+		proficiencyChoicesJSON, spellcastingJSON, class.SpellcastingAbility.Name, classLevelsJSON,
 	)
 
 	return err
@@ -118,7 +118,7 @@ func main() {
 
 	// Configura as rotas
 	log.Println("Setting up routes...")
-	router := api.SetupRoutes(dbClient, pythonClient)
+	router := api.SetupRoutes(dbClient, pythonClient, nil)
 
 	// Configura o servidor HTTP
 	port := getEnv("PORT", "8080")
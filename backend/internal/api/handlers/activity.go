@@ -0,0 +1,270 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"rpg-saas-backend/internal/db"
+	"rpg-saas-backend/internal/models"
+	"rpg-saas-backend/internal/utils"
+)
+
+// activityKeepAlive é o intervalo dos comentários que mantêm o stream aberto em proxies
+const activityKeepAlive = 25 * time.Second
+
+// ActivityLog grava os eventos do feed de atividade das campanhas e os repassa aos inscritos
+// no stream ao vivo. É compartilhado pelos handlers de campanha e de sala; um ActivityLog nil
+// não grava nada.
+type ActivityLog struct {
+	DB          *db.PostgresDB
+	mu          sync.RWMutex
+	subscribers map[int]map[chan models.CampaignActivity]struct{}
+}
+
+func NewActivityLog(db *db.PostgresDB) *ActivityLog {
+	return &ActivityLog{
+		DB:          db,
+		subscribers: make(map[int]map[chan models.CampaignActivity]struct{}),
+	}
+}
+
+// Record grava o evento e o publica no stream da campanha. A gravação é best-effort: a ação
+// do usuário já foi concluída, então falhas só vão para o log.
+func (l *ActivityLog) Record(ctx context.Context, activity models.CampaignActivity) {
+	if l == nil {
+		return
+	}
+
+	if err := l.DB.RecordCampaignActivity(ctx, &activity); err != nil {
+		log.Printf("failed to record campaign activity: %v", err)
+		return
+	}
+
+	l.publish(activity)
+}
+
+// Subscribe inscreve um ouvinte no stream da campanha. cancel deve ser chamado ao sair.
+func (l *ActivityLog) Subscribe(campaignID int) (events <-chan models.CampaignActivity, cancel func()) {
+	ch := make(chan models.CampaignActivity, 16)
+
+	l.mu.Lock()
+	if _, ok := l.subscribers[campaignID]; !ok {
+		l.subscribers[campaignID] = make(map[chan models.CampaignActivity]struct{})
+	}
+	l.subscribers[campaignID][ch] = struct{}{}
+	l.mu.Unlock()
+
+	return ch, func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		delete(l.subscribers[campaignID], ch)
+		if len(l.subscribers[campaignID]) == 0 {
+			delete(l.subscribers, campaignID)
+		}
+	}
+}
+
+// publish entrega o evento sem bloquear: ouvintes lentos perdem eventos e podem recuperá-los
+// pelo feed paginado
+func (l *ActivityLog) publish(activity models.CampaignActivity) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	for ch := range l.subscribers[activity.CampaignID] {
+		select {
+		case ch <- activity:
+		default:
+		}
+	}
+}
+
+// GetCampaignActivity lista o feed de atividade da campanha com filtros e paginação (DM ou co-DM)
+func (h *CampaignHandler) GetCampaignActivity(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	if !canManageCampaign(campaign, userID) {
+		h.Response.SendForbidden(w, "Only the DM can view the campaign activity")
+		return
+	}
+
+	filter, ok := parseActivityFilter(w, r, h.Response)
+	if !ok {
+		return
+	}
+
+	pagination := utils.ExtractPagination(r, 50)
+	activity, total, err := h.DB.GetCampaignActivity(r.Context(), campaign.ID, filter, pagination.Limit, pagination.Offset)
+	if err != nil {
+		h.Response.HandleDBError(w, err, "fetch campaign activity")
+		return
+	}
+
+	h.Response.SendJSON(w, map[string]any{
+		"activity": activity,
+		"count":    len(activity),
+		"total":    total,
+		"limit":    pagination.Limit,
+		"offset":   pagination.Offset,
+	}, http.StatusOK)
+}
+
+// StreamCampaignActivity envia os novos eventos da campanha como Server-Sent Events, com os
+// mesmos filtros do feed (DM ou co-DM)
+func (h *CampaignHandler) StreamCampaignActivity(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	if !canManageCampaign(campaign, userID) {
+		h.Response.SendForbidden(w, "Only the DM can view the campaign activity")
+		return
+	}
+
+	filter, ok := parseActivityFilter(w, r, h.Response)
+	if !ok {
+		return
+	}
+
+	if h.Activity == nil {
+		h.Response.SendError(w, "Activity stream unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	events, cancel := h.Activity.Subscribe(campaign.ID)
+	defer cancel()
+
+	// O stream fica aberto além do WriteTimeout do servidor
+	rc := http.NewResponseController(w)
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, ": connected\n\n")
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	keepAlive := time.NewTicker(activityKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": ping\n\n")
+		case activity := <-events:
+			if !filter.Matches(activity) {
+				continue
+			}
+			data, err := json.Marshal(activity)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", activity.ID, activity.EventType, data)
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// parseActivityFilter lê os filtros do feed: type (lista separada por vírgulas de tipos ou
+// categorias), actor_id, target_type, target_id, since e until (RFC 3339). Em caso de erro,
+// a resposta já foi enviada e ok é false.
+func parseActivityFilter(w http.ResponseWriter, r *http.Request, response *utils.ResponseHandler) (filter models.ActivityFilter, ok bool) {
+	query := r.URL.Query()
+
+	for _, t := range strings.Split(query.Get("type"), ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			filter.Types = append(filter.Types, t)
+		}
+	}
+
+	if raw := query.Get("actor_id"); raw != "" {
+		actorID, err := strconv.Atoi(raw)
+		if err != nil {
+			response.SendBadRequest(w, "Invalid actor_id")
+			return filter, false
+		}
+		filter.ActorID = &actorID
+	}
+
+	filter.TargetType = query.Get("target_type")
+	filter.TargetID = query.Get("target_id")
+
+	for name, dest := range map[string]**time.Time{"since": &filter.Since, "until": &filter.Until} {
+		raw := query.Get(name)
+		if raw == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			response.SendBadRequest(w, fmt.Sprintf("Invalid %s: use RFC 3339 (e.g. 2024-05-01T18:00:00Z)", name))
+			return filter, false
+		}
+		*dest = &parsed
+	}
+
+	return filter, true
+}
+
+// activityTarget formata o ID do alvo de um evento
+func activityTarget(id int) string {
+	return strconv.Itoa(id)
+}
+
+// recordCharacterActivity registra as alterações de um personagem da campanha. Mudanças de
+// status e de PV viram eventos próprios, para que o feed possa filtrá-las; edited registra
+// também a edição da ficha.
+func (h *CampaignHandler) recordCharacterActivity(ctx context.Context, userID int, character *models.CampaignCharacter, prevStatus string, prevHP *int, edited bool) {
+	base := models.CampaignActivity{
+		CampaignID: character.CampaignID,
+		ActorID:    &userID,
+		TargetType: models.ActivityTargetCharacter,
+		TargetID:   activityTarget(character.ID),
+	}
+
+	if character.Status != prevStatus {
+		activity := base
+		activity.EventType = models.ActivityCharacterStatusChanged
+		activity.Summary = fmt.Sprintf("%s is now %s", character.Name, character.Status)
+		activity.Metadata = models.JSONB{"from": prevStatus, "to": character.Status}
+		h.Activity.Record(ctx, activity)
+	}
+
+	if hpChanged(prevHP, character.CurrentHP) {
+		activity := base
+		activity.EventType = models.ActivityCharacterHPChanged
+		activity.Summary = fmt.Sprintf("%s HP changed", character.Name)
+		activity.Metadata = models.JSONB{"from": prevHP, "to": character.CurrentHP, "max": character.HP}
+		h.Activity.Record(ctx, activity)
+	}
+
+	if edited {
+		activity := base
+		activity.EventType = models.ActivityCharacterUpdated
+		activity.Summary = fmt.Sprintf("Edited %s", character.Name)
+		h.Activity.Record(ctx, activity)
+	}
+}
+
+// hpChanged compara dois valores de PV atual, em que nil significa não informado
+func hpChanged(before, after *int) bool {
+	if before == nil || after == nil {
+		return before != after
+	}
+	return *before != *after
+}
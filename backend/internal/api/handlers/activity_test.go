@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+
+	"rpg-saas-backend/internal/models"
+)
+
+var campaignActivityCols = []string{
+	"id", "campaign_id", "actor_id", "actor_name", "event_type", "target_type", "target_id", "summary", "metadata", "created_at",
+}
+
+func TestCampaignHandler_GetCampaignActivity(t *testing.T) {
+	t.Run("players can't read the feed", func(t *testing.T) {
+		handler, mock, cleanup := newMockCampaignHandler(t)
+		defer cleanup()
+
+		expectCampaignAccess(mock, 10, 8, 7, 8)
+
+		req := withCampaignUser(httptest.NewRequest(http.MethodGet, "/api/campaigns/10/activity", nil), "10", 8)
+		rr := httptest.NewRecorder()
+		handler.GetCampaignActivity(rr, req)
+
+		if rr.Code != http.StatusForbidden {
+			t.Fatalf("expected 403, got %d: %s", rr.Code, rr.Body.String())
+		}
+	})
+
+	t.Run("invalid since is rejected", func(t *testing.T) {
+		handler, mock, cleanup := newMockCampaignHandler(t)
+		defer cleanup()
+
+		expectCampaignAccess(mock, 10, 7, 7, 8)
+
+		req := withCampaignUser(httptest.NewRequest(http.MethodGet, "/api/campaigns/10/activity?since=yesterday", nil), "10", 7)
+		rr := httptest.NewRecorder()
+		handler.GetCampaignActivity(rr, req)
+
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d: %s", rr.Code, rr.Body.String())
+		}
+	})
+
+	t.Run("DM filters by type and actor", func(t *testing.T) {
+		handler, mock, cleanup := newMockCampaignHandler(t)
+		defer cleanup()
+
+		expectCampaignAccess(mock, 10, 7, 7, 8)
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FROM campaign_activity`).WithArgs(10, `{"character"}`, 8).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectQuery(`FROM campaign_activity a`).WithArgs(10, `{"character"}`, 8, 20, 0).
+			WillReturnRows(sqlmock.NewRows(campaignActivityCols).
+				AddRow(3, 10, 8, "bob", models.ActivityCharacterHPChanged, "character", "5", "Aria HP changed", []byte(`{"from":12,"to":7}`), time.Now()))
+
+		req := withCampaignUser(httptest.NewRequest(http.MethodGet, "/api/campaigns/10/activity?type=character&actor_id=8&limit=20", nil), "10", 7)
+		rr := httptest.NewRecorder()
+		handler.GetCampaignActivity(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		var body struct {
+			Activity []models.CampaignActivity `json:"activity"`
+			Total    int                       `json:"total"`
+		}
+		if err := json.NewDecoder(rr.Body).Decode(&body); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if body.Total != 1 || len(body.Activity) != 1 || body.Activity[0].EventType != models.ActivityCharacterHPChanged {
+			t.Fatalf("unexpected feed: %+v", body)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+}
+
+func TestCampaignHandler_HPChangeIsRecordedAndStreamed(t *testing.T) {
+	handler, mock, cleanup := newMockCampaignHandler(t)
+	defer cleanup()
+	handler.Activity = NewActivityLog(handler.DB)
+
+	events, cancel := handler.Activity.Subscribe(10)
+	defer cancel()
+
+	expectCampaignCharacterRow(mock, 5, 10, 8, "active")
+	mock.ExpectBegin()
+	expectVersionHistory(mock, models.CharacterTypeCampaign, 5)
+	mock.ExpectExec(`UPDATE campaign_characters SET`).WithArgs(7, "active", "", 5, 10).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectCharacterVersionRecorded(mock, 5, 10)
+	mock.ExpectCommit()
+	mock.ExpectQuery(`INSERT INTO campaign_activity`).
+		WithArgs(10, 8, models.ActivityCharacterHPChanged, models.ActivityTargetCharacter, "5", "Aria HP changed", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "actor_name"}).AddRow(50, time.Now(), "bob"))
+
+	req := withCampaignUser(httptest.NewRequest(http.MethodPut, "/api/campaigns/10/characters/5", bytes.NewBufferString(`{"current_hp":7}`)), "10", 8)
+	req = addChiURLParam(req, "characterId", "5")
+	rr := httptest.NewRecorder()
+	handler.UpdateCampaignCharacter(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+
+	select {
+	case activity := <-events:
+		if activity.ID != 50 || activity.EventType != models.ActivityCharacterHPChanged || activity.ActorName != "bob" {
+			t.Fatalf("unexpected streamed activity: %+v", activity)
+		}
+	default:
+		t.Fatalf("expected the HP change to be streamed")
+	}
+}
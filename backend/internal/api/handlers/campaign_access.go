@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"net/http"
+
+	"rpg-saas-backend/internal/db"
+	"rpg-saas-backend/internal/models"
+	"rpg-saas-backend/internal/utils"
+)
+
+// loadCampaignForUser extrai o usuário e o {id} da campanha e verifica o acesso
+// (DM ou player ativo). Em caso de erro, a resposta já foi enviada e ok é false.
+func loadCampaignForUser(w http.ResponseWriter, r *http.Request, database *db.PostgresDB, response *utils.ResponseHandler) (campaign *models.Campaign, userID int, ok bool) {
+	userID, err := utils.ExtractUserID(r)
+	if err != nil {
+		response.SendInternalError(w, "User ID not found in context")
+		return nil, 0, false
+	}
+
+	campaignID, err := utils.ExtractID(r)
+	if err != nil {
+		response.SendBadRequest(w, "Invalid campaign ID")
+		return nil, 0, false
+	}
+
+	campaign, err = database.GetCampaignByID(r.Context(), campaignID, userID)
+	if err != nil {
+		response.SendNotFound(w, "Campaign not found or access denied")
+		return nil, 0, false
+	}
+
+	return campaign, userID, true
+}
+
+// isCampaignDM indica se o usuário é o mestre da campanha
+func isCampaignDM(campaign *models.Campaign, userID int) bool {
+	return campaign.DMID == userID
+}
+
+// canManageCampaign indica se o usuário pode editar o conteúdo da campanha: o DM ou um co-DM
+// ativo. Exclusões e gestão de membros continuam restritas ao DM (isCampaignDM).
+func canManageCampaign(campaign *models.Campaign, userID int) bool {
+	if isCampaignDM(campaign, userID) {
+		return true
+	}
+	for _, player := range campaign.Players {
+		if player.UserID == userID {
+			return player.Status == models.PlayerStatusActive && player.Role == models.PlayerRoleCoDM
+		}
+	}
+	return false
+}
+
+// loadManagedCampaign carrega uma campanha informada fora da URL (ex.: campaign_id no corpo)
+// e exige DM ou co-DM. Em caso de erro, a resposta já foi enviada e ok é false.
+func loadManagedCampaign(w http.ResponseWriter, r *http.Request, database *db.PostgresDB, response *utils.ResponseHandler, campaignID int) (campaign *models.Campaign, ok bool) {
+	userID, err := utils.ExtractUserID(r)
+	if err != nil {
+		response.SendInternalError(w, "User ID not found in context")
+		return nil, false
+	}
+
+	campaign, err = database.GetCampaignByID(r.Context(), campaignID, userID)
+	if err != nil {
+		response.SendNotFound(w, "Campaign not found or access denied")
+		return nil, false
+	}
+
+	if !canManageCampaign(campaign, userID) {
+		response.SendForbidden(w, "Only the DM can add content to the campaign")
+		return nil, false
+	}
+
+	return campaign, true
+}
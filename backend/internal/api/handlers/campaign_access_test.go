@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+
+	"rpg-saas-backend/internal/api/middleware"
+	"rpg-saas-backend/internal/db"
+	"rpg-saas-backend/internal/utils"
+)
+
+var campaignAccessCols = []string{
+	"id", "name", "description", "dm_id", "max_players", "current_session",
+	"status", "allow_homebrew", "leveling_mode", "invite_code", "created_at", "updated_at",
+}
+
+// expectCampaignAccess simula GetCampaignByID (campanha, players ativos e personagens vazios)
+func expectCampaignAccess(mock sqlmock.Sqlmock, campaignID, userID, dmID int, playerIDs ...int) {
+	expectCampaignAccessWithCoDMs(mock, campaignID, userID, dmID, nil, playerIDs...)
+}
+
+// expectCampaignAccessWithCoDMs é como expectCampaignAccess, mas marca os players em coDMs como co-DM
+func expectCampaignAccessWithCoDMs(mock sqlmock.Sqlmock, campaignID, userID, dmID int, coDMs []int, playerIDs ...int) {
+	expectCampaignAccessWithMode(mock, "xp", campaignID, userID, dmID, coDMs, playerIDs...)
+}
+
+// expectCampaignAccessWithMode é como expectCampaignAccessWithCoDMs, com o modo de progressão informado
+func expectCampaignAccessWithMode(mock sqlmock.Sqlmock, levelingMode string, campaignID, userID, dmID int, coDMs []int, playerIDs ...int) {
+	expectCampaignAccessRow(mock, "active", levelingMode, campaignID, userID, dmID, coDMs, playerIDs...)
+}
+
+// expectCampaignAccessWithStatus é como expectCampaignAccess, com o status da campanha informado
+func expectCampaignAccessWithStatus(mock sqlmock.Sqlmock, status string, campaignID, userID, dmID int, playerIDs ...int) {
+	expectCampaignAccessRow(mock, status, "xp", campaignID, userID, dmID, nil, playerIDs...)
+}
+
+func expectCampaignAccessRow(mock sqlmock.Sqlmock, status, levelingMode string, campaignID, userID, dmID int, coDMs []int, playerIDs ...int) {
+	now := time.Now()
+	mock.ExpectQuery(`FROM campaigns c`).WithArgs(campaignID, userID).
+		WillReturnRows(sqlmock.NewRows(campaignAccessCols).
+			AddRow(campaignID, "Campaign", "desc", dmID, 5, 2, status, false, levelingMode, "CODE1234", now, now))
+
+	players := sqlmock.NewRows([]string{"id", "campaign_id", "user_id", "joined_at", "status", "username", "email", "role"})
+	for i, playerID := range playerIDs {
+		role := "player"
+		for _, coDM := range coDMs {
+			if coDM == playerID {
+				role = "co_dm"
+			}
+		}
+		players.AddRow(i+1, campaignID, playerID, now, "active", "player", "player@example.com", role)
+	}
+	mock.ExpectQuery(`FROM campaign_players`).WithArgs(campaignID).WillReturnRows(players)
+
+	mock.ExpectQuery(`FROM campaign_characters`).WithArgs(campaignID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+}
+
+// expectCampaignAccessDenied simula GetCampaignByID para um usuário sem acesso
+func expectCampaignAccessDenied(mock sqlmock.Sqlmock, campaignID, userID int) {
+	mock.ExpectQuery(`FROM campaigns c`).WithArgs(campaignID, userID).
+		WillReturnRows(sqlmock.NewRows(campaignAccessCols))
+}
+
+// withCampaignUser adiciona o usuário autenticado e o {id} da campanha à requisição
+func withCampaignUser(req *http.Request, campaignID string, userID int) *http.Request {
+	req = addChiURLParam(req, "id", campaignID)
+	return req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+}
+
+func TestLoadCampaignForUser(t *testing.T) {
+	rawDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer rawDB.Close()
+	pdb := &db.PostgresDB{DB: sqlx.NewDb(rawDB, "postgres")}
+	response := utils.NewResponseHandler()
+
+	expectCampaignAccess(mock, 10, 7, 7, 8)
+	req := withCampaignUser(httptest.NewRequest(http.MethodGet, "/api/campaigns/10", nil), "10", 7)
+	rr := httptest.NewRecorder()
+	campaign, userID, ok := loadCampaignForUser(rr, req, pdb, response)
+	if !ok || userID != 7 || !isCampaignDM(campaign, userID) || !isActiveCampaignPlayer(campaign, 8) {
+		t.Fatalf("expected DM access with one player, got %+v %d %v", campaign, userID, ok)
+	}
+
+	expectCampaignAccessDenied(mock, 10, 9)
+	req = withCampaignUser(httptest.NewRequest(http.MethodGet, "/api/campaigns/10", nil), "10", 9)
+	rr = httptest.NewRecorder()
+	if _, _, ok := loadCampaignForUser(rr, req, pdb, response); ok || rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for outsider, got %d", rr.Code)
+	}
+
+	req = withCampaignUser(httptest.NewRequest(http.MethodGet, "/api/campaigns/abc", nil), "abc", 7)
+	rr = httptest.NewRecorder()
+	if _, _, ok := loadCampaignForUser(rr, req, pdb, response); ok || rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid id, got %d", rr.Code)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
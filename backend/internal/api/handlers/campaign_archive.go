@@ -0,0 +1,275 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"rpg-saas-backend/internal/models"
+	"rpg-saas-backend/internal/utils"
+)
+
+// ExportCampaign gera o pacote JSON versionado da campanha para backup ou migração (apenas DM)
+func (h *CampaignHandler) ExportCampaign(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	if !isCampaignDM(campaign, userID) {
+		h.Response.SendForbidden(w, "Only the DM can export the campaign")
+		return
+	}
+
+	archive, err := h.buildCampaignArchive(r.Context(), campaign)
+	if err != nil {
+		h.Response.HandleDBError(w, err, "export campaign")
+		return
+	}
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="campaign-%d-v%d.json"`, campaign.ID, archive.Version))
+	h.Response.SendJSON(w, archive, http.StatusOK)
+}
+
+// ImportCampaign cria uma campanha nova a partir de um pacote exportado. Quem importa vira o
+// DM; IDs são remapeados e referências ausentes nesta instância são listadas no relatório.
+func (h *CampaignHandler) ImportCampaign(w http.ResponseWriter, r *http.Request) {
+	userID, err := utils.ExtractUserID(r)
+	if err != nil {
+		h.Response.SendInternalError(w, "User ID not found in context")
+		return
+	}
+
+	var archive models.CampaignArchive
+	if err := json.NewDecoder(r.Body).Decode(&archive); err != nil {
+		h.Response.SendBadRequest(w, "Invalid campaign archive")
+		return
+	}
+
+	if archive.Version < 1 || archive.Version > models.CampaignArchiveVersion {
+		h.Response.SendValidationError(w, fmt.Sprintf("unsupported archive version %d (supported: 1-%d)", archive.Version, models.CampaignArchiveVersion))
+		return
+	}
+
+	h.createCampaignFromArchive(w, r, userID, &archive, models.CampaignCopyRequest{}, "Campaign imported successfully")
+}
+
+// createCampaignFromArchive cria a campanha de userID a partir do pacote, aplicando os
+// ajustes de req, e responde com a campanha e o relatório da importação
+func (h *CampaignHandler) createCampaignFromArchive(w http.ResponseWriter, r *http.Request, userID int, archive *models.CampaignArchive, req models.CampaignCopyRequest, message string) {
+	if name := strings.TrimSpace(req.Name); name != "" {
+		archive.Campaign.Name = name
+	}
+	if req.Description != nil {
+		archive.Campaign.Description = *req.Description
+	}
+	if req.MaxPlayers > 0 {
+		archive.Campaign.MaxPlayers = req.MaxPlayers
+	}
+	if archive.Campaign.MaxPlayers <= 0 {
+		archive.Campaign.MaxPlayers = 6
+	}
+
+	validationErrors := h.Validator.BatchValidate(
+		func() error { return h.Validator.ValidateName(archive.Campaign.Name, "name") },
+		func() error { return h.Validator.ValidatePlayerCount(archive.Campaign.MaxPlayers) },
+	)
+	if validationErrors.HasErrors() {
+		h.Response.SendValidationError(w, validationErrors.Error())
+		return
+	}
+
+	inviteCode, err := utils.GenerateInviteCode()
+	if err != nil {
+		h.Response.SendInternalError(w, "Failed to generate invite code")
+		return
+	}
+
+	campaign := &models.Campaign{
+		Name:           archive.Campaign.Name,
+		Description:    archive.Campaign.Description,
+		DMID:           userID,
+		MaxPlayers:     archive.Campaign.MaxPlayers,
+		CurrentSession: archive.Campaign.CurrentSession,
+		Status:         models.CampaignStatusPlanning, // Sala, sessões e congelamento recomeçam do zero
+		AllowHomebrew:  archive.Campaign.AllowHomebrew,
+		LevelingMode:   archive.Campaign.LevelingMode,
+		InviteCode:     utils.NormalizeInviteCode(inviteCode),
+	}
+	if campaign.CurrentSession <= 0 {
+		campaign.CurrentSession = 1
+	}
+	if campaign.LevelingMode != models.LevelingModeMilestone {
+		campaign.LevelingMode = models.LevelingModeXP
+	}
+
+	roomID := ""
+	if archive.Scene != nil {
+		roomID = generateRoomID()
+	}
+
+	report, err := h.DB.ImportCampaignArchive(r.Context(), archive, campaign, roomID)
+	if err != nil {
+		h.Response.HandleDBError(w, err, "create campaign from archive")
+		return
+	}
+
+	campaign.InviteCode = inviteCode
+	h.Response.SendCreated(w, message, map[string]any{
+		"campaign": campaign,
+		"report":   report,
+	})
+}
+
+// buildCampaignArchive monta o pacote completo da campanha (jogadores, personagens, NPCs,
+// encontros, homebrew referenciado, wiki, quests e cena da sala)
+func (h *CampaignHandler) buildCampaignArchive(ctx context.Context, campaign *models.Campaign) (*models.CampaignArchive, error) {
+	dm, err := h.DB.GetUserByID(ctx, campaign.DMID)
+	if err != nil {
+		return nil, err
+	}
+
+	npcs, err := h.DB.GetCampaignNPCs(ctx, campaign.ID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	encounters, err := h.DB.GetCampaignEncounters(ctx, campaign.ID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	archive := &models.CampaignArchive{
+		Version:    models.CampaignArchiveVersion,
+		ExportedAt: time.Now().UTC(),
+		Campaign: models.ArchivedCampaign{
+			Name:           campaign.Name,
+			Description:    campaign.Description,
+			DMUsername:     dm.Username,
+			MaxPlayers:     campaign.MaxPlayers,
+			CurrentSession: campaign.CurrentSession,
+			Status:         campaign.Status,
+			AllowHomebrew:  campaign.AllowHomebrew,
+			LevelingMode:   campaign.LevelingMode,
+		},
+		Players:    []models.ArchivedPlayer{},
+		Characters: []models.ArchivedCharacter{},
+		NPCs:       npcs,
+		Encounters: encounters,
+	}
+
+	ownerIDs := []int{campaign.DMID}
+	for _, player := range campaign.Players {
+		if player.User == nil {
+			continue
+		}
+		archive.Players = append(archive.Players, models.ArchivedPlayer{
+			ID:       player.UserID,
+			Username: player.User.Username,
+			Status:   player.Status,
+			Role:     player.Role,
+		})
+	}
+
+	for _, character := range campaign.Characters {
+		username := ""
+		if character.Player != nil {
+			username = character.Player.Username
+		}
+		character.Player = nil
+		archive.Characters = append(archive.Characters, models.ArchivedCharacter{
+			CampaignCharacter: character,
+			Username:          username,
+		})
+		ownerIDs = append(ownerIDs, character.PlayerID)
+	}
+
+	archive.Homebrew, err = h.DB.GetReferencedHomebrew(ctx, referencedContentNames(archive), ownerIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	archive.Wiki, err = h.DB.GetWikiEntries(ctx, campaign.ID, campaign.DMID, true, models.WikiFilters{})
+	if err != nil {
+		return nil, err
+	}
+
+	archive.Quests, err = h.DB.GetCampaignQuests(ctx, campaign.ID, false, "")
+	if err != nil {
+		return nil, err
+	}
+	if err := h.attachArchivedObjectives(ctx, archive.Quests); err != nil {
+		return nil, err
+	}
+
+	room, err := h.DB.GetRoomByCampaignID(ctx, campaign.ID)
+	if err != nil {
+		return nil, err
+	}
+	if room != nil {
+		archive.Scene = &models.ArchivedScene{
+			RoomName:   room.Name,
+			SceneState: room.SceneState,
+			Metadata:   room.Metadata,
+		}
+	}
+
+	return archive, nil
+}
+
+// attachArchivedObjectives preenche os objetivos das quests exportadas
+func (h *CampaignHandler) attachArchivedObjectives(ctx context.Context, quests []models.Quest) error {
+	if len(quests) == 0 {
+		return nil
+	}
+
+	questIDs := make([]int, len(quests))
+	for i, quest := range quests {
+		questIDs[i] = quest.ID
+	}
+
+	objectives, err := h.DB.GetQuestObjectives(ctx, questIDs...)
+	if err != nil {
+		return err
+	}
+
+	byQuest := map[int][]models.QuestObjective{}
+	for _, objective := range objectives {
+		byQuest[objective.QuestID] = append(byQuest[objective.QuestID], objective)
+	}
+	for i := range quests {
+		quests[i].Objectives = byQuest[quests[i].ID]
+	}
+	return nil
+}
+
+// referencedContentNames coleta, sem repetição, os nomes de raça, classe e antecedente usados
+// pelos personagens e NPCs do pacote
+func referencedContentNames(archive *models.CampaignArchive) []string {
+	seen := map[string]bool{}
+	add := func(names ...string) {
+		for _, name := range names {
+			if name = strings.TrimSpace(name); name != "" {
+				seen[name] = true
+			}
+		}
+	}
+
+	for _, character := range archive.Characters {
+		add(character.Race, character.Class, character.Background)
+	}
+	for _, npc := range archive.NPCs {
+		add(npc.Race, npc.Class, npc.Background)
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
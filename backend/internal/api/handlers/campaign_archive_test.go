@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+
+	"rpg-saas-backend/internal/api/middleware"
+	"rpg-saas-backend/internal/models"
+)
+
+// expectCampaignArchive simula as consultas de buildCampaignArchive (DM 7, um NPC, um encontro,
+// uma raça homebrew, uma entrada de wiki, uma quest concluída e a sala)
+func expectCampaignArchive(mock sqlmock.Sqlmock, campaignID int) {
+	now := time.Now()
+	mock.ExpectQuery(`SELECT \* FROM users WHERE id = \$1`).WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username", "email", "password", "created_at", "updated_at", "admin", "plan"}).
+			AddRow(7, "dm", "dm@example.com", "hash", now, now, false, 0))
+	mock.ExpectQuery(`FROM npcs WHERE campaign_id = \$1`).WithArgs(campaignID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "level", "race", "class", "background",
+			"attributes", "abilities", "equipment", "hp", "ca", "created_at"}).
+			AddRow(3, "Durnan", "", 5, "Owlfolk", "Fighter", "", nil, nil, nil, 40, 16, now))
+	mock.ExpectQuery(`FROM encounters WHERE campaign_id = \$1`).WithArgs(campaignID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "theme", "difficulty", "total_xp", "player_level", "player_count", "created_at"}).
+			AddRow(4, "cellar", "easy", 50, 1, 4, now))
+	mock.ExpectQuery(`SELECT \* FROM encounter_monsters WHERE encounter_id = \$1`).WithArgs(4).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "encounter_id", "name", "xp", "cr", "created_at"}).
+			AddRow(5, 4, "Rat", 10, 0.0, now))
+	mock.ExpectQuery(`FROM homebrew_races`).WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "size"}).AddRow(6, "Owlfolk", "Medium"))
+	mock.ExpectQuery(`FROM homebrew_classes`).WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+	mock.ExpectQuery(`FROM homebrew_backgrounds`).WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+	mock.ExpectQuery(`FROM campaign_wiki_entries`).WithArgs(campaignID).
+		WillReturnRows(addWikiRow(sqlmock.NewRows(wikiCols), 2, "Yawning Portal", "all", "{}", "{}"))
+	mock.ExpectQuery(`FROM quests q`).WithArgs(campaignID).
+		WillReturnRows(questRow(1, "completed", true))
+	mock.ExpectQuery(`FROM quest_objectives`).WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows(questObjectiveCols).AddRow(1, 1, "Find Gundren", true, 0, now, now))
+	expectCampaignRoom(mock, campaignID)
+}
+
+func TestCampaignHandler_ExportCampaign(t *testing.T) {
+	handler, mock, cleanup := newMockCampaignHandler(t)
+	defer cleanup()
+
+	expectCampaignAccess(mock, 10, 7, 7, 8)
+	expectCampaignArchive(mock, 10)
+
+	req := withCampaignUser(httptest.NewRequest(http.MethodGet, "/api/campaigns/10/export", nil), "10", 7)
+	rr := httptest.NewRecorder()
+	handler.ExportCampaign(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if !strings.Contains(rr.Header().Get("Content-Disposition"), "campaign-10-v1.json") {
+		t.Fatalf("expected attachment filename, got %q", rr.Header().Get("Content-Disposition"))
+	}
+
+	var archive models.CampaignArchive
+	if err := json.NewDecoder(rr.Body).Decode(&archive); err != nil {
+		t.Fatalf("failed to decode archive: %v", err)
+	}
+	if archive.Version != models.CampaignArchiveVersion || archive.Campaign.DMUsername != "dm" {
+		t.Fatalf("unexpected archive header: %+v", archive.Campaign)
+	}
+	if len(archive.Players) != 1 || archive.Players[0].Username != "player" || archive.Players[0].Role != "player" {
+		t.Fatalf("players should be exported by username, got %+v", archive.Players)
+	}
+	if len(archive.NPCs) != 1 || len(archive.Encounters) != 1 || len(archive.Encounters[0].Monsters) != 1 {
+		t.Fatalf("expected NPCs and encounters with monsters, got %+v / %+v", archive.NPCs, archive.Encounters)
+	}
+	if len(archive.Homebrew.Races) != 1 || archive.Scene == nil || archive.Scene.RoomName != "Table" {
+		t.Fatalf("expected homebrew and scene, got %+v / %+v", archive.Homebrew, archive.Scene)
+	}
+	if len(archive.Wiki) != 1 || len(archive.Quests) != 1 || len(archive.Quests[0].Objectives) != 1 {
+		t.Fatalf("expected wiki and quests with objectives, got %+v / %+v", archive.Wiki, archive.Quests)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestCampaignHandler_ExportCampaignPlayerForbidden(t *testing.T) {
+	handler, mock, cleanup := newMockCampaignHandler(t)
+	defer cleanup()
+
+	expectCampaignAccess(mock, 10, 8, 7, 8)
+
+	req := withCampaignUser(httptest.NewRequest(http.MethodGet, "/api/campaigns/10/export", nil), "10", 8)
+	rr := httptest.NewRecorder()
+	handler.ExportCampaign(rr, req)
+
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", rr.Code)
+	}
+}
+
+func TestCampaignHandler_ImportCampaign(t *testing.T) {
+	t.Run("unsupported version", func(t *testing.T) {
+		handler, _, cleanup := newMockCampaignHandler(t)
+		defer cleanup()
+
+		req := httptest.NewRequest(http.MethodPost, "/api/campaigns/import", bytes.NewBufferString(`{"version":99,"campaign":{"name":"Heist"}}`))
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, 7))
+		rr := httptest.NewRecorder()
+		handler.ImportCampaign(rr, req)
+
+		if rr.Code != http.StatusUnprocessableEntity {
+			t.Fatalf("expected 422, got %d", rr.Code)
+		}
+	})
+
+	t.Run("imports any archived status as planning", func(t *testing.T) {
+		handler, mock, cleanup := newMockCampaignHandler(t)
+		defer cleanup()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO campaigns`).
+			WithArgs("Heist", "", 7, 6, 1, "planning", false, "xp", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(21))
+		mock.ExpectCommit()
+
+		body := `{"version":1,"campaign":{"name":"Heist","status":"completed"}}`
+		req := httptest.NewRequest(http.MethodPost, "/api/campaigns/import", bytes.NewBufferString(body))
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, 7))
+		rr := httptest.NewRecorder()
+		handler.ImportCampaign(rr, req)
+
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+
+	t.Run("creates campaign owned by importer", func(t *testing.T) {
+		handler, mock, cleanup := newMockCampaignHandler(t)
+		defer cleanup()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO campaigns`).
+			WithArgs("Heist", "", 7, 6, 1, "planning", true, "xp", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(20))
+		mock.ExpectQuery(`SELECT id FROM users WHERE username = \$1`).WithArgs("bob").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectCommit()
+
+		body := `{"version":1,"campaign":{"name":"Heist","allow_homebrew":true},"players":[{"username":"bob","status":"active"}]}`
+		req := httptest.NewRequest(http.MethodPost, "/api/campaigns/import", bytes.NewBufferString(body))
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, 7))
+		rr := httptest.NewRecorder()
+		handler.ImportCampaign(rr, req)
+
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+		}
+
+		var resp struct {
+			Data struct {
+				Campaign models.Campaign             `json:"campaign"`
+				Report   models.CampaignImportReport `json:"report"`
+			} `json:"data"`
+		}
+		if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if resp.Data.Campaign.ID != 20 || resp.Data.Campaign.DMID != 7 || len(resp.Data.Campaign.InviteCode) != 9 {
+			t.Fatalf("unexpected campaign: %+v", resp.Data.Campaign)
+		}
+		if len(resp.Data.Report.Unresolved) != 1 || resp.Data.Report.Unresolved[0].Reference != "bob" {
+			t.Fatalf("expected bob to be unresolved, got %+v", resp.Data.Report.Unresolved)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+}
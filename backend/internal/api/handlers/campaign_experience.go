@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"rpg-saas-backend/internal/db"
+	"rpg-saas-backend/internal/models"
+	"rpg-saas-backend/internal/utils"
+)
+
+// AwardXP distribui o XP de um encontro da campanha ou uma quantia avulsa entre os
+// personagens escolhidos (DM ou co-DM, apenas em campanhas por XP)
+func (h *CampaignHandler) AwardXP(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	if !canManageCampaign(campaign, userID) {
+		h.Response.SendForbidden(w, "Only the DM can award experience")
+		return
+	}
+
+	if !checkCampaignNotCompleted(w, h.Response, campaign) {
+		return
+	}
+
+	if campaign.LevelingMode == models.LevelingModeMilestone {
+		h.Response.SendConflict(w, "Campaign uses milestone leveling; award a milestone instead")
+		return
+	}
+
+	var req models.AwardXPRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Response.SendBadRequest(w, "Invalid request body")
+		return
+	}
+
+	errs := utils.ValidationErrors{}
+	switch {
+	case req.EncounterID != nil && req.Amount != 0:
+		errs = append(errs, utils.ValidationError{Field: "amount", Message: "use either encounter_id or amount, not both", Code: "conflict"})
+	case req.EncounterID == nil && req.Amount <= 0:
+		errs = append(errs, utils.ValidationError{Field: "amount", Message: "amount must be positive when no encounter_id is given", Code: "invalid_range"})
+	}
+	if len(req.CharacterIDs) == 0 {
+		errs = append(errs, utils.ValidationError{Field: "character_ids", Message: "at least one character is required", Code: "required"})
+	}
+	if errs.HasErrors() {
+		h.Response.SendValidationError(w, errs.Error())
+		return
+	}
+
+	result, err := h.DB.AwardExperience(r.Context(), campaign.ID, userID, req)
+	switch {
+	case errors.Is(err, db.ErrEncounterUnavailable):
+		h.Response.SendConflict(w, "Encounter not found in this campaign or XP already awarded")
+		return
+	case errors.Is(err, db.ErrCharacterNotInCampaign):
+		h.Response.SendValidationError(w, err.Error())
+		return
+	case err != nil:
+		h.Response.HandleDBError(w, err, "award experience")
+		return
+	}
+
+	h.Response.SendSuccess(w, "Experience awarded", result)
+}
+
+// AwardMilestone libera a subida de nível dos personagens escolhidos (DM ou co-DM,
+// apenas em campanhas por marco)
+func (h *CampaignHandler) AwardMilestone(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	if !canManageCampaign(campaign, userID) {
+		h.Response.SendForbidden(w, "Only the DM can award milestones")
+		return
+	}
+
+	if !checkCampaignNotCompleted(w, h.Response, campaign) {
+		return
+	}
+
+	if campaign.LevelingMode != models.LevelingModeMilestone {
+		h.Response.SendConflict(w, "Campaign uses XP leveling; award experience instead")
+		return
+	}
+
+	var req models.AwardMilestoneRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Response.SendBadRequest(w, "Invalid request body")
+		return
+	}
+
+	if len(req.CharacterIDs) == 0 {
+		h.Response.SendValidationError(w, "character_ids: at least one character is required")
+		return
+	}
+
+	result, err := h.DB.AwardMilestone(r.Context(), campaign.ID, userID, req)
+	switch {
+	case errors.Is(err, db.ErrCharacterNotInCampaign):
+		h.Response.SendValidationError(w, err.Error())
+		return
+	case err != nil:
+		h.Response.HandleDBError(w, err, "award milestone")
+		return
+	}
+
+	h.Response.SendSuccess(w, "Milestone awarded", result)
+}
+
+// canRaiseCharacterLevel indica se userID pode levar o personagem de from para to. Jogadores só
+// sobem um nível por vez e apenas com level_up_available (XP ou marco concedido pelo DM); o
+// DM e os co-DMs podem ajustar o nível livremente.
+func (h *CampaignHandler) canRaiseCharacterLevel(ctx context.Context, campaignID, userID, from, to int, levelUpAvailable bool) (bool, error) {
+	if to <= from || (levelUpAvailable && to == from+1) {
+		return true, nil
+	}
+
+	campaign, err := h.DB.GetCampaignByID(ctx, campaignID, userID)
+	if err != nil {
+		return false, err
+	}
+	return canManageCampaign(campaign, userID), nil
+}
@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+
+	"rpg-saas-backend/internal/models"
+)
+
+func TestCampaignHandler_AwardXP(t *testing.T) {
+	t.Run("player cannot award", func(t *testing.T) {
+		handler, mock, cleanup := newMockCampaignHandler(t)
+		defer cleanup()
+
+		expectCampaignAccess(mock, 10, 8, 7, 8)
+
+		req := withCampaignUser(httptest.NewRequest(http.MethodPost, "/api/campaigns/10/xp", bytes.NewBufferString(`{"amount":100,"character_ids":[1]}`)), "10", 8)
+		rr := httptest.NewRecorder()
+		handler.AwardXP(rr, req)
+
+		if rr.Code != http.StatusForbidden {
+			t.Fatalf("expected 403, got %d", rr.Code)
+		}
+	})
+
+	t.Run("milestone campaign rejects XP", func(t *testing.T) {
+		handler, mock, cleanup := newMockCampaignHandler(t)
+		defer cleanup()
+
+		expectCampaignAccessWithMode(mock, "milestone", 10, 7, 7, nil)
+
+		req := withCampaignUser(httptest.NewRequest(http.MethodPost, "/api/campaigns/10/xp", bytes.NewBufferString(`{"amount":100,"character_ids":[1]}`)), "10", 7)
+		rr := httptest.NewRecorder()
+		handler.AwardXP(rr, req)
+
+		if rr.Code != http.StatusConflict {
+			t.Fatalf("expected 409, got %d: %s", rr.Code, rr.Body.String())
+		}
+	})
+
+	t.Run("needs encounter or amount", func(t *testing.T) {
+		handler, mock, cleanup := newMockCampaignHandler(t)
+		defer cleanup()
+
+		expectCampaignAccess(mock, 10, 7, 7)
+
+		req := withCampaignUser(httptest.NewRequest(http.MethodPost, "/api/campaigns/10/xp", bytes.NewBufferString(`{"encounter_id":4,"amount":50,"character_ids":[1]}`)), "10", 7)
+		rr := httptest.NewRecorder()
+		handler.AwardXP(rr, req)
+
+		if rr.Code != http.StatusUnprocessableEntity {
+			t.Fatalf("expected 422, got %d: %s", rr.Code, rr.Body.String())
+		}
+	})
+
+	t.Run("co-DM awards custom amount", func(t *testing.T) {
+		handler, mock, cleanup := newMockCampaignHandler(t)
+		defer cleanup()
+
+		expectCampaignAccessWithCoDMs(mock, 10, 8, 7, []int{8}, 8)
+		mock.ExpectBegin()
+		mock.ExpectQuery(`FROM campaign_characters`).WithArgs(10, "{1}").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "level", "experience_points", "level_up_available"}).
+				AddRow(1, "Aria", 1, 100, false))
+		expectVersionHistory(mock, models.CharacterTypeCampaign, 1)
+		mock.ExpectExec(`UPDATE campaign_characters SET experience_points`).WithArgs(300, true, 1, 10).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		expectCharacterVersionRecorded(mock, 1, 10)
+		mock.ExpectCommit()
+
+		req := withCampaignUser(httptest.NewRequest(http.MethodPost, "/api/campaigns/10/xp", bytes.NewBufferString(`{"amount":200,"character_ids":[1],"reason":"rescued the mayor"}`)), "10", 8)
+		rr := httptest.NewRecorder()
+		handler.AwardXP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+}
+
+func TestCampaignHandler_AwardMilestoneRequiresMilestoneMode(t *testing.T) {
+	handler, mock, cleanup := newMockCampaignHandler(t)
+	defer cleanup()
+
+	expectCampaignAccess(mock, 10, 7, 7)
+
+	req := withCampaignUser(httptest.NewRequest(http.MethodPost, "/api/campaigns/10/milestones", bytes.NewBufferString(`{"character_ids":[1]}`)), "10", 7)
+	rr := httptest.NewRecorder()
+	handler.AwardMilestone(rr, req)
+
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestCampaignHandler_CreateCampaignRejectsUnknownLevelingMode(t *testing.T) {
+	handler, mock, cleanup := newMockCampaignHandler(t)
+	defer cleanup()
+
+	req := withCampaignUser(httptest.NewRequest(http.MethodPost, "/api/campaigns", bytes.NewBufferString(`{"name":"Saga","leveling_mode":"fast"}`)), "", 7)
+	rr := httptest.NewRecorder()
+	handler.CreateCampaign(rr, req)
+
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestCampaignHandler_UpdateCharacterFullLevelRules(t *testing.T) {
+	levelRow := func(mock sqlmock.Sqlmock, levelUpAvailable bool) {
+		mock.ExpectQuery(`FROM campaign_characters cc`).WithArgs(5, 10, 8).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "campaign_id", "player_id", "source_pc_id", "status", "name", "level", "level_up_available"}).
+				AddRow(5, 10, 8, 3, "active", "Aria", 4, levelUpAvailable))
+	}
+
+	t.Run("player cannot raise level without a level-up", func(t *testing.T) {
+		handler, mock, cleanup := newMockCampaignHandler(t)
+		defer cleanup()
+
+		levelRow(mock, false)
+		expectCampaignAccess(mock, 10, 8, 7, 8)
+
+		req := withCampaignUser(httptest.NewRequest(http.MethodPut, "/api/campaigns/10/characters/5/full", bytes.NewBufferString(`{"name":"Aria","level":5}`)), "10", 8)
+		req = addChiURLParam(req, "characterId", "5")
+		rr := httptest.NewRecorder()
+		handler.UpdateCampaignCharacterFull(rr, req)
+
+		if rr.Code != http.StatusForbidden {
+			t.Fatalf("expected 403, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+
+	t.Run("available level-up allows a single level", func(t *testing.T) {
+		handler, mock, cleanup := newMockCampaignHandler(t)
+		defer cleanup()
+
+		levelRow(mock, true)
+		expectCampaignAccess(mock, 10, 8, 7, 8)
+
+		req := withCampaignUser(httptest.NewRequest(http.MethodPut, "/api/campaigns/10/characters/5/full", bytes.NewBufferString(`{"name":"Aria","level":6}`)), "10", 8)
+		req = addChiURLParam(req, "characterId", "5")
+		rr := httptest.NewRecorder()
+		handler.UpdateCampaignCharacterFull(rr, req)
+
+		if rr.Code != http.StatusForbidden {
+			t.Fatalf("expected 403 for skipping a level, got %d: %s", rr.Code, rr.Body.String())
+		}
+
+		levelRow(mock, true)
+		mock.ExpectBegin()
+		expectVersionHistory(mock, models.CharacterTypeCampaign, 5)
+		mock.ExpectExec(`UPDATE campaign_characters SET\s+name = \$1`).WillReturnResult(sqlmock.NewResult(0, 1))
+		expectCharacterVersionRecorded(mock, 5, 10)
+		mock.ExpectCommit()
+
+		req = withCampaignUser(httptest.NewRequest(http.MethodPut, "/api/campaigns/10/characters/5/full", bytes.NewBufferString(`{"name":"Aria","level":5}`)), "10", 8)
+		req = addChiURLParam(req, "characterId", "5")
+		rr = httptest.NewRecorder()
+		handler.UpdateCampaignCharacterFull(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+}
+
+func TestCampaignHandler_SyncPullRejectsLevelIncrease(t *testing.T) {
+	handler, mock, cleanup := newMockCampaignHandler(t)
+	defer cleanup()
+
+	// O PC subiu para o nível 4 fora da campanha, que não liberou level-up
+	mock.ExpectQuery(`FROM campaign_characters cc`).WithArgs(5, 80, 7).
+		WillReturnRows(sqlmock.NewRows(syncCharCols).AddRow(syncCharRow(5, 80, "PC", 3)...))
+	mock.ExpectQuery(`FROM pcs`).WithArgs(3, 7).
+		WillReturnRows(sqlmock.NewRows(syncPCCols).AddRow(syncPCRow("PC", 4)...))
+	mock.ExpectQuery(`SELECT sync_base FROM campaign_characters`).WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"sync_base"}).AddRow(syncBaseJSON(t, "PC", 3)))
+	expectCampaignAccess(mock, 80, 7, 9, 7)
+
+	rec := httptest.NewRecorder()
+	handler.SyncCampaignCharacter(rec, newSyncRequest(`{"direction":"pull"}`, 7))
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"rpg-saas-backend/internal/db"
+	"rpg-saas-backend/internal/models"
+)
+
+// GetHomebrewWhitelist retorna a política de homebrew da campanha e o conteúdo aprovado
+func (h *CampaignHandler) GetHomebrewWhitelist(w http.ResponseWriter, r *http.Request) {
+	campaign, _, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	entries, err := h.DB.GetCampaignHomebrewWhitelist(r.Context(), campaign.ID)
+	if err != nil {
+		h.Response.HandleDBError(w, err, "fetch homebrew whitelist")
+		return
+	}
+
+	h.Response.SendJSON(w, map[string]any{
+		"allow_homebrew": campaign.AllowHomebrew,
+		"approved":       entries,
+		"count":          len(entries),
+	}, http.StatusOK)
+}
+
+// UpdateHomebrewWhitelist substitui o homebrew aprovado da campanha (DM ou co-DM)
+func (h *CampaignHandler) UpdateHomebrewWhitelist(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	if !canManageCampaign(campaign, userID) {
+		h.Response.SendForbidden(w, "Only the DM can manage approved homebrew")
+		return
+	}
+
+	var req models.UpdateHomebrewWhitelistRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Response.SendBadRequest(w, "Invalid request body")
+		return
+	}
+
+	err := h.DB.ReplaceCampaignHomebrewWhitelist(r.Context(), campaign.ID, campaign.DMID, req.Entries())
+	switch {
+	case errors.Is(err, db.ErrHomebrewNotFound):
+		h.Response.SendValidationError(w, err.Error())
+		return
+	case err != nil:
+		h.Response.HandleDBError(w, err, "update homebrew whitelist")
+		return
+	}
+
+	entries, err := h.DB.GetCampaignHomebrewWhitelist(r.Context(), campaign.ID)
+	if err != nil {
+		h.Response.HandleDBError(w, err, "fetch homebrew whitelist")
+		return
+	}
+
+	h.Response.SendSuccess(w, "Approved homebrew updated", map[string]any{
+		"allow_homebrew": campaign.AllowHomebrew,
+		"approved":       entries,
+		"count":          len(entries),
+	})
+}
+
+// characterContent é a parte do personagem sujeita à política de homebrew da campanha
+type characterContent struct {
+	race       string
+	classes    []string
+	background string
+}
+
+func contentOf(character *models.CampaignCharacter) characterContent {
+	return characterContent{race: character.Race, classes: character.Classes().Names(), background: character.Background}
+}
+
+// characterContentChangeRejections verifica contra a política da campanha apenas a raça, as
+// classes e o antecedente que mudaram em relação a before: o que já foi aceito na entrada do
+// personagem não volta a ser barrado por uma edição de outros campos
+func (h *CampaignHandler) characterContentChangeRejections(ctx context.Context, character *models.CampaignCharacter, before characterContent) ([]models.ContentRejection, error) {
+	after := contentOf(character)
+
+	race, background := after.race, after.background
+	if strings.EqualFold(race, before.race) {
+		race = ""
+	}
+	if strings.EqualFold(background, before.background) {
+		background = ""
+	}
+	classes := []string{}
+	for _, class := range after.classes {
+		if !slices.ContainsFunc(before.classes, func(previous string) bool { return strings.EqualFold(previous, class) }) {
+			classes = append(classes, class)
+		}
+	}
+
+	if race == "" && background == "" && len(classes) == 0 {
+		return nil, nil
+	}
+	return h.DB.CheckCampaignCharacterContent(ctx, character.CampaignID, race, classes, background)
+}
+
+// sendContentRejections responde 422 listando o conteúdo barrado pela política da campanha
+func (h *CampaignHandler) sendContentRejections(w http.ResponseWriter, rejections []models.ContentRejection) {
+	h.Response.SendJSON(w, map[string]any{
+		"error":  "Character content is not allowed in this campaign",
+		"fields": rejections,
+	}, http.StatusUnprocessableEntity)
+}
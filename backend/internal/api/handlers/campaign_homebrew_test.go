@@ -0,0 +1,275 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+
+	"rpg-saas-backend/internal/api/middleware"
+	"rpg-saas-backend/internal/models"
+)
+
+func TestCampaignHandler_AddCharacterRejectsHomebrew(t *testing.T) {
+	handler, mock, cleanup := newMockCampaignHandler(t)
+	defer cleanup()
+
+	now := time.Now()
+	mock.ExpectQuery(`FROM pcs`).WithArgs(4).WillReturnRows(sqlmock.NewRows([]string{
+		"id", "name", "description", "level", "race", "class", "background", "alignment",
+		"attributes", "abilities", "equipment", "hp", "ca", "player_name", "player_id", "created_at",
+	}).AddRow(4, "PC", "desc", 3, "Owlfolk", "Wizard", "Sage", "neutral", []byte(`{}`), []byte(`{}`), []byte(`{}`), 18, 14, "Player", 7, now))
+	mock.ExpectQuery(`SELECT EXISTS\(`).WithArgs(60, 7).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM campaign_characters`).WithArgs(60, 4).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	expectCampaignAccess(mock, 60, 7, 9, 7)
+	mock.ExpectQuery(`SELECT c.allow_homebrew`).WithArgs(60).
+		WillReturnRows(sqlmock.NewRows([]string{"allow_homebrew", "exists"}).AddRow(false, false))
+	mock.ExpectQuery(`FROM dnd_races`).WithArgs("Owlfolk").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectQuery(`FROM dnd_classes`).WithArgs("Wizard").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery(`FROM dnd_backgrounds`).WithArgs("Sage").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/campaigns/60/characters", bytes.NewBufferString(`{"source_pc_id":4}`))
+	req = addChiURLParam(req, "id", "60")
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, 7))
+	rr := httptest.NewRecorder()
+	handler.AddCharacterToCampaign(rr, req)
+
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var resp struct {
+		Fields []models.ContentRejection `json:"fields"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(resp.Fields) != 1 || resp.Fields[0].Field != "race" || resp.Fields[0].Value != "Owlfolk" {
+		t.Fatalf("expected race rejection, got %+v", resp.Fields)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestCampaignHandler_AddMulticlassCharacter(t *testing.T) {
+	handler, mock, cleanup := newMockCampaignHandler(t)
+	defer cleanup()
+
+	// O resumo "Fighter 3 / Wizard 2" não é uma classe: cada classe é verificada por si
+	now := time.Now()
+	mock.ExpectQuery(`FROM pcs`).WithArgs(4).WillReturnRows(sqlmock.NewRows([]string{
+		"id", "name", "description", "level", "race", "class", "class_levels", "background", "alignment",
+		"attributes", "abilities", "equipment", "hp", "ca", "player_name", "player_id", "created_at",
+	}).AddRow(4, "PC", "desc", 5, "Elf", "Fighter 3 / Wizard 2",
+		[]byte(`[{"class":"Fighter","level":3},{"class":"Wizard","level":2}]`), "Sage", "neutral",
+		[]byte(`{}`), []byte(`{}`), []byte(`{}`), 38, 16, "Player", 7, now))
+	mock.ExpectQuery(`SELECT EXISTS\(`).WithArgs(60, 7).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM campaign_characters`).WithArgs(60, 4).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	expectCampaignAccess(mock, 60, 7, 9, 7)
+	mock.ExpectQuery(`SELECT c.allow_homebrew`).WithArgs(60).
+		WillReturnRows(sqlmock.NewRows([]string{"allow_homebrew", "exists"}).AddRow(false, false))
+	mock.ExpectQuery(`FROM dnd_races`).WithArgs("Elf").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery(`FROM dnd_classes`).WithArgs("Fighter").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery(`FROM dnd_classes`).WithArgs("Wizard").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery(`FROM dnd_backgrounds`).WithArgs("Sage").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO campaign_characters`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(99))
+	expectCharacterVersionRecorded(mock, 99, 60)
+	mock.ExpectCommit()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/campaigns/60/characters", bytes.NewBufferString(`{"source_pc_id":4}`))
+	req = addChiURLParam(req, "id", "60")
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, 7))
+	rr := httptest.NewRecorder()
+	handler.AddCharacterToCampaign(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestCampaignHandler_UpdateHomebrewWhitelist(t *testing.T) {
+	t.Run("player cannot update", func(t *testing.T) {
+		handler, mock, cleanup := newMockCampaignHandler(t)
+		defer cleanup()
+
+		expectCampaignAccess(mock, 10, 8, 7, 8)
+
+		req := withCampaignUser(httptest.NewRequest(http.MethodPut, "/api/campaigns/10/homebrew-whitelist", bytes.NewBufferString(`{"races":[5]}`)), "10", 8)
+		rr := httptest.NewRecorder()
+		handler.UpdateHomebrewWhitelist(rr, req)
+
+		if rr.Code != http.StatusForbidden {
+			t.Fatalf("expected 403, got %d", rr.Code)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+
+	t.Run("unknown homebrew", func(t *testing.T) {
+		handler, mock, cleanup := newMockCampaignHandler(t)
+		defer cleanup()
+
+		expectCampaignAccess(mock, 10, 7, 7)
+		mock.ExpectBegin()
+		mock.ExpectExec(`DELETE FROM campaign_homebrew_whitelist`).WithArgs(10).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(`FROM homebrew_races`).WithArgs(5, 7).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+		mock.ExpectRollback()
+
+		req := withCampaignUser(httptest.NewRequest(http.MethodPut, "/api/campaigns/10/homebrew-whitelist", bytes.NewBufferString(`{"races":[5]}`)), "10", 7)
+		rr := httptest.NewRecorder()
+		handler.UpdateHomebrewWhitelist(rr, req)
+
+		if rr.Code != http.StatusUnprocessableEntity {
+			t.Fatalf("expected 422, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+
+	t.Run("DM replaces whitelist", func(t *testing.T) {
+		handler, mock, cleanup := newMockCampaignHandler(t)
+		defer cleanup()
+
+		expectCampaignAccess(mock, 10, 7, 7)
+		mock.ExpectBegin()
+		mock.ExpectExec(`DELETE FROM campaign_homebrew_whitelist`).WithArgs(10).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(`FROM homebrew_backgrounds`).WithArgs(3, 7).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+		mock.ExpectExec(`INSERT INTO campaign_homebrew_whitelist`).WithArgs(10, "background", 3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		mock.ExpectQuery(`FROM campaign_homebrew_whitelist w`).WithArgs(10).
+			WillReturnRows(sqlmock.NewRows([]string{"campaign_id", "content_type", "content_id", "name", "created_at"}).
+				AddRow(10, "background", 3, "Tavern Keeper", time.Now()))
+
+		req := withCampaignUser(httptest.NewRequest(http.MethodPut, "/api/campaigns/10/homebrew-whitelist", bytes.NewBufferString(`{"backgrounds":[3]}`)), "10", 7)
+		rr := httptest.NewRecorder()
+		handler.UpdateHomebrewWhitelist(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		var resp struct {
+			Data struct {
+				Approved []models.HomebrewWhitelistEntry `json:"approved"`
+			} `json:"data"`
+		}
+		if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if len(resp.Data.Approved) != 1 || resp.Data.Approved[0].Name != "Tavern Keeper" {
+			t.Fatalf("unexpected whitelist: %+v", resp.Data.Approved)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+}
+
+func TestCampaignHandler_UpdateCharacterFullRejectsHomebrew(t *testing.T) {
+	handler, mock, cleanup := newMockCampaignHandler(t)
+	defer cleanup()
+
+	expectCampaignCharacterRow(mock, 5, 10, 8, "active")
+	mock.ExpectQuery(`SELECT c.allow_homebrew`).WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"allow_homebrew", "exists"}).AddRow(false, false))
+	mock.ExpectQuery(`FROM dnd_races`).WithArgs("Owlfolk").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+	req := withCampaignUser(httptest.NewRequest(http.MethodPut, "/api/campaigns/10/characters/5/full", bytes.NewBufferString(`{"name":"Aria","level":4,"race":"Owlfolk"}`)), "10", 8)
+	req = addChiURLParam(req, "characterId", "5")
+	rr := httptest.NewRecorder()
+	handler.UpdateCampaignCharacterFull(rr, req)
+
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestCampaignHandler_SyncPullRejectsHomebrew(t *testing.T) {
+	handler, mock, cleanup := newMockCampaignHandler(t)
+	defer cleanup()
+
+	pcRow := syncPCRow("PC", 3)
+	pcRow[4] = "Owlfolk"
+	mock.ExpectQuery(`FROM campaign_characters cc`).WithArgs(5, 80, 7).
+		WillReturnRows(sqlmock.NewRows(syncCharCols).AddRow(syncCharRow(5, 80, "PC", 3)...))
+	mock.ExpectQuery(`FROM pcs`).WithArgs(3, 7).WillReturnRows(sqlmock.NewRows(syncPCCols).AddRow(pcRow...))
+	mock.ExpectQuery(`SELECT sync_base FROM campaign_characters`).WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"sync_base"}).AddRow(syncBaseJSON(t, "PC", 3)))
+	mock.ExpectQuery(`SELECT c.allow_homebrew`).WithArgs(80).
+		WillReturnRows(sqlmock.NewRows([]string{"allow_homebrew", "exists"}).AddRow(false, false))
+	mock.ExpectQuery(`FROM dnd_races`).WithArgs("Owlfolk").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+	rec := httptest.NewRecorder()
+	handler.SyncCampaignCharacter(rec, newSyncRequest(`{"direction":"pull"}`, 7))
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestCampaignHandler_SyncSkipsCampaignsRejectingHomebrew(t *testing.T) {
+	handler, mock, cleanup := newMockCampaignHandler(t)
+	defer cleanup()
+
+	// A campanha 80 aceita qualquer homebrew; a 81 só aceita SRD e fica de fora
+	pcRow := syncPCRow("PC", 3)
+	pcRow[4] = "Owlfolk"
+	mock.ExpectQuery(`FROM campaign_characters cc`).WithArgs(5, 80, 7).
+		WillReturnRows(sqlmock.NewRows(syncCharCols).AddRow(syncCharRow(5, 80, "PC", 3)...))
+	mock.ExpectQuery(`FROM pcs`).WithArgs(3, 7).WillReturnRows(sqlmock.NewRows(syncPCCols).AddRow(pcRow...))
+	mock.ExpectQuery(`SELECT sync_base FROM campaign_characters`).WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"sync_base"}).AddRow(syncBaseJSON(t, "PC", 3)))
+	mock.ExpectQuery(`SELECT c.allow_homebrew`).WithArgs(80).
+		WillReturnRows(sqlmock.NewRows([]string{"allow_homebrew", "exists"}).AddRow(true, false))
+
+	otherCols := append(append([]string{}, syncCharCols...), "sync_base")
+	mock.ExpectQuery(`FROM campaign_characters\s+WHERE source_pc_id = \$1`).WithArgs(3).
+		WillReturnRows(sqlmock.NewRows(otherCols).AddRow(append(syncCharRow(6, 81, "PC", 3), syncBaseJSON(t, "PC", 3))...))
+	mock.ExpectQuery(`SELECT c.allow_homebrew`).WithArgs(81).
+		WillReturnRows(sqlmock.NewRows([]string{"allow_homebrew", "exists"}).AddRow(false, false))
+	mock.ExpectQuery(`FROM dnd_races`).WithArgs("Owlfolk").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+	mock.ExpectBegin()
+	expectVersionHistory(mock, models.CharacterTypeCampaign, 5)
+	mock.ExpectExec(`UPDATE campaign_characters SET\s+name`).WillReturnResult(sqlmock.NewResult(0, 1))
+	expectCharacterVersionRecorded(mock, 5, 80)
+	mock.ExpectExec(`UPDATE campaign_characters SET\s+sync_base`).WithArgs(sqlmock.AnyArg(), 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	rec := httptest.NewRecorder()
+	handler.SyncCampaignCharacter(rec, newSyncRequest(`{"direction":"pull","sync_to_other_campaigns":true}`, 7))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp models.SyncCharacterResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.SyncCount != 0 || len(resp.Campaigns) != 1 || resp.Campaigns[0].Synced || len(resp.Campaigns[0].Rejections) != 1 {
+		t.Fatalf("expected the campaign rejecting homebrew to be skipped, got %+v", resp)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
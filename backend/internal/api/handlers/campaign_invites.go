@@ -0,0 +1,299 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"rpg-saas-backend/internal/db"
+	"rpg-saas-backend/internal/models"
+	"rpg-saas-backend/internal/utils"
+)
+
+// GetInvites lista os convites da campanha (apenas DM)
+func (h *CampaignHandler) GetInvites(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	if !isCampaignDM(campaign, userID) {
+		h.Response.SendForbidden(w, "Only the DM can manage invites")
+		return
+	}
+
+	invites, err := h.DB.GetCampaignInvites(r.Context(), campaign.ID)
+	if err != nil {
+		h.Response.HandleDBError(w, err, "fetch invites")
+		return
+	}
+
+	for i := range invites {
+		invites[i].Code = formatInviteCode(invites[i].Code)
+	}
+
+	h.Response.SendJSON(w, map[string]any{
+		"invites": invites,
+		"count":   len(invites),
+	}, http.StatusOK)
+}
+
+// CreateInvite gera um convite com validade, limite de usos e aprovação opcionais (apenas DM)
+func (h *CampaignHandler) CreateInvite(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	if !isCampaignDM(campaign, userID) {
+		h.Response.SendForbidden(w, "Only the DM can manage invites")
+		return
+	}
+
+	var req models.CreateInviteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Response.SendBadRequest(w, "Invalid request body")
+		return
+	}
+
+	now := time.Now()
+	validationErrors := h.Validator.BatchValidate(
+		func() error { return h.Validator.ValidateNonNegative(req.MaxUses, "max_uses") },
+		func() error { return h.Validator.ValidateNonNegative(req.ExpiresInHours, "expires_in_hours") },
+		func() error {
+			if req.ExpiresAt != nil && !req.ExpiresAt.After(now) {
+				return utils.ValidationError{
+					Field:   "expires_at",
+					Message: "expires_at must be in the future",
+					Code:    "invalid_range",
+				}
+			}
+			return nil
+		},
+	)
+	if validationErrors.HasErrors() {
+		h.Response.SendValidationError(w, validationErrors.Error())
+		return
+	}
+
+	code, err := utils.GenerateInviteCode()
+	if err != nil {
+		h.Response.SendInternalError(w, "Failed to generate invite code")
+		return
+	}
+
+	invite := &models.CampaignInvite{
+		CampaignID:       campaign.ID,
+		Code:             utils.NormalizeInviteCode(code),
+		CreatedBy:        &userID,
+		ExpiresAt:        req.ExpiresAt,
+		MaxUses:          req.MaxUses,
+		RequiresApproval: req.RequiresApproval,
+		AllowWaitlist:    req.AllowWaitlist,
+	}
+	if invite.ExpiresAt == nil && req.ExpiresInHours > 0 {
+		expiresAt := now.Add(time.Duration(req.ExpiresInHours) * time.Hour)
+		invite.ExpiresAt = &expiresAt
+	}
+
+	if err := h.DB.CreateCampaignInvite(r.Context(), invite); err != nil {
+		h.Response.HandleDBError(w, err, "create invite")
+		return
+	}
+
+	invite.Code = formatInviteCode(invite.Code)
+	h.Activity.Record(r.Context(), models.CampaignActivity{
+		CampaignID: campaign.ID,
+		ActorID:    &userID,
+		EventType:  models.ActivityInviteCreated,
+		TargetType: models.ActivityTargetInvite,
+		TargetID:   activityTarget(invite.ID),
+		Summary:    "Created invite " + invite.Code,
+		Metadata: models.JSONB{
+			"max_uses":          invite.MaxUses,
+			"expires_at":        invite.ExpiresAt,
+			"requires_approval": invite.RequiresApproval,
+		},
+	})
+	h.Response.SendCreated(w, "Invite created successfully", invite)
+}
+
+// RevokeInvite invalida um convite (apenas DM)
+func (h *CampaignHandler) RevokeInvite(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	if !isCampaignDM(campaign, userID) {
+		h.Response.SendForbidden(w, "Only the DM can manage invites")
+		return
+	}
+
+	inviteID, err := utils.ExtractIDParam(r, "inviteId")
+	if err != nil {
+		h.Response.SendBadRequest(w, err.Error())
+		return
+	}
+
+	if err := h.DB.RevokeCampaignInvite(r.Context(), inviteID, campaign.ID); err != nil {
+		h.Response.SendNotFound(w, "Invite not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetJoinRequests lista os pedidos pendentes e a lista de espera (apenas DM)
+func (h *CampaignHandler) GetJoinRequests(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	if !isCampaignDM(campaign, userID) {
+		h.Response.SendForbidden(w, "Only the DM can review join requests")
+		return
+	}
+
+	pending := []models.CampaignPlayer{}
+	waitlist := []models.CampaignPlayer{}
+	for _, player := range campaign.Players {
+		switch player.Status {
+		case models.PlayerStatusPending:
+			pending = append(pending, player)
+		case models.PlayerStatusWaitlisted:
+			waitlist = append(waitlist, player)
+		}
+	}
+
+	h.Response.SendJSON(w, map[string]any{
+		"pending":     pending,
+		"waitlist":    waitlist,
+		"max_players": campaign.MaxPlayers,
+	}, http.StatusOK)
+}
+
+// AcceptJoinRequest aceita um pedido pendente ou promove alguém da lista de espera (apenas DM)
+func (h *CampaignHandler) AcceptJoinRequest(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	if !isCampaignDM(campaign, userID) {
+		h.Response.SendForbidden(w, "Only the DM can review join requests")
+		return
+	}
+
+	playerID, err := utils.ExtractIDParam(r, "userId")
+	if err != nil {
+		h.Response.SendBadRequest(w, err.Error())
+		return
+	}
+
+	err = h.DB.ApproveCampaignPlayer(r.Context(), campaign.ID, playerID)
+	switch {
+	case errors.Is(err, db.ErrJoinRequestNotFound):
+		h.Response.SendNotFound(w, "Join request not found")
+		return
+	case errors.Is(err, db.ErrCampaignFull):
+		h.Response.SendConflict(w, "Campaign is full; raise max_players or remove a player first")
+		return
+	case err != nil:
+		h.Response.HandleDBError(w, err, "approve join request")
+		return
+	}
+
+	h.Activity.Record(r.Context(), models.CampaignActivity{
+		CampaignID: campaign.ID,
+		ActorID:    &userID,
+		EventType:  models.ActivityPlayerJoined,
+		TargetType: models.ActivityTargetPlayer,
+		TargetID:   activityTarget(playerID),
+		Summary:    "Accepted a join request",
+		Metadata:   models.JSONB{"via": "approval"},
+	})
+	h.Response.SendSuccess(w, "Player accepted into the campaign", map[string]any{
+		"campaign_id": campaign.ID,
+		"user_id":     playerID,
+		"status":      models.PlayerStatusActive,
+	})
+}
+
+// RejectJoinRequest recusa um pedido pendente ou remove alguém da lista de espera (apenas DM)
+func (h *CampaignHandler) RejectJoinRequest(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	if !isCampaignDM(campaign, userID) {
+		h.Response.SendForbidden(w, "Only the DM can review join requests")
+		return
+	}
+
+	playerID, err := utils.ExtractIDParam(r, "userId")
+	if err != nil {
+		h.Response.SendBadRequest(w, err.Error())
+		return
+	}
+
+	if err := h.DB.RejectCampaignPlayer(r.Context(), campaign.ID, playerID); err != nil {
+		if errors.Is(err, db.ErrJoinRequestNotFound) {
+			h.Response.SendNotFound(w, "Join request not found")
+			return
+		}
+		h.Response.HandleDBError(w, err, "reject join request")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// joinWithInvite entra na campanha usando um convite de campaign_invites
+func (h *CampaignHandler) joinWithInvite(w http.ResponseWriter, r *http.Request, code string, userID int) {
+	result, err := h.DB.RedeemCampaignInvite(r.Context(), code, userID)
+	if err != nil {
+		h.Response.SendBadRequest(w, "Failed to join campaign: "+err.Error())
+		return
+	}
+
+	status := http.StatusOK
+	switch result.Status {
+	case models.PlayerStatusPending:
+		status = http.StatusAccepted
+		result.Message = "Join request sent; waiting for DM approval"
+	case models.PlayerStatusWaitlisted:
+		status = http.StatusAccepted
+		result.Message = "Campaign is full; you have been added to the waitlist"
+	default:
+		result.Message = "Successfully joined campaign"
+	}
+
+	activity := models.CampaignActivity{
+		CampaignID: result.CampaignID,
+		ActorID:    &userID,
+		EventType:  models.ActivityPlayerJoined,
+		TargetType: models.ActivityTargetPlayer,
+		TargetID:   activityTarget(userID),
+		Summary:    "Joined the campaign with an invite",
+		Metadata:   models.JSONB{"via": "invite", "status": result.Status},
+	}
+	if result.Status != models.PlayerStatusActive {
+		activity.EventType = models.ActivityPlayerJoinRequested
+		activity.Summary = "Asked to join the campaign"
+	}
+	h.Activity.Record(r.Context(), activity)
+
+	h.Response.SendJSON(w, result, status)
+}
+
+// formatInviteCode formata o código para exibição (XXXX-XXXX)
+func formatInviteCode(code string) string {
+	if len(code) == 8 {
+		return code[:4] + "-" + code[4:]
+	}
+	return code
+}
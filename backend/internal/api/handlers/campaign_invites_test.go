@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+
+	"rpg-saas-backend/internal/api/middleware"
+	"rpg-saas-backend/internal/models"
+)
+
+func TestCampaignHandler_JoinWithApprovalInvite(t *testing.T) {
+	handler, mock, cleanup := newMockCampaignHandler(t)
+	defer cleanup()
+
+	// O código não é o permanente da campanha, então cai nos convites
+	mock.ExpectQuery(`FROM campaigns\s+WHERE invite_code = \$1`).WithArgs("WXYZ9876").
+		WillReturnRows(sqlmock.NewRows(campaignAccessCols))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM campaign_invites WHERE code = \$1 FOR UPDATE`).WithArgs("WXYZ9876").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "campaign_id", "code", "created_by", "expires_at", "max_uses", "uses",
+			"requires_approval", "allow_waitlist", "revoked", "created_at",
+		}).AddRow(3, 10, "WXYZ9876", 7, nil, 5, 1, true, false, false, time.Now()))
+	mock.ExpectQuery(`SELECT c.dm_id, c.max_players`).WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"dm_id", "max_players", "count"}).AddRow(7, 4, 1))
+	mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM campaign_players`).WithArgs(10, 8).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectExec(`INSERT INTO campaign_players`).WithArgs(10, 8, sqlmock.AnyArg(), "pending").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`UPDATE campaign_invites SET uses = uses \+ 1`).WithArgs(3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/campaigns/join", bytes.NewBufferString(`{"invite_code":"wxyz-9876"}`))
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, 8))
+	rr := httptest.NewRecorder()
+	handler.JoinCampaign(rr, req)
+
+	if rr.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var result models.JoinCampaignResult
+	if err := json.NewDecoder(rr.Body).Decode(&result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if result.Status != models.PlayerStatusPending || result.CampaignID != 10 {
+		t.Fatalf("unexpected join result: %+v", result)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestCampaignHandler_CreateInvite(t *testing.T) {
+	handler, mock, cleanup := newMockCampaignHandler(t)
+	defer cleanup()
+
+	expectCampaignAccess(mock, 10, 7, 7)
+	mock.ExpectQuery(`INSERT INTO campaign_invites`).
+		WithArgs(10, sqlmock.AnyArg(), 7, sqlmock.AnyArg(), 3, true, true, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	body := bytes.NewBufferString(`{"expires_in_hours":48,"max_uses":3,"requires_approval":true,"allow_waitlist":true}`)
+	req := withCampaignUser(httptest.NewRequest(http.MethodPost, "/api/campaigns/10/invites", body), "10", 7)
+	rr := httptest.NewRecorder()
+	handler.CreateInvite(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var resp struct {
+		Data models.CampaignInvite `json:"data"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(resp.Data.Code) != 9 || resp.Data.Code[4] != '-' {
+		t.Fatalf("expected formatted code, got %q", resp.Data.Code)
+	}
+	if resp.Data.ExpiresAt == nil || resp.Data.ExpiresAt.Before(time.Now().Add(47*time.Hour)) {
+		t.Fatalf("expected expiry about 48h ahead, got %v", resp.Data.ExpiresAt)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestCampaignHandler_CreateInviteValidation(t *testing.T) {
+	handler, mock, cleanup := newMockCampaignHandler(t)
+	defer cleanup()
+
+	expectCampaignAccess(mock, 10, 7, 7)
+
+	req := withCampaignUser(httptest.NewRequest(http.MethodPost, "/api/campaigns/10/invites",
+		bytes.NewBufferString(`{"max_uses":-1,"expires_at":"2000-01-01T00:00:00Z"}`)), "10", 7)
+	rr := httptest.NewRecorder()
+	handler.CreateInvite(rr, req)
+
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d", rr.Code)
+	}
+
+	// Players não criam convites
+	expectCampaignAccess(mock, 10, 8, 7, 8)
+
+	req = withCampaignUser(httptest.NewRequest(http.MethodPost, "/api/campaigns/10/invites", bytes.NewBufferString(`{}`)), "10", 8)
+	rr = httptest.NewRecorder()
+	handler.CreateInvite(rr, req)
+
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", rr.Code)
+	}
+}
+
+func TestCampaignHandler_AcceptJoinRequestWhenFull(t *testing.T) {
+	handler, mock, cleanup := newMockCampaignHandler(t)
+	defer cleanup()
+
+	expectCampaignAccess(mock, 10, 7, 7)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT status FROM campaign_players`).WithArgs(10, 8).
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("pending"))
+	mock.ExpectQuery(`SELECT c.max_players`).WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"max_players", "count"}).AddRow(4, 4))
+	mock.ExpectRollback()
+
+	req := withCampaignUser(httptest.NewRequest(http.MethodPost, "/api/campaigns/10/join-requests/8/accept", nil), "10", 7)
+	req = addChiURLParam(req, "userId", "8")
+	rr := httptest.NewRecorder()
+	handler.AcceptJoinRequest(rr, req)
+
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestCampaignHandler_RejectJoinRequest(t *testing.T) {
+	handler, mock, cleanup := newMockCampaignHandler(t)
+	defer cleanup()
+
+	expectCampaignAccess(mock, 10, 7, 7)
+	mock.ExpectExec(`DELETE FROM campaign_players\s+WHERE campaign_id = \$1 AND user_id = \$2 AND status IN`).WithArgs(10, 8).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	req := withCampaignUser(httptest.NewRequest(http.MethodPost, "/api/campaigns/10/join-requests/8/reject", nil), "10", 7)
+	req = addChiURLParam(req, "userId", "8")
+	rr := httptest.NewRecorder()
+	handler.RejectJoinRequest(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 without pending request, got %d", rr.Code)
+	}
+}
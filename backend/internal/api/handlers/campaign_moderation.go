@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"rpg-saas-backend/internal/db"
+	"rpg-saas-backend/internal/models"
+	"rpg-saas-backend/internal/utils"
+)
+
+// KickPlayer remove um jogador da campanha e aposenta os personagens dele (apenas DM)
+func (h *CampaignHandler) KickPlayer(w http.ResponseWriter, r *http.Request) {
+	h.removePlayer(w, r, models.PlayerStatusRemoved, "Player removed from the campaign")
+}
+
+// BanPlayer bane um jogador da campanha; ele não pode voltar por convite (apenas DM)
+func (h *CampaignHandler) BanPlayer(w http.ResponseWriter, r *http.Request) {
+	h.removePlayer(w, r, models.PlayerStatusBanned, "Player banned from the campaign")
+}
+
+// UnbanPlayer retira o banimento de um jogador (apenas DM)
+func (h *CampaignHandler) UnbanPlayer(w http.ResponseWriter, r *http.Request) {
+	campaign, playerID, ok := h.loadModerationTarget(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.DB.UnbanCampaignPlayer(r.Context(), campaign.ID, playerID)
+	switch {
+	case errors.Is(err, db.ErrPlayerNotFound):
+		h.Response.SendNotFound(w, "Banned player not found")
+		return
+	case err != nil:
+		h.Response.HandleDBError(w, err, "unban player")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SetPlayerRole promove um jogador ativo a co-DM ou o rebaixa a player (apenas DM)
+func (h *CampaignHandler) SetPlayerRole(w http.ResponseWriter, r *http.Request) {
+	campaign, playerID, ok := h.loadModerationTarget(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.UpdatePlayerRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Response.SendBadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := h.Validator.ValidateChoice(req.Role, "role", models.PlayerRoles); err != nil {
+		h.Response.SendValidationError(w, err.Error())
+		return
+	}
+
+	err := h.DB.SetCampaignPlayerRole(r.Context(), campaign.ID, playerID, req.Role)
+	switch {
+	case errors.Is(err, db.ErrPlayerNotFound):
+		h.Response.SendNotFound(w, "Active player not found in campaign")
+		return
+	case err != nil:
+		h.Response.HandleDBError(w, err, "update player role")
+		return
+	}
+
+	h.Response.SendSuccess(w, "Player role updated", map[string]any{
+		"campaign_id": campaign.ID,
+		"user_id":     playerID,
+		"role":        req.Role,
+	})
+}
+
+// TransferOwnership passa a campanha para outro jogador ativo; o DM atual vira co-DM (apenas DM)
+func (h *CampaignHandler) TransferOwnership(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	if !isCampaignDM(campaign, userID) {
+		h.Response.SendForbidden(w, "Only the DM can transfer the campaign")
+		return
+	}
+
+	var req models.TransferCampaignRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Response.SendBadRequest(w, "Invalid request body")
+		return
+	}
+
+	if req.UserID <= 0 {
+		h.Response.SendValidationError(w, "user_id is required")
+		return
+	}
+	if req.UserID == userID {
+		h.Response.SendBadRequest(w, "You already own this campaign")
+		return
+	}
+
+	err := h.DB.TransferCampaignOwnership(r.Context(), campaign.ID, userID, req.UserID)
+	switch {
+	case errors.Is(err, db.ErrPlayerNotFound):
+		h.Response.SendNotFound(w, "New DM must be an active player of the campaign")
+		return
+	case err != nil:
+		h.Response.HandleDBError(w, err, "transfer campaign")
+		return
+	}
+
+	h.Response.SendSuccess(w, "Campaign transferred successfully", map[string]any{
+		"campaign_id": campaign.ID,
+		"dm_id":       req.UserID,
+		"previous_dm": userID,
+	})
+}
+
+func (h *CampaignHandler) removePlayer(w http.ResponseWriter, r *http.Request, status, message string) {
+	campaign, playerID, ok := h.loadModerationTarget(w, r)
+	if !ok {
+		return
+	}
+
+	retired, err := h.DB.RemoveCampaignPlayer(r.Context(), campaign.ID, playerID, status, campaign.DMID)
+	switch {
+	case errors.Is(err, db.ErrPlayerNotFound):
+		h.Response.SendNotFound(w, "Player not found in campaign")
+		return
+	case errors.Is(err, db.ErrPlayerBanned):
+		h.Response.SendConflict(w, "Player is already banned")
+		return
+	case err != nil:
+		h.Response.HandleDBError(w, err, "remove player")
+		return
+	}
+
+	h.Activity.Record(r.Context(), models.CampaignActivity{
+		CampaignID: campaign.ID,
+		ActorID:    &campaign.DMID,
+		EventType:  models.ActivityPlayerRemoved,
+		TargetType: models.ActivityTargetPlayer,
+		TargetID:   activityTarget(playerID),
+		Summary:    message,
+		Metadata:   models.JSONB{"status": status, "retired_characters": retired},
+	})
+	h.Response.SendSuccess(w, message, map[string]any{
+		"campaign_id":        campaign.ID,
+		"user_id":            playerID,
+		"status":             status,
+		"retired_characters": retired,
+	})
+}
+
+// loadModerationTarget carrega a campanha, exige que o usuário seja o DM e extrai o {userId} alvo
+func (h *CampaignHandler) loadModerationTarget(w http.ResponseWriter, r *http.Request) (*models.Campaign, int, bool) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return nil, 0, false
+	}
+
+	if !isCampaignDM(campaign, userID) {
+		h.Response.SendForbidden(w, "Only the DM can manage players")
+		return nil, 0, false
+	}
+
+	playerID, err := utils.ExtractIDParam(r, "userId")
+	if err != nil {
+		h.Response.SendBadRequest(w, err.Error())
+		return nil, 0, false
+	}
+
+	if playerID == userID {
+		h.Response.SendBadRequest(w, "The DM cannot moderate themselves")
+		return nil, 0, false
+	}
+
+	return campaign, playerID, true
+}
@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+
+	"rpg-saas-backend/internal/models"
+)
+
+func TestCampaignHandler_KickPlayerRetiresCharacters(t *testing.T) {
+	handler, mock, cleanup := newMockCampaignHandler(t)
+	defer cleanup()
+
+	expectCampaignAccess(mock, 10, 7, 7, 8)
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE campaign_players SET status = \$1, role = 'player'`).WithArgs("removed", 10, 8).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`FROM campaign_characters\s+WHERE campaign_id = \$1 AND player_id = \$2`).WithArgs(10, 8).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "campaign_id", "source_pc_id", "name", "level", "current_hp", "campaign_notes", "status"}).
+			AddRow(3, 10, 20, "Aria", 3, nil, "", "active").
+			AddRow(4, 10, 21, "Brom", 3, nil, "", "inactive").
+			AddRow(5, 10, 22, "Cara", 3, nil, "", "dead"))
+	for _, char := range []struct {
+		id   int
+		from string
+	}{{3, "active"}, {4, "inactive"}} {
+		expectVersionHistory(mock, models.CharacterTypeCampaign, char.id)
+		mock.ExpectExec(`UPDATE campaign_characters SET`).
+			WithArgs(nil, "retired", "", char.id, 10, char.from).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(`SELECT p.is_unique AND NOT EXISTS`).
+			WillReturnRows(sqlmock.NewRows([]string{"released"}).AddRow(false))
+		expectCharacterVersionRecorded(mock, char.id, 10)
+	}
+	mock.ExpectCommit()
+
+	req := withCampaignUser(httptest.NewRequest(http.MethodPost, "/api/campaigns/10/players/8/kick", nil), "10", 7)
+	req = addChiURLParam(req, "userId", "8")
+	rr := httptest.NewRecorder()
+	handler.KickPlayer(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var resp struct {
+		Data struct {
+			Status  string `json:"status"`
+			Retired int    `json:"retired_characters"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Data.Status != "removed" || resp.Data.Retired != 2 {
+		t.Fatalf("unexpected kick result: %+v", resp.Data)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestCampaignHandler_BanPlayerRules(t *testing.T) {
+	t.Run("co-DM cannot ban", func(t *testing.T) {
+		handler, mock, cleanup := newMockCampaignHandler(t)
+		defer cleanup()
+
+		expectCampaignAccessWithCoDMs(mock, 10, 8, 7, []int{8}, 8, 9)
+
+		req := withCampaignUser(httptest.NewRequest(http.MethodPost, "/api/campaigns/10/players/9/ban", nil), "10", 8)
+		req = addChiURLParam(req, "userId", "9")
+		rr := httptest.NewRecorder()
+		handler.BanPlayer(rr, req)
+
+		if rr.Code != http.StatusForbidden {
+			t.Fatalf("expected 403, got %d", rr.Code)
+		}
+	})
+
+	t.Run("DM cannot ban themselves", func(t *testing.T) {
+		handler, mock, cleanup := newMockCampaignHandler(t)
+		defer cleanup()
+
+		expectCampaignAccess(mock, 10, 7, 7)
+
+		req := withCampaignUser(httptest.NewRequest(http.MethodPost, "/api/campaigns/10/players/7/ban", nil), "10", 7)
+		req = addChiURLParam(req, "userId", "7")
+		rr := httptest.NewRecorder()
+		handler.BanPlayer(rr, req)
+
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rr.Code)
+		}
+	})
+
+	t.Run("already banned", func(t *testing.T) {
+		handler, mock, cleanup := newMockCampaignHandler(t)
+		defer cleanup()
+
+		expectCampaignAccess(mock, 10, 7, 7)
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT INTO campaign_players .* ON CONFLICT`).WithArgs("banned", 10, 42).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		req := withCampaignUser(httptest.NewRequest(http.MethodPost, "/api/campaigns/10/players/42/ban", nil), "10", 7)
+		req = addChiURLParam(req, "userId", "42")
+		rr := httptest.NewRecorder()
+		handler.BanPlayer(rr, req)
+
+		if rr.Code != http.StatusConflict {
+			t.Fatalf("expected 409, got %d", rr.Code)
+		}
+	})
+
+	t.Run("player who already left", func(t *testing.T) {
+		handler, mock, cleanup := newMockCampaignHandler(t)
+		defer cleanup()
+
+		expectCampaignAccess(mock, 10, 7, 7)
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT INTO campaign_players .* ON CONFLICT`).WithArgs("banned", 10, 42).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(`FROM campaign_characters\s+WHERE campaign_id = \$1 AND player_id = \$2`).WithArgs(10, 42).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectCommit()
+
+		req := withCampaignUser(httptest.NewRequest(http.MethodPost, "/api/campaigns/10/players/42/ban", nil), "10", 7)
+		req = addChiURLParam(req, "userId", "42")
+		rr := httptest.NewRecorder()
+		handler.BanPlayer(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+}
+
+func TestCampaignHandler_SetPlayerRole(t *testing.T) {
+	handler, mock, cleanup := newMockCampaignHandler(t)
+	defer cleanup()
+
+	expectCampaignAccess(mock, 10, 7, 7, 8)
+
+	req := withCampaignUser(httptest.NewRequest(http.MethodPut, "/api/campaigns/10/players/8/role", bytes.NewBufferString(`{"role":"owner"}`)), "10", 7)
+	req = addChiURLParam(req, "userId", "8")
+	rr := httptest.NewRecorder()
+	handler.SetPlayerRole(rr, req)
+
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422 for invalid role, got %d", rr.Code)
+	}
+
+	expectCampaignAccess(mock, 10, 7, 7, 8)
+	mock.ExpectExec(`UPDATE campaign_players SET role = \$1`).WithArgs("co_dm", 10, 8).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	req = withCampaignUser(httptest.NewRequest(http.MethodPut, "/api/campaigns/10/players/8/role", bytes.NewBufferString(`{"role":"co_dm"}`)), "10", 7)
+	req = addChiURLParam(req, "userId", "8")
+	rr = httptest.NewRecorder()
+	handler.SetPlayerRole(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestCampaignHandler_TransferOwnership(t *testing.T) {
+	handler, mock, cleanup := newMockCampaignHandler(t)
+	defer cleanup()
+
+	expectCampaignAccess(mock, 10, 7, 7, 8)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT status FROM campaign_players`).WithArgs(10, 8).
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("active"))
+	mock.ExpectExec(`UPDATE campaigns SET dm_id = \$1`).WithArgs(8, sqlmock.AnyArg(), 10, 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM campaign_players`).WithArgs(10, 8).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO campaign_players`).WithArgs(10, 7, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	req := withCampaignUser(httptest.NewRequest(http.MethodPost, "/api/campaigns/10/transfer", bytes.NewBufferString(`{"user_id":8}`)), "10", 7)
+	rr := httptest.NewRecorder()
+	handler.TransferOwnership(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+
+	// O alvo precisa ser player ativo
+	expectCampaignAccess(mock, 10, 7, 7)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT status FROM campaign_players`).WithArgs(10, 9).
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("pending"))
+	mock.ExpectRollback()
+
+	req = withCampaignUser(httptest.NewRequest(http.MethodPost, "/api/campaigns/10/transfer", bytes.NewBufferString(`{"user_id":9}`)), "10", 7)
+	rr = httptest.NewRecorder()
+	handler.TransferOwnership(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rr.Code)
+	}
+}
+
+func TestWikiHandler_CoDMCanEditButNotDelete(t *testing.T) {
+	handler, mock, cleanup := newMockWikiHandler(t)
+	defer cleanup()
+
+	expectCampaignAccessWithCoDMs(mock, 10, 8, 7, []int{8}, 8)
+	mock.ExpectQuery(`INSERT INTO campaign_wiki_entries`).
+		WithArgs(10, "lore", "The Sundering", "", sqlmock.AnyArg(), sqlmock.AnyArg(),
+			sqlmock.AnyArg(), sqlmock.AnyArg(), 8, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	body := bytes.NewBufferString(`{"entry_type":"lore","title":"The Sundering"}`)
+	req := withCampaignUser(httptest.NewRequest(http.MethodPost, "/api/campaigns/10/wiki", body), "10", 8)
+	rr := httptest.NewRecorder()
+	handler.CreateEntry(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected co-DM create to succeed, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	expectCampaignAccessWithCoDMs(mock, 10, 8, 7, []int{8}, 8)
+
+	req = withCampaignUser(httptest.NewRequest(http.MethodDelete, "/api/campaigns/10/wiki/1", nil), "10", 8)
+	req = addChiURLParam(req, "entryId", "1")
+	rr = httptest.NewRecorder()
+	handler.DeleteEntry(rr, req)
+
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected co-DM delete to be forbidden, got %d", rr.Code)
+	}
+}
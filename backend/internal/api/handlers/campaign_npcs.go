@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"rpg-saas-backend/internal/models"
+	"rpg-saas-backend/internal/utils"
+)
+
+// GetCampaignNPCs lista os NPCs da campanha. DM e co-DMs veem todos; players, só os revelados.
+func (h *NPCHandler) GetCampaignNPCs(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	npcs, err := h.DB.GetCampaignNPCs(r.Context(), campaign.ID, !canManageCampaign(campaign, userID))
+	if err != nil {
+		h.Response.HandleDBError(w, err, "fetch campaign NPCs")
+		return
+	}
+
+	h.Response.SendJSON(w, map[string]any{
+		"npcs":  npcs,
+		"count": len(npcs),
+	}, http.StatusOK)
+}
+
+// CreateCampaignNPC cria um NPC vinculado à campanha, escondido dos players até ser
+// revelado (DM ou co-DM)
+func (h *NPCHandler) CreateCampaignNPC(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	if !canManageCampaign(campaign, userID) {
+		h.Response.SendForbidden(w, "Only the DM can add NPCs to the campaign")
+		return
+	}
+
+	var npc models.NPC
+	if err := json.NewDecoder(r.Body).Decode(&npc); err != nil {
+		h.Response.SendBadRequest(w, "Invalid request body")
+		return
+	}
+
+	validationErrors := h.Validator.BatchValidate(
+		func() error { return h.Validator.ValidateName(npc.Name, "name") },
+		func() error { return h.Validator.ValidateLevel(npc.Level) },
+	)
+	if validationErrors.HasErrors() {
+		h.Response.SendValidationError(w, validationErrors.Error())
+		return
+	}
+
+	npc.CampaignID = &campaign.ID
+	if err := h.DB.CreateNPC(r.Context(), &npc); err != nil {
+		h.Response.HandleDBError(w, err, "create campaign NPC")
+		return
+	}
+
+	h.Response.SendCreated(w, "NPC created successfully", npc)
+}
+
+// RevealCampaignNPC revela ou esconde um NPC da campanha para os players (DM ou co-DM)
+func (h *NPCHandler) RevealCampaignNPC(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	if !canManageCampaign(campaign, userID) {
+		h.Response.SendForbidden(w, "Only the DM can reveal NPCs")
+		return
+	}
+
+	npcID, err := utils.ExtractIDParam(r, "npcId")
+	if err != nil {
+		h.Response.SendBadRequest(w, "Invalid NPC ID")
+		return
+	}
+
+	var req models.RevealRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Response.SendBadRequest(w, "Invalid request body")
+		return
+	}
+
+	npc, err := h.DB.SetNPCRevealed(r.Context(), campaign.ID, npcID, req.Revealed)
+	if err != nil {
+		h.Response.HandleDBError(w, err, "reveal campaign NPC")
+		return
+	}
+	if npc == nil {
+		h.Response.SendNotFound(w, "NPC not found")
+		return
+	}
+
+	h.Response.SendSuccess(w, "NPC updated successfully", npc)
+}
+
+// GetCampaignEncounters lista os encontros da campanha. DM e co-DMs veem todos; players, só
+// os revelados.
+func (h *EncounterHandler) GetCampaignEncounters(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	encounters, err := h.DB.GetCampaignEncounters(r.Context(), campaign.ID, !canManageCampaign(campaign, userID))
+	if err != nil {
+		h.Response.HandleDBError(w, err, "fetch campaign encounters")
+		return
+	}
+
+	h.Response.SendJSON(w, map[string]any{
+		"encounters": encounters,
+		"count":      len(encounters),
+	}, http.StatusOK)
+}
+
+// CreateCampaignEncounter cria um encontro vinculado à campanha, escondido dos players até
+// ser revelado (DM ou co-DM)
+func (h *EncounterHandler) CreateCampaignEncounter(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	if !canManageCampaign(campaign, userID) {
+		h.Response.SendForbidden(w, "Only the DM can add encounters to the campaign")
+		return
+	}
+
+	var encounter models.Encounter
+	if err := json.NewDecoder(r.Body).Decode(&encounter); err != nil {
+		h.Response.SendBadRequest(w, "Invalid request body")
+		return
+	}
+
+	encounter.CampaignID = &campaign.ID
+	if err := h.DB.CreateEncounter(r.Context(), &encounter); err != nil {
+		h.Response.HandleDBError(w, err, "create campaign encounter")
+		return
+	}
+
+	h.Response.SendCreated(w, "Encounter created successfully", encounter)
+}
+
+// RevealCampaignEncounter revela ou esconde um encontro da campanha para os players
+// (DM ou co-DM)
+func (h *EncounterHandler) RevealCampaignEncounter(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	if !canManageCampaign(campaign, userID) {
+		h.Response.SendForbidden(w, "Only the DM can reveal encounters")
+		return
+	}
+
+	encounterID, err := utils.ExtractIDParam(r, "encounterId")
+	if err != nil {
+		h.Response.SendBadRequest(w, "Invalid encounter ID")
+		return
+	}
+
+	var req models.RevealRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Response.SendBadRequest(w, "Invalid request body")
+		return
+	}
+
+	encounter, err := h.DB.SetEncounterRevealed(r.Context(), campaign.ID, encounterID, req.Revealed)
+	if err != nil {
+		h.Response.HandleDBError(w, err, "reveal campaign encounter")
+		return
+	}
+	if encounter == nil {
+		h.Response.SendNotFound(w, "Encounter not found")
+		return
+	}
+
+	h.Response.SendSuccess(w, "Encounter updated successfully", encounter)
+}
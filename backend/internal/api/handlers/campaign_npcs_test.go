@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+
+	"rpg-saas-backend/internal/db"
+	"rpg-saas-backend/internal/python"
+)
+
+var campaignNPCCols = []string{
+	"id", "name", "description", "level", "race", "class", "background",
+	"attributes", "abilities", "equipment", "hp", "ca", "is_homebrew", "campaign_id", "revealed", "created_at",
+}
+
+func TestNPCHandler_GetCampaignNPCs(t *testing.T) {
+	t.Run("player sees only revealed", func(t *testing.T) {
+		handler, mock, cleanup := newMockNPCHandler(t)
+		defer cleanup()
+
+		expectCampaignAccess(mock, 10, 8, 7, 8)
+		mock.ExpectQuery(`FROM npcs WHERE campaign_id = \$1 AND revealed`).WithArgs(10).
+			WillReturnRows(sqlmock.NewRows(campaignNPCCols).
+				AddRow(3, "Durnan", "", 5, "human", "fighter", "", []byte(`{}`), []byte(`{}`), []byte(`{}`), 40, 15, false, 10, true, time.Now()))
+
+		req := withCampaignUser(httptest.NewRequest(http.MethodGet, "/api/campaigns/10/npcs", nil), "10", 8)
+		rr := httptest.NewRecorder()
+		handler.GetCampaignNPCs(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+
+	t.Run("co-DM sees everything", func(t *testing.T) {
+		handler, mock, cleanup := newMockNPCHandler(t)
+		defer cleanup()
+
+		expectCampaignAccessWithCoDMs(mock, 10, 8, 7, []int{8}, 8)
+		mock.ExpectQuery(`FROM npcs WHERE campaign_id = \$1 ORDER BY id`).WithArgs(10).
+			WillReturnRows(sqlmock.NewRows(campaignNPCCols))
+
+		req := withCampaignUser(httptest.NewRequest(http.MethodGet, "/api/campaigns/10/npcs", nil), "10", 8)
+		rr := httptest.NewRecorder()
+		handler.GetCampaignNPCs(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+}
+
+func TestNPCHandler_CreateCampaignNPC(t *testing.T) {
+	t.Run("player cannot create", func(t *testing.T) {
+		handler, mock, cleanup := newMockNPCHandler(t)
+		defer cleanup()
+
+		expectCampaignAccess(mock, 10, 8, 7, 8)
+
+		req := withCampaignUser(httptest.NewRequest(http.MethodPost, "/api/campaigns/10/npcs", bytes.NewBufferString(`{"name":"Spy","level":1}`)), "10", 8)
+		rr := httptest.NewRecorder()
+		handler.CreateCampaignNPC(rr, req)
+
+		if rr.Code != http.StatusForbidden {
+			t.Fatalf("expected 403, got %d", rr.Code)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+
+	t.Run("DM creates hidden NPC", func(t *testing.T) {
+		handler, mock, cleanup := newMockNPCHandler(t)
+		defer cleanup()
+
+		expectCampaignAccess(mock, 10, 7, 7)
+		mock.ExpectQuery(`INSERT INTO npcs`).WithArgs(
+			"Spy", "", 1, "", "", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), 0, 0, 10, false,
+		).WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(21, time.Now()))
+
+		req := withCampaignUser(httptest.NewRequest(http.MethodPost, "/api/campaigns/10/npcs", bytes.NewBufferString(`{"name":"Spy","level":1,"campaign_id":99}`)), "10", 7)
+		rr := httptest.NewRecorder()
+		handler.CreateCampaignNPC(rr, req)
+
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+}
+
+func TestNPCHandler_RevealCampaignNPC(t *testing.T) {
+	t.Run("reveals", func(t *testing.T) {
+		handler, mock, cleanup := newMockNPCHandler(t)
+		defer cleanup()
+
+		expectCampaignAccess(mock, 10, 7, 7)
+		mock.ExpectQuery(`UPDATE npcs SET revealed = \$1 WHERE id = \$2 AND campaign_id = \$3`).WithArgs(true, 3, 10).
+			WillReturnRows(sqlmock.NewRows(campaignNPCCols).
+				AddRow(3, "Durnan", "", 5, "human", "fighter", "", []byte(`{}`), []byte(`{}`), []byte(`{}`), 40, 15, false, 10, true, time.Now()))
+
+		req := withCampaignUser(httptest.NewRequest(http.MethodPut, "/api/campaigns/10/npcs/3/reveal", bytes.NewBufferString(`{"revealed":true}`)), "10", 7)
+		req = addChiURLParam(req, "npcId", "3")
+		rr := httptest.NewRecorder()
+		handler.RevealCampaignNPC(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		var resp struct {
+			Data struct {
+				Revealed bool `json:"revealed"`
+			} `json:"data"`
+		}
+		if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if !resp.Data.Revealed {
+			t.Fatalf("expected NPC to be revealed")
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+
+	t.Run("NPC from another campaign", func(t *testing.T) {
+		handler, mock, cleanup := newMockNPCHandler(t)
+		defer cleanup()
+
+		expectCampaignAccess(mock, 10, 7, 7)
+		mock.ExpectQuery(`UPDATE npcs SET revealed`).WithArgs(true, 4, 10).
+			WillReturnRows(sqlmock.NewRows(campaignNPCCols))
+
+		req := withCampaignUser(httptest.NewRequest(http.MethodPut, "/api/campaigns/10/npcs/4/reveal", bytes.NewBufferString(`{"revealed":true}`)), "10", 7)
+		req = addChiURLParam(req, "npcId", "4")
+		rr := httptest.NewRecorder()
+		handler.RevealCampaignNPC(rr, req)
+
+		if rr.Code != http.StatusNotFound {
+			t.Fatalf("expected 404, got %d", rr.Code)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+}
+
+func TestNPCHandler_GetNPCByIDHidesCampaignNPC(t *testing.T) {
+	handler, mock, cleanup := newMockNPCHandler(t)
+	defer cleanup()
+
+	mock.ExpectQuery(`SELECT \* FROM npcs WHERE id = \$1`).WithArgs(3).
+		WillReturnRows(sqlmock.NewRows(campaignNPCCols).
+			AddRow(3, "Durnan", "", 5, "human", "fighter", "", []byte(`{}`), []byte(`{}`), []byte(`{}`), 40, 15, false, 10, false, time.Now()))
+
+	req := withCampaignUser(httptest.NewRequest(http.MethodGet, "/api/npcs/3", nil), "3", 8)
+	rr := httptest.NewRecorder()
+	handler.GetNPCByID(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rr.Code)
+	}
+}
+
+func TestEncounterHandler_GenerateForCampaignRequiresDM(t *testing.T) {
+	rawDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer rawDB.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("python service should not be called")
+	}))
+	defer server.Close()
+
+	pdb := &db.PostgresDB{DB: sqlx.NewDb(rawDB, "postgres")}
+	handler := NewEncounterHandler(pdb, &python.Client{BaseURL: server.URL, HTTPClient: server.Client()})
+
+	expectCampaignAccess(mock, 10, 8, 7, 8)
+
+	body := `{"player_level":2,"player_count":4,"difficulty":"m","campaign_id":10}`
+	req := withCampaignUser(httptest.NewRequest(http.MethodPost, "/api/encounters/generate", bytes.NewBufferString(body)), "", 8)
+	rr := httptest.NewRecorder()
+	handler.GenerateRandomEncounter(rr, req)
+
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"rpg-saas-backend/internal/models"
+	"rpg-saas-backend/internal/utils"
+)
+
+// campaignStatusEvent valida a mudança de status da campanha (DM ou co-DM) e monta o evento
+// a gravar. Status igual ao atual não altera nada e retorna evento nil. Em caso de erro, a
+// resposta já foi enviada e ok é false.
+func (h *CampaignHandler) campaignStatusEvent(w http.ResponseWriter, r *http.Request, campaignID, userID int, status string) (*models.CampaignStatusEvent, bool) {
+	campaign, err := h.DB.GetCampaignByID(r.Context(), campaignID, userID)
+	if err != nil {
+		h.Response.SendNotFound(w, "Campaign not found or access denied")
+		return nil, false
+	}
+
+	if !canManageCampaign(campaign, userID) {
+		h.Response.SendForbidden(w, "Only the DM can change the campaign status")
+		return nil, false
+	}
+
+	if status == campaign.Status {
+		return nil, true
+	}
+
+	if err := models.ValidateCampaignStatusTransition(campaign.Status, status); err != nil {
+		h.Response.SendValidationError(w, err.Error())
+		return nil, false
+	}
+
+	return &models.CampaignStatusEvent{From: campaign.Status, To: status, ChangedBy: userID}, true
+}
+
+// announceCampaignStatusChange registra a atividade da mudança de status já gravada e, ao
+// pausar ou concluir, fecha as conexões ao vivo das salas. Só deve rodar após o commit.
+func (h *CampaignHandler) announceCampaignStatusChange(r *http.Request, campaignID, userID int, change *models.CampaignStatusChange) {
+	h.Activity.Record(r.Context(), models.CampaignActivity{
+		CampaignID: campaignID,
+		ActorID:    &userID,
+		EventType:  models.ActivityCampaignStatusChanged,
+		TargetType: models.ActivityTargetCampaign,
+		TargetID:   activityTarget(campaignID),
+		Summary:    "Campaign is now " + change.To,
+		Metadata:   models.JSONB{"from": change.From, "to": change.To, "frozen_characters": change.FrozenCharacters},
+	})
+
+	if h.Hub != nil {
+		for _, roomID := range change.RoomIDs {
+			change.ClosedSockets += h.Hub.CloseRoom(roomID, RoomSocketMessage{
+				Type:     "campaign:" + change.To,
+				RoomID:   roomID,
+				SenderID: userID,
+				Message:  "The campaign is " + change.To,
+				Metadata: map[string]any{
+					"campaign_id": campaignID,
+					"status":      change.To,
+				},
+				Timestamp: time.Now().UnixMilli(),
+			})
+		}
+	}
+}
+
+// checkCampaignNotCompleted responde 409 e retorna false se a campanha já foi concluída
+func checkCampaignNotCompleted(w http.ResponseWriter, response *utils.ResponseHandler, campaign *models.Campaign) bool {
+	if models.CampaignAcceptsChanges(campaign.Status) {
+		return true
+	}
+	response.SendConflict(w, "Campaign is completed; its characters are frozen")
+	return false
+}
+
+// checkCharacterNotFrozen responde 409 e retorna false se a ficha foi congelada na conclusão
+// da campanha
+func checkCharacterNotFrozen(w http.ResponseWriter, response *utils.ResponseHandler, character *models.CampaignCharacter) bool {
+	if character.FrozenAt == nil {
+		return true
+	}
+	response.SendConflict(w, "Character is frozen: its campaign is completed")
+	return false
+}
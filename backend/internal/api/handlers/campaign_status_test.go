@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+
+	"rpg-saas-backend/internal/models"
+)
+
+func TestCampaignHandler_UpdateCampaignStatusLifecycle(t *testing.T) {
+	t.Run("illegal transition is rejected", func(t *testing.T) {
+		handler, mock, cleanup := newMockCampaignHandler(t)
+		defer cleanup()
+
+		expectCampaignAccess(mock, 15, 7, 7, 8)
+
+		req := withCampaignUser(httptest.NewRequest(http.MethodPut, "/api/campaigns/15", bytes.NewBufferString(`{"name":"Updated","status":"completed"}`)), "15", 7)
+		rr := httptest.NewRecorder()
+		handler.UpdateCampaign(rr, req)
+
+		if rr.Code != http.StatusUnprocessableEntity {
+			t.Fatalf("expected 422, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+
+	t.Run("players cannot change the status", func(t *testing.T) {
+		handler, mock, cleanup := newMockCampaignHandler(t)
+		defer cleanup()
+
+		expectCampaignAccess(mock, 15, 8, 7, 8)
+
+		req := withCampaignUser(httptest.NewRequest(http.MethodPut, "/api/campaigns/15", bytes.NewBufferString(`{"status":"paused"}`)), "15", 8)
+		rr := httptest.NewRecorder()
+		handler.UpdateCampaign(rr, req)
+
+		if rr.Code != http.StatusForbidden {
+			t.Fatalf("expected 403, got %d: %s", rr.Code, rr.Body.String())
+		}
+	})
+
+	t.Run("activating without players is rejected", func(t *testing.T) {
+		handler, mock, cleanup := newMockCampaignHandler(t)
+		defer cleanup()
+
+		expectCampaignAccessWithStatus(mock, models.CampaignStatusPlanning, 15, 7, 7)
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE campaigns SET status = \$1`).
+			WithArgs(models.CampaignStatusActive, 15, models.CampaignStatusPlanning).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FROM campaign_players`).WithArgs(15).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectRollback()
+
+		req := withCampaignUser(httptest.NewRequest(http.MethodPut, "/api/campaigns/15", bytes.NewBufferString(`{"status":"active"}`)), "15", 7)
+		rr := httptest.NewRecorder()
+		handler.UpdateCampaign(rr, req)
+
+		if rr.Code != http.StatusUnprocessableEntity {
+			t.Fatalf("expected 422, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+
+	t.Run("pausing reports the campaign rooms", func(t *testing.T) {
+		handler, mock, cleanup := newMockCampaignHandler(t)
+		defer cleanup()
+		handler.Hub = NewRoomHub()
+
+		expectCampaignAccess(mock, 15, 7, 7, 8)
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE campaigns SET status = \$1`).
+			WithArgs(models.CampaignStatusPaused, 15, models.CampaignStatusActive).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(`SELECT id FROM rooms WHERE campaign_id = \$1`).WithArgs(15).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("room-1"))
+		mock.ExpectExec(`UPDATE campaigns SET`).WithArgs(
+			"Campaign", "", 0, 0, "", false, "", sqlmock.AnyArg(), 15, 7,
+		).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		req := withCampaignUser(httptest.NewRequest(http.MethodPut, "/api/campaigns/15", bytes.NewBufferString(`{"name":"Campaign","status":"paused"}`)), "15", 7)
+		rr := httptest.NewRecorder()
+		handler.UpdateCampaign(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		var body struct {
+			Status       string                      `json:"status"`
+			StatusChange models.CampaignStatusChange `json:"status_change"`
+		}
+		if err := json.NewDecoder(rr.Body).Decode(&body); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if body.Status != models.CampaignStatusPaused || len(body.StatusChange.RoomIDs) != 1 {
+			t.Fatalf("expected paused campaign with one closed room, got %+v", body)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+
+	t.Run("failed field update rolls back the status change", func(t *testing.T) {
+		handler, mock, cleanup := newMockCampaignHandler(t)
+		defer cleanup()
+
+		expectCampaignAccess(mock, 15, 7, 7, 8)
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE campaigns SET status = \$1`).
+			WithArgs(models.CampaignStatusPaused, 15, models.CampaignStatusActive).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(`SELECT id FROM rooms WHERE campaign_id = \$1`).WithArgs(15).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("room-1"))
+		mock.ExpectExec(`UPDATE campaigns SET`).WillReturnError(sqlmock.ErrCancelled)
+		mock.ExpectRollback()
+
+		req := withCampaignUser(httptest.NewRequest(http.MethodPut, "/api/campaigns/15", bytes.NewBufferString(`{"name":"Campaign","status":"paused"}`)), "15", 7)
+		rr := httptest.NewRecorder()
+		handler.UpdateCampaign(rr, req)
+
+		if rr.Code != http.StatusInternalServerError {
+			t.Fatalf("expected 500, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+}
+
+func TestCampaignHandler_CompletedCampaignIsFrozen(t *testing.T) {
+	t.Run("frozen character can't be updated", func(t *testing.T) {
+		handler, mock, cleanup := newMockCampaignHandler(t)
+		defer cleanup()
+
+		mock.ExpectQuery(`FROM campaign_characters cc`).WithArgs(5, 10, 8).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "campaign_id", "player_id", "source_pc_id", "status", "name", "level", "frozen_at"}).
+				AddRow(5, 10, 8, 3, "active", "Aria", 4, time.Now()))
+
+		req := withCampaignUser(httptest.NewRequest(http.MethodPut, "/api/campaigns/10/characters/5", bytes.NewBufferString(`{"current_hp":3}`)), "10", 8)
+		req = addChiURLParam(req, "characterId", "5")
+		rr := httptest.NewRecorder()
+		handler.UpdateCampaignCharacter(rr, req)
+
+		if rr.Code != http.StatusConflict {
+			t.Fatalf("expected 409, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+
+	t.Run("completed campaign can't award XP", func(t *testing.T) {
+		handler, mock, cleanup := newMockCampaignHandler(t)
+		defer cleanup()
+
+		expectCampaignAccessWithStatus(mock, models.CampaignStatusCompleted, 10, 7, 7, 8)
+
+		req := withCampaignUser(httptest.NewRequest(http.MethodPost, "/api/campaigns/10/xp", bytes.NewBufferString(`{"amount":300}`)), "10", 7)
+		rr := httptest.NewRecorder()
+		handler.AwardXP(rr, req)
+
+		if rr.Code != http.StatusConflict {
+			t.Fatalf("expected 409, got %d: %s", rr.Code, rr.Body.String())
+		}
+	})
+
+	t.Run("frozen character can't be deleted", func(t *testing.T) {
+		handler, mock, cleanup := newMockCampaignHandler(t)
+		defer cleanup()
+
+		mock.ExpectQuery(`FROM campaign_characters cc`).WithArgs(5, 10, 7).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "campaign_id", "player_id", "source_pc_id", "status", "name", "level", "frozen_at"}).
+				AddRow(5, 10, 8, 3, "active", "Aria", 4, time.Now()))
+
+		req := withCampaignUser(httptest.NewRequest(http.MethodDelete, "/api/campaigns/10/characters/5", nil), "10", 7)
+		req = addChiURLParam(req, "characterId", "5")
+		rr := httptest.NewRecorder()
+		handler.DeleteCampaignCharacter(rr, req)
+
+		if rr.Code != http.StatusConflict {
+			t.Fatalf("expected 409, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+
+	t.Run("completed campaign can't take new characters", func(t *testing.T) {
+		handler, mock, cleanup := newMockCampaignHandler(t)
+		defer cleanup()
+
+		mock.ExpectQuery(`FROM pcs`).WithArgs(4).WillReturnRows(sqlmock.NewRows([]string{
+			"id", "name", "race", "class", "background", "level", "player_id",
+		}).AddRow(4, "PC", "Elf", "Wizard", "Sage", 3, 8))
+		mock.ExpectQuery(`SELECT EXISTS\(`).WithArgs(10, 8).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+		mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM campaign_characters`).WithArgs(10, 4).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+		expectCampaignAccessWithStatus(mock, models.CampaignStatusCompleted, 10, 8, 7, 8)
+
+		req := withCampaignUser(httptest.NewRequest(http.MethodPost, "/api/campaigns/10/characters", bytes.NewBufferString(`{"source_pc_id":4}`)), "10", 8)
+		rr := httptest.NewRecorder()
+		handler.AddCharacterToCampaign(rr, req)
+
+		if rr.Code != http.StatusConflict {
+			t.Fatalf("expected 409, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+}
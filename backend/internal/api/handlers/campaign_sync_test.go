@@ -0,0 +1,347 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+
+	"rpg-saas-backend/internal/api/middleware"
+	"rpg-saas-backend/internal/models"
+)
+
+var syncCharCols = []string{
+	"id", "campaign_id", "player_id", "source_pc_id", "status", "joined_at", "last_sync", "campaign_notes",
+	"name", "description", "level", "race", "class", "background", "alignment", "attributes", "abilities",
+	"equipment", "hp", "current_hp", "ca", "proficiency_bonus", "inspiration", "skills", "attacks", "spells",
+	"personality_traits", "ideals", "bonds", "flaws", "features", "player_name",
+}
+
+var syncPCCols = []string{
+	"id", "name", "description", "level", "race", "class", "background", "alignment",
+	"attributes", "abilities", "equipment", "hp", "current_hp", "ca", "proficiency_bonus",
+	"inspiration", "skills", "attacks", "spells", "personality_traits", "ideals", "bonds",
+	"flaws", "features", "player_name", "player_id", "is_homebrew", "is_unique", "created_at",
+}
+
+// syncCharRow monta um snapshot de teste com nome e nível configuráveis
+func syncCharRow(id, campaignID int, name string, level int) []driver.Value {
+	now := time.Now()
+	return []driver.Value{
+		id, campaignID, 7, 3, "active", now, now, "note", name, "desc", level, "elf", "wizard", "sage", "neutral",
+		[]byte(`{}`), []byte(`{}`), []byte(`{}`), 20, 18, 14, 2, false, []byte(`[]`), []byte(`[]`), []byte(`[]`),
+		"brave", "ideal", "bond", "flaw", pq.StringArray{"feature"}, "Player",
+	}
+}
+
+// syncPCRow monta o PC original de teste com nome e nível configuráveis
+func syncPCRow(name string, level int) []driver.Value {
+	return []driver.Value{
+		3, name, "desc", level, "elf", "wizard", "sage", "neutral",
+		[]byte(`{}`), []byte(`{}`), []byte(`{}`), 20, 20, 14, 2, false,
+		[]byte(`[]`), []byte(`[]`), []byte(`[]`), "brave", "ideal", "bond", "flaw", pq.StringArray{"feature"},
+		"Player", 7, false, false, time.Now(),
+	}
+}
+
+// syncBaseJSON monta o estado base salvo no último sync
+func syncBaseJSON(t *testing.T, name string, level int) []byte {
+	t.Helper()
+	char := &models.CampaignCharacter{
+		Name: name, Description: "desc", Level: level, Race: "elf", Class: "wizard", Background: "sage",
+		Alignment: "neutral", Attributes: models.JSONBFlexible{Data: map[string]any{}},
+		Abilities: models.JSONBFlexible{Data: map[string]any{}}, Equipment: models.JSONBFlexible{Data: map[string]any{}},
+		HP: 20, CA: 14, ProficiencyBonus: 2, Skills: models.JSONBFlexible{Data: []any{}},
+		Attacks: models.JSONBFlexible{Data: []any{}}, Spells: models.JSONBFlexible{Data: []any{}},
+		PersonalityTraits: "brave", Ideals: "ideal", Bonds: "bond", Flaws: "flaw",
+		Features: pq.StringArray{"feature"}, PlayerName: "Player",
+	}
+	data, err := json.Marshal(char.SyncState())
+	if err != nil {
+		t.Fatalf("failed to marshal base: %v", err)
+	}
+	return data
+}
+
+func newSyncRequest(body string, userID int) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/api/campaigns/80/characters/5/sync", bytes.NewBufferString(body))
+	req = addChiURLParam(req, "id", "80")
+	req = addChiURLParam(req, "characterId", "5")
+	return req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+}
+
+func TestCampaignHandler_SyncPushWithDiffs(t *testing.T) {
+	handler, mock, cleanup := newMockCampaignHandler(t)
+	defer cleanup()
+
+	// Snapshot subiu para o nível 4; PC continua igual à base
+	mock.ExpectQuery(`FROM campaign_characters cc`).WithArgs(5, 80, 7).
+		WillReturnRows(sqlmock.NewRows(syncCharCols).AddRow(syncCharRow(5, 80, "PC", 4)...))
+	mock.ExpectQuery(`FROM pcs`).WithArgs(3, 7).
+		WillReturnRows(sqlmock.NewRows(syncPCCols).AddRow(syncPCRow("PC", 3)...))
+	mock.ExpectQuery(`SELECT sync_base FROM campaign_characters`).WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"sync_base"}).AddRow(syncBaseJSON(t, "PC", 3)))
+	mock.ExpectBegin()
+	expectVersionHistory(mock, models.CharacterTypePC, 3)
+	mock.ExpectExec(`UPDATE pcs SET`).WillReturnResult(sqlmock.NewResult(0, 1))
+	expectPCVersionRecorded(mock, 3)
+	mock.ExpectExec(`UPDATE campaign_characters SET\s+sync_base`).WithArgs(sqlmock.AnyArg(), 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	rec := httptest.NewRecorder()
+	handler.SyncCampaignCharacter(rec, newSyncRequest(`{}`, 7))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp models.SyncCharacterResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Direction != models.SyncDirectionPush || len(resp.Diffs) != 1 || resp.Diffs[0].Field != "level" || len(resp.Conflicts) != 0 ||
+		len(resp.Applied) != 1 || resp.Applied[0] != "level" {
+		t.Fatalf("unexpected sync response: %+v", resp)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestCampaignHandler_SyncConflict(t *testing.T) {
+	handler, mock, cleanup := newMockCampaignHandler(t)
+	defer cleanup()
+
+	// Snapshot e PC mudaram o nível para valores diferentes desde a base
+	mock.ExpectQuery(`FROM campaign_characters cc`).WithArgs(5, 80, 7).
+		WillReturnRows(sqlmock.NewRows(syncCharCols).AddRow(syncCharRow(5, 80, "PC", 4)...))
+	mock.ExpectQuery(`FROM pcs`).WithArgs(3, 7).
+		WillReturnRows(sqlmock.NewRows(syncPCCols).AddRow(syncPCRow("PC", 5)...))
+	mock.ExpectQuery(`SELECT sync_base FROM campaign_characters`).WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"sync_base"}).AddRow(syncBaseJSON(t, "PC", 3)))
+
+	rec := httptest.NewRecorder()
+	handler.SyncCampaignCharacter(rec, newSyncRequest(`{"direction":"pull"}`, 7))
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp models.SyncCharacterResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Success || len(resp.Conflicts) != 1 || resp.Conflicts[0].Field != "level" {
+		t.Fatalf("expected level conflict, got %+v", resp)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestCampaignHandler_SyncPullToOtherCampaigns(t *testing.T) {
+	handler, mock, cleanup := newMockCampaignHandler(t)
+	defer cleanup()
+
+	// PC foi renomeado; esta campanha e a 81 estão na base, a 82 renomeou localmente
+	mock.ExpectQuery(`FROM campaign_characters cc`).WithArgs(5, 80, 7).
+		WillReturnRows(sqlmock.NewRows(syncCharCols).AddRow(syncCharRow(5, 80, "PC", 3)...))
+	mock.ExpectQuery(`FROM pcs`).WithArgs(3, 7).
+		WillReturnRows(sqlmock.NewRows(syncPCCols).AddRow(syncPCRow("Renamed", 3)...))
+	mock.ExpectQuery(`SELECT sync_base FROM campaign_characters`).WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"sync_base"}).AddRow(syncBaseJSON(t, "PC", 3)))
+
+	otherCols := append(append([]string{}, syncCharCols[:8]...), append([]string{"sync_base"}, syncCharCols[8:]...)...)
+	withBase := func(row []driver.Value, base []byte) []driver.Value {
+		return append(append(append([]driver.Value{}, row[:8]...), base), row[8:]...)
+	}
+	base := syncBaseJSON(t, "PC", 3)
+	mock.ExpectQuery(`FROM campaign_characters\s+WHERE source_pc_id = \$1`).WithArgs(3).
+		WillReturnRows(sqlmock.NewRows(otherCols).
+			AddRow(withBase(syncCharRow(5, 80, "Renamed", 3), base)...).
+			AddRow(withBase(syncCharRow(6, 81, "PC", 3), base)...).
+			AddRow(withBase(syncCharRow(7, 82, "Local name", 3), base)...))
+
+	// Esta campanha e a 81 recebem o nome novo na mesma transação
+	mock.ExpectBegin()
+	for _, id := range []int{5, 6} {
+		expectVersionHistory(mock, models.CharacterTypeCampaign, id)
+		mock.ExpectExec(`UPDATE campaign_characters SET\s+name`).WillReturnResult(sqlmock.NewResult(0, 1))
+		expectCharacterVersionRecorded(mock, id, 80)
+		mock.ExpectExec(`UPDATE campaign_characters SET\s+sync_base`).WithArgs(sqlmock.AnyArg(), id).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectCommit()
+
+	rec := httptest.NewRecorder()
+	handler.SyncCampaignCharacter(rec, newSyncRequest(`{"direction":"pull","sync_to_other_campaigns":true}`, 7))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp models.SyncCharacterResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.SyncCount != 1 || len(resp.Campaigns) != 2 {
+		t.Fatalf("expected one campaign synced and one skipped, got %+v", resp)
+	}
+	if !resp.Campaigns[0].Synced || resp.Campaigns[1].Synced || len(resp.Campaigns[1].Conflicts) != 1 {
+		t.Fatalf("unexpected per-campaign results: %+v", resp.Campaigns)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestCampaignHandler_SyncPushRequiresOwner(t *testing.T) {
+	handler, mock, cleanup := newMockCampaignHandler(t)
+	defer cleanup()
+
+	// DM (usuário 9) tem acesso ao snapshot, mas não pode alterar o PC do jogador
+	mock.ExpectQuery(`FROM campaign_characters cc`).WithArgs(5, 80, 9).
+		WillReturnRows(sqlmock.NewRows(syncCharCols).AddRow(syncCharRow(5, 80, "PC", 3)...))
+
+	rec := httptest.NewRecorder()
+	handler.SyncCampaignCharacter(rec, newSyncRequest(`{"direction":"push"}`, 9))
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.SyncCampaignCharacter(rec, newSyncRequest(`{"direction":"sideways"}`, 9))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid direction, got %d", rec.Code)
+	}
+}
+
+func TestCampaignHandler_SyncPushKeepsPCEdits(t *testing.T) {
+	handler, mock, cleanup := newMockCampaignHandler(t)
+	defer cleanup()
+
+	// Snapshot subiu de nível e o PC foi renomeado: o push leva só o nível
+	mock.ExpectQuery(`FROM campaign_characters cc`).WithArgs(5, 80, 7).
+		WillReturnRows(sqlmock.NewRows(syncCharCols).AddRow(syncCharRow(5, 80, "PC", 4)...))
+	mock.ExpectQuery(`FROM pcs`).WithArgs(3, 7).
+		WillReturnRows(sqlmock.NewRows(syncPCCols).AddRow(syncPCRow("Renamed", 3)...))
+	mock.ExpectQuery(`SELECT sync_base FROM campaign_characters`).WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"sync_base"}).AddRow(syncBaseJSON(t, "PC", 3)))
+
+	args := []driver.Value{"Renamed", "desc", 4}
+	for len(args) < 29 {
+		args = append(args, sqlmock.AnyArg())
+	}
+	mock.ExpectBegin()
+	expectVersionHistory(mock, models.CharacterTypePC, 3)
+	mock.ExpectExec(`UPDATE pcs SET`).WithArgs(args...).WillReturnResult(sqlmock.NewResult(0, 1))
+	expectPCVersionRecorded(mock, 3)
+	mock.ExpectExec(`UPDATE campaign_characters SET\s+sync_base`).WithArgs(sqlmock.AnyArg(), 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	rec := httptest.NewRecorder()
+	handler.SyncCampaignCharacter(rec, newSyncRequest(`{"direction":"push"}`, 7))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestCampaignHandler_SyncRollsBackOnFailure(t *testing.T) {
+	handler, mock, cleanup := newMockCampaignHandler(t)
+	defer cleanup()
+
+	mock.ExpectQuery(`FROM campaign_characters cc`).WithArgs(5, 80, 7).
+		WillReturnRows(sqlmock.NewRows(syncCharCols).AddRow(syncCharRow(5, 80, "PC", 4)...))
+	mock.ExpectQuery(`FROM pcs`).WithArgs(3, 7).
+		WillReturnRows(sqlmock.NewRows(syncPCCols).AddRow(syncPCRow("PC", 3)...))
+	mock.ExpectQuery(`SELECT sync_base FROM campaign_characters`).WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"sync_base"}).AddRow(syncBaseJSON(t, "PC", 3)))
+	mock.ExpectBegin()
+	expectVersionHistory(mock, models.CharacterTypePC, 3)
+	mock.ExpectExec(`UPDATE pcs SET`).WillReturnResult(sqlmock.NewResult(0, 1))
+	expectPCVersionRecorded(mock, 3)
+	mock.ExpectExec(`UPDATE campaign_characters SET\s+sync_base`).WithArgs(sqlmock.AnyArg(), 5).
+		WillReturnError(sql.ErrConnDone)
+	mock.ExpectRollback()
+
+	rec := httptest.NewRecorder()
+	handler.SyncCampaignCharacter(rec, newSyncRequest(`{}`, 7))
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestCampaignHandler_SyncOtherCampaignsRequiresOwner(t *testing.T) {
+	handler, mock, cleanup := newMockCampaignHandler(t)
+	defer cleanup()
+
+	// O DM pode puxar o PC para o snapshot desta campanha, mas não para as outras do jogador
+	mock.ExpectQuery(`FROM campaign_characters cc`).WithArgs(5, 80, 9).
+		WillReturnRows(sqlmock.NewRows(syncCharCols).AddRow(syncCharRow(5, 80, "PC", 3)...))
+
+	rec := httptest.NewRecorder()
+	handler.SyncCampaignCharacter(rec, newSyncRequest(`{"direction":"pull","sync_to_other_campaigns":true}`, 9))
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestCampaignHandler_SyncSkipsFrozenCampaigns(t *testing.T) {
+	handler, mock, cleanup := newMockCampaignHandler(t)
+	defer cleanup()
+
+	// A campanha 81 congelou o personagem: fica de fora mesmo com force
+	mock.ExpectQuery(`FROM campaign_characters cc`).WithArgs(5, 80, 7).
+		WillReturnRows(sqlmock.NewRows(syncCharCols).AddRow(syncCharRow(5, 80, "PC", 3)...))
+	mock.ExpectQuery(`FROM pcs`).WithArgs(3, 7).
+		WillReturnRows(sqlmock.NewRows(syncPCCols).AddRow(syncPCRow("Renamed", 3)...))
+	mock.ExpectQuery(`SELECT sync_base FROM campaign_characters`).WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"sync_base"}).AddRow(syncBaseJSON(t, "PC", 3)))
+
+	otherCols := append(append([]string{}, syncCharCols...), "sync_base", "frozen_at")
+	base := syncBaseJSON(t, "PC", 3)
+	mock.ExpectQuery(`FROM campaign_characters\s+WHERE source_pc_id = \$1`).WithArgs(3).
+		WillReturnRows(sqlmock.NewRows(otherCols).
+			AddRow(append(syncCharRow(6, 81, "PC", 3), base, time.Now())...))
+
+	mock.ExpectBegin()
+	expectVersionHistory(mock, models.CharacterTypeCampaign, 5)
+	mock.ExpectExec(`UPDATE campaign_characters SET\s+name`).WillReturnResult(sqlmock.NewResult(0, 1))
+	expectCharacterVersionRecorded(mock, 5, 80)
+	mock.ExpectExec(`UPDATE campaign_characters SET\s+sync_base`).WithArgs(sqlmock.AnyArg(), 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	rec := httptest.NewRecorder()
+	handler.SyncCampaignCharacter(rec, newSyncRequest(`{"direction":"pull","sync_to_other_campaigns":true,"force":true}`, 7))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp models.SyncCharacterResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.SyncCount != 0 || len(resp.Campaigns) != 1 || !resp.Campaigns[0].Frozen || resp.Campaigns[0].Synced {
+		t.Fatalf("expected the frozen campaign to be reported as skipped, got %+v", resp)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
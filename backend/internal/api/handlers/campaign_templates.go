@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"rpg-saas-backend/internal/models"
+	"rpg-saas-backend/internal/utils"
+)
+
+// CloneCampaign cria uma cópia da campanha, sem jogadores nem personagens e com novo código
+// de convite (apenas DM)
+func (h *CampaignHandler) CloneCampaign(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	if !isCampaignDM(campaign, userID) {
+		h.Response.SendForbidden(w, "Only the DM can clone the campaign")
+		return
+	}
+
+	var req models.CampaignCopyRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.Response.SendBadRequest(w, "Invalid request body")
+			return
+		}
+	}
+
+	archive, err := h.buildCampaignArchive(r.Context(), campaign)
+	if err != nil {
+		h.Response.HandleDBError(w, err, "clone campaign")
+		return
+	}
+
+	archive.AsTemplate()
+	if strings.TrimSpace(req.Name) == "" {
+		req.Name = campaign.Name + " (copy)"
+	}
+
+	h.createCampaignFromArchive(w, r, userID, archive, req, "Campaign cloned successfully")
+}
+
+// SaveAsTemplate salva a campanha como template: descrição, configurações, NPCs, encontros,
+// wiki, quests e cena, sem jogadores nem personagens (apenas DM)
+func (h *CampaignHandler) SaveAsTemplate(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	if !isCampaignDM(campaign, userID) {
+		h.Response.SendForbidden(w, "Only the DM can save the campaign as a template")
+		return
+	}
+
+	var req models.SaveCampaignTemplateRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.Response.SendBadRequest(w, "Invalid request body")
+			return
+		}
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		req.Name = campaign.Name
+	}
+	if err := h.Validator.ValidateName(req.Name, "name"); err != nil {
+		h.Response.SendValidationError(w, err.Error())
+		return
+	}
+
+	archive, err := h.buildCampaignArchive(r.Context(), campaign)
+	if err != nil {
+		h.Response.HandleDBError(w, err, "build campaign template")
+		return
+	}
+	archive.AsTemplate()
+
+	template := &models.CampaignTemplate{
+		OwnerID:          userID,
+		Name:             req.Name,
+		Description:      req.Description,
+		SourceCampaignID: &campaign.ID,
+		Archive:          archive,
+	}
+	if template.Description == "" {
+		template.Description = campaign.Description
+	}
+
+	if err := h.DB.CreateCampaignTemplate(r.Context(), template); err != nil {
+		h.Response.HandleDBError(w, err, "create campaign template")
+		return
+	}
+
+	h.Response.SendCreated(w, "Campaign template created successfully", template)
+}
+
+// GetTemplates lista os templates de campanha do usuário
+func (h *CampaignHandler) GetTemplates(w http.ResponseWriter, r *http.Request) {
+	userID, err := utils.ExtractUserID(r)
+	if err != nil {
+		h.Response.SendInternalError(w, "User ID not found in context")
+		return
+	}
+
+	templates, err := h.DB.GetCampaignTemplates(r.Context(), userID)
+	if err != nil {
+		h.Response.HandleDBError(w, err, "fetch campaign templates")
+		return
+	}
+
+	h.Response.SendJSON(w, map[string]any{
+		"templates": templates,
+		"count":     len(templates),
+	}, http.StatusOK)
+}
+
+// GetTemplate retorna um template do usuário com o conteúdo completo
+func (h *CampaignHandler) GetTemplate(w http.ResponseWriter, r *http.Request) {
+	template, _, ok := h.loadTemplate(w, r)
+	if !ok {
+		return
+	}
+
+	h.Response.SendJSON(w, template, http.StatusOK)
+}
+
+// DeleteTemplate remove um template do usuário; campanhas criadas a partir dele não mudam
+func (h *CampaignHandler) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	userID, err := utils.ExtractUserID(r)
+	if err != nil {
+		h.Response.SendInternalError(w, "User ID not found in context")
+		return
+	}
+
+	templateID, err := utils.ExtractID(r)
+	if err != nil {
+		h.Response.SendBadRequest(w, "Invalid template ID")
+		return
+	}
+
+	if err := h.DB.DeleteCampaignTemplate(r.Context(), templateID, userID); err != nil {
+		h.Response.SendNotFound(w, "Campaign template not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CreateCampaignFromTemplate inicia uma campanha nova a partir de um template, com novo
+// código de convite
+func (h *CampaignHandler) CreateCampaignFromTemplate(w http.ResponseWriter, r *http.Request) {
+	template, userID, ok := h.loadTemplate(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.CampaignCopyRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.Response.SendBadRequest(w, "Invalid request body")
+			return
+		}
+	}
+
+	if template.Archive == nil {
+		h.Response.SendInternalError(w, "Campaign template has no content")
+		return
+	}
+
+	h.createCampaignFromArchive(w, r, userID, template.Archive, req, "Campaign created from template")
+}
+
+// loadTemplate carrega o template {id} do usuário. Em caso de erro, a resposta já foi enviada.
+func (h *CampaignHandler) loadTemplate(w http.ResponseWriter, r *http.Request) (*models.CampaignTemplate, int, bool) {
+	userID, err := utils.ExtractUserID(r)
+	if err != nil {
+		h.Response.SendInternalError(w, "User ID not found in context")
+		return nil, 0, false
+	}
+
+	templateID, err := utils.ExtractID(r)
+	if err != nil {
+		h.Response.SendBadRequest(w, "Invalid template ID")
+		return nil, 0, false
+	}
+
+	template, err := h.DB.GetCampaignTemplate(r.Context(), templateID, userID)
+	if err != nil {
+		h.Response.HandleDBError(w, err, "fetch campaign template")
+		return nil, 0, false
+	}
+	if template == nil {
+		h.Response.SendNotFound(w, "Campaign template not found")
+		return nil, 0, false
+	}
+
+	return template, userID, true
+}
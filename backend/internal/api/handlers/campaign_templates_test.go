@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+
+	"rpg-saas-backend/internal/api/middleware"
+	"rpg-saas-backend/internal/models"
+)
+
+// expectTemplateImport simula a criação da campanha a partir do pacote de expectCampaignArchive
+// já convertido em template: sem jogadores, quest reaberta e objetivo pendente
+func expectTemplateImport(mock sqlmock.Sqlmock, name string, newCampaignID int) {
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO campaigns`).
+		WithArgs(name, "desc", 7, 5, 1, "planning", false, "xp", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(newCampaignID))
+	mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM homebrew_races`).WithArgs("Owlfolk", 7).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery(`INSERT INTO npcs`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(30))
+	mock.ExpectQuery(`INSERT INTO encounters`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(40))
+	mock.ExpectQuery(`INSERT INTO encounter_monsters`).WithArgs(40, "Rat", 10, 0.0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(41, time.Now()))
+	mock.ExpectQuery(`INSERT INTO campaign_wiki_entries`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(50))
+	mock.ExpectQuery(`INSERT INTO quests`).
+		WithArgs(newCampaignID, "Lost Mine", "Find the mine", 30, "", "active", true, sqlmock.AnyArg(), nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(60))
+	mock.ExpectExec(`INSERT INTO quest_objectives`).WithArgs(60, "Find Gundren", false, 0, nil, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(61, 1))
+	mock.ExpectExec(`INSERT INTO rooms`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO room_members`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+}
+
+func TestCampaignHandler_CloneCampaign(t *testing.T) {
+	handler, mock, cleanup := newMockCampaignHandler(t)
+	defer cleanup()
+
+	expectCampaignAccess(mock, 10, 7, 7, 8)
+	expectCampaignArchive(mock, 10)
+	expectTemplateImport(mock, "Campaign (copy)", 20)
+
+	req := withCampaignUser(httptest.NewRequest(http.MethodPost, "/api/campaigns/10/clone", nil), "10", 7)
+	rr := httptest.NewRecorder()
+	handler.CloneCampaign(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var resp struct {
+		Data struct {
+			Campaign models.Campaign             `json:"campaign"`
+			Report   models.CampaignImportReport `json:"report"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Data.Campaign.ID != 20 || resp.Data.Campaign.InviteCode == "CODE1234" {
+		t.Fatalf("expected a new campaign with a fresh invite code, got %+v", resp.Data.Campaign)
+	}
+	if resp.Data.Report.Players != 0 || resp.Data.Report.Characters != 0 {
+		t.Fatalf("clone must not copy players or characters, got %+v", resp.Data.Report)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestCampaignHandler_SaveAsTemplate(t *testing.T) {
+	handler, mock, cleanup := newMockCampaignHandler(t)
+	defer cleanup()
+
+	expectCampaignAccess(mock, 10, 7, 7, 8)
+	expectCampaignArchive(mock, 10)
+	mock.ExpectQuery(`INSERT INTO campaign_templates`).
+		WithArgs(7, "Lost Mine one-shot", "desc", 10, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+
+	req := withCampaignUser(httptest.NewRequest(http.MethodPost, "/api/campaigns/10/templates",
+		bytes.NewBufferString(`{"name":"Lost Mine one-shot"}`)), "10", 7)
+	rr := httptest.NewRecorder()
+	handler.SaveAsTemplate(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var resp struct {
+		Data models.CampaignTemplate `json:"data"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	archive := resp.Data.Archive
+	if archive == nil || len(archive.Players) != 0 || len(archive.Characters) != 0 {
+		t.Fatalf("template must not include players or characters, got %+v", archive)
+	}
+	if archive.Quests[0].Status != models.QuestStatusActive || archive.Quests[0].Objectives[0].Completed {
+		t.Fatalf("template should reset quest progress, got %+v", archive.Quests[0])
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+
+	// Players não salvam templates
+	expectCampaignAccess(mock, 10, 8, 7, 8)
+
+	req = withCampaignUser(httptest.NewRequest(http.MethodPost, "/api/campaigns/10/templates", nil), "10", 8)
+	rr = httptest.NewRecorder()
+	handler.SaveAsTemplate(rr, req)
+
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", rr.Code)
+	}
+}
+
+func TestCampaignHandler_CreateCampaignFromTemplate(t *testing.T) {
+	handler, mock, cleanup := newMockCampaignHandler(t)
+	defer cleanup()
+
+	archive := models.CampaignArchive{
+		Version:  models.CampaignArchiveVersion,
+		Campaign: models.ArchivedCampaign{Name: "Lost Mine", Description: "desc", MaxPlayers: 5, Status: "planning", CurrentSession: 1},
+	}
+	archiveJSON, _ := json.Marshal(archive)
+
+	mock.ExpectQuery(`FROM campaign_templates\s+WHERE id = \$1 AND owner_id = \$2`).WithArgs(3, 7).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "owner_id", "name", "description", "source_campaign_id", "archive", "created_at"}).
+			AddRow(3, 7, "Lost Mine one-shot", "desc", 10, archiveJSON, time.Now()))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO campaigns`).
+		WithArgs("Tuesday group", "desc", 7, 4, 1, "planning", false, "xp", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(21))
+	mock.ExpectCommit()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/campaign-templates/3/campaigns",
+		bytes.NewBufferString(`{"name":"Tuesday group","max_players":4}`))
+	req = addChiURLParam(req, "id", "3")
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, 7))
+	rr := httptest.NewRecorder()
+	handler.CreateCampaignFromTemplate(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+
+	// Template de outro usuário
+	mock.ExpectQuery(`FROM campaign_templates`).WithArgs(3, 8).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	req = httptest.NewRequest(http.MethodPost, "/api/campaign-templates/3/campaigns", nil)
+	req = addChiURLParam(req, "id", "3")
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, 8))
+	rr = httptest.NewRecorder()
+	handler.CreateCampaignFromTemplate(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rr.Code)
+	}
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -20,6 +21,8 @@ type CampaignHandler struct {
 	DB        *db.PostgresDB
 	Response  *utils.ResponseHandler
 	Validator *utils.Validator
+	Hub       *RoomHub     // Usado para fechar as salas da campanha ao pausá-la ou concluí-la
+	Activity  *ActivityLog // Feed de atividade da campanha; nil não registra eventos
 }
 
 func NewCampaignHandler(db *db.PostgresDB) *CampaignHandler {
@@ -96,6 +99,7 @@ func (h *CampaignHandler) CreateCampaign(w http.ResponseWriter, r *http.Request)
 	validationErrors := h.Validator.BatchValidate(
 		func() error { return h.Validator.ValidateName(req.Name, "name") },
 		func() error { return h.Validator.ValidatePlayerCount(req.MaxPlayers) },
+		func() error { return h.validateLevelingMode(req.LevelingMode) },
 	)
 
 	if validationErrors.HasErrors() {
@@ -107,6 +111,9 @@ func (h *CampaignHandler) CreateCampaign(w http.ResponseWriter, r *http.Request)
 	if req.MaxPlayers <= 0 {
 		req.MaxPlayers = 6
 	}
+	if req.LevelingMode == "" {
+		req.LevelingMode = models.LevelingModeXP
+	}
 
 	// Gerar código de convite único
 	inviteCode, err := utils.GenerateInviteCode()
@@ -125,6 +132,7 @@ func (h *CampaignHandler) CreateCampaign(w http.ResponseWriter, r *http.Request)
 		MaxPlayers:    req.MaxPlayers,
 		Status:        "planning",
 		AllowHomebrew: req.AllowHomebrew,
+		LevelingMode:  req.LevelingMode,
 		InviteCode:    normalizedCode,
 	}
 
@@ -161,6 +169,21 @@ func (h *CampaignHandler) UpdateCampaign(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if err := h.validateLevelingMode(req.LevelingMode); err != nil {
+		h.Response.SendValidationError(w, err.Error())
+		return
+	}
+
+	// O status segue o ciclo de vida da campanha; a mudança é gravada com os demais campos
+	var statusEvent *models.CampaignStatusEvent
+	if req.Status != "" {
+		event, ok := h.campaignStatusEvent(w, r, id, userID, req.Status)
+		if !ok {
+			return
+		}
+		statusEvent = event
+	}
+
 	campaign := &models.Campaign{
 		ID:             id,
 		Name:           req.Name,
@@ -168,7 +191,7 @@ func (h *CampaignHandler) UpdateCampaign(w http.ResponseWriter, r *http.Request)
 		DMID:           userID,
 		MaxPlayers:     req.MaxPlayers,
 		CurrentSession: req.CurrentSession,
-		Status:         req.Status,
+		LevelingMode:   req.LevelingMode, // vazio mantém o modo atual
 	}
 
 	// Update AllowHomebrew if provided
@@ -176,16 +199,55 @@ func (h *CampaignHandler) UpdateCampaign(w http.ResponseWriter, r *http.Request)
 		campaign.AllowHomebrew = *req.AllowHomebrew
 	}
 
-	err = h.DB.UpdateCampaign(r.Context(), campaign)
-	if err != nil {
+	var statusChange *models.CampaignStatusChange
+	if statusEvent != nil {
+		statusChange, err = h.DB.UpdateCampaignWithStatus(r.Context(), campaign, id, statusEvent)
+	} else {
+		err = h.DB.UpdateCampaign(r.Context(), campaign)
+	}
+	switch {
+	case errors.Is(err, db.ErrCampaignStatusChanged):
+		h.Response.SendConflict(w, "Campaign status changed in the meantime; reload and try again")
+		return
+	case errors.Is(err, db.ErrCampaignWithoutPlayers):
+		h.Response.SendValidationError(w, err.Error())
+		return
+	case err != nil:
 		http.Error(w, "Failed to update campaign: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
+	if statusChange != nil {
+		h.announceCampaignStatusChange(r, id, userID, statusChange)
+	}
+	campaign.Status = req.Status
+	h.Activity.Record(r.Context(), models.CampaignActivity{
+		CampaignID: id,
+		ActorID:    &userID,
+		EventType:  models.ActivityCampaignUpdated,
+		TargetType: models.ActivityTargetCampaign,
+		TargetID:   activityTarget(id),
+		Summary:    "Updated the campaign settings",
+	})
 
 	w.Header().Set("Content-Type", "application/json")
+	if statusChange != nil {
+		json.NewEncoder(w).Encode(struct {
+			*models.Campaign
+			StatusChange *models.CampaignStatusChange `json:"status_change"`
+		}{campaign, statusChange})
+		return
+	}
 	json.NewEncoder(w).Encode(campaign)
 }
 
+// validateLevelingMode aceita modo vazio (padrão ou sem alteração), xp ou milestone
+func (h *CampaignHandler) validateLevelingMode(mode string) error {
+	if mode == "" {
+		return nil
+	}
+	return h.Validator.ValidateChoice(mode, "leveling_mode", models.LevelingModes)
+}
+
 // DeleteCampaign deleta uma campanha
 func (h *CampaignHandler) DeleteCampaign(w http.ResponseWriter, r *http.Request) {
 	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
@@ -237,11 +299,25 @@ func (h *CampaignHandler) JoinCampaign(w http.ResponseWriter, r *http.Request) {
 
 	normalizedCode := utils.NormalizeInviteCode(req.InviteCode)
 
-	err := h.DB.JoinCampaignByCode(r.Context(), normalizedCode, userID)
+	campaignID, err := h.DB.JoinCampaignByCode(r.Context(), normalizedCode, userID)
+	if errors.Is(err, db.ErrInvalidInviteCode) {
+		// Não é o código permanente da campanha: tenta os convites com validade/limite
+		h.joinWithInvite(w, r, normalizedCode, userID)
+		return
+	}
 	if err != nil {
 		http.Error(w, "Failed to join campaign: "+err.Error(), http.StatusBadRequest)
 		return
 	}
+	h.Activity.Record(r.Context(), models.CampaignActivity{
+		CampaignID: campaignID,
+		ActorID:    &userID,
+		EventType:  models.ActivityPlayerJoined,
+		TargetType: models.ActivityTargetPlayer,
+		TargetID:   activityTarget(userID),
+		Summary:    "Joined the campaign with the invite code",
+		Metadata:   models.JSONB{"via": "code"},
+	})
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]string{"message": "Successfully joined campaign"})
@@ -263,10 +339,25 @@ func (h *CampaignHandler) LeaveCampaign(w http.ResponseWriter, r *http.Request)
 	}
 
 	err = h.DB.RemovePlayerFromCampaign(r.Context(), campaignID, userID)
-	if err != nil {
+	switch {
+	case errors.Is(err, db.ErrPlayerBanned):
+		http.Error(w, "Banned players cannot leave the campaign", http.StatusForbidden)
+		return
+	case errors.Is(err, db.ErrPlayerNotFound):
+		http.Error(w, "You are not a player in this campaign", http.StatusNotFound)
+		return
+	case err != nil:
 		http.Error(w, "Failed to leave campaign: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
+	h.Activity.Record(r.Context(), models.CampaignActivity{
+		CampaignID: campaignID,
+		ActorID:    &userID,
+		EventType:  models.ActivityPlayerLeft,
+		TargetType: models.ActivityTargetPlayer,
+		TargetID:   activityTarget(userID),
+		Summary:    "Left the campaign",
+	})
 
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -351,6 +442,14 @@ func (h *CampaignHandler) RegenerateInviteCode(w http.ResponseWriter, r *http.Re
 		http.Error(w, "Failed to update invite code", http.StatusInternalServerError)
 		return
 	}
+	h.Activity.Record(r.Context(), models.CampaignActivity{
+		CampaignID: campaignID,
+		ActorID:    &userID,
+		EventType:  models.ActivityInviteCodeRegenerated,
+		TargetType: models.ActivityTargetCampaign,
+		TargetID:   activityTarget(campaignID),
+		Summary:    "Generated a new invite code",
+	})
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(models.CampaignInviteResponse{
@@ -489,6 +588,27 @@ func (h *CampaignHandler) AddCharacterToCampaign(w http.ResponseWriter, r *http.
 		return
 	}
 
+	// Campanha concluída não recebe personagens novos
+	campaign, err := h.DB.GetCampaignByID(r.Context(), campaignID, userID)
+	if err != nil {
+		http.Error(w, "Campaign not found or access denied", http.StatusNotFound)
+		return
+	}
+	if !checkCampaignNotCompleted(w, h.Response, campaign) {
+		return
+	}
+
+	// Verificar raça, classes e antecedente contra a política de homebrew da campanha
+	rejections, err := h.DB.CheckCampaignCharacterContent(r.Context(), campaignID, pc.Race, pc.Classes().Names(), pc.Background)
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+	if len(rejections) > 0 {
+		h.sendContentRejections(w, rejections)
+		return
+	}
+
 	// Criar snapshot completo do PC para a campanha
 	campaignChar := &models.CampaignCharacter{
 		CampaignID:        campaignID,
@@ -499,6 +619,7 @@ func (h *CampaignHandler) AddCharacterToCampaign(w http.ResponseWriter, r *http.
 		Level:             pc.Level,
 		Race:              pc.Race,
 		Class:             pc.Class,
+		ClassLevels:       pc.Classes(),
 		Background:        pc.Background,
 		Alignment:         pc.Alignment,
 		Attributes:        pc.Attributes,
@@ -522,11 +643,20 @@ func (h *CampaignHandler) AddCharacterToCampaign(w http.ResponseWriter, r *http.
 		JoinedAt:          time.Now(),
 	}
 
-	err = h.DB.AddPCToCampaign(r.Context(), campaignChar)
+	err = h.DB.AddPCToCampaign(r.Context(), campaignChar, userID)
 	if err != nil {
 		http.Error(w, "Failed to add character to campaign: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
+	h.Activity.Record(r.Context(), models.CampaignActivity{
+		CampaignID: campaignID,
+		ActorID:    &userID,
+		EventType:  models.ActivityCharacterAdded,
+		TargetType: models.ActivityTargetCharacter,
+		TargetID:   activityTarget(campaignChar.ID),
+		Summary:    fmt.Sprintf("Added %s (level %d %s)", campaignChar.Name, campaignChar.Level, campaignChar.Class),
+		Metadata:   models.JSONB{"source_pc_id": campaignChar.SourcePCID},
+	})
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
@@ -568,22 +698,52 @@ func (h *CampaignHandler) UpdateCampaignCharacter(w http.ResponseWriter, r *http
 		return
 	}
 
+	if !checkCharacterNotFrozen(w, h.Response, campaignChar) {
+		return
+	}
+
+	if !h.checkCharacterStatusChange(w, r, campaignChar, userID, req.Status, req.CurrentHP) {
+		return
+	}
+
+	prevStatus, prevHP := campaignChar.Status, campaignChar.CurrentHP
+	notesChanged := req.Notes != "" && req.Notes != campaignChar.CampaignNotes
+
 	// Atualizar campos específicos da campanha
 	if req.CurrentHP != nil {
 		campaignChar.CurrentHP = req.CurrentHP
 	}
-	if req.Status != "" {
-		campaignChar.Status = req.Status
-	}
 	if req.Notes != "" {
 		campaignChar.CampaignNotes = req.Notes
 	}
 
-	err = h.DB.UpdateCampaignCharacter(r.Context(), campaignChar)
+	if req.Status != "" && req.Status != campaignChar.Status {
+		change, ok := h.changeCharacterStatus(w, r, campaignChar, models.CharacterStatusEvent{
+			From:      campaignChar.Status,
+			To:        req.Status,
+			ChangedBy: userID,
+			Cause:     req.Cause,
+			SessionID: req.SessionID,
+		})
+		if !ok {
+			return
+		}
+		h.recordCharacterActivity(r.Context(), userID, campaignChar, prevStatus, prevHP, notesChanged)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(struct {
+			*models.CampaignCharacter
+			StatusChange *models.CharacterStatusChange `json:"status_change"`
+		}{campaignChar, change})
+		return
+	}
+
+	err = h.DB.UpdateCampaignCharacter(r.Context(), campaignChar, userID)
 	if err != nil {
 		http.Error(w, "Failed to update character: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
+	h.recordCharacterActivity(r.Context(), userID, campaignChar, prevStatus, prevHP, notesChanged)
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(campaignChar)
@@ -612,12 +772,16 @@ func (h *CampaignHandler) DeleteCampaignCharacter(w http.ResponseWriter, r *http
 	}
 
 	// Verificar se o usuário tem permissão para deletar (dono do personagem ou DM)
-	_, err = h.DB.GetCampaignCharacter(r.Context(), characterID, campaignID, userID)
+	character, err := h.DB.GetCampaignCharacter(r.Context(), characterID, campaignID, userID)
 	if err != nil {
 		http.Error(w, "Character not found or access denied", http.StatusNotFound)
 		return
 	}
 
+	if !checkCharacterNotFrozen(w, h.Response, character) {
+		return
+	}
+
 	err = h.DB.DeleteCampaignCharacter(r.Context(), characterID, campaignID)
 	if err != nil {
 		http.Error(w, "Failed to delete character: "+err.Error(), http.StatusInternalServerError)
@@ -692,6 +856,13 @@ func (h *CampaignHandler) UpdateCampaignCharacterFull(w http.ResponseWriter, r *
 		return
 	}
 
+	// class e level viram o resumo de class_levels (ou o contrário, para clientes antigos)
+	classes := models.CampaignCharacter{Class: req.Class, Level: req.Level, ClassLevels: req.ClassLevels, Abilities: req.Abilities}
+	if err := classes.NormalizeClassLevels(); err != nil {
+		h.Response.SendValidationErrors(w, utils.ValidationErrors{fieldError("class_levels", "invalid", "%s", err.Error())})
+		return
+	}
+
 	// Verificar acesso
 	campaignChar, err := h.DB.GetCampaignCharacter(r.Context(), charID, campaignID, userID)
 	if err != nil {
@@ -699,12 +870,44 @@ func (h *CampaignHandler) UpdateCampaignCharacterFull(w http.ResponseWriter, r *
 		return
 	}
 
+	if !checkCharacterNotFrozen(w, h.Response, campaignChar) {
+		return
+	}
+
+	if !h.checkCharacterStatusChange(w, r, campaignChar, userID, req.Status, req.CurrentHP) {
+		return
+	}
+
+	allowed, err := h.canRaiseCharacterLevel(r.Context(), campaignID, userID, campaignChar.Level, classes.Level, campaignChar.LevelUpAvailable)
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+	if !allowed {
+		h.Response.SendForbidden(w, "Only the DM can raise a character's level without an available level-up")
+		return
+	}
+
+	prevStatus, prevHP := campaignChar.Status, campaignChar.CurrentHP
+	prevContent := contentOf(campaignChar)
+
+	// A mudança de status roda com seus hooks na mesma transação do snapshot
+	var statusEvent *models.CharacterStatusEvent
+	if req.Status != "" && req.Status != campaignChar.Status {
+		statusEvent = &models.CharacterStatusEvent{
+			From:      campaignChar.Status,
+			To:        req.Status,
+			ChangedBy: userID,
+		}
+	}
+
 	// Atualizar todos os campos do snapshot
 	campaignChar.Name = req.Name
 	campaignChar.Description = req.Description
-	campaignChar.Level = req.Level
+	campaignChar.Level = classes.Level
 	campaignChar.Race = req.Race
-	campaignChar.Class = req.Class
+	campaignChar.Class = classes.Class
+	campaignChar.ClassLevels = classes.ClassLevels
 	campaignChar.Background = req.Background
 	campaignChar.Alignment = req.Alignment
 	campaignChar.Attributes = req.Attributes
@@ -726,24 +929,37 @@ func (h *CampaignHandler) UpdateCampaignCharacterFull(w http.ResponseWriter, r *
 	campaignChar.Flaws = req.Flaws
 	campaignChar.Features = req.Features
 	campaignChar.PlayerName = req.PlayerName
-	if req.Status != "" {
-		campaignChar.Status = req.Status
-	}
 	if req.CampaignNotes != "" {
 		campaignChar.CampaignNotes = req.CampaignNotes
 	}
 
-	err = h.DB.UpdateCampaignCharacterFull(r.Context(), campaignChar)
+	// Trocar raça, classe ou antecedente passa de novo pela política de homebrew da campanha
+	rejections, err := h.characterContentChangeRejections(r.Context(), campaignChar, prevContent)
 	if err != nil {
-		http.Error(w, "Failed to update character: "+err.Error(), http.StatusInternalServerError)
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+	if len(rejections) > 0 {
+		h.sendContentRejections(w, rejections)
 		return
 	}
 
+	_, err = h.DB.UpdateCampaignCharacterFullWithStatus(r.Context(), campaignChar, userID, statusEvent)
+	if err != nil {
+		if !h.sendCharacterStatusError(w, err) {
+			http.Error(w, "Failed to update character: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	h.recordCharacterActivity(r.Context(), userID, campaignChar, prevStatus, prevHP, true)
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(campaignChar)
 }
 
-// SyncCampaignCharacter sincroniza o personagem com outras campanhas
+// SyncCampaignCharacter sincroniza o snapshot da campanha com o PC original
+// (push: snapshot → PC, pull: PC → snapshot) e, opcionalmente, com as outras
+// campanhas que usam o mesmo PC. Conflitos são detectados contra o estado do último sync.
 func (h *CampaignHandler) SyncCampaignCharacter(w http.ResponseWriter, r *http.Request) {
 	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
 	if !ok {
@@ -771,29 +987,189 @@ func (h *CampaignHandler) SyncCampaignCharacter(w http.ResponseWriter, r *http.R
 		return
 	}
 
+	if req.Direction == "" {
+		req.Direction = models.SyncDirectionPush
+	}
+	if req.Direction != models.SyncDirectionPush && req.Direction != models.SyncDirectionPull {
+		http.Error(w, "Invalid direction: must be push or pull", http.StatusBadRequest)
+		return
+	}
+
 	// Verificar acesso ao personagem
-	_, err = h.DB.GetCampaignCharacter(r.Context(), charID, campaignID, userID)
+	campaignChar, err := h.DB.GetCampaignCharacter(r.Context(), charID, campaignID, userID)
 	if err != nil {
 		http.Error(w, "Character not found or access denied", http.StatusNotFound)
 		return
 	}
 
-	syncCount := 0
-	if req.SyncToOtherCampaigns {
-		// TODO: Implementar GetCampaignCharactersBySourcePC no db/campaigns.go
-		// Por enquanto, retorna mensagem de sucesso
-		syncCount = 0
+	if !checkCharacterNotFrozen(w, h.Response, campaignChar) {
+		return
 	}
 
-	message := "Character synchronized successfully"
-	if syncCount > 0 {
-		message = fmt.Sprintf("Character synchronized with %d other campaign(s)", syncCount)
+	// Apenas o dono pode alterar o PC original ou propagar para as outras campanhas dele
+	if req.Direction == models.SyncDirectionPush && campaignChar.PlayerID != userID {
+		http.Error(w, "Only the character owner can push changes to the original PC", http.StatusForbidden)
+		return
+	}
+	if req.SyncToOtherCampaigns && campaignChar.PlayerID != userID {
+		http.Error(w, "Only the character owner can sync other campaigns", http.StatusForbidden)
+		return
+	}
+
+	pc, err := h.DB.GetPCByIDAndPlayer(r.Context(), campaignChar.SourcePCID, campaignChar.PlayerID)
+	if err != nil {
+		http.Error(w, "Source PC not found", http.StatusNotFound)
+		return
+	}
+
+	base, err := h.DB.GetCampaignCharacterSyncBase(r.Context(), campaignChar.ID)
+	if err != nil {
+		http.Error(w, "Failed to load sync state: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	snapshotState, pcState := campaignChar.SyncState(), pc.SyncState()
+	diffs, conflicts := models.DiffSyncStates(snapshotState, pcState, base)
+	response := models.SyncCharacterResponse{
+		Direction: req.Direction,
+		Diffs:     diffs,
+		Conflicts: conflicts,
+	}
+
+	if len(conflicts) > 0 && !req.Force {
+		response.Message = "Snapshot and original PC both changed since last sync; resolve the conflicts or retry with force"
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	// Merge de três vias: só os campos alterados na origem desde o último sync são aplicados
+	var updatedPC *models.PC
+	snapshots := []models.SyncedSnapshot{}
+	if req.Direction == models.SyncDirectionPush {
+		merge := models.MergeSyncStates(snapshotState, pcState, base, req.Force)
+		if len(merge.Fields) > 0 {
+			if err := pc.ApplySyncChanges(merge.Changes); err != nil {
+				http.Error(w, "Failed to sync character: "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+			updatedPC = pc
+		}
+		response.Applied = merge.Fields
+		snapshots = append(snapshots, models.SyncedSnapshot{Character: campaignChar, Base: merge.Base})
+	} else {
+		merge := models.MergeSyncStates(pcState, snapshotState, base, req.Force)
+		prevContent, prevLevel := contentOf(campaignChar), campaignChar.Level
+		if err := campaignChar.ApplySyncChanges(merge.Changes); err != nil {
+			http.Error(w, "Failed to sync character: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		allowed, err := h.canRaiseCharacterLevel(r.Context(), campaignID, userID, prevLevel, campaignChar.Level, campaignChar.LevelUpAvailable)
+		if err != nil {
+			http.Error(w, "Failed to sync character: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if !allowed {
+			h.Response.SendForbidden(w, "Only the DM can raise a character's level without an available level-up")
+			return
+		}
+		rejections, err := h.characterContentChangeRejections(r.Context(), campaignChar, prevContent)
+		if err != nil {
+			http.Error(w, "Failed to sync character: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if len(rejections) > 0 {
+			h.sendContentRejections(w, rejections)
+			return
+		}
+		response.Applied = merge.Fields
+		snapshots = append(snapshots, models.SyncedSnapshot{Character: campaignChar, Changed: len(merge.Fields) > 0, Base: merge.Base})
+	}
+
+	if req.SyncToOtherCampaigns {
+		others, err := h.DB.GetCampaignCharactersBySourcePC(r.Context(), pc.ID)
+		if err != nil {
+			http.Error(w, "Failed to load other campaigns: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		synced := pc.SyncState()
+		for i := range others {
+			other := &others[i]
+			if other.ID == campaignChar.ID {
+				continue
+			}
+
+			// O PC já está atualizado; conflito = campo alterado localmente na outra campanha
+			otherState := other.SyncState()
+			otherDiffs, otherConflicts := models.DiffSyncStates(otherState, synced, other.SyncBase)
+			result := models.CampaignSyncResult{
+				CampaignCharacterID: other.ID,
+				CampaignID:          other.CampaignID,
+				Diffs:               otherDiffs,
+				Conflicts:           otherConflicts,
+			}
+
+			// Personagem congelado não recebe alterações, nem com force
+			if other.FrozenAt != nil {
+				result.Frozen = true
+				response.Campaigns = append(response.Campaigns, result)
+				continue
+			}
+
+			if len(otherConflicts) == 0 || req.Force {
+				merge := models.MergeSyncStates(synced, otherState, other.SyncBase, req.Force)
+				prevContent, prevLevel := contentOf(other), other.Level
+				if err := other.ApplySyncChanges(merge.Changes); err != nil {
+					http.Error(w, "Failed to sync character: "+err.Error(), http.StatusInternalServerError)
+					return
+				}
+
+				// Subir de nível sem level-up disponível na outra campanha também a deixa de fora
+				allowed, err := h.canRaiseCharacterLevel(r.Context(), other.CampaignID, userID, prevLevel, other.Level, other.LevelUpAvailable)
+				if err != nil {
+					http.Error(w, "Failed to sync character: "+err.Error(), http.StatusInternalServerError)
+					return
+				}
+				if !allowed {
+					result.LevelBlocked = true
+					response.Campaigns = append(response.Campaigns, result)
+					continue
+				}
+
+				// Conteúdo barrado pela política da outra campanha: ela fica de fora do sync
+				rejections, err := h.characterContentChangeRejections(r.Context(), other, prevContent)
+				if err != nil {
+					http.Error(w, "Failed to sync character: "+err.Error(), http.StatusInternalServerError)
+					return
+				}
+				if len(rejections) > 0 {
+					result.Rejections = rejections
+					response.Campaigns = append(response.Campaigns, result)
+					continue
+				}
+				snapshots = append(snapshots, models.SyncedSnapshot{Character: other, Changed: len(merge.Fields) > 0, Base: merge.Base})
+				result.Synced = true
+				response.SyncCount++
+			}
+
+			response.Campaigns = append(response.Campaigns, result)
+		}
+	}
+
+	// PC, snapshots, bases e versões são gravados juntos: uma falha não deixa o sync pela metade
+	if err := h.DB.ApplyCharacterSync(r.Context(), updatedPC, snapshots, userID); err != nil {
+		http.Error(w, "Failed to sync character: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response.Success = true
+	response.Message = "Character synchronized successfully"
+	if response.SyncCount > 0 {
+		response.Message = fmt.Sprintf("Character synchronized with %d other campaign(s)", response.SyncCount)
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]any{
-		"message":    message,
-		"sync_count": syncCount,
-		"success":    true,
-	})
+	json.NewEncoder(w).Encode(response)
 }
@@ -81,7 +81,7 @@ func TestCampaignHandler_CreateCampaign(t *testing.T) {
 	defer cleanup()
 
 	mock.ExpectQuery(`INSERT INTO campaigns`).
-		WithArgs("New Campaign", "desc", 7, 6, "planning", false, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WithArgs("New Campaign", "desc", 7, 6, "planning", false, "xp", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(99))
 
 	body := bytes.NewBufferString(`{"name":"New Campaign","description":"desc","max_players":6}`)
@@ -120,10 +120,13 @@ func TestCampaignHandler_JoinAndLeaveCampaign(t *testing.T) {
 			"id", "name", "description", "dm_id", "max_players", "current_session", "status", "allow_homebrew", "invite_code", "created_at", "updated_at",
 		}).AddRow(5, "Joinable", "desc", 20, 5, 1, "planning", false, "ABCD1234", now, now))
 
+	mock.ExpectBegin()
 	mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM campaign_players WHERE campaign_id = \$1 AND user_id = \$2\)`).
 		WithArgs(5, 7).
 		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
 
+	mock.ExpectQuery(`SELECT max_players FROM campaigns WHERE id = \$1 FOR UPDATE`).WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"max_players"}).AddRow(5))
 	mock.ExpectQuery(`SELECT COUNT\(cp.user_id\) as player_count`).
 		WithArgs(5).
 		WillReturnRows(sqlmock.NewRows([]string{"player_count"}).AddRow(1))
@@ -131,6 +134,7 @@ func TestCampaignHandler_JoinAndLeaveCampaign(t *testing.T) {
 	mock.ExpectExec(`INSERT INTO campaign_players`).
 		WithArgs(5, 7, sqlmock.AnyArg()).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
 
 	joinBody := bytes.NewBufferString(`{"invite_code":"ABCD-1234"}`)
 	joinReq := httptest.NewRequest(http.MethodPost, "/api/campaigns/join", joinBody)
@@ -142,7 +146,7 @@ func TestCampaignHandler_JoinAndLeaveCampaign(t *testing.T) {
 	}
 
 	// Leave flow expectations
-	mock.ExpectExec(`DELETE FROM campaign_players WHERE campaign_id = \$1 AND user_id = \$2`).
+	mock.ExpectExec(`UPDATE campaign_players SET status = 'removed'`).
 		WithArgs(5, 7).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
@@ -190,7 +194,7 @@ func TestCampaignHandler_LeaveCampaignErrors(t *testing.T) {
 	})
 
 	t.Run("database error", func(t *testing.T) {
-		mock.ExpectExec(`DELETE FROM campaign_players WHERE campaign_id = \$1 AND user_id = \$2`).
+		mock.ExpectExec(`UPDATE campaign_players SET status = 'removed'`).
 			WithArgs(5, 7).
 			WillReturnError(sqlmock.ErrCancelled)
 
@@ -232,7 +236,7 @@ func TestCampaignHandler_GetInviteCode(t *testing.T) {
 		"id", "campaign_id", "player_id", "source_pc_id", "status", "joined_at", "last_sync", "campaign_notes",
 		"name", "description", "level", "race", "class", "background", "alignment", "attributes", "abilities",
 		"equipment", "hp", "current_hp", "ca", "proficiency_bonus", "inspiration", "skills", "attacks", "spells",
-		"personality_traits", "ideals", "bonds", "flaws", "features", "player_name", "player_username",
+		"personality_traits", "ideals", "bonds", "flaws", "features", "player_name", "experience_points", "level_up_available", "class_levels", "player_username",
 	}
 	characterRows := sqlmock.NewRows(characterCols)
 	mock.ExpectQuery(`FROM campaign_characters`).WithArgs(10).WillReturnRows(characterRows)
@@ -278,7 +282,7 @@ func TestCampaignHandler_GetCampaignByID(t *testing.T) {
 		"id", "campaign_id", "player_id", "source_pc_id", "status", "joined_at", "last_sync", "campaign_notes",
 		"name", "description", "level", "race", "class", "background", "alignment", "attributes", "abilities",
 		"equipment", "hp", "current_hp", "ca", "proficiency_bonus", "inspiration", "skills", "attacks", "spells",
-		"personality_traits", "ideals", "bonds", "flaws", "features", "player_name", "player_username",
+		"personality_traits", "ideals", "bonds", "flaws", "features", "player_name", "experience_points", "level_up_available", "class_levels", "player_username",
 	}
 	characterRows := sqlmock.NewRows(characterCols)
 	mock.ExpectQuery(`FROM campaign_characters`).WithArgs(30).WillReturnRows(characterRows)
@@ -302,8 +306,10 @@ func TestCampaignHandler_UpdateAndDeleteCampaign(t *testing.T) {
 	handler, mock, cleanup := newMockCampaignHandler(t)
 	defer cleanup()
 
+	// O status pedido é o atual: nenhuma transição, e o UPDATE mantém o status
+	expectCampaignAccess(mock, 15, 7, 7, 8)
 	mock.ExpectExec(`UPDATE campaigns SET`).WithArgs(
-		"Updated", "desc", 6, 2, "active", false, sqlmock.AnyArg(), 15, 7,
+		"Updated", "desc", 6, 2, "", false, "", sqlmock.AnyArg(), 15, 7,
 	).WillReturnResult(sqlmock.NewResult(0, 1))
 
 	updateReq := httptest.NewRequest(http.MethodPut, "/api/campaigns/15", bytes.NewBufferString(`{"name":"Updated","description":"desc","max_players":6,"current_session":2,"status":"active"}`))
@@ -469,13 +475,20 @@ func TestCampaignHandler_AddUpdateDeleteCharacter(t *testing.T) {
 	mock.ExpectQuery(`SELECT EXISTS\(`).WithArgs(60, 7).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
 	mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM campaign_characters`).WithArgs(60, 4).
 		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	expectCampaignAccess(mock, 60, 7, 9, 7)
+	mock.ExpectQuery(`SELECT c.allow_homebrew`).WithArgs(60).
+		WillReturnRows(sqlmock.NewRows([]string{"allow_homebrew", "exists"}).AddRow(true, false))
 
+	mock.ExpectBegin()
 	mock.ExpectQuery(`INSERT INTO campaign_characters`).WithArgs(
 		60, 7, 4, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), // campaign_id, player_id, source_pc_id, status, joined_at, notes
 		sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), // name..alignment
 		sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), // attributes..ca
 		sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+		sqlmock.AnyArg(), // class_levels
 	).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(99))
+	expectCharacterVersionRecorded(mock, 99, 60)
+	mock.ExpectCommit()
 
 	addReq := httptest.NewRequest(http.MethodPost, "/api/campaigns/60/characters", bytes.NewBufferString(`{"source_pc_id":4}`))
 	addReq = addChiURLParam(addReq, "id", "60")
@@ -504,9 +517,13 @@ func TestCampaignHandler_AddUpdateDeleteCharacter(t *testing.T) {
 
 	mock.ExpectQuery(`FROM campaign_characters cc`).WithArgs(99, 60, 7).WillReturnRows(charRow())
 
+	mock.ExpectBegin()
+	expectVersionHistory(mock, models.CharacterTypeCampaign, 99)
 	mock.ExpectExec(`UPDATE campaign_characters SET`).WithArgs(
-		sqlmock.AnyArg(), "inactive", "Updated notes", 99, 60,
+		sqlmock.AnyArg(), "inactive", "Updated notes", 99, 60, "active",
 	).WillReturnResult(sqlmock.NewResult(0, 1))
+	expectCharacterVersionRecorded(mock, 99, 60)
+	mock.ExpectCommit()
 
 	updateReq := httptest.NewRequest(http.MethodPut, "/api/campaigns/60/characters/99", bytes.NewBufferString(`{"current_hp":10,"status":"inactive","campaign_notes":"Updated notes"}`))
 	updateReq = addChiURLParam(updateReq, "id", "60")
@@ -520,13 +537,19 @@ func TestCampaignHandler_AddUpdateDeleteCharacter(t *testing.T) {
 
 	// UpdateCampaignCharacterFull flow
 	mock.ExpectQuery(`FROM campaign_characters cc`).WithArgs(99, 60, 7).WillReturnRows(charRow())
+	// Subir de 3 para 4 sem level-up disponível exige o DM
+	expectCampaignAccess(mock, 60, 7, 7)
+	mock.ExpectBegin()
+	expectVersionHistory(mock, models.CharacterTypeCampaign, 99)
 	mock.ExpectExec(`UPDATE campaign_characters SET`).WithArgs(
 		sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
 		sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
 		sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
 		sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
-		sqlmock.AnyArg(), sqlmock.AnyArg(), 99, 60,
+		sqlmock.AnyArg(), sqlmock.AnyArg(), 99, 60, sqlmock.AnyArg(), sqlmock.AnyArg(),
 	).WillReturnResult(sqlmock.NewResult(0, 1))
+	expectCharacterVersionRecorded(mock, 99, 60)
+	mock.ExpectCommit()
 
 	fullReq := httptest.NewRequest(http.MethodPut, "/api/campaigns/60/characters/99/full", bytes.NewBufferString(`{"name":"Full","level":4}`))
 	fullReq = addChiURLParam(fullReq, "id", "60")
@@ -678,13 +701,13 @@ func TestCampaignHandler_GetCampaignCharactersAndSingle(t *testing.T) {
 		"id", "campaign_id", "player_id", "source_pc_id", "status", "joined_at", "last_sync", "campaign_notes",
 		"name", "description", "level", "race", "class", "background", "alignment", "attributes", "abilities",
 		"equipment", "hp", "current_hp", "ca", "proficiency_bonus", "inspiration", "skills", "attacks", "spells",
-		"personality_traits", "ideals", "bonds", "flaws", "features", "player_name", "player_username",
+		"personality_traits", "ideals", "bonds", "flaws", "features", "player_name", "experience_points", "level_up_available", "class_levels", "player_username",
 	}
 	characterRows := sqlmock.NewRows(charCols).AddRow(
 		5, 70, 7, 3, "active", now, now, "note",
 		"PC", "desc", 3, "elf", "wizard", "sage", "neutral", []byte(`{}`), []byte(`{}`),
 		[]byte(`{}`), 20, 18, 14, 2, false, []byte(`[]`), []byte(`[]`), []byte(`[]`),
-		"brave", "ideal", "bond", "flaw", pq.StringArray{"feature"}, "Player", "player_username",
+		"brave", "ideal", "bond", "flaw", pq.StringArray{"feature"}, "Player", 0, false, []byte(`[]`), "player_username",
 	)
 	mock.ExpectQuery(`FROM campaign_characters`).WithArgs(70).WillReturnRows(characterRows)
 	mock.ExpectQuery(`FROM campaign_characters`).WithArgs(70).WillReturnRows(characterRows)
@@ -791,7 +814,7 @@ func TestCampaignHandler_GetCampaignCharactersErrors(t *testing.T) {
 			"id", "campaign_id", "player_id", "source_pc_id", "status", "joined_at", "last_sync", "campaign_notes",
 			"name", "description", "level", "race", "class", "background", "alignment", "attributes", "abilities",
 			"equipment", "hp", "current_hp", "ca", "proficiency_bonus", "inspiration", "skills", "attacks", "spells",
-			"personality_traits", "ideals", "bonds", "flaws", "features", "player_name", "player_username",
+			"personality_traits", "ideals", "bonds", "flaws", "features", "player_name", "experience_points", "level_up_available", "class_levels", "player_username",
 		}
 		mock.ExpectQuery(`FROM campaign_characters`).WithArgs(70).WillReturnRows(sqlmock.NewRows(charCols))
 
@@ -879,7 +902,7 @@ func TestCampaignHandler_RegenerateInviteCodeErrors(t *testing.T) {
 				"id", "campaign_id", "player_id", "source_pc_id", "status", "joined_at", "last_sync", "campaign_notes",
 				"name", "description", "level", "race", "class", "background", "alignment", "attributes", "abilities",
 				"equipment", "hp", "current_hp", "ca", "proficiency_bonus", "inspiration", "skills", "attacks", "spells",
-				"personality_traits", "ideals", "bonds", "flaws", "features", "player_name", "player_username",
+				"personality_traits", "ideals", "bonds", "flaws", "features", "player_name", "experience_points", "level_up_available", "class_levels", "player_username",
 			}))
 
 		req := httptest.NewRequest(http.MethodPost, "/api/campaigns/80/regenerate-code", nil)
@@ -911,7 +934,7 @@ func TestCampaignHandler_RegenerateInviteCodeErrors(t *testing.T) {
 				"id", "campaign_id", "player_id", "source_pc_id", "status", "joined_at", "last_sync", "campaign_notes",
 				"name", "description", "level", "race", "class", "background", "alignment", "attributes", "abilities",
 				"equipment", "hp", "current_hp", "ca", "proficiency_bonus", "inspiration", "skills", "attacks", "spells",
-				"personality_traits", "ideals", "bonds", "flaws", "features", "player_name", "player_username",
+				"personality_traits", "ideals", "bonds", "flaws", "features", "player_name", "experience_points", "level_up_available", "class_levels", "player_username",
 			}))
 
 		mock.ExpectExec(`UPDATE campaigns SET invite_code =`).WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 80).
@@ -1018,7 +1041,7 @@ func TestCampaignHandler_RegenerateInviteAndSync(t *testing.T) {
 			"id", "campaign_id", "player_id", "source_pc_id", "status", "joined_at", "last_sync", "campaign_notes",
 			"name", "description", "level", "race", "class", "background", "alignment", "attributes", "abilities",
 			"equipment", "hp", "current_hp", "ca", "proficiency_bonus", "inspiration", "skills", "attacks", "spells",
-			"personality_traits", "ideals", "bonds", "flaws", "features", "player_name", "player_username",
+			"personality_traits", "ideals", "bonds", "flaws", "features", "player_name", "experience_points", "level_up_available", "class_levels", "player_username",
 		}))
 
 	mock.ExpectExec(`UPDATE campaigns SET invite_code =`).WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 80).
@@ -1033,7 +1056,7 @@ func TestCampaignHandler_RegenerateInviteAndSync(t *testing.T) {
 		t.Fatalf("expected 200, got %d", rec.Code)
 	}
 
-	// SyncCampaignCharacter (no-op path)
+	// SyncCampaignCharacter (sem alterações: snapshot igual ao PC)
 	charCols := []string{
 		"id", "campaign_id", "player_id", "source_pc_id", "status", "joined_at", "last_sync", "campaign_notes",
 		"name", "description", "level", "race", "class", "background", "alignment", "attributes", "abilities",
@@ -1046,6 +1069,20 @@ func TestCampaignHandler_RegenerateInviteAndSync(t *testing.T) {
 		"brave", "ideal", "bond", "flaw", pq.StringArray{"feature"}, "Player",
 	)
 	mock.ExpectQuery(`FROM campaign_characters cc`).WithArgs(5, 80, 7).WillReturnRows(charRow)
+	mock.ExpectQuery(`FROM pcs`).WithArgs(3, 7).
+		WillReturnRows(sqlmock.NewRows(syncPCCols).AddRow(syncPCRow("PC", 3)...))
+	mock.ExpectQuery(`SELECT sync_base FROM campaign_characters`).WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"sync_base"}).AddRow(nil))
+	mock.ExpectQuery(`WHERE source_pc_id = \$1`).WithArgs(3).
+		WillReturnRows(sqlmock.NewRows(charCols).AddRow(
+			5, 80, 7, 3, "active", now, now, "note", "PC", "desc", 3, "elf", "wizard", "sage", "neutral",
+			[]byte(`{}`), []byte(`{}`), []byte(`{}`), 20, 18, 14, 2, false, []byte(`[]`), []byte(`[]`), []byte(`[]`),
+			"brave", "ideal", "bond", "flaw", pq.StringArray{"feature"}, "Player",
+		))
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE campaign_characters SET\s+sync_base`).WithArgs(sqlmock.AnyArg(), 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
 
 	reqSync := httptest.NewRequest(http.MethodPost, "/api/campaigns/80/characters/5/sync", bytes.NewBufferString(`{"sync_to_other_campaigns":true}`))
 	reqSync = addChiURLParam(reqSync, "id", "80")
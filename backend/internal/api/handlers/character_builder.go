@@ -0,0 +1,292 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	"rpg-saas-backend/internal/db"
+	"rpg-saas-backend/internal/models"
+	"rpg-saas-backend/internal/utils"
+)
+
+// RollAbilityScores rola 4d6 descartando o menor para cada atributo e grava a rolagem, que o
+// rascunho referencia em roll_id com o método roll
+func (h *PCHandler) RollAbilityScores(w http.ResponseWriter, r *http.Request) {
+	userID, err := utils.ExtractUserID(r)
+	if err != nil {
+		h.Response.SendInternalError(w, "User ID not found in context")
+		return
+	}
+
+	dice := make([]int64, 0, models.AbilityRollScores*models.AbilityRollDice)
+	for i := 0; i < models.AbilityRollScores; i++ {
+		rolls, _, err := rollDice(h.dice(), models.AbilityRollDice, 6, 0)
+		if err != nil {
+			h.Response.SendInternalError(w, "Failed to roll ability scores")
+			return
+		}
+		for _, roll := range rolls {
+			dice = append(dice, int64(roll))
+		}
+	}
+
+	roll := models.AbilityScoreRoll{
+		UserID: userID,
+		Dice:   dice,
+		Scores: models.ScoresFromDice(dice),
+	}
+	if err := h.DB.CreateAbilityScoreRoll(r.Context(), &roll); err != nil {
+		h.Response.HandleDBError(w, err, "record ability score roll")
+		return
+	}
+
+	h.Response.SendCreated(w, "Ability scores rolled", roll)
+}
+
+// ValidateCharacterDraft valida o rascunho sem gravar nada e retorna a ficha montada
+func (h *PCHandler) ValidateCharacterDraft(w http.ResponseWriter, r *http.Request) {
+	draft, rules, ok := h.loadCharacterDraft(w, r)
+	if !ok {
+		return
+	}
+
+	h.Response.SendJSON(w, map[string]any{
+		"valid": true,
+		"build": models.NewCharacterBuild(*draft, *rules),
+	}, http.StatusOK)
+}
+
+// BuildPC valida o rascunho e cria o PC, com PV, CA e bônus de proficiência calculados pelas
+// regras. Uma rolagem de atributos só pode criar um PC.
+func (h *PCHandler) BuildPC(w http.ResponseWriter, r *http.Request) {
+	draft, rules, ok := h.loadCharacterDraft(w, r)
+	if !ok {
+		return
+	}
+
+	userID, _ := utils.ExtractUserID(r)
+	build := models.NewCharacterBuild(*draft, *rules)
+	pc := build.ToPC(*draft)
+	pc.PlayerID = userID
+	models.ComputeSheet(&pc, models.CharacterRules{Race: rules.Race, Class: rules.Class}).ApplyTo(&pc)
+
+	var rollID *int
+	if draft.AbilityMethod == models.AbilityMethodRoll {
+		rollID = draft.RollID
+	}
+
+	err := h.DB.CreateBuiltPC(r.Context(), &pc, rollID, userID)
+	if errors.Is(err, db.ErrAbilityRollUsed) {
+		h.Response.SendConflict(w, "This ability score roll was already used; roll again")
+		return
+	}
+	if err != nil {
+		h.Response.HandleDBError(w, err, "create PC")
+		return
+	}
+
+	h.Response.SendCreated(w, "PC created successfully", map[string]any{
+		"pc":    pc,
+		"build": build,
+	})
+}
+
+// loadCharacterDraft lê o rascunho, resolve as regras e o valida. Em caso de erro, a resposta
+// já foi enviada e ok é false.
+func (h *PCHandler) loadCharacterDraft(w http.ResponseWriter, r *http.Request) (*models.CharacterDraft, *models.BuilderRules, bool) {
+	userID, err := utils.ExtractUserID(r)
+	if err != nil {
+		h.Response.SendInternalError(w, "User ID not found in context")
+		return nil, nil, false
+	}
+
+	var draft models.CharacterDraft
+	if err := json.NewDecoder(r.Body).Decode(&draft); err != nil {
+		h.Response.SendBadRequest(w, "Invalid request body: "+err.Error())
+		return nil, nil, false
+	}
+
+	rules, err := h.DB.LoadBuilderRules(r.Context(), &draft, userID)
+	if err != nil {
+		h.Response.HandleDBError(w, err, "load character rules")
+		return nil, nil, false
+	}
+
+	if errs := h.validateCharacterDraft(draft, *rules); errs.HasErrors() {
+		h.Response.SendValidationErrors(w, errs)
+		return nil, nil, false
+	}
+
+	return &draft, rules, true
+}
+
+// validateCharacterDraft confere o rascunho contra as regras do SRD ou homebrew e reporta
+// cada campo inválido
+func (h *PCHandler) validateCharacterDraft(draft models.CharacterDraft, rules models.BuilderRules) utils.ValidationErrors {
+	errs := h.Validator.BatchValidate(
+		func() error { return h.Validator.ValidateRequired(draft.Name, "name") },
+		func() error { return h.Validator.ValidateLevel(draft.Level) },
+	)
+
+	switch {
+	case strings.TrimSpace(draft.Race) == "":
+		errs = append(errs, fieldError("race", "required", "race is required"))
+	case rules.Race.Source == models.RulesSourceDefault:
+		errs = append(errs, fieldError("race", "not_found", "race %q was not found in the SRD or in your homebrew", draft.Race))
+	}
+
+	switch {
+	case strings.TrimSpace(draft.Class) == "":
+		errs = append(errs, fieldError("class", "required", "class is required"))
+	case rules.Class.Source == models.RulesSourceDefault:
+		errs = append(errs, fieldError("class", "not_found", "class %q was not found in the SRD or in your homebrew", draft.Class))
+	}
+
+	if draft.Background != "" && rules.Background == nil {
+		errs = append(errs, fieldError("background", "not_found", "background %q was not found in the SRD or in your homebrew", draft.Background))
+	}
+
+	errs = append(errs, validateAbilityScores(draft, rules.Roll)...)
+	errs = append(errs, validateSkillChoices(draft, rules)...)
+
+	return errs
+}
+
+// validateAbilityScores confere os seis atributos contra o método escolhido
+func validateAbilityScores(draft models.CharacterDraft, roll *models.AbilityScoreRoll) utils.ValidationErrors {
+	errs := utils.ValidationErrors{}
+
+	if !slices.Contains(models.AbilityMethods, draft.AbilityMethod) {
+		errs = append(errs, fieldError("ability_method", "invalid_choice", "ability_method must be one of: %s", strings.Join(models.AbilityMethods, ", ")))
+		return errs
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(draft.AbilityScores)) {
+		if !slices.Contains(models.AbilityNames, key) {
+			errs = append(errs, fieldError("ability_scores."+key, "unknown_ability", "%s is not an ability score", key))
+		}
+	}
+	scores := []int{}
+	for _, ability := range models.AbilityNames {
+		score, ok := draft.AbilityScores[ability]
+		if !ok {
+			errs = append(errs, fieldError("ability_scores."+ability, "required", "%s is required", ability))
+			continue
+		}
+		scores = append(scores, score)
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	switch draft.AbilityMethod {
+	case models.AbilityMethodPointBuy:
+		spent := 0
+		for _, ability := range models.AbilityNames {
+			cost, ok := models.PointBuyCost(draft.AbilityScores[ability])
+			if !ok {
+				errs = append(errs, fieldError("ability_scores."+ability, "invalid_range", "point-buy scores must be between 8 and 15"))
+				continue
+			}
+			spent += cost
+		}
+		if len(errs) == 0 && spent > models.PointBuyBudget {
+			errs = append(errs, fieldError("ability_scores", "budget_exceeded", "point-buy costs %d points; the budget is %d", spent, models.PointBuyBudget))
+		}
+
+	case models.AbilityMethodStandardArray:
+		if !sameScores(scores, models.StandardArray) {
+			errs = append(errs, fieldError("ability_scores", "invalid_assignment", "standard array scores must be 15, 14, 13, 12, 10 and 8, each used once"))
+		}
+
+	case models.AbilityMethodRoll:
+		switch {
+		case draft.RollID == nil:
+			errs = append(errs, fieldError("roll_id", "required", "roll_id is required for rolled scores"))
+		case roll == nil:
+			errs = append(errs, fieldError("roll_id", "not_found", "ability score roll %d not found", *draft.RollID))
+		case roll.UsedAt != nil:
+			errs = append(errs, fieldError("roll_id", "roll_used", "ability score roll %d was already used", roll.ID))
+		default:
+			rolled := make([]int, len(roll.Scores))
+			for i, score := range roll.Scores {
+				rolled[i] = int(score)
+			}
+			if !sameScores(scores, rolled) {
+				errs = append(errs, fieldError("ability_scores", "roll_mismatch", "scores must be the rolled values %v, each used once", rolled))
+			}
+		}
+	}
+
+	return errs
+}
+
+// validateSkillChoices confere quantidade e opções das perícias da classe e que nenhuma
+// repete uma proficiência da raça ou do antecedente
+func validateSkillChoices(draft models.CharacterDraft, rules models.BuilderRules) utils.ValidationErrors {
+	errs := utils.ValidationErrors{}
+
+	if rules.Class.Source == models.RulesSourceDefault {
+		return errs
+	}
+
+	if count := rules.Class.SkillChoiceCount; len(draft.SkillChoices) != count {
+		errs = append(errs, fieldError("skill_choices", "invalid_count", "%s must choose exactly %d skills, got %d", rules.Class.Name, count, len(draft.SkillChoices)))
+	}
+
+	granted := map[string]string{}
+	for _, skill := range rules.Race.Skills {
+		granted[skill] = "the " + rules.Race.Name + " race"
+	}
+	if rules.Background != nil {
+		for _, skill := range rules.Background.Skills {
+			if by, ok := granted[skill]; ok {
+				errs = append(errs, fieldError("background", "duplicate", "%s proficiency from the %s background is already granted by %s", skill, rules.Background.Name, by))
+				continue
+			}
+			granted[skill] = "the " + rules.Background.Name + " background"
+		}
+	}
+
+	chosen := map[string]bool{}
+	for i, choice := range draft.SkillChoices {
+		field := fmt.Sprintf("skill_choices[%d]", i)
+		skill := models.FindSkill(choice)
+		switch {
+		case skill == nil:
+			errs = append(errs, fieldError(field, "not_found", "%q is not a skill", choice))
+		case !slices.Contains(rules.Class.SkillOptions, skill.Index):
+			errs = append(errs, fieldError(field, "invalid_choice", "%s is not a %s skill option", skill.Name, rules.Class.Name))
+		case chosen[skill.Index]:
+			errs = append(errs, fieldError(field, "duplicate", "%s was chosen more than once", skill.Name))
+		case granted[skill.Index] != "":
+			errs = append(errs, fieldError(field, "duplicate", "%s is already granted by %s; choose another skill", skill.Name, granted[skill.Index]))
+		}
+		if skill != nil {
+			chosen[skill.Index] = true
+		}
+	}
+
+	return errs
+}
+
+// fieldError monta o erro de validação de um campo
+func fieldError(field, code, format string, args ...any) utils.ValidationError {
+	return utils.ValidationError{Field: field, Message: fmt.Sprintf(format, args...), Code: code}
+}
+
+// sameScores indica se as duas listas têm os mesmos valores, em qualquer ordem
+func sameScores(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(a, b)
+}
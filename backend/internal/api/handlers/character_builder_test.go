@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+
+	"rpg-saas-backend/internal/api/middleware"
+	"rpg-saas-backend/internal/utils"
+)
+
+// expectFighterRules espera a resolução das regras de um guerreiro anão do SRD
+func expectFighterRules(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(`FROM dnd_races`).WithArgs("dwarf").
+		WillReturnRows(sqlmock.NewRows([]string{"api_index", "name", "speed", "ability_bonuses", "proficiencies"}).
+			AddRow("dwarf", "Dwarf", 25, []byte(`[{"ability_score":{"index":"con"},"bonus":2}]`), []byte(`[]`)))
+	mock.ExpectQuery(`FROM dnd_classes`).WithArgs("fighter").
+		WillReturnRows(sqlmock.NewRows([]string{"api_index", "name", "hit_die", "saving_throws", "proficiency_choices", "spellcasting", "spellcasting_ability"}).
+			AddRow("fighter", "Fighter", 10, pq.StringArray{"str", "con"},
+				[]byte(`[{"choose":2,"from":{"options":[{"item":{"index":"skill-athletics"}},{"item":{"index":"skill-perception"}},{"item":{"index":"skill-survival"}}]}}]`),
+				[]byte(`{}`), ""))
+}
+
+func builderRequest(t *testing.T, path string, body any) *http.Request {
+	t.Helper()
+	payload, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("failed to encode body: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(payload))
+	return req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, 7))
+}
+
+func TestPCHandler_RollAbilityScores(t *testing.T) {
+	handler, mock, cleanup := newMockPCHandler(t)
+	defer cleanup()
+
+	handler.Dice = NewScriptedDiceSource(
+		6, 6, 6, 1,
+		5, 5, 5, 5,
+		4, 4, 4, 4,
+		3, 3, 3, 3,
+		2, 2, 2, 2,
+		1, 2, 3, 4,
+	)
+	mock.ExpectQuery(`INSERT INTO ability_score_rolls`).
+		WithArgs(7, "{6,6,6,1,5,5,5,5,4,4,4,4,3,3,3,3,2,2,2,2,1,2,3,4}", "{18,15,12,9,6,9}").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(3, time.Now()))
+
+	rr := httptest.NewRecorder()
+	handler.RollAbilityScores(rr, builderRequest(t, "/api/pcs/builder/roll", nil))
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestPCHandler_ValidateCharacterDraft_FieldErrors(t *testing.T) {
+	handler, mock, cleanup := newMockPCHandler(t)
+	defer cleanup()
+
+	expectFighterRules(mock)
+
+	rr := httptest.NewRecorder()
+	handler.ValidateCharacterDraft(rr, builderRequest(t, "/api/pcs/builder/validate", map[string]any{
+		"name":           "Tordek",
+		"level":          1,
+		"race":           "dwarf",
+		"class":          "fighter",
+		"ability_method": "point_buy",
+		"ability_scores": map[string]int{"strength": 15, "dexterity": 15, "constitution": 15, "intelligence": 15, "wisdom": 8, "charisma": 8},
+		"skill_choices":  []string{"Athletics", "Arcana"},
+	}))
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var resp struct {
+		Fields utils.ValidationErrors `json:"fields"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	codes := map[string]string{}
+	for _, field := range resp.Fields {
+		codes[field.Field] = field.Code
+	}
+	if codes["ability_scores"] != "budget_exceeded" || codes["skill_choices[1]"] != "invalid_choice" {
+		t.Fatalf("unexpected field errors: %+v", resp.Fields)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestPCHandler_BuildPC(t *testing.T) {
+	handler, mock, cleanup := newMockPCHandler(t)
+	defer cleanup()
+
+	expectFighterRules(mock)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO pcs`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(20))
+	expectPCVersionRecorded(mock, 20)
+	mock.ExpectCommit()
+
+	rr := httptest.NewRecorder()
+	handler.BuildPC(rr, builderRequest(t, "/api/pcs/builder", map[string]any{
+		"name":           "Tordek",
+		"level":          1,
+		"race":           "dwarf",
+		"class":          "fighter",
+		"ability_method": "standard_array",
+		"ability_scores": map[string]int{"strength": 15, "dexterity": 13, "constitution": 14, "intelligence": 8, "wisdom": 12, "charisma": 10},
+		"skill_choices":  []string{"athletics", "Percepção"},
+	}))
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var resp struct {
+		Data struct {
+			PC struct {
+				ID int `json:"id"`
+				HP int `json:"hp"`
+				CA int `json:"ca"`
+			} `json:"pc"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	// d10 + Con 16 (+3); CA 10 + Des +1
+	if resp.Data.PC.ID != 20 || resp.Data.PC.HP != 13 || resp.Data.PC.CA != 11 {
+		t.Fatalf("unexpected PC: %+v", resp.Data.PC)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
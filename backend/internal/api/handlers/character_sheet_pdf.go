@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"rpg-saas-backend/internal/models"
+	"rpg-saas-backend/internal/pdf"
+	"rpg-saas-backend/internal/utils"
+)
+
+// GetPCSheetPDF gera a ficha imprimível do PC em PDF, com os valores calculados pelas regras
+func (h *PCHandler) GetPCSheetPDF(w http.ResponseWriter, r *http.Request) {
+	userID, err := utils.ExtractUserID(r)
+	if err != nil {
+		h.Response.SendInternalError(w, "User ID not found in context")
+		return
+	}
+
+	id, err := utils.ExtractID(r)
+	if err != nil {
+		h.Response.SendBadRequest(w, err.Error())
+		return
+	}
+
+	pc, err := h.DB.GetPCByIDAndPlayer(r.Context(), id, userID)
+	if err != nil {
+		h.Response.SendNotFound(w, "PC not found")
+		return
+	}
+
+	sheet, ok := h.computeSheet(w, r, pc, userID)
+	if !ok {
+		return
+	}
+
+	data, err := pdf.CharacterSheet(pc, *sheet)
+	if err != nil {
+		h.Response.SendInternalError(w, "Failed to generate character sheet")
+		return
+	}
+
+	sendSheetPDF(w, fmt.Sprintf("pc-%d-sheet.pdf", pc.ID), data)
+}
+
+// GetCampaignCharacterSheetPDF gera a ficha imprimível de um personagem da campanha (dono ou
+// DM). As regras homebrew são resolvidas pelo dono do personagem.
+func (h *CampaignHandler) GetCampaignCharacterSheetPDF(w http.ResponseWriter, r *http.Request) {
+	userID, err := utils.ExtractUserID(r)
+	if err != nil {
+		h.Response.SendInternalError(w, "User ID not found in context")
+		return
+	}
+
+	campaignID, err := utils.ExtractIDParam(r, "id")
+	if err != nil {
+		h.Response.SendBadRequest(w, err.Error())
+		return
+	}
+
+	characterID, err := utils.ExtractIDParam(r, "characterId")
+	if err != nil {
+		h.Response.SendBadRequest(w, err.Error())
+		return
+	}
+
+	character, err := h.DB.GetCampaignCharacter(r.Context(), characterID, campaignID, userID)
+	if err != nil {
+		h.Response.SendNotFound(w, "Character not found or access denied")
+		return
+	}
+
+	pc := &models.PC{ID: character.ID, PlayerID: character.PlayerID, CurrentHP: character.CurrentHP}
+	pc.ApplySnapshot(character)
+
+	rules, err := h.DB.LoadCharacterRules(r.Context(), pc, character.PlayerID)
+	if err != nil {
+		h.Response.HandleDBError(w, err, "load character rules")
+		return
+	}
+
+	data, err := pdf.CharacterSheet(pc, models.ComputeSheet(pc, *rules))
+	if err != nil {
+		h.Response.SendInternalError(w, "Failed to generate character sheet")
+		return
+	}
+
+	sendSheetPDF(w, fmt.Sprintf("campaign-%d-character-%d-sheet.pdf", campaignID, character.ID), data)
+}
+
+// sendSheetPDF envia o PDF como anexo para download
+func sendSheetPDF(w http.ResponseWriter, filename string, data []byte) {
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
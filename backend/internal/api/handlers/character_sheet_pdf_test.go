@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+
+	"rpg-saas-backend/internal/api/middleware"
+)
+
+func TestPCHandler_GetPCSheetPDF(t *testing.T) {
+	handler, mock, cleanup := newMockPCHandler(t)
+	defer cleanup()
+
+	pcCols := []string{
+		"id", "name", "description", "level", "race", "class", "background", "alignment",
+		"attributes", "abilities", "equipment", "hp", "current_hp", "ca", "proficiency_bonus",
+		"inspiration", "skills", "attacks", "spells", "personality_traits", "ideals", "bonds",
+		"flaws", "features", "player_name", "player_id", "is_homebrew", "is_unique", "created_at",
+	}
+	mock.ExpectQuery(`FROM pcs`).WithArgs(1, 7).WillReturnRows(sqlmock.NewRows(pcCols).AddRow(
+		1, "Brom", "", 3, "dwarf", "cleric", "acolyte", "",
+		[]byte(`{"strength":14,"dexterity":10,"constitution":16,"intelligence":10,"wisdom":16,"charisma":8}`),
+		[]byte(`{"proficiencies":["Light armor","Shields"]}`), []byte(`[{"name":"Torch","quantity":5,"equipped":false}]`),
+		25, 20, 10, 2, false, []byte(`{"religion":{"proficient":true}}`),
+		[]byte(`[{"name":"Warhammer","bonus":4,"damage":"1d8+2","type":"bludgeoning"}]`),
+		[]byte(`{"spell_slots":{"1":{"total":4,"used":1}},"known_spells":[{"name":"Bless","level":1}]}`),
+		"Stubborn", "Faith", "", "", pq.StringArray{"Darkvision"},
+		"Player", 7, false, false, time.Now(),
+	))
+	expectClericRules(mock)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/pcs/1/sheet.pdf", nil)
+	req = addChiParam(req, "id", "1")
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, 7))
+	rr := httptest.NewRecorder()
+	handler.GetPCSheetPDF(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	if rr.Header().Get("Content-Type") != "application/pdf" {
+		t.Fatalf("unexpected content type %q", rr.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(rr.Header().Get("Content-Disposition"), "pc-1-sheet.pdf") {
+		t.Fatalf("expected attachment filename, got %q", rr.Header().Get("Content-Disposition"))
+	}
+	if !bytes.HasPrefix(rr.Body.Bytes(), []byte("%PDF-")) || !bytes.Contains(rr.Body.Bytes(), []byte("/Count 2")) {
+		t.Fatalf("expected a two-page PDF")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestCampaignHandler_GetCampaignCharacterSheetPDF(t *testing.T) {
+	t.Run("DM downloads a player's sheet", func(t *testing.T) {
+		handler, mock, cleanup := newMockCampaignHandler(t)
+		defer cleanup()
+
+		mock.ExpectQuery(`FROM campaign_characters cc`).WithArgs(5, 10, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "campaign_id", "player_id", "status", "name", "level", "race", "class", "attributes", "hp", "current_hp", "ca"}).
+				AddRow(5, 10, 8, "active", "Brom", 3, "dwarf", "cleric", []byte(`{"constitution":16,"wisdom":16}`), 25, 12, 10))
+		expectClericRules(mock)
+
+		req := withCampaignUser(httptest.NewRequest(http.MethodGet, "/api/campaigns/10/characters/5/sheet.pdf", nil), "10", 1)
+		req = addChiURLParam(req, "characterId", "5")
+		rr := httptest.NewRecorder()
+		handler.GetCampaignCharacterSheetPDF(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if rr.Header().Get("Content-Type") != "application/pdf" || !bytes.HasPrefix(rr.Body.Bytes(), []byte("%PDF-")) {
+			t.Fatalf("expected a PDF response")
+		}
+		if !strings.Contains(rr.Header().Get("Content-Disposition"), "campaign-10-character-5-sheet.pdf") {
+			t.Fatalf("expected attachment filename, got %q", rr.Header().Get("Content-Disposition"))
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+
+	t.Run("outsider gets 404", func(t *testing.T) {
+		handler, mock, cleanup := newMockCampaignHandler(t)
+		defer cleanup()
+
+		mock.ExpectQuery(`FROM campaign_characters cc`).WithArgs(5, 10, 99).WillReturnError(sql.ErrNoRows)
+
+		req := withCampaignUser(httptest.NewRequest(http.MethodGet, "/api/campaigns/10/characters/5/sheet.pdf", nil), "10", 99)
+		req = addChiURLParam(req, "characterId", "5")
+		rr := httptest.NewRecorder()
+		handler.GetCampaignCharacterSheetPDF(rr, req)
+
+		if rr.Code != http.StatusNotFound {
+			t.Fatalf("expected 404, got %d: %s", rr.Code, rr.Body.String())
+		}
+	})
+}
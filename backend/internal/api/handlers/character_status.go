@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"rpg-saas-backend/internal/db"
+	"rpg-saas-backend/internal/models"
+)
+
+// checkCharacterStatusChange valida a transição para status (vazio mantém o atual) e a
+// alteração de PV pedidas. Morte, ressurreição e volta da aposentadoria exigem DM ou co-DM.
+// Em caso de erro, a resposta já foi enviada e o retorno é false.
+func (h *CampaignHandler) checkCharacterStatusChange(w http.ResponseWriter, r *http.Request, character *models.CampaignCharacter, userID int, status string, currentHP *int) bool {
+	target := character.Status
+	if status != "" {
+		target = status
+	}
+
+	hpChanged := currentHP != nil && (character.CurrentHP == nil || *character.CurrentHP != *currentHP)
+	if hpChanged && !models.CanTakeHPUpdate(character.Status) && target != models.CharacterStatusResurrected {
+		h.Response.SendConflict(w, "Dead characters can't take HP updates")
+		return false
+	}
+
+	if target == character.Status {
+		return true
+	}
+
+	if err := models.ValidateCharacterStatusTransition(character.Status, target); err != nil {
+		h.Response.SendValidationError(w, err.Error())
+		return false
+	}
+
+	if models.CharacterStatusRequiresDM(character.Status, target) {
+		campaign, err := h.DB.GetCampaignByID(r.Context(), character.CampaignID, userID)
+		if err != nil || !canManageCampaign(campaign, userID) {
+			h.Response.SendForbidden(w, "Only the DM can mark a character dead, resurrect it or bring it back from retirement")
+			return false
+		}
+	}
+
+	return true
+}
+
+// changeCharacterStatus grava a transição já validada e executa os hooks. Em caso de erro,
+// a resposta já foi enviada e ok é false.
+func (h *CampaignHandler) changeCharacterStatus(w http.ResponseWriter, r *http.Request, character *models.CampaignCharacter, event models.CharacterStatusEvent) (*models.CharacterStatusChange, bool) {
+	change, err := h.DB.ChangeCampaignCharacterStatus(r.Context(), character, event)
+	if err != nil {
+		if !h.sendCharacterStatusError(w, err) {
+			h.Response.HandleDBError(w, err, "change character status")
+		}
+		return nil, false
+	}
+
+	return change, true
+}
+
+// sendCharacterStatusError responde os erros próprios de uma mudança de status de personagem
+// e retorna false para os demais, que ficam a cargo de quem chama
+func (h *CampaignHandler) sendCharacterStatusError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, db.ErrCharacterStatusChanged):
+		h.Response.SendConflict(w, "Character status changed in the meantime; reload and try again")
+	case errors.Is(err, db.ErrUniquePCInUse):
+		h.Response.SendConflict(w, err.Error())
+	case errors.Is(err, db.ErrSessionNotInCampaign):
+		h.Response.SendValidationError(w, err.Error())
+	default:
+		return false
+	}
+	return true
+}
+
+// GetCharacterDeaths lista as mortes de personagens da campanha (membros da campanha)
+func (h *CampaignHandler) GetCharacterDeaths(w http.ResponseWriter, r *http.Request) {
+	campaign, _, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	deaths, err := h.DB.GetCharacterDeaths(r.Context(), campaign.ID)
+	if err != nil {
+		h.Response.HandleDBError(w, err, "fetch character deaths")
+		return
+	}
+
+	h.Response.SendJSON(w, map[string]any{
+		"deaths": deaths,
+		"count":  len(deaths),
+	}, http.StatusOK)
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+
+	"rpg-saas-backend/internal/models"
+)
+
+func expectCampaignCharacterRow(mock sqlmock.Sqlmock, characterID, campaignID, userID int, status string) {
+	mock.ExpectQuery(`FROM campaign_characters cc`).WithArgs(characterID, campaignID, userID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "campaign_id", "player_id", "source_pc_id", "status", "name", "level", "current_hp"}).
+			AddRow(characterID, campaignID, 8, 3, status, "Aria", 4, 0))
+}
+
+func TestCampaignHandler_UpdateCampaignCharacterStatusRules(t *testing.T) {
+	t.Run("player cannot mark own character dead", func(t *testing.T) {
+		handler, mock, cleanup := newMockCampaignHandler(t)
+		defer cleanup()
+
+		expectCampaignCharacterRow(mock, 5, 10, 8, "unconscious")
+		expectCampaignAccess(mock, 10, 8, 7, 8)
+
+		req := withCampaignUser(httptest.NewRequest(http.MethodPut, "/api/campaigns/10/characters/5", bytes.NewBufferString(`{"status":"dead","cause":"failed death saves"}`)), "10", 8)
+		req = addChiURLParam(req, "characterId", "5")
+		rr := httptest.NewRecorder()
+		handler.UpdateCampaignCharacter(rr, req)
+
+		if rr.Code != http.StatusForbidden {
+			t.Fatalf("expected 403, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+
+	t.Run("dead character rejects HP updates", func(t *testing.T) {
+		handler, mock, cleanup := newMockCampaignHandler(t)
+		defer cleanup()
+
+		expectCampaignCharacterRow(mock, 5, 10, 7, "dead")
+
+		req := withCampaignUser(httptest.NewRequest(http.MethodPut, "/api/campaigns/10/characters/5", bytes.NewBufferString(`{"current_hp":5}`)), "10", 7)
+		req = addChiURLParam(req, "characterId", "5")
+		rr := httptest.NewRecorder()
+		handler.UpdateCampaignCharacter(rr, req)
+
+		if rr.Code != http.StatusConflict {
+			t.Fatalf("expected 409, got %d: %s", rr.Code, rr.Body.String())
+		}
+	})
+
+	t.Run("invalid transition", func(t *testing.T) {
+		handler, mock, cleanup := newMockCampaignHandler(t)
+		defer cleanup()
+
+		expectCampaignCharacterRow(mock, 5, 10, 7, "dead")
+
+		req := withCampaignUser(httptest.NewRequest(http.MethodPut, "/api/campaigns/10/characters/5", bytes.NewBufferString(`{"status":"active"}`)), "10", 7)
+		req = addChiURLParam(req, "characterId", "5")
+		rr := httptest.NewRecorder()
+		handler.UpdateCampaignCharacter(rr, req)
+
+		if rr.Code != http.StatusUnprocessableEntity {
+			t.Fatalf("expected 422, got %d: %s", rr.Code, rr.Body.String())
+		}
+	})
+}
+
+func TestCampaignHandler_UpdateCampaignCharacterFullStatus(t *testing.T) {
+	t.Run("status and snapshot are written together", func(t *testing.T) {
+		handler, mock, cleanup := newMockCampaignHandler(t)
+		defer cleanup()
+
+		expectCampaignCharacterRow(mock, 5, 10, 8, "active")
+		mock.ExpectBegin()
+		expectVersionHistory(mock, models.CharacterTypeCampaign, 5)
+		mock.ExpectExec(`UPDATE campaign_characters SET\s+current_hp = \$1, status = \$2`).
+			WithArgs(sqlmock.AnyArg(), "inactive", "", 5, 10, "active").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		expectCharacterVersionRecorded(mock, 5, 10)
+		mock.ExpectExec(`UPDATE campaign_characters SET\s+name = \$1`).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(`SELECT\s+id, campaign_id, player_id`).WithArgs(5).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "campaign_id", "name", "status"}).AddRow(5, 10, "Aria", "inactive"))
+		mock.ExpectQuery(`SELECT version, snapshot FROM character_versions`).WillReturnError(sqlmock.ErrCancelled)
+		mock.ExpectRollback()
+
+		req := withCampaignUser(httptest.NewRequest(http.MethodPut, "/api/campaigns/10/characters/5/full", bytes.NewBufferString(`{"name":"Aria","level":4,"status":"inactive"}`)), "10", 8)
+		req = addChiURLParam(req, "characterId", "5")
+		rr := httptest.NewRecorder()
+		handler.UpdateCampaignCharacterFull(rr, req)
+
+		// A falha ao registrar a versão do snapshot desfaz também a mudança de status
+		if rr.Code != http.StatusInternalServerError {
+			t.Fatalf("expected 500, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+
+	t.Run("concurrent status change is a conflict", func(t *testing.T) {
+		handler, mock, cleanup := newMockCampaignHandler(t)
+		defer cleanup()
+
+		expectCampaignCharacterRow(mock, 5, 10, 8, "active")
+		mock.ExpectBegin()
+		expectVersionHistory(mock, models.CharacterTypeCampaign, 5)
+		mock.ExpectExec(`UPDATE campaign_characters SET\s+current_hp = \$1, status = \$2`).
+			WithArgs(sqlmock.AnyArg(), "inactive", "", 5, 10, "active").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		req := withCampaignUser(httptest.NewRequest(http.MethodPut, "/api/campaigns/10/characters/5/full", bytes.NewBufferString(`{"name":"Aria","level":4,"status":"inactive"}`)), "10", 8)
+		req = addChiURLParam(req, "characterId", "5")
+		rr := httptest.NewRecorder()
+		handler.UpdateCampaignCharacterFull(rr, req)
+
+		if rr.Code != http.StatusConflict {
+			t.Fatalf("expected 409, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+}
@@ -0,0 +1,304 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"rpg-saas-backend/internal/db"
+	"rpg-saas-backend/internal/models"
+	"rpg-saas-backend/internal/utils"
+)
+
+// GetCampaignCharacterHistory lista todas as alterações de personagens da campanha (DM ou co-DM)
+func (h *CampaignHandler) GetCampaignCharacterHistory(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	if !canManageCampaign(campaign, userID) {
+		h.Response.SendForbidden(w, "Only the DM can view the campaign character history")
+		return
+	}
+
+	pagination := utils.ExtractPagination(r, 50)
+	versions, err := h.DB.GetCampaignCharacterVersions(r.Context(), campaign.ID, pagination.Limit, pagination.Offset)
+	if err != nil {
+		h.Response.HandleDBError(w, err, "fetch character history")
+		return
+	}
+
+	h.Response.SendJSON(w, map[string]any{
+		"versions": versions,
+		"count":    len(versions),
+		"limit":    pagination.Limit,
+		"offset":   pagination.Offset,
+	}, http.StatusOK)
+}
+
+// GetCampaignCharacterVersions lista as versões de um personagem da campanha (dono, DM ou co-DM)
+func (h *CampaignHandler) GetCampaignCharacterVersions(w http.ResponseWriter, r *http.Request) {
+	_, characterID, ok := h.loadVersionedCharacter(w, r)
+	if !ok {
+		return
+	}
+
+	sendCharacterVersions(w, r, h.DB, h.Response, models.CharacterTypeCampaign, characterID)
+}
+
+// GetCampaignCharacterVersion retorna uma versão com o snapshot completo
+func (h *CampaignHandler) GetCampaignCharacterVersion(w http.ResponseWriter, r *http.Request) {
+	_, characterID, ok := h.loadVersionedCharacter(w, r)
+	if !ok {
+		return
+	}
+
+	sendCharacterVersion(w, r, h.DB, h.Response, models.CharacterTypeCampaign, characterID)
+}
+
+// DiffCampaignCharacterVersions compara duas versões (?from=&to=) de um personagem da campanha
+func (h *CampaignHandler) DiffCampaignCharacterVersions(w http.ResponseWriter, r *http.Request) {
+	_, characterID, ok := h.loadVersionedCharacter(w, r)
+	if !ok {
+		return
+	}
+
+	sendCharacterVersionDiff(w, r, h.DB, h.Response, models.CharacterTypeCampaign, characterID)
+}
+
+// RevertCampaignCharacter restaura um personagem da campanha para uma versão anterior
+// (DM ou co-DM). A reversão vira uma nova versão, então também pode ser desfeita.
+func (h *CampaignHandler) RevertCampaignCharacter(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	if !canManageCampaign(campaign, userID) {
+		h.Response.SendForbidden(w, "Only the DM can revert campaign characters")
+		return
+	}
+
+	if !checkCampaignNotCompleted(w, h.Response, campaign) {
+		return
+	}
+
+	characterID, err := utils.ExtractIDParam(r, "characterId")
+	if err != nil {
+		h.Response.SendBadRequest(w, err.Error())
+		return
+	}
+
+	version, err := utils.ExtractIDParam(r, "version")
+	if err != nil {
+		h.Response.SendBadRequest(w, err.Error())
+		return
+	}
+
+	character, recorded, err := h.DB.RevertCampaignCharacter(r.Context(), campaign.ID, characterID, version, userID)
+	switch {
+	case errors.Is(err, db.ErrVersionNotFound):
+		h.Response.SendNotFound(w, "Character version not found in this campaign")
+		return
+	case errors.Is(err, db.ErrCharacterFrozen):
+		h.Response.SendConflict(w, "Character is frozen: its campaign is completed")
+		return
+	case err != nil:
+		h.Response.HandleDBError(w, err, "revert campaign character")
+		return
+	}
+
+	h.Response.SendSuccess(w, "Character reverted", map[string]any{
+		"character": character,
+		"version":   recorded,
+	})
+}
+
+// loadVersionedCharacter valida o acesso ao histórico de um personagem da campanha: o dono
+// do personagem, o DM ou um co-DM. Em caso de erro, a resposta já foi enviada e ok é false.
+func (h *CampaignHandler) loadVersionedCharacter(w http.ResponseWriter, r *http.Request) (*models.Campaign, int, bool) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return nil, 0, false
+	}
+
+	characterID, err := utils.ExtractIDParam(r, "characterId")
+	if err != nil {
+		h.Response.SendBadRequest(w, err.Error())
+		return nil, 0, false
+	}
+
+	ownerID, found, err := h.DB.GetCampaignCharacterOwner(r.Context(), campaign.ID, characterID)
+	if err != nil {
+		h.Response.HandleDBError(w, err, "fetch campaign character")
+		return nil, 0, false
+	}
+	if !found {
+		h.Response.SendNotFound(w, "Character not found in this campaign")
+		return nil, 0, false
+	}
+
+	if ownerID != userID && !canManageCampaign(campaign, userID) {
+		h.Response.SendForbidden(w, "Only the character owner or the DM can view its history")
+		return nil, 0, false
+	}
+
+	return campaign, characterID, true
+}
+
+// GetPCVersions lista as versões de um PC do usuário
+func (h *PCHandler) GetPCVersions(w http.ResponseWriter, r *http.Request) {
+	pc, ok := h.loadOwnedPC(w, r)
+	if !ok {
+		return
+	}
+
+	sendCharacterVersions(w, r, h.DB, h.Response, models.CharacterTypePC, pc.ID)
+}
+
+// GetPCVersion retorna uma versão do PC com o snapshot completo
+func (h *PCHandler) GetPCVersion(w http.ResponseWriter, r *http.Request) {
+	pc, ok := h.loadOwnedPC(w, r)
+	if !ok {
+		return
+	}
+
+	sendCharacterVersion(w, r, h.DB, h.Response, models.CharacterTypePC, pc.ID)
+}
+
+// DiffPCVersions compara duas versões (?from=&to=) de um PC do usuário
+func (h *PCHandler) DiffPCVersions(w http.ResponseWriter, r *http.Request) {
+	pc, ok := h.loadOwnedPC(w, r)
+	if !ok {
+		return
+	}
+
+	sendCharacterVersionDiff(w, r, h.DB, h.Response, models.CharacterTypePC, pc.ID)
+}
+
+// RevertPC restaura um PC do usuário para uma versão anterior, registrada como nova versão
+func (h *PCHandler) RevertPC(w http.ResponseWriter, r *http.Request) {
+	pc, ok := h.loadOwnedPC(w, r)
+	if !ok {
+		return
+	}
+
+	version, err := utils.ExtractIDParam(r, "version")
+	if err != nil {
+		h.Response.SendBadRequest(w, err.Error())
+		return
+	}
+
+	target, err := h.DB.GetCharacterVersion(r.Context(), models.CharacterTypePC, pc.ID, version)
+	if err != nil {
+		h.Response.HandleDBError(w, err, "fetch PC version")
+		return
+	}
+	if target == nil {
+		h.Response.SendNotFound(w, "PC version not found")
+		return
+	}
+
+	if err := pc.ApplyVersionState(target.Snapshot); err != nil {
+		h.Response.SendInternalError(w, "Failed to apply PC version")
+		return
+	}
+
+	if err := h.DB.UpdatePC(r.Context(), pc, pc.PlayerID, models.VersionActionRevert); err != nil {
+		h.Response.HandleDBError(w, err, "revert PC")
+		return
+	}
+
+	h.Response.SendSuccess(w, "PC reverted", pc)
+}
+
+// loadOwnedPC carrega o PC {id} do usuário. Em caso de erro, a resposta já foi enviada.
+func (h *PCHandler) loadOwnedPC(w http.ResponseWriter, r *http.Request) (*models.PC, bool) {
+	userID, err := utils.ExtractUserID(r)
+	if err != nil {
+		h.Response.SendInternalError(w, "User ID not found in context")
+		return nil, false
+	}
+
+	id, err := utils.ExtractID(r)
+	if err != nil {
+		h.Response.SendBadRequest(w, err.Error())
+		return nil, false
+	}
+
+	pc, err := h.DB.GetPCByIDAndPlayer(r.Context(), id, userID)
+	if err != nil {
+		h.Response.HandleDBError(w, err, "fetch PC")
+		return nil, false
+	}
+
+	return pc, true
+}
+
+func sendCharacterVersions(w http.ResponseWriter, r *http.Request, database *db.PostgresDB, response *utils.ResponseHandler, characterType string, characterID int) {
+	versions, err := database.GetCharacterVersions(r.Context(), characterType, characterID)
+	if err != nil {
+		response.HandleDBError(w, err, "fetch character versions")
+		return
+	}
+
+	response.SendJSON(w, map[string]any{
+		"versions": versions,
+		"count":    len(versions),
+	}, http.StatusOK)
+}
+
+func sendCharacterVersion(w http.ResponseWriter, r *http.Request, database *db.PostgresDB, response *utils.ResponseHandler, characterType string, characterID int) {
+	number, err := utils.ExtractIDParam(r, "version")
+	if err != nil {
+		response.SendBadRequest(w, err.Error())
+		return
+	}
+
+	version, err := database.GetCharacterVersion(r.Context(), characterType, characterID, number)
+	if err != nil {
+		response.HandleDBError(w, err, "fetch character version")
+		return
+	}
+	if version == nil {
+		response.SendNotFound(w, "Character version not found")
+		return
+	}
+
+	response.SendJSON(w, version, http.StatusOK)
+}
+
+// sendCharacterVersionDiff compara os snapshots de duas versões quaisquer; from pode ser
+// posterior a to para ver o que uma reversão desfaria
+func sendCharacterVersionDiff(w http.ResponseWriter, r *http.Request, database *db.PostgresDB, response *utils.ResponseHandler, characterType string, characterID int) {
+	from, errFrom := strconv.Atoi(r.URL.Query().Get("from"))
+	to, errTo := strconv.Atoi(r.URL.Query().Get("to"))
+	if errFrom != nil || errTo != nil || from <= 0 || to <= 0 {
+		response.SendBadRequest(w, "Query parameters from and to must be positive version numbers")
+		return
+	}
+
+	fromVersion, err := database.GetCharacterVersion(r.Context(), characterType, characterID, from)
+	if err != nil {
+		response.HandleDBError(w, err, "fetch character version")
+		return
+	}
+	toVersion, err := database.GetCharacterVersion(r.Context(), characterType, characterID, to)
+	if err != nil {
+		response.HandleDBError(w, err, "fetch character version")
+		return
+	}
+	if fromVersion == nil || toVersion == nil {
+		response.SendNotFound(w, "Character version not found")
+		return
+	}
+
+	response.SendJSON(w, models.VersionDiff{
+		CharacterType: characterType,
+		CharacterID:   characterID,
+		From:          from,
+		To:            to,
+		Changes:       models.DiffVersionStates(fromVersion.Snapshot, toVersion.Snapshot),
+	}, http.StatusOK)
+}
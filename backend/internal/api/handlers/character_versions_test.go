@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+
+	"rpg-saas-backend/internal/models"
+)
+
+var characterVersionCols = []string{
+	"id", "character_type", "character_id", "campaign_id", "version", "action", "author_id",
+	"author_name", "character_name", "changes", "created_at", "snapshot",
+}
+
+// expectVersionHistory simula personagem que já tem histórico: nenhuma versão de base é gravada
+func expectVersionHistory(mock sqlmock.Sqlmock, characterType string, characterID int) {
+	mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM character_versions`).WithArgs(characterType, characterID).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+}
+
+// expectCharacterVersionRecorded espera o registro da primeira versão de um personagem da campanha
+func expectCharacterVersionRecorded(mock sqlmock.Sqlmock, characterID, campaignID int) {
+	mock.ExpectQuery(`SELECT\s+id, campaign_id, player_id`).WithArgs(characterID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "campaign_id", "name", "level", "status"}).
+			AddRow(characterID, campaignID, "Aria", 1, "active"))
+	mock.ExpectQuery(`SELECT version, snapshot FROM character_versions`).
+		WithArgs(models.CharacterTypeCampaign, characterID).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(`INSERT INTO character_versions`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(1, time.Now()))
+}
+
+// expectPCVersionRecorded espera o registro da primeira versão de um PC
+func expectPCVersionRecorded(mock sqlmock.Sqlmock, pcID int) {
+	mock.ExpectQuery(`FROM pcs WHERE id = \$1`).WithArgs(pcID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "level", "player_id"}).AddRow(pcID, "PC", 1, 7))
+	mock.ExpectQuery(`SELECT version, snapshot FROM character_versions`).
+		WithArgs(models.CharacterTypePC, pcID).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(`INSERT INTO character_versions`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(1, time.Now()))
+}
+
+func TestCampaignHandler_RevertCampaignCharacterRequiresDM(t *testing.T) {
+	handler, mock, cleanup := newMockCampaignHandler(t)
+	defer cleanup()
+
+	expectCampaignAccess(mock, 10, 8, 7, 8)
+
+	req := withCampaignUser(httptest.NewRequest(http.MethodPost, "/api/campaigns/10/characters/5/versions/2/revert", nil), "10", 8)
+	req = addChiURLParam(req, "characterId", "5")
+	req = addChiURLParam(req, "version", "2")
+	rr := httptest.NewRecorder()
+	handler.RevertCampaignCharacter(rr, req)
+
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestCampaignHandler_RevertFrozenCharacter(t *testing.T) {
+	handler, mock, cleanup := newMockCampaignHandler(t)
+	defer cleanup()
+
+	expectCampaignAccess(mock, 10, 7, 7, 8)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT snapshot FROM character_versions`).
+		WithArgs(models.CharacterTypeCampaign, 5, 2, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"snapshot"}).AddRow([]byte(`{"level":2}`)))
+	mock.ExpectQuery(`FOR UPDATE`).WithArgs(5, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "campaign_id", "status", "frozen_at"}).AddRow(5, 10, "active", time.Now()))
+	mock.ExpectRollback()
+
+	req := withCampaignUser(httptest.NewRequest(http.MethodPost, "/api/campaigns/10/characters/5/versions/2/revert", nil), "10", 7)
+	req = addChiURLParam(req, "characterId", "5")
+	req = addChiURLParam(req, "version", "2")
+	rr := httptest.NewRecorder()
+	handler.RevertCampaignCharacter(rr, req)
+
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestCampaignHandler_CharacterVersionsOwnerOrDM(t *testing.T) {
+	t.Run("other player is forbidden", func(t *testing.T) {
+		handler, mock, cleanup := newMockCampaignHandler(t)
+		defer cleanup()
+
+		expectCampaignAccess(mock, 10, 9, 7, 8, 9)
+		mock.ExpectQuery(`SELECT player_id FROM campaign_characters`).WithArgs(5, 10).
+			WillReturnRows(sqlmock.NewRows([]string{"player_id"}).AddRow(8))
+
+		req := withCampaignUser(httptest.NewRequest(http.MethodGet, "/api/campaigns/10/characters/5/versions", nil), "10", 9)
+		req = addChiURLParam(req, "characterId", "5")
+		rr := httptest.NewRecorder()
+		handler.GetCampaignCharacterVersions(rr, req)
+
+		if rr.Code != http.StatusForbidden {
+			t.Fatalf("expected 403, got %d: %s", rr.Code, rr.Body.String())
+		}
+	})
+
+	t.Run("DM diffs two versions", func(t *testing.T) {
+		handler, mock, cleanup := newMockCampaignHandler(t)
+		defer cleanup()
+
+		expectCampaignAccess(mock, 10, 7, 7, 8)
+		mock.ExpectQuery(`SELECT player_id FROM campaign_characters`).WithArgs(5, 10).
+			WillReturnRows(sqlmock.NewRows([]string{"player_id"}).AddRow(8))
+		mock.ExpectQuery(`FROM character_versions v`).WithArgs(models.CharacterTypeCampaign, 5, 1).
+			WillReturnRows(sqlmock.NewRows(characterVersionCols).
+				AddRow(1, models.CharacterTypeCampaign, 5, 10, 1, "create", 8, "bob", "Aria", []byte(`[]`), time.Now(), []byte(`{"level":1,"hp":10,"name":"Aria"}`)))
+		mock.ExpectQuery(`FROM character_versions v`).WithArgs(models.CharacterTypeCampaign, 5, 3).
+			WillReturnRows(sqlmock.NewRows(characterVersionCols).
+				AddRow(3, models.CharacterTypeCampaign, 5, 10, 3, "xp", 7, "dm", "Aria", []byte(`[]`), time.Now(), []byte(`{"level":2,"hp":17,"name":"Aria"}`)))
+
+		req := withCampaignUser(httptest.NewRequest(http.MethodGet, "/api/campaigns/10/characters/5/versions/diff?from=1&to=3", nil), "10", 7)
+		req = addChiURLParam(req, "characterId", "5")
+		rr := httptest.NewRecorder()
+		handler.DiffCampaignCharacterVersions(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+
+		var diff models.VersionDiff
+		if err := json.NewDecoder(rr.Body).Decode(&diff); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if diff.From != 1 || diff.To != 3 || len(diff.Changes) != 2 || diff.Changes[0].Field != "hp" || diff.Changes[1].Field != "level" {
+			t.Fatalf("unexpected diff: %+v", diff)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+}
@@ -1,24 +1,42 @@
 package handlers
 
 import (
-	"crypto/rand"
 	"encoding/json"
 	"fmt"
-	"math/big"
 	"net/http"
 	"regexp"
 	"strconv"
 	"time"
 
+	"rpg-saas-backend/internal/db"
 	"rpg-saas-backend/internal/models"
+	"rpg-saas-backend/internal/utils"
 )
 
-// DiceHandler gerencia operações de rolagem de dados
-type DiceHandler struct{}
+// DiceHandler gerencia operações de rolagem de dados e macros salvas
+type DiceHandler struct {
+	DB        *db.PostgresDB
+	Response  *utils.ResponseHandler
+	Validator *utils.Validator
+	Source    DiceSource // crypto/rand por padrão; substituível em testes e replays
+}
 
 // NewDiceHandler cria um novo handler de dados
-func NewDiceHandler() *DiceHandler {
-	return &DiceHandler{}
+func NewDiceHandler(db *db.PostgresDB) *DiceHandler {
+	return &DiceHandler{
+		DB:        db,
+		Response:  utils.NewResponseHandler(),
+		Validator: utils.NewValidator(),
+		Source:    NewCryptoDiceSource(),
+	}
+}
+
+// source retorna a fonte de dados configurada, usando crypto/rand se nenhuma foi definida
+func (h *DiceHandler) source() DiceSource {
+	if h.Source == nil {
+		return NewCryptoDiceSource()
+	}
+	return h.Source
 }
 
 // parseDiceNotation faz o parse da notação de dados (ex: "2d6+3", "1d20", "3d8-2")
@@ -58,17 +76,15 @@ func parseDiceNotation(notation string) (*models.ParsedDice, error) {
 }
 
 // rollDice rola os dados e retorna os resultados individuais e o total
-func rollDice(quantity, sides, modifier int) ([]int, int, error) {
+func rollDice(source DiceSource, quantity, sides, modifier int) ([]int, int, error) {
 	rolls := make([]int, quantity)
 	total := modifier
 
 	for i := 0; i < quantity; i++ {
-		// Usar crypto/rand para aleatoriedade mais segura
-		n, err := rand.Int(rand.Reader, big.NewInt(int64(sides)))
+		roll, err := source.Roll(sides)
 		if err != nil {
-			return nil, 0, fmt.Errorf("erro ao gerar número aleatório: %w", err)
+			return nil, 0, err
 		}
-		roll := int(n.Int64()) + 1 // +1 porque rand retorna 0 a sides-1
 		rolls[i] = roll
 		total += roll
 	}
@@ -101,65 +117,12 @@ func (h *DiceHandler) RollDice(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var rolls []int
-	var total int
-	var droppedRolls []int
-
-	// Se for vantagem ou desvantagem e for 1d20, rolar 2d20
-	if (req.Advantage || req.Disadvantage) && parsed.Quantity == 1 && parsed.Sides == 20 {
-		// Rolar 2d20
-		allRolls, _, err := rollDice(2, parsed.Sides, 0)
-		if err != nil {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusInternalServerError)
-			json.NewEncoder(w).Encode(map[string]string{"error": "Erro ao rolar dados: " + err.Error()})
-			return
-		}
-
-		if req.Advantage {
-			// Vantagem: pegar o maior
-			if allRolls[0] > allRolls[1] {
-				rolls = []int{allRolls[0]}
-				droppedRolls = []int{allRolls[1]}
-			} else {
-				rolls = []int{allRolls[1]}
-				droppedRolls = []int{allRolls[0]}
-			}
-		} else {
-			// Desvantagem: pegar o menor
-			if allRolls[0] < allRolls[1] {
-				rolls = []int{allRolls[0]}
-				droppedRolls = []int{allRolls[1]}
-			} else {
-				rolls = []int{allRolls[1]}
-				droppedRolls = []int{allRolls[0]}
-			}
-		}
-		total = rolls[0] + parsed.Modifier
-	} else {
-		// Rolagem normal
-		rolls, total, err = rollDice(parsed.Quantity, parsed.Sides, parsed.Modifier)
-		if err != nil {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusInternalServerError)
-			json.NewEncoder(w).Encode(map[string]string{"error": "Erro ao rolar dados: " + err.Error()})
-			return
-		}
-	}
-
-	// Preparar resposta
-	response := models.DiceRollResponse{
-		Notation:     req.Notation,
-		Quantity:     parsed.Quantity,
-		Sides:        parsed.Sides,
-		Modifier:     parsed.Modifier,
-		Rolls:        rolls,
-		Total:        total,
-		Timestamp:    time.Now(),
-		Label:        req.Label,
-		Advantage:    req.Advantage,
-		Disadvantage: req.Disadvantage,
-		DroppedRolls: droppedRolls,
+	response, err := executeRoll(h.source(), req, parsed)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Erro ao rolar dados: " + err.Error()})
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -196,7 +159,7 @@ func (h *DiceHandler) RollMultiple(w http.ResponseWriter, r *http.Request) {
 		}
 
 		// Rolar os dados
-		rolls, total, err := rollDice(parsed.Quantity, parsed.Sides, parsed.Modifier)
+		rolls, total, err := rollDice(h.source(), parsed.Quantity, parsed.Sides, parsed.Modifier)
 		if err != nil {
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusInternalServerError)
@@ -221,3 +184,61 @@ func (h *DiceHandler) RollMultiple(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(responses)
 }
+
+// executeRoll rola uma notação já validada, aplicando vantagem/desvantagem em 1d20
+func executeRoll(source DiceSource, req models.DiceRollRequest, parsed *models.ParsedDice) (*models.DiceRollResponse, error) {
+	var rolls []int
+	var total int
+	var droppedRolls []int
+	var err error
+
+	// Se for vantagem ou desvantagem e for 1d20, rolar 2d20
+	if (req.Advantage || req.Disadvantage) && parsed.Quantity == 1 && parsed.Sides == 20 {
+		// Rolar 2d20
+		allRolls, _, err := rollDice(source, 2, parsed.Sides, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		if req.Advantage {
+			// Vantagem: pegar o maior
+			if allRolls[0] > allRolls[1] {
+				rolls = []int{allRolls[0]}
+				droppedRolls = []int{allRolls[1]}
+			} else {
+				rolls = []int{allRolls[1]}
+				droppedRolls = []int{allRolls[0]}
+			}
+		} else {
+			// Desvantagem: pegar o menor
+			if allRolls[0] < allRolls[1] {
+				rolls = []int{allRolls[0]}
+				droppedRolls = []int{allRolls[1]}
+			} else {
+				rolls = []int{allRolls[1]}
+				droppedRolls = []int{allRolls[0]}
+			}
+		}
+		total = rolls[0] + parsed.Modifier
+	} else {
+		// Rolagem normal
+		rolls, total, err = rollDice(source, parsed.Quantity, parsed.Sides, parsed.Modifier)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &models.DiceRollResponse{
+		Notation:     req.Notation,
+		Quantity:     parsed.Quantity,
+		Sides:        parsed.Sides,
+		Modifier:     parsed.Modifier,
+		Rolls:        rolls,
+		Total:        total,
+		Timestamp:    time.Now(),
+		Label:        req.Label,
+		Advantage:    req.Advantage,
+		Disadvantage: req.Disadvantage,
+		DroppedRolls: droppedRolls,
+	}, nil
+}
@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"rpg-saas-backend/internal/models"
+	"rpg-saas-backend/internal/utils"
+)
+
+// bonusOnlyRegex aceita um acerto informado apenas como bônus ("+5", "-1", "3")
+var bonusOnlyRegex = regexp.MustCompile(`^[+-]?\d+$`)
+
+// RollAttack rola o acerto (d20) e o dano de um ataque, aplicando as regras de crítico
+func (h *DiceHandler) RollAttack(w http.ResponseWriter, r *http.Request) {
+	var req models.AttackRollRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Response.SendBadRequest(w, "Invalid request body")
+		return
+	}
+
+	if req.CritRule == "" {
+		req.CritRule = models.CritRuleDoubleDice
+	}
+	if req.CritRange == 0 {
+		req.CritRange = 20
+	}
+
+	var toHit, damage *models.ParsedDice
+	validationErrors := h.Validator.BatchValidate(
+		func() error {
+			parsed, err := parseToHitNotation(req.ToHit)
+			if err != nil {
+				return utils.ValidationError{Field: "to_hit", Message: err.Error(), Code: "invalid_format"}
+			}
+			toHit = parsed
+			return nil
+		},
+		func() error {
+			parsed, err := parseDiceNotation(req.Damage)
+			if err != nil {
+				return utils.ValidationError{Field: "damage", Message: err.Error(), Code: "invalid_format"}
+			}
+			damage = parsed
+			return nil
+		},
+		func() error {
+			if req.Advantage && req.Disadvantage {
+				return utils.ValidationError{
+					Field:   "advantage",
+					Message: "Não é possível rolar com vantagem e desvantagem ao mesmo tempo",
+					Code:    "invalid_combination",
+				}
+			}
+			return nil
+		},
+		func() error { return h.Validator.ValidateChoice(req.CritRule, "crit_rule", models.CritRules) },
+		func() error { return h.Validator.ValidateIntRange(req.CritRange, "crit_range", 2, 20) },
+	)
+
+	if validationErrors.HasErrors() {
+		h.Response.SendValidationError(w, validationErrors.Error())
+		return
+	}
+
+	response, err := executeAttack(h.source(), req, toHit, damage)
+	if err != nil {
+		h.Response.SendInternalError(w, "Erro ao rolar dados: "+err.Error())
+		return
+	}
+
+	h.Response.SendJSON(w, response, http.StatusOK)
+}
+
+// parseToHitNotation aceita "1d20+X" ou apenas o bônus, sempre resultando em 1d20
+func parseToHitNotation(notation string) (*models.ParsedDice, error) {
+	if bonusOnlyRegex.MatchString(notation) {
+		modifier, err := strconv.Atoi(notation)
+		if err != nil {
+			return nil, fmt.Errorf("bônus de acerto inválido")
+		}
+		return &models.ParsedDice{Quantity: 1, Sides: 20, Modifier: modifier}, nil
+	}
+
+	parsed, err := parseDiceNotation(notation)
+	if err != nil {
+		return nil, err
+	}
+
+	if parsed.Quantity != 1 || parsed.Sides != 20 {
+		return nil, fmt.Errorf("a rolagem de acerto deve usar 1d20 (recebido %s)", notation)
+	}
+
+	return parsed, nil
+}
+
+// formatNotation monta a notação XdY±Z a partir de uma notação parseada
+func formatNotation(parsed *models.ParsedDice) string {
+	switch {
+	case parsed.Modifier > 0:
+		return fmt.Sprintf("%dd%d+%d", parsed.Quantity, parsed.Sides, parsed.Modifier)
+	case parsed.Modifier < 0:
+		return fmt.Sprintf("%dd%d%d", parsed.Quantity, parsed.Sides, parsed.Modifier)
+	default:
+		return fmt.Sprintf("%dd%d", parsed.Quantity, parsed.Sides)
+	}
+}
+
+// executeAttack rola acerto e dano; o dano de um crítico segue req.CritRule
+func executeAttack(source DiceSource, req models.AttackRollRequest, toHit, damage *models.ParsedDice) (*models.AttackRollResponse, error) {
+	hitRoll, err := executeRoll(source, models.DiceRollRequest{
+		Notation:     formatNotation(toHit),
+		Label:        req.Label,
+		Advantage:    req.Advantage,
+		Disadvantage: req.Disadvantage,
+	}, toHit)
+	if err != nil {
+		return nil, err
+	}
+
+	natural := hitRoll.Rolls[0]
+	critical := natural >= req.CritRange
+	fumble := natural == 1
+
+	damageRoll, err := rollDamage(source, req.Damage, damage, critical, req.CritRule)
+	if err != nil {
+		return nil, err
+	}
+	damageRoll.Label = req.Label
+
+	response := &models.AttackRollResponse{
+		ToHit:       *hitRoll,
+		Damage:      *damageRoll,
+		NaturalRoll: natural,
+		Critical:    critical,
+		Fumble:      fumble,
+		CritRule:    req.CritRule,
+		Label:       req.Label,
+		Timestamp:   time.Now(),
+	}
+
+	if req.TargetAC != nil {
+		// Crítico sempre acerta e 1 natural sempre erra
+		hit := critical || (!fumble && hitRoll.Total >= *req.TargetAC)
+		response.Hit = &hit
+	}
+
+	return response, nil
+}
+
+// rollDamage rola o dano, aplicando a regra de crítico quando necessário
+func rollDamage(source DiceSource, notation string, parsed *models.ParsedDice, critical bool, critRule string) (*models.DiceRollResponse, error) {
+	quantity := parsed.Quantity
+	if critical && critRule == models.CritRuleDoubleDice {
+		quantity *= 2
+	}
+
+	rolls, total, err := rollDice(source, quantity, parsed.Sides, parsed.Modifier)
+	if err != nil {
+		return nil, err
+	}
+
+	if critical {
+		switch critRule {
+		case models.CritRuleMaxDice:
+			for i := 0; i < parsed.Quantity; i++ {
+				rolls = append(rolls, parsed.Sides)
+				total += parsed.Sides
+			}
+			quantity *= 2
+		case models.CritRuleDoubleTotal:
+			total *= 2
+		}
+	}
+
+	return &models.DiceRollResponse{
+		Notation:  notation,
+		Quantity:  quantity,
+		Sides:     parsed.Sides,
+		Modifier:  parsed.Modifier,
+		Rolls:     rolls,
+		Total:     total,
+		Timestamp: time.Now(),
+	}, nil
+}
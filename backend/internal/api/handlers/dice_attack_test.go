@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"rpg-saas-backend/internal/models"
+)
+
+func TestParseToHitNotation(t *testing.T) {
+	parsed, err := parseToHitNotation("+5")
+	if err != nil {
+		t.Fatalf("expected bonus-only to-hit to parse, got %v", err)
+	}
+	if parsed.Quantity != 1 || parsed.Sides != 20 || parsed.Modifier != 5 {
+		t.Fatalf("unexpected parsed to-hit: %+v", parsed)
+	}
+
+	parsed, err = parseToHitNotation("1d20-1")
+	if err != nil || parsed.Modifier != -1 {
+		t.Fatalf("expected 1d20-1 to parse, got %+v %v", parsed, err)
+	}
+
+	if _, err := parseToHitNotation("2d6+3"); err == nil {
+		t.Fatal("expected error for non-d20 to-hit")
+	}
+}
+
+func TestRollDamage_CritRules(t *testing.T) {
+	parsed := &models.ParsedDice{Quantity: 2, Sides: 6, Modifier: 3}
+
+	normal, err := rollDamage(NewScriptedDiceSource(2, 5), "2d6+3", parsed, false, models.CritRuleDoubleDice)
+	if err != nil || len(normal.Rolls) != 2 || normal.Total != 10 {
+		t.Fatalf("expected 2 damage dice totalling 10, got %+v %v", normal, err)
+	}
+
+	doubled, _ := rollDamage(NewScriptedDiceSource(2, 5, 1, 6), "2d6+3", parsed, true, models.CritRuleDoubleDice)
+	if len(doubled.Rolls) != 4 || doubled.Quantity != 4 || doubled.Total != 17 {
+		t.Fatalf("double_dice should roll 4 dice, got %+v", doubled)
+	}
+
+	maxed, _ := rollDamage(NewScriptedDiceSource(2, 5), "2d6+3", parsed, true, models.CritRuleMaxDice)
+	if len(maxed.Rolls) != 4 || maxed.Rolls[2] != 6 || maxed.Rolls[3] != 6 || maxed.Total != 22 {
+		t.Fatalf("max_dice should append maxed dice, got %+v", maxed)
+	}
+
+	total, _ := rollDamage(NewScriptedDiceSource(2, 5), "2d6+3", parsed, true, models.CritRuleDoubleTotal)
+	if len(total.Rolls) != 2 || total.Total != 20 {
+		t.Fatalf("double_total should double the whole result, got %+v", total)
+	}
+}
+
+func TestExecuteAttack_CritAndHit(t *testing.T) {
+	toHit := &models.ParsedDice{Quantity: 1, Sides: 20, Modifier: 0}
+	damage := &models.ParsedDice{Quantity: 1, Sides: 8, Modifier: 2}
+	ac := 30
+	req := models.AttackRollRequest{
+		ToHit: "+0", Damage: "1d8+2", CritRule: models.CritRuleDoubleDice, CritRange: 19, TargetAC: &ac,
+	}
+
+	// 19 natural com crit_range 19: crítico e acerto automático
+	resp, err := executeAttack(NewScriptedDiceSource(19, 4, 7), req, toHit, damage)
+	if err != nil {
+		t.Fatalf("unexpected attack error: %v", err)
+	}
+	if !resp.Critical || resp.Hit == nil || !*resp.Hit || len(resp.Damage.Rolls) != 2 || resp.Damage.Total != 13 {
+		t.Fatalf("expected critical hit with doubled dice: %+v", resp)
+	}
+
+	// 1 natural: falha crítica, sempre erra
+	resp, _ = executeAttack(NewScriptedDiceSource(1, 4), req, toHit, damage)
+	if !resp.Fumble || resp.Critical || *resp.Hit {
+		t.Fatalf("natural 1 should be a fumble and miss: %+v", resp)
+	}
+
+	// Resultado comum abaixo da CA
+	resp, _ = executeAttack(NewScriptedDiceSource(15, 4), req, toHit, damage)
+	if resp.Critical || resp.Fumble || *resp.Hit || resp.Damage.Total != 6 {
+		t.Fatalf("expected a regular miss: %+v", resp)
+	}
+}
+
+func TestExecuteAttack_Advantage(t *testing.T) {
+	toHit := &models.ParsedDice{Quantity: 1, Sides: 20, Modifier: 4}
+	damage := &models.ParsedDice{Quantity: 1, Sides: 6, Modifier: 0}
+
+	resp, err := executeAttack(NewScriptedDiceSource(8, 20, 3, 5), models.AttackRollRequest{
+		ToHit: "+4", Damage: "1d6", Advantage: true, CritRule: models.CritRuleDoubleDice, CritRange: 20,
+	}, toHit, damage)
+	if err != nil {
+		t.Fatalf("unexpected attack error: %v", err)
+	}
+	if resp.NaturalRoll != 20 || len(resp.ToHit.DroppedRolls) != 1 || resp.ToHit.DroppedRolls[0] != 8 {
+		t.Fatalf("advantage should keep the highest d20: %+v", resp.ToHit)
+	}
+	if resp.ToHit.Total != 24 || resp.ToHit.Notation != "1d20+4" || !resp.Critical || resp.Damage.Total != 8 {
+		t.Fatalf("unexpected attack result: %+v", resp)
+	}
+	if resp.Hit != nil {
+		t.Fatal("hit should be omitted without target_ac")
+	}
+}
+
+func TestRollAttackHandler(t *testing.T) {
+	handler := NewDiceHandler(nil)
+
+	invalid := []models.AttackRollRequest{
+		{ToHit: "2d6", Damage: "1d8"},
+		{ToHit: "+5", Damage: "bad"},
+		{ToHit: "+5", Damage: "1d8", Advantage: true, Disadvantage: true},
+		{ToHit: "+5", Damage: "1d8", CritRule: "triple"},
+		{ToHit: "+5", Damage: "1d8", CritRange: 25},
+	}
+	for _, payload := range invalid {
+		body, _ := json.Marshal(payload)
+		req := httptest.NewRequest(http.MethodPost, "/api/dice/attack", bytes.NewBuffer(body))
+		rr := httptest.NewRecorder()
+		handler.RollAttack(rr, req)
+		if rr.Code != http.StatusUnprocessableEntity {
+			t.Fatalf("expected 422 for %+v, got %d", payload, rr.Code)
+		}
+	}
+
+	body, _ := json.Marshal(models.AttackRollRequest{ToHit: "1d20+5", Damage: "1d8+3", Label: "Espada longa"})
+	req := httptest.NewRequest(http.MethodPost, "/api/dice/attack", bytes.NewBuffer(body))
+	rr := httptest.NewRecorder()
+	handler.RollAttack(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var resp models.AttackRollResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.CritRule != models.CritRuleDoubleDice || resp.Label != "Espada longa" {
+		t.Fatalf("unexpected attack response: %+v", resp)
+	}
+	if resp.Critical != (resp.NaturalRoll == 20) || resp.Fumble != (resp.NaturalRoll == 1) {
+		t.Fatalf("critical/fumble flags inconsistent with natural roll: %+v", resp)
+	}
+}
@@ -8,7 +8,7 @@ import (
 )
 
 func TestDiceHandler_RollDice_Errors(t *testing.T) {
-	handler := NewDiceHandler()
+	handler := NewDiceHandler(nil)
 
 	t.Run("invalid JSON body", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodPost, "/api/dice/roll", bytes.NewBufferString("invalid"))
@@ -88,7 +88,7 @@ func TestDiceHandler_RollDice_Errors(t *testing.T) {
 }
 
 func TestDiceHandler_RollMultiple_Errors(t *testing.T) {
-	handler := NewDiceHandler()
+	handler := NewDiceHandler(nil)
 
 	t.Run("invalid JSON body", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodPost, "/api/dice/roll-multiple", bytes.NewBufferString("invalid"))
@@ -0,0 +1,269 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"rpg-saas-backend/internal/db"
+	"rpg-saas-backend/internal/models"
+	"rpg-saas-backend/internal/utils"
+)
+
+// GetMacros lista as macros do usuário (filtro opcional ?pc_id=)
+func (h *DiceHandler) GetMacros(w http.ResponseWriter, r *http.Request) {
+	userID, err := utils.ExtractUserID(r)
+	if err != nil {
+		h.Response.SendInternalError(w, "User ID not found in context")
+		return
+	}
+
+	var pcID *int
+	if raw := r.URL.Query().Get("pc_id"); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil {
+			h.Response.SendBadRequest(w, "invalid pc_id parameter")
+			return
+		}
+		pcID = &id
+	}
+
+	macros, err := h.DB.GetDiceMacros(r.Context(), userID, pcID)
+	if err != nil {
+		h.Response.HandleDBError(w, err, "fetch dice macros")
+		return
+	}
+
+	h.Response.SendJSON(w, map[string]any{
+		"macros": macros,
+		"count":  len(macros),
+	}, http.StatusOK)
+}
+
+// GetMacroByID retorna uma macro do usuário
+func (h *DiceHandler) GetMacroByID(w http.ResponseWriter, r *http.Request) {
+	userID, err := utils.ExtractUserID(r)
+	if err != nil {
+		h.Response.SendInternalError(w, "User ID not found in context")
+		return
+	}
+
+	id, err := utils.ExtractID(r)
+	if err != nil {
+		h.Response.SendBadRequest(w, err.Error())
+		return
+	}
+
+	macro, err := h.DB.GetDiceMacroByID(r.Context(), id, userID)
+	if err != nil {
+		h.Response.HandleDBError(w, err, "fetch dice macro")
+		return
+	}
+	if macro == nil {
+		h.Response.SendNotFound(w, "dice macro not found")
+		return
+	}
+
+	h.Response.SendJSON(w, macro, http.StatusOK)
+}
+
+// CreateMacro salva uma nova macro de rolagem
+func (h *DiceHandler) CreateMacro(w http.ResponseWriter, r *http.Request) {
+	userID, err := utils.ExtractUserID(r)
+	if err != nil {
+		h.Response.SendInternalError(w, "User ID not found in context")
+		return
+	}
+
+	var req models.DiceMacroRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Response.SendBadRequest(w, "Invalid request body")
+		return
+	}
+
+	if !h.validateMacroRequest(w, r, &req, userID) {
+		return
+	}
+
+	macro := &models.DiceMacro{
+		UserID:       userID,
+		PCID:         req.PCID,
+		Name:         strings.TrimSpace(req.Name),
+		Notation:     req.Notation,
+		Label:        req.Label,
+		Advantage:    req.Advantage,
+		Disadvantage: req.Disadvantage,
+	}
+
+	err = h.DB.CreateDiceMacro(r.Context(), macro)
+	switch {
+	case errors.Is(err, db.ErrDiceMacroNameTaken):
+		h.Response.SendConflict(w, "A dice macro with this name already exists")
+		return
+	case err != nil:
+		h.Response.HandleDBError(w, err, "create dice macro")
+		return
+	}
+
+	h.Response.SendCreated(w, "Dice macro created successfully", macro)
+}
+
+// UpdateMacro atualiza uma macro existente
+func (h *DiceHandler) UpdateMacro(w http.ResponseWriter, r *http.Request) {
+	userID, err := utils.ExtractUserID(r)
+	if err != nil {
+		h.Response.SendInternalError(w, "User ID not found in context")
+		return
+	}
+
+	id, err := utils.ExtractID(r)
+	if err != nil {
+		h.Response.SendBadRequest(w, err.Error())
+		return
+	}
+
+	var req models.DiceMacroRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Response.SendBadRequest(w, "Invalid request body")
+		return
+	}
+
+	macro, err := h.DB.GetDiceMacroByID(r.Context(), id, userID)
+	if err != nil {
+		h.Response.HandleDBError(w, err, "fetch dice macro")
+		return
+	}
+	if macro == nil {
+		h.Response.SendNotFound(w, "dice macro not found")
+		return
+	}
+
+	if !h.validateMacroRequest(w, r, &req, userID) {
+		return
+	}
+
+	macro.PCID = req.PCID
+	macro.Name = strings.TrimSpace(req.Name)
+	macro.Notation = req.Notation
+	macro.Label = req.Label
+	macro.Advantage = req.Advantage
+	macro.Disadvantage = req.Disadvantage
+
+	err = h.DB.UpdateDiceMacro(r.Context(), macro)
+	switch {
+	case errors.Is(err, db.ErrDiceMacroNameTaken):
+		h.Response.SendConflict(w, "A dice macro with this name already exists")
+		return
+	case err != nil:
+		h.Response.HandleDBError(w, err, "update dice macro")
+		return
+	}
+
+	h.Response.SendJSON(w, macro, http.StatusOK)
+}
+
+// DeleteMacro remove uma macro do usuário
+func (h *DiceHandler) DeleteMacro(w http.ResponseWriter, r *http.Request) {
+	userID, err := utils.ExtractUserID(r)
+	if err != nil {
+		h.Response.SendInternalError(w, "User ID not found in context")
+		return
+	}
+
+	id, err := utils.ExtractID(r)
+	if err != nil {
+		h.Response.SendBadRequest(w, err.Error())
+		return
+	}
+
+	if err := h.DB.DeleteDiceMacro(r.Context(), id, userID); err != nil {
+		h.Response.HandleDBError(w, err, "delete dice macro")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RollMacro executa a rolagem de uma macro salva
+func (h *DiceHandler) RollMacro(w http.ResponseWriter, r *http.Request) {
+	userID, err := utils.ExtractUserID(r)
+	if err != nil {
+		h.Response.SendInternalError(w, "User ID not found in context")
+		return
+	}
+
+	id, err := utils.ExtractID(r)
+	if err != nil {
+		h.Response.SendBadRequest(w, err.Error())
+		return
+	}
+
+	macro, err := h.DB.GetDiceMacroByID(r.Context(), id, userID)
+	if err != nil {
+		h.Response.HandleDBError(w, err, "fetch dice macro")
+		return
+	}
+	if macro == nil {
+		h.Response.SendNotFound(w, "dice macro not found")
+		return
+	}
+
+	response, err := rollMacro(h.source(), macro)
+	if err != nil {
+		h.Response.SendInternalError(w, "Erro ao rolar dados: "+err.Error())
+		return
+	}
+
+	h.Response.SendJSON(w, response, http.StatusOK)
+}
+
+// validateMacroRequest valida o payload e a posse do PC; envia a resposta de erro quando inválido
+func (h *DiceHandler) validateMacroRequest(w http.ResponseWriter, r *http.Request, req *models.DiceMacroRequest, userID int) bool {
+	validationErrors := h.Validator.BatchValidate(
+		func() error { return h.Validator.ValidateName(req.Name, "name") },
+		func() error { return h.Validator.ValidateRequired(req.Notation, "notation") },
+		func() error {
+			if _, err := parseDiceNotation(req.Notation); err != nil {
+				return utils.ValidationError{Field: "notation", Message: err.Error(), Code: "invalid_format"}
+			}
+			return nil
+		},
+		func() error {
+			if req.Advantage && req.Disadvantage {
+				return utils.ValidationError{
+					Field:   "advantage",
+					Message: "Não é possível rolar com vantagem e desvantagem ao mesmo tempo",
+					Code:    "invalid_combination",
+				}
+			}
+			return nil
+		},
+	)
+
+	if validationErrors.HasErrors() {
+		h.Response.SendValidationError(w, validationErrors.Error())
+		return false
+	}
+
+	if req.PCID != nil {
+		if _, err := h.DB.GetPCByIDAndPlayer(r.Context(), *req.PCID, userID); err != nil {
+			h.Response.SendForbidden(w, "PC not found or not owned by user")
+			return false
+		}
+	}
+
+	return true
+}
+
+// rollMacro rola a notação salva na macro
+func rollMacro(source DiceSource, macro *models.DiceMacro) (*models.DiceRollResponse, error) {
+	parsed, err := parseDiceNotation(macro.Notation)
+	if err != nil {
+		return nil, fmt.Errorf("macro %q possui notação inválida: %w", macro.Name, err)
+	}
+
+	return executeRoll(source, macro.ToRollRequest(), parsed)
+}
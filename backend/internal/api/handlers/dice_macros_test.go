@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"rpg-saas-backend/internal/api/middleware"
+	"rpg-saas-backend/internal/db"
+	"rpg-saas-backend/internal/models"
+)
+
+func newMockDiceHandler(t *testing.T) (*DiceHandler, sqlmock.Sqlmock, func()) {
+	t.Helper()
+
+	rawDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+
+	pdb := &db.PostgresDB{DB: sqlx.NewDb(rawDB, "postgres")}
+	return NewDiceHandler(pdb), mock, func() { rawDB.Close() }
+}
+
+func macroColumns() []string {
+	return []string{"id", "user_id", "pc_id", "name", "notation", "label", "advantage", "disadvantage", "created_at", "updated_at"}
+}
+
+func TestDiceHandler_CreateMacro(t *testing.T) {
+	handler, mock, cleanup := newMockDiceHandler(t)
+	defer cleanup()
+
+	mock.ExpectQuery(`INSERT INTO dice_macros`).
+		WithArgs(7, nil, "Longsword", "1d8+3", "Ataque", false, false, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(12))
+
+	body := bytes.NewBufferString(`{"name":"Longsword","notation":"1d8+3","label":"Ataque"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/dice/macros", body)
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, 7))
+	rr := httptest.NewRecorder()
+
+	handler.CreateMacro(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestDiceHandler_CreateMacro_DuplicateName(t *testing.T) {
+	handler, mock, cleanup := newMockDiceHandler(t)
+	defer cleanup()
+
+	mock.ExpectQuery(`INSERT INTO dice_macros`).
+		WillReturnError(&pq.Error{Code: "23505", Message: "duplicate key value violates unique constraint"})
+
+	body := bytes.NewBufferString(`{"name":"Longsword","notation":"1d8+3"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/dice/macros", body)
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, 7))
+	rr := httptest.NewRecorder()
+
+	handler.CreateMacro(rr, req)
+
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestDiceHandler_CreateMacro_Validation(t *testing.T) {
+	handler, mock, cleanup := newMockDiceHandler(t)
+	defer cleanup()
+
+	cases := map[string]string{
+		"missing name":     `{"notation":"1d20"}`,
+		"invalid notation": `{"name":"x","notation":"d20"}`,
+		"adv and disadv":   `{"name":"x","notation":"1d20","advantage":true,"disadvantage":true}`,
+	}
+
+	for name, payload := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/dice/macros", bytes.NewBufferString(payload))
+			req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, 7))
+			rr := httptest.NewRecorder()
+
+			handler.CreateMacro(rr, req)
+
+			if rr.Code != http.StatusUnprocessableEntity {
+				t.Fatalf("expected 422, got %d", rr.Code)
+			}
+		})
+	}
+
+	t.Run("pc not owned", func(t *testing.T) {
+		mock.ExpectQuery(`FROM pcs`).WithArgs(3, 7).WillReturnError(sqlmock.ErrCancelled)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/dice/macros", bytes.NewBufferString(`{"name":"x","notation":"1d20","pc_id":3}`))
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, 7))
+		rr := httptest.NewRecorder()
+
+		handler.CreateMacro(rr, req)
+
+		if rr.Code != http.StatusForbidden {
+			t.Fatalf("expected 403, got %d", rr.Code)
+		}
+	})
+}
+
+func TestDiceHandler_GetMacros_FilterByPC(t *testing.T) {
+	handler, mock, cleanup := newMockDiceHandler(t)
+	defer cleanup()
+
+	now := time.Now()
+	rows := sqlmock.NewRows(macroColumns()).
+		AddRow(1, 7, nil, "Init", "1d20+2", "", false, false, now, now).
+		AddRow(2, 7, 3, "Fireball", "8d6", "", false, false, now, now)
+	mock.ExpectQuery(`FROM dice_macros\s+WHERE user_id = \$1 AND \(pc_id IS NULL OR pc_id = \$2\)`).
+		WithArgs(7, 3).WillReturnRows(rows)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/dice/macros?pc_id=3", nil)
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, 7))
+	rr := httptest.NewRecorder()
+
+	handler.GetMacros(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+
+	var resp struct {
+		Macros []models.DiceMacro `json:"macros"`
+		Count  int                `json:"count"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Count != 2 || resp.Macros[1].PCID == nil || *resp.Macros[1].PCID != 3 {
+		t.Fatalf("unexpected macros: %+v", resp)
+	}
+}
+
+func TestDiceHandler_RollMacro(t *testing.T) {
+	handler, mock, cleanup := newMockDiceHandler(t)
+	defer cleanup()
+
+	now := time.Now()
+	mock.ExpectQuery(`FROM dice_macros\s+WHERE id = \$1 AND user_id = \$2`).
+		WithArgs(5, 7).
+		WillReturnRows(sqlmock.NewRows(macroColumns()).AddRow(5, 7, nil, "Stealth", "1d20+4", "", true, false, now, now))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/dice/macros/5/roll", nil)
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, 7))
+	req = addChiURLParam(req, "id", "5")
+	rr := httptest.NewRecorder()
+
+	handler.RollMacro(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var resp models.DiceRollResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Label != "Stealth" || !resp.Advantage || len(resp.DroppedRolls) != 1 {
+		t.Fatalf("unexpected roll response: %+v", resp)
+	}
+	if resp.Total != resp.Rolls[0]+4 {
+		t.Fatalf("expected total roll+4, got %+v", resp)
+	}
+}
+
+func TestDiceHandler_RollMacro_NotFound(t *testing.T) {
+	handler, mock, cleanup := newMockDiceHandler(t)
+	defer cleanup()
+
+	mock.ExpectQuery(`FROM dice_macros`).WithArgs(9, 7).WillReturnRows(sqlmock.NewRows(macroColumns()))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/dice/macros/9/roll", nil)
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, 7))
+	req = addChiURLParam(req, "id", "9")
+	rr := httptest.NewRecorder()
+
+	handler.RollMacro(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rr.Code)
+	}
+}
+
+func TestParseMacroCommand(t *testing.T) {
+	if name, ok := parseMacroCommand("/macro Longsword"); !ok || name != "Longsword" {
+		t.Fatalf("expected macro command, got %q %v", name, ok)
+	}
+	if _, ok := parseMacroCommand("hello there"); ok {
+		t.Fatal("plain chat should not be treated as macro")
+	}
+	if _, ok := parseMacroCommand("/macro   "); ok {
+		t.Fatal("empty macro name should be rejected")
+	}
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	mathrand "math/rand"
+	"sync"
+)
+
+// DiceSource fornece os resultados individuais dos dados.
+// Roll deve retornar um valor entre 1 e sides.
+type DiceSource interface {
+	Roll(sides int) (int, error)
+}
+
+// CryptoDiceSource usa crypto/rand; é a fonte padrão em produção
+type CryptoDiceSource struct{}
+
+// NewCryptoDiceSource cria a fonte padrão baseada em crypto/rand
+func NewCryptoDiceSource() *CryptoDiceSource {
+	return &CryptoDiceSource{}
+}
+
+// Roll rola um dado de sides lados
+func (s *CryptoDiceSource) Roll(sides int) (int, error) {
+	if sides < 1 {
+		return 0, fmt.Errorf("número de lados inválido: %d", sides)
+	}
+
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(sides)))
+	if err != nil {
+		return 0, fmt.Errorf("erro ao gerar número aleatório: %w", err)
+	}
+	return int(n.Int64()) + 1, nil // +1 porque rand retorna 0 a sides-1
+}
+
+// SeededDiceSource usa um PRNG com semente fixa; a mesma semente
+// reproduz exatamente a mesma sequência de rolagens.
+type SeededDiceSource struct {
+	mu   sync.Mutex
+	seed int64
+	rng  *mathrand.Rand
+}
+
+// NewSeededDiceSource cria uma fonte determinística a partir da semente
+func NewSeededDiceSource(seed int64) *SeededDiceSource {
+	return &SeededDiceSource{
+		seed: seed,
+		rng:  mathrand.New(mathrand.NewSource(seed)),
+	}
+}
+
+// Seed retorna a semente usada, para que a sessão possa ser reproduzida
+func (s *SeededDiceSource) Seed() int64 {
+	return s.seed
+}
+
+// Roll rola um dado de sides lados
+func (s *SeededDiceSource) Roll(sides int) (int, error) {
+	if sides < 1 {
+		return 0, fmt.Errorf("número de lados inválido: %d", sides)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rng.Intn(sides) + 1, nil
+}
+
+// ScriptedDiceSource devolve resultados pré-definidos em ordem.
+// Útil em testes e para reproduzir as rolagens gravadas de uma sessão.
+type ScriptedDiceSource struct {
+	mu      sync.Mutex
+	results []int
+	next    int
+}
+
+// NewScriptedDiceSource cria uma fonte que devolve os resultados informados
+func NewScriptedDiceSource(results ...int) *ScriptedDiceSource {
+	return &ScriptedDiceSource{results: results}
+}
+
+// Remaining retorna quantos resultados ainda não foram consumidos
+func (s *ScriptedDiceSource) Remaining() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.results) - s.next
+}
+
+// Roll devolve o próximo resultado do roteiro
+func (s *ScriptedDiceSource) Roll(sides int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.next >= len(s.results) {
+		return 0, fmt.Errorf("rolagens roteirizadas esgotadas após %d resultados", len(s.results))
+	}
+
+	result := s.results[s.next]
+	if result < 1 || result > sides {
+		return 0, fmt.Errorf("resultado roteirizado %d inválido para d%d", result, sides)
+	}
+
+	s.next++
+	return result, nil
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"rpg-saas-backend/internal/models"
+)
+
+func TestSeededDiceSource_Reproducible(t *testing.T) {
+	a := NewSeededDiceSource(42)
+	b := NewSeededDiceSource(42)
+
+	for i := 0; i < 20; i++ {
+		ra, err := a.Roll(20)
+		if err != nil {
+			t.Fatalf("unexpected roll error: %v", err)
+		}
+		rb, _ := b.Roll(20)
+		if ra != rb {
+			t.Fatalf("same seed should produce same sequence: %d != %d at %d", ra, rb, i)
+		}
+		if ra < 1 || ra > 20 {
+			t.Fatalf("roll out of range: %d", ra)
+		}
+	}
+
+	if a.Seed() != 42 {
+		t.Fatalf("expected seed 42, got %d", a.Seed())
+	}
+}
+
+func TestDiceSources_RejectInvalidSides(t *testing.T) {
+	for _, source := range []DiceSource{NewCryptoDiceSource(), NewSeededDiceSource(1)} {
+		for _, sides := range []int{0, -6} {
+			if _, err := source.Roll(sides); err == nil {
+				t.Fatalf("%T: expected an error for d%d", source, sides)
+			}
+		}
+	}
+}
+
+func TestScriptedDiceSource(t *testing.T) {
+	source := NewScriptedDiceSource(3, 6)
+
+	rolls, total, err := rollDice(source, 2, 6, 1)
+	if err != nil {
+		t.Fatalf("unexpected roll error: %v", err)
+	}
+	if rolls[0] != 3 || rolls[1] != 6 || total != 10 {
+		t.Fatalf("unexpected scripted result: %v %d", rolls, total)
+	}
+	if source.Remaining() != 0 {
+		t.Fatalf("expected script to be consumed, %d left", source.Remaining())
+	}
+
+	if _, err := source.Roll(6); err == nil {
+		t.Fatal("expected error when script is exhausted")
+	}
+	if _, err := NewScriptedDiceSource(7).Roll(6); err == nil {
+		t.Fatal("expected error for result larger than die")
+	}
+}
+
+func TestRollDiceHandler_ScriptedSource(t *testing.T) {
+	handler := NewDiceHandler(nil)
+	handler.Source = NewScriptedDiceSource(4, 17)
+
+	body, _ := json.Marshal(models.DiceRollRequest{Notation: "1d20+2", Disadvantage: true})
+	req := httptest.NewRequest(http.MethodPost, "/api/dice/roll", bytes.NewBuffer(body))
+	rr := httptest.NewRecorder()
+	handler.RollDice(rr, req)
+
+	var resp models.DiceRollResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Total != 6 || resp.Rolls[0] != 4 || resp.DroppedRolls[0] != 17 {
+		t.Fatalf("unexpected disadvantage result: %+v", resp)
+	}
+}
@@ -25,7 +25,7 @@ func TestParseDiceNotation(t *testing.T) {
 }
 
 func TestRollDice(t *testing.T) {
-	rolls, total, err := rollDice(3, 6, 2)
+	rolls, total, err := rollDice(NewCryptoDiceSource(), 3, 6, 2)
 	if err != nil {
 		t.Fatalf("unexpected roll error: %v", err)
 	}
@@ -43,7 +43,7 @@ func TestRollDice(t *testing.T) {
 }
 
 func TestRollDiceHandler(t *testing.T) {
-	handler := NewDiceHandler()
+	handler := NewDiceHandler(nil)
 
 	// Advantage + disadvantage should fail
 	body, _ := json.Marshal(models.DiceRollRequest{Notation: "1d20", Advantage: true, Disadvantage: true})
@@ -85,7 +85,7 @@ func TestRollDiceHandler(t *testing.T) {
 }
 
 func TestRollDiceHandler_AdditionalCases(t *testing.T) {
-	handler := NewDiceHandler()
+	handler := NewDiceHandler(nil)
 
 	t.Run("invalid JSON body", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodPost, "/api/dice/roll", bytes.NewBufferString("invalid-json"))
@@ -156,7 +156,7 @@ func TestRollDiceHandler_AdditionalCases(t *testing.T) {
 }
 
 func TestRollMultipleHandler(t *testing.T) {
-	handler := NewDiceHandler()
+	handler := NewDiceHandler(nil)
 
 	// No requests should be rejected
 	req := httptest.NewRequest(http.MethodPost, "/api/dice/roll-multiple", bytes.NewBufferString("[]"))
@@ -198,7 +198,7 @@ func TestRollMultipleHandler(t *testing.T) {
 }
 
 func TestRollMultipleHandler_Errors(t *testing.T) {
-	handler := NewDiceHandler()
+	handler := NewDiceHandler(nil)
 
 	t.Run("invalid JSON", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodPost, "/api/dice/roll-multiple", bytes.NewBufferString("invalid"))
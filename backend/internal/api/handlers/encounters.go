@@ -54,6 +54,12 @@ func (h *EncounterHandler) GetEncounterByID(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	// Encontros de campanha só são visíveis pelas rotas da campanha
+	if encounter.CampaignID != nil {
+		h.Response.SendNotFound(w, "Encounter not found")
+		return
+	}
+
 	h.Response.SendJSON(w, encounter, http.StatusOK)
 }
 
@@ -66,6 +72,12 @@ func (h *EncounterHandler) CreateEncounter(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	if encounter.CampaignID != nil {
+		if _, ok := loadManagedCampaign(w, r, h.DB, h.Response, *encounter.CampaignID); !ok {
+			return
+		}
+	}
+
 	err = h.DB.CreateEncounter(r.Context(), &encounter)
 	if err != nil {
 		h.Response.HandleDBError(w, err, "create encounter")
@@ -80,6 +92,7 @@ func (h *EncounterHandler) GenerateRandomEncounter(w http.ResponseWriter, r *htt
 		PlayerLevel int    `json:"player_level"`
 		PlayerCount int    `json:"player_count"`
 		Difficulty  string `json:"difficulty"`
+		CampaignID  *int   `json:"campaign_id,omitempty"`
 	}
 
 	err := json.NewDecoder(r.Body).Decode(&request)
@@ -106,11 +119,18 @@ func (h *EncounterHandler) GenerateRandomEncounter(w http.ResponseWriter, r *htt
 		return
 	}
 
+	if request.CampaignID != nil {
+		if _, ok := loadManagedCampaign(w, r, h.DB, h.Response, *request.CampaignID); !ok {
+			return
+		}
+	}
+
 	encounter, err := h.Python.GenerateEncounter(r.Context(), request.PlayerLevel, request.PlayerCount, request.Difficulty)
 	if err != nil {
 		h.Response.SendInternalError(w, "Failed to generate encounter: "+err.Error())
 		return
 	}
+	encounter.CampaignID = request.CampaignID
 
 	err = h.DB.CreateEncounter(r.Context(), encounter)
 	if err != nil {
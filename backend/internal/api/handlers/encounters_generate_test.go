@@ -47,7 +47,7 @@ func TestEncounterHandler_GenerateRandomEncounter(t *testing.T) {
 	handler := NewEncounterHandler(pdb, pyClient)
 
 	mock.ExpectBegin()
-	mock.ExpectQuery(`INSERT INTO encounters`).WithArgs("Forest", "m", 300, 2, 4, nil).
+	mock.ExpectQuery(`INSERT INTO encounters`).WithArgs("Forest", "m", 300, 2, 4, nil, false).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(10, time.Now()))
 	mock.ExpectCommit()
 
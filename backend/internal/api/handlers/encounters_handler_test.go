@@ -69,7 +69,7 @@ func TestEncounterHandler_Create(t *testing.T) {
 	defer cleanup()
 
 	mock.ExpectBegin()
-	mock.ExpectQuery(`INSERT INTO encounters`).WithArgs("Cave", "m", 400, 2, 4, nil).
+	mock.ExpectQuery(`INSERT INTO encounters`).WithArgs("Cave", "m", 400, 2, 4, nil, false).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(5, time.Now()))
 	mock.ExpectCommit()
 
@@ -240,6 +240,7 @@ func (h *HomebrewHandler) CreateHomebrewClass(w http.ResponseWriter, r *http.Req
 		func() error { return h.Validator.ValidateRequired(req.Name, "name") },
 		func() error { return h.Validator.ValidateRequired(req.Description, "description") },
 		func() error { return h.Validator.ValidateRequired(req.PrimaryAbility, "primary_ability") },
+		func() error { return h.Validator.ValidateIntChoice(req.HitDie, "hit_die", models.HomebrewHitDice) },
 	)
 
 	if validationErrors.HasErrors() {
@@ -291,6 +292,11 @@ func (h *HomebrewHandler) UpdateHomebrewClass(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	if err := h.Validator.ValidateIntChoice(req.HitDie, "hit_die", models.HomebrewHitDice); err != nil {
+		h.Response.SendValidationError(w, err.Error())
+		return
+	}
+
 	class := &models.HomebrewClass{
 		ID:                id,
 		Name:              req.Name,
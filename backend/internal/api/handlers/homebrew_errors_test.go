@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -149,6 +150,18 @@ func TestHomebrewHandler_CreateHomebrewClass_Errors(t *testing.T) {
 		}
 	})
 
+	t.Run("invalid hit die", func(t *testing.T) {
+		body := bytes.NewBufferString(`{"name":"Gunslinger","description":"desc","hit_die":0,"primary_ability":"dex"}`)
+		req := httptest.NewRequest(http.MethodPost, "/api/homebrew/classes", body)
+		req = req.WithContext(contextWithUserID(context.Background(), 5))
+		rec := httptest.NewRecorder()
+
+		handler.CreateHomebrewClass(rec, req)
+		if rec.Code != http.StatusUnprocessableEntity || !strings.Contains(rec.Body.String(), "hit_die") {
+			t.Fatalf("expected 422 for hit_die, got %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+
 	t.Run("missing required fields", func(t *testing.T) {
 		body := bytes.NewBufferString(`{"name":""}`)
 		req := httptest.NewRequest(http.MethodPost, "/api/homebrew/classes", body)
@@ -57,6 +57,12 @@ func (h *NPCHandler) GetNPCByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// NPCs de campanha só são visíveis pelas rotas da campanha
+	if npc.CampaignID != nil {
+		h.Response.SendNotFound(w, "NPC not found")
+		return
+	}
+
 	h.Response.SendJSON(w, npc, http.StatusOK)
 }
 
@@ -80,6 +86,12 @@ func (h *NPCHandler) CreateNPC(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if npc.CampaignID != nil {
+		if _, ok := loadManagedCampaign(w, r, h.DB, h.Response, *npc.CampaignID); !ok {
+			return
+		}
+	}
+
 	err = h.DB.CreateNPC(r.Context(), &npc)
 	if err != nil {
 		h.Response.HandleDBError(w, err, "create NPC")
@@ -143,6 +155,7 @@ func (h *NPCHandler) GenerateRandomNPC(w http.ResponseWriter, r *http.Request) {
 		Race             string `json:"race,omitempty"`
 		Class            string `json:"class,omitempty"`      // Frontend sends 'class', ensure this matches
 		Background       string `json:"background,omitempty"` // Frontend sends 'background'
+		CampaignID       *int   `json:"campaign_id,omitempty"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
@@ -171,11 +184,18 @@ func (h *NPCHandler) GenerateRandomNPC(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if request.CampaignID != nil {
+		if _, ok := loadManagedCampaign(w, r, h.DB, h.Response, *request.CampaignID); !ok {
+			return
+		}
+	}
+
 	npc, err := h.Python.GenerateNPC(r.Context(), request.Level, request.AttributesMethod, request.Manual, request.Race, request.Class, request.Background)
 	if err != nil {
 		h.Response.SendInternalError(w, "Failed to generate NPC: "+err.Error())
 		return
 	}
+	npc.CampaignID = request.CampaignID
 
 	err = h.DB.CreateNPC(r.Context(), npc) // Assuming npc is of type *models.NPC
 	if err != nil {
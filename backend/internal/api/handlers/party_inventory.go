@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"rpg-saas-backend/internal/db"
+	"rpg-saas-backend/internal/models"
+	"rpg-saas-backend/internal/utils"
+)
+
+// GetPartyInventory retorna o inventário compartilhado e a bolsa de moedas da campanha
+func (h *CampaignHandler) GetPartyInventory(w http.ResponseWriter, r *http.Request) {
+	campaign, _, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	inventory, err := h.DB.GetPartyInventory(r.Context(), campaign.ID)
+	if err != nil {
+		h.Response.HandleDBError(w, err, "fetch party inventory")
+		return
+	}
+
+	h.Response.SendJSON(w, inventory, http.StatusOK)
+}
+
+// GetLootLog lista quem recebeu o quê do inventário do grupo
+func (h *CampaignHandler) GetLootLog(w http.ResponseWriter, r *http.Request) {
+	campaign, _, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	records, err := h.DB.GetLootLog(r.Context(), campaign.ID)
+	if err != nil {
+		h.Response.HandleDBError(w, err, "fetch loot log")
+		return
+	}
+
+	h.Response.SendJSON(w, map[string]any{
+		"records": records,
+		"count":   len(records),
+	}, http.StatusOK)
+}
+
+// AssignTreasure coloca um tesouro gerado no inventário do grupo (DM ou co-DM)
+func (h *CampaignHandler) AssignTreasure(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	if !canManageCampaign(campaign, userID) {
+		h.Response.SendForbidden(w, "Only the DM can assign treasure")
+		return
+	}
+
+	if !checkCampaignNotCompleted(w, h.Response, campaign) {
+		return
+	}
+
+	treasureID, err := utils.ExtractIDParam(r, "treasureId")
+	if err != nil {
+		h.Response.SendBadRequest(w, "Invalid treasure ID")
+		return
+	}
+
+	result, err := h.DB.AssignTreasureToCampaign(r.Context(), campaign.ID, treasureID, userID)
+	switch {
+	case errors.Is(err, db.ErrTreasureUnavailable):
+		h.Response.SendConflict(w, "Treasure not found or already assigned to a campaign")
+		return
+	case err != nil:
+		h.Response.HandleDBError(w, err, "assign treasure")
+		return
+	}
+
+	h.Response.SendSuccess(w, "Treasure added to the party inventory", result)
+}
+
+// DistributeLoot divide itens e moedas do inventário do grupo entre os personagens da
+// campanha, igualmente ou como o DM escolher (DM ou co-DM)
+func (h *CampaignHandler) DistributeLoot(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	if !canManageCampaign(campaign, userID) {
+		h.Response.SendForbidden(w, "Only the DM can distribute loot")
+		return
+	}
+
+	if !checkCampaignNotCompleted(w, h.Response, campaign) {
+		return
+	}
+
+	var req models.DistributeLootRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Response.SendBadRequest(w, "Invalid request body")
+		return
+	}
+
+	if req.Mode == "" {
+		req.Mode = models.LootModeEven
+	}
+	if validationErrors := h.validateLootRequest(req); validationErrors.HasErrors() {
+		h.Response.SendValidationError(w, validationErrors.Error())
+		return
+	}
+
+	records, err := h.DB.DistributeLoot(r.Context(), campaign.ID, userID, req)
+	switch {
+	case errors.Is(err, db.ErrLootUnavailable), errors.Is(err, db.ErrCharacterNotInCampaign):
+		h.Response.SendValidationError(w, err.Error())
+		return
+	case err != nil:
+		h.Response.HandleDBError(w, err, "distribute loot")
+		return
+	}
+
+	inventory, err := h.DB.GetPartyInventory(r.Context(), campaign.ID)
+	if err != nil {
+		h.Response.HandleDBError(w, err, "fetch party inventory")
+		return
+	}
+
+	h.Response.SendSuccess(w, "Loot distributed", map[string]any{
+		"records":   records,
+		"inventory": inventory,
+	})
+}
+
+// validateLootRequest confere modo, personagens, quantidades e moedas da divisão
+func (h *CampaignHandler) validateLootRequest(req models.DistributeLootRequest) utils.ValidationErrors {
+	errs := utils.ValidationErrors{}
+	if err := h.Validator.ValidateChoice(req.Mode, "mode", models.LootModes); err != nil {
+		var choiceErr utils.ValidationError
+		if errors.As(err, &choiceErr) {
+			return append(errs, choiceErr)
+		}
+		return append(errs, utils.ValidationError{Field: "mode", Message: err.Error(), Code: "invalid_choice"})
+	}
+
+	if req.Mode == models.LootModeEven {
+		if len(req.CharacterIDs) == 0 {
+			errs = append(errs, utils.ValidationError{Field: "character_ids", Message: "at least one character is required", Code: "required"})
+		}
+		if len(req.ItemIDs) == 0 && !req.SplitCoins {
+			errs = append(errs, utils.ValidationError{Field: "item_ids", Message: "nothing to distribute: choose items or split_coins", Code: "required"})
+		}
+		return errs
+	}
+
+	if len(req.Allocations) == 0 {
+		errs = append(errs, utils.ValidationError{Field: "allocations", Message: "at least one allocation is required", Code: "required"})
+	}
+	for _, allocation := range req.Allocations {
+		for _, item := range allocation.Items {
+			if item.Quantity <= 0 {
+				errs = append(errs, utils.ValidationError{Field: "allocations.items.quantity", Message: "must be positive", Code: "invalid_range"})
+			}
+		}
+		for _, amount := range allocation.Coins {
+			if amount < 0 {
+				errs = append(errs, utils.ValidationError{Field: "allocations.coins", Message: "must not be negative", Code: "invalid_range"})
+			}
+		}
+	}
+	return errs
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestCampaignHandler_GetPartyInventory(t *testing.T) {
+	handler, mock, cleanup := newMockCampaignHandler(t)
+	defer cleanup()
+
+	expectCampaignAccess(mock, 10, 8, 7, 8)
+	mock.ExpectQuery(`SELECT coins FROM campaign_purses`).WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"coins"}).AddRow([]byte(`{"gp":30}`)))
+	mock.ExpectQuery(`FROM campaign_inventory_items WHERE campaign_id = \$1 AND quantity > 0`).WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "campaign_id", "treasure_id", "name", "type", "category", "value", "rank", "quantity", "created_at"}))
+
+	req := withCampaignUser(httptest.NewRequest(http.MethodGet, "/api/campaigns/10/inventory", nil), "10", 8)
+	rr := httptest.NewRecorder()
+	handler.GetPartyInventory(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestCampaignHandler_AssignTreasure(t *testing.T) {
+	t.Run("player cannot assign", func(t *testing.T) {
+		handler, mock, cleanup := newMockCampaignHandler(t)
+		defer cleanup()
+
+		expectCampaignAccess(mock, 10, 8, 7, 8)
+
+		req := withCampaignUser(httptest.NewRequest(http.MethodPost, "/api/campaigns/10/treasures/5/assign", nil), "10", 8)
+		req = addChiURLParam(req, "treasureId", "5")
+		rr := httptest.NewRecorder()
+		handler.AssignTreasure(rr, req)
+
+		if rr.Code != http.StatusForbidden {
+			t.Fatalf("expected 403, got %d", rr.Code)
+		}
+	})
+
+	t.Run("treasure already assigned", func(t *testing.T) {
+		handler, mock, cleanup := newMockCampaignHandler(t)
+		defer cleanup()
+
+		expectCampaignAccess(mock, 10, 7, 7)
+		mock.ExpectBegin()
+		mock.ExpectQuery(`UPDATE treasures SET campaign_id`).WithArgs(10, 5).
+			WillReturnRows(sqlmock.NewRows([]string{"name"}))
+		mock.ExpectRollback()
+
+		req := withCampaignUser(httptest.NewRequest(http.MethodPost, "/api/campaigns/10/treasures/5/assign", nil), "10", 7)
+		req = addChiURLParam(req, "treasureId", "5")
+		rr := httptest.NewRecorder()
+		handler.AssignTreasure(rr, req)
+
+		if rr.Code != http.StatusConflict {
+			t.Fatalf("expected 409, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+}
+
+func TestCampaignHandler_DistributeLootValidation(t *testing.T) {
+	cases := map[string]string{
+		"invalid mode":          `{"mode":"random"}`,
+		"even without targets":  `{"mode":"even","item_ids":[1]}`,
+		"even with nothing":     `{"character_ids":[3]}`,
+		"manual with zero item": `{"mode":"manual","allocations":[{"character_id":3,"items":[{"item_id":1,"quantity":0}]}]}`,
+	}
+
+	for name, body := range cases {
+		t.Run(name, func(t *testing.T) {
+			handler, mock, cleanup := newMockCampaignHandler(t)
+			defer cleanup()
+
+			expectCampaignAccess(mock, 10, 7, 7)
+
+			req := withCampaignUser(httptest.NewRequest(http.MethodPost, "/api/campaigns/10/inventory/distribute", bytes.NewBufferString(body)), "10", 7)
+			rr := httptest.NewRecorder()
+			handler.DistributeLoot(rr, req)
+
+			if rr.Code != http.StatusUnprocessableEntity {
+				t.Fatalf("expected 422, got %d: %s", rr.Code, rr.Body.String())
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatalf("sql expectations not met: %v", err)
+			}
+		})
+	}
+}
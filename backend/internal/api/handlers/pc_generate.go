@@ -0,0 +1,327 @@
+package handlers
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"rpg-saas-backend/internal/models"
+	"rpg-saas-backend/internal/python"
+	"rpg-saas-backend/internal/utils"
+)
+
+// GenerateRandomPC gera um PC completo pelo serviço Python e o grava para o usuário. Se o
+// serviço estiver indisponível (conexão, timeout ou 5xx), a ficha é montada em Go com as
+// regras do construtor de personagens; outras falhas respondem 502.
+func (h *PCHandler) GenerateRandomPC(w http.ResponseWriter, r *http.Request) {
+	userID, err := utils.ExtractUserID(r)
+	if err != nil {
+		h.Response.SendInternalError(w, "User ID not found in context")
+		return
+	}
+
+	var request models.GeneratePCRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.Response.SendBadRequest(w, "Invalid request body: "+err.Error())
+		return
+	}
+
+	if request.AttributesMethod == "" {
+		request.AttributesMethod = "rolagem"
+	}
+
+	validationErrors := h.Validator.BatchValidate(
+		func() error { return h.Validator.ValidateLevel(request.Level) },
+		func() error { return h.Validator.ValidateAttributeMethod(request.AttributesMethod) },
+	)
+
+	if validationErrors.HasErrors() {
+		h.Response.SendValidationError(w, validationErrors.Error())
+		return
+	}
+
+	generator := models.PCGeneratorService
+	pc, err := h.Python.GeneratePC(r.Context(), request)
+	switch {
+	case err == nil:
+		if !h.validateGeneratedPC(w, r, pc, userID) {
+			return
+		}
+		models.CompleteGeneratedPC(pc)
+	case python.IsUnavailable(err):
+		log.Printf("PC generation service unavailable, using fallback: %v", err)
+		generator = models.PCGeneratorFallback
+		var ok bool
+		if pc, ok = h.generateFallbackPC(w, r, request, userID); !ok {
+			return
+		}
+	default:
+		log.Printf("PC generation service failed: %v", err)
+		h.Response.SendError(w, "PC generation service failed", http.StatusBadGateway)
+		return
+	}
+
+	pc.PlayerID = userID
+	if request.PlayerName != "" {
+		pc.PlayerName = request.PlayerName
+	}
+	if pc.PlayerName == "" {
+		if user, err := h.DB.GetUserByID(r.Context(), userID); err == nil {
+			pc.PlayerName = user.Username
+		}
+	}
+
+	if err := pc.NormalizeClassLevels(); err != nil {
+		h.Response.SendInternalError(w, "Generated PC is invalid: "+err.Error())
+		return
+	}
+
+	if err := h.DB.CreatePC(r.Context(), pc, userID); err != nil {
+		h.Response.HandleDBError(w, err, "save generated PC")
+		return
+	}
+
+	h.Response.SendCreated(w, "PC generated and saved successfully", models.GeneratedPC{PC: *pc, Generator: generator})
+}
+
+// validateGeneratedPC confere a ficha devolvida pelo serviço contra as regras do construtor:
+// raça, classe e antecedente precisam existir no SRD ou no homebrew do usuário e os seis
+// atributos precisam estar nos limites da geração. Em caso de erro, a resposta já foi enviada.
+func (h *PCHandler) validateGeneratedPC(w http.ResponseWriter, r *http.Request, pc *models.PC, userID int) bool {
+	draft := models.CharacterDraft{Race: pc.Race, Class: pc.Class, Background: pc.Background, Level: pc.Level}
+	rules, err := h.DB.LoadBuilderRules(r.Context(), &draft, userID)
+	if err != nil {
+		h.Response.HandleDBError(w, err, "load character rules")
+		return false
+	}
+
+	errs := utils.ValidationErrors{}
+	if err := h.Validator.ValidateLevel(pc.Level); err != nil {
+		errs = append(errs, fieldError("level", "invalid_range", "%s", err.Error()))
+	}
+	if rules.Race.Source == models.RulesSourceDefault {
+		errs = append(errs, fieldError("race", "not_found", "race %q was not found in the SRD or in your homebrew", pc.Race))
+	}
+	if rules.Class.Source == models.RulesSourceDefault {
+		errs = append(errs, fieldError("class", "not_found", "class %q was not found in the SRD or in your homebrew", pc.Class))
+	}
+	if pc.Background != "" && rules.Background == nil {
+		errs = append(errs, fieldError("background", "not_found", "background %q was not found in the SRD or in your homebrew", pc.Background))
+	}
+
+	attributes, _ := pc.Attributes.Data.(map[string]any)
+	for _, ability := range models.AbilityNames {
+		score, ok := attributes[ability].(int)
+		switch {
+		case !ok:
+			errs = append(errs, fieldError("attributes."+ability, "required", "%s is required", ability))
+		case score < models.MinGeneratedAbilityScore || score > models.MaxAbilityScore:
+			errs = append(errs, fieldError("attributes."+ability, "invalid_range", "%s must be between %d and %d, got %d",
+				ability, models.MinGeneratedAbilityScore, models.MaxAbilityScore, score))
+		}
+	}
+
+	if errs.HasErrors() {
+		log.Printf("PC generation service returned an invalid character: %v", errs)
+		h.Response.SendJSON(w, utils.ErrorResponse{
+			Error:  "PC generation service returned an invalid character",
+			Code:   "invalid_generated_pc",
+			Fields: errs,
+		}, http.StatusBadGateway)
+		return false
+	}
+	return true
+}
+
+// generateFallbackPC monta a ficha em Go: sorteia raça, classe e antecedente não informados,
+// gera os atributos pelo método pedido e escolhe perícias, equipamento inicial e magias do
+// SRD. Em caso de erro, a resposta já foi enviada e ok é false.
+func (h *PCHandler) generateFallbackPC(w http.ResponseWriter, r *http.Request, request models.GeneratePCRequest, userID int) (*models.PC, bool) {
+	dice := h.dice()
+	draft := models.CharacterDraft{
+		Level:         request.Level,
+		PlayerName:    request.PlayerName,
+		AbilityMethod: models.GenerationAbilityMethods[request.AttributesMethod],
+	}
+	if request.Manual {
+		draft.Race, draft.Class, draft.Background = request.Race, request.Class, request.Background
+	}
+
+	for _, choice := range []struct {
+		value   *string
+		options []string
+	}{
+		{&draft.Race, models.GenerationRaces},
+		{&draft.Class, models.GenerationClasses},
+		{&draft.Background, models.GenerationBackgrounds},
+	} {
+		if *choice.value != "" {
+			continue
+		}
+		picked, err := pickRandom(dice, choice.options, 1)
+		if err != nil {
+			h.Response.SendInternalError(w, "Failed to generate PC")
+			return nil, false
+		}
+		*choice.value = picked[0]
+	}
+
+	values, err := generatedAbilityValues(dice, draft.AbilityMethod)
+	if err != nil {
+		h.Response.SendInternalError(w, "Failed to roll ability scores")
+		return nil, false
+	}
+	draft.AbilityScores = models.AssignAbilityScores(draft.Class, values)
+
+	rules, err := h.DB.LoadBuilderRules(r.Context(), &draft, userID)
+	if err != nil {
+		h.Response.HandleDBError(w, err, "load character rules")
+		return nil, false
+	}
+
+	errs := utils.ValidationErrors{}
+	if rules.Race.Source == models.RulesSourceDefault {
+		errs = append(errs, fieldError("race", "not_found", "race %q was not found in the SRD or in your homebrew", draft.Race))
+	}
+	if rules.Class.Source == models.RulesSourceDefault {
+		errs = append(errs, fieldError("class", "not_found", "class %q was not found in the SRD or in your homebrew", draft.Class))
+	}
+	if errs.HasErrors() {
+		h.Response.SendValidationErrors(w, errs)
+		return nil, false
+	}
+
+	// Perícias da classe que a raça e o antecedente ainda não concedem
+	granted := slices.Clone(rules.Race.Skills)
+	if rules.Background != nil {
+		granted = append(granted, rules.Background.Skills...)
+	}
+	options := slices.DeleteFunc(slices.Clone(rules.Class.SkillOptions), func(skill string) bool {
+		return slices.Contains(granted, skill)
+	})
+	if draft.SkillChoices, err = pickRandom(dice, options, rules.Class.SkillChoiceCount); err != nil {
+		h.Response.SendInternalError(w, "Failed to generate PC")
+		return nil, false
+	}
+
+	background := draft.Background
+	if rules.Background != nil {
+		background = rules.Background.Name
+	}
+	draft.Name = rules.Race.Name + " " + rules.Class.Name
+	draft.Description = fmt.Sprintf("A level %d %s %s with a %s background.", draft.Level, rules.Race.Name, rules.Class.Name, background)
+
+	pc := models.NewCharacterBuild(draft, *rules).ToPC(draft)
+	pc.Equipment = models.JSONBFlexible{Data: models.StartingEquipment(cmp.Or(rules.Class.Index, draft.Class))}
+
+	slots, pact := models.ClassSpellSlots(rules.Class, pc.ClassLevels[0])
+	known, err := h.generatedSpells(r, dice, rules.Class, draft.Level, slots, pact)
+	if err != nil {
+		h.Response.HandleDBError(w, err, "load class spells")
+		return nil, false
+	}
+	models.ApplyGeneratedSpells(&pc, slots, pact, known)
+
+	sheet, ok := h.computeSheet(w, r, &pc, userID)
+	if !ok {
+		return nil, false
+	}
+	sheet.ApplyTo(&pc)
+
+	return &pc, true
+}
+
+// generatedAbilityValues gera os seis valores de atributo: 4d6 descartando o menor, o
+// conjunto padrão ou a distribuição fixa da compra de pontos
+func generatedAbilityValues(dice DiceSource, method string) ([]int, error) {
+	switch method {
+	case models.AbilityMethodStandardArray:
+		return slices.Clone(models.StandardArray), nil
+	case models.AbilityMethodPointBuy:
+		return slices.Clone(models.GenerationPointBuy), nil
+	}
+
+	rolled := make([]int64, 0, models.AbilityRollScores*models.AbilityRollDice)
+	for i := 0; i < models.AbilityRollScores; i++ {
+		rolls, _, err := rollDice(dice, models.AbilityRollDice, 6, 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, roll := range rolls {
+			rolled = append(rolled, int64(roll))
+		}
+	}
+
+	values := []int{}
+	for _, score := range models.ScoresFromDice(rolled) {
+		values = append(values, int(score))
+	}
+	return values, nil
+}
+
+// generatedSpells sorteia truques e magias da lista da classe no SRD, até o maior círculo
+// com espaços. Classes homebrew ou sem conjuração não recebem magias.
+func (h *PCHandler) generatedSpells(r *http.Request, dice DiceSource, class models.ClassRules, level int, slots map[string]int, pact *models.PactMagic) ([]models.DnDSpell, error) {
+	maxCircle := 0
+	for circle := range slots {
+		if n, _ := strconv.Atoi(circle); n > maxCircle {
+			maxCircle = n
+		}
+	}
+	if pact != nil {
+		maxCircle = max(maxCircle, pact.SlotLevel)
+	}
+	if maxCircle == 0 || class.Index == "" {
+		return nil, nil
+	}
+
+	spells, err := h.DB.GetDnDSpells(r.Context(), 1000, 0, nil, "", class.Index)
+	if err != nil {
+		return nil, err
+	}
+
+	var cantrips, leveled []models.DnDSpell
+	for _, spell := range spells {
+		switch {
+		case spell.Level == 0:
+			cantrips = append(cantrips, spell)
+		case spell.Level <= maxCircle:
+			leveled = append(leveled, spell)
+		}
+	}
+
+	cantripCount, spellCount := models.GeneratedSpellCounts(level)
+	known, err := pickRandom(dice, cantrips, cantripCount)
+	if err != nil {
+		return nil, err
+	}
+	picked, err := pickRandom(dice, leveled, spellCount)
+	if err != nil {
+		return nil, err
+	}
+	known = append(known, picked...)
+
+	slices.SortFunc(known, func(a, b models.DnDSpell) int {
+		return cmp.Or(cmp.Compare(a.Level, b.Level), cmp.Compare(a.Name, b.Name))
+	})
+	return known, nil
+}
+
+// pickRandom sorteia até n opções distintas com a fonte de dados
+func pickRandom[T any](dice DiceSource, options []T, n int) ([]T, error) {
+	pool := slices.Clone(options)
+	picked := make([]T, 0, n)
+	for len(picked) < n && len(pool) > 0 {
+		roll, err := dice.Roll(len(pool))
+		if err != nil {
+			return nil, err
+		}
+		picked = append(picked, pool[roll-1])
+		pool = slices.Delete(pool, roll-1, roll)
+	}
+	return picked, nil
+}
@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+
+	"rpg-saas-backend/internal/models"
+	"rpg-saas-backend/internal/python"
+)
+
+// stubPCGenerator sobe um serviço de geração que responde com o status e o corpo informados
+func stubPCGenerator(t *testing.T, status int, body string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/generate-pc" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func decodeGeneratedPC(t *testing.T, rr *httptest.ResponseRecorder) models.GeneratedPC {
+	t.Helper()
+	var resp struct {
+		Data models.GeneratedPC `json:"data"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	return resp.Data
+}
+
+// expectGeneratedWizardRules espera a validação de um mago elfo sábio do SRD
+func expectGeneratedWizardRules(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(`FROM dnd_races`).WithArgs("Elf").
+		WillReturnRows(sqlmock.NewRows([]string{"api_index", "name", "speed", "ability_bonuses", "proficiencies"}).
+			AddRow("elf", "Elf", 30, []byte(`[{"ability_score":{"index":"dex"},"bonus":2}]`), []byte(`[]`)))
+	mock.ExpectQuery(`FROM dnd_classes`).WithArgs("Wizard").
+		WillReturnRows(sqlmock.NewRows([]string{"api_index", "name", "hit_die", "saving_throws", "proficiency_choices", "spellcasting", "spellcasting_ability"}).
+			AddRow("wizard", "Wizard", 6, pq.StringArray{"int", "wis"}, []byte(`[]`), []byte(`{}`), "int"))
+	mock.ExpectQuery(`FROM dnd_backgrounds`).WithArgs("Sage").
+		WillReturnRows(sqlmock.NewRows([]string{"api_index", "name", "starting_proficiencies"}).
+			AddRow("sage", "Sage", []byte(`[]`)))
+}
+
+func TestPCHandler_GenerateRandomPC_Service(t *testing.T) {
+	handler, mock, cleanup := newMockPCHandler(t)
+	defer cleanup()
+
+	server := stubPCGenerator(t, http.StatusOK, `{
+		"name":"Elf Wizard","level":3,"race":"Elf","class":"Wizard","background":"Sage",
+		"attributes":{"strength":8,"dexterity":14,"constitution":14,"intelligence":16,"wisdom":12,"charisma":10},"abilities":["Arcane Recovery"],
+		"equipment":["Quarterstaff"],"hp":14,"ac":12,"spells":{"level_0":["Light"]}
+	}`)
+	handler.Python = &python.Client{BaseURL: server.URL, HTTPClient: server.Client()}
+
+	expectGeneratedWizardRules(mock)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO pcs`).WithArgs(
+		"Elf Wizard", "", 3, "Elf", "Wizard", "Sage", "", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+		14, 12, 2, "Ana", 7, false, false, sqlmock.AnyArg(), sqlmock.AnyArg(),
+		sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+	).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(30))
+	expectPCVersionRecorded(mock, 30)
+	mock.ExpectCommit()
+
+	rr := httptest.NewRecorder()
+	handler.GenerateRandomPC(rr, builderRequest(t, "/api/pcs/generate", map[string]any{"level": 3, "player_name": "Ana"}))
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	generated := decodeGeneratedPC(t, rr)
+	if generated.Generator != models.PCGeneratorService || generated.PC.ID != 30 || generated.PC.PlayerID != 7 {
+		t.Fatalf("unexpected generated PC: %+v", generated)
+	}
+	// Espaços de magia do mago nível 3 completados pelo backend
+	slots := generated.PC.Spells.Data.(map[string]any)["spell_slots"].(map[string]any)
+	if second, _ := slots["2"].(map[string]any); second["total"] != float64(2) {
+		t.Fatalf("unexpected spell slots: %v", slots)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestPCHandler_GenerateRandomPC_Fallback(t *testing.T) {
+	handler, mock, cleanup := newMockPCHandler(t)
+	defer cleanup()
+
+	server := stubPCGenerator(t, http.StatusServiceUnavailable, `{"error":"unavailable"}`)
+	handler.Python = &python.Client{BaseURL: server.URL, HTTPClient: server.Client()}
+	// Duas perícias do guerreiro entre percepção e sobrevivência (atletismo vem do antecedente)
+	handler.Dice = NewScriptedDiceSource(1, 1)
+
+	expectFighterRules(mock)
+	mock.ExpectQuery(`FROM dnd_backgrounds`).WithArgs("soldier").
+		WillReturnRows(sqlmock.NewRows([]string{"api_index", "name", "starting_proficiencies"}).
+			AddRow("soldier", "Soldier", []byte(`[{"index":"skill-athletics"},{"index":"skill-intimidation"}]`)))
+	expectFighterRules(mock)
+	mock.ExpectQuery(`FROM dnd_equipment`).
+		WillReturnRows(sqlmock.NewRows([]string{"api_index", "name", "armor_category", "armor_class"}).
+			AddRow("chain-mail", "Chain Mail", "Heavy", []byte(`{"base":16,"dex_bonus":false}`)).
+			AddRow("shield", "Shield", "Shield", []byte(`{"base":2,"dex_bonus":false}`)))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO pcs`).WithArgs(
+		"Dwarf Fighter", sqlmock.AnyArg(), 1, "dwarf", "fighter", "soldier", "", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+		13, 18, 2, "Ana", 7, false, false, sqlmock.AnyArg(), sqlmock.AnyArg(),
+		sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+	).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(31))
+	expectPCVersionRecorded(mock, 31)
+	mock.ExpectCommit()
+
+	rr := httptest.NewRecorder()
+	handler.GenerateRandomPC(rr, builderRequest(t, "/api/pcs/generate", map[string]any{
+		"level":             1,
+		"attributes_method": "array",
+		"manual":            true,
+		"race":              "dwarf",
+		"class":             "fighter",
+		"background":        "soldier",
+		"player_name":       "Ana",
+	}))
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	generated := decodeGeneratedPC(t, rr)
+	pc := generated.PC
+	if generated.Generator != models.PCGeneratorFallback || pc.ID != 31 {
+		t.Fatalf("unexpected generated PC: %+v", generated)
+	}
+	// Conjunto padrão com Força e Constituição primeiro, +2 de Constituição do anão
+	if scores := pc.AttributeScores(); scores["strength"] != 15 || scores["constitution"] != 16 {
+		t.Fatalf("unexpected ability scores: %v", scores)
+	}
+	skills := pc.Skills.Data.(map[string]any)
+	if len(skills) != 4 {
+		t.Fatalf("expected background and class skills, got %v", skills)
+	}
+	if names := pc.EquippedItemNames(); len(names) != 3 || names[0] != "Chain Mail" {
+		t.Fatalf("unexpected equipped items: %v", names)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestPCHandler_GenerateRandomPC_InvalidMethod(t *testing.T) {
+	handler, _, cleanup := newMockPCHandler(t)
+	defer cleanup()
+
+	rr := httptest.NewRecorder()
+	handler.GenerateRandomPC(rr, builderRequest(t, "/api/pcs/generate", map[string]any{"level": 1, "attributes_method": "dados"}))
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestPCHandler_GenerateRandomPC_InvalidServiceOutput(t *testing.T) {
+	handler, mock, cleanup := newMockPCHandler(t)
+	defer cleanup()
+
+	server := stubPCGenerator(t, http.StatusOK, `{
+		"name":"Elf Wizard","level":3,"race":"Elf","class":"Wizard","background":"Sage",
+		"attributes":{"strength":8,"dexterity":14,"constitution":14,"intelligence":25,"wisdom":12},
+		"hp":14,"ac":12
+	}`)
+	handler.Python = &python.Client{BaseURL: server.URL, HTTPClient: server.Client()}
+	expectGeneratedWizardRules(mock)
+
+	rr := httptest.NewRecorder()
+	handler.GenerateRandomPC(rr, builderRequest(t, "/api/pcs/generate", map[string]any{"level": 3}))
+	if rr.Code != http.StatusBadGateway {
+		t.Fatalf("expected 502, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var resp struct {
+		Fields []struct {
+			Field string `json:"field"`
+		} `json:"fields"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	if len(resp.Fields) != 2 || resp.Fields[0].Field != "attributes.intelligence" || resp.Fields[1].Field != "attributes.charisma" {
+		t.Fatalf("expected the out-of-range and missing abilities, got %s", rr.Body.String())
+	}
+	// Nada é gravado
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestPCHandler_GenerateRandomPC_ClientErrorSkipsFallback(t *testing.T) {
+	handler, mock, cleanup := newMockPCHandler(t)
+	defer cleanup()
+
+	server := stubPCGenerator(t, http.StatusBadRequest, `{"error":"invalid level"}`)
+	handler.Python = &python.Client{BaseURL: server.URL, HTTPClient: server.Client()}
+
+	rr := httptest.NewRecorder()
+	handler.GenerateRandomPC(rr, builderRequest(t, "/api/pcs/generate", map[string]any{"level": 3}))
+	if rr.Code != http.StatusBadGateway {
+		t.Fatalf("expected 502 without fallback, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	"rpg-saas-backend/internal/models"
+	"rpg-saas-backend/internal/utils"
+)
+
+// GetLevelUpPlan mostra o que o PC ganha no próximo nível e as escolhas que precisa fazer
+// (aumento de atributo, subclasse, perícias de multiclasse) antes de POST /level-up. ?class=
+// escolhe a classe (obrigatório para multiclasse, ou para prever a entrada em uma nova).
+func (h *PCHandler) GetLevelUpPlan(w http.ResponseWriter, r *http.Request) {
+	pc, rules, _, ok := h.loadLevelUp(w, r, r.URL.Query().Get("class"))
+	if !ok {
+		return
+	}
+
+	h.Response.SendJSON(w, models.NewLevelUpPlan(pc, *rules, ""), http.StatusOK)
+}
+
+// LevelUpPC sobe o PC um nível: PV pelo dado de vida (rolado ou média), bônus de
+// proficiência, características e espaços de magia pela progressão da classe e as escolhas
+// do jogador. Escolhas pendentes ou inválidas são reportadas por campo.
+func (h *PCHandler) LevelUpPC(w http.ResponseWriter, r *http.Request) {
+	// Corpo vazio sobe de nível com PV pela média, se o nível não exigir escolhas
+	var req models.LevelUpRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.Response.SendBadRequest(w, "Invalid request body: "+err.Error())
+		return
+	}
+	if req.HPMethod == "" {
+		req.HPMethod = models.HPMethodAverage
+	}
+
+	pc, rules, userID, ok := h.loadLevelUp(w, r, req.Class)
+	if !ok {
+		return
+	}
+
+	plan := models.NewLevelUpPlan(pc, *rules, strings.TrimSpace(req.Subclass))
+	if errs := validateLevelUp(pc, plan, req); errs.HasErrors() {
+		h.Response.SendValidationErrors(w, errs)
+		return
+	}
+
+	result := models.LevelUpResult{Plan: plan, HPMethod: req.HPMethod}
+	dieResult := plan.AverageHP
+	if req.HPMethod == models.HPMethodRoll {
+		_, total, err := rollDice(h.dice(), 1, plan.HitDie, 0)
+		if err != nil {
+			h.Response.SendInternalError(w, "Failed to roll hit points")
+			return
+		}
+		dieResult = total
+		result.HPRoll = &total
+	}
+
+	oldCon := pc.AttributeScores()["constitution"]
+	newCon := oldCon + req.ASI["constitution"]
+	result.HPGained = models.LevelHitPoints(dieResult, plan.FromLevel, oldCon, newCon)
+
+	models.ApplyLevelUp(pc, plan, req, result.HPGained)
+	if err := h.DB.UpdatePC(r.Context(), pc, userID, models.VersionActionLevelUp); err != nil {
+		h.Response.HandleDBError(w, err, "level up PC")
+		return
+	}
+
+	result.PC = *pc
+	h.Response.SendJSON(w, result, http.StatusOK)
+}
+
+// loadLevelUp carrega o PC e a progressão do próximo nível na classe informada (por padrão, a
+// única classe do PC) e confere os pré-requisitos de multiclasse ao entrar em uma classe nova.
+// Em caso de erro, a resposta já foi enviada e ok é false.
+func (h *PCHandler) loadLevelUp(w http.ResponseWriter, r *http.Request, class string) (*models.PC, *models.LevelUpRules, int, bool) {
+	userID, err := utils.ExtractUserID(r)
+	if err != nil {
+		h.Response.SendInternalError(w, "User ID not found in context")
+		return nil, nil, 0, false
+	}
+
+	id, err := utils.ExtractID(r)
+	if err != nil {
+		h.Response.SendBadRequest(w, err.Error())
+		return nil, nil, 0, false
+	}
+
+	pc, err := h.DB.GetPCByIDAndPlayer(r.Context(), id, userID)
+	if err != nil {
+		h.Response.SendNotFound(w, "PC not found")
+		return nil, nil, 0, false
+	}
+
+	if pc.Level >= models.MaxCharacterLevel {
+		h.Response.SendConflict(w, "PC is already at the maximum level")
+		return nil, nil, 0, false
+	}
+
+	classes := pc.Classes()
+	class = strings.TrimSpace(class)
+	if class == "" {
+		switch len(classes) {
+		case 0:
+			class = pc.Class
+		case 1:
+			class = classes[0].Class
+		default:
+			names := make([]string, len(classes))
+			for i, entry := range classes {
+				names[i] = entry.Class
+			}
+			h.Response.SendValidationErrors(w, utils.ValidationErrors{
+				fieldError("class", "choice_required", "choose which class gains the level: %s", strings.Join(names, ", ")),
+			})
+			return nil, nil, 0, false
+		}
+	}
+
+	rules, err := h.DB.LoadLevelUpRules(r.Context(), pc, class, userID)
+	if err != nil {
+		h.Response.HandleDBError(w, err, "load class progression")
+		return nil, nil, 0, false
+	}
+	if rules == nil {
+		h.Response.SendValidationErrors(w, utils.ValidationErrors{
+			fieldError("class", "not_found", "class %q was not found in the SRD or in your homebrew", class),
+		})
+		return nil, nil, 0, false
+	}
+
+	if len(classes) > 0 && classes.Find(rules.Class) == nil {
+		if unmet := models.UnmetMulticlassPrerequisites(classes, rules.Class, pc.AttributeScores()); len(unmet) > 0 {
+			h.Response.SendValidationErrors(w, utils.ValidationErrors{
+				fieldError("class", "prerequisites_not_met", "cannot multiclass into %s: %s", rules.Class, strings.Join(unmet, "; ")),
+			})
+			return nil, nil, 0, false
+		}
+	}
+
+	return pc, rules, userID, true
+}
+
+// validateLevelUp confere o método de PV e as escolhas do pedido contra as exigidas pelo nível
+func validateLevelUp(pc *models.PC, plan models.LevelUpPlan, req models.LevelUpRequest) utils.ValidationErrors {
+	errs := utils.ValidationErrors{}
+
+	if !slices.Contains(models.HPMethods, req.HPMethod) {
+		errs = append(errs, fieldError("hp_method", "invalid_choice", "hp_method must be one of: %s", strings.Join(models.HPMethods, ", ")))
+	}
+
+	subclass := strings.TrimSpace(req.Subclass)
+	if choice := plan.Choice(models.LevelUpChoiceSubclass); choice != nil {
+		switch {
+		case subclass == "":
+			errs = append(errs, fieldError("subclass", "choice_required", "choose a %s subclass: %s", plan.Class, strings.Join(choice.Options, ", ")))
+		case !slices.Contains(choice.Options, plan.Subclass):
+			errs = append(errs, fieldError("subclass", "invalid_choice", "%q is not a %s subclass", subclass, plan.Class))
+		}
+	} else if current := plan.Classes.Find(plan.Class); subclass != "" && !strings.EqualFold(subclass, current.Subclass) {
+		errs = append(errs, fieldError("subclass", "not_allowed", "no subclass choice at %s level %d", plan.Class, plan.ClassLevel))
+	}
+
+	if choice := plan.Choice(models.LevelUpChoiceSkills); choice != nil {
+		switch {
+		case len(req.Skills) != choice.Points:
+			errs = append(errs, fieldError("skills", "choice_required", "choose %d skill(s): %s", choice.Points, strings.Join(choice.Options, ", ")))
+		default:
+			for _, name := range req.Skills {
+				if skill := models.FindSkill(name); skill == nil || !slices.Contains(choice.Options, skill.Index) {
+					errs = append(errs, fieldError("skills", "invalid_choice", "%q is not one of the skill options", name))
+				}
+			}
+		}
+	} else if len(req.Skills) > 0 {
+		errs = append(errs, fieldError("skills", "not_allowed", "no skill choice at %s level %d", plan.Class, plan.ClassLevel))
+	}
+
+	if plan.Choice(models.LevelUpChoiceASI) == nil {
+		if len(req.ASI) > 0 || req.Feat != "" {
+			errs = append(errs, fieldError("asi", "not_allowed", "level %d %s does not grant an ability score improvement", plan.ToLevel, plan.Class))
+		}
+		return errs
+	}
+
+	switch {
+	case len(req.ASI) > 0 && req.Feat != "":
+		errs = append(errs, fieldError("asi", "conflicting_choice", "choose either an ability score improvement or a feat, not both"))
+	case req.Feat != "":
+		if slices.Contains(pc.Features, req.Feat) {
+			errs = append(errs, fieldError("feat", "duplicate", "%s was already taken", req.Feat))
+		}
+	case len(req.ASI) == 0:
+		errs = append(errs, fieldError("asi", "choice_required", "choose an ability score improvement (%d points) or a feat", models.ASIPoints))
+	default:
+		scores := pc.AttributeScores()
+		points := 0
+		for _, ability := range slices.Sorted(maps.Keys(req.ASI)) {
+			increase := req.ASI[ability]
+			field := "asi." + ability
+			switch {
+			case !slices.Contains(models.AbilityNames, ability):
+				errs = append(errs, fieldError(field, "unknown_ability", "%s is not an ability score", ability))
+			case increase < 1:
+				errs = append(errs, fieldError(field, "invalid_range", "increase must be at least 1"))
+			case scores[ability]+increase > models.MaxAbilityScore:
+				errs = append(errs, fieldError(field, "invalid_range", "%s cannot go above %d", ability, models.MaxAbilityScore))
+			}
+			points += increase
+		}
+		if points != models.ASIPoints {
+			errs = append(errs, fieldError("asi", "invalid_points", "an ability score improvement is worth exactly %d points, got %d", models.ASIPoints, points))
+		}
+	}
+
+	return errs
+}
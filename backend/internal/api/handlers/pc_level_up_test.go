@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+
+	"rpg-saas-backend/internal/api/middleware"
+	"rpg-saas-backend/internal/models"
+	"rpg-saas-backend/internal/utils"
+)
+
+// expectWizardLevelUp espera a busca de um mago nível 3 e da progressão do nível 4 no SRD
+func expectWizardLevelUp(mock sqlmock.Sqlmock) {
+	expectWizardPC(mock)
+	mock.ExpectQuery(`FROM dnd_classes`).WithArgs("wizard").
+		WillReturnRows(sqlmock.NewRows([]string{"api_index", "name", "hit_die", "class_levels"}).
+			AddRow("wizard", "Wizard", 6, []byte(`[
+				{"level":3,"prof_bonus":2,"ability_score_bonuses":0,"spellcasting":{"spell_slots_level_1":4,"spell_slots_level_2":2}},
+				{"level":4,"prof_bonus":2,"ability_score_bonuses":1,"spellcasting":{"spell_slots_level_1":4,"spell_slots_level_2":3}}
+			]`)))
+	mock.ExpectQuery(`FROM dnd_features`).WithArgs("Wizard", 4).
+		WillReturnRows(sqlmock.NewRows([]string{"api_index", "name", "level", "subclass_name", "description"}).
+			AddRow("wizard-ability-score-improvement-1", "Ability Score Improvement", 4, "", ""))
+	mock.ExpectQuery(`GROUP BY subclass_name`).WithArgs("Wizard").
+		WillReturnRows(sqlmock.NewRows([]string{"subclass_name", "level"}).AddRow("Evocation", 2))
+}
+
+// expectWizardPC espera a busca do mago nível 3 (Int 16, Des 14, Con 14) com a subclasse em
+// abilities, como os PCs gravados antes de class_levels
+func expectWizardPC(mock sqlmock.Sqlmock) {
+	pcCols := []string{
+		"id", "name", "description", "level", "race", "class", "background", "alignment",
+		"attributes", "abilities", "equipment", "hp", "current_hp", "ca", "proficiency_bonus",
+		"inspiration", "skills", "attacks", "spells", "personality_traits", "ideals", "bonds",
+		"flaws", "features", "player_name", "player_id", "is_homebrew", "is_unique", "created_at",
+	}
+	mock.ExpectQuery(`FROM pcs`).WithArgs(1, 7).WillReturnRows(sqlmock.NewRows(pcCols).AddRow(
+		1, "Elminster", "", 3, "human", "wizard", "sage", "",
+		[]byte(`{"strength":8,"dexterity":14,"constitution":14,"intelligence":16,"wisdom":12,"charisma":10}`),
+		[]byte(`{"subclass":"Evocation"}`), []byte(`[]`), 17, 17, 12, 2, false,
+		[]byte(`{}`), []byte(`[]`), []byte(`{"spell_slots":{"1":{"total":4,"used":1}},"known_spells":[]}`),
+		"", "", "", "", pq.StringArray{"Arcane Recovery"}, "Player", 7, false, false, time.Now(),
+	))
+}
+
+// expectRogueMulticlass espera a entrada do mago nível 3 de expectWizardPC no ladino:
+// progressão do ladino nível 1 e as regras do mago para os espaços de magia combinados
+func expectRogueMulticlass(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(`FROM dnd_classes`).WithArgs("Rogue").
+		WillReturnRows(sqlmock.NewRows([]string{"api_index", "name", "hit_die", "class_levels", "proficiency_choices"}).
+			AddRow("rogue", "Rogue", 8,
+				[]byte(`[{"level":1,"prof_bonus":2,"ability_score_bonuses":0}]`),
+				[]byte(`[{"choose":4,"from":{"options":[{"item":{"index":"skill-stealth"}},{"item":{"index":"skill-acrobatics"}}]}}]`)))
+	mock.ExpectQuery(`FROM dnd_features`).WithArgs("Rogue", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"api_index", "name", "level", "subclass_name", "description"}).
+			AddRow("rogue-sneak-attack", "Sneak Attack", 1, "", ""))
+	mock.ExpectQuery(`GROUP BY subclass_name`).WithArgs("Rogue").
+		WillReturnRows(sqlmock.NewRows([]string{"subclass_name", "level"}).AddRow("Thief", 3))
+	mock.ExpectQuery(`FROM dnd_classes`).WithArgs("wizard").
+		WillReturnRows(sqlmock.NewRows([]string{"api_index", "name", "hit_die"}).AddRow("wizard", "Wizard", 6))
+}
+
+func levelUpRequest(method, body string) *http.Request {
+	req := httptest.NewRequest(method, "/api/pcs/1/level-up", bytes.NewBufferString(body))
+	req = addChiParam(req, "id", "1")
+	return req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, 7))
+}
+
+func TestPCHandler_GetLevelUpPlan(t *testing.T) {
+	handler, mock, cleanup := newMockPCHandler(t)
+	defer cleanup()
+
+	expectWizardLevelUp(mock)
+
+	rr := httptest.NewRecorder()
+	handler.GetLevelUpPlan(rr, levelUpRequest(http.MethodGet, ""))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var plan models.LevelUpPlan
+	if err := json.Unmarshal(rr.Body.Bytes(), &plan); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	if plan.ToLevel != 4 || plan.AverageHP != 4 || plan.SpellSlots["2"] != 3 {
+		t.Fatalf("unexpected plan: %+v", plan)
+	}
+	if len(plan.Choices) != 1 || plan.Choices[0].Type != models.LevelUpChoiceASI {
+		t.Fatalf("expected only the ASI choice, got %+v", plan.Choices)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestPCHandler_LevelUpPC_ChoiceRequired(t *testing.T) {
+	handler, mock, cleanup := newMockPCHandler(t)
+	defer cleanup()
+
+	expectWizardLevelUp(mock)
+
+	rr := httptest.NewRecorder()
+	handler.LevelUpPC(rr, levelUpRequest(http.MethodPost, `{"hp_method":"roll"}`))
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var resp struct {
+		Fields utils.ValidationErrors `json:"fields"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	if len(resp.Fields) != 1 || resp.Fields[0].Field != "asi" || resp.Fields[0].Code != "choice_required" {
+		t.Fatalf("unexpected field errors: %+v", resp.Fields)
+	}
+}
+
+func TestPCHandler_LevelUpPC(t *testing.T) {
+	handler, mock, cleanup := newMockPCHandler(t)
+	defer cleanup()
+
+	handler.Dice = NewScriptedDiceSource(5)
+	expectWizardLevelUp(mock)
+	mock.ExpectBegin()
+	expectVersionHistory(mock, models.CharacterTypePC, 1)
+	mock.ExpectExec(`UPDATE pcs SET`).WillReturnResult(sqlmock.NewResult(0, 1))
+	expectPCVersionRecorded(mock, 1)
+	mock.ExpectCommit()
+
+	rr := httptest.NewRecorder()
+	handler.LevelUpPC(rr, levelUpRequest(http.MethodPost, `{"hp_method":"roll","asi":{"constitution":2}}`))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var result models.LevelUpResult
+	if err := json.Unmarshal(rr.Body.Bytes(), &result); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	// 5 no d6 + Con 16 (+3), e +1 retroativo nos 3 níveis anteriores
+	if result.HPRoll == nil || *result.HPRoll != 5 || result.HPGained != 11 {
+		t.Fatalf("unexpected HP: roll %v, gained %d", result.HPRoll, result.HPGained)
+	}
+	if result.PC.Level != 4 || result.PC.HP != 28 || result.PC.AttributeScores()["constitution"] != 16 {
+		t.Fatalf("unexpected PC: %+v", result.PC)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestPCHandler_LevelUpPC_Multiclass(t *testing.T) {
+	handler, mock, cleanup := newMockPCHandler(t)
+	defer cleanup()
+
+	expectWizardPC(mock)
+	expectRogueMulticlass(mock)
+	mock.ExpectBegin()
+	expectVersionHistory(mock, models.CharacterTypePC, 1)
+	mock.ExpectExec(`UPDATE pcs SET`).WillReturnResult(sqlmock.NewResult(0, 1))
+	expectPCVersionRecorded(mock, 1)
+	mock.ExpectCommit()
+
+	rr := httptest.NewRecorder()
+	handler.LevelUpPC(rr, levelUpRequest(http.MethodPost, `{"class":"Rogue","skills":["stealth"]}`))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var result models.LevelUpResult
+	if err := json.Unmarshal(rr.Body.Bytes(), &result); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	pc := result.PC
+	if pc.Class != "wizard 3 / Rogue 1" || pc.Level != 4 || len(pc.ClassLevels) != 2 {
+		t.Fatalf("unexpected classes: %q level %d %+v", pc.Class, pc.Level, pc.ClassLevels)
+	}
+	// d8 médio 5 + Con 14 (+2)
+	if result.HPGained != 7 || pc.HP != 24 {
+		t.Fatalf("unexpected HP: gained %d, total %d", result.HPGained, pc.HP)
+	}
+	if !result.Plan.NewClass || !slices.Contains(result.Plan.Proficiencies, "Thieves' tools") {
+		t.Fatalf("expected rogue multiclass proficiencies, got %+v", result.Plan)
+	}
+	// Mago 3 + ladino sem Trapaceiro Arcano = conjurador de nível 3
+	if result.Plan.SpellSlots["1"] != 4 || result.Plan.SpellSlots["2"] != 2 {
+		t.Fatalf("unexpected combined spell slots: %v", result.Plan.SpellSlots)
+	}
+	skills := pc.Skills.Data.(map[string]any)
+	if stealth, _ := skills[models.FindSkill("stealth").Label].(map[string]any); stealth["proficient"] != true {
+		t.Fatalf("stealth proficiency not applied: %v", skills)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestPCHandler_LevelUpPC_MulticlassRequiresClass(t *testing.T) {
+	handler, mock, cleanup := newMockPCHandler(t)
+	defer cleanup()
+
+	mock.ExpectQuery(`FROM pcs`).WithArgs(1, 7).WillReturnRows(sqlmock.NewRows([]string{"id", "level", "class", "class_levels"}).
+		AddRow(1, 5, "Fighter 3 / Wizard 2", []byte(`[{"class":"Fighter","level":3},{"class":"Wizard","level":2}]`)))
+
+	rr := httptest.NewRecorder()
+	handler.LevelUpPC(rr, levelUpRequest(http.MethodPost, `{}`))
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var resp struct {
+		Fields utils.ValidationErrors `json:"fields"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	if len(resp.Fields) != 1 || resp.Fields[0].Field != "class" || resp.Fields[0].Code != "choice_required" {
+		t.Fatalf("unexpected field errors: %+v", resp.Fields)
+	}
+}
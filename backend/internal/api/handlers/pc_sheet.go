@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"rpg-saas-backend/internal/models"
+	"rpg-saas-backend/internal/utils"
+)
+
+// GetComputedSheet calcula a ficha do PC a partir das regras: modificadores, testes de
+// resistência, perícias, percepção passiva, CA, PV máximos, iniciativa e conjuração
+func (h *PCHandler) GetComputedSheet(w http.ResponseWriter, r *http.Request) {
+	userID, err := utils.ExtractUserID(r)
+	if err != nil {
+		h.Response.SendInternalError(w, "User ID not found in context")
+		return
+	}
+
+	id, err := utils.ExtractID(r)
+	if err != nil {
+		h.Response.SendBadRequest(w, err.Error())
+		return
+	}
+
+	pc, err := h.DB.GetPCByIDAndPlayer(r.Context(), id, userID)
+	if err != nil {
+		h.Response.SendNotFound(w, "PC not found")
+		return
+	}
+
+	sheet, ok := h.computeSheet(w, r, pc, userID)
+	if !ok {
+		return
+	}
+
+	h.Response.SendJSON(w, sheet, http.StatusOK)
+}
+
+// computeSheet resolve as regras do PC e calcula a ficha. Em caso de erro, a resposta já foi
+// enviada e ok é false.
+func (h *PCHandler) computeSheet(w http.ResponseWriter, r *http.Request, pc *models.PC, userID int) (*models.ComputedSheet, bool) {
+	rules, err := h.DB.LoadCharacterRules(r.Context(), pc, userID)
+	if err != nil {
+		h.Response.HandleDBError(w, err, "load character rules")
+		return nil, false
+	}
+
+	sheet := models.ComputeSheet(pc, *rules)
+	return &sheet, true
+}
+
+// autoFillRequested indica se a criação ou edição deve preencher PV, CA e bônus de
+// proficiência pelas regras (?auto_fill=true)
+func autoFillRequested(r *http.Request) bool {
+	autoFill, _ := strconv.ParseBool(r.URL.Query().Get("auto_fill"))
+	return autoFill
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+
+	"rpg-saas-backend/internal/api/middleware"
+	"rpg-saas-backend/internal/models"
+)
+
+// expectClericRules espera a resolução das regras de um clérigo anão do SRD sem armadura
+func expectClericRules(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(`FROM dnd_races`).WithArgs("dwarf").
+		WillReturnRows(sqlmock.NewRows([]string{"api_index", "name", "speed", "ability_bonuses", "proficiencies"}).
+			AddRow("dwarf", "Dwarf", 25, []byte(`[{"ability_score":{"index":"con"},"bonus":2}]`), []byte(`[]`)))
+	mock.ExpectQuery(`FROM dnd_classes`).WithArgs("cleric").
+		WillReturnRows(sqlmock.NewRows([]string{"api_index", "name", "hit_die", "saving_throws", "spellcasting", "spellcasting_ability"}).
+			AddRow("cleric", "Cleric", 8, pq.StringArray{"wis", "cha"}, []byte(`{"spellcasting_ability":{"index":"wis"}}`), ""))
+}
+
+func TestPCHandler_GetComputedSheet(t *testing.T) {
+	handler, mock, cleanup := newMockPCHandler(t)
+	defer cleanup()
+
+	pcCols := []string{
+		"id", "name", "description", "level", "race", "class", "background", "alignment",
+		"attributes", "abilities", "equipment", "hp", "current_hp", "ca", "proficiency_bonus",
+		"inspiration", "skills", "attacks", "spells", "personality_traits", "ideals", "bonds",
+		"flaws", "features", "player_name", "player_id", "is_homebrew", "is_unique", "created_at",
+	}
+	mock.ExpectQuery(`FROM pcs`).WithArgs(1, 7).WillReturnRows(sqlmock.NewRows(pcCols).AddRow(
+		1, "Brom", "", 3, "dwarf", "cleric", "acolyte", "",
+		[]byte(`{"strength":14,"dexterity":10,"constitution":16,"intelligence":10,"wisdom":16,"charisma":8}`),
+		[]byte(`{}`), []byte(`[{"name":"Torch","equipped":false}]`), 25, 25, 10, 2, false,
+		[]byte(`{}`), []byte(`[]`), []byte(`{}`), "", "", "", "", pq.StringArray{},
+		"Player", 7, false, false, time.Now(),
+	))
+	expectClericRules(mock)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/pcs/1/computed-sheet", nil)
+	req = addChiParam(req, "id", "1")
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, 7))
+	rr := httptest.NewRecorder()
+	handler.GetComputedSheet(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var sheet models.ComputedSheet
+	if err := json.Unmarshal(rr.Body.Bytes(), &sheet); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	// 8 + 5 + 5 com Con +3
+	if sheet.MaxHP != 27 || sheet.ArmorClass != 10 || sheet.Speed != 25 {
+		t.Fatalf("unexpected sheet: %+v", sheet)
+	}
+	if sheet.Spellcasting == nil || sheet.Spellcasting.SaveDC != 13 || sheet.SavingThrows["wisdom"].Bonus != 5 {
+		t.Fatalf("unexpected cleric stats: %+v", sheet)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestPCHandler_GetComputedSheet_NotFound(t *testing.T) {
+	handler, mock, cleanup := newMockPCHandler(t)
+	defer cleanup()
+
+	mock.ExpectQuery(`FROM pcs`).WithArgs(9, 7).WillReturnError(sql.ErrNoRows)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/pcs/9/computed-sheet", nil)
+	req = addChiParam(req, "id", "9")
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, 7))
+	rr := httptest.NewRecorder()
+	handler.GetComputedSheet(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rr.Code)
+	}
+}
+
+func TestPCHandler_CreatePC_AutoFill(t *testing.T) {
+	handler, mock, cleanup := newMockPCHandler(t)
+	defer cleanup()
+
+	expectClericRules(mock)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO pcs`).WithArgs(
+		"Brom", "", 3, "dwarf", "cleric", "", "", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+		27, 10, 2, "", 7, false, false, sqlmock.AnyArg(), sqlmock.AnyArg(),
+		sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+	).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(11))
+	expectPCVersionRecorded(mock, 11)
+	mock.ExpectCommit()
+
+	body := `{"name":"Brom","level":3,"race":"dwarf","class":"cleric","hp":99,"ca":20,
+		"attributes":{"strength":14,"dexterity":10,"constitution":16,"intelligence":10,"wisdom":16,"charisma":8}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/pcs?auto_fill=true", bytes.NewBufferString(body))
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, 7))
+	rr := httptest.NewRecorder()
+	handler.CreatePC(rr, req)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
@@ -15,6 +15,7 @@ type PCHandler struct {
 	Python    *python.Client
 	Response  *utils.ResponseHandler
 	Validator *utils.Validator
+	Dice      DiceSource // Rolagens de atributos do construtor; crypto/rand por padrão
 }
 
 func NewPCHandler(db *db.PostgresDB, python *python.Client) *PCHandler {
@@ -23,9 +24,18 @@ func NewPCHandler(db *db.PostgresDB, python *python.Client) *PCHandler {
 		Python:    python,
 		Response:  utils.NewResponseHandler(),
 		Validator: utils.NewValidator(),
+		Dice:      NewCryptoDiceSource(),
 	}
 }
 
+// dice retorna a fonte de dados configurada, usando crypto/rand se nenhuma foi definida
+func (h *PCHandler) dice() DiceSource {
+	if h.Dice == nil {
+		return NewCryptoDiceSource()
+	}
+	return h.Dice
+}
+
 // GetPCs retorna os PCs do usuário logado
 func (h *PCHandler) GetPCs(w http.ResponseWriter, r *http.Request) {
 	// Extract user ID using utility
@@ -87,6 +97,11 @@ func (h *PCHandler) CreatePC(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Com class_levels, class e level são só o resumo da lista
+	if len(pc.ClassLevels) > 0 {
+		pc.Class, pc.Level = pc.ClassLevels.Summary(), pc.ClassLevels.TotalLevel()
+	}
+
 	// Validate using centralized validator
 	validationErrors := h.Validator.BatchValidate(
 		func() error { return h.Validator.ValidateRequired(pc.Name, "name") },
@@ -100,6 +115,11 @@ func (h *PCHandler) CreatePC(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := pc.NormalizeClassLevels(); err != nil {
+		h.Response.SendValidationErrors(w, utils.ValidationErrors{fieldError("class_levels", "invalid", "%s", err.Error())})
+		return
+	}
+
 	// Definir valores padrão se não fornecidos
 	if pc.HP <= 0 {
 		pc.HP = 10 + pc.Level*5
@@ -109,6 +129,10 @@ func (h *PCHandler) CreatePC(w http.ResponseWriter, r *http.Request) {
 		pc.CA = 10
 	}
 
+	if pc.ProficiencyBonus <= 0 {
+		pc.ProficiencyBonus = pc.GetProficiencyBonus()
+	}
+
 	// Garantir que campos JSONBFlexible existam com valores padrão válidos
 	if pc.Abilities.Data == nil {
 		pc.Abilities = models.JSONBFlexible{Data: map[string]any{}}
@@ -147,7 +171,16 @@ func (h *PCHandler) CreatePC(w http.ResponseWriter, r *http.Request) {
 	// Associar ao usuário fmtado
 	pc.PlayerID = userID
 
-	err = h.DB.CreatePC(r.Context(), &pc)
+	// Calcular PV, CA e bônus de proficiência pelas regras, se pedido
+	if autoFillRequested(r) {
+		sheet, ok := h.computeSheet(w, r, &pc, userID)
+		if !ok {
+			return
+		}
+		sheet.ApplyTo(&pc)
+	}
+
+	err = h.DB.CreatePC(r.Context(), &pc, userID)
 	if err != nil {
 		h.Response.HandleDBError(w, err, "create PC")
 		return
@@ -178,6 +211,11 @@ func (h *PCHandler) UpdatePC(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Com class_levels, class e level são só o resumo da lista
+	if len(pc.ClassLevels) > 0 {
+		pc.Class, pc.Level = pc.ClassLevels.Summary(), pc.ClassLevels.TotalLevel()
+	}
+
 	// Validate using centralized validator
 	validationErrors := h.Validator.BatchValidate(
 		func() error { return h.Validator.ValidateRequired(pc.Name, "name") },
@@ -189,6 +227,11 @@ func (h *PCHandler) UpdatePC(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := pc.NormalizeClassLevels(); err != nil {
+		h.Response.SendValidationErrors(w, utils.ValidationErrors{fieldError("class_levels", "invalid", "%s", err.Error())})
+		return
+	}
+
 	// Garantir que campos JSONBFlexible existam com valores padrão válidos
 	if pc.Abilities.Data == nil {
 		pc.Abilities = models.JSONBFlexible{Data: map[string]any{}}
@@ -217,7 +260,16 @@ func (h *PCHandler) UpdatePC(w http.ResponseWriter, r *http.Request) {
 	pc.ID = id
 	pc.PlayerID = userID
 
-	err = h.DB.UpdatePC(r.Context(), &pc)
+	// Calcular PV, CA e bônus de proficiência pelas regras, se pedido
+	if autoFillRequested(r) {
+		sheet, ok := h.computeSheet(w, r, &pc, userID)
+		if !ok {
+			return
+		}
+		sheet.ApplyTo(&pc)
+	}
+
+	err = h.DB.UpdatePC(r.Context(), &pc, userID, models.VersionActionUpdate)
 	if err != nil {
 		h.Response.HandleDBError(w, err, "update PC")
 		return
@@ -346,89 +398,3 @@ func (h *PCHandler) CheckUniquePCAvailability(w http.ResponseWriter, r *http.Req
 		"campaign_count": campaignCount,
 	}, http.StatusOK)
 }
-
-// GenerateRandomPC gera um PC usando o serviço Python
-// func (h *PCHandler) GenerateRandomPC(w http.ResponseWriter, r *http.Request) {
-// 	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
-// 	if !ok {
-// 		http.Error(w, "User ID not found in context", http.StatusInternalServerError)
-// 		return
-// 	}
-
-// 	var request struct {
-// 		Level            int    `json:"level"`
-// 		AttributesMethod string `json:"attributes_method,omitempty"`
-// 		Manual           bool   `json:"manual"`
-// 		Race             string `json:"race,omitempty"`
-// 		Class            string `json:"class,omitempty"`
-// 		Background       string `json:"background,omitempty"`
-// 		PlayerName       string `json:"player_name,omitempty"`
-// 	}
-
-// 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-// 		http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
-// 		return
-// 	}
-
-// 	if request.Level < 1 || request.Level > 20 {
-// 		http.Error(w, "Level must be between 1 and 20", http.StatusBadRequest)
-// 		return
-// 	}
-
-// 	// Default attributes_method se não for manual e não foi fornecido
-// 	if !request.Manual && request.AttributesMethod == "" {
-// 		request.AttributesMethod = "rolagem"
-// 	}
-
-// 	// Validar attributes_method
-// 	if !request.Manual || request.AttributesMethod != "" {
-// 		if request.AttributesMethod != "rolagem" && request.AttributesMethod != "array" && request.AttributesMethod != "compra" {
-// 			http.Error(w, "Invalid attributes_method. Must be 'rolagem', 'array', or 'compra'", http.StatusBadRequest)
-// 			return
-// 		}
-// 	}
-
-// 	// Gerar NPC via Python (que será convertido para PC)
-// 	npc, err := h.Python.GenerateNPC(r.Context(), request.Level, request.AttributesMethod, request.Manual, request.Race, request.Class, request.Background)
-// 	if err != nil {
-// 		http.Error(w, "Failed to generate PC: "+err.Error(), http.StatusInternalServerError)
-// 		return
-// 	}
-
-// 	// Converter NPC para PC
-// 	pc := &models.PC{
-// 		Name:        npc.Name,
-// 		Description: npc.Description,
-// 		Level:       npc.Level,
-// 		Race:        npc.Race,
-// 		Class:       npc.Class,
-// 		Background:  npc.Background,
-// 		Attributes:  npc.Attributes,
-// 		Abilities:   npc.Abilities,
-// 		Equipment:   npc.Equipment,
-// 		HP:          npc.HP,
-// 		CA:          npc.CA,
-// 		PlayerID:    userID,
-// 		PlayerName:  request.PlayerName,
-// 	}
-
-// 	// Se o player_name não foi fornecido, usar um padrão
-// 	if pc.PlayerName == "" {
-// 		user, err := h.DB.GetUserByID(r.Context(), userID)
-// 		if err == nil {
-// 			pc.PlayerName = user.Username
-// 		} else {
-// 			pc.PlayerName = "Unknown Player"
-// 		}
-// 	}
-
-// 	err = h.DB.CreatePC(r.Context(), pc)
-// 	if err != nil {
-// 		http.Error(w, "Failed to save generated PC: "+err.Error(), http.StatusInternalServerError)
-// 		return
-// 	}
-
-// 	w.Header().Set("Content-Type", "application/json")
-// 	w.WriteHeader(http.StatusCreated)
-// 	json.NewEncoder(w).Encode(pc)
-// }
@@ -16,6 +16,7 @@ import (
 
 	"rpg-saas-backend/internal/api/middleware"
 	"rpg-saas-backend/internal/db"
+	"rpg-saas-backend/internal/models"
 	"rpg-saas-backend/internal/python"
 )
 
@@ -92,10 +93,14 @@ func TestPCHandler_CreateUpdateDelete(t *testing.T) {
 	defer cleanup()
 
 	// Create
+	mock.ExpectBegin()
 	mock.ExpectQuery(`INSERT INTO pcs`).WithArgs(
 		"New", "desc", 2, "elf", "wizard", "sage", "", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
-		sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), 7, false, false, sqlmock.AnyArg(),
+		sqlmock.AnyArg(), sqlmock.AnyArg(), 2, sqlmock.AnyArg(), 7, false, false, sqlmock.AnyArg(), sqlmock.AnyArg(),
+		sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
 	).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+	expectPCVersionRecorded(mock, 10)
+	mock.ExpectCommit()
 
 	createBody := `{"name":"New","description":"desc","level":2,"race":"elf","class":"wizard","background":"sage"}`
 	reqCreate := httptest.NewRequest(http.MethodPost, "/api/pcs", bytes.NewBufferString(createBody))
@@ -107,7 +112,11 @@ func TestPCHandler_CreateUpdateDelete(t *testing.T) {
 	}
 
 	// Update
+	mock.ExpectBegin()
+	expectVersionHistory(mock, models.CharacterTypePC, 10)
 	mock.ExpectExec(`UPDATE pcs SET`).WillReturnResult(sqlmock.NewResult(0, 1))
+	expectPCVersionRecorded(mock, 10)
+	mock.ExpectCommit()
 	updateBody := `{"name":"Upd","description":"desc","level":3,"race":"elf","class":"wizard"}`
 	reqUpdate := httptest.NewRequest(http.MethodPut, "/api/pcs/10", bytes.NewBufferString(updateBody))
 	reqUpdate = addChiParam(reqUpdate, "id", "10")
@@ -372,3 +381,28 @@ func TestPCHandler_CheckUniquePCAvailability_NonUnique(t *testing.T) {
 		t.Fatalf("expected 200 for non-unique availability, got %d", recNonUnique.Code)
 	}
 }
+
+func TestPCHandler_UpdatePC_VersionFailureRollsBack(t *testing.T) {
+	handler, mock, cleanup := newMockPCHandler(t)
+	defer cleanup()
+
+	// Sem a versão, a alteração também não é gravada
+	mock.ExpectBegin()
+	expectVersionHistory(mock, models.CharacterTypePC, 10)
+	mock.ExpectExec(`UPDATE pcs SET`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`FROM pcs WHERE id = \$1`).WithArgs(10).WillReturnError(sql.ErrConnDone)
+	mock.ExpectRollback()
+
+	req := httptest.NewRequest(http.MethodPut, "/api/pcs/10", bytes.NewBufferString(`{"name":"Upd","level":3,"race":"elf","class":"wizard"}`))
+	req = addChiParam(req, "id", "10")
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, 7))
+	rec := httptest.NewRecorder()
+	handler.UpdatePC(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
@@ -0,0 +1,545 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"rpg-saas-backend/internal/db"
+	"rpg-saas-backend/internal/models"
+	"rpg-saas-backend/internal/utils"
+)
+
+// QuestHandler gerencia as quests e objetivos de uma campanha
+type QuestHandler struct {
+	DB        *db.PostgresDB
+	Response  *utils.ResponseHandler
+	Validator *utils.Validator
+	Hub       *RoomHub // Usado para avisar a sala da campanha sobre mudanças nas quests
+}
+
+func NewQuestHandler(db *db.PostgresDB, hub *RoomHub) *QuestHandler {
+	return &QuestHandler{
+		DB:        db,
+		Response:  utils.NewResponseHandler(),
+		Validator: utils.NewValidator(),
+		Hub:       hub,
+	}
+}
+
+// GetQuests lista as quests da campanha; players veem apenas as reveladas (filtro ?status=)
+func (h *QuestHandler) GetQuests(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	if status != "" {
+		if err := h.Validator.ValidateChoice(status, "status", models.QuestStatuses); err != nil {
+			h.Response.SendBadRequest(w, err.Error())
+			return
+		}
+	}
+
+	quests, err := h.DB.GetCampaignQuests(r.Context(), campaign.ID, !canManageCampaign(campaign, userID), status)
+	if err != nil {
+		h.Response.HandleDBError(w, err, "fetch quests")
+		return
+	}
+
+	questIDs := make([]int, len(quests))
+	for i := range quests {
+		questIDs[i] = quests[i].ID
+	}
+
+	objectives, err := h.DB.GetQuestObjectives(r.Context(), questIDs...)
+	if err != nil {
+		h.Response.HandleDBError(w, err, "fetch quest objectives")
+		return
+	}
+
+	byQuest := make(map[int][]models.QuestObjective)
+	for _, objective := range objectives {
+		byQuest[objective.QuestID] = append(byQuest[objective.QuestID], objective)
+	}
+	for i := range quests {
+		quests[i].Objectives = byQuest[quests[i].ID]
+		if quests[i].Objectives == nil {
+			quests[i].Objectives = []models.QuestObjective{}
+		}
+	}
+
+	h.Response.SendJSON(w, map[string]any{
+		"quests": quests,
+		"count":  len(quests),
+	}, http.StatusOK)
+}
+
+// GetQuest retorna uma quest com seus objetivos
+func (h *QuestHandler) GetQuest(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	quest, ok := h.loadQuest(w, r, campaign, userID)
+	if !ok {
+		return
+	}
+
+	if !h.loadObjectives(w, r, quest) {
+		return
+	}
+
+	h.Response.SendJSON(w, quest, http.StatusOK)
+}
+
+// CreateQuest cria uma quest com objetivos iniciais (apenas DM)
+func (h *QuestHandler) CreateQuest(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	if !canManageCampaign(campaign, userID) {
+		h.Response.SendForbidden(w, "Only the DM can create quests")
+		return
+	}
+
+	var req models.CreateQuestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Response.SendBadRequest(w, "Invalid request body")
+		return
+	}
+
+	validationErrors := h.Validator.BatchValidate(
+		func() error { return h.Validator.ValidateName(req.Title, "title") },
+		func() error {
+			for _, description := range req.Objectives {
+				if err := h.Validator.ValidateRequired(description, "objectives"); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	)
+	if validationErrors.HasErrors() {
+		h.Response.SendValidationError(w, validationErrors.Error())
+		return
+	}
+
+	if req.GiverNPCID != nil && !h.validateGiverNPC(w, r, campaign.ID, *req.GiverNPCID) {
+		return
+	}
+
+	quest := &models.Quest{
+		CampaignID:  campaign.ID,
+		Title:       strings.TrimSpace(req.Title),
+		Description: req.Description,
+		GiverNPCID:  req.GiverNPCID,
+		GiverName:   strings.TrimSpace(req.GiverName),
+		Status:      models.QuestStatusActive,
+		Revealed:    req.Revealed,
+		Rewards:     req.Rewards,
+		Objectives:  make([]models.QuestObjective, len(req.Objectives)),
+	}
+	if quest.Rewards == nil {
+		quest.Rewards = models.JSONB{}
+	}
+	for i, description := range req.Objectives {
+		quest.Objectives[i] = models.QuestObjective{Description: strings.TrimSpace(description), SortOrder: i}
+	}
+
+	if err := h.DB.CreateQuest(r.Context(), quest); err != nil {
+		h.Response.HandleDBError(w, err, "create quest")
+		return
+	}
+
+	if quest.Revealed {
+		h.broadcastQuest(r.Context(), quest, userID, "quest:revealed")
+	}
+
+	h.Response.SendCreated(w, "Quest created successfully", quest)
+}
+
+// UpdateQuest edita os dados da quest e a revelação aos players (apenas DM)
+func (h *QuestHandler) UpdateQuest(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	if !canManageCampaign(campaign, userID) {
+		h.Response.SendForbidden(w, "Only the DM can edit quests")
+		return
+	}
+
+	quest, ok := h.loadQuest(w, r, campaign, userID)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateQuestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Response.SendBadRequest(w, "Invalid request body")
+		return
+	}
+
+	if req.Title != "" {
+		if err := h.Validator.ValidateName(req.Title, "title"); err != nil {
+			h.Response.SendValidationError(w, err.Error())
+			return
+		}
+		quest.Title = strings.TrimSpace(req.Title)
+	}
+
+	if req.GiverNPCID != nil {
+		if *req.GiverNPCID == 0 {
+			quest.GiverNPCID = nil
+			quest.GiverNPCName = ""
+		} else {
+			if !h.validateGiverNPC(w, r, campaign.ID, *req.GiverNPCID) {
+				return
+			}
+			quest.GiverNPCID = req.GiverNPCID
+		}
+	}
+
+	if req.Description != nil {
+		quest.Description = *req.Description
+	}
+	if req.GiverName != nil {
+		quest.GiverName = strings.TrimSpace(*req.GiverName)
+	}
+	if req.Rewards != nil {
+		quest.Rewards = req.Rewards
+	}
+
+	newlyRevealed := req.Revealed != nil && *req.Revealed && !quest.Revealed
+	if req.Revealed != nil {
+		quest.Revealed = *req.Revealed
+	}
+
+	if err := h.DB.UpdateQuest(r.Context(), quest); err != nil {
+		h.Response.HandleDBError(w, err, "update quest")
+		return
+	}
+
+	if !h.loadObjectives(w, r, quest) {
+		return
+	}
+
+	if newlyRevealed {
+		h.broadcastQuest(r.Context(), quest, userID, "quest:revealed")
+	}
+
+	h.Response.SendJSON(w, quest, http.StatusOK)
+}
+
+// UpdateQuestStatus altera o status da quest e avisa a sala da campanha (apenas DM)
+func (h *QuestHandler) UpdateQuestStatus(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	if !canManageCampaign(campaign, userID) {
+		h.Response.SendForbidden(w, "Only the DM can change quest status")
+		return
+	}
+
+	quest, ok := h.loadQuest(w, r, campaign, userID)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateQuestStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Response.SendBadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := h.Validator.ValidateChoice(req.Status, "status", models.QuestStatuses); err != nil {
+		h.Response.SendValidationError(w, err.Error())
+		return
+	}
+
+	previousStatus := quest.Status
+	quest.Status = req.Status
+	quest.CompletedAt = nil
+	if req.Status != models.QuestStatusActive {
+		now := time.Now()
+		quest.CompletedAt = &now
+	}
+
+	if err := h.DB.UpdateQuest(r.Context(), quest); err != nil {
+		h.Response.HandleDBError(w, err, "update quest status")
+		return
+	}
+
+	if previousStatus != quest.Status {
+		h.broadcastQuest(r.Context(), quest, userID, "quest:status")
+	}
+
+	h.Response.SendJSON(w, quest, http.StatusOK)
+}
+
+// DeleteQuest remove uma quest (apenas DM)
+func (h *QuestHandler) DeleteQuest(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	if !isCampaignDM(campaign, userID) {
+		h.Response.SendForbidden(w, "Only the DM can delete quests")
+		return
+	}
+
+	questID, err := utils.ExtractIDParam(r, "questId")
+	if err != nil {
+		h.Response.SendBadRequest(w, err.Error())
+		return
+	}
+
+	if err := h.DB.DeleteQuest(r.Context(), questID, campaign.ID); err != nil {
+		h.Response.SendNotFound(w, "Quest not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CreateObjective adiciona um objetivo à quest (apenas DM)
+func (h *QuestHandler) CreateObjective(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	if !canManageCampaign(campaign, userID) {
+		h.Response.SendForbidden(w, "Only the DM can edit quest objectives")
+		return
+	}
+
+	quest, ok := h.loadQuest(w, r, campaign, userID)
+	if !ok {
+		return
+	}
+
+	var req models.QuestObjectiveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Response.SendBadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := h.Validator.ValidateRequired(req.Description, "description"); err != nil {
+		h.Response.SendValidationError(w, err.Error())
+		return
+	}
+
+	// sort_order negativo faz o banco colocar o objetivo no final
+	objective := &models.QuestObjective{
+		QuestID:     quest.ID,
+		Description: strings.TrimSpace(req.Description),
+		SortOrder:   -1,
+	}
+	if req.SortOrder != nil {
+		objective.SortOrder = *req.SortOrder
+	}
+	if req.Completed != nil && *req.Completed {
+		now := time.Now()
+		objective.Completed = true
+		objective.CompletedAt = &now
+	}
+
+	if err := h.DB.CreateQuestObjective(r.Context(), objective); err != nil {
+		h.Response.HandleDBError(w, err, "create quest objective")
+		return
+	}
+
+	h.Response.SendCreated(w, "Objective created successfully", objective)
+}
+
+// UpdateObjective edita ou marca um objetivo como concluído (apenas DM)
+func (h *QuestHandler) UpdateObjective(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	if !canManageCampaign(campaign, userID) {
+		h.Response.SendForbidden(w, "Only the DM can edit quest objectives")
+		return
+	}
+
+	quest, ok := h.loadQuest(w, r, campaign, userID)
+	if !ok {
+		return
+	}
+
+	objectiveID, err := utils.ExtractIDParam(r, "objectiveId")
+	if err != nil {
+		h.Response.SendBadRequest(w, err.Error())
+		return
+	}
+
+	objective, err := h.DB.GetQuestObjective(r.Context(), objectiveID, quest.ID)
+	if err != nil {
+		h.Response.HandleDBError(w, err, "fetch quest objective")
+		return
+	}
+	if objective == nil {
+		h.Response.SendNotFound(w, "Objective not found")
+		return
+	}
+
+	var req models.QuestObjectiveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Response.SendBadRequest(w, "Invalid request body")
+		return
+	}
+
+	if strings.TrimSpace(req.Description) != "" {
+		objective.Description = strings.TrimSpace(req.Description)
+	}
+	if req.SortOrder != nil {
+		objective.SortOrder = *req.SortOrder
+	}
+
+	completionChanged := req.Completed != nil && *req.Completed != objective.Completed
+	if completionChanged {
+		objective.Completed = *req.Completed
+		objective.CompletedAt = nil
+		if objective.Completed {
+			now := time.Now()
+			objective.CompletedAt = &now
+		}
+	}
+
+	if err := h.DB.UpdateQuestObjective(r.Context(), objective); err != nil {
+		h.Response.HandleDBError(w, err, "update quest objective")
+		return
+	}
+
+	if completionChanged {
+		h.broadcastQuest(r.Context(), quest, userID, "quest:objective", map[string]any{
+			"objective_id": objective.ID,
+			"completed":    objective.Completed,
+		})
+	}
+
+	h.Response.SendJSON(w, objective, http.StatusOK)
+}
+
+// DeleteObjective remove um objetivo da quest (apenas DM)
+func (h *QuestHandler) DeleteObjective(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	if !isCampaignDM(campaign, userID) {
+		h.Response.SendForbidden(w, "Only the DM can edit quest objectives")
+		return
+	}
+
+	quest, ok := h.loadQuest(w, r, campaign, userID)
+	if !ok {
+		return
+	}
+
+	objectiveID, err := utils.ExtractIDParam(r, "objectiveId")
+	if err != nil {
+		h.Response.SendBadRequest(w, err.Error())
+		return
+	}
+
+	if err := h.DB.DeleteQuestObjective(r.Context(), objectiveID, quest.ID); err != nil {
+		h.Response.SendNotFound(w, "Objective not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// loadQuest busca a quest {questId} da campanha; quests não reveladas respondem 404 para players
+func (h *QuestHandler) loadQuest(w http.ResponseWriter, r *http.Request, campaign *models.Campaign, userID int) (*models.Quest, bool) {
+	questID, err := utils.ExtractIDParam(r, "questId")
+	if err != nil {
+		h.Response.SendBadRequest(w, err.Error())
+		return nil, false
+	}
+
+	quest, err := h.DB.GetQuest(r.Context(), questID, campaign.ID)
+	if err != nil {
+		h.Response.HandleDBError(w, err, "fetch quest")
+		return nil, false
+	}
+	if quest == nil || (!quest.Revealed && !canManageCampaign(campaign, userID)) {
+		h.Response.SendNotFound(w, "Quest not found")
+		return nil, false
+	}
+
+	return quest, true
+}
+
+// loadObjectives preenche os objetivos da quest
+func (h *QuestHandler) loadObjectives(w http.ResponseWriter, r *http.Request, quest *models.Quest) bool {
+	objectives, err := h.DB.GetQuestObjectives(r.Context(), quest.ID)
+	if err != nil {
+		h.Response.HandleDBError(w, err, "fetch quest objectives")
+		return false
+	}
+
+	quest.Objectives = objectives
+	return true
+}
+
+// validateGiverNPC verifica se o NPC informado como quest giver existe e é
+// global ou da própria campanha. NPCs de outras campanhas respondem como
+// inexistentes para não revelar que existem.
+func (h *QuestHandler) validateGiverNPC(w http.ResponseWriter, r *http.Request, campaignID, npcID int) bool {
+	npc, err := h.DB.GetNPCByID(r.Context(), npcID)
+	if err != nil || (npc.CampaignID != nil && *npc.CampaignID != campaignID) {
+		h.Response.SendValidationError(w, "giver_npc_id: NPC not found")
+		return false
+	}
+	return true
+}
+
+// broadcastQuest avisa a sala da campanha sobre a quest. Quests não reveladas
+// nunca são transmitidas, pois a sala inclui os players.
+func (h *QuestHandler) broadcastQuest(ctx context.Context, quest *models.Quest, senderID int, eventType string, extra ...map[string]any) {
+	if h.Hub == nil || !quest.Revealed {
+		return
+	}
+
+	room, err := h.DB.GetRoomByCampaignID(ctx, quest.CampaignID)
+	if err != nil || room == nil {
+		return
+	}
+
+	metadata := map[string]any{
+		"quest_id": quest.ID,
+		"title":    quest.Title,
+		"status":   quest.Status,
+	}
+	for _, values := range extra {
+		for key, value := range values {
+			metadata[key] = value
+		}
+	}
+
+	h.Hub.Broadcast(room.ID, RoomSocketMessage{
+		Type:      eventType,
+		RoomID:    room.ID,
+		SenderID:  senderID,
+		Message:   quest.Title,
+		Metadata:  metadata,
+		Timestamp: time.Now().UnixMilli(),
+	})
+}
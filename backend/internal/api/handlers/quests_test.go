@@ -0,0 +1,316 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+
+	"rpg-saas-backend/internal/db"
+	"rpg-saas-backend/internal/models"
+)
+
+var questCols = []string{
+	"id", "campaign_id", "title", "description", "giver_npc_id", "giver_npc_name", "giver_name",
+	"status", "revealed", "rewards", "completed_at", "created_at", "updated_at",
+}
+
+var questObjectiveCols = []string{"id", "quest_id", "description", "completed", "sort_order", "completed_at", "created_at"}
+
+var roomCols = []string{"id", "name", "owner_id", "campaign_id", "scene_state", "metadata", "created_at", "updated_at"}
+
+func newMockQuestHandler(t *testing.T) (*QuestHandler, sqlmock.Sqlmock, func()) {
+	t.Helper()
+
+	rawDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+
+	pdb := &db.PostgresDB{DB: sqlx.NewDb(rawDB, "postgres")}
+	return NewQuestHandler(pdb, NewRoomHub()), mock, func() { rawDB.Close() }
+}
+
+func questRow(id int, status string, revealed bool) *sqlmock.Rows {
+	now := time.Now()
+	return sqlmock.NewRows(questCols).
+		AddRow(id, 10, "Lost Mine", "Find the mine", 3, "Gundren", "", status, revealed, []byte(`{"xp":300}`), nil, now, now)
+}
+
+func expectCampaignRoom(mock sqlmock.Sqlmock, campaignID int) {
+	now := time.Now()
+	mock.ExpectQuery(`FROM rooms`).WithArgs(campaignID).
+		WillReturnRows(sqlmock.NewRows(roomCols).AddRow("room-1", "Table", 7, campaignID, []byte(`{}`), []byte(`{}`), now, now))
+}
+
+func TestQuestHandler_GetQuestsOnlyRevealedForPlayers(t *testing.T) {
+	handler, mock, cleanup := newMockQuestHandler(t)
+	defer cleanup()
+
+	expectCampaignAccess(mock, 10, 8, 7, 8)
+	mock.ExpectQuery(`WHERE q.campaign_id = \$1 AND q.revealed = TRUE`).WithArgs(10).
+		WillReturnRows(questRow(1, "active", true))
+	now := time.Now()
+	mock.ExpectQuery(`FROM quest_objectives`).WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows(questObjectiveCols).
+			AddRow(1, 1, "Reach Phandalin", true, 0, now, now).
+			AddRow(2, 1, "Find Cragmaw Castle", false, 1, nil, now))
+
+	req := withCampaignUser(httptest.NewRequest(http.MethodGet, "/api/campaigns/10/quests", nil), "10", 8)
+	rr := httptest.NewRecorder()
+	handler.GetQuests(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var resp struct {
+		Quests []models.Quest `json:"quests"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(resp.Quests) != 1 || len(resp.Quests[0].Objectives) != 2 || resp.Quests[0].GiverNPCName != "Gundren" {
+		t.Fatalf("unexpected quests: %+v", resp.Quests)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestQuestHandler_GetQuestHiddenFromPlayer(t *testing.T) {
+	handler, mock, cleanup := newMockQuestHandler(t)
+	defer cleanup()
+
+	expectCampaignAccess(mock, 10, 8, 7, 8)
+	mock.ExpectQuery(`WHERE q.id = \$1 AND q.campaign_id = \$2`).WithArgs(1, 10).
+		WillReturnRows(questRow(1, "active", false))
+
+	req := withCampaignUser(httptest.NewRequest(http.MethodGet, "/api/campaigns/10/quests/1", nil), "10", 8)
+	req = addChiURLParam(req, "questId", "1")
+	rr := httptest.NewRecorder()
+	handler.GetQuest(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unrevealed quest, got %d", rr.Code)
+	}
+}
+
+func TestQuestHandler_CreateQuestWithObjectives(t *testing.T) {
+	handler, mock, cleanup := newMockQuestHandler(t)
+	defer cleanup()
+
+	expectCampaignAccess(mock, 10, 7, 7)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO quests`).
+		WithArgs(10, "Goblin Ambush", "", nil, "Sildar", "active", false, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+	mock.ExpectQuery(`INSERT INTO quest_objectives`).WithArgs(4, "Defeat the goblins", false, 0, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(`INSERT INTO quest_objectives`).WithArgs(4, "Rescue Sildar", false, 1, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectCommit()
+
+	body := bytes.NewBufferString(`{"title":"Goblin Ambush","giver_name":"Sildar","objectives":["Defeat the goblins","Rescue Sildar"]}`)
+	req := withCampaignUser(httptest.NewRequest(http.MethodPost, "/api/campaigns/10/quests", body), "10", 7)
+	rr := httptest.NewRecorder()
+	handler.CreateQuest(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestQuestHandler_CreateQuestUnknownNPC(t *testing.T) {
+	handler, mock, cleanup := newMockQuestHandler(t)
+	defer cleanup()
+
+	expectCampaignAccess(mock, 10, 7, 7)
+	mock.ExpectQuery(`SELECT \* FROM npcs WHERE id = \$1`).WithArgs(99).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	body := bytes.NewBufferString(`{"title":"Goblin Ambush","giver_npc_id":99}`)
+	req := withCampaignUser(httptest.NewRequest(http.MethodPost, "/api/campaigns/10/quests", body), "10", 7)
+	rr := httptest.NewRecorder()
+	handler.CreateQuest(rr, req)
+
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestQuestHandler_CreateQuestNPCFromOtherCampaign(t *testing.T) {
+	handler, mock, cleanup := newMockQuestHandler(t)
+	defer cleanup()
+
+	expectCampaignAccess(mock, 10, 7, 7)
+	mock.ExpectQuery(`SELECT \* FROM npcs WHERE id = \$1`).WithArgs(42).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "campaign_id"}).AddRow(42, "Strahd", 11))
+
+	body := bytes.NewBufferString(`{"title":"Castle Ravenloft","giver_npc_id":42}`)
+	req := withCampaignUser(httptest.NewRequest(http.MethodPost, "/api/campaigns/10/quests", body), "10", 7)
+	rr := httptest.NewRecorder()
+	handler.CreateQuest(rr, req)
+
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422 for an NPC from another campaign, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestQuestHandler_CreateQuestGlobalNPC(t *testing.T) {
+	handler, mock, cleanup := newMockQuestHandler(t)
+	defer cleanup()
+
+	expectCampaignAccess(mock, 10, 7, 7)
+	mock.ExpectQuery(`SELECT \* FROM npcs WHERE id = \$1`).WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "campaign_id"}).AddRow(3, "Gundren", nil))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO quests`).
+		WithArgs(10, "Lost Mine", "", 3, "", "active", false, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+	mock.ExpectCommit()
+
+	body := bytes.NewBufferString(`{"title":"Lost Mine","giver_npc_id":3}`)
+	req := withCampaignUser(httptest.NewRequest(http.MethodPost, "/api/campaigns/10/quests", body), "10", 7)
+	rr := httptest.NewRecorder()
+	handler.CreateQuest(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201 for a global NPC, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestQuestHandler_UpdateQuestStatusBroadcasts(t *testing.T) {
+	handler, mock, cleanup := newMockQuestHandler(t)
+	defer cleanup()
+
+	expectCampaignAccess(mock, 10, 7, 7)
+	mock.ExpectQuery(`WHERE q.id = \$1 AND q.campaign_id = \$2`).WithArgs(1, 10).
+		WillReturnRows(questRow(1, "active", true))
+	mock.ExpectExec(`UPDATE quests SET`).
+		WithArgs("Lost Mine", "Find the mine", 3, "", "completed", true, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), 1, 10).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectCampaignRoom(mock, 10)
+
+	req := withCampaignUser(httptest.NewRequest(http.MethodPut, "/api/campaigns/10/quests/1/status", bytes.NewBufferString(`{"status":"completed"}`)), "10", 7)
+	req = addChiURLParam(req, "questId", "1")
+	rr := httptest.NewRecorder()
+	handler.UpdateQuestStatus(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var quest models.Quest
+	if err := json.NewDecoder(rr.Body).Decode(&quest); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if quest.Status != models.QuestStatusCompleted || quest.CompletedAt == nil {
+		t.Fatalf("expected completed quest with completed_at, got %+v", quest)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestQuestHandler_UpdateQuestStatusHiddenQuestNotBroadcast(t *testing.T) {
+	handler, mock, cleanup := newMockQuestHandler(t)
+	defer cleanup()
+
+	expectCampaignAccess(mock, 10, 7, 7)
+	mock.ExpectQuery(`WHERE q.id = \$1 AND q.campaign_id = \$2`).WithArgs(1, 10).
+		WillReturnRows(questRow(1, "active", false))
+	mock.ExpectExec(`UPDATE quests SET`).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	req := withCampaignUser(httptest.NewRequest(http.MethodPut, "/api/campaigns/10/quests/1/status", bytes.NewBufferString(`{"status":"failed"}`)), "10", 7)
+	req = addChiURLParam(req, "questId", "1")
+	rr := httptest.NewRecorder()
+	handler.UpdateQuestStatus(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("hidden quests must not query the room: %v", err)
+	}
+}
+
+func TestQuestHandler_UpdateQuestStatusValidation(t *testing.T) {
+	handler, mock, cleanup := newMockQuestHandler(t)
+	defer cleanup()
+
+	// Status inválido
+	expectCampaignAccess(mock, 10, 7, 7)
+	mock.ExpectQuery(`WHERE q.id = \$1 AND q.campaign_id = \$2`).WithArgs(1, 10).
+		WillReturnRows(questRow(1, "active", true))
+
+	req := withCampaignUser(httptest.NewRequest(http.MethodPut, "/api/campaigns/10/quests/1/status", bytes.NewBufferString(`{"status":"abandoned"}`)), "10", 7)
+	req = addChiURLParam(req, "questId", "1")
+	rr := httptest.NewRecorder()
+	handler.UpdateQuestStatus(rr, req)
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d", rr.Code)
+	}
+
+	// Players não alteram status
+	expectCampaignAccess(mock, 10, 8, 7, 8)
+
+	req = withCampaignUser(httptest.NewRequest(http.MethodPut, "/api/campaigns/10/quests/1/status", bytes.NewBufferString(`{"status":"completed"}`)), "10", 8)
+	req = addChiURLParam(req, "questId", "1")
+	rr = httptest.NewRecorder()
+	handler.UpdateQuestStatus(rr, req)
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", rr.Code)
+	}
+}
+
+func TestQuestHandler_UpdateObjectiveCompletion(t *testing.T) {
+	handler, mock, cleanup := newMockQuestHandler(t)
+	defer cleanup()
+
+	now := time.Now()
+	expectCampaignAccess(mock, 10, 7, 7)
+	mock.ExpectQuery(`WHERE q.id = \$1 AND q.campaign_id = \$2`).WithArgs(1, 10).
+		WillReturnRows(questRow(1, "active", true))
+	mock.ExpectQuery(`FROM quest_objectives WHERE id = \$1 AND quest_id = \$2`).WithArgs(2, 1).
+		WillReturnRows(sqlmock.NewRows(questObjectiveCols).AddRow(2, 1, "Find Cragmaw Castle", false, 1, nil, now))
+	mock.ExpectExec(`UPDATE quest_objectives SET`).
+		WithArgs("Find Cragmaw Castle", true, 1, sqlmock.AnyArg(), 2, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectCampaignRoom(mock, 10)
+
+	req := withCampaignUser(httptest.NewRequest(http.MethodPut, "/api/campaigns/10/quests/1/objectives/2", bytes.NewBufferString(`{"completed":true}`)), "10", 7)
+	req = addChiURLParam(req, "questId", "1")
+	req = addChiURLParam(req, "objectiveId", "2")
+	rr := httptest.NewRecorder()
+	handler.UpdateObjective(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var objective models.QuestObjective
+	if err := json.NewDecoder(rr.Body).Decode(&objective); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if !objective.Completed || objective.CompletedAt == nil {
+		t.Fatalf("expected completed objective, got %+v", objective)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
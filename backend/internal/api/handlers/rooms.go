@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -24,6 +25,8 @@ type RoomHandler struct {
 	DB       *db.PostgresDB
 	Response *utils.ResponseHandler
 	Hub      *RoomHub
+	Dice     DiceSource
+	Activity *ActivityLog // Registra as mudanças de cena no feed da campanha da sala
 }
 
 // NewRoomHandler creates a handler with DB persistence.
@@ -32,6 +35,7 @@ func NewRoomHandler(db *db.PostgresDB) *RoomHandler {
 		DB:       db,
 		Response: utils.NewResponseHandler(),
 		Hub:      NewRoomHub(),
+		Dice:     NewCryptoDiceSource(),
 	}
 }
 
@@ -206,6 +210,11 @@ func (h *RoomHandler) JoinRoom(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if room.ArchivedAt != nil {
+		h.Response.SendConflict(w, "room is archived: its campaign is completed")
+		return
+	}
+
 	member, err := h.DB.AddRoomMember(r.Context(), roomID, userID, roleForUser(userID, room.OwnerID))
 	if err != nil {
 		h.Response.HandleDBError(w, err, "add room member")
@@ -250,6 +259,11 @@ func (h *RoomHandler) UpdateScene(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if room.ArchivedAt != nil {
+		h.Response.SendConflict(w, "room is archived: its campaign is completed")
+		return
+	}
+
 	// Allow only members to update
 	isMember, err := h.DB.IsRoomMember(r.Context(), roomID, userID)
 	if err != nil {
@@ -277,6 +291,8 @@ func (h *RoomHandler) UpdateScene(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.recordSceneActivity(r.Context(), room, userID, "http")
+
 	members, _ := h.DB.ListRoomMembers(r.Context(), roomID)
 	updated.Members = members
 	h.Response.SendSuccess(w, "scene updated", updated)
@@ -327,6 +343,22 @@ func (h *RoomHandler) RoomWebsocket(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if room.ArchivedAt != nil {
+		http.Error(w, "room is archived: its campaign is completed", http.StatusConflict)
+		return
+	}
+	if room.CampaignID != nil {
+		status, err := h.DB.GetCampaignStatus(r.Context(), *room.CampaignID)
+		if err != nil {
+			http.Error(w, "campaign status check failed", http.StatusInternalServerError)
+			return
+		}
+		if status == models.CampaignStatusPaused {
+			http.Error(w, "campaign is paused", http.StatusConflict)
+			return
+		}
+	}
+
 	// Garantir membership e papel correto
 	_, _ = h.DB.AddRoomMember(r.Context(), roomID, userID, roleForUser(userID, room.OwnerID))
 
@@ -396,6 +428,11 @@ func (h *RoomHandler) RoomWebsocket(w http.ResponseWriter, r *http.Request) {
 
 		switch msg.Type {
 		case "chat:message":
+			if name, ok := parseMacroCommand(msg.Message); ok {
+				msg.Message = name
+				h.broadcastMacroRoll(r.Context(), conn, roomID, userID, msg)
+				continue
+			}
 			h.Hub.Broadcast(roomID, msg)
 		case "scene:update":
 			updated, err := h.DB.UpdateRoomScene(r.Context(), roomID, msg.SceneState, msg.Metadata)
@@ -405,6 +442,7 @@ func (h *RoomHandler) RoomWebsocket(w http.ResponseWriter, r *http.Request) {
 			}
 			if updated != nil {
 				msg.SceneState = updated.SceneState
+				h.recordSceneActivity(r.Context(), room, userID, "socket")
 			}
 			msg.Type = "scene:state"
 			h.Hub.Broadcast(roomID, msg)
@@ -417,6 +455,8 @@ func (h *RoomHandler) RoomWebsocket(w http.ResponseWriter, r *http.Request) {
 			})
 		case "dice:roll":
 			h.Hub.Broadcast(roomID, msg)
+		case "dice:macro":
+			h.broadcastMacroRoll(r.Context(), conn, roomID, userID, msg)
 		default:
 			// ignore unknown message types
 		}
@@ -490,6 +530,24 @@ func (h *RoomHub) Members(roomID string) []int {
 	return members
 }
 
+// CloseRoom envia msg a todos os conectados na sala, encerra as conexões e retorna quantas
+// foram fechadas. Usado quando a campanha é pausada ou concluída.
+func (h *RoomHub) CloseRoom(roomID string, msg RoomSocketMessage) int {
+	h.mu.Lock()
+	clients := make([]*SocketClient, 0, len(h.rooms[roomID]))
+	for _, client := range h.rooms[roomID] {
+		clients = append(clients, client)
+	}
+	delete(h.rooms, roomID)
+	h.mu.Unlock()
+
+	for _, client := range clients {
+		_ = client.Conn.WriteJSON(msg)
+		_ = client.Conn.Close()
+	}
+	return len(clients)
+}
+
 func (h *RoomHub) Broadcast(roomID string, msg RoomSocketMessage) {
 	h.mu.RLock()
 	clients := make([]*SocketClient, 0, len(h.rooms[roomID]))
@@ -506,6 +564,65 @@ func (h *RoomHub) Broadcast(roomID string, msg RoomSocketMessage) {
 	}
 }
 
+// broadcastMacroRoll rola uma macro salva do usuário (msg.Message = nome da macro,
+// metadata.pc_id opcional) e transmite o resultado como "dice:roll".
+func (h *RoomHandler) broadcastMacroRoll(ctx context.Context, conn *websocket.Conn, roomID string, userID int, msg RoomSocketMessage) {
+	name := strings.TrimSpace(msg.Message)
+	if name == "" {
+		writeSocketError(conn, "macro name is required")
+		return
+	}
+
+	var pcID *int
+	if raw, ok := msg.Metadata["pc_id"].(float64); ok {
+		id := int(raw)
+		pcID = &id
+	}
+
+	macro, err := h.DB.GetDiceMacroByName(ctx, userID, pcID, name)
+	if err != nil {
+		writeSocketError(conn, "failed to load macro")
+		return
+	}
+	if macro == nil {
+		writeSocketError(conn, fmt.Sprintf("macro %q not found", name))
+		return
+	}
+
+	result, err := rollMacro(h.Dice, macro)
+	if err != nil {
+		writeSocketError(conn, err.Error())
+		return
+	}
+
+	msg.Type = "dice:roll"
+	msg.Message = result.Label
+	msg.Metadata["macro_id"] = macro.ID
+	msg.Dice = map[string]any{
+		"notation":      result.Notation,
+		"rolls":         result.Rolls,
+		"dropped_rolls": result.DroppedRolls,
+		"modifier":      result.Modifier,
+		"total":         result.Total,
+		"label":         result.Label,
+		"advantage":     result.Advantage,
+		"disadvantage":  result.Disadvantage,
+		"macro":         macro.Name,
+	}
+	h.Hub.Broadcast(roomID, msg)
+}
+
+// parseMacroCommand reconhece o comando de chat "/macro <nome>"
+func parseMacroCommand(text string) (string, bool) {
+	const prefix = "/macro "
+	trimmed := strings.TrimSpace(text)
+	if !strings.HasPrefix(strings.ToLower(trimmed), prefix) {
+		return "", false
+	}
+	name := strings.TrimSpace(trimmed[len(prefix):])
+	return name, name != ""
+}
+
 func writeSocketError(conn *websocket.Conn, message string) {
 	_ = conn.WriteJSON(RoomSocketMessage{
 		Type:      "error",
@@ -521,6 +638,23 @@ func getUserIDFromContext(r *http.Request) (int, bool) {
 	return userID, ok
 }
 
+// recordSceneActivity registra a mudança de cena no feed da campanha, se a sala tiver uma
+func (h *RoomHandler) recordSceneActivity(ctx context.Context, room *models.Room, userID int, via string) {
+	if room.CampaignID == nil {
+		return
+	}
+
+	h.Activity.Record(ctx, models.CampaignActivity{
+		CampaignID: *room.CampaignID,
+		ActorID:    &userID,
+		EventType:  models.ActivitySceneUpdated,
+		TargetType: models.ActivityTargetRoom,
+		TargetID:   room.ID,
+		Summary:    "Updated the scene in " + room.Name,
+		Metadata:   models.JSONB{"via": via},
+	})
+}
+
 func roleForUser(userID, ownerID int) string {
 	if userID == ownerID {
 		return "gm"
@@ -0,0 +1,450 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"rpg-saas-backend/internal/db"
+	"rpg-saas-backend/internal/models"
+	"rpg-saas-backend/internal/utils"
+)
+
+// SessionHandler gerencia as sessões de jogo de uma campanha
+type SessionHandler struct {
+	DB        *db.PostgresDB
+	Response  *utils.ResponseHandler
+	Validator *utils.Validator
+	Hub       *RoomHub // Usado para avisar a sala da campanha sobre início/fim de sessão
+}
+
+// NewSessionHandler cria um handler de sessões ligado ao hub das salas
+func NewSessionHandler(db *db.PostgresDB, hub *RoomHub) *SessionHandler {
+	return &SessionHandler{
+		DB:        db,
+		Response:  utils.NewResponseHandler(),
+		Validator: utils.NewValidator(),
+		Hub:       hub,
+	}
+}
+
+// GetSessions lista as sessões da campanha
+func (h *SessionHandler) GetSessions(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	sessions, err := h.DB.GetCampaignSessions(r.Context(), campaign.ID)
+	if err != nil {
+		h.Response.HandleDBError(w, err, "fetch sessions")
+		return
+	}
+
+	if !canManageCampaign(campaign, userID) {
+		for i := range sessions {
+			sessions[i].DMNotes = ""
+		}
+	}
+
+	h.Response.SendJSON(w, map[string]any{
+		"sessions":        sessions,
+		"count":           len(sessions),
+		"current_session": campaign.CurrentSession,
+	}, http.StatusOK)
+}
+
+// GetSession retorna uma sessão com a lista de presença
+func (h *SessionHandler) GetSession(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	session, ok := h.loadSession(w, r, campaign.ID)
+	if !ok {
+		return
+	}
+
+	attendance, err := h.DB.GetSessionAttendance(r.Context(), session.ID)
+	if err != nil {
+		h.Response.HandleDBError(w, err, "fetch attendance")
+		return
+	}
+	session.Attendance = attendance
+
+	if !canManageCampaign(campaign, userID) {
+		hidePrivateSessionData(session, userID)
+	}
+
+	h.Response.SendJSON(w, session, http.StatusOK)
+}
+
+// CreateSession agenda uma nova sessão (apenas DM)
+func (h *SessionHandler) CreateSession(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	if !canManageCampaign(campaign, userID) {
+		h.Response.SendForbidden(w, "Only the DM can schedule sessions")
+		return
+	}
+
+	var req models.CreateSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Response.SendBadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := h.Validator.ValidateNonNegative(req.SessionNumber, "session_number"); err != nil {
+		h.Response.SendValidationError(w, err.Error())
+		return
+	}
+
+	session := &models.CampaignSession{
+		CampaignID:    campaign.ID,
+		SessionNumber: req.SessionNumber,
+		Title:         strings.TrimSpace(req.Title),
+		ScheduledAt:   req.ScheduledAt,
+		Status:        models.SessionStatusScheduled,
+	}
+
+	if err := h.DB.CreateCampaignSession(r.Context(), session); err != nil {
+		h.Response.HandleDBError(w, err, "create session")
+		return
+	}
+
+	h.Response.SendCreated(w, "Session scheduled successfully", session)
+}
+
+// UpdateSession edita título, data, notas do DM e recap (apenas DM)
+func (h *SessionHandler) UpdateSession(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	if !canManageCampaign(campaign, userID) {
+		h.Response.SendForbidden(w, "Only the DM can edit sessions")
+		return
+	}
+
+	session, ok := h.loadSession(w, r, campaign.ID)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Response.SendBadRequest(w, "Invalid request body")
+		return
+	}
+
+	// Início e fim passam pelos endpoints próprios, que avisam a sala
+	if req.Status != "" && req.Status != session.Status {
+		if err := h.Validator.ValidateChoice(req.Status, "status", []string{models.SessionStatusScheduled, models.SessionStatusCancelled}); err != nil {
+			h.Response.SendValidationError(w, err.Error()+" (use the start/end endpoints to run a session)")
+			return
+		}
+		if session.Status == models.SessionStatusInProgress {
+			h.Response.SendConflict(w, "End the session before changing its status")
+			return
+		}
+		session.Status = req.Status
+	}
+
+	if req.Title != "" {
+		session.Title = strings.TrimSpace(req.Title)
+	}
+	if req.ScheduledAt != nil {
+		session.ScheduledAt = req.ScheduledAt
+	}
+	if req.DMNotes != nil {
+		session.DMNotes = *req.DMNotes
+	}
+	if req.Recap != nil {
+		session.Recap = *req.Recap
+	}
+
+	if err := h.DB.UpdateCampaignSession(r.Context(), session); err != nil {
+		h.Response.HandleDBError(w, err, "update session")
+		return
+	}
+
+	h.Response.SendJSON(w, session, http.StatusOK)
+}
+
+// DeleteSession remove uma sessão (apenas DM)
+func (h *SessionHandler) DeleteSession(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	if !isCampaignDM(campaign, userID) {
+		h.Response.SendForbidden(w, "Only the DM can delete sessions")
+		return
+	}
+
+	sessionID, err := utils.ExtractIDParam(r, "sessionId")
+	if err != nil {
+		h.Response.SendBadRequest(w, err.Error())
+		return
+	}
+
+	if err := h.DB.DeleteCampaignSession(r.Context(), sessionID, campaign.ID); err != nil {
+		h.Response.SendNotFound(w, "Session not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// StartSession inicia a sessão, vincula à sala da campanha e avança current_session
+func (h *SessionHandler) StartSession(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	if !canManageCampaign(campaign, userID) {
+		h.Response.SendForbidden(w, "Only the DM can start a session")
+		return
+	}
+
+	session, ok := h.loadSession(w, r, campaign.ID)
+	if !ok {
+		return
+	}
+
+	if session.Status != models.SessionStatusScheduled {
+		h.Response.SendConflict(w, "Only scheduled sessions can be started (current status: "+session.Status+")")
+		return
+	}
+
+	room, err := h.DB.GetRoomByCampaignID(r.Context(), campaign.ID)
+	if err != nil {
+		h.Response.HandleDBError(w, err, "fetch campaign room")
+		return
+	}
+	if room != nil {
+		session.RoomID = &room.ID
+	}
+
+	err = h.DB.StartCampaignSession(r.Context(), session)
+	switch {
+	case errors.Is(err, db.ErrSessionInProgress):
+		h.Response.SendConflict(w, "Another session is already in progress for this campaign")
+		return
+	case errors.Is(err, db.ErrSessionNotScheduled):
+		h.Response.SendConflict(w, "Session is no longer scheduled; it may have been started concurrently")
+		return
+	case err != nil:
+		h.Response.HandleDBError(w, err, "start session")
+		return
+	}
+
+	h.broadcastSession(session, userID, "session:start")
+
+	h.Response.SendJSON(w, map[string]any{
+		"session":         session,
+		"current_session": max(campaign.CurrentSession, session.SessionNumber),
+	}, http.StatusOK)
+}
+
+// EndSession encerra a sessão em andamento, opcionalmente salvando o recap
+func (h *SessionHandler) EndSession(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	if !canManageCampaign(campaign, userID) {
+		h.Response.SendForbidden(w, "Only the DM can end a session")
+		return
+	}
+
+	session, ok := h.loadSession(w, r, campaign.ID)
+	if !ok {
+		return
+	}
+
+	if session.Status != models.SessionStatusInProgress {
+		h.Response.SendConflict(w, "Session is not in progress")
+		return
+	}
+
+	var req models.EndSessionRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.Response.SendBadRequest(w, "Invalid request body")
+			return
+		}
+	}
+	if req.Recap != "" {
+		session.Recap = req.Recap
+	}
+
+	if err := h.DB.EndCampaignSession(r.Context(), session); err != nil {
+		h.Response.HandleDBError(w, err, "end session")
+		return
+	}
+
+	h.broadcastSession(session, userID, "session:end")
+
+	h.Response.SendJSON(w, session, http.StatusOK)
+}
+
+// UpdateAttendance registra a presença. Jogadores confirmam ou recusam a própria
+// presença; o DM pode marcar qualquer status para qualquer jogador.
+func (h *SessionHandler) UpdateAttendance(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	session, ok := h.loadSession(w, r, campaign.ID)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateAttendanceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Response.SendBadRequest(w, "Invalid request body")
+		return
+	}
+
+	isDM := canManageCampaign(campaign, userID)
+	targetID := userID
+	if req.UserID != 0 && req.UserID != userID {
+		if !isDM {
+			h.Response.SendForbidden(w, "Only the DM can update another player's attendance")
+			return
+		}
+		targetID = req.UserID
+	}
+
+	allowed := models.AttendanceRSVPStatuses
+	if isDM {
+		allowed = models.AttendanceStatuses
+	}
+	if err := h.Validator.ValidateChoice(req.Status, "status", allowed); err != nil {
+		h.Response.SendValidationError(w, err.Error())
+		return
+	}
+
+	if !isActiveCampaignPlayer(campaign, targetID) {
+		h.Response.SendBadRequest(w, "User is not an active player in this campaign")
+		return
+	}
+
+	if err := h.DB.UpsertSessionAttendance(r.Context(), session.ID, targetID, req.Status); err != nil {
+		h.Response.HandleDBError(w, err, "update attendance")
+		return
+	}
+
+	h.Response.SendSuccess(w, "Attendance updated", map[string]any{
+		"session_id": session.ID,
+		"user_id":    targetID,
+		"status":     req.Status,
+	})
+}
+
+// UpdateSessionNotes salva as notas pessoais do jogador na sessão
+func (h *SessionHandler) UpdateSessionNotes(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	if !isActiveCampaignPlayer(campaign, userID) {
+		h.Response.SendForbidden(w, "Only players can keep session notes; the DM uses dm_notes")
+		return
+	}
+
+	session, ok := h.loadSession(w, r, campaign.ID)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateSessionNotesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Response.SendBadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := h.DB.UpsertSessionNotes(r.Context(), session.ID, userID, req.Notes); err != nil {
+		h.Response.HandleDBError(w, err, "update session notes")
+		return
+	}
+
+	h.Response.SendSuccess(w, "Session notes saved", map[string]any{
+		"session_id": session.ID,
+		"notes":      req.Notes,
+	})
+}
+
+// loadSession busca a sessão {sessionId} da campanha; envia 404 se não existir
+func (h *SessionHandler) loadSession(w http.ResponseWriter, r *http.Request, campaignID int) (*models.CampaignSession, bool) {
+	sessionID, err := utils.ExtractIDParam(r, "sessionId")
+	if err != nil {
+		h.Response.SendBadRequest(w, err.Error())
+		return nil, false
+	}
+
+	session, err := h.DB.GetCampaignSession(r.Context(), sessionID, campaignID)
+	if err != nil {
+		h.Response.HandleDBError(w, err, "fetch session")
+		return nil, false
+	}
+	if session == nil {
+		h.Response.SendNotFound(w, "Session not found")
+		return nil, false
+	}
+
+	return session, true
+}
+
+// broadcastSession avisa os conectados na sala da campanha sobre a mudança da sessão
+func (h *SessionHandler) broadcastSession(session *models.CampaignSession, senderID int, eventType string) {
+	if h.Hub == nil || session.RoomID == nil {
+		return
+	}
+
+	h.Hub.Broadcast(*session.RoomID, RoomSocketMessage{
+		Type:     eventType,
+		RoomID:   *session.RoomID,
+		SenderID: senderID,
+		Message:  session.Title,
+		Metadata: map[string]any{
+			"session_id":     session.ID,
+			"session_number": session.SessionNumber,
+			"status":         session.Status,
+		},
+		Timestamp: time.Now().UnixMilli(),
+	})
+}
+
+// hidePrivateSessionData remove as notas do DM e as notas de outros jogadores
+func hidePrivateSessionData(session *models.CampaignSession, userID int) {
+	session.DMNotes = ""
+	for i := range session.Attendance {
+		if session.Attendance[i].UserID != userID {
+			session.Attendance[i].Notes = ""
+		}
+	}
+}
+
+// isActiveCampaignPlayer indica se o usuário é jogador ativo da campanha
+func isActiveCampaignPlayer(campaign *models.Campaign, userID int) bool {
+	for _, player := range campaign.Players {
+		if player.UserID == userID && player.Status == "active" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,289 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+
+	"rpg-saas-backend/internal/db"
+	"rpg-saas-backend/internal/models"
+)
+
+var sessionCols = []string{
+	"id", "campaign_id", "session_number", "title", "scheduled_at", "status", "room_id",
+	"started_at", "ended_at", "dm_notes", "recap", "created_at", "updated_at",
+}
+
+func newMockSessionHandler(t *testing.T) (*SessionHandler, sqlmock.Sqlmock, func()) {
+	t.Helper()
+
+	rawDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+
+	pdb := &db.PostgresDB{DB: sqlx.NewDb(rawDB, "postgres")}
+	return NewSessionHandler(pdb, NewRoomHub()), mock, func() { rawDB.Close() }
+}
+
+func sessionRow(id, number int, status string) *sqlmock.Rows {
+	now := time.Now()
+	return sqlmock.NewRows(sessionCols).
+		AddRow(id, 10, number, "Session", now, status, nil, nil, nil, "secret plans", "", now, now)
+}
+
+func TestSessionHandler_CreateSession(t *testing.T) {
+	handler, mock, cleanup := newMockSessionHandler(t)
+	defer cleanup()
+
+	expectCampaignAccess(mock, 10, 7, 7)
+	mock.ExpectQuery(`INSERT INTO campaign_sessions`).
+		WithArgs(10, 0, "Into the mines", sqlmock.AnyArg(), "scheduled", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "session_number"}).AddRow(1, 3))
+
+	body := bytes.NewBufferString(`{"title":"Into the mines","scheduled_at":"2026-11-01T19:00:00Z"}`)
+	req := withCampaignUser(httptest.NewRequest(http.MethodPost, "/api/campaigns/10/sessions", body), "10", 7)
+	rr := httptest.NewRecorder()
+	handler.CreateSession(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestSessionHandler_CreateSession_PlayerForbidden(t *testing.T) {
+	handler, mock, cleanup := newMockSessionHandler(t)
+	defer cleanup()
+
+	expectCampaignAccess(mock, 10, 8, 7, 8)
+
+	req := withCampaignUser(httptest.NewRequest(http.MethodPost, "/api/campaigns/10/sessions", bytes.NewBufferString(`{}`)), "10", 8)
+	rr := httptest.NewRecorder()
+	handler.CreateSession(rr, req)
+
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", rr.Code)
+	}
+}
+
+func TestSessionHandler_StartSessionAdvancesCampaign(t *testing.T) {
+	handler, mock, cleanup := newMockSessionHandler(t)
+	defer cleanup()
+
+	expectCampaignAccess(mock, 10, 7, 7)
+	mock.ExpectQuery(`FROM campaign_sessions\s+WHERE id = \$1 AND campaign_id = \$2`).WithArgs(4, 10).
+		WillReturnRows(sessionRow(4, 3, "scheduled"))
+	now := time.Now()
+	mock.ExpectQuery(`FROM rooms`).WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "owner_id", "campaign_id", "scene_state", "metadata", "created_at", "updated_at"}).
+			AddRow("room-1", "Table", 7, 10, []byte(`{}`), []byte(`{}`), now, now))
+	mock.ExpectBegin()
+	mock.ExpectExec(`SELECT id FROM campaigns WHERE id = \$1 FOR UPDATE`).WithArgs(10).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`status = 'in_progress'`).WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectExec(`UPDATE campaign_sessions SET\s+status = \$1, started_at = \$2, room_id = \$3`).
+		WithArgs("in_progress", sqlmock.AnyArg(), "room-1", sqlmock.AnyArg(), 4, 10).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE campaigns SET\s+current_session = GREATEST\(current_session, \$1\)`).
+		WithArgs(3, sqlmock.AnyArg(), 10).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	req := withCampaignUser(httptest.NewRequest(http.MethodPost, "/api/campaigns/10/sessions/4/start", nil), "10", 7)
+	req = addChiURLParam(req, "sessionId", "4")
+	rr := httptest.NewRecorder()
+	handler.StartSession(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var resp struct {
+		Session        models.CampaignSession `json:"session"`
+		CurrentSession int                    `json:"current_session"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.CurrentSession != 3 || resp.Session.Status != models.SessionStatusInProgress || resp.Session.RoomID == nil {
+		t.Fatalf("unexpected start response: %+v", resp)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestSessionHandler_StartSessionConflicts(t *testing.T) {
+	handler, mock, cleanup := newMockSessionHandler(t)
+	defer cleanup()
+
+	// Sessão já concluída
+	expectCampaignAccess(mock, 10, 7, 7)
+	mock.ExpectQuery(`FROM campaign_sessions`).WithArgs(4, 10).WillReturnRows(sessionRow(4, 3, "completed"))
+
+	req := withCampaignUser(httptest.NewRequest(http.MethodPost, "/api/campaigns/10/sessions/4/start", nil), "10", 7)
+	req = addChiURLParam(req, "sessionId", "4")
+	rr := httptest.NewRecorder()
+	handler.StartSession(rr, req)
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for completed session, got %d", rr.Code)
+	}
+
+	// Outra sessão em andamento
+	expectCampaignAccess(mock, 10, 7, 7)
+	mock.ExpectQuery(`FROM campaign_sessions`).WithArgs(5, 10).WillReturnRows(sessionRow(5, 4, "scheduled"))
+	mock.ExpectQuery(`FROM rooms`).WithArgs(10).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectBegin()
+	mock.ExpectExec(`SELECT id FROM campaigns WHERE id = \$1 FOR UPDATE`).WithArgs(10).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`status = 'in_progress'`).WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectRollback()
+
+	req = withCampaignUser(httptest.NewRequest(http.MethodPost, "/api/campaigns/10/sessions/5/start", nil), "10", 7)
+	req = addChiURLParam(req, "sessionId", "5")
+	rr = httptest.NewRecorder()
+	handler.StartSession(rr, req)
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected 409 with session in progress, got %d", rr.Code)
+	}
+
+	// Sessão iniciada por outra requisição entre a leitura e o UPDATE
+	expectCampaignAccess(mock, 10, 7, 7)
+	mock.ExpectQuery(`FROM campaign_sessions`).WithArgs(6, 10).WillReturnRows(sessionRow(6, 5, "scheduled"))
+	mock.ExpectQuery(`FROM rooms`).WithArgs(10).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectBegin()
+	mock.ExpectExec(`SELECT id FROM campaigns WHERE id = \$1 FOR UPDATE`).WithArgs(10).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`status = 'in_progress'`).WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectExec(`UPDATE campaign_sessions SET\s+status = \$1`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	req = withCampaignUser(httptest.NewRequest(http.MethodPost, "/api/campaigns/10/sessions/6/start", nil), "10", 7)
+	req = addChiURLParam(req, "sessionId", "6")
+	rr = httptest.NewRecorder()
+	handler.StartSession(rr, req)
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected 409 when the session is no longer scheduled, got %d", rr.Code)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestSessionHandler_EndSessionWithRecap(t *testing.T) {
+	handler, mock, cleanup := newMockSessionHandler(t)
+	defer cleanup()
+
+	expectCampaignAccess(mock, 10, 7, 7)
+	mock.ExpectQuery(`FROM campaign_sessions`).WithArgs(4, 10).WillReturnRows(sessionRow(4, 3, "in_progress"))
+	mock.ExpectExec(`UPDATE campaign_sessions SET\s+status = \$1, ended_at = \$2, recap = \$3`).
+		WithArgs("completed", sqlmock.AnyArg(), "The party found the lost forge", sqlmock.AnyArg(), 4, 10).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	body := bytes.NewBufferString(`{"recap":"The party found the lost forge"}`)
+	req := withCampaignUser(httptest.NewRequest(http.MethodPost, "/api/campaigns/10/sessions/4/end", body), "10", 7)
+	req = addChiURLParam(req, "sessionId", "4")
+	rr := httptest.NewRecorder()
+	handler.EndSession(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestSessionHandler_GetSessionHidesDMNotes(t *testing.T) {
+	handler, mock, cleanup := newMockSessionHandler(t)
+	defer cleanup()
+
+	expectCampaignAccess(mock, 10, 8, 7, 8, 9)
+	mock.ExpectQuery(`FROM campaign_sessions`).WithArgs(4, 10).WillReturnRows(sessionRow(4, 3, "completed"))
+	now := time.Now()
+	mock.ExpectQuery(`FROM session_attendance sa`).WithArgs(4).
+		WillReturnRows(sqlmock.NewRows([]string{"session_id", "user_id", "username", "status", "notes", "updated_at"}).
+			AddRow(4, 8, "me", "attended", "my notes", now).
+			AddRow(4, 9, "other", "attended", "their notes", now))
+
+	req := withCampaignUser(httptest.NewRequest(http.MethodGet, "/api/campaigns/10/sessions/4", nil), "10", 8)
+	req = addChiURLParam(req, "sessionId", "4")
+	rr := httptest.NewRecorder()
+	handler.GetSession(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+
+	var session models.CampaignSession
+	if err := json.NewDecoder(rr.Body).Decode(&session); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if session.DMNotes != "" {
+		t.Fatal("players must not see DM notes")
+	}
+	if session.Attendance[0].Notes != "my notes" || session.Attendance[1].Notes != "" {
+		t.Fatalf("players should only see their own notes: %+v", session.Attendance)
+	}
+}
+
+func TestSessionHandler_UpdateAttendance(t *testing.T) {
+	handler, mock, cleanup := newMockSessionHandler(t)
+	defer cleanup()
+
+	// Jogador confirma a própria presença
+	expectCampaignAccess(mock, 10, 8, 7, 8, 9)
+	mock.ExpectQuery(`FROM campaign_sessions`).WithArgs(4, 10).WillReturnRows(sessionRow(4, 3, "scheduled"))
+	mock.ExpectExec(`INSERT INTO session_attendance`).WithArgs(4, 8, "confirmed", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	req := withCampaignUser(httptest.NewRequest(http.MethodPut, "/api/campaigns/10/sessions/4/attendance", bytes.NewBufferString(`{"status":"confirmed"}`)), "10", 8)
+	req = addChiURLParam(req, "sessionId", "4")
+	rr := httptest.NewRecorder()
+	handler.UpdateAttendance(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	// Jogador não pode alterar a presença de outro
+	expectCampaignAccess(mock, 10, 8, 7, 8, 9)
+	mock.ExpectQuery(`FROM campaign_sessions`).WithArgs(4, 10).WillReturnRows(sessionRow(4, 3, "scheduled"))
+
+	req = withCampaignUser(httptest.NewRequest(http.MethodPut, "/api/campaigns/10/sessions/4/attendance", bytes.NewBufferString(`{"user_id":9,"status":"declined"}`)), "10", 8)
+	req = addChiURLParam(req, "sessionId", "4")
+	rr = httptest.NewRecorder()
+	handler.UpdateAttendance(rr, req)
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", rr.Code)
+	}
+
+	// DM marca ausência
+	expectCampaignAccess(mock, 10, 7, 7, 8, 9)
+	mock.ExpectQuery(`FROM campaign_sessions`).WithArgs(4, 10).WillReturnRows(sessionRow(4, 3, "completed"))
+	mock.ExpectExec(`INSERT INTO session_attendance`).WithArgs(4, 9, "absent", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	req = withCampaignUser(httptest.NewRequest(http.MethodPut, "/api/campaigns/10/sessions/4/attendance", bytes.NewBufferString(`{"user_id":9,"status":"absent"}`)), "10", 7)
+	req = addChiURLParam(req, "sessionId", "4")
+	rr = httptest.NewRecorder()
+	handler.UpdateAttendance(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 for DM, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
@@ -0,0 +1,409 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"rpg-saas-backend/internal/db"
+	"rpg-saas-backend/internal/models"
+	"rpg-saas-backend/internal/utils"
+)
+
+// Limite do corpo Markdown de uma entrada (em bytes)
+const maxWikiBodyLength = 100000
+
+// WikiHandler gerencia o wiki/diário da campanha
+type WikiHandler struct {
+	DB        *db.PostgresDB
+	Response  *utils.ResponseHandler
+	Validator *utils.Validator
+}
+
+func NewWikiHandler(db *db.PostgresDB) *WikiHandler {
+	return &WikiHandler{
+		DB:        db,
+		Response:  utils.NewResponseHandler(),
+		Validator: utils.NewValidator(),
+	}
+}
+
+// GetEntries lista as entradas visíveis ao usuário (filtros ?type=, ?tag=, ?q=)
+func (h *WikiHandler) GetEntries(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	filters := models.WikiFilters{
+		EntryType: r.URL.Query().Get("type"),
+		Tag:       strings.TrimSpace(r.URL.Query().Get("tag")),
+		Search:    strings.TrimSpace(r.URL.Query().Get("q")),
+	}
+
+	if filters.EntryType != "" {
+		if err := h.Validator.ValidateChoice(filters.EntryType, "type", models.WikiEntryTypes); err != nil {
+			h.Response.SendBadRequest(w, err.Error())
+			return
+		}
+	}
+
+	isDM := canManageCampaign(campaign, userID)
+	entries, err := h.DB.GetWikiEntries(r.Context(), campaign.ID, userID, isDM, filters)
+	if err != nil {
+		h.Response.HandleDBError(w, err, "fetch wiki entries")
+		return
+	}
+
+	// Players não veem com quem cada entrada foi compartilhada nem links para entradas ocultas
+	if !isDM {
+		visible, err := h.visibleLinkedIDs(r, campaign.ID, userID, entries)
+		if err != nil {
+			h.Response.HandleDBError(w, err, "fetch linked wiki entries")
+			return
+		}
+		for i := range entries {
+			entries[i].SelectedPlayers = nil
+			entries[i].LinkedEntryIDs = filterLinkedIDs(entries[i].LinkedEntryIDs, visible)
+		}
+	}
+
+	h.Response.SendJSON(w, map[string]any{
+		"entries": entries,
+		"count":   len(entries),
+	}, http.StatusOK)
+}
+
+// GetEntry retorna uma entrada com os links e backlinks visíveis ao usuário
+func (h *WikiHandler) GetEntry(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	isDM := canManageCampaign(campaign, userID)
+	entry, ok := h.loadEntry(w, r, campaign.ID)
+	if !ok {
+		return
+	}
+
+	// Entradas ocultas respondem 404 para não revelar que existem
+	if !entry.CanView(userID, isDM) {
+		h.Response.SendNotFound(w, "Wiki entry not found")
+		return
+	}
+
+	linked, err := h.DB.GetWikiEntriesByIDs(r.Context(), campaign.ID, entry.LinkedEntryIDs)
+	if err != nil {
+		h.Response.HandleDBError(w, err, "fetch linked wiki entries")
+		return
+	}
+
+	backlinks, err := h.DB.GetWikiBacklinks(r.Context(), campaign.ID, entry.ID)
+	if err != nil {
+		h.Response.HandleDBError(w, err, "fetch wiki backlinks")
+		return
+	}
+
+	entry.LinkedEntries = visibleWikiSummaries(linked, userID, isDM)
+	entry.Backlinks = visibleWikiSummaries(backlinks, userID, isDM)
+
+	if !isDM {
+		entry.SelectedPlayers = nil
+		entry.LinkedEntryIDs = summaryIDs(entry.LinkedEntries)
+	}
+
+	h.Response.SendJSON(w, entry, http.StatusOK)
+}
+
+// CreateEntry cria uma entrada no wiki (apenas DM)
+func (h *WikiHandler) CreateEntry(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	if !canManageCampaign(campaign, userID) {
+		h.Response.SendForbidden(w, "Only the DM can create wiki entries")
+		return
+	}
+
+	var req models.WikiEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Response.SendBadRequest(w, "Invalid request body")
+		return
+	}
+
+	entry := &models.WikiEntry{CampaignID: campaign.ID, CreatedBy: &userID}
+	if !h.applyEntryRequest(w, r, campaign, entry, &req) {
+		return
+	}
+
+	if err := h.DB.CreateWikiEntry(r.Context(), entry); err != nil {
+		h.Response.HandleDBError(w, err, "create wiki entry")
+		return
+	}
+
+	h.Response.SendCreated(w, "Wiki entry created successfully", entry)
+}
+
+// UpdateEntry substitui o conteúdo de uma entrada (apenas DM)
+func (h *WikiHandler) UpdateEntry(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	if !canManageCampaign(campaign, userID) {
+		h.Response.SendForbidden(w, "Only the DM can edit wiki entries")
+		return
+	}
+
+	entry, ok := h.loadEntry(w, r, campaign.ID)
+	if !ok {
+		return
+	}
+
+	var req models.WikiEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Response.SendBadRequest(w, "Invalid request body")
+		return
+	}
+
+	if !h.applyEntryRequest(w, r, campaign, entry, &req) {
+		return
+	}
+
+	if err := h.DB.UpdateWikiEntry(r.Context(), entry); err != nil {
+		h.Response.HandleDBError(w, err, "update wiki entry")
+		return
+	}
+
+	h.Response.SendJSON(w, entry, http.StatusOK)
+}
+
+// DeleteEntry remove uma entrada e os links para ela (apenas DM)
+func (h *WikiHandler) DeleteEntry(w http.ResponseWriter, r *http.Request) {
+	campaign, userID, ok := loadCampaignForUser(w, r, h.DB, h.Response)
+	if !ok {
+		return
+	}
+
+	if !isCampaignDM(campaign, userID) {
+		h.Response.SendForbidden(w, "Only the DM can delete wiki entries")
+		return
+	}
+
+	entryID, err := utils.ExtractIDParam(r, "entryId")
+	if err != nil {
+		h.Response.SendBadRequest(w, err.Error())
+		return
+	}
+
+	if err := h.DB.DeleteWikiEntry(r.Context(), entryID, campaign.ID); err != nil {
+		h.Response.SendNotFound(w, "Wiki entry not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// loadEntry busca a entrada {entryId} da campanha; envia 404 se não existir
+func (h *WikiHandler) loadEntry(w http.ResponseWriter, r *http.Request, campaignID int) (*models.WikiEntry, bool) {
+	entryID, err := utils.ExtractIDParam(r, "entryId")
+	if err != nil {
+		h.Response.SendBadRequest(w, err.Error())
+		return nil, false
+	}
+
+	entry, err := h.DB.GetWikiEntry(r.Context(), entryID, campaignID)
+	if err != nil {
+		h.Response.HandleDBError(w, err, "fetch wiki entry")
+		return nil, false
+	}
+	if entry == nil {
+		h.Response.SendNotFound(w, "Wiki entry not found")
+		return nil, false
+	}
+
+	return entry, true
+}
+
+// applyEntryRequest valida o payload e copia os dados para a entrada; envia a resposta de erro quando inválido
+func (h *WikiHandler) applyEntryRequest(w http.ResponseWriter, r *http.Request, campaign *models.Campaign, entry *models.WikiEntry, req *models.WikiEntryRequest) bool {
+	if req.Visibility == "" {
+		req.Visibility = models.WikiVisibilityDMOnly
+	}
+
+	validationErrors := h.Validator.BatchValidate(
+		func() error { return h.Validator.ValidateChoice(req.EntryType, "entry_type", models.WikiEntryTypes) },
+		func() error { return h.Validator.ValidateName(req.Title, "title") },
+		func() error { return h.Validator.ValidateChoice(req.Visibility, "visibility", models.WikiVisibilities) },
+		func() error {
+			if len(req.Body) > maxWikiBodyLength {
+				return utils.ValidationError{
+					Field:   "body",
+					Message: fmt.Sprintf("body must be at most %d characters", maxWikiBodyLength),
+					Code:    "max_length",
+				}
+			}
+			return nil
+		},
+		func() error {
+			if req.Visibility == models.WikiVisibilitySelected && len(req.SelectedPlayers) == 0 {
+				return utils.ValidationError{
+					Field:   "selected_players",
+					Message: "selected_players is required when visibility is selected",
+					Code:    "required",
+				}
+			}
+			return nil
+		},
+		func() error {
+			for _, playerID := range req.SelectedPlayers {
+				if !isActiveCampaignPlayer(campaign, playerID) {
+					return utils.ValidationError{
+						Field:   "selected_players",
+						Message: fmt.Sprintf("user %d is not an active player in this campaign", playerID),
+						Code:    "invalid_player",
+					}
+				}
+			}
+			return nil
+		},
+		func() error {
+			if entry.ID != 0 && slices.Contains(req.LinkedEntryIDs, entry.ID) {
+				return utils.ValidationError{
+					Field:   "linked_entry_ids",
+					Message: "an entry cannot link to itself",
+					Code:    "invalid_link",
+				}
+			}
+			return nil
+		},
+	)
+
+	if validationErrors.HasErrors() {
+		h.Response.SendValidationError(w, validationErrors.Error())
+		return false
+	}
+
+	links := toInt64Array(req.LinkedEntryIDs)
+	if len(links) > 0 {
+		existing, err := h.DB.GetWikiEntriesByIDs(r.Context(), campaign.ID, links)
+		if err != nil {
+			h.Response.HandleDBError(w, err, "fetch linked wiki entries")
+			return false
+		}
+		if len(existing) != len(links) {
+			h.Response.SendValidationError(w, "linked_entry_ids: "+missingWikiLinks(links, existing))
+			return false
+		}
+	}
+
+	entry.EntryType = req.EntryType
+	entry.Title = strings.TrimSpace(req.Title)
+	entry.Body = req.Body
+	entry.Tags = normalizeWikiTags(req.Tags)
+	entry.Visibility = req.Visibility
+	entry.SelectedPlayers = pq.Int64Array{}
+	if req.Visibility == models.WikiVisibilitySelected {
+		entry.SelectedPlayers = toInt64Array(req.SelectedPlayers)
+	}
+	entry.LinkedEntryIDs = links
+
+	return true
+}
+
+// normalizeWikiTags deixa as tags em minúsculas, sem espaços e sem repetição
+func normalizeWikiTags(tags []string) pq.StringArray {
+	normalized := pq.StringArray{}
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !slices.Contains(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized
+}
+
+// toInt64Array converte IDs removendo repetições, mantendo a ordem
+func toInt64Array(ids []int) pq.Int64Array {
+	result := pq.Int64Array{}
+	for _, id := range ids {
+		if !slices.Contains(result, int64(id)) {
+			result = append(result, int64(id))
+		}
+	}
+	return result
+}
+
+// missingWikiLinks descreve os IDs de link que não pertencem à campanha
+func missingWikiLinks(ids pq.Int64Array, existing []models.WikiEntry) string {
+	var missing []string
+	for _, id := range ids {
+		found := slices.ContainsFunc(existing, func(e models.WikiEntry) bool { return int64(e.ID) == id })
+		if !found {
+			missing = append(missing, fmt.Sprint(id))
+		}
+	}
+	return "entries not found in this campaign: " + strings.Join(missing, ", ")
+}
+
+// visibleWikiSummaries filtra as entradas visíveis ao usuário e as resume
+func visibleWikiSummaries(entries []models.WikiEntry, userID int, isDM bool) []models.WikiEntrySummary {
+	summaries := []models.WikiEntrySummary{}
+	for i := range entries {
+		if entries[i].CanView(userID, isDM) {
+			summaries = append(summaries, entries[i].Summary())
+		}
+	}
+	return summaries
+}
+
+// visibleLinkedIDs busca de uma vez as entradas linkadas pela lista e retorna as que o player vê
+func (h *WikiHandler) visibleLinkedIDs(r *http.Request, campaignID, userID int, entries []models.WikiEntry) (map[int64]bool, error) {
+	ids := []int64{}
+	seen := map[int64]bool{}
+	for _, entry := range entries {
+		for _, id := range entry.LinkedEntryIDs {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	linked, err := h.DB.GetWikiEntriesByIDs(r.Context(), campaignID, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := map[int64]bool{}
+	for _, summary := range visibleWikiSummaries(linked, userID, false) {
+		visible[int64(summary.ID)] = true
+	}
+	return visible, nil
+}
+
+func filterLinkedIDs(ids pq.Int64Array, visible map[int64]bool) pq.Int64Array {
+	filtered := pq.Int64Array{}
+	for _, id := range ids {
+		if visible[id] {
+			filtered = append(filtered, id)
+		}
+	}
+	return filtered
+}
+
+func summaryIDs(summaries []models.WikiEntrySummary) pq.Int64Array {
+	ids := pq.Int64Array{}
+	for _, summary := range summaries {
+		ids = append(ids, int64(summary.ID))
+	}
+	return ids
+}
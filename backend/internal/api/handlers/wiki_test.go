@@ -0,0 +1,330 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+
+	"rpg-saas-backend/internal/db"
+	"rpg-saas-backend/internal/models"
+)
+
+var wikiCols = []string{
+	"id", "campaign_id", "entry_type", "title", "body", "tags", "visibility",
+	"selected_players", "linked_entry_ids", "created_by", "created_at", "updated_at",
+}
+
+func newMockWikiHandler(t *testing.T) (*WikiHandler, sqlmock.Sqlmock, func()) {
+	t.Helper()
+
+	rawDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+
+	pdb := &db.PostgresDB{DB: sqlx.NewDb(rawDB, "postgres")}
+	return NewWikiHandler(pdb), mock, func() { rawDB.Close() }
+}
+
+func addWikiRow(rows *sqlmock.Rows, id int, title, visibility, selected, links string) *sqlmock.Rows {
+	now := time.Now()
+	return rows.AddRow(id, 10, "location", title, "# "+title, "{city}", visibility, selected, links, 7, now, now)
+}
+
+func TestWikiHandler_GetEntriesFiltersForPlayers(t *testing.T) {
+	handler, mock, cleanup := newMockWikiHandler(t)
+	defer cleanup()
+
+	expectCampaignAccess(mock, 10, 8, 7, 8)
+	mock.ExpectQuery(`visibility = 'all' OR \(visibility = 'selected' AND \$2 = ANY\(selected_players\)\).*entry_type = \$3.*\$4 = ANY\(tags\)`).
+		WithArgs(10, 8, "location", "city").
+		WillReturnRows(addWikiRow(sqlmock.NewRows(wikiCols), 1, "Waterdeep", "all", "{}", "{}"))
+
+	req := withCampaignUser(httptest.NewRequest(http.MethodGet, "/api/campaigns/10/wiki?type=location&tag=City", nil), "10", 8)
+	rr := httptest.NewRecorder()
+	handler.GetEntries(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestWikiHandler_GetEntriesSearchEscapesWildcards(t *testing.T) {
+	handler, mock, cleanup := newMockWikiHandler(t)
+	defer cleanup()
+
+	expectCampaignAccess(mock, 10, 8, 7, 8)
+	mock.ExpectQuery(`title ILIKE \$3 OR body ILIKE \$3`).
+		WithArgs(10, 8, `%100\%\_off%`).
+		WillReturnRows(addWikiRow(sqlmock.NewRows(wikiCols), 1, "Secret Vault", "selected", "{8,9}", "{}"))
+
+	req := withCampaignUser(httptest.NewRequest(http.MethodGet, "/api/campaigns/10/wiki?q=100%25_off", nil), "10", 8)
+	rr := httptest.NewRecorder()
+	handler.GetEntries(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var resp struct {
+		Entries []map[string]any `json:"entries"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(resp.Entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(resp.Entries))
+	}
+	if _, ok := resp.Entries[0]["selected_players"]; ok {
+		t.Fatalf("selected_players must be hidden from players: %v", resp.Entries[0])
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestWikiHandler_GetEntriesFiltersLinksForPlayers(t *testing.T) {
+	handler, mock, cleanup := newMockWikiHandler(t)
+	defer cleanup()
+
+	expectCampaignAccess(mock, 10, 8, 7, 8)
+	entries := sqlmock.NewRows(wikiCols)
+	addWikiRow(entries, 1, "Waterdeep", "all", "{}", "{2,3}")
+	addWikiRow(entries, 2, "Yawning Portal", "all", "{}", "{3}")
+	mock.ExpectQuery(`FROM campaign_wiki_entries`).WithArgs(10, 8).WillReturnRows(entries)
+	linked := sqlmock.NewRows(wikiCols)
+	addWikiRow(linked, 2, "Yawning Portal", "all", "{}", "{3}")
+	addWikiRow(linked, 3, "Xanathar Hideout", "dm_only", "{}", "{}")
+	mock.ExpectQuery(`id = ANY\(\$2\)`).WithArgs(10, sqlmock.AnyArg()).WillReturnRows(linked)
+
+	req := withCampaignUser(httptest.NewRequest(http.MethodGet, "/api/campaigns/10/wiki", nil), "10", 8)
+	rr := httptest.NewRecorder()
+	handler.GetEntries(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var resp struct {
+		Entries []models.WikiEntry `json:"entries"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(resp.Entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(resp.Entries))
+	}
+	if ids := resp.Entries[0].LinkedEntryIDs; len(ids) != 1 || ids[0] != 2 {
+		t.Fatalf("hidden link IDs must not leak: %v", ids)
+	}
+	if ids := resp.Entries[1].LinkedEntryIDs; len(ids) != 0 {
+		t.Fatalf("hidden link IDs must not leak: %v", ids)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestWikiHandler_GetEntriesInvalidType(t *testing.T) {
+	handler, mock, cleanup := newMockWikiHandler(t)
+	defer cleanup()
+
+	expectCampaignAccess(mock, 10, 7, 7)
+
+	req := withCampaignUser(httptest.NewRequest(http.MethodGet, "/api/campaigns/10/wiki?type=monster", nil), "10", 7)
+	rr := httptest.NewRecorder()
+	handler.GetEntries(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rr.Code)
+	}
+}
+
+func TestWikiHandler_GetEntryHiddenFromPlayer(t *testing.T) {
+	handler, mock, cleanup := newMockWikiHandler(t)
+	defer cleanup()
+
+	expectCampaignAccess(mock, 10, 8, 7, 8, 9)
+	mock.ExpectQuery(`FROM campaign_wiki_entries WHERE id = \$1 AND campaign_id = \$2`).WithArgs(3, 10).
+		WillReturnRows(addWikiRow(sqlmock.NewRows(wikiCols), 3, "Secret Lair", "selected", "{9}", "{}"))
+
+	req := withCampaignUser(httptest.NewRequest(http.MethodGet, "/api/campaigns/10/wiki/3", nil), "10", 8)
+	req = addChiURLParam(req, "entryId", "3")
+	rr := httptest.NewRecorder()
+	handler.GetEntry(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for hidden entry, got %d", rr.Code)
+	}
+}
+
+func TestWikiHandler_GetEntryFiltersLinks(t *testing.T) {
+	handler, mock, cleanup := newMockWikiHandler(t)
+	defer cleanup()
+
+	expectCampaignAccess(mock, 10, 8, 7, 8)
+	mock.ExpectQuery(`FROM campaign_wiki_entries WHERE id = \$1 AND campaign_id = \$2`).WithArgs(1, 10).
+		WillReturnRows(addWikiRow(sqlmock.NewRows(wikiCols), 1, "Waterdeep", "all", "{}", "{2,3}"))
+	linked := sqlmock.NewRows(wikiCols)
+	addWikiRow(linked, 2, "Yawning Portal", "all", "{}", "{}")
+	addWikiRow(linked, 3, "Xanathar Hideout", "dm_only", "{}", "{}")
+	mock.ExpectQuery(`id = ANY\(\$2\)`).WithArgs(10, sqlmock.AnyArg()).WillReturnRows(linked)
+	mock.ExpectQuery(`\$2 = ANY\(linked_entry_ids\)`).WithArgs(10, 1).
+		WillReturnRows(addWikiRow(sqlmock.NewRows(wikiCols), 4, "Sword Coast", "selected", "{8}", "{1}"))
+
+	req := withCampaignUser(httptest.NewRequest(http.MethodGet, "/api/campaigns/10/wiki/1", nil), "10", 8)
+	req = addChiURLParam(req, "entryId", "1")
+	rr := httptest.NewRecorder()
+	handler.GetEntry(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var entry models.WikiEntry
+	if err := json.NewDecoder(rr.Body).Decode(&entry); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(entry.LinkedEntries) != 1 || entry.LinkedEntries[0].ID != 2 {
+		t.Fatalf("dm_only links must be hidden from players: %+v", entry.LinkedEntries)
+	}
+	if len(entry.LinkedEntryIDs) != 1 || entry.LinkedEntryIDs[0] != 2 {
+		t.Fatalf("hidden link IDs must not leak: %v", entry.LinkedEntryIDs)
+	}
+	if len(entry.Backlinks) != 1 || entry.Backlinks[0].ID != 4 {
+		t.Fatalf("expected backlink shared with player, got %+v", entry.Backlinks)
+	}
+}
+
+func TestWikiHandler_CreateEntry(t *testing.T) {
+	handler, mock, cleanup := newMockWikiHandler(t)
+	defer cleanup()
+
+	expectCampaignAccess(mock, 10, 7, 7, 8)
+	mock.ExpectQuery(`id = ANY\(\$2\)`).WithArgs(10, sqlmock.AnyArg()).
+		WillReturnRows(addWikiRow(sqlmock.NewRows(wikiCols), 2, "Yawning Portal", "all", "{}", "{}"))
+	mock.ExpectQuery(`INSERT INTO campaign_wiki_entries`).
+		WithArgs(10, "faction", "Zhentarim", "**Black Network**", sqlmock.AnyArg(), "selected",
+			sqlmock.AnyArg(), sqlmock.AnyArg(), 7, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+
+	body := bytes.NewBufferString(`{"entry_type":"faction","title":" Zhentarim ","body":"**Black Network**",
+		"tags":["Villains","villains"," mercenaries "],"visibility":"selected","selected_players":[8],"linked_entry_ids":[2,2]}`)
+	req := withCampaignUser(httptest.NewRequest(http.MethodPost, "/api/campaigns/10/wiki", body), "10", 7)
+	rr := httptest.NewRecorder()
+	handler.CreateEntry(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var resp struct {
+		Data models.WikiEntry `json:"data"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(resp.Data.Tags) != 2 || resp.Data.Tags[0] != "villains" || resp.Data.Tags[1] != "mercenaries" {
+		t.Fatalf("tags should be normalized, got %v", resp.Data.Tags)
+	}
+	if len(resp.Data.LinkedEntryIDs) != 1 {
+		t.Fatalf("duplicate links should be removed, got %v", resp.Data.LinkedEntryIDs)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestWikiHandler_CreateEntryValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"invalid type", `{"entry_type":"monster","title":"Beholder"}`},
+		{"missing title", `{"entry_type":"lore","title":""}`},
+		{"selected without players", `{"entry_type":"lore","title":"Prophecy","visibility":"selected"}`},
+		{"selected non player", `{"entry_type":"lore","title":"Prophecy","visibility":"selected","selected_players":[99]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mock, cleanup := newMockWikiHandler(t)
+			defer cleanup()
+
+			expectCampaignAccess(mock, 10, 7, 7, 8)
+
+			req := withCampaignUser(httptest.NewRequest(http.MethodPost, "/api/campaigns/10/wiki", bytes.NewBufferString(tt.body)), "10", 7)
+			rr := httptest.NewRecorder()
+			handler.CreateEntry(rr, req)
+
+			if rr.Code != http.StatusUnprocessableEntity {
+				t.Fatalf("expected 422, got %d: %s", rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestWikiHandler_CreateEntryUnknownLink(t *testing.T) {
+	handler, mock, cleanup := newMockWikiHandler(t)
+	defer cleanup()
+
+	expectCampaignAccess(mock, 10, 7, 7)
+	mock.ExpectQuery(`id = ANY\(\$2\)`).WithArgs(10, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows(wikiCols))
+
+	body := bytes.NewBufferString(`{"entry_type":"item","title":"Blackstaff","linked_entry_ids":[42]}`)
+	req := withCampaignUser(httptest.NewRequest(http.MethodPost, "/api/campaigns/10/wiki", body), "10", 7)
+	rr := httptest.NewRecorder()
+	handler.CreateEntry(rr, req)
+
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestWikiHandler_PlayerCannotWrite(t *testing.T) {
+	handler, mock, cleanup := newMockWikiHandler(t)
+	defer cleanup()
+
+	expectCampaignAccess(mock, 10, 8, 7, 8)
+
+	req := withCampaignUser(httptest.NewRequest(http.MethodPost, "/api/campaigns/10/wiki", bytes.NewBufferString(`{}`)), "10", 8)
+	rr := httptest.NewRecorder()
+	handler.CreateEntry(rr, req)
+
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", rr.Code)
+	}
+}
+
+func TestWikiHandler_DeleteEntryRemovesLinks(t *testing.T) {
+	handler, mock, cleanup := newMockWikiHandler(t)
+	defer cleanup()
+
+	expectCampaignAccess(mock, 10, 7, 7)
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM campaign_wiki_entries`).WithArgs(3, 10).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`array_remove\(linked_entry_ids, \$1\)`).WithArgs(3, 10).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	req := withCampaignUser(httptest.NewRequest(http.MethodDelete, "/api/campaigns/10/wiki/3", nil), "10", 7)
+	req = addChiURLParam(req, "entryId", "3")
+	rr := httptest.NewRecorder()
+	handler.DeleteEntry(rr, req)
+
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", rr.Code)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
@@ -42,7 +42,9 @@ func corsMiddleware(next http.Handler) http.Handler {
 	})
 }
 
-func SetupRoutes(dbClient *db.PostgresDB, pythonClient *python.Client) *chi.Mux {
+// SetupRoutes monta o roteador. dice substitui a fonte de dados das rolagens (testes e
+// reprodução de sessões gravadas); nil mantém a fonte padrão baseada em crypto/rand.
+func SetupRoutes(dbClient *db.PostgresDB, pythonClient *python.Client, dice handlers.DiceSource) *chi.Mux {
 	router := chi.NewRouter()
 
 	router.Use(chitrace.Middleware(chitrace.WithServiceName("rpg-saas-backend")))
@@ -66,8 +68,23 @@ func SetupRoutes(dbClient *db.PostgresDB, pythonClient *python.Client) *chi.Mux
 	campaignHandler := handlers.NewCampaignHandler(dbClient)
 	dndHandler := handlers.NewDnDHandler(dbClient)
 	homebrewHandler := handlers.NewHomebrewHandler(dbClient)
-	diceHandler := handlers.NewDiceHandler()
+	diceHandler := handlers.NewDiceHandler(dbClient)
 	roomHandler := handlers.NewRoomHandler(dbClient)
+	sessionHandler := handlers.NewSessionHandler(dbClient, roomHandler.Hub)
+	wikiHandler := handlers.NewWikiHandler(dbClient)
+	questHandler := handlers.NewQuestHandler(dbClient, roomHandler.Hub)
+	campaignHandler.Hub = roomHandler.Hub
+
+	// Feed de atividade compartilhado pelos handlers de campanha e de sala
+	activityLog := handlers.NewActivityLog(dbClient)
+	campaignHandler.Activity = activityLog
+	roomHandler.Activity = activityLog
+
+	if dice != nil {
+		diceHandler.Source = dice
+		roomHandler.Dice = dice
+		pcHandler.Dice = dice
+	}
 
 	// Websocket para salas (usa token via query)
 	router.Get("/api/rooms/{id}/ws", roomHandler.RoomWebsocket)
@@ -91,10 +108,24 @@ func SetupRoutes(dbClient *db.PostgresDB, pythonClient *python.Client) *chi.Mux
 		r.Put("/{id}", pcHandler.UpdatePC)
 		r.Delete("/{id}", pcHandler.DeletePC)
 
-		// r.Post("/generate", pcHandler.GenerateRandomPC)
+		r.Post("/generate", pcHandler.GenerateRandomPC)
+
+		// Construtor de personagens validado pelas regras do SRD
+		r.Post("/builder", pcHandler.BuildPC)
+		r.Post("/builder/roll", pcHandler.RollAbilityScores)
+		r.Post("/builder/validate", pcHandler.ValidateCharacterDraft)
 
 		r.Get("/{id}/campaigns", pcHandler.GetPCCampaigns)
 		r.Get("/{id}/check-availability", pcHandler.CheckUniquePCAvailability)
+		r.Get("/{id}/computed-sheet", pcHandler.GetComputedSheet)
+		r.Get("/{id}/sheet.pdf", pcHandler.GetPCSheetPDF)
+		r.Get("/{id}/level-up", pcHandler.GetLevelUpPlan)
+		r.Post("/{id}/level-up", pcHandler.LevelUpPC)
+
+		r.Get("/{id}/versions", pcHandler.GetPCVersions)
+		r.Get("/{id}/versions/diff", pcHandler.DiffPCVersions)
+		r.Get("/{id}/versions/{version}", pcHandler.GetPCVersion)
+		r.Post("/{id}/versions/{version}/revert", pcHandler.RevertPC)
 	})
 
 	router.Route("/api/encounters", func(r chi.Router) {
@@ -149,18 +180,109 @@ func SetupRoutes(dbClient *db.PostgresDB, pythonClient *python.Client) *chi.Mux
 		r.Get("/{id}/invite-code", campaignHandler.GetInviteCode)
 		r.Post("/{id}/regenerate-code", campaignHandler.RegenerateInviteCode)
 
+		r.Get("/{id}/invites", campaignHandler.GetInvites)
+		r.Post("/{id}/invites", campaignHandler.CreateInvite)
+		r.Delete("/{id}/invites/{inviteId}", campaignHandler.RevokeInvite)
+
+		r.Get("/{id}/join-requests", campaignHandler.GetJoinRequests)
+		r.Post("/{id}/join-requests/{userId}/accept", campaignHandler.AcceptJoinRequest)
+		r.Post("/{id}/join-requests/{userId}/reject", campaignHandler.RejectJoinRequest)
+
+		r.Post("/{id}/players/{userId}/kick", campaignHandler.KickPlayer)
+		r.Post("/{id}/players/{userId}/ban", campaignHandler.BanPlayer)
+		r.Delete("/{id}/players/{userId}/ban", campaignHandler.UnbanPlayer)
+		r.Put("/{id}/players/{userId}/role", campaignHandler.SetPlayerRole)
+		r.Post("/{id}/transfer", campaignHandler.TransferOwnership)
+
+		r.Get("/{id}/export", campaignHandler.ExportCampaign)
+		r.Post("/import", campaignHandler.ImportCampaign)
+		r.Post("/{id}/clone", campaignHandler.CloneCampaign)
+		r.Post("/{id}/templates", campaignHandler.SaveAsTemplate)
+
 		r.Post("/join", campaignHandler.JoinCampaign)
 		r.Delete("/{id}/leave", campaignHandler.LeaveCampaign)
 
 		r.Get("/{id}/available-characters", campaignHandler.GetAvailableCharacters)
 
+		r.Get("/{id}/homebrew-whitelist", campaignHandler.GetHomebrewWhitelist)
+		r.Put("/{id}/homebrew-whitelist", campaignHandler.UpdateHomebrewWhitelist)
+
 		r.Get("/{id}/characters", campaignHandler.GetCampaignCharacters)
 		r.Post("/{id}/characters", campaignHandler.AddCharacterToCampaign)
 		r.Get("/{id}/characters/{characterId}", campaignHandler.GetSingleCampaignCharacter)
+		r.Get("/{id}/characters/{characterId}/sheet.pdf", campaignHandler.GetCampaignCharacterSheetPDF)
 		r.Put("/{id}/characters/{characterId}", campaignHandler.UpdateCampaignCharacter)
 		r.Put("/{id}/characters/{characterId}/full", campaignHandler.UpdateCampaignCharacterFull)
 		r.Post("/{id}/characters/{characterId}/sync", campaignHandler.SyncCampaignCharacter)
 		r.Delete("/{id}/characters/{characterId}", campaignHandler.DeleteCampaignCharacter)
+		r.Get("/{id}/deaths", campaignHandler.GetCharacterDeaths)
+
+		// Feed de atividade (DM ou co-DM)
+		r.Get("/{id}/activity", campaignHandler.GetCampaignActivity)
+		r.Get("/{id}/activity/stream", campaignHandler.StreamCampaignActivity)
+
+		// Histórico de versões dos personagens
+		r.Get("/{id}/character-versions", campaignHandler.GetCampaignCharacterHistory)
+		r.Get("/{id}/characters/{characterId}/versions", campaignHandler.GetCampaignCharacterVersions)
+		r.Get("/{id}/characters/{characterId}/versions/diff", campaignHandler.DiffCampaignCharacterVersions)
+		r.Get("/{id}/characters/{characterId}/versions/{version}", campaignHandler.GetCampaignCharacterVersion)
+		r.Post("/{id}/characters/{characterId}/versions/{version}/revert", campaignHandler.RevertCampaignCharacter)
+
+		// Sessões
+		r.Get("/{id}/sessions", sessionHandler.GetSessions)
+		r.Post("/{id}/sessions", sessionHandler.CreateSession)
+		r.Get("/{id}/sessions/{sessionId}", sessionHandler.GetSession)
+		r.Put("/{id}/sessions/{sessionId}", sessionHandler.UpdateSession)
+		r.Delete("/{id}/sessions/{sessionId}", sessionHandler.DeleteSession)
+		r.Post("/{id}/sessions/{sessionId}/start", sessionHandler.StartSession)
+		r.Post("/{id}/sessions/{sessionId}/end", sessionHandler.EndSession)
+		r.Put("/{id}/sessions/{sessionId}/attendance", sessionHandler.UpdateAttendance)
+		r.Put("/{id}/sessions/{sessionId}/notes", sessionHandler.UpdateSessionNotes)
+
+		// Wiki da campanha
+		r.Get("/{id}/wiki", wikiHandler.GetEntries)
+		r.Post("/{id}/wiki", wikiHandler.CreateEntry)
+		r.Get("/{id}/wiki/{entryId}", wikiHandler.GetEntry)
+		r.Put("/{id}/wiki/{entryId}", wikiHandler.UpdateEntry)
+		r.Delete("/{id}/wiki/{entryId}", wikiHandler.DeleteEntry)
+
+		// Quests e objetivos
+		r.Get("/{id}/quests", questHandler.GetQuests)
+		r.Post("/{id}/quests", questHandler.CreateQuest)
+		r.Get("/{id}/quests/{questId}", questHandler.GetQuest)
+		r.Put("/{id}/quests/{questId}", questHandler.UpdateQuest)
+		r.Delete("/{id}/quests/{questId}", questHandler.DeleteQuest)
+		r.Put("/{id}/quests/{questId}/status", questHandler.UpdateQuestStatus)
+		r.Post("/{id}/quests/{questId}/objectives", questHandler.CreateObjective)
+		r.Put("/{id}/quests/{questId}/objectives/{objectiveId}", questHandler.UpdateObjective)
+		r.Delete("/{id}/quests/{questId}/objectives/{objectiveId}", questHandler.DeleteObjective)
+
+		// Inventário do grupo e divisão de saque
+		r.Get("/{id}/inventory", campaignHandler.GetPartyInventory)
+		r.Get("/{id}/inventory/log", campaignHandler.GetLootLog)
+		r.Post("/{id}/inventory/distribute", campaignHandler.DistributeLoot)
+		r.Post("/{id}/treasures/{treasureId}/assign", campaignHandler.AssignTreasure)
+
+		// Progressão: XP e marcos
+		r.Post("/{id}/xp", campaignHandler.AwardXP)
+		r.Post("/{id}/milestones", campaignHandler.AwardMilestone)
+
+		// NPCs e encontros da campanha
+		r.Get("/{id}/npcs", npcHandler.GetCampaignNPCs)
+		r.Post("/{id}/npcs", npcHandler.CreateCampaignNPC)
+		r.Put("/{id}/npcs/{npcId}/reveal", npcHandler.RevealCampaignNPC)
+		r.Get("/{id}/encounters", encounterHandler.GetCampaignEncounters)
+		r.Post("/{id}/encounters", encounterHandler.CreateCampaignEncounter)
+		r.Put("/{id}/encounters/{encounterId}/reveal", encounterHandler.RevealCampaignEncounter)
+	})
+
+	router.Route("/api/campaign-templates", func(r chi.Router) {
+		r.Use(customMiddleware.AuthMiddleware)
+
+		r.Get("/", campaignHandler.GetTemplates)
+		r.Get("/{id}", campaignHandler.GetTemplate)
+		r.Delete("/{id}", campaignHandler.DeleteTemplate)
+		r.Post("/{id}/campaigns", campaignHandler.CreateCampaignFromTemplate)
 	})
 
 	// ========================================
@@ -258,6 +380,15 @@ func SetupRoutes(dbClient *db.PostgresDB, pythonClient *python.Client) *chi.Mux
 		r.Use(customMiddleware.AuthMiddleware)
 		r.Post("/roll", diceHandler.RollDice)
 		r.Post("/roll-multiple", diceHandler.RollMultiple)
+		r.Post("/attack", diceHandler.RollAttack)
+
+		// Macros salvas
+		r.Get("/macros", diceHandler.GetMacros)
+		r.Post("/macros", diceHandler.CreateMacro)
+		r.Get("/macros/{id}", diceHandler.GetMacroByID)
+		r.Put("/macros/{id}", diceHandler.UpdateMacro)
+		r.Delete("/macros/{id}", diceHandler.DeleteMacro)
+		r.Post("/macros/{id}/roll", diceHandler.RollMacro)
 	})
 
 	return router
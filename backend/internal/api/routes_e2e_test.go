@@ -13,6 +13,7 @@ import (
 	"github.com/lib/pq"
 	"golang.org/x/crypto/bcrypt"
 
+	"rpg-saas-backend/internal/api/handlers"
 	"rpg-saas-backend/internal/auth"
 	"rpg-saas-backend/internal/db"
 	"rpg-saas-backend/internal/models"
@@ -32,7 +33,7 @@ func newE2EServer(t *testing.T) (*httptest.Server, sqlmock.Sqlmock, func()) {
 	}
 
 	dbClient := &db.PostgresDB{DB: sqlx.NewDb(rawDB, "postgres")}
-	router := SetupRoutes(dbClient, &python.Client{BaseURL: "http://python", HTTPClient: &http.Client{}})
+	router := SetupRoutes(dbClient, &python.Client{BaseURL: "http://python", HTTPClient: &http.Client{}}, nil)
 	server := httptest.NewServer(router)
 
 	cleanup := func() {
@@ -234,7 +235,7 @@ func TestE2E_CampaignCreateAndList(t *testing.T) {
 	now := time.Now()
 
 	mock.ExpectQuery(`INSERT INTO campaigns`).
-		WithArgs("E2E Campaign", "desc", 42, 5, "planning", false, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WithArgs("E2E Campaign", "desc", 42, 5, "planning", false, "xp", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(99))
 
 	createBody := bytes.NewBufferString(`{"name":"E2E Campaign","description":"desc","max_players":5}`)
@@ -304,3 +305,39 @@ func TestE2E_CampaignCreateAndList(t *testing.T) {
 		t.Fatalf("sql expectations not met: %v", err)
 	}
 }
+
+func TestE2E_InjectedDiceSource(t *testing.T) {
+	secret := testhelpers.SetRandomJWTSecret(t)
+
+	rawDB, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer rawDB.Close()
+
+	dbClient := &db.PostgresDB{DB: sqlx.NewDb(rawDB, "postgres")}
+	router := SetupRoutes(dbClient, &python.Client{BaseURL: "http://python", HTTPClient: &http.Client{}}, handlers.NewScriptedDiceSource(17))
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/api/dice/roll", bytes.NewBufferString(`{"notation":"1d20+2"}`))
+	if err != nil {
+		t.Fatalf("failed to build roll request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+bearerToken(t, secret, 42, "player@example.com"))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("roll request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var roll models.DiceRollResponse
+	if err := json.NewDecoder(resp.Body).Decode(&roll); err != nil {
+		t.Fatalf("failed to decode roll response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || roll.Total != 19 {
+		t.Fatalf("expected the injected source to roll 17+2, got %d: %+v", resp.StatusCode, roll)
+	}
+}
@@ -0,0 +1,99 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"rpg-saas-backend/internal/models"
+)
+
+// RecordCampaignActivity acrescenta um evento ao feed da campanha. O log é append-only: não
+// há update nem delete de eventos.
+func (p *PostgresDB) RecordCampaignActivity(ctx context.Context, activity *models.CampaignActivity) error {
+	query := `
+		INSERT INTO campaign_activity (campaign_id, actor_id, event_type, target_type, target_id, summary, metadata)
+		VALUES ($1, $2, $3, $4, $5, $6, COALESCE($7::jsonb, '{}'))
+		RETURNING id, created_at, COALESCE((SELECT username FROM users WHERE id = $2), '') AS actor_name
+	`
+
+	err := p.DB.QueryRowxContext(ctx, query,
+		activity.CampaignID, activity.ActorID, activity.EventType, activity.TargetType,
+		activity.TargetID, activity.Summary, activity.Metadata,
+	).Scan(&activity.ID, &activity.CreatedAt, &activity.ActorName)
+	if err != nil {
+		return fmt.Errorf("failed to record %s activity for campaign %d: %w", activity.EventType, activity.CampaignID, err)
+	}
+
+	return nil
+}
+
+// GetCampaignActivity lista o feed da campanha, do evento mais recente ao mais antigo, e o
+// total de eventos que passam pelo filtro
+func (p *PostgresDB) GetCampaignActivity(ctx context.Context, campaignID int, filter models.ActivityFilter, limit, offset int) ([]models.CampaignActivity, int, error) {
+	conditions := []string{"a.campaign_id = $1"}
+	args := []interface{}{campaignID}
+	argIndex := 2
+
+	if len(filter.Types) > 0 {
+		conditions = append(conditions, fmt.Sprintf("(a.event_type = ANY($%d) OR split_part(a.event_type, '.', 1) = ANY($%d))", argIndex, argIndex))
+		args = append(args, pq.Array(filter.Types))
+		argIndex++
+	}
+
+	if filter.ActorID != nil {
+		conditions = append(conditions, fmt.Sprintf("a.actor_id = $%d", argIndex))
+		args = append(args, *filter.ActorID)
+		argIndex++
+	}
+
+	if filter.TargetType != "" {
+		conditions = append(conditions, fmt.Sprintf("a.target_type = $%d", argIndex))
+		args = append(args, filter.TargetType)
+		argIndex++
+	}
+
+	if filter.TargetID != "" {
+		conditions = append(conditions, fmt.Sprintf("a.target_id = $%d", argIndex))
+		args = append(args, filter.TargetID)
+		argIndex++
+	}
+
+	if filter.Since != nil {
+		conditions = append(conditions, fmt.Sprintf("a.created_at >= $%d", argIndex))
+		args = append(args, *filter.Since)
+		argIndex++
+	}
+
+	if filter.Until != nil {
+		conditions = append(conditions, fmt.Sprintf("a.created_at <= $%d", argIndex))
+		args = append(args, *filter.Until)
+		argIndex++
+	}
+
+	where := strings.Join(conditions, " AND ")
+
+	var total int
+	if err := p.DB.GetContext(ctx, &total, `SELECT COUNT(*) FROM campaign_activity a WHERE `+where, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to count activity for campaign %d: %w", campaignID, err)
+	}
+
+	activity := []models.CampaignActivity{}
+	query := `
+		SELECT a.id, a.campaign_id, a.actor_id, COALESCE(u.username, '') AS actor_name, a.event_type,
+			a.target_type, a.target_id, a.summary, a.metadata, a.created_at
+		FROM campaign_activity a
+		LEFT JOIN users u ON u.id = a.actor_id
+		WHERE ` + where + fmt.Sprintf(`
+		ORDER BY a.created_at DESC, a.id DESC
+		LIMIT $%d OFFSET $%d`, argIndex, argIndex+1)
+	args = append(args, limit, offset)
+
+	if err := p.DB.SelectContext(ctx, &activity, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch activity for campaign %d: %w", campaignID, err)
+	}
+
+	return activity, total, nil
+}
@@ -0,0 +1,70 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+
+	"rpg-saas-backend/internal/models"
+)
+
+func TestRecordCampaignActivity(t *testing.T) {
+	pdb, mock, cleanup := newMockCampaignDB(t)
+	defer cleanup()
+
+	actorID := 7
+	now := time.Now()
+	mock.ExpectQuery(`INSERT INTO campaign_activity`).
+		WithArgs(1, 7, models.ActivityCharacterHPChanged, models.ActivityTargetCharacter, "5", "Aria HP changed", `{"from":12,"to":7}`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "actor_name"}).AddRow(40, now, "dm"))
+
+	activity := &models.CampaignActivity{
+		CampaignID: 1,
+		ActorID:    &actorID,
+		EventType:  models.ActivityCharacterHPChanged,
+		TargetType: models.ActivityTargetCharacter,
+		TargetID:   "5",
+		Summary:    "Aria HP changed",
+		Metadata:   models.JSONB{"from": 12, "to": 7},
+	}
+	if err := pdb.RecordCampaignActivity(context.Background(), activity); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if activity.ID != 40 || activity.ActorName != "dm" {
+		t.Fatalf("expected id and actor name filled in, got %+v", activity)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestGetCampaignActivityFilters(t *testing.T) {
+	pdb, mock, cleanup := newMockCampaignDB(t)
+	defer cleanup()
+
+	actorID := 8
+	since := time.Date(2024, 5, 1, 18, 0, 0, 0, time.UTC)
+	filter := models.ActivityFilter{Types: []string{"character", models.ActivityPlayerJoined}, ActorID: &actorID, Since: &since}
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM campaign_activity a WHERE a.campaign_id = \$1 AND \(a.event_type = ANY\(\$2\) OR split_part\(a.event_type, '.', 1\) = ANY\(\$2\)\) AND a.actor_id = \$3 AND a.created_at >= \$4`).
+		WithArgs(1, "{\"character\",\"player.joined\"}", 8, since).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectQuery(`FROM campaign_activity a\s+LEFT JOIN users u ON u.id = a.actor_id\s+WHERE .*\s+ORDER BY a.created_at DESC, a.id DESC\s+LIMIT \$5 OFFSET \$6`).
+		WithArgs(1, "{\"character\",\"player.joined\"}", 8, since, 2, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "campaign_id", "actor_id", "actor_name", "event_type", "target_type", "target_id", "summary", "metadata", "created_at"}).
+			AddRow(41, 1, 8, "bob", models.ActivityCharacterUpdated, "character", "5", "Edited Aria", []byte(`{}`), time.Now()).
+			AddRow(40, 1, 8, "bob", models.ActivityPlayerJoined, "player", "8", "Joined the campaign", []byte(`{"via":"code"}`), time.Now()))
+
+	activity, total, err := pdb.GetCampaignActivity(context.Background(), 1, filter, 2, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if total != 3 || len(activity) != 2 || activity[1].Metadata["via"] != "code" {
+		t.Fatalf("unexpected activity page: total=%d %+v", total, activity)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
@@ -0,0 +1,515 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"rpg-saas-backend/internal/models"
+)
+
+// GetReferencedHomebrew busca o homebrew cujos nomes aparecem na campanha. Conteúdo dos
+// donos informados tem prioridade sobre conteúdo público de mesmo nome.
+func (p *PostgresDB) GetReferencedHomebrew(ctx context.Context, names []string, ownerIDs []int) (models.ArchivedHomebrew, error) {
+	homebrew := models.ArchivedHomebrew{
+		Races:       []models.HomebrewRace{},
+		Classes:     []models.HomebrewClass{},
+		Backgrounds: []models.HomebrewBackground{},
+	}
+	if len(names) == 0 {
+		return homebrew, nil
+	}
+
+	owners := make(pq.Int64Array, len(ownerIDs))
+	for i, id := range ownerIDs {
+		owners[i] = int64(id)
+	}
+
+	where := `
+		WHERE name = ANY($1) AND (user_id = ANY($2) OR is_public = true)
+		ORDER BY name, (user_id = ANY($2)) DESC, id
+	`
+
+	if err := p.DB.SelectContext(ctx, &homebrew.Races, `SELECT DISTINCT ON (name) * FROM homebrew_races`+where, pq.Array(names), owners); err != nil {
+		return homebrew, fmt.Errorf("failed to fetch referenced homebrew races: %w", err)
+	}
+	if err := p.DB.SelectContext(ctx, &homebrew.Classes, `SELECT DISTINCT ON (name) * FROM homebrew_classes`+where, pq.Array(names), owners); err != nil {
+		return homebrew, fmt.Errorf("failed to fetch referenced homebrew classes: %w", err)
+	}
+	if err := p.DB.SelectContext(ctx, &homebrew.Backgrounds, `SELECT DISTINCT ON (name) * FROM homebrew_backgrounds`+where, pq.Array(names), owners); err != nil {
+		return homebrew, fmt.Errorf("failed to fetch referenced homebrew backgrounds: %w", err)
+	}
+
+	return homebrew, nil
+}
+
+// ImportCampaignArchive cria uma nova campanha a partir de um pacote exportado, numa única
+// transação. campaign já vem com DM, código de convite e dados básicos preenchidos; roomID
+// vazio ignora a cena. Referências que não existem nesta instância entram no relatório.
+func (p *PostgresDB) ImportCampaignArchive(ctx context.Context, archive *models.CampaignArchive, campaign *models.Campaign, roomID string) (*models.CampaignImportReport, error) {
+	if campaign.Status == "" {
+		campaign.Status = models.CampaignStatusPlanning
+	}
+	if !slices.Contains(models.CampaignStatuses, campaign.Status) {
+		return nil, fmt.Errorf("invalid campaign status %q", campaign.Status)
+	}
+
+	tx, err := p.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	campaign.CreatedAt = now
+	campaign.UpdatedAt = now
+	if campaign.LevelingMode == "" {
+		campaign.LevelingMode = models.LevelingModeXP
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO campaigns (name, description, dm_id, max_players, current_session, status, allow_homebrew, leveling_mode, invite_code, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id
+	`, campaign.Name, campaign.Description, campaign.DMID, campaign.MaxPlayers, campaign.CurrentSession,
+		campaign.Status, campaign.AllowHomebrew, campaign.LevelingMode, campaign.InviteCode, now, now,
+	).Scan(&campaign.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create imported campaign: %w", err)
+	}
+
+	report := &models.CampaignImportReport{CampaignID: campaign.ID, Unresolved: []models.UnresolvedReference{}}
+	users := newUsernameResolver(tx)
+
+	for _, player := range archive.Players {
+		status := player.Status
+		if status == "" {
+			status = models.PlayerStatusActive
+		}
+		role := player.Role
+		if role == "" {
+			role = models.PlayerRolePlayer
+		}
+		if !slices.Contains(models.PlayerStatuses, status) {
+			report.AddUnresolved("player", player.Username, "invalid status "+status)
+			continue
+		}
+		if !slices.Contains(models.PlayerRoles, role) {
+			report.AddUnresolved("player", player.Username, "invalid role "+role)
+			continue
+		}
+
+		userID, found, err := users.resolve(ctx, player.Username)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			report.AddUnresolved("player", player.Username, "user not found")
+			continue
+		}
+		if player.ID != 0 {
+			report.MapID("user", player.ID, userID)
+		}
+		if userID == campaign.DMID || status == models.PlayerStatusRemoved {
+			continue
+		}
+
+		// Ninguém entra na campanha sem aprovação: o usuário local com o mesmo username vira
+		// um pedido pendente de player. Banimentos são mantidos.
+		if status != models.PlayerStatusBanned {
+			status = models.PlayerStatusPending
+			if role == models.PlayerRoleCoDM {
+				report.AddUnresolved("player", player.Username, "co_dm role must be granted again after approval")
+			}
+		}
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO campaign_players (campaign_id, user_id, joined_at, status, role)
+			VALUES ($1, $2, $3, $4, $5)
+		`, campaign.ID, userID, now, status, models.PlayerRolePlayer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import player %s: %w", player.Username, err)
+		}
+		if status == models.PlayerStatusPending {
+			report.Players++
+		}
+	}
+
+	if err := importArchivedHomebrew(ctx, tx, &archive.Homebrew, campaign.DMID, report); err != nil {
+		return nil, err
+	}
+
+	boundPCs := map[int]bool{}
+	for _, character := range archive.Characters {
+		playerID, found, err := users.resolve(ctx, character.Username)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			report.AddUnresolved("character", character.Name, "owner "+character.Username+" not found")
+			continue
+		}
+
+		// Só os PCs do próprio importador podem ser ligados; os dos players são adicionados
+		// por eles depois da aprovação
+		if playerID != campaign.DMID {
+			report.AddUnresolved("character", character.Name, "source PC belongs to "+character.Username+", who must add it after joining")
+			continue
+		}
+		if character.Status != "" && !slices.Contains(models.CharacterStatuses, character.Status) {
+			report.AddUnresolved("character", character.Name, "invalid status "+character.Status)
+			continue
+		}
+
+		var sourcePCID int
+		err = tx.GetContext(ctx, &sourcePCID, `SELECT id FROM pcs WHERE player_id = $1 AND name = $2 ORDER BY id LIMIT 1`, playerID, character.Name)
+		if err == sql.ErrNoRows {
+			report.AddUnresolved("character", character.Name, "source PC not found for "+character.Username)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve source PC for %s: %w", character.Name, err)
+		}
+		if boundPCs[sourcePCID] {
+			report.AddUnresolved("character", character.Name, "source PC already bound to another archived character")
+			continue
+		}
+		boundPCs[sourcePCID] = true
+
+		oldID := character.ID
+		imported := character.CampaignCharacter
+		if imported.Status == "" {
+			imported.Status = models.CharacterStatusActive
+		}
+		imported.CampaignID = campaign.ID
+		imported.PlayerID = playerID
+		imported.SourcePCID = sourcePCID
+		if imported.JoinedAt.IsZero() {
+			imported.JoinedAt = now
+		}
+
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO campaign_characters (
+				campaign_id, player_id, source_pc_id, status, joined_at, campaign_notes,
+				name, description, level, race, class, background, alignment,
+				attributes, abilities, equipment, hp, current_hp, ca, proficiency_bonus,
+				inspiration, skills, attacks, spells, personality_traits, ideals,
+				bonds, flaws, features, player_name, experience_points, level_up_available,
+				class_levels
+			) VALUES (
+				$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16,
+				$17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32,
+				$33
+			)
+			RETURNING id
+		`, imported.CampaignID, imported.PlayerID, imported.SourcePCID,
+			imported.Status, imported.JoinedAt, imported.CampaignNotes,
+			imported.Name, imported.Description, imported.Level,
+			imported.Race, imported.Class, imported.Background,
+			imported.Alignment, imported.Attributes, imported.Abilities,
+			imported.Equipment, imported.HP, imported.CurrentHP,
+			imported.CA, imported.ProficiencyBonus, imported.Inspiration,
+			imported.Skills, imported.Attacks, imported.Spells,
+			imported.PersonalityTraits, imported.Ideals, imported.Bonds,
+			imported.Flaws, imported.Features, imported.PlayerName,
+			imported.ExperiencePoints, imported.LevelUpAvailable, imported.ClassLevels,
+		).Scan(&imported.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import character %s: %w", character.Name, err)
+		}
+		if _, err := recordCampaignCharacterVersionTx(ctx, tx, imported.ID, &campaign.DMID, models.VersionActionImport); err != nil {
+			return nil, err
+		}
+		report.MapID("character", oldID, imported.ID)
+		report.Characters++
+	}
+
+	for _, npc := range archive.NPCs {
+		var newID int
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO npcs (name, description, level, race, class, background, attributes, abilities, equipment, hp, ca, campaign_id, is_homebrew, revealed)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+			RETURNING id
+		`, npc.Name, npc.Description, npc.Level, npc.Race, npc.Class, npc.Background,
+			npc.Attributes, npc.Abilities, npc.Equipment, npc.HP, npc.CA, campaign.ID,
+			npc.IsHomebrew, npc.Revealed,
+		).Scan(&newID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import NPC %s: %w", npc.Name, err)
+		}
+		report.MapID("npc", npc.ID, newID)
+		report.NPCs++
+	}
+
+	for _, encounter := range archive.Encounters {
+		var newID int
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO encounters (theme, difficulty, total_xp, player_level, player_count, campaign_id, revealed)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING id
+		`, encounter.Theme, encounter.Difficulty, encounter.TotalXP,
+			encounter.PlayerLevel, encounter.PlayerCount, campaign.ID, encounter.Revealed,
+		).Scan(&newID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import encounter: %w", err)
+		}
+
+		for _, monster := range encounter.Monsters {
+			monster.EncounterID = newID
+			if err := p.createMonsterTx(ctx, tx, &monster); err != nil {
+				return nil, fmt.Errorf("failed to import encounter monster %s: %w", monster.Name, err)
+			}
+		}
+		report.MapID("encounter", encounter.ID, newID)
+		report.Encounters++
+	}
+
+	if err := importArchivedJournal(ctx, tx, archive, campaign, report); err != nil {
+		return nil, err
+	}
+
+	if archive.Scene != nil && roomID != "" {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO rooms (id, name, owner_id, campaign_id, scene_state, metadata, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		`, roomID, archive.Scene.RoomName, campaign.DMID, campaign.ID,
+			archive.Scene.SceneState, archive.Scene.Metadata, now, now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import room scene: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO room_members (room_id, user_id, role, joined_at)
+			VALUES ($1, $2, 'gm', $3)
+		`, roomID, campaign.DMID, now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add DM to imported room: %w", err)
+		}
+		report.RoomID = roomID
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit campaign import: %w", err)
+	}
+
+	return report, nil
+}
+
+// importArchivedJournal recria o wiki e as quests da campanha. Links entre entradas, jogadores
+// selecionados e NPCs que dão quests são remapeados; o que não existir mais é descartado.
+func importArchivedJournal(ctx context.Context, tx *sqlx.Tx, archive *models.CampaignArchive, campaign *models.Campaign, report *models.CampaignImportReport) error {
+	now := time.Now()
+
+	for _, entry := range archive.Wiki {
+		selected := pq.Int64Array{}
+		for _, oldID := range entry.SelectedPlayers {
+			if newID, ok := report.IDMap["user"][int(oldID)]; ok {
+				selected = append(selected, int64(newID))
+			}
+		}
+
+		visibility := entry.Visibility
+		if visibility == models.WikiVisibilitySelected && len(selected) == 0 {
+			visibility = models.WikiVisibilityDMOnly
+			report.AddUnresolved("wiki_entry", entry.Title, "selected players not found; entry is now dm_only")
+		}
+
+		var newID int
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO campaign_wiki_entries (
+				campaign_id, entry_type, title, body, tags, visibility,
+				selected_players, linked_entry_ids, created_by, created_at, updated_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, '{}', $8, $9, $10)
+			RETURNING id
+		`, campaign.ID, entry.EntryType, entry.Title, entry.Body, entry.Tags, visibility,
+			selected, campaign.DMID, now, now,
+		).Scan(&newID)
+		if err != nil {
+			return fmt.Errorf("failed to import wiki entry %s: %w", entry.Title, err)
+		}
+		report.MapID("wiki_entry", entry.ID, newID)
+	}
+
+	// Os links só podem ser gravados depois que todas as entradas existem
+	for _, entry := range archive.Wiki {
+		links := pq.Int64Array{}
+		for _, oldID := range entry.LinkedEntryIDs {
+			if newID, ok := report.IDMap["wiki_entry"][int(oldID)]; ok {
+				links = append(links, int64(newID))
+			}
+		}
+		if len(links) == 0 {
+			continue
+		}
+
+		_, err := tx.ExecContext(ctx, `UPDATE campaign_wiki_entries SET linked_entry_ids = $1 WHERE id = $2`,
+			links, report.IDMap["wiki_entry"][entry.ID])
+		if err != nil {
+			return fmt.Errorf("failed to link wiki entry %s: %w", entry.Title, err)
+		}
+	}
+
+	for _, quest := range archive.Quests {
+		var giverNPCID *int
+		giverName := quest.GiverName
+		if quest.GiverNPCID != nil {
+			if newID, ok := report.IDMap["npc"][*quest.GiverNPCID]; ok {
+				giverNPCID = &newID
+			} else if giverName == "" {
+				giverName = quest.GiverNPCName
+			}
+		}
+
+		status := quest.Status
+		if status == "" {
+			status = models.QuestStatusActive
+		}
+
+		var newID int
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO quests (campaign_id, title, description, giver_npc_id, giver_name, status, revealed, rewards, completed_at, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			RETURNING id
+		`, campaign.ID, quest.Title, quest.Description, giverNPCID, giverName,
+			status, quest.Revealed, quest.Rewards, quest.CompletedAt, now, now,
+		).Scan(&newID)
+		if err != nil {
+			return fmt.Errorf("failed to import quest %s: %w", quest.Title, err)
+		}
+
+		for _, objective := range quest.Objectives {
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO quest_objectives (quest_id, description, completed, sort_order, completed_at, created_at)
+				VALUES ($1, $2, $3, $4, $5, $6)
+			`, newID, objective.Description, objective.Completed, objective.SortOrder, objective.CompletedAt, now)
+			if err != nil {
+				return fmt.Errorf("failed to import objective of quest %s: %w", quest.Title, err)
+			}
+		}
+		report.MapID("quest", quest.ID, newID)
+	}
+
+	return nil
+}
+
+// importArchivedHomebrew cria, como conteúdo privado do DM, o homebrew que ainda não existe
+// para ele. Um item com o mesmo nome (dele ou público) é reaproveitado.
+func importArchivedHomebrew(ctx context.Context, tx *sqlx.Tx, homebrew *models.ArchivedHomebrew, dmID int, report *models.CampaignImportReport) error {
+	exists := func(table, name string) (bool, error) {
+		var found bool
+		query := `SELECT EXISTS(SELECT 1 FROM ` + table + ` WHERE name = $1 AND (user_id = $2 OR is_public = true))`
+		if err := tx.GetContext(ctx, &found, query, name, dmID); err != nil {
+			return false, fmt.Errorf("failed to check existing homebrew %s: %w", name, err)
+		}
+		return found, nil
+	}
+
+	for _, race := range homebrew.Races {
+		found, err := exists("homebrew_races", race.Name)
+		if err != nil {
+			return err
+		}
+		if found {
+			continue
+		}
+
+		var newID int
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO homebrew_races
+			(name, description, speed, size, languages, traits, abilities, proficiencies, user_id, is_public)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, false)
+			RETURNING id
+		`, race.Name, race.Description, race.Speed, race.Size, race.Languages,
+			race.Traits, race.Abilities, race.Proficiencies, dmID,
+		).Scan(&newID)
+		if err != nil {
+			return fmt.Errorf("failed to import homebrew race %s: %w", race.Name, err)
+		}
+		report.MapID("homebrew_race", race.ID, newID)
+		report.Homebrew++
+	}
+
+	for _, class := range homebrew.Classes {
+		found, err := exists("homebrew_classes", class.Name)
+		if err != nil {
+			return err
+		}
+		if found {
+			continue
+		}
+
+		var newID int
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO homebrew_classes
+			(name, description, hit_die, primary_ability, saving_throws, armor_proficiency,
+			 weapon_proficiency, tool_proficiency, skill_choices, features, spellcasting, user_id, is_public)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, false)
+			RETURNING id
+		`, class.Name, class.Description, class.HitDie, class.PrimaryAbility, class.SavingThrows,
+			class.ArmorProficiency, class.WeaponProficiency, class.ToolProficiency,
+			class.SkillChoices, class.Features, class.Spellcasting, dmID,
+		).Scan(&newID)
+		if err != nil {
+			return fmt.Errorf("failed to import homebrew class %s: %w", class.Name, err)
+		}
+		report.MapID("homebrew_class", class.ID, newID)
+		report.Homebrew++
+	}
+
+	for _, bg := range homebrew.Backgrounds {
+		found, err := exists("homebrew_backgrounds", bg.Name)
+		if err != nil {
+			return err
+		}
+		if found {
+			continue
+		}
+
+		var newID int
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO homebrew_backgrounds
+			(name, description, skill_proficiencies, tool_proficiencies, languages,
+			 equipment, feature, suggested_traits, user_id, is_public)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, false)
+			RETURNING id
+		`, bg.Name, bg.Description, bg.SkillProficiencies, bg.ToolProficiencies, bg.Languages,
+			bg.Equipment, bg.Feature, bg.SuggestedTraits, dmID,
+		).Scan(&newID)
+		if err != nil {
+			return fmt.Errorf("failed to import homebrew background %s: %w", bg.Name, err)
+		}
+		report.MapID("homebrew_background", bg.ID, newID)
+		report.Homebrew++
+	}
+
+	return nil
+}
+
+// usernameResolver resolve usernames para IDs locais, com cache por importação
+type usernameResolver struct {
+	tx    *sqlx.Tx
+	cache map[string]int
+}
+
+func newUsernameResolver(tx *sqlx.Tx) *usernameResolver {
+	return &usernameResolver{tx: tx, cache: map[string]int{}}
+}
+
+func (u *usernameResolver) resolve(ctx context.Context, username string) (int, bool, error) {
+	if id, ok := u.cache[username]; ok {
+		return id, id != 0, nil
+	}
+
+	var id int
+	err := u.tx.GetContext(ctx, &id, `SELECT id FROM users WHERE username = $1`, username)
+	if err != nil && err != sql.ErrNoRows {
+		return 0, false, fmt.Errorf("failed to resolve user %s: %w", username, err)
+	}
+
+	u.cache[username] = id
+	return id, id != 0, nil
+}
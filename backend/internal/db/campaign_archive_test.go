@@ -0,0 +1,235 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+
+	"rpg-saas-backend/internal/models"
+)
+
+func TestImportCampaignArchive(t *testing.T) {
+	pdb, mock, cleanup := newMockCampaignDB(t)
+	defer cleanup()
+
+	archive := &models.CampaignArchive{
+		Version: models.CampaignArchiveVersion,
+		Players: []models.ArchivedPlayer{
+			{Username: "alice", Status: "active", Role: "co_dm"},
+			{Username: "ghost", Status: "active"},
+		},
+		Characters: []models.ArchivedCharacter{
+			{CampaignCharacter: models.CampaignCharacter{ID: 11, Name: "Lia", Status: "active"}, Username: "alice"},
+		},
+		NPCs: []models.NPC{{ID: 21, Name: "Durnan", Race: "Human"}},
+		Encounters: []models.Encounter{{
+			ID: 31, Theme: "cellar", Difficulty: "easy",
+			Monsters: []models.Monster{{ID: 32, Name: "Rat", XP: 10}},
+		}},
+		Homebrew: models.ArchivedHomebrew{Races: []models.HomebrewRace{{ID: 41, Name: "Owlfolk", Size: "Medium"}}},
+		Scene:    &models.ArchivedScene{RoomName: "Tavern"},
+	}
+	campaign := &models.Campaign{Name: "Dragon Heist", DMID: 7, MaxPlayers: 5, CurrentSession: 3, Status: "active", InviteCode: "ABCD1234"}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO campaigns`).
+		WithArgs("Dragon Heist", "", 7, 5, 3, "active", false, "xp", "ABCD1234", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(50))
+	mock.ExpectQuery(`SELECT id FROM users WHERE username = \$1`).WithArgs("alice").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(8))
+	mock.ExpectExec(`INSERT INTO campaign_players`).WithArgs(50, 8, sqlmock.AnyArg(), "pending", "player").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(`SELECT id FROM users WHERE username = \$1`).WithArgs("ghost").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM homebrew_races`).WithArgs("Owlfolk", 7).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectQuery(`INSERT INTO homebrew_races`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(90))
+	// alice já está no cache; o PC dela não é ligado pelo importador
+	mock.ExpectQuery(`INSERT INTO npcs`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(60))
+	mock.ExpectQuery(`INSERT INTO encounters`).WithArgs("cellar", "easy", 0, 0, 0, 50, false).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(70))
+	mock.ExpectQuery(`INSERT INTO encounter_monsters`).WithArgs(70, "Rat", 10, 0.0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(71, time.Now()))
+	mock.ExpectExec(`INSERT INTO rooms`).WithArgs("room-x", "Tavern", 7, 50, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO room_members`).WithArgs("room-x", 7, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	report, err := pdb.ImportCampaignArchive(context.Background(), archive, campaign, "room-x")
+	if err != nil {
+		t.Fatalf("expected import to succeed, got %v", err)
+	}
+
+	if campaign.ID != 50 || report.CampaignID != 50 {
+		t.Fatalf("expected new campaign id 50, got %d/%d", campaign.ID, report.CampaignID)
+	}
+	if report.Players != 1 || report.Characters != 0 || report.NPCs != 1 || report.Encounters != 1 || report.Homebrew != 1 {
+		t.Fatalf("unexpected counts: %+v", report)
+	}
+	if report.IDMap["npc"][21] != 60 || report.IDMap["encounter"][31] != 70 || report.IDMap["homebrew_race"][41] != 90 {
+		t.Fatalf("unexpected id map: %+v", report.IDMap)
+	}
+	if len(report.Unresolved) != 3 || report.Unresolved[0].Reference != "alice" ||
+		report.Unresolved[1].Reference != "ghost" || report.Unresolved[2].Type != "character" {
+		t.Fatalf("unexpected unresolved references: %+v", report.Unresolved)
+	}
+	if report.RoomID != "room-x" {
+		t.Fatalf("expected room to be created, got %q", report.RoomID)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestImportCampaignArchive_PlayersAndCharacters(t *testing.T) {
+	pdb, mock, cleanup := newMockCampaignDB(t)
+	defer cleanup()
+
+	archive := &models.CampaignArchive{
+		Version: models.CampaignArchiveVersion,
+		Players: []models.ArchivedPlayer{
+			{Username: "mallory", Status: "active", Role: "owner"},
+			{Username: "eve", Status: "superuser"},
+			{Username: "trent", Status: "banned"},
+			{Username: "dave", Status: "removed"},
+		},
+		Characters: []models.ArchivedCharacter{
+			{CampaignCharacter: models.CampaignCharacter{ID: 11, Name: "Lia", Status: "active"}, Username: "dm"},
+			{CampaignCharacter: models.CampaignCharacter{ID: 12, Name: "Lia", Status: "dead"}, Username: "dm"},
+			{CampaignCharacter: models.CampaignCharacter{ID: 13, Name: "Kor", Status: "zombie"}, Username: "dm"},
+		},
+	}
+	campaign := &models.Campaign{Name: "Heist", DMID: 7, MaxPlayers: 4, CurrentSession: 1, Status: "planning", InviteCode: "ABCD1234"}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO campaigns`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(50))
+	// mallory e eve são rejeitados antes da busca do usuário
+	mock.ExpectQuery(`SELECT id FROM users WHERE username = \$1`).WithArgs("trent").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(9))
+	mock.ExpectExec(`INSERT INTO campaign_players`).WithArgs(50, 9, sqlmock.AnyArg(), "banned", "player").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(`SELECT id FROM users WHERE username = \$1`).WithArgs("dave").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+	mock.ExpectQuery(`SELECT id FROM users WHERE username = \$1`).WithArgs("dm").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	mock.ExpectQuery(`SELECT id FROM pcs WHERE player_id = \$1 AND name = \$2`).WithArgs(7, "Lia").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(30))
+	mock.ExpectQuery(`INSERT INTO campaign_characters`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(80))
+	expectCampaignCharacterVersion(mock, 80, 50, models.VersionActionImport)
+	// O segundo Lia aponta para o mesmo PC e é reportado em vez de abortar a importação
+	mock.ExpectQuery(`SELECT id FROM pcs WHERE player_id = \$1 AND name = \$2`).WithArgs(7, "Lia").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(30))
+	mock.ExpectCommit()
+
+	report, err := pdb.ImportCampaignArchive(context.Background(), archive, campaign, "")
+	if err != nil {
+		t.Fatalf("expected import to succeed, got %v", err)
+	}
+	if report.Players != 0 || report.Characters != 1 || report.IDMap["character"][11] != 80 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	reasons := map[string]string{}
+	for _, ref := range report.Unresolved {
+		reasons[ref.Type+":"+ref.Reference] += ref.Reason
+	}
+	if reasons["player:mallory"] != "invalid role owner" || reasons["player:eve"] != "invalid status superuser" ||
+		reasons["character:Kor"] != "invalid status zombie" ||
+		reasons["character:Lia"] != "source PC already bound to another archived character" || len(report.Unresolved) != 4 {
+		t.Fatalf("unexpected unresolved references: %+v", report.Unresolved)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestImportCampaignArchive_RejectsInvalidStatus(t *testing.T) {
+	pdb, mock, cleanup := newMockCampaignDB(t)
+	defer cleanup()
+
+	archive := &models.CampaignArchive{Version: models.CampaignArchiveVersion}
+	campaign := &models.Campaign{Name: "Heist", DMID: 7, MaxPlayers: 4, CurrentSession: 1, Status: "archived", InviteCode: "ABCD1234"}
+
+	if _, err := pdb.ImportCampaignArchive(context.Background(), archive, campaign, ""); err == nil {
+		t.Fatal("expected an unknown campaign status to be rejected")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestImportCampaignArchive_ReusesExistingHomebrew(t *testing.T) {
+	pdb, mock, cleanup := newMockCampaignDB(t)
+	defer cleanup()
+
+	archive := &models.CampaignArchive{
+		Version:  models.CampaignArchiveVersion,
+		Homebrew: models.ArchivedHomebrew{Classes: []models.HomebrewClass{{ID: 3, Name: "Blood Hunter"}}},
+	}
+	campaign := &models.Campaign{Name: "One-shot", DMID: 7, MaxPlayers: 4, CurrentSession: 1, Status: "planning", InviteCode: "ABCD1234"}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO campaigns`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(51))
+	mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM homebrew_classes`).WithArgs("Blood Hunter", 7).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectCommit()
+
+	report, err := pdb.ImportCampaignArchive(context.Background(), archive, campaign, "")
+	if err != nil {
+		t.Fatalf("expected import to succeed, got %v", err)
+	}
+	if report.Homebrew != 0 || report.RoomID != "" {
+		t.Fatalf("expected nothing new besides the campaign, got %+v", report)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestImportCampaignArchive_RemapsWikiLinksAndSelectedPlayers(t *testing.T) {
+	pdb, mock, cleanup := newMockCampaignDB(t)
+	defer cleanup()
+
+	archive := &models.CampaignArchive{
+		Version: models.CampaignArchiveVersion,
+		Players: []models.ArchivedPlayer{{ID: 100, Username: "alice", Status: "active"}},
+		Wiki: []models.WikiEntry{
+			{ID: 1, EntryType: "location", Title: "Waterdeep", Visibility: "selected", SelectedPlayers: []int64{100, 101}, LinkedEntryIDs: []int64{2, 999}},
+			{ID: 2, EntryType: "faction", Title: "Zhentarim", Visibility: "selected", SelectedPlayers: []int64{101}},
+		},
+	}
+	campaign := &models.Campaign{Name: "Heist", DMID: 7, MaxPlayers: 4, CurrentSession: 1, Status: "planning", InviteCode: "ABCD1234"}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO campaigns`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(50))
+	mock.ExpectQuery(`SELECT id FROM users WHERE username = \$1`).WithArgs("alice").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(8))
+	mock.ExpectExec(`INSERT INTO campaign_players`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(`INSERT INTO campaign_wiki_entries`).
+		WithArgs(50, "location", "Waterdeep", "", sqlmock.AnyArg(), "selected", "{8}", 7, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(11))
+	mock.ExpectQuery(`INSERT INTO campaign_wiki_entries`).
+		WithArgs(50, "faction", "Zhentarim", "", sqlmock.AnyArg(), "dm_only", "{}", 7, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(12))
+	mock.ExpectExec(`UPDATE campaign_wiki_entries SET linked_entry_ids = \$1 WHERE id = \$2`).WithArgs("{12}", 11).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	report, err := pdb.ImportCampaignArchive(context.Background(), archive, campaign, "")
+	if err != nil {
+		t.Fatalf("expected import to succeed, got %v", err)
+	}
+	if len(report.Unresolved) != 1 || report.Unresolved[0].Reference != "Zhentarim" {
+		t.Fatalf("expected the unreachable selected entry to be reported, got %+v", report.Unresolved)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
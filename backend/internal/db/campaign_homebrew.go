@@ -0,0 +1,193 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"rpg-saas-backend/internal/models"
+)
+
+// ErrHomebrewNotFound indica um ID de homebrew inexistente ou não visível para o DM
+var ErrHomebrewNotFound = errors.New("homebrew content not found")
+
+// srdContentTables e homebrewContentTables ligam cada tipo de conteúdo à sua tabela
+var srdContentTables = map[string]string{
+	models.ContentTypeRace:       "dnd_races",
+	models.ContentTypeClass:      "dnd_classes",
+	models.ContentTypeBackground: "dnd_backgrounds",
+}
+
+var homebrewContentTables = map[string]string{
+	models.ContentTypeRace:       "homebrew_races",
+	models.ContentTypeClass:      "homebrew_classes",
+	models.ContentTypeBackground: "homebrew_backgrounds",
+}
+
+// GetCampaignHomebrewWhitelist lista o homebrew aprovado para a campanha, com o nome atual
+func (p *PostgresDB) GetCampaignHomebrewWhitelist(ctx context.Context, campaignID int) ([]models.HomebrewWhitelistEntry, error) {
+	entries := []models.HomebrewWhitelistEntry{}
+	query := `
+		SELECT w.campaign_id, w.content_type, w.content_id,
+			COALESCE(r.name, c.name, b.name, '') AS name, w.created_at
+		FROM campaign_homebrew_whitelist w
+		LEFT JOIN homebrew_races r ON w.content_type = 'race' AND r.id = w.content_id
+		LEFT JOIN homebrew_classes c ON w.content_type = 'class' AND c.id = w.content_id
+		LEFT JOIN homebrew_backgrounds b ON w.content_type = 'background' AND b.id = w.content_id
+		WHERE w.campaign_id = $1
+		ORDER BY w.content_type, name
+	`
+
+	if err := p.DB.SelectContext(ctx, &entries, query, campaignID); err != nil {
+		return nil, fmt.Errorf("failed to fetch homebrew whitelist: %w", err)
+	}
+
+	return entries, nil
+}
+
+// ReplaceCampaignHomebrewWhitelist substitui o homebrew aprovado da campanha. Cada ID precisa
+// existir e ser público ou do próprio DM; caso contrário nada é alterado.
+func (p *PostgresDB) ReplaceCampaignHomebrewWhitelist(ctx context.Context, campaignID, dmID int, entries []models.HomebrewWhitelistEntry) error {
+	tx, err := p.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM campaign_homebrew_whitelist WHERE campaign_id = $1`, campaignID); err != nil {
+		return fmt.Errorf("failed to clear homebrew whitelist: %w", err)
+	}
+
+	for _, entry := range entries {
+		table, ok := homebrewContentTables[entry.ContentType]
+		if !ok {
+			return fmt.Errorf("invalid content type %q", entry.ContentType)
+		}
+
+		var visible bool
+		query := fmt.Sprintf(`SELECT EXISTS(SELECT 1 FROM %s WHERE id = $1 AND (is_public OR user_id = $2))`, table)
+		if err := tx.QueryRowContext(ctx, query, entry.ContentID, dmID).Scan(&visible); err != nil {
+			return fmt.Errorf("failed to check homebrew %s %d: %w", entry.ContentType, entry.ContentID, err)
+		}
+		if !visible {
+			return fmt.Errorf("%w: %s %d", ErrHomebrewNotFound, entry.ContentType, entry.ContentID)
+		}
+
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO campaign_homebrew_whitelist (campaign_id, content_type, content_id, created_at)
+			VALUES ($1, $2, $3, NOW())
+		`, campaignID, entry.ContentType, entry.ContentID)
+		if err != nil {
+			return fmt.Errorf("failed to add homebrew %s %d to whitelist: %w", entry.ContentType, entry.ContentID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit homebrew whitelist: %w", err)
+	}
+
+	return nil
+}
+
+// CheckCampaignCharacterContent verifica raça, classes e antecedente contra a política de
+// homebrew da campanha. Sem homebrew, apenas conteúdo SRD é aceito; com homebrew e uma
+// lista de aprovados, o conteúdo precisa ser SRD ou estar na lista. Campos vazios são ignorados;
+// cada classe de um personagem multiclasse é verificada separadamente.
+func (p *PostgresDB) CheckCampaignCharacterContent(ctx context.Context, campaignID int, race string, classes []string, background string) ([]models.ContentRejection, error) {
+	var allowHomebrew, hasWhitelist bool
+	err := p.DB.QueryRowContext(ctx, `
+		SELECT c.allow_homebrew,
+			EXISTS(SELECT 1 FROM campaign_homebrew_whitelist w WHERE w.campaign_id = c.id)
+		FROM campaigns c
+		WHERE c.id = $1
+	`, campaignID).Scan(&allowHomebrew, &hasWhitelist)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch campaign homebrew policy: %w", err)
+	}
+
+	if allowHomebrew && !hasWhitelist {
+		return nil, nil
+	}
+
+	values := map[string][]string{
+		models.ContentTypeRace:       {race},
+		models.ContentTypeClass:      classes,
+		models.ContentTypeBackground: {background},
+	}
+
+	rejections := []models.ContentRejection{}
+	for _, contentType := range models.ContentTypes {
+		for _, value := range values[contentType] {
+			value = strings.TrimSpace(value)
+			if value == "" {
+				continue
+			}
+
+			isSRD, err := p.isSRDContent(ctx, contentType, value)
+			if err != nil {
+				return nil, err
+			}
+			if isSRD {
+				continue
+			}
+
+			if !allowHomebrew {
+				rejections = append(rejections, models.ContentRejection{
+					Field:  contentType,
+					Value:  value,
+					Code:   "homebrew_not_allowed",
+					Reason: fmt.Sprintf("%s %q is not SRD content and this campaign does not allow homebrew", contentType, value),
+				})
+				continue
+			}
+
+			approved, err := p.isApprovedHomebrew(ctx, campaignID, contentType, value)
+			if err != nil {
+				return nil, err
+			}
+			if !approved {
+				rejections = append(rejections, models.ContentRejection{
+					Field:  contentType,
+					Value:  value,
+					Code:   "homebrew_not_approved",
+					Reason: fmt.Sprintf("%s %q is not in this campaign's approved homebrew", contentType, value),
+				})
+			}
+		}
+	}
+
+	return rejections, nil
+}
+
+// isSRDContent verifica se o valor corresponde ao nome ou api_index de um registro SRD
+func (p *PostgresDB) isSRDContent(ctx context.Context, contentType, value string) (bool, error) {
+	var exists bool
+	query := fmt.Sprintf(`
+		SELECT EXISTS(SELECT 1 FROM %s WHERE LOWER(name) = LOWER($1) OR LOWER(api_index) = LOWER($1))
+	`, srdContentTables[contentType])
+
+	if err := p.DB.QueryRowContext(ctx, query, value).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check SRD %s: %w", contentType, err)
+	}
+
+	return exists, nil
+}
+
+// isApprovedHomebrew verifica se o valor corresponde a um homebrew aprovado para a campanha
+func (p *PostgresDB) isApprovedHomebrew(ctx context.Context, campaignID int, contentType, value string) (bool, error) {
+	var exists bool
+	query := fmt.Sprintf(`
+		SELECT EXISTS(
+			SELECT 1 FROM campaign_homebrew_whitelist w
+			JOIN %s h ON h.id = w.content_id
+			WHERE w.campaign_id = $1 AND w.content_type = $2 AND LOWER(h.name) = LOWER($3)
+		)
+	`, homebrewContentTables[contentType])
+
+	if err := p.DB.QueryRowContext(ctx, query, campaignID, contentType, value).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check approved homebrew %s: %w", contentType, err)
+	}
+
+	return exists, nil
+}
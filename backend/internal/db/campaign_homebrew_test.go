@@ -0,0 +1,148 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+
+	"rpg-saas-backend/internal/models"
+)
+
+func expectHomebrewPolicy(mock sqlmock.Sqlmock, campaignID int, allowHomebrew, hasWhitelist bool) {
+	mock.ExpectQuery(`SELECT c.allow_homebrew`).WithArgs(campaignID).
+		WillReturnRows(sqlmock.NewRows([]string{"allow_homebrew", "exists"}).AddRow(allowHomebrew, hasWhitelist))
+}
+
+func expectExists(mock sqlmock.Sqlmock, pattern string, exists bool, args ...driver.Value) {
+	mock.ExpectQuery(pattern).WithArgs(args...).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(exists))
+}
+
+func TestCheckCampaignCharacterContent(t *testing.T) {
+	t.Run("homebrew allowed without whitelist skips checks", func(t *testing.T) {
+		pdb, mock, cleanup := newMockCampaignDB(t)
+		defer cleanup()
+
+		expectHomebrewPolicy(mock, 1, true, false)
+
+		rejections, err := pdb.CheckCampaignCharacterContent(context.Background(), 1, "Owlfolk", []string{"Wizard"}, "Sage")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(rejections) != 0 {
+			t.Fatalf("expected no rejections, got %+v", rejections)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+
+	t.Run("homebrew disallowed accepts only SRD", func(t *testing.T) {
+		pdb, mock, cleanup := newMockCampaignDB(t)
+		defer cleanup()
+
+		expectHomebrewPolicy(mock, 1, false, false)
+		expectExists(mock, `FROM dnd_races`, false, "Owlfolk")
+		expectExists(mock, `FROM dnd_classes`, true, "Wizard")
+
+		rejections, err := pdb.CheckCampaignCharacterContent(context.Background(), 1, "Owlfolk", []string{"Wizard"}, "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(rejections) != 1 || rejections[0].Field != "race" || rejections[0].Code != "homebrew_not_allowed" {
+			t.Fatalf("expected race rejection, got %+v", rejections)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+
+	t.Run("whitelist rejects unapproved homebrew", func(t *testing.T) {
+		pdb, mock, cleanup := newMockCampaignDB(t)
+		defer cleanup()
+
+		expectHomebrewPolicy(mock, 1, true, true)
+		expectExists(mock, `FROM dnd_races`, false, "Owlfolk")
+		expectExists(mock, `JOIN homebrew_races`, true, 1, "race", "Owlfolk")
+		expectExists(mock, `FROM dnd_classes`, false, "Gunslinger")
+		expectExists(mock, `JOIN homebrew_classes`, false, 1, "class", "Gunslinger")
+		expectExists(mock, `FROM dnd_backgrounds`, true, "Sage")
+
+		rejections, err := pdb.CheckCampaignCharacterContent(context.Background(), 1, "Owlfolk", []string{"Gunslinger"}, "Sage")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(rejections) != 1 || rejections[0].Field != "class" || rejections[0].Code != "homebrew_not_approved" {
+			t.Fatalf("expected class rejection, got %+v", rejections)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+
+	t.Run("multiclass checks each class", func(t *testing.T) {
+		pdb, mock, cleanup := newMockCampaignDB(t)
+		defer cleanup()
+
+		expectHomebrewPolicy(mock, 1, false, false)
+		expectExists(mock, `FROM dnd_races`, true, "Elf")
+		expectExists(mock, `FROM dnd_classes`, true, "Fighter")
+		expectExists(mock, `FROM dnd_classes`, false, "Gunslinger")
+		expectExists(mock, `FROM dnd_backgrounds`, true, "Sage")
+
+		rejections, err := pdb.CheckCampaignCharacterContent(context.Background(), 1, "Elf", []string{"Fighter", "Gunslinger"}, "Sage")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(rejections) != 1 || rejections[0].Field != "class" || rejections[0].Value != "Gunslinger" {
+			t.Fatalf("expected Gunslinger rejection, got %+v", rejections)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+}
+
+func TestReplaceCampaignHomebrewWhitelist(t *testing.T) {
+	t.Run("replaces entries", func(t *testing.T) {
+		pdb, mock, cleanup := newMockCampaignDB(t)
+		defer cleanup()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`DELETE FROM campaign_homebrew_whitelist`).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+		expectExists(mock, `FROM homebrew_races WHERE id = \$1 AND \(is_public OR user_id = \$2\)`, true, 5, 7)
+		mock.ExpectExec(`INSERT INTO campaign_homebrew_whitelist`).WithArgs(1, "race", 5).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		entries := models.UpdateHomebrewWhitelistRequest{Races: []int{5, 5}}.Entries()
+		if err := pdb.ReplaceCampaignHomebrewWhitelist(context.Background(), 1, 7, entries); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+
+	t.Run("private homebrew of another user", func(t *testing.T) {
+		pdb, mock, cleanup := newMockCampaignDB(t)
+		defer cleanup()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`DELETE FROM campaign_homebrew_whitelist`).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+		expectExists(mock, `FROM homebrew_classes`, false, 9, 7)
+		mock.ExpectRollback()
+
+		entries := models.UpdateHomebrewWhitelistRequest{Classes: []int{9}}.Entries()
+		err := pdb.ReplaceCampaignHomebrewWhitelist(context.Background(), 1, 7, entries)
+		if !errors.Is(err, ErrHomebrewNotFound) {
+			t.Fatalf("expected ErrHomebrewNotFound, got %v", err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+}
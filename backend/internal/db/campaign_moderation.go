@@ -0,0 +1,218 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"rpg-saas-backend/internal/models"
+)
+
+var (
+	// ErrPlayerNotFound indica que o usuário não é membro ativo da campanha
+	ErrPlayerNotFound = errors.New("player not found in campaign")
+	// ErrPlayerBanned indica jogador banido, que não pode sair nem voltar por conta própria
+	ErrPlayerBanned = errors.New("player is banned from this campaign")
+)
+
+// checkReturningPlayer decide se um usuário que já tem linha em campaign_players pode
+// entrar de novo: removidos podem voltar, banidos e membros atuais não.
+func (p *PostgresDB) checkReturningPlayer(ctx context.Context, q sqlx.QueryerContext, campaignID, userID int) error {
+	var status string
+	query := `SELECT status FROM campaign_players WHERE campaign_id = $1 AND user_id = $2`
+	if err := sqlx.GetContext(ctx, q, &status, query, campaignID, userID); err != nil {
+		return fmt.Errorf("failed to check player status: %w", err)
+	}
+
+	switch status {
+	case models.PlayerStatusRemoved:
+		return nil
+	case models.PlayerStatusBanned:
+		return fmt.Errorf("player is banned from this campaign")
+	default:
+		return fmt.Errorf("player already in campaign")
+	}
+}
+
+// RemoveCampaignPlayer marca o jogador como removido ou banido e aposenta os personagens
+// dele na campanha pela máquina de status, em nome de performedBy. Mortos ficam como estão,
+// assim como personagens sem transição para retired (ex.: inconscientes). Retorna quantos
+// personagens foram aposentados.
+func (p *PostgresDB) RemoveCampaignPlayer(ctx context.Context, campaignID, userID int, status string, performedBy int) (int, error) {
+	tx, err := p.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// O banimento vale mesmo para quem já saiu da campanha (ou nunca entrou); a remoção
+	// exige um membro na campanha
+	query := `
+		UPDATE campaign_players SET status = $1, role = 'player'
+		WHERE campaign_id = $2 AND user_id = $3 AND status <> 'banned'
+	`
+	if status == models.PlayerStatusBanned {
+		query = `
+			INSERT INTO campaign_players (campaign_id, user_id, joined_at, status, role)
+			VALUES ($2, $3, CURRENT_TIMESTAMP, $1, 'player')
+			ON CONFLICT (campaign_id, user_id)
+			DO UPDATE SET status = EXCLUDED.status, role = 'player'
+			WHERE campaign_players.status <> 'banned'
+		`
+	}
+	result, err := tx.ExecContext(ctx, query, status, campaignID, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update player status: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		if status == models.PlayerStatusBanned {
+			return 0, ErrPlayerBanned
+		}
+		return 0, ErrPlayerNotFound
+	}
+
+	characters := []models.CampaignCharacter{}
+	err = tx.SelectContext(ctx, &characters, `
+		SELECT id, campaign_id, source_pc_id, name, level, current_hp,
+		       COALESCE(campaign_notes, '') AS campaign_notes, status
+		FROM campaign_characters
+		WHERE campaign_id = $1 AND player_id = $2
+		ORDER BY id
+		FOR UPDATE
+	`, campaignID, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch player characters: %w", err)
+	}
+
+	retired := 0
+	for i := range characters {
+		character := &characters[i]
+		if character.Status == models.CharacterStatusDead ||
+			models.ValidateCharacterStatusTransition(character.Status, models.CharacterStatusRetired) != nil {
+			continue
+		}
+
+		event := models.CharacterStatusEvent{
+			From:          character.Status,
+			To:            models.CharacterStatusRetired,
+			ChangedBy:     performedBy,
+			VersionAction: models.VersionActionRetire,
+		}
+		if _, err := changeCampaignCharacterStatusTx(ctx, tx, character, event); err != nil {
+			return 0, err
+		}
+		retired++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit player removal: %w", err)
+	}
+
+	return retired, nil
+}
+
+// UnbanCampaignPlayer retira o banimento; o jogador fica como removido e pode voltar por convite
+func (p *PostgresDB) UnbanCampaignPlayer(ctx context.Context, campaignID, userID int) error {
+	result, err := p.DB.ExecContext(ctx, `
+		UPDATE campaign_players SET status = 'removed'
+		WHERE campaign_id = $1 AND user_id = $2 AND status = 'banned'
+	`, campaignID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to unban player: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrPlayerNotFound
+	}
+
+	return nil
+}
+
+// SetCampaignPlayerRole define o papel (player ou co_dm) de um jogador ativo
+func (p *PostgresDB) SetCampaignPlayerRole(ctx context.Context, campaignID, userID int, role string) error {
+	result, err := p.DB.ExecContext(ctx, `
+		UPDATE campaign_players SET role = $1
+		WHERE campaign_id = $2 AND user_id = $3 AND status = 'active'
+	`, role, campaignID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to update player role: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrPlayerNotFound
+	}
+
+	return nil
+}
+
+// TransferCampaignOwnership passa o dm_id para um jogador ativo. O antigo DM continua
+// na campanha como co-DM e o novo DM deixa a lista de jogadores.
+func (p *PostgresDB) TransferCampaignOwnership(ctx context.Context, campaignID, fromUserID, toUserID int) error {
+	tx, err := p.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var status string
+	statusQuery := `SELECT status FROM campaign_players WHERE campaign_id = $1 AND user_id = $2 FOR UPDATE`
+	if err := tx.GetContext(ctx, &status, statusQuery, campaignID, toUserID); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrPlayerNotFound
+		}
+		return fmt.Errorf("failed to fetch new DM membership: %w", err)
+	}
+	if status != models.PlayerStatusActive {
+		return ErrPlayerNotFound
+	}
+
+	now := time.Now()
+	result, err := tx.ExecContext(ctx, `
+		UPDATE campaigns SET dm_id = $1, updated_at = $2
+		WHERE id = $3 AND dm_id = $4
+	`, toUserID, now, campaignID, fromUserID)
+	if err != nil {
+		return fmt.Errorf("failed to transfer campaign: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("campaign not found or user not authorized")
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM campaign_players WHERE campaign_id = $1 AND user_id = $2`, campaignID, toUserID); err != nil {
+		return fmt.Errorf("failed to remove new DM from players: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO campaign_players (campaign_id, user_id, joined_at, status, role)
+		VALUES ($1, $2, $3, 'active', 'co_dm')
+		ON CONFLICT (campaign_id, user_id)
+		DO UPDATE SET status = 'active', role = 'co_dm'
+	`, campaignID, fromUserID, now)
+	if err != nil {
+		return fmt.Errorf("failed to keep previous DM as co-DM: %w", err)
+	}
+
+	return tx.Commit()
+}
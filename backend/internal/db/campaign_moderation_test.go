@@ -0,0 +1,158 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+
+	"rpg-saas-backend/internal/models"
+)
+
+func TestRemoveCampaignPlayer(t *testing.T) {
+	t.Run("retires characters", func(t *testing.T) {
+		pdb, mock, cleanup := newMockCampaignDB(t)
+		defer cleanup()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT INTO campaign_players .* ON CONFLICT`).WithArgs("banned", 1, 7).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		expectPlayerCharacters(mock, 1, 7, []string{"active", "inactive", "resurrected"})
+		for i, from := range []string{"active", "inactive", "resurrected"} {
+			expectCharacterRetired(mock, 4+i, 1, from)
+		}
+		mock.ExpectCommit()
+
+		retired, err := pdb.RemoveCampaignPlayer(context.Background(), 1, 7, "banned", 99)
+		if err != nil {
+			t.Fatalf("expected removal to succeed, got %v", err)
+		}
+		if retired != 3 {
+			t.Fatalf("expected 3 retired characters, got %d", retired)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+
+	t.Run("keeps dead and unconscious characters", func(t *testing.T) {
+		pdb, mock, cleanup := newMockCampaignDB(t)
+		defer cleanup()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE campaign_players SET status = \$1`).WithArgs("removed", 1, 7).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		// 4 morto, 5 inconsciente, 6 já aposentado: só o 7 é aposentado
+		expectPlayerCharacters(mock, 1, 7, []string{"dead", "unconscious", "retired", "active"})
+		expectCharacterRetired(mock, 7, 1, "active")
+		mock.ExpectCommit()
+
+		retired, err := pdb.RemoveCampaignPlayer(context.Background(), 1, 7, "removed", 99)
+		if err != nil {
+			t.Fatalf("expected removal to succeed, got %v", err)
+		}
+		if retired != 1 {
+			t.Fatalf("expected 1 retired character, got %d", retired)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+
+	t.Run("not a member", func(t *testing.T) {
+		pdb, mock, cleanup := newMockCampaignDB(t)
+		defer cleanup()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE campaign_players SET status = \$1`).WithArgs("removed", 1, 7).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		if _, err := pdb.RemoveCampaignPlayer(context.Background(), 1, 7, "removed", 99); !errors.Is(err, ErrPlayerNotFound) {
+			t.Fatalf("expected ErrPlayerNotFound, got %v", err)
+		}
+	})
+}
+
+// expectPlayerCharacters espera a leitura dos personagens do jogador, com IDs a partir de 4
+func expectPlayerCharacters(mock sqlmock.Sqlmock, campaignID, userID int, statuses []string) {
+	rows := sqlmock.NewRows([]string{"id", "campaign_id", "source_pc_id", "name", "level", "current_hp", "campaign_notes", "status"})
+	for i, status := range statuses {
+		rows.AddRow(4+i, campaignID, 20+i, "Aria", 3, nil, "", status)
+	}
+	mock.ExpectQuery(`FROM campaign_characters\s+WHERE campaign_id = \$1 AND player_id = \$2`).
+		WithArgs(campaignID, userID).WillReturnRows(rows)
+}
+
+// expectCharacterRetired espera a aposentadoria pela máquina de status, com a versão "retire"
+func expectCharacterRetired(mock sqlmock.Sqlmock, characterID, campaignID int, from string) {
+	expectVersionHistory(mock, models.CharacterTypeCampaign, characterID)
+	mock.ExpectExec(`UPDATE campaign_characters SET`).
+		WithArgs(nil, models.CharacterStatusRetired, "", characterID, campaignID, from).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`SELECT p.is_unique AND NOT EXISTS`).
+		WillReturnRows(sqlmock.NewRows([]string{"released"}).AddRow(false))
+	expectCampaignCharacterVersion(mock, characterID, campaignID, models.VersionActionRetire)
+}
+
+func TestRedeemCampaignInvite_ReturningPlayers(t *testing.T) {
+	t.Run("removed player can rejoin", func(t *testing.T) {
+		pdb, mock, cleanup := newMockCampaignDB(t)
+		defer cleanup()
+
+		expectInviteLookup(mock, nil, 0, 0, false, false, false)
+		mock.ExpectQuery(`SELECT c.dm_id, c.max_players`).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"dm_id", "max_players", "count"}).AddRow(99, 4, 2))
+		mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM campaign_players`).WithArgs(1, 7).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+		mock.ExpectQuery(`SELECT status FROM campaign_players`).WithArgs(1, 7).
+			WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("removed"))
+		mock.ExpectExec(`INSERT INTO campaign_players .* ON CONFLICT`).WithArgs(1, 7, sqlmock.AnyArg(), "active").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`UPDATE campaign_invites SET uses = uses \+ 1`).WithArgs(3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		result, err := pdb.RedeemCampaignInvite(context.Background(), "WXYZ9876", 7)
+		if err != nil {
+			t.Fatalf("expected removed player to rejoin, got %v", err)
+		}
+		if result.Status != "active" {
+			t.Fatalf("unexpected result: %+v", result)
+		}
+	})
+
+	t.Run("banned player is refused", func(t *testing.T) {
+		pdb, mock, cleanup := newMockCampaignDB(t)
+		defer cleanup()
+
+		expectInviteLookup(mock, nil, 0, 0, false, false, false)
+		mock.ExpectQuery(`SELECT c.dm_id, c.max_players`).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"dm_id", "max_players", "count"}).AddRow(99, 4, 2))
+		mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM campaign_players`).WithArgs(1, 7).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+		mock.ExpectQuery(`SELECT status FROM campaign_players`).WithArgs(1, 7).
+			WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("banned"))
+		mock.ExpectRollback()
+
+		_, err := pdb.RedeemCampaignInvite(context.Background(), "WXYZ9876", 7)
+		if err == nil || err.Error() != "player is banned from this campaign" {
+			t.Fatalf("expected ban error, got %v", err)
+		}
+	})
+}
+
+func TestTransferCampaignOwnership_RequiresActivePlayer(t *testing.T) {
+	pdb, mock, cleanup := newMockCampaignDB(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT status FROM campaign_players`).WithArgs(1, 8).
+		WillReturnRows(sqlmock.NewRows([]string{"status"}))
+	mock.ExpectRollback()
+
+	if err := pdb.TransferCampaignOwnership(context.Background(), 1, 7, 8); !errors.Is(err, ErrPlayerNotFound) {
+		t.Fatalf("expected ErrPlayerNotFound, got %v", err)
+	}
+}
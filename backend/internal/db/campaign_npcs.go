@@ -0,0 +1,100 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"rpg-saas-backend/internal/models"
+)
+
+const npcColumns = `
+	id, name, COALESCE(description, '') AS description, COALESCE(level, 1) AS level,
+	COALESCE(race, '') AS race, COALESCE(class, '') AS class, COALESCE(background, '') AS background,
+	attributes, abilities, equipment, COALESCE(hp, 0) AS hp, COALESCE(ca, 0) AS ca,
+	COALESCE(is_homebrew, false) AS is_homebrew, campaign_id, revealed, created_at
+`
+
+const encounterColumns = `
+	id, COALESCE(theme, '') AS theme, COALESCE(difficulty, '') AS difficulty, COALESCE(total_xp, 0) AS total_xp,
+	COALESCE(player_level, 0) AS player_level, COALESCE(player_count, 0) AS player_count,
+	campaign_id, revealed, xp_awarded, created_at
+`
+
+// GetCampaignNPCs lista os NPCs vinculados à campanha; com revealedOnly, apenas os
+// revelados aos players
+func (p *PostgresDB) GetCampaignNPCs(ctx context.Context, campaignID int, revealedOnly bool) ([]models.NPC, error) {
+	npcs := []models.NPC{}
+	query := `SELECT ` + npcColumns + ` FROM npcs WHERE campaign_id = $1`
+	if revealedOnly {
+		query += ` AND revealed`
+	}
+	query += ` ORDER BY id`
+
+	if err := p.DB.SelectContext(ctx, &npcs, query, campaignID); err != nil {
+		return nil, fmt.Errorf("failed to fetch NPCs for campaign %d: %w", campaignID, err)
+	}
+
+	return npcs, nil
+}
+
+// GetCampaignEncounters lista os encontros vinculados à campanha, com seus monstros; com
+// revealedOnly, apenas os revelados aos players
+func (p *PostgresDB) GetCampaignEncounters(ctx context.Context, campaignID int, revealedOnly bool) ([]models.Encounter, error) {
+	encounters := []models.Encounter{}
+	query := `SELECT ` + encounterColumns + ` FROM encounters WHERE campaign_id = $1`
+	if revealedOnly {
+		query += ` AND revealed`
+	}
+	query += ` ORDER BY id`
+
+	if err := p.DB.SelectContext(ctx, &encounters, query, campaignID); err != nil {
+		return nil, fmt.Errorf("failed to fetch encounters for campaign %d: %w", campaignID, err)
+	}
+
+	for i := range encounters {
+		monsters, err := p.GetMonstersByEncounterID(ctx, encounters[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		encounters[i].Monsters = monsters
+	}
+
+	return encounters, nil
+}
+
+// SetNPCRevealed revela ou esconde um NPC da campanha (nil, nil se não existir)
+func (p *PostgresDB) SetNPCRevealed(ctx context.Context, campaignID, npcID int, revealed bool) (*models.NPC, error) {
+	var npc models.NPC
+	query := `UPDATE npcs SET revealed = $1 WHERE id = $2 AND campaign_id = $3 RETURNING ` + npcColumns
+
+	if err := p.DB.GetContext(ctx, &npc, query, revealed, npcID, campaignID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to update NPC %d: %w", npcID, err)
+	}
+
+	return &npc, nil
+}
+
+// SetEncounterRevealed revela ou esconde um encontro da campanha (nil, nil se não existir)
+func (p *PostgresDB) SetEncounterRevealed(ctx context.Context, campaignID, encounterID int, revealed bool) (*models.Encounter, error) {
+	var encounter models.Encounter
+	query := `UPDATE encounters SET revealed = $1 WHERE id = $2 AND campaign_id = $3 RETURNING ` + encounterColumns
+
+	if err := p.DB.GetContext(ctx, &encounter, query, revealed, encounterID, campaignID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to update encounter %d: %w", encounterID, err)
+	}
+
+	monsters, err := p.GetMonstersByEncounterID(ctx, encounter.ID)
+	if err != nil {
+		return nil, err
+	}
+	encounter.Monsters = monsters
+
+	return &encounter, nil
+}
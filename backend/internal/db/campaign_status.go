@@ -0,0 +1,173 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"rpg-saas-backend/internal/models"
+)
+
+var (
+	// ErrCampaignStatusChanged indica que o status mudou desde a leitura da campanha
+	ErrCampaignStatusChanged = errors.New("campaign status changed concurrently")
+	// ErrCampaignWithoutPlayers indica campanha sem players ativos ao tentar ativá-la
+	ErrCampaignWithoutPlayers = errors.New("campaign needs at least one active player to become active")
+)
+
+// campaignStatusHook é um efeito colateral de uma mudança de status da campanha, executado
+// na mesma transação. Cada hook decide, pelo evento, se se aplica.
+type campaignStatusHook func(ctx context.Context, tx *sqlx.Tx, campaignID int, event models.CampaignStatusEvent, change *models.CampaignStatusChange) error
+
+var campaignStatusHooks = []campaignStatusHook{
+	requireCampaignPlayers,
+	closeCampaignRooms,
+	freezeCampaignCharacters,
+}
+
+// ChangeCampaignStatus grava o novo status e executa os hooks da transição. A transição já
+// deve ter sido validada; se o status no banco não for mais event.From, nada é alterado.
+// Fechar as conexões ao vivo das salas (change.RoomIDs) fica a cargo de quem chama.
+func (p *PostgresDB) ChangeCampaignStatus(ctx context.Context, campaignID int, event models.CampaignStatusEvent) (*models.CampaignStatusChange, error) {
+	return p.UpdateCampaignWithStatus(ctx, nil, campaignID, &event)
+}
+
+// UpdateCampaignWithStatus grava a mudança de status (se event não for nil) e os demais campos
+// da campanha (se campaign não for nil) numa única transação: um erro em qualquer etapa desfaz
+// também os hooks do status. Os efeitos fora do banco ficam para depois do commit.
+func (p *PostgresDB) UpdateCampaignWithStatus(ctx context.Context, campaign *models.Campaign, campaignID int, event *models.CampaignStatusEvent) (*models.CampaignStatusChange, error) {
+	tx, err := p.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var change *models.CampaignStatusChange
+	if event != nil {
+		if change, err = changeCampaignStatusTx(ctx, tx, campaignID, *event); err != nil {
+			return nil, err
+		}
+	}
+
+	if campaign != nil {
+		if err := updateCampaignTx(ctx, tx, campaign); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit campaign status change: %w", err)
+	}
+
+	return change, nil
+}
+
+func changeCampaignStatusTx(ctx context.Context, tx *sqlx.Tx, campaignID int, event models.CampaignStatusEvent) (*models.CampaignStatusChange, error) {
+	result, err := tx.ExecContext(ctx, `
+		UPDATE campaigns SET status = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2 AND status = $3
+	`, event.To, campaignID, event.From)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update campaign status: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		return nil, ErrCampaignStatusChanged
+	}
+
+	change := &models.CampaignStatusChange{From: event.From, To: event.To}
+	for _, hook := range campaignStatusHooks {
+		if err := hook(ctx, tx, campaignID, event, change); err != nil {
+			return nil, err
+		}
+	}
+
+	return change, nil
+}
+
+// GetCampaignStatus retorna o status da campanha, sem checar acesso
+func (p *PostgresDB) GetCampaignStatus(ctx context.Context, campaignID int) (string, error) {
+	var status string
+	if err := p.DB.GetContext(ctx, &status, `SELECT status FROM campaigns WHERE id = $1`, campaignID); err != nil {
+		return "", fmt.Errorf("failed to fetch status of campaign %d: %w", campaignID, err)
+	}
+	return status, nil
+}
+
+// requireCampaignPlayers impede ativar uma campanha sem nenhum player ativo (co-DMs não contam)
+func requireCampaignPlayers(ctx context.Context, tx *sqlx.Tx, campaignID int, event models.CampaignStatusEvent, _ *models.CampaignStatusChange) error {
+	if event.To != models.CampaignStatusActive {
+		return nil
+	}
+
+	var players int
+	err := tx.GetContext(ctx, &players, `
+		SELECT COUNT(*) FROM campaign_players
+		WHERE campaign_id = $1 AND status = 'active' AND role = 'player'
+	`, campaignID)
+	if err != nil {
+		return fmt.Errorf("failed to count players of campaign %d: %w", campaignID, err)
+	}
+	if players == 0 {
+		return ErrCampaignWithoutPlayers
+	}
+	return nil
+}
+
+// closeCampaignRooms lista as salas da campanha ao pausá-la e as arquiva ao concluí-la
+func closeCampaignRooms(ctx context.Context, tx *sqlx.Tx, campaignID int, event models.CampaignStatusEvent, change *models.CampaignStatusChange) error {
+	var query string
+	switch event.To {
+	case models.CampaignStatusPaused:
+		query = `SELECT id FROM rooms WHERE campaign_id = $1 AND archived_at IS NULL`
+	case models.CampaignStatusCompleted:
+		query = `
+			UPDATE rooms SET archived_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+			WHERE campaign_id = $1 AND archived_at IS NULL
+			RETURNING id
+		`
+	default:
+		return nil
+	}
+
+	roomIDs := []string{}
+	if err := tx.SelectContext(ctx, &roomIDs, query, campaignID); err != nil {
+		return fmt.Errorf("failed to close rooms of campaign %d: %w", campaignID, err)
+	}
+
+	change.RoomIDs = roomIDs
+	return nil
+}
+
+// freezeCampaignCharacters congela as fichas ao concluir a campanha e grava a versão final
+// de cada personagem
+func freezeCampaignCharacters(ctx context.Context, tx *sqlx.Tx, campaignID int, event models.CampaignStatusEvent, change *models.CampaignStatusChange) error {
+	if event.To != models.CampaignStatusCompleted {
+		return nil
+	}
+
+	characterIDs := []int{}
+	err := tx.SelectContext(ctx, &characterIDs, `
+		UPDATE campaign_characters SET frozen_at = CURRENT_TIMESTAMP
+		WHERE campaign_id = $1 AND frozen_at IS NULL
+		RETURNING id
+	`, campaignID)
+	if err != nil {
+		return fmt.Errorf("failed to freeze characters of campaign %d: %w", campaignID, err)
+	}
+
+	for _, characterID := range characterIDs {
+		if _, err := recordCampaignCharacterVersionTx(ctx, tx, characterID, &event.ChangedBy, models.VersionActionFreeze); err != nil {
+			return err
+		}
+	}
+
+	change.FrozenCharacters = len(characterIDs)
+	return nil
+}
@@ -0,0 +1,83 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+
+	"rpg-saas-backend/internal/models"
+)
+
+func TestChangeCampaignStatus(t *testing.T) {
+	t.Run("completing archives rooms and freezes characters", func(t *testing.T) {
+		pdb, mock, cleanup := newMockCampaignDB(t)
+		defer cleanup()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE campaigns SET status = \$1`).
+			WithArgs(models.CampaignStatusCompleted, 1, models.CampaignStatusPaused).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(`UPDATE rooms SET archived_at`).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("room-1"))
+		mock.ExpectQuery(`UPDATE campaign_characters SET frozen_at`).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+		expectCampaignCharacterVersion(mock, 5, 1, models.VersionActionFreeze)
+		mock.ExpectCommit()
+
+		change, err := pdb.ChangeCampaignStatus(context.Background(), 1, models.CampaignStatusEvent{
+			From: models.CampaignStatusPaused, To: models.CampaignStatusCompleted, ChangedBy: 7,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(change.RoomIDs) != 1 || change.RoomIDs[0] != "room-1" || change.FrozenCharacters != 1 {
+			t.Fatalf("expected one archived room and one frozen character, got %+v", change)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+
+	t.Run("activating requires a player", func(t *testing.T) {
+		pdb, mock, cleanup := newMockCampaignDB(t)
+		defer cleanup()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE campaigns SET status = \$1`).
+			WithArgs(models.CampaignStatusActive, 1, models.CampaignStatusPlanning).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FROM campaign_players`).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectRollback()
+
+		_, err := pdb.ChangeCampaignStatus(context.Background(), 1, models.CampaignStatusEvent{
+			From: models.CampaignStatusPlanning, To: models.CampaignStatusActive, ChangedBy: 7,
+		})
+		if !errors.Is(err, ErrCampaignWithoutPlayers) {
+			t.Fatalf("expected ErrCampaignWithoutPlayers, got %v", err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+
+	t.Run("concurrent change is rejected", func(t *testing.T) {
+		pdb, mock, cleanup := newMockCampaignDB(t)
+		defer cleanup()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE campaigns SET status = \$1`).
+			WithArgs(models.CampaignStatusPaused, 1, models.CampaignStatusActive).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		_, err := pdb.ChangeCampaignStatus(context.Background(), 1, models.CampaignStatusEvent{
+			From: models.CampaignStatusActive, To: models.CampaignStatusPaused, ChangedBy: 7,
+		})
+		if !errors.Is(err, ErrCampaignStatusChanged) {
+			t.Fatalf("expected ErrCampaignStatusChanged, got %v", err)
+		}
+	})
+}
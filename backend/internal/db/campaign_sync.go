@@ -0,0 +1,118 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"rpg-saas-backend/internal/models"
+)
+
+// GetCampaignCharacterSyncBase retorna o estado compartilhado registrado no último sync
+// (nil se o personagem nunca foi sincronizado)
+func (p *PostgresDB) GetCampaignCharacterSyncBase(ctx context.Context, charID int) (models.JSONB, error) {
+	var base models.JSONB
+	query := `SELECT sync_base FROM campaign_characters WHERE id = $1`
+
+	if err := p.DB.GetContext(ctx, &base, query, charID); err != nil {
+		return nil, fmt.Errorf("failed to fetch sync base for campaign character %d: %w", charID, err)
+	}
+
+	return base, nil
+}
+
+// GetCampaignCharactersBySourcePC retorna todos os snapshots criados a partir do mesmo PC
+func (p *PostgresDB) GetCampaignCharactersBySourcePC(ctx context.Context, sourcePCID int) ([]models.CampaignCharacter, error) {
+	characters := []models.CampaignCharacter{}
+	query := `
+		SELECT
+			id, campaign_id, player_id, source_pc_id, status,
+			joined_at, last_sync, campaign_notes, sync_base,
+			name, description, level, race, class, class_levels, background,
+			alignment, attributes, abilities, equipment, hp,
+			current_hp, ca, proficiency_bonus, inspiration,
+			skills, attacks, spells, personality_traits, ideals,
+			bonds, flaws, features, player_name, level_up_available, frozen_at
+		FROM campaign_characters
+		WHERE source_pc_id = $1 AND status != 'removed'
+		ORDER BY campaign_id
+	`
+
+	if err := p.DB.SelectContext(ctx, &characters, query, sourcePCID); err != nil {
+		return nil, fmt.Errorf("failed to fetch campaign characters for PC %d: %w", sourcePCID, err)
+	}
+
+	return characters, nil
+}
+
+// markCampaignCharacterSyncedTx grava o novo estado base e atualiza last_sync
+func markCampaignCharacterSyncedTx(ctx context.Context, q sqlx.ExecerContext, charID int, base models.JSONB) error {
+	query := `
+		UPDATE campaign_characters SET
+		sync_base = $1, last_sync = CURRENT_TIMESTAMP
+		WHERE id = $2
+	`
+
+	result, err := q.ExecContext(ctx, query, base, charID)
+	if err != nil {
+		return fmt.Errorf("failed to mark campaign character %d as synced: %w", charID, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("character not found in campaign")
+	}
+
+	return nil
+}
+
+// ApplyCharacterSync grava um sync inteiro em uma transação: o PC (quando pc não é nil), os
+// snapshots alterados, a nova base de cada snapshot e as versões correspondentes. Uma falha no
+// meio desfaz tudo.
+func (p *PostgresDB) ApplyCharacterSync(ctx context.Context, pc *models.PC, snapshots []models.SyncedSnapshot, authorID int) error {
+	tx, err := p.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if pc != nil {
+		if err := recordBaselineVersionTx(ctx, tx, models.CharacterTypePC, pc.ID); err != nil {
+			return err
+		}
+		if err := updatePCTx(ctx, tx, pc); err != nil {
+			return err
+		}
+		if _, err := recordPCVersionTx(ctx, tx, pc.ID, &authorID, models.VersionActionSync); err != nil {
+			return err
+		}
+	}
+
+	for _, snapshot := range snapshots {
+		if snapshot.Changed {
+			if err := recordBaselineVersionTx(ctx, tx, models.CharacterTypeCampaign, snapshot.Character.ID); err != nil {
+				return err
+			}
+			if err := updateCampaignCharacterFullTx(ctx, tx, snapshot.Character); err != nil {
+				return err
+			}
+			if _, err := recordCampaignCharacterVersionTx(ctx, tx, snapshot.Character.ID, &authorID, models.VersionActionSync); err != nil {
+				return err
+			}
+		}
+		if err := markCampaignCharacterSyncedTx(ctx, tx, snapshot.Character.ID, snapshot.Base); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit character sync: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"rpg-saas-backend/internal/models"
+)
+
+// GetCampaignTemplates lista os templates do usuário, sem o pacote
+func (p *PostgresDB) GetCampaignTemplates(ctx context.Context, ownerID int) ([]models.CampaignTemplate, error) {
+	templates := []models.CampaignTemplate{}
+	query := `
+		SELECT id, owner_id, name, description, source_campaign_id, created_at
+		FROM campaign_templates
+		WHERE owner_id = $1
+		ORDER BY created_at DESC
+	`
+
+	if err := p.DB.SelectContext(ctx, &templates, query, ownerID); err != nil {
+		return nil, fmt.Errorf("failed to fetch campaign templates: %w", err)
+	}
+
+	return templates, nil
+}
+
+// GetCampaignTemplate retorna um template do usuário com o pacote (nil, nil se não existir)
+func (p *PostgresDB) GetCampaignTemplate(ctx context.Context, id, ownerID int) (*models.CampaignTemplate, error) {
+	var template models.CampaignTemplate
+	query := `
+		SELECT id, owner_id, name, description, source_campaign_id, archive, created_at
+		FROM campaign_templates
+		WHERE id = $1 AND owner_id = $2
+	`
+
+	if err := p.DB.GetContext(ctx, &template, query, id, ownerID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch campaign template %d: %w", id, err)
+	}
+
+	return &template, nil
+}
+
+// CreateCampaignTemplate salva um template de campanha
+func (p *PostgresDB) CreateCampaignTemplate(ctx context.Context, template *models.CampaignTemplate) error {
+	query := `
+		INSERT INTO campaign_templates (owner_id, name, description, source_campaign_id, archive, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`
+
+	template.CreatedAt = time.Now()
+
+	err := p.DB.QueryRowContext(ctx, query,
+		template.OwnerID, template.Name, template.Description, template.SourceCampaignID,
+		template.Archive, template.CreatedAt,
+	).Scan(&template.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create campaign template: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteCampaignTemplate remove um template do usuário
+func (p *PostgresDB) DeleteCampaignTemplate(ctx context.Context, id, ownerID int) error {
+	result, err := p.DB.ExecContext(ctx, `DELETE FROM campaign_templates WHERE id = $1 AND owner_id = $2`, id, ownerID)
+	if err != nil {
+		return fmt.Errorf("failed to delete campaign template: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("campaign template not found")
+	}
+
+	return nil
+}
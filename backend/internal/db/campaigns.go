@@ -3,8 +3,11 @@ package db
 import (
 	"context"
 	"fmt"
+	"math"
 	"time"
 
+	"github.com/jmoiron/sqlx"
+
 	"rpg-saas-backend/internal/models"
 )
 
@@ -12,7 +15,7 @@ func (p *PostgresDB) GetCampaigns(ctx context.Context, userID int, limit, offset
 	campaigns := []models.CampaignSummary{}
 	query := `
 		SELECT
-			c.id, c.name, c.description, c.status, c.allow_homebrew, c.max_players,
+			c.id, c.name, c.description, c.status, c.allow_homebrew, c.leveling_mode, c.max_players,
 			c.current_session, c.invite_code, c.created_at, c.updated_at,
 			u.username as dm_name,
 			COUNT(cp.user_id) as player_count
@@ -23,7 +26,7 @@ func (p *PostgresDB) GetCampaigns(ctx context.Context, userID int, limit, offset
 			SELECT campaign_id FROM campaign_players
 			WHERE user_id = $1 AND status = 'active'
 		)
-		GROUP BY c.id, u.username, c.name, c.description, c.status, c.allow_homebrew, c.max_players,
+		GROUP BY c.id, u.username, c.name, c.description, c.status, c.allow_homebrew, c.leveling_mode, c.max_players,
 		         c.current_session, c.invite_code, c.created_at, c.updated_at
 		ORDER BY c.updated_at DESC
 		LIMIT $2 OFFSET $3
@@ -41,7 +44,7 @@ func (p *PostgresDB) GetCampaignByID(ctx context.Context, id, userID int) (*mode
 	var campaign models.Campaign
 	query := `
 		SELECT c.id, c.name, c.description, c.dm_id, c.max_players, c.current_session,
-		       c.status, c.allow_homebrew, c.invite_code, c.created_at, c.updated_at
+		       c.status, c.allow_homebrew, c.leveling_mode, c.invite_code, c.created_at, c.updated_at
 		FROM campaigns c
 		WHERE c.id = $1 AND (
 			c.dm_id = $2 OR c.id IN (
@@ -73,8 +76,8 @@ func (p *PostgresDB) GetCampaignByID(ctx context.Context, id, userID int) (*mode
 
 func (p *PostgresDB) CreateCampaign(ctx context.Context, campaign *models.Campaign) error {
 	query := `
-		INSERT INTO campaigns (name, description, dm_id, max_players, status, allow_homebrew, invite_code, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		INSERT INTO campaigns (name, description, dm_id, max_players, status, allow_homebrew, leveling_mode, invite_code, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 		RETURNING id
 	`
 
@@ -88,29 +91,40 @@ func (p *PostgresDB) CreateCampaign(ctx context.Context, campaign *models.Campai
 	if campaign.MaxPlayers == 0 {
 		campaign.MaxPlayers = 6
 	}
+	if campaign.LevelingMode == "" {
+		campaign.LevelingMode = models.LevelingModeXP
+	}
 
 	row := p.DB.QueryRowContext(ctx, query,
 		campaign.Name, campaign.Description, campaign.DMID,
-		campaign.MaxPlayers, campaign.Status, campaign.AllowHomebrew, campaign.InviteCode,
+		campaign.MaxPlayers, campaign.Status, campaign.AllowHomebrew, campaign.LevelingMode, campaign.InviteCode,
 		campaign.CreatedAt, campaign.UpdatedAt,
 	)
 
 	return row.Scan(&campaign.ID)
 }
 
+// UpdateCampaign atualiza a campanha; campaign.DMID deve ser o DM ou um co-DM ativo
 func (p *PostgresDB) UpdateCampaign(ctx context.Context, campaign *models.Campaign) error {
+	return updateCampaignTx(ctx, p.DB, campaign)
+}
+
+func updateCampaignTx(ctx context.Context, q sqlx.ExecerContext, campaign *models.Campaign) error {
 	query := `
 		UPDATE campaigns SET
 		name = $1, description = $2, max_players = $3,
-		current_session = $4, status = $5, allow_homebrew = $6, updated_at = $7
-		WHERE id = $8 AND dm_id = $9
+		current_session = $4, status = COALESCE(NULLIF($5, ''), status), allow_homebrew = $6, leveling_mode = COALESCE(NULLIF($7, ''), leveling_mode), updated_at = $8
+		WHERE id = $9 AND (dm_id = $10 OR EXISTS (
+			SELECT 1 FROM campaign_players
+			WHERE campaign_id = $9 AND user_id = $10 AND status = 'active' AND role = 'co_dm'
+		))
 	`
 
 	campaign.UpdatedAt = time.Now()
 
-	result, err := p.DB.ExecContext(ctx, query,
+	result, err := q.ExecContext(ctx, query,
 		campaign.Name, campaign.Description, campaign.MaxPlayers,
-		campaign.CurrentSession, campaign.Status, campaign.AllowHomebrew, campaign.UpdatedAt,
+		campaign.CurrentSession, campaign.Status, campaign.AllowHomebrew, campaign.LevelingMode, campaign.UpdatedAt,
 		campaign.ID, campaign.DMID,
 	)
 
@@ -153,7 +167,8 @@ func (p *PostgresDB) DeleteCampaign(ctx context.Context, id, dmID int) error {
 func (p *PostgresDB) GetCampaignPlayers(ctx context.Context, campaignID int) ([]models.CampaignPlayer, error) {
 	players := []models.CampaignPlayer{}
 	query := `
-		SELECT cp.*, u.username, u.email
+		SELECT cp.id, cp.campaign_id, cp.user_id, cp.joined_at, cp.status,
+		       u.username, u.email, COALESCE(cp.role, 'player') AS role
 		FROM campaign_players cp
 		LEFT JOIN users u ON cp.user_id = u.id
 		WHERE cp.campaign_id = $1
@@ -173,7 +188,7 @@ func (p *PostgresDB) GetCampaignPlayers(ctx context.Context, campaignID int) ([]
 		err := rows.Scan(
 			&player.ID, &player.CampaignID, &player.UserID,
 			&player.JoinedAt, &player.Status,
-			&user.Username, &user.Email,
+			&user.Username, &user.Email, &player.Role,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan campaign player: %w", err)
@@ -190,7 +205,7 @@ func (p *PostgresDB) GetCampaignPlayers(ctx context.Context, campaignID int) ([]
 func (p *PostgresDB) GetCampaignByInviteCode(ctx context.Context, inviteCode string) (*models.Campaign, error) {
 	var campaign models.Campaign
 	query := `
-		SELECT id, name, description, dm_id, max_players, current_session, status, allow_homebrew, invite_code, created_at, updated_at
+		SELECT id, name, description, dm_id, max_players, current_session, status, allow_homebrew, leveling_mode, invite_code, created_at, updated_at
 		FROM campaigns
 		WHERE invite_code = $1
 	`
@@ -203,21 +218,36 @@ func (p *PostgresDB) GetCampaignByInviteCode(ctx context.Context, inviteCode str
 	return &campaign, nil
 }
 
-func (p *PostgresDB) JoinCampaignByCode(ctx context.Context, inviteCode string, userID int) error {
+func (p *PostgresDB) JoinCampaignByCode(ctx context.Context, inviteCode string, userID int) (int, error) {
 	campaign, err := p.GetCampaignByInviteCode(ctx, inviteCode)
 	if err != nil {
-		return fmt.Errorf("invalid invite code")
+		return 0, ErrInvalidInviteCode
 	}
 
+	tx, err := p.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
 	var exists bool
 	checkQuery := `SELECT EXISTS(SELECT 1 FROM campaign_players WHERE campaign_id = $1 AND user_id = $2)`
-	err = p.DB.GetContext(ctx, &exists, checkQuery, campaign.ID, userID)
+	err = tx.GetContext(ctx, &exists, checkQuery, campaign.ID, userID)
 	if err != nil {
-		return fmt.Errorf("failed to check if player exists: %w", err)
+		return 0, fmt.Errorf("failed to check if player exists: %w", err)
 	}
 
 	if exists {
-		return fmt.Errorf("player already in campaign")
+		if err := p.checkReturningPlayer(ctx, tx, campaign.ID, userID); err != nil {
+			return 0, err
+		}
+	}
+
+	// A campanha fica travada até o commit: joins simultâneos não passam de max_players
+	var maxPlayers int
+	lockQuery := `SELECT max_players FROM campaigns WHERE id = $1 FOR UPDATE`
+	if err := tx.GetContext(ctx, &maxPlayers, lockQuery, campaign.ID); err != nil {
+		return 0, fmt.Errorf("failed to lock campaign: %w", err)
 	}
 
 	var playerCount int
@@ -226,30 +256,36 @@ func (p *PostgresDB) JoinCampaignByCode(ctx context.Context, inviteCode string,
 		FROM campaign_players cp
 		WHERE cp.campaign_id = $1 AND cp.status = 'active'
 	`
-	err = p.DB.GetContext(ctx, &playerCount, countQuery, campaign.ID)
+	err = tx.GetContext(ctx, &playerCount, countQuery, campaign.ID)
 	if err != nil {
-		return fmt.Errorf("failed to check campaign capacity: %w", err)
+		return 0, fmt.Errorf("failed to check campaign capacity: %w", err)
 	}
 
-	if playerCount >= campaign.MaxPlayers {
-		return fmt.Errorf("campaign is full")
+	if playerCount >= maxPlayers {
+		return 0, ErrCampaignFull
 	}
 
 	if campaign.DMID == userID {
-		return fmt.Errorf("DM cannot join their own campaign as a player")
+		return 0, fmt.Errorf("DM cannot join their own campaign as a player")
 	}
 
 	query := `
 		INSERT INTO campaign_players (campaign_id, user_id, joined_at, status)
 		VALUES ($1, $2, $3, 'active')
+		ON CONFLICT (campaign_id, user_id)
+		DO UPDATE SET status = 'active', role = 'player', joined_at = EXCLUDED.joined_at
 	`
 
-	_, err = p.DB.ExecContext(ctx, query, campaign.ID, userID, time.Now())
+	_, err = tx.ExecContext(ctx, query, campaign.ID, userID, time.Now())
 	if err != nil {
-		return fmt.Errorf("failed to add player to campaign: %w", err)
+		return 0, fmt.Errorf("failed to add player to campaign: %w", err)
 	}
 
-	return nil
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit campaign join: %w", err)
+	}
+
+	return campaign.ID, nil
 }
 
 func (p *PostgresDB) AddPlayerToCampaign(ctx context.Context, campaignID, userID int) error {
@@ -296,8 +332,13 @@ func (p *PostgresDB) AddPlayerToCampaign(ctx context.Context, campaignID, userID
 	return nil
 }
 
+// RemovePlayerFromCampaign registra a saída do jogador. A linha fica como removida, para que
+// o jogador possa voltar por convite; banidos não podem sair, pois apagariam o banimento.
 func (p *PostgresDB) RemovePlayerFromCampaign(ctx context.Context, campaignID, userID int) error {
-	query := `DELETE FROM campaign_players WHERE campaign_id = $1 AND user_id = $2`
+	query := `
+		UPDATE campaign_players SET status = 'removed', role = 'player'
+		WHERE campaign_id = $1 AND user_id = $2 AND status NOT IN ('removed', 'banned')
+	`
 
 	result, err := p.DB.ExecContext(ctx, query, campaignID, userID)
 	if err != nil {
@@ -310,7 +351,15 @@ func (p *PostgresDB) RemovePlayerFromCampaign(ctx context.Context, campaignID, u
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("player not found in campaign")
+		var banned bool
+		bannedQuery := `SELECT EXISTS(SELECT 1 FROM campaign_players WHERE campaign_id = $1 AND user_id = $2 AND status = 'banned')`
+		if err := p.DB.GetContext(ctx, &banned, bannedQuery, campaignID, userID); err != nil {
+			return fmt.Errorf("failed to check player status: %w", err)
+		}
+		if banned {
+			return ErrPlayerBanned
+		}
+		return ErrPlayerNotFound
 	}
 
 	return nil
@@ -343,6 +392,7 @@ func (p *PostgresDB) GetCampaignCharacters(ctx context.Context, campaignID int)
 			cc.current_hp, cc.ca, cc.proficiency_bonus, cc.inspiration,
 			cc.skills, cc.attacks, cc.spells, cc.personality_traits, cc.ideals,
 			cc.bonds, cc.flaws, cc.features, cc.player_name,
+			cc.experience_points, cc.level_up_available, cc.class_levels,
 			u.username as player_username
 		FROM campaign_characters cc
 		LEFT JOIN users u ON cc.player_id = u.id
@@ -369,6 +419,7 @@ func (p *PostgresDB) GetCampaignCharacters(ctx context.Context, campaignID int)
 			&character.CA, &character.ProficiencyBonus, &character.Inspiration, &character.Skills,
 			&character.Attacks, &character.Spells, &character.PersonalityTraits, &character.Ideals,
 			&character.Bonds, &character.Flaws, &character.Features, &character.PlayerName,
+			&character.ExperiencePoints, &character.LevelUpAvailable, &character.ClassLevels,
 			&playerUsername,
 		)
 		if err != nil {
@@ -394,7 +445,7 @@ func (p *PostgresDB) GetAvailablePCs(ctx context.Context, userID, campaignID int
 	fmt.Println("Fetching available PCs for user:", userID, "in campaign:", campaignID)
 	query := `
 		SELECT 
-			pc.id, pc.name, pc.description, pc.level, pc.race, pc.class, 
+			pc.id, pc.name, pc.description, pc.level, pc.race, pc.class, pc.class_levels,
 			pc.background, pc.alignment, pc.attributes, pc.abilities, 
 			pc.equipment, pc.hp, pc.ca, pc.player_name, pc.player_id, 
 			pc.created_at
@@ -404,7 +455,7 @@ func (p *PostgresDB) GetAvailablePCs(ctx context.Context, userID, campaignID int
 			SELECT COALESCE(cc.source_pc_id, 0)
 			FROM campaign_characters cc 
 			WHERE cc.campaign_id = $2 
-			AND cc.status IN ('active', 'inactive')
+			AND cc.status IN ('active', 'inactive', 'unconscious', 'dead', 'resurrected')
 		)
 		ORDER BY pc.name
 	`
@@ -417,22 +468,29 @@ func (p *PostgresDB) GetAvailablePCs(ctx context.Context, userID, campaignID int
 	return pcs, nil
 }
 
-func (p *PostgresDB) AddPCToCampaign(ctx context.Context, campaignChar *models.CampaignCharacter) error {
+// AddPCToCampaign grava o snapshot do PC na campanha e sua primeira versão na mesma transação
+func (p *PostgresDB) AddPCToCampaign(ctx context.Context, campaignChar *models.CampaignCharacter, authorID int) error {
+	tx, err := p.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
 	query := `
 		INSERT INTO campaign_characters (
 			campaign_id, player_id, source_pc_id, status, joined_at, campaign_notes,
 			name, description, level, race, class, background, alignment, 
 			attributes, abilities, equipment, hp, current_hp, ca, proficiency_bonus,
 			inspiration, skills, attacks, spells, personality_traits, ideals, 
-			bonds, flaws, features, player_name
+			bonds, flaws, features, player_name, class_levels
 		) VALUES (
 			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, 
-			$17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30
+			$17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31
 		)
 		RETURNING id
 	`
 
-	err := p.DB.QueryRowContext(ctx, query,
+	err = tx.QueryRowContext(ctx, query,
 		campaignChar.CampaignID, campaignChar.PlayerID, campaignChar.SourcePCID,
 		campaignChar.Status, campaignChar.JoinedAt, campaignChar.CampaignNotes,
 		campaignChar.Name, campaignChar.Description, campaignChar.Level,
@@ -443,18 +501,26 @@ func (p *PostgresDB) AddPCToCampaign(ctx context.Context, campaignChar *models.C
 		campaignChar.Skills, campaignChar.Attacks, campaignChar.Spells,
 		campaignChar.PersonalityTraits, campaignChar.Ideals, campaignChar.Bonds,
 		campaignChar.Flaws, campaignChar.Features, campaignChar.PlayerName,
+		campaignChar.ClassLevels,
 	).Scan(&campaignChar.ID)
 
 	if err != nil {
 		return fmt.Errorf("failed to add PC to campaign: %w", err)
 	}
 
+	if _, err := recordCampaignCharacterVersionTx(ctx, tx, campaignChar.ID, &authorID, models.VersionActionCreate); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit campaign character: %w", err)
+	}
 	return nil
 }
 
 func (p *PostgresDB) IsPCInCampaign(ctx context.Context, campaignID, pcID int) (bool, error) {
 	var exists bool
-	query := `SELECT EXISTS(SELECT 1 FROM campaign_characters WHERE campaign_id = $1 AND source_pc_id = $2 AND status IN ('active', 'inactive'))`
+	query := `SELECT EXISTS(SELECT 1 FROM campaign_characters WHERE campaign_id = $1 AND source_pc_id = $2 AND status IN ('active', 'inactive', 'unconscious', 'dead', 'resurrected'))`
 
 	err := p.DB.GetContext(ctx, &exists, query, campaignID, pcID)
 	if err != nil {
@@ -474,7 +540,8 @@ func (p *PostgresDB) GetCampaignCharacter(ctx context.Context, charID, campaignI
 			cc.alignment, cc.attributes, cc.abilities, cc.equipment, cc.hp, 
 			cc.current_hp, cc.ca, cc.proficiency_bonus, cc.inspiration,
 			cc.skills, cc.attacks, cc.spells, cc.personality_traits, cc.ideals,
-			cc.bonds, cc.flaws, cc.features, cc.player_name
+			cc.bonds, cc.flaws, cc.features, cc.player_name,
+			cc.experience_points, cc.level_up_available, cc.frozen_at, cc.class_levels
 		FROM campaign_characters cc
 		JOIN campaigns c ON cc.campaign_id = c.id
 		WHERE cc.id = $1 AND cc.campaign_id = $2 
@@ -489,15 +556,26 @@ func (p *PostgresDB) GetCampaignCharacter(ctx context.Context, charID, campaignI
 	return &character, nil
 }
 
-// UpdateCampaignCharacter - para atualizações simples (quick stats)
-func (p *PostgresDB) UpdateCampaignCharacter(ctx context.Context, character *models.CampaignCharacter) error {
+// UpdateCampaignCharacter - para atualizações simples (quick stats), com a versão registrada
+// na mesma transação
+func (p *PostgresDB) UpdateCampaignCharacter(ctx context.Context, character *models.CampaignCharacter, authorID int) error {
+	tx, err := p.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := recordBaselineVersionTx(ctx, tx, models.CharacterTypeCampaign, character.ID); err != nil {
+		return err
+	}
+
 	query := `
 		UPDATE campaign_characters SET
-		current_hp = $1, status = $2, campaign_notes = $3
+		current_hp = $1, status = $2, campaign_notes = $3, updated_at = CURRENT_TIMESTAMP
 		WHERE id = $4 AND campaign_id = $5
 	`
 
-	result, err := p.DB.ExecContext(ctx, query,
+	result, err := tx.ExecContext(ctx, query,
 		character.CurrentHP, character.Status, character.CampaignNotes,
 		character.ID, character.CampaignID,
 	)
@@ -515,11 +593,25 @@ func (p *PostgresDB) UpdateCampaignCharacter(ctx context.Context, character *mod
 		return fmt.Errorf("character not found in campaign")
 	}
 
+	if _, err := recordCampaignCharacterVersionTx(ctx, tx, character.ID, &authorID, models.VersionActionUpdate); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit campaign character update: %w", err)
+	}
 	return nil
 }
 
-// UpdateCampaignCharacterFull - para atualizações completas do snapshot
-func (p *PostgresDB) UpdateCampaignCharacterFull(ctx context.Context, character *models.CampaignCharacter) error {
+// UpdateCampaignCharacterFull - para atualizações completas do snapshot, com a versão
+// registrada na mesma transação
+func (p *PostgresDB) UpdateCampaignCharacterFull(ctx context.Context, character *models.CampaignCharacter, authorID int) error {
+	_, err := p.UpdateCampaignCharacterFullWithStatus(ctx, character, authorID, nil)
+	return err
+}
+
+// updateCampaignCharacterFullTx grava o snapshot completo pelo executor informado (banco ou transação)
+func updateCampaignCharacterFullTx(ctx context.Context, q sqlx.ExecerContext, character *models.CampaignCharacter) error {
 	query := `
 		UPDATE campaign_characters SET
 		name = $1, description = $2, level = $3, race = $4, class = $5, background = $6,
@@ -527,11 +619,22 @@ func (p *PostgresDB) UpdateCampaignCharacterFull(ctx context.Context, character
 		current_hp = $12, ca = $13, proficiency_bonus = $14, inspiration = $15, 
 		skills = $16, attacks = $17, spells = $18, personality_traits = $19, ideals = $20,
 		bonds = $21, flaws = $22, features = $23, player_name = $24, status = $25,
-		campaign_notes = $26, last_sync = CURRENT_TIMESTAMP
+		campaign_notes = $26, class_levels = $30, last_sync = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP,
+		level_up_available = CASE
+			WHEN $3 <= level THEN level_up_available
+			WHEN (SELECT leveling_mode FROM campaigns WHERE id = $28) = 'milestone' THEN FALSE
+			ELSE experience_points >= $29
+		END
 		WHERE id = $27 AND campaign_id = $28
 	`
 
-	result, err := p.DB.ExecContext(ctx, query,
+	// Ao subir de nível, o marco é consumido; no modo XP ainda pode sobrar XP para o próximo nível
+	nextLevelXP, ok := models.XPForLevel(character.Level + 1)
+	if !ok {
+		nextLevelXP = math.MaxInt32
+	}
+
+	result, err := q.ExecContext(ctx, query,
 		character.Name, character.Description, character.Level, character.Race,
 		character.Class, character.Background, character.Alignment, character.Attributes,
 		character.Abilities, character.Equipment, character.HP, character.CurrentHP,
@@ -539,6 +642,7 @@ func (p *PostgresDB) UpdateCampaignCharacterFull(ctx context.Context, character
 		character.Attacks, character.Spells, character.PersonalityTraits, character.Ideals,
 		character.Bonds, character.Flaws, character.Features, character.PlayerName,
 		character.Status, character.CampaignNotes, character.ID, character.CampaignID,
+		nextLevelXP, character.ClassLevels,
 	)
 
 	if err != nil {
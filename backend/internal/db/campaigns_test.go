@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -29,16 +30,20 @@ func TestJoinCampaignByCode_Succeeds(t *testing.T) {
 	}).AddRow(1, "Joinable", "desc", 99, 5, 1, "planning", false, "ABCD1234", now, now)
 	mock.ExpectQuery(`FROM campaigns`).WithArgs("ABCD1234").WillReturnRows(campaignRow)
 
+	mock.ExpectBegin()
 	mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM campaign_players`).WithArgs(1, 7).
 		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
 
+	mock.ExpectQuery(`SELECT max_players FROM campaigns WHERE id = \$1 FOR UPDATE`).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"max_players"}).AddRow(5))
 	mock.ExpectQuery(`SELECT COUNT\(cp.user_id\) as player_count`).WithArgs(1).
 		WillReturnRows(sqlmock.NewRows([]string{"player_count"}).AddRow(1))
 
 	mock.ExpectExec(`INSERT INTO campaign_players`).WithArgs(1, 7, sqlmock.AnyArg()).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
 
-	if err := pdb.JoinCampaignByCode(context.Background(), "ABCD1234", 7); err != nil {
+	if _, err := pdb.JoinCampaignByCode(context.Background(), "ABCD1234", 7); err != nil {
 		t.Fatalf("expected join to succeed, got error: %v", err)
 	}
 
@@ -47,6 +52,34 @@ func TestJoinCampaignByCode_Succeeds(t *testing.T) {
 	}
 }
 
+func TestJoinCampaignByCode_FullAfterLock(t *testing.T) {
+	pdb, mock, cleanup := newMockCampaignDB(t)
+	defer cleanup()
+
+	now := time.Now()
+	campaignRow := sqlmock.NewRows([]string{
+		"id", "name", "description", "dm_id", "max_players", "current_session", "status", "allow_homebrew", "invite_code", "created_at", "updated_at",
+	}).AddRow(1, "Joinable", "desc", 99, 5, 1, "planning", false, "ABCD1234", now, now)
+	mock.ExpectQuery(`FROM campaigns`).WithArgs("ABCD1234").WillReturnRows(campaignRow)
+
+	// Outro join entrou enquanto esperávamos a trava: a contagem já vê a vaga ocupada
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM campaign_players`).WithArgs(1, 7).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectQuery(`SELECT max_players FROM campaigns WHERE id = \$1 FOR UPDATE`).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"max_players"}).AddRow(5))
+	mock.ExpectQuery(`SELECT COUNT\(cp.user_id\) as player_count`).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"player_count"}).AddRow(5))
+	mock.ExpectRollback()
+
+	if _, err := pdb.JoinCampaignByCode(context.Background(), "ABCD1234", 7); !errors.Is(err, ErrCampaignFull) {
+		t.Fatalf("expected ErrCampaignFull, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
 func TestJoinCampaignByCode_DMCannotJoin(t *testing.T) {
 	pdb, mock, cleanup := newMockCampaignDB(t)
 	defer cleanup()
@@ -57,13 +90,17 @@ func TestJoinCampaignByCode_DMCannotJoin(t *testing.T) {
 	}).AddRow(2, "Owned", "desc", 7, 5, 1, "planning", false, "ZZZZ1111", now, now)
 	mock.ExpectQuery(`FROM campaigns`).WithArgs("ZZZZ1111").WillReturnRows(campaignRow)
 
+	mock.ExpectBegin()
 	mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM campaign_players`).WithArgs(2, 7).
 		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
 
+	mock.ExpectQuery(`SELECT max_players FROM campaigns WHERE id = \$1 FOR UPDATE`).WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"max_players"}).AddRow(5))
 	mock.ExpectQuery(`SELECT COUNT\(cp.user_id\) as player_count`).WithArgs(2).
 		WillReturnRows(sqlmock.NewRows([]string{"player_count"}).AddRow(0))
+	mock.ExpectRollback()
 
-	err := pdb.JoinCampaignByCode(context.Background(), "ZZZZ1111", 7)
+	_, err := pdb.JoinCampaignByCode(context.Background(), "ZZZZ1111", 7)
 	if err == nil || err.Error() != "DM cannot join their own campaign as a player" {
 		t.Fatalf("expected DM restriction error, got: %v", err)
 	}
@@ -135,14 +172,14 @@ func TestGetCampaignCharacters(t *testing.T) {
 		"id", "campaign_id", "player_id", "source_pc_id", "status", "joined_at", "last_sync", "campaign_notes",
 		"name", "description", "level", "race", "class", "background", "alignment", "attributes", "abilities",
 		"equipment", "hp", "current_hp", "ca", "proficiency_bonus", "inspiration", "skills", "attacks", "spells",
-		"personality_traits", "ideals", "bonds", "flaws", "features", "player_name", "player_username",
+		"personality_traits", "ideals", "bonds", "flaws", "features", "player_name", "experience_points", "level_up_available", "class_levels", "player_username",
 	}
 	rows := sqlmock.NewRows(cols).AddRow(
 		1, 10, 7, 4, "active", now, now, "note",
 		"Hero", "desc", 3, "elf", "wizard", "sage", "neutral",
 		[]byte(`{"int":16}`), []byte(`{"spell":"fire"}`), []byte(`{"staff":1}`),
 		20, 18, 12, 2, true, []byte(`[]`), []byte(`[]`), []byte(`[]`),
-		"brave", "ideal", "bond", "flaw", "{feature}", "Player One", "player_username",
+		"brave", "ideal", "bond", "flaw", "{feature}", "Player One", 450, true, []byte(`[{"class":"wizard","level":3}]`), "player_username",
 	)
 
 	mock.ExpectQuery(`FROM campaign_characters`).WithArgs(10).WillReturnRows(rows)
@@ -157,6 +194,9 @@ func TestGetCampaignCharacters(t *testing.T) {
 	if characters[0].Player == nil || characters[0].Player.Username != "player_username" {
 		t.Fatalf("expected player username to be set: %+v", characters[0])
 	}
+	if characters[0].ExperiencePoints != 450 || !characters[0].LevelUpAvailable {
+		t.Fatalf("expected progression to be scanned: %+v", characters[0])
+	}
 
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Fatalf("sql expectations not met: %v", err)
@@ -233,7 +273,7 @@ func TestGetCampaignByID(t *testing.T) {
 		"id", "campaign_id", "player_id", "source_pc_id", "status", "joined_at", "last_sync", "campaign_notes",
 		"name", "description", "level", "race", "class", "background", "alignment", "attributes", "abilities",
 		"equipment", "hp", "current_hp", "ca", "proficiency_bonus", "inspiration", "skills", "attacks", "spells",
-		"personality_traits", "ideals", "bonds", "flaws", "features", "player_name", "player_username",
+		"personality_traits", "ideals", "bonds", "flaws", "features", "player_name", "experience_points", "level_up_available", "player_username",
 	}
 	charRows := sqlmock.NewRows(charCols)
 
@@ -259,7 +299,7 @@ func TestCreateCampaign(t *testing.T) {
 	defer cleanup()
 
 	mock.ExpectQuery(`INSERT INTO campaigns`).
-		WithArgs("NewCamp", "desc", 7, 6, "planning", false, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WithArgs("NewCamp", "desc", 7, 6, "planning", false, "xp", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
 
 	campaign := &models.Campaign{
@@ -287,7 +327,7 @@ func TestUpdateCampaign(t *testing.T) {
 	defer cleanup()
 
 	mock.ExpectExec(`UPDATE campaigns SET`).
-		WithArgs("Updated", "new desc", 8, 3, "active", false, sqlmock.AnyArg(), 15, 7).
+		WithArgs("Updated", "new desc", 8, 3, "active", false, "", sqlmock.AnyArg(), 15, 7).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 
 	campaign := &models.Campaign{
@@ -332,7 +372,7 @@ func TestRemovePlayerFromCampaign(t *testing.T) {
 	pdb, mock, cleanup := newMockCampaignDB(t)
 	defer cleanup()
 
-	mock.ExpectExec(`DELETE FROM campaign_players WHERE campaign_id = \$1 AND user_id = \$2`).
+	mock.ExpectExec(`UPDATE campaign_players SET status = 'removed'`).
 		WithArgs(5, 8).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 
@@ -346,6 +386,26 @@ func TestRemovePlayerFromCampaign(t *testing.T) {
 	}
 }
 
+func TestRemovePlayerFromCampaign_Banned(t *testing.T) {
+	pdb, mock, cleanup := newMockCampaignDB(t)
+	defer cleanup()
+
+	// O banido não pode sair: isso apagaria o banimento
+	mock.ExpectExec(`UPDATE campaign_players SET status = 'removed'`).
+		WithArgs(5, 8).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM campaign_players WHERE campaign_id = \$1 AND user_id = \$2 AND status = 'banned'\)`).
+		WithArgs(5, 8).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	if err := pdb.RemovePlayerFromCampaign(context.Background(), 5, 8); !errors.Is(err, ErrPlayerBanned) {
+		t.Fatalf("expected ErrPlayerBanned, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
 func TestIsPlayerInCampaign(t *testing.T) {
 	pdb, mock, cleanup := newMockCampaignDB(t)
 	defer cleanup()
@@ -370,8 +430,8 @@ func TestGetCampaignPlayers(t *testing.T) {
 	pdb, mock, cleanup := newMockCampaignDB(t)
 	defer cleanup()
 
-	rows := sqlmock.NewRows([]string{"id", "campaign_id", "user_id", "joined_at", "status", "username", "email"}).
-		AddRow(1, 10, 7, time.Now(), "active", "player1", "player1@test.com")
+	rows := sqlmock.NewRows([]string{"id", "campaign_id", "user_id", "joined_at", "status", "username", "email", "role"}).
+		AddRow(1, 10, 7, time.Now(), "active", "player1", "player1@test.com", "co_dm")
 
 	mock.ExpectQuery(`FROM campaign_players`).WithArgs(10).WillReturnRows(rows)
 
@@ -379,7 +439,7 @@ func TestGetCampaignPlayers(t *testing.T) {
 	if err != nil {
 		t.Fatalf("GetCampaignPlayers error: %v", err)
 	}
-	if len(players) != 1 || players[0].User == nil || players[0].User.Username != "player1" {
+	if len(players) != 1 || players[0].User == nil || players[0].User.Username != "player1" || players[0].Role != "co_dm" {
 		t.Fatalf("unexpected players: %+v", players)
 	}
 
@@ -398,15 +458,18 @@ func TestAddPCToCampaign(t *testing.T) {
 	//        name, description, level, race, class, background, alignment,
 	//        attributes, abilities, equipment, hp, current_hp, ca, proficiency_bonus,
 	//        inspiration, skills, attacks, spells, personality_traits, ideals,
-	//        bonds, flaws, features, player_name
+	//        bonds, flaws, features, player_name, class_levels
+	mock.ExpectBegin()
 	mock.ExpectQuery(`INSERT INTO campaign_characters`).
 		WithArgs(10, 7, 5, "active", now, "",
 			"TestPC", "desc", 3, "elf", "wizard", "sage", "neutral",
 			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
 			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
 			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
-			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "Player").
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "Player", "[]").
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(100))
+	expectCampaignCharacterVersion(mock, 100, 10, models.VersionActionCreate)
+	mock.ExpectCommit()
 
 	char := &models.CampaignCharacter{
 		CampaignID:   10,
@@ -426,7 +489,7 @@ func TestAddPCToCampaign(t *testing.T) {
 		PlayerName:   "Player",
 	}
 
-	err := pdb.AddPCToCampaign(context.Background(), char)
+	err := pdb.AddPCToCampaign(context.Background(), char, 7)
 	if err != nil {
 		t.Fatalf("AddPCToCampaign error: %v", err)
 	}
@@ -441,9 +504,13 @@ func TestUpdateCampaignCharacter(t *testing.T) {
 	defer cleanup()
 
 	currentHP := 25
+	mock.ExpectBegin()
+	expectVersionHistory(mock, models.CharacterTypeCampaign, 100)
 	mock.ExpectExec(`UPDATE campaign_characters SET`).
 		WithArgs(&currentHP, "active", "notes", 100, 10).
 		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectCampaignCharacterVersion(mock, 100, 10, models.VersionActionUpdate)
+	mock.ExpectCommit()
 
 	char := &models.CampaignCharacter{
 		ID:            100,
@@ -453,7 +520,7 @@ func TestUpdateCampaignCharacter(t *testing.T) {
 		CampaignNotes: "notes",
 	}
 
-	err := pdb.UpdateCampaignCharacter(context.Background(), char)
+	err := pdb.UpdateCampaignCharacter(context.Background(), char, 7)
 	if err != nil {
 		t.Fatalf("UpdateCampaignCharacter error: %v", err)
 	}
@@ -553,7 +620,9 @@ func TestUpdateCampaignCharacterFull(t *testing.T) {
 	// Order from query: name, description, level, race, class, background, alignment,
 	// attributes, abilities, equipment, hp, current_hp, ca, proficiency_bonus, inspiration,
 	// skills, attacks, spells, personality_traits, ideals, bonds, flaws, features,
-	// player_name, status, campaign_notes, id, campaign_id
+	// player_name, status, campaign_notes, id, campaign_id, XP do próximo nível, class_levels
+	mock.ExpectBegin()
+	expectVersionHistory(mock, models.CharacterTypeCampaign, 100)
 	mock.ExpectExec(`UPDATE campaign_characters SET`).
 		WithArgs(
 			"UpdatedChar", "new desc", 6, "elf", "wizard", "sage", "good",
@@ -563,9 +632,11 @@ func TestUpdateCampaignCharacterFull(t *testing.T) {
 			"personality", "ideals", "bonds", "flaws",
 			sqlmock.AnyArg(), "Player1",
 			"active", "notes",
-			100, 10,
+			100, 10, 23000, "[]",
 		).
 		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectCampaignCharacterVersion(mock, 100, 10, models.VersionActionUpdate)
+	mock.ExpectCommit()
 
 	char := &models.CampaignCharacter{
 		ID:                100,
@@ -591,7 +662,7 @@ func TestUpdateCampaignCharacterFull(t *testing.T) {
 		PlayerName:        "Player1",
 	}
 
-	err := pdb.UpdateCampaignCharacterFull(context.Background(), char)
+	err := pdb.UpdateCampaignCharacterFull(context.Background(), char, 7)
 	if err != nil {
 		t.Fatalf("UpdateCampaignCharacterFull error: %v", err)
 	}
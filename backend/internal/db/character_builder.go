@@ -0,0 +1,149 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"rpg-saas-backend/internal/models"
+)
+
+// ErrAbilityRollUsed indica que a rolagem de atributos já criou um PC
+var ErrAbilityRollUsed = errors.New("ability score roll was already used")
+
+// CreateAbilityScoreRoll grava uma rolagem de atributos do construtor
+func (p *PostgresDB) CreateAbilityScoreRoll(ctx context.Context, roll *models.AbilityScoreRoll) error {
+	err := p.DB.QueryRowxContext(ctx, `
+		INSERT INTO ability_score_rolls (user_id, dice, scores)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`, roll.UserID, roll.Dice, roll.Scores).Scan(&roll.ID, &roll.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record ability score roll: %w", err)
+	}
+	return nil
+}
+
+// GetAbilityScoreRoll retorna uma rolagem de atributos do usuário
+func (p *PostgresDB) GetAbilityScoreRoll(ctx context.Context, id, userID int) (*models.AbilityScoreRoll, error) {
+	var roll models.AbilityScoreRoll
+	err := p.DB.GetContext(ctx, &roll, `
+		SELECT id, user_id, dice, scores, pc_id, used_at, created_at
+		FROM ability_score_rolls
+		WHERE id = $1 AND user_id = $2
+	`, id, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch ability score roll %d: %w", id, err)
+	}
+	return &roll, nil
+}
+
+// LoadBuilderRules resolve raça, classe e antecedente do rascunho (SRD e, na falta, homebrew) e
+// a rolagem de atributos informada. Raça e classe não encontradas vêm com fonte "default";
+// antecedente e rolagem não encontrados ficam nil.
+func (p *PostgresDB) LoadBuilderRules(ctx context.Context, draft *models.CharacterDraft, userID int) (*models.BuilderRules, error) {
+	race, err := p.loadRaceRules(ctx, draft.Race, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	class, err := p.loadClassRules(ctx, draft.Class, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := &models.BuilderRules{Race: race, Class: class}
+
+	if draft.Background != "" {
+		if rules.Background, err = p.loadBackgroundRules(ctx, draft.Background, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	if draft.RollID != nil {
+		roll, err := p.GetAbilityScoreRoll(ctx, *draft.RollID, userID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		rules.Roll = roll
+	}
+
+	return rules, nil
+}
+
+func (p *PostgresDB) loadBackgroundRules(ctx context.Context, name string, userID int) (*models.BackgroundRules, error) {
+	var background models.DnDBackground
+	err := p.DB.GetContext(ctx, &background, `
+		SELECT api_index, name, COALESCE(starting_proficiencies, '[]'::jsonb) AS starting_proficiencies
+		FROM dnd_backgrounds
+		WHERE api_index = LOWER($1) OR LOWER(name) = LOWER($1)
+		LIMIT 1
+	`, name)
+	if err == nil {
+		rules := models.BackgroundRulesFromSRD(&background)
+		return &rules, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to fetch D&D background %q: %w", name, err)
+	}
+
+	var homebrew models.HomebrewBackground
+	err = p.DB.GetContext(ctx, &homebrew, `
+		SELECT id, name, COALESCE(skill_proficiencies, '{}') AS skill_proficiencies,
+		       COALESCE(tool_proficiencies, '{}') AS tool_proficiencies
+		FROM homebrew_backgrounds
+		WHERE LOWER(name) = LOWER($1) AND (is_public = true OR user_id = $2)
+		ORDER BY (user_id = $2) DESC, id
+		LIMIT 1
+	`, name, userID)
+	if err == nil {
+		rules := models.BackgroundRulesFromHomebrew(&homebrew)
+		return &rules, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to fetch homebrew background %q: %w", name, err)
+	}
+
+	return nil, nil
+}
+
+// CreateBuiltPC grava o PC montado pelo construtor e sua primeira versão e, se ele usou uma
+// rolagem de atributos, marca a rolagem como usada na mesma transação
+func (p *PostgresDB) CreateBuiltPC(ctx context.Context, pc *models.PC, rollID *int, authorID int) error {
+	tx, err := p.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := insertPC(ctx, tx, pc); err != nil {
+		return fmt.Errorf("failed to create PC: %w", err)
+	}
+
+	if rollID != nil {
+		result, err := tx.ExecContext(ctx, `
+			UPDATE ability_score_rolls SET used_at = CURRENT_TIMESTAMP, pc_id = $1
+			WHERE id = $2 AND user_id = $3 AND used_at IS NULL
+		`, pc.ID, *rollID, pc.PlayerID)
+		if err != nil {
+			return fmt.Errorf("failed to mark ability score roll %d as used: %w", *rollID, err)
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get affected rows: %w", err)
+		}
+		if rowsAffected == 0 {
+			return ErrAbilityRollUsed
+		}
+	}
+
+	if _, err := recordPCVersionTx(ctx, tx, pc.ID, &authorID, models.VersionActionCreate); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit PC creation: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+
+	"rpg-saas-backend/internal/models"
+)
+
+func TestCreateAbilityScoreRoll(t *testing.T) {
+	pdb, mock, cleanup := newMockPCDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery(`INSERT INTO ability_score_rolls`).
+		WithArgs(7, "{6,5,4,1}", "{15}").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(3, time.Now()))
+
+	roll := &models.AbilityScoreRoll{UserID: 7, Dice: pq.Int64Array{6, 5, 4, 1}, Scores: pq.Int64Array{15}}
+	if err := pdb.CreateAbilityScoreRoll(context.Background(), roll); err != nil {
+		t.Fatalf("CreateAbilityScoreRoll returned error: %v", err)
+	}
+	if roll.ID != 3 {
+		t.Fatalf("expected id 3, got %d", roll.ID)
+	}
+}
+
+func TestLoadBuilderRules(t *testing.T) {
+	pdb, mock, cleanup := newMockPCDB(t)
+	defer cleanup()
+
+	rollID := 3
+	mock.ExpectQuery(`FROM dnd_races`).WithArgs("Dwarf").
+		WillReturnRows(sqlmock.NewRows([]string{"api_index", "name", "speed", "ability_bonuses", "proficiencies"}).
+			AddRow("dwarf", "Dwarf", 25, []byte(`[{"ability_score":{"index":"con"},"bonus":2}]`), []byte(`[]`)))
+	mock.ExpectQuery(`FROM dnd_classes`).WithArgs("Fighter").
+		WillReturnRows(sqlmock.NewRows([]string{"api_index", "name", "hit_die", "saving_throws", "proficiency_choices", "spellcasting", "spellcasting_ability"}).
+			AddRow("fighter", "Fighter", 10, pq.StringArray{"str", "con"},
+				[]byte(`[{"choose":2,"from":{"options":[{"item":{"index":"skill-athletics"}},{"item":{"index":"skill-survival"}}]}}]`),
+				[]byte(`{}`), ""))
+	mock.ExpectQuery(`FROM dnd_backgrounds`).WithArgs("Smuggler").WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(`FROM homebrew_backgrounds`).WithArgs("Smuggler", 7).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "skill_proficiencies", "tool_proficiencies"}).
+			AddRow(2, "Smuggler", pq.StringArray{"Stealth"}, pq.StringArray{}))
+	mock.ExpectQuery(`FROM ability_score_rolls`).WithArgs(3, 7).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "dice", "scores", "pc_id", "used_at", "created_at"}).
+			AddRow(3, 7, "{6,5,4,1}", "{15}", nil, nil, time.Now()))
+
+	draft := &models.CharacterDraft{Race: "Dwarf", Class: "Fighter", Background: "Smuggler", RollID: &rollID}
+	rules, err := pdb.LoadBuilderRules(context.Background(), draft, 7)
+	if err != nil {
+		t.Fatalf("LoadBuilderRules returned error: %v", err)
+	}
+	if rules.Class.SkillChoiceCount != 2 || len(rules.Class.SkillOptions) != 2 {
+		t.Fatalf("unexpected class rules: %+v", rules.Class)
+	}
+	if rules.Background == nil || rules.Background.Source != models.RulesSourceHomebrew || rules.Background.Skills[0] != "stealth" {
+		t.Fatalf("unexpected background: %+v", rules.Background)
+	}
+	if rules.Roll == nil || rules.Roll.Scores[0] != 15 {
+		t.Fatalf("unexpected roll: %+v", rules.Roll)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestCreateBuiltPC(t *testing.T) {
+	pdb, mock, cleanup := newMockPCDB(t)
+	defer cleanup()
+
+	rollID := 3
+	pc := &models.PC{Name: "Tordek", Level: 1, Race: "dwarf", Class: "fighter", PlayerID: 7}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO pcs`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(20))
+	mock.ExpectExec(`UPDATE ability_score_rolls SET used_at`).WithArgs(20, 3, 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectPCVersion(mock, 20, models.VersionActionCreate)
+	mock.ExpectCommit()
+
+	if err := pdb.CreateBuiltPC(context.Background(), pc, &rollID, 7); err != nil {
+		t.Fatalf("CreateBuiltPC returned error: %v", err)
+	}
+	if pc.ID != 20 {
+		t.Fatalf("expected PC id 20, got %d", pc.ID)
+	}
+
+	// Rolagem já usada: nada é gravado
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO pcs`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(21))
+	mock.ExpectExec(`UPDATE ability_score_rolls SET used_at`).WithArgs(21, 3, 7).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	if err := pdb.CreateBuiltPC(context.Background(), pc, &rollID, 7); !errors.Is(err, ErrAbilityRollUsed) {
+		t.Fatalf("expected ErrAbilityRollUsed, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
@@ -0,0 +1,250 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"rpg-saas-backend/internal/models"
+)
+
+var (
+	// ErrCharacterStatusChanged indica que o status mudou desde a leitura do personagem
+	ErrCharacterStatusChanged = errors.New("character status changed concurrently")
+	// ErrUniquePCInUse indica PC único já em jogo em outra campanha
+	ErrUniquePCInUse = errors.New("unique PC is already in play in another campaign")
+	// ErrSessionNotInCampaign indica sessão informada que não pertence à campanha
+	ErrSessionNotInCampaign = errors.New("session not found in campaign")
+)
+
+// characterStatusHook é um efeito colateral de uma mudança de status, executado na mesma
+// transação. Cada hook decide, pelo evento, se se aplica.
+type characterStatusHook func(ctx context.Context, tx *sqlx.Tx, character *models.CampaignCharacter, event models.CharacterStatusEvent, change *models.CharacterStatusChange) error
+
+var characterStatusHooks = []characterStatusHook{
+	claimUniquePC,
+	logCharacterDeath,
+	releaseUniquePC,
+}
+
+// ChangeCampaignCharacterStatus grava o novo status (com PV e notas) e executa os hooks da
+// transição. A transição já deve ter sido validada; se o status no banco não for mais
+// event.From, nada é alterado.
+func (p *PostgresDB) ChangeCampaignCharacterStatus(ctx context.Context, character *models.CampaignCharacter, event models.CharacterStatusEvent) (*models.CharacterStatusChange, error) {
+	tx, err := p.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	change, err := changeCampaignCharacterStatusTx(ctx, tx, character, event)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit status change: %w", err)
+	}
+
+	return change, nil
+}
+
+// UpdateCampaignCharacterFullWithStatus grava a mudança de status (se event não for nil) e o
+// snapshot completo numa única transação, cada etapa com sua versão
+func (p *PostgresDB) UpdateCampaignCharacterFullWithStatus(ctx context.Context, character *models.CampaignCharacter, authorID int, event *models.CharacterStatusEvent) (*models.CharacterStatusChange, error) {
+	tx, err := p.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// A mudança de status já grava a versão de base; sem ela, a base é gravada aqui
+	var change *models.CharacterStatusChange
+	if event != nil {
+		if change, err = changeCampaignCharacterStatusTx(ctx, tx, character, *event); err != nil {
+			return nil, err
+		}
+	} else if err := recordBaselineVersionTx(ctx, tx, models.CharacterTypeCampaign, character.ID); err != nil {
+		return nil, err
+	}
+
+	if err := updateCampaignCharacterFullTx(ctx, tx, character); err != nil {
+		return nil, err
+	}
+
+	if _, err := recordCampaignCharacterVersionTx(ctx, tx, character.ID, &authorID, models.VersionActionUpdate); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit campaign character update: %w", err)
+	}
+
+	return change, nil
+}
+
+func changeCampaignCharacterStatusTx(ctx context.Context, tx *sqlx.Tx, character *models.CampaignCharacter, event models.CharacterStatusEvent) (*models.CharacterStatusChange, error) {
+	if err := recordBaselineVersionTx(ctx, tx, models.CharacterTypeCampaign, character.ID); err != nil {
+		return nil, err
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE campaign_characters SET
+		current_hp = $1, status = $2, campaign_notes = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $4 AND campaign_id = $5 AND status = $6
+	`, character.CurrentHP, event.To, character.CampaignNotes, character.ID, character.CampaignID, event.From)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update character status: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		return nil, ErrCharacterStatusChanged
+	}
+	character.Status = event.To
+
+	change := &models.CharacterStatusChange{From: event.From, To: event.To}
+	for _, hook := range characterStatusHooks {
+		if err := hook(ctx, tx, character, event, change); err != nil {
+			return nil, err
+		}
+	}
+
+	action := event.VersionAction
+	if action == "" {
+		action = models.VersionActionStatus
+	}
+	if _, err := recordCampaignCharacterVersionTx(ctx, tx, character.ID, &event.ChangedBy, action); err != nil {
+		return nil, err
+	}
+
+	return change, nil
+}
+
+// GetCharacterDeaths lista as mortes registradas na campanha, da mais recente à mais antiga
+func (p *PostgresDB) GetCharacterDeaths(ctx context.Context, campaignID int) ([]models.CharacterDeath, error) {
+	deaths := []models.CharacterDeath{}
+	query := `
+		SELECT id, character_id, campaign_id, character_name, level, session_id, cause, recorded_by, created_at
+		FROM character_deaths
+		WHERE campaign_id = $1
+		ORDER BY created_at DESC, id DESC
+	`
+
+	if err := p.DB.SelectContext(ctx, &deaths, query, campaignID); err != nil {
+		return nil, fmt.Errorf("failed to fetch character deaths for campaign %d: %w", campaignID, err)
+	}
+
+	return deaths, nil
+}
+
+// claimUniquePC impede que um personagem volte ao jogo se o PC único de origem já estiver
+// em jogo em outra campanha
+func claimUniquePC(ctx context.Context, tx *sqlx.Tx, character *models.CampaignCharacter, event models.CharacterStatusEvent, _ *models.CharacterStatusChange) error {
+	if models.CharacterStatusInPlay(event.From) || !models.CharacterStatusInPlay(event.To) {
+		return nil
+	}
+
+	var campaignID int
+	err := tx.GetContext(ctx, &campaignID, `
+		SELECT cc.campaign_id
+		FROM campaign_characters cc
+		JOIN pcs p ON p.id = cc.source_pc_id
+		WHERE cc.source_pc_id = $1 AND cc.id <> $2 AND p.is_unique = true
+		  AND cc.status IN ('active', 'inactive', 'unconscious', 'dead', 'resurrected')
+		LIMIT 1
+	`, character.SourcePCID, character.ID)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check unique PC %d: %w", character.SourcePCID, err)
+	}
+
+	return fmt.Errorf("%w (campaign %d)", ErrUniquePCInUse, campaignID)
+}
+
+// logCharacterDeath registra a morte com a causa e a sessão informada ou, sem ela, a sessão
+// em andamento da campanha
+func logCharacterDeath(ctx context.Context, tx *sqlx.Tx, character *models.CampaignCharacter, event models.CharacterStatusEvent, change *models.CharacterStatusChange) error {
+	if event.To != models.CharacterStatusDead {
+		return nil
+	}
+
+	if event.SessionID != nil {
+		var exists bool
+		err := tx.GetContext(ctx, &exists, `
+			SELECT EXISTS(SELECT 1 FROM campaign_sessions WHERE id = $1 AND campaign_id = $2)
+		`, *event.SessionID, character.CampaignID)
+		if err != nil {
+			return fmt.Errorf("failed to check session %d: %w", *event.SessionID, err)
+		}
+		if !exists {
+			return ErrSessionNotInCampaign
+		}
+	}
+
+	death := &models.CharacterDeath{
+		CharacterID:   character.ID,
+		CampaignID:    character.CampaignID,
+		CharacterName: character.Name,
+		Level:         character.Level,
+		Cause:         event.Cause,
+		RecordedBy:    &event.ChangedBy,
+	}
+	err := tx.QueryRowxContext(ctx, `
+		INSERT INTO character_deaths (character_id, campaign_id, character_name, level, session_id, cause, recorded_by)
+		VALUES ($1, $2, $3, $4, COALESCE($5, (
+			SELECT id FROM campaign_sessions
+			WHERE campaign_id = $2 AND status = 'in_progress'
+			ORDER BY started_at DESC NULLS LAST LIMIT 1
+		)), $6, $7)
+		RETURNING id, session_id, created_at
+	`, death.CharacterID, death.CampaignID, death.CharacterName, death.Level, event.SessionID,
+		death.Cause, death.RecordedBy,
+	).Scan(&death.ID, &death.SessionID, &death.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to log death of character %d: %w", character.ID, err)
+	}
+
+	change.Death = death
+	return nil
+}
+
+// releaseUniquePC informa o PC único liberado quando o personagem se aposenta e nenhum outro
+// personagem o mantém em jogo. A disponibilidade é derivada do status, então basta o UPDATE
+// do status para liberar o PC; o hook só reporta a liberação.
+func releaseUniquePC(ctx context.Context, tx *sqlx.Tx, character *models.CampaignCharacter, event models.CharacterStatusEvent, change *models.CharacterStatusChange) error {
+	if event.To != models.CharacterStatusRetired {
+		return nil
+	}
+
+	var released bool
+	err := tx.GetContext(ctx, &released, `
+		SELECT p.is_unique AND NOT EXISTS(
+			SELECT 1 FROM campaign_characters cc
+			WHERE cc.source_pc_id = p.id AND cc.id <> $2
+			  AND cc.status IN ('active', 'inactive', 'unconscious', 'dead', 'resurrected')
+		)
+		FROM pcs p
+		WHERE p.id = $1
+	`, character.SourcePCID, character.ID)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check unique PC %d: %w", character.SourcePCID, err)
+	}
+
+	if released {
+		pcID := character.SourcePCID
+		change.ReleasedPCID = &pcID
+	}
+	return nil
+}
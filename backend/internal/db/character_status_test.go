@@ -0,0 +1,122 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+
+	"rpg-saas-backend/internal/models"
+)
+
+func TestChangeCampaignCharacterStatus(t *testing.T) {
+	t.Run("death is logged in the running session", func(t *testing.T) {
+		pdb, mock, cleanup := newMockCampaignDB(t)
+		defer cleanup()
+
+		character := &models.CampaignCharacter{ID: 5, CampaignID: 1, SourcePCID: 3, Name: "Aria", Level: 4, Status: "active"}
+
+		mock.ExpectBegin()
+		expectVersionHistory(mock, models.CharacterTypeCampaign, 5)
+		mock.ExpectExec(`UPDATE campaign_characters SET`).
+			WithArgs(nil, models.CharacterStatusDead, "", 5, 1, models.CharacterStatusActive).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(`INSERT INTO character_deaths`).
+			WithArgs(5, 1, "Aria", 4, nil, "Beholder disintegration ray", 7).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "session_id", "created_at"}).AddRow(30, 12, time.Now()))
+		expectCampaignCharacterVersion(mock, 5, 1, models.VersionActionStatus)
+		mock.ExpectCommit()
+
+		change, err := pdb.ChangeCampaignCharacterStatus(context.Background(), character, models.CharacterStatusEvent{
+			From: models.CharacterStatusActive, To: models.CharacterStatusDead, ChangedBy: 7, Cause: "Beholder disintegration ray",
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if change.Death == nil || change.Death.SessionID == nil || *change.Death.SessionID != 12 {
+			t.Fatalf("expected death logged in session 12, got %+v", change.Death)
+		}
+		if character.Status != models.CharacterStatusDead {
+			t.Fatalf("expected character status updated, got %s", character.Status)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+
+	t.Run("retirement releases unique PC", func(t *testing.T) {
+		pdb, mock, cleanup := newMockCampaignDB(t)
+		defer cleanup()
+
+		character := &models.CampaignCharacter{ID: 5, CampaignID: 1, SourcePCID: 3, Status: "inactive"}
+
+		mock.ExpectBegin()
+		expectVersionHistory(mock, models.CharacterTypeCampaign, 5)
+		mock.ExpectExec(`UPDATE campaign_characters SET`).
+			WithArgs(nil, models.CharacterStatusRetired, "", 5, 1, models.CharacterStatusInactive).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(`SELECT p.is_unique AND NOT EXISTS`).WithArgs(3, 5).
+			WillReturnRows(sqlmock.NewRows([]string{"released"}).AddRow(true))
+		expectCampaignCharacterVersion(mock, 5, 1, models.VersionActionStatus)
+		mock.ExpectCommit()
+
+		change, err := pdb.ChangeCampaignCharacterStatus(context.Background(), character, models.CharacterStatusEvent{
+			From: models.CharacterStatusInactive, To: models.CharacterStatusRetired, ChangedBy: 7,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if change.ReleasedPCID == nil || *change.ReleasedPCID != 3 {
+			t.Fatalf("expected PC 3 released, got %+v", change)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+
+	t.Run("unique PC in play elsewhere blocks return", func(t *testing.T) {
+		pdb, mock, cleanup := newMockCampaignDB(t)
+		defer cleanup()
+
+		character := &models.CampaignCharacter{ID: 5, CampaignID: 1, SourcePCID: 3, Status: "retired"}
+
+		mock.ExpectBegin()
+		expectVersionHistory(mock, models.CharacterTypeCampaign, 5)
+		mock.ExpectExec(`UPDATE campaign_characters SET`).
+			WithArgs(nil, models.CharacterStatusActive, "", 5, 1, models.CharacterStatusRetired).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(`SELECT cc.campaign_id`).WithArgs(3, 5).
+			WillReturnRows(sqlmock.NewRows([]string{"campaign_id"}).AddRow(2))
+		mock.ExpectRollback()
+
+		_, err := pdb.ChangeCampaignCharacterStatus(context.Background(), character, models.CharacterStatusEvent{
+			From: models.CharacterStatusRetired, To: models.CharacterStatusActive, ChangedBy: 7,
+		})
+		if !errors.Is(err, ErrUniquePCInUse) {
+			t.Fatalf("expected ErrUniquePCInUse, got %v", err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+
+	t.Run("status changed concurrently", func(t *testing.T) {
+		pdb, mock, cleanup := newMockCampaignDB(t)
+		defer cleanup()
+
+		mock.ExpectBegin()
+		expectVersionHistory(mock, models.CharacterTypeCampaign, 5)
+		mock.ExpectExec(`UPDATE campaign_characters SET`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		_, err := pdb.ChangeCampaignCharacterStatus(context.Background(), &models.CampaignCharacter{ID: 5, CampaignID: 1}, models.CharacterStatusEvent{
+			From: models.CharacterStatusActive, To: models.CharacterStatusInactive, ChangedBy: 7,
+		})
+		if !errors.Is(err, ErrCharacterStatusChanged) {
+			t.Fatalf("expected ErrCharacterStatusChanged, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,308 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"rpg-saas-backend/internal/models"
+)
+
+var (
+	// ErrVersionNotFound indica versão inexistente para o personagem
+	ErrVersionNotFound = errors.New("character version not found")
+	// ErrCharacterFrozen indica personagem congelado pela conclusão da campanha
+	ErrCharacterFrozen = errors.New("character is frozen: its campaign is completed")
+)
+
+const campaignCharacterStateColumns = `
+	id, campaign_id, player_id, source_pc_id, status, joined_at, last_sync, campaign_notes,
+	name, description, level, race, class, class_levels, background, alignment, attributes, abilities,
+	equipment, hp, current_hp, ca, proficiency_bonus, inspiration, skills, attacks, spells,
+	personality_traits, ideals, bonds, flaws, features, player_name,
+	experience_points, level_up_available
+`
+
+const pcStateColumns = `
+	id, name, description, level, race, class, class_levels, background, alignment,
+	attributes, abilities, equipment, hp, current_hp, ca, proficiency_bonus,
+	inspiration, skills, attacks, spells, personality_traits, ideals, bonds,
+	flaws, features, player_name, player_id, is_homebrew, is_unique, created_at
+`
+
+const versionListColumns = `
+	v.id, v.character_type, v.character_id, v.campaign_id, v.version, v.action, v.author_id,
+	COALESCE(u.username, '') AS author_name, v.character_name, v.changes, v.created_at
+`
+
+// RecordCampaignCharacterVersion registra o estado atual do personagem de campanha
+func (p *PostgresDB) RecordCampaignCharacterVersion(ctx context.Context, characterID, authorID int, action string) error {
+	_, err := recordCampaignCharacterVersionTx(ctx, p.DB, characterID, &authorID, action)
+	return err
+}
+
+// RecordPCVersion registra o estado atual do PC
+func (p *PostgresDB) RecordPCVersion(ctx context.Context, pcID, authorID int, action string) error {
+	_, err := recordPCVersionTx(ctx, p.DB, pcID, &authorID, action)
+	return err
+}
+
+// recordPCVersionTx lê o PC pelo mesmo executor da alteração (banco ou transação) e registra
+// o estado
+func recordPCVersionTx(ctx context.Context, q sqlx.ExtContext, pcID int, authorID *int, action string) (*models.CharacterVersion, error) {
+	var pc models.PC
+	if err := sqlx.GetContext(ctx, q, &pc, `SELECT `+pcStateColumns+` FROM pcs WHERE id = $1`, pcID); err != nil {
+		return nil, fmt.Errorf("failed to load PC %d for versioning: %w", pcID, err)
+	}
+
+	return insertCharacterVersion(ctx, q, &models.CharacterVersion{
+		CharacterType: models.CharacterTypePC,
+		CharacterID:   pc.ID,
+		Action:        action,
+		AuthorID:      authorID,
+		CharacterName: pc.Name,
+		Snapshot:      pc.VersionState(),
+	})
+}
+
+// recordCampaignCharacterVersionTx lê o personagem pelo mesmo executor da alteração (banco ou
+// transação) e registra o estado. authorID nil indica alteração do sistema.
+func recordCampaignCharacterVersionTx(ctx context.Context, q sqlx.ExtContext, characterID int, authorID *int, action string) (*models.CharacterVersion, error) {
+	var character models.CampaignCharacter
+	err := sqlx.GetContext(ctx, q, &character, `SELECT `+campaignCharacterStateColumns+` FROM campaign_characters WHERE id = $1`, characterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load campaign character %d for versioning: %w", characterID, err)
+	}
+
+	return insertCharacterVersion(ctx, q, &models.CharacterVersion{
+		CharacterType: models.CharacterTypeCampaign,
+		CharacterID:   character.ID,
+		CampaignID:    &character.CampaignID,
+		Action:        action,
+		AuthorID:      authorID,
+		CharacterName: character.Name,
+		Snapshot:      character.VersionState(),
+	})
+}
+
+// recordBaselineVersionTx grava o estado atual como primeira versão quando o personagem ainda
+// não tem histórico (criado antes do versionamento). Roda antes da alteração, pelo mesmo
+// executor, para que a versão seguinte tenha contra o que comparar.
+func recordBaselineVersionTx(ctx context.Context, q sqlx.ExtContext, characterType string, characterID int) error {
+	var exists bool
+	err := sqlx.GetContext(ctx, q, &exists, `
+		SELECT EXISTS(SELECT 1 FROM character_versions WHERE character_type = $1 AND character_id = $2)
+	`, characterType, characterID)
+	if err != nil {
+		return fmt.Errorf("failed to check character history: %w", err)
+	}
+	if exists {
+		return nil
+	}
+
+	if characterType == models.CharacterTypePC {
+		_, err = recordPCVersionTx(ctx, q, characterID, nil, models.VersionActionBaseline)
+	} else {
+		_, err = recordCampaignCharacterVersionTx(ctx, q, characterID, nil, models.VersionActionBaseline)
+	}
+	return err
+}
+
+// insertCharacterVersion grava a próxima versão com o diff para a anterior. Se nada mudou,
+// não grava e retorna nil. Sem histórico, a versão 1 não tem alterações.
+func insertCharacterVersion(ctx context.Context, q sqlx.ExtContext, version *models.CharacterVersion) (*models.CharacterVersion, error) {
+	var previous struct {
+		Version  int          `db:"version"`
+		Snapshot models.JSONB `db:"snapshot"`
+	}
+	err := sqlx.GetContext(ctx, q, &previous, `
+		SELECT version, snapshot FROM character_versions
+		WHERE character_type = $1 AND character_id = $2
+		ORDER BY version DESC LIMIT 1
+	`, version.CharacterType, version.CharacterID)
+	switch {
+	case err == sql.ErrNoRows:
+		version.Version = 1
+		version.Changes = models.FieldChanges{}
+	case err != nil:
+		return nil, fmt.Errorf("failed to fetch previous character version: %w", err)
+	default:
+		version.Changes = models.DiffVersionStates(previous.Snapshot, version.Snapshot)
+		if len(version.Changes) == 0 {
+			return nil, nil
+		}
+		version.Version = previous.Version + 1
+	}
+
+	err = sqlx.GetContext(ctx, q, version, `
+		INSERT INTO character_versions
+			(character_type, character_id, campaign_id, version, action, author_id, character_name, snapshot, changes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at
+	`, version.CharacterType, version.CharacterID, version.CampaignID, version.Version, version.Action,
+		version.AuthorID, version.CharacterName, version.Snapshot, version.Changes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record character version: %w", err)
+	}
+
+	return version, nil
+}
+
+// GetCharacterVersions lista as versões de um personagem, da mais recente à mais antiga (sem snapshot)
+func (p *PostgresDB) GetCharacterVersions(ctx context.Context, characterType string, characterID int) ([]models.CharacterVersion, error) {
+	versions := []models.CharacterVersion{}
+	query := `
+		SELECT ` + versionListColumns + `
+		FROM character_versions v
+		LEFT JOIN users u ON u.id = v.author_id
+		WHERE v.character_type = $1 AND v.character_id = $2
+		ORDER BY v.version DESC
+	`
+
+	if err := p.DB.SelectContext(ctx, &versions, query, characterType, characterID); err != nil {
+		return nil, fmt.Errorf("failed to fetch versions of %s %d: %w", characterType, characterID, err)
+	}
+
+	return versions, nil
+}
+
+// GetCampaignCharacterVersions lista as alterações de todos os personagens da campanha
+func (p *PostgresDB) GetCampaignCharacterVersions(ctx context.Context, campaignID, limit, offset int) ([]models.CharacterVersion, error) {
+	versions := []models.CharacterVersion{}
+	query := `
+		SELECT ` + versionListColumns + `
+		FROM character_versions v
+		LEFT JOIN users u ON u.id = v.author_id
+		WHERE v.campaign_id = $1
+		ORDER BY v.created_at DESC, v.id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	if err := p.DB.SelectContext(ctx, &versions, query, campaignID, limit, offset); err != nil {
+		return nil, fmt.Errorf("failed to fetch character versions for campaign %d: %w", campaignID, err)
+	}
+
+	return versions, nil
+}
+
+// GetCharacterVersion retorna uma versão com o snapshot completo (nil se não existir)
+func (p *PostgresDB) GetCharacterVersion(ctx context.Context, characterType string, characterID, version int) (*models.CharacterVersion, error) {
+	var result models.CharacterVersion
+	query := `
+		SELECT ` + versionListColumns + `, v.snapshot
+		FROM character_versions v
+		LEFT JOIN users u ON u.id = v.author_id
+		WHERE v.character_type = $1 AND v.character_id = $2 AND v.version = $3
+	`
+
+	err := p.DB.GetContext(ctx, &result, query, characterType, characterID, version)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch version %d of %s %d: %w", version, characterType, characterID, err)
+	}
+
+	return &result, nil
+}
+
+// RevertCampaignCharacter restaura o personagem de campanha para uma versão anterior e
+// registra a reversão como nova versão. O status não é revertido: ele só muda pela máquina
+// de estados (ChangeCampaignCharacterStatus). Personagens congelados não são revertidos.
+func (p *PostgresDB) RevertCampaignCharacter(ctx context.Context, campaignID, characterID, version, authorID int) (*models.CampaignCharacter, *models.CharacterVersion, error) {
+	tx, err := p.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var snapshot models.JSONB
+	err = tx.GetContext(ctx, &snapshot, `
+		SELECT snapshot FROM character_versions
+		WHERE character_type = $1 AND character_id = $2 AND version = $3 AND campaign_id = $4
+	`, models.CharacterTypeCampaign, characterID, version, campaignID)
+	if err == sql.ErrNoRows {
+		return nil, nil, ErrVersionNotFound
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch character version: %w", err)
+	}
+
+	var character models.CampaignCharacter
+	err = tx.GetContext(ctx, &character, `
+		SELECT `+campaignCharacterStateColumns+`, frozen_at FROM campaign_characters
+		WHERE id = $1 AND campaign_id = $2
+		FOR UPDATE
+	`, characterID, campaignID)
+	if err == sql.ErrNoRows {
+		return nil, nil, ErrVersionNotFound
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch campaign character: %w", err)
+	}
+	if character.FrozenAt != nil {
+		return nil, nil, ErrCharacterFrozen
+	}
+
+	// O status só muda pela máquina de status; morto continua sem PV restaurado. O ponteiro
+	// de PV é solto antes para a versão não sobrescrever o valor guardado
+	status, currentHP := character.Status, character.CurrentHP
+	character.CurrentHP = nil
+	if err := character.ApplyVersionState(snapshot); err != nil {
+		return nil, nil, fmt.Errorf("failed to apply character version: %w", err)
+	}
+	character.Status = status
+	if status == models.CharacterStatusDead {
+		character.CurrentHP = currentHP
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE campaign_characters SET
+		name = $1, description = $2, level = $3, race = $4, class = $5, background = $6,
+		alignment = $7, attributes = $8, abilities = $9, equipment = $10, hp = $11,
+		current_hp = $12, ca = $13, proficiency_bonus = $14, inspiration = $15,
+		skills = $16, attacks = $17, spells = $18, personality_traits = $19, ideals = $20,
+		bonds = $21, flaws = $22, features = $23, player_name = $24,
+		campaign_notes = $25, experience_points = $26, level_up_available = $27,
+		class_levels = $28, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $29 AND campaign_id = $30
+	`, character.Name, character.Description, character.Level, character.Race,
+		character.Class, character.Background, character.Alignment, character.Attributes,
+		character.Abilities, character.Equipment, character.HP, character.CurrentHP,
+		character.CA, character.ProficiencyBonus, character.Inspiration, character.Skills,
+		character.Attacks, character.Spells, character.PersonalityTraits, character.Ideals,
+		character.Bonds, character.Flaws, character.Features, character.PlayerName,
+		character.CampaignNotes, character.ExperiencePoints, character.LevelUpAvailable,
+		character.ClassLevels, character.ID, campaignID,
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to revert campaign character: %w", err)
+	}
+
+	recorded, err := recordCampaignCharacterVersionTx(ctx, tx, characterID, &authorID, models.VersionActionRevert)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit revert: %w", err)
+	}
+
+	return &character, recorded, nil
+}
+
+// GetCampaignCharacterOwner retorna o jogador dono do personagem; found é false se o
+// personagem não pertence à campanha
+func (p *PostgresDB) GetCampaignCharacterOwner(ctx context.Context, campaignID, characterID int) (playerID int, found bool, err error) {
+	err = p.DB.GetContext(ctx, &playerID, `SELECT player_id FROM campaign_characters WHERE id = $1 AND campaign_id = $2`, characterID, campaignID)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to fetch campaign character %d: %w", characterID, err)
+	}
+	return playerID, true, nil
+}
@@ -0,0 +1,321 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+
+	"rpg-saas-backend/internal/models"
+)
+
+var campaignCharacterStateCols = []string{
+	"id", "campaign_id", "player_id", "source_pc_id", "status", "joined_at", "last_sync", "campaign_notes",
+	"name", "description", "level", "race", "class", "background", "alignment", "attributes", "abilities",
+	"equipment", "hp", "current_hp", "ca", "proficiency_bonus", "inspiration", "skills", "attacks", "spells",
+	"personality_traits", "ideals", "bonds", "flaws", "features", "player_name",
+	"experience_points", "level_up_available",
+}
+
+func campaignCharacterStateRow(id, campaignID, level int, status string) *sqlmock.Rows {
+	return sqlmock.NewRows(campaignCharacterStateCols).AddRow(
+		id, campaignID, 7, 3, status, time.Now(), nil, "",
+		"Aria", "", level, "Elf", "Wizard", "Sage", "Neutral", []byte(`{}`), []byte(`{}`),
+		[]byte(`[]`), 12, 12, 13, 2, false, []byte(`{}`), []byte(`[]`), []byte(`{}`),
+		"", "", "", "", "{}", "Bob",
+		0, false,
+	)
+}
+
+// expectCampaignCharacterVersion espera o registro da primeira versão do personagem
+func expectCampaignCharacterVersion(mock sqlmock.Sqlmock, characterID, campaignID int, action string) {
+	mock.ExpectQuery(`SELECT\s+id, campaign_id, player_id`).WithArgs(characterID).
+		WillReturnRows(campaignCharacterStateRow(characterID, campaignID, 3, "retired"))
+	mock.ExpectQuery(`SELECT version, snapshot FROM character_versions`).
+		WithArgs(models.CharacterTypeCampaign, characterID).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(`INSERT INTO character_versions`).
+		WithArgs(models.CharacterTypeCampaign, characterID, campaignID, 1, action,
+			sqlmock.AnyArg(), "Aria", sqlmock.AnyArg(), "[]").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(characterID*10, time.Now()))
+}
+
+// expectVersionHistory simula personagem que já tem histórico: nenhuma versão de base é gravada
+func expectVersionHistory(mock sqlmock.Sqlmock, characterType string, characterID int) {
+	mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM character_versions`).WithArgs(characterType, characterID).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+}
+
+// expectPCVersion espera o registro da primeira versão do PC
+func expectPCVersion(mock sqlmock.Sqlmock, pcID int, action string) {
+	mock.ExpectQuery(`FROM pcs WHERE id = \$1`).WithArgs(pcID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "level", "player_id"}).AddRow(pcID, "PC", 1, 7))
+	mock.ExpectQuery(`SELECT version, snapshot FROM character_versions`).
+		WithArgs(models.CharacterTypePC, pcID).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(`INSERT INTO character_versions`).
+		WithArgs(models.CharacterTypePC, pcID, nil, 1, action, 7, "PC", sqlmock.AnyArg(), "[]").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(pcID*10, time.Now()))
+}
+
+func TestRecordCampaignCharacterVersion(t *testing.T) {
+	t.Run("first version has no changes", func(t *testing.T) {
+		pdb, mock, cleanup := newMockCampaignDB(t)
+		defer cleanup()
+
+		expectCampaignCharacterVersion(mock, 5, 1, models.VersionActionCreate)
+
+		if err := pdb.RecordCampaignCharacterVersion(context.Background(), 5, 7, models.VersionActionCreate); err != nil {
+			t.Fatalf("expected version to be recorded, got %v", err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+
+	t.Run("records field diff against previous version", func(t *testing.T) {
+		pdb, mock, cleanup := newMockCampaignDB(t)
+		defer cleanup()
+
+		character := models.CampaignCharacter{ID: 5, CampaignID: 1, Name: "Aria", Level: 2, Status: "active"}
+		previous := character.VersionState()
+
+		mock.ExpectQuery(`SELECT\s+id, campaign_id, player_id`).WithArgs(5).
+			WillReturnRows(campaignCharacterStateRow(5, 1, 3, "active"))
+		mock.ExpectQuery(`SELECT version, snapshot FROM character_versions`).
+			WithArgs(models.CharacterTypeCampaign, 5).
+			WillReturnRows(sqlmock.NewRows([]string{"version", "snapshot"}).AddRow(4, mustJSON(t, previous)))
+		mock.ExpectQuery(`INSERT INTO character_versions`).
+			WithArgs(models.CharacterTypeCampaign, 5, 1, 5, models.VersionActionUpdate,
+				7, "Aria", sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(50, time.Now()))
+
+		version, err := recordCampaignCharacterVersionTx(context.Background(), pdb.DB, 5, intPtr(7), models.VersionActionUpdate)
+		if err != nil {
+			t.Fatalf("expected version to be recorded, got %v", err)
+		}
+
+		changed := map[string]bool{}
+		for _, change := range version.Changes {
+			changed[change.Field] = true
+		}
+		if version.Version != 5 || !changed["level"] || !changed["race"] {
+			t.Fatalf("expected version 5 with level and race changes, got %+v", version)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+
+	t.Run("skips unchanged state", func(t *testing.T) {
+		pdb, mock, cleanup := newMockCampaignDB(t)
+		defer cleanup()
+
+		snapshot := &capturedArg{}
+		mock.ExpectQuery(`SELECT\s+id, campaign_id, player_id`).WithArgs(5).
+			WillReturnRows(campaignCharacterStateRow(5, 1, 3, "active"))
+		mock.ExpectQuery(`SELECT version, snapshot FROM character_versions`).
+			WithArgs(models.CharacterTypeCampaign, 5).
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectQuery(`INSERT INTO character_versions`).
+			WithArgs(models.CharacterTypeCampaign, 5, 1, 1, models.VersionActionCreate,
+				7, "Aria", snapshot, "[]").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(50, time.Now()))
+
+		if err := pdb.RecordCampaignCharacterVersion(context.Background(), 5, 7, models.VersionActionCreate); err != nil {
+			t.Fatalf("expected first version, got %v", err)
+		}
+
+		mock.ExpectQuery(`SELECT\s+id, campaign_id, player_id`).WithArgs(5).
+			WillReturnRows(campaignCharacterStateRow(5, 1, 3, "active"))
+		mock.ExpectQuery(`SELECT version, snapshot FROM character_versions`).
+			WithArgs(models.CharacterTypeCampaign, 5).
+			WillReturnRows(sqlmock.NewRows([]string{"version", "snapshot"}).AddRow(1, []byte(snapshot.value.(string))))
+
+		version, err := recordCampaignCharacterVersionTx(context.Background(), pdb.DB, 5, intPtr(7), models.VersionActionUpdate)
+		if err != nil || version != nil {
+			t.Fatalf("expected no new version, got %+v, %v", version, err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+}
+
+func TestRecordBaselineVersion(t *testing.T) {
+	t.Run("records current state when there is no history", func(t *testing.T) {
+		pdb, mock, cleanup := newMockCampaignDB(t)
+		defer cleanup()
+
+		mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM character_versions`).WithArgs(models.CharacterTypeCampaign, 5).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+		mock.ExpectQuery(`SELECT\s+id, campaign_id, player_id`).WithArgs(5).
+			WillReturnRows(campaignCharacterStateRow(5, 1, 3, "active"))
+		mock.ExpectQuery(`SELECT version, snapshot FROM character_versions`).
+			WithArgs(models.CharacterTypeCampaign, 5).
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectQuery(`INSERT INTO character_versions`).
+			WithArgs(models.CharacterTypeCampaign, 5, 1, 1, models.VersionActionBaseline,
+				nil, "Aria", sqlmock.AnyArg(), "[]").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(50, time.Now()))
+
+		if err := recordBaselineVersionTx(context.Background(), pdb.DB, models.CharacterTypeCampaign, 5); err != nil {
+			t.Fatalf("expected baseline version, got %v", err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+
+	t.Run("skips characters with history", func(t *testing.T) {
+		pdb, mock, cleanup := newMockCampaignDB(t)
+		defer cleanup()
+
+		expectVersionHistory(mock, models.CharacterTypePC, 3)
+
+		if err := recordBaselineVersionTx(context.Background(), pdb.DB, models.CharacterTypePC, 3); err != nil {
+			t.Fatalf("expected no baseline version, got %v", err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+}
+
+// capturedArg aceita qualquer argumento e guarda o valor enviado ao banco
+type capturedArg struct {
+	value driver.Value
+}
+
+func (c *capturedArg) Match(v driver.Value) bool {
+	c.value = v
+	return true
+}
+
+func TestRevertCampaignCharacter_VersionNotFound(t *testing.T) {
+	pdb, mock, cleanup := newMockCampaignDB(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT snapshot FROM character_versions`).
+		WithArgs(models.CharacterTypeCampaign, 5, 3, 1).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	_, _, err := pdb.RevertCampaignCharacter(context.Background(), 1, 5, 3, 7)
+	if !errors.Is(err, ErrVersionNotFound) {
+		t.Fatalf("expected ErrVersionNotFound, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestRevertCampaignCharacter_KeepsStatus(t *testing.T) {
+	pdb, mock, cleanup := newMockCampaignDB(t)
+	defer cleanup()
+
+	// A versão antiga é de quando o personagem estava morto; o status atual é mantido
+	old := models.CampaignCharacter{ID: 5, CampaignID: 1, Name: "Aria", Level: 2, Status: "dead"}
+	cols := append(append([]string{}, campaignCharacterStateCols...), "frozen_at")
+	row := func(frozenAt any) *sqlmock.Rows {
+		return sqlmock.NewRows(cols).AddRow(
+			5, 1, 7, 3, "active", time.Now(), nil, "",
+			"Aria", "", 3, "Elf", "Wizard", "Sage", "Neutral", []byte(`{}`), []byte(`{}`),
+			[]byte(`[]`), 12, 12, 13, 2, false, []byte(`{}`), []byte(`[]`), []byte(`{}`),
+			"", "", "", "", "{}", "Bob",
+			0, false, frozenAt,
+		)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT snapshot FROM character_versions`).
+		WithArgs(models.CharacterTypeCampaign, 5, 2, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"snapshot"}).AddRow(mustJSON(t, old.VersionState())))
+	mock.ExpectQuery(`FROM campaign_characters\s+WHERE id = \$1 AND campaign_id = \$2\s+FOR UPDATE`).WithArgs(5, 1).
+		WillReturnRows(row(nil))
+	mock.ExpectExec(`UPDATE campaign_characters SET`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectCampaignCharacterVersion(mock, 5, 1, models.VersionActionRevert)
+	mock.ExpectCommit()
+
+	character, _, err := pdb.RevertCampaignCharacter(context.Background(), 1, 5, 2, 7)
+	if err != nil {
+		t.Fatalf("expected revert, got %v", err)
+	}
+	if character.Status != "active" || character.Level != 2 {
+		t.Fatalf("expected level reverted and status kept, got %+v", character)
+	}
+
+	// Personagem congelado não é revertido
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT snapshot FROM character_versions`).
+		WithArgs(models.CharacterTypeCampaign, 5, 2, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"snapshot"}).AddRow(mustJSON(t, old.VersionState())))
+	mock.ExpectQuery(`FOR UPDATE`).WithArgs(5, 1).WillReturnRows(row(time.Now()))
+	mock.ExpectRollback()
+
+	if _, _, err := pdb.RevertCampaignCharacter(context.Background(), 1, 5, 2, 7); !errors.Is(err, ErrCharacterFrozen) {
+		t.Fatalf("expected ErrCharacterFrozen, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func mustJSON(t *testing.T, value any) []byte {
+	t.Helper()
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("failed to marshal fixture: %v", err)
+	}
+	return data
+}
+
+func TestRevertCampaignCharacter_DeadKeepsHP(t *testing.T) {
+	pdb, mock, cleanup := newMockCampaignDB(t)
+	defer cleanup()
+
+	// A versão antiga tinha PV; o personagem morto continua com 0
+	hp := 8
+	old := models.CampaignCharacter{ID: 5, CampaignID: 1, Name: "Aria", Level: 2, CurrentHP: &hp, Status: "active"}
+	cols := append(append([]string{}, campaignCharacterStateCols...), "frozen_at")
+	args := make([]driver.Value, 30)
+	for i := range args {
+		args[i] = sqlmock.AnyArg()
+	}
+	args[11] = 0
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT snapshot FROM character_versions`).
+		WithArgs(models.CharacterTypeCampaign, 5, 2, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"snapshot"}).AddRow(mustJSON(t, old.VersionState())))
+	mock.ExpectQuery(`FOR UPDATE`).WithArgs(5, 1).
+		WillReturnRows(sqlmock.NewRows(cols).AddRow(
+			5, 1, 7, 3, "dead", time.Now(), nil, "",
+			"Aria", "", 3, "Elf", "Wizard", "Sage", "Neutral", []byte(`{}`), []byte(`{}`),
+			[]byte(`[]`), 12, 0, 13, 2, false, []byte(`{}`), []byte(`[]`), []byte(`{}`),
+			"", "", "", "", "{}", "Bob",
+			0, false, nil,
+		))
+	mock.ExpectExec(`UPDATE campaign_characters SET`).WithArgs(args...).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectCampaignCharacterVersion(mock, 5, 1, models.VersionActionRevert)
+	mock.ExpectCommit()
+
+	character, _, err := pdb.RevertCampaignCharacter(context.Background(), 1, 5, 2, 7)
+	if err != nil {
+		t.Fatalf("expected revert, got %v", err)
+	}
+	if character.Status != "dead" || character.CurrentHP == nil || *character.CurrentHP != 0 || character.Level != 2 {
+		t.Fatalf("expected level reverted with dead status and HP kept, got %+v", character)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
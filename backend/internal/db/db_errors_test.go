@@ -50,7 +50,7 @@ func TestRemovePlayerFromCampaign_Error(t *testing.T) {
 	pdb, mock, cleanup := newMockDB(t)
 	defer cleanup()
 
-	mock.ExpectExec(`DELETE FROM campaign_players`).
+	mock.ExpectExec(`UPDATE campaign_players SET status = 'removed'`).
 		WithArgs(1, 5).
 		WillReturnError(errors.New("db error"))
 
@@ -141,9 +141,12 @@ func TestUpdateCampaignCharacter_Error(t *testing.T) {
 	defer cleanup()
 
 	currentHP := 100
+	mock.ExpectBegin()
+	expectVersionHistory(mock, models.CharacterTypeCampaign, 10)
 	mock.ExpectExec(`UPDATE campaign_characters`).
 		WithArgs(100, "healthy", "notes", 10, 1).
 		WillReturnError(errors.New("db error"))
+	mock.ExpectRollback()
 
 	character := &models.CampaignCharacter{
 		ID:            10,
@@ -153,7 +156,7 @@ func TestUpdateCampaignCharacter_Error(t *testing.T) {
 		CampaignNotes: "notes",
 	}
 
-	err := pdb.UpdateCampaignCharacter(context.Background(), character)
+	err := pdb.UpdateCampaignCharacter(context.Background(), character, 7)
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
@@ -0,0 +1,160 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"rpg-saas-backend/internal/models"
+)
+
+// LoadCharacterRules resolve as regras usadas no cálculo da ficha: raça e classe vêm do SRD
+// (dnd_races, dnd_classes) pelo índice ou nome e, se não existirem lá, das tabelas homebrew
+// visíveis ao usuário; as armaduras equipadas vêm de dnd_equipment. Raça ou classe não
+// encontrada usa os valores padrão (fonte "default"). Multiclasse carrega as regras de cada
+// classe de class_levels; a primeira é a classe inicial.
+func (p *PostgresDB) LoadCharacterRules(ctx context.Context, pc *models.PC, userID int) (*models.CharacterRules, error) {
+	race, err := p.loadRaceRules(ctx, pc.Race, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{pc.Class}
+	if classes := pc.Classes(); len(classes) > 0 {
+		names = make([]string, len(classes))
+		for i, class := range classes {
+			names[i] = class.Class
+		}
+	}
+
+	classes := make([]models.ClassRules, len(names))
+	for i, name := range names {
+		if classes[i], err = p.loadClassRules(ctx, name, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	armor, err := p.loadEquippedArmor(ctx, pc.EquippedItemNames())
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.CharacterRules{Race: race, Class: classes[0], Classes: classes, Armor: armor}, nil
+}
+
+func (p *PostgresDB) loadRaceRules(ctx context.Context, name string, userID int) (models.RaceRules, error) {
+	var race models.DnDRace
+	err := p.DB.GetContext(ctx, &race, `
+		SELECT api_index, name, COALESCE(speed, 30) AS speed,
+		       COALESCE(ability_bonuses, '[]'::jsonb) AS ability_bonuses,
+		       COALESCE(proficiencies, '[]'::jsonb) AS proficiencies
+		FROM dnd_races
+		WHERE api_index = LOWER($1) OR LOWER(name) = LOWER($1)
+		LIMIT 1
+	`, name)
+	if err == nil {
+		return models.RaceRulesFromSRD(&race), nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return models.RaceRules{}, fmt.Errorf("failed to fetch D&D race %q: %w", name, err)
+	}
+
+	var homebrew models.HomebrewRace
+	err = p.DB.GetContext(ctx, &homebrew, `
+		SELECT id, name, COALESCE(speed, 30) AS speed,
+		       COALESCE(abilities, '{}'::jsonb) AS abilities,
+		       COALESCE(proficiencies, '{}'::jsonb) AS proficiencies
+		FROM homebrew_races
+		WHERE LOWER(name) = LOWER($1) AND (is_public = true OR user_id = $2)
+		ORDER BY (user_id = $2) DESC, id
+		LIMIT 1
+	`, name, userID)
+	if err == nil {
+		return models.RaceRulesFromHomebrew(&homebrew), nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return models.RaceRules{}, fmt.Errorf("failed to fetch homebrew race %q: %w", name, err)
+	}
+
+	return models.RaceRules{Name: name, Source: models.RulesSourceDefault, Speed: 30}, nil
+}
+
+func (p *PostgresDB) loadClassRules(ctx context.Context, name string, userID int) (models.ClassRules, error) {
+	var class models.DnDClass
+	err := p.DB.GetContext(ctx, &class, `
+		SELECT api_index, name, COALESCE(hit_die, 8) AS hit_die,
+		       COALESCE(saving_throws, ARRAY[]::text[]) AS saving_throws,
+		       COALESCE(proficiency_choices, '[]'::jsonb) AS proficiency_choices,
+		       COALESCE(spellcasting, '{}'::jsonb) AS spellcasting,
+		       COALESCE(spellcasting_ability, '') AS spellcasting_ability
+		FROM dnd_classes
+		WHERE api_index = LOWER($1) OR LOWER(name) = LOWER($1)
+		LIMIT 1
+	`, name)
+	if err == nil {
+		return models.ClassRulesFromSRD(&class), nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return models.ClassRules{}, fmt.Errorf("failed to fetch D&D class %q: %w", name, err)
+	}
+
+	var homebrew models.HomebrewClass
+	err = p.DB.GetContext(ctx, &homebrew, `
+		SELECT id, name, hit_die, saving_throws,
+		       COALESCE(skill_choices, '{}'::jsonb) AS skill_choices,
+		       COALESCE(spellcasting, '{}'::jsonb) AS spellcasting
+		FROM homebrew_classes
+		WHERE LOWER(name) = LOWER($1) AND (is_public = true OR user_id = $2)
+		ORDER BY (user_id = $2) DESC, id
+		LIMIT 1
+	`, name, userID)
+	if err == nil {
+		return models.ClassRulesFromHomebrew(&homebrew), nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return models.ClassRules{}, fmt.Errorf("failed to fetch homebrew class %q: %w", name, err)
+	}
+
+	return models.ClassRules{
+		Name:         name,
+		Source:       models.RulesSourceDefault,
+		HitDie:       models.DefaultHitDie,
+		SavingThrows: []string{},
+	}, nil
+}
+
+// loadEquippedArmor busca as armaduras e escudos do SRD entre os itens equipados (pelo nome
+// ou índice); os demais itens são ignorados
+func (p *PostgresDB) loadEquippedArmor(ctx context.Context, names []string) ([]models.ArmorRules, error) {
+	armor := []models.ArmorRules{}
+	if len(names) == 0 {
+		return armor, nil
+	}
+
+	keys := make([]string, len(names))
+	for i, name := range names {
+		keys[i] = strings.ToLower(name)
+	}
+
+	items := []models.DnDEquipment{}
+	err := p.DB.SelectContext(ctx, &items, `
+		SELECT api_index, name, COALESCE(armor_category, '') AS armor_category,
+		       COALESCE(armor_class, '{}'::jsonb) AS armor_class
+		FROM dnd_equipment
+		WHERE armor_category IS NOT NULL AND (api_index = ANY($1) OR LOWER(name) = ANY($1))
+	`, pq.Array(keys))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch equipped armor: %w", err)
+	}
+
+	for i := range items {
+		if piece, ok := models.ArmorRulesFromSRD(&items[i]); ok {
+			armor = append(armor, piece)
+		}
+	}
+	return armor, nil
+}
@@ -0,0 +1,82 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+
+	"rpg-saas-backend/internal/models"
+)
+
+func TestLoadCharacterRules_SRD(t *testing.T) {
+	pdb, mock, cleanup := newMockPCDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery(`FROM dnd_races`).WithArgs("Elf").
+		WillReturnRows(sqlmock.NewRows([]string{"api_index", "name", "speed", "ability_bonuses", "proficiencies"}).
+			AddRow("elf", "Elf", 30, []byte(`[{"ability_score":{"index":"dex"},"bonus":2}]`), []byte(`[{"index":"skill-perception"}]`)))
+	mock.ExpectQuery(`FROM dnd_classes`).WithArgs("Fighter").
+		WillReturnRows(sqlmock.NewRows([]string{"api_index", "name", "hit_die", "saving_throws", "spellcasting", "spellcasting_ability"}).
+			AddRow("fighter", "Fighter", 10, pq.StringArray{"str", "con"}, []byte(`{}`), ""))
+	mock.ExpectQuery(`FROM dnd_equipment`).WithArgs(`{"chain mail","shield"}`).
+		WillReturnRows(sqlmock.NewRows([]string{"api_index", "name", "armor_category", "armor_class"}).
+			AddRow("chain-mail", "Chain Mail", "Heavy", []byte(`{"base":16,"dex_bonus":false}`)).
+			AddRow("shield", "Shield", "Shield", []byte(`{"base":2,"dex_bonus":false}`)))
+
+	pc := &models.PC{Race: "Elf", Class: "Fighter", Equipment: models.JSONBFlexible{Data: []any{
+		map[string]any{"name": "Chain Mail", "equipped": true},
+		map[string]any{"name": "Shield", "equipped": true},
+		map[string]any{"name": "Longbow", "equipped": false},
+	}}}
+
+	rules, err := pdb.LoadCharacterRules(context.Background(), pc, 7)
+	if err != nil {
+		t.Fatalf("LoadCharacterRules returned error: %v", err)
+	}
+	if rules.Race.Source != models.RulesSourceSRD || rules.Race.Skills[0] != "perception" {
+		t.Fatalf("unexpected race rules: %+v", rules.Race)
+	}
+	if rules.Class.HitDie != 10 || rules.Class.SavingThrows[1] != "constitution" {
+		t.Fatalf("unexpected class rules: %+v", rules.Class)
+	}
+	if len(rules.Armor) != 2 {
+		t.Fatalf("expected armor and shield, got %+v", rules.Armor)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestLoadCharacterRules_HomebrewFallback(t *testing.T) {
+	pdb, mock, cleanup := newMockPCDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery(`FROM dnd_races`).WithArgs("Sylvan").WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(`FROM homebrew_races`).WithArgs("Sylvan", 7).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "speed", "abilities", "proficiencies"}).
+			AddRow(3, "Sylvan", 35, []byte(`{"wisdom":2}`), []byte(`{"skills":["stealth"]}`)))
+	mock.ExpectQuery(`FROM dnd_classes`).WithArgs("Runesmith").WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(`FROM homebrew_classes`).WithArgs("Runesmith", 7).WillReturnError(sql.ErrNoRows)
+
+	rules, err := pdb.LoadCharacterRules(context.Background(), &models.PC{Race: "Sylvan", Class: "Runesmith"}, 7)
+	if err != nil {
+		t.Fatalf("LoadCharacterRules returned error: %v", err)
+	}
+	if rules.Race.Source != models.RulesSourceHomebrew || rules.Race.Speed != 35 {
+		t.Fatalf("expected homebrew race, got %+v", rules.Race)
+	}
+	if rules.Class.Source != models.RulesSourceDefault || rules.Class.HitDie != models.DefaultHitDie {
+		t.Fatalf("expected default class, got %+v", rules.Class)
+	}
+	if len(rules.Armor) != 0 {
+		t.Fatalf("expected no armor, got %+v", rules.Armor)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
@@ -0,0 +1,177 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"rpg-saas-backend/internal/models"
+)
+
+// ErrDiceMacroNameTaken indica que o usuário já tem uma macro com o mesmo nome no mesmo
+// escopo (geral ou do PC)
+var ErrDiceMacroNameTaken = errors.New("a dice macro with this name already exists")
+
+// GetDiceMacros retorna as macros do usuário. Se pcID for informado, retorna
+// as macros daquele PC junto com as macros gerais do usuário.
+func (p *PostgresDB) GetDiceMacros(ctx context.Context, userID int, pcID *int) ([]models.DiceMacro, error) {
+	macros := []models.DiceMacro{}
+	query := `
+		SELECT id, user_id, pc_id, name, notation, label, advantage, disadvantage, created_at, updated_at
+		FROM dice_macros
+		WHERE user_id = $1
+		ORDER BY pc_id NULLS FIRST, name
+	`
+	args := []any{userID}
+
+	if pcID != nil {
+		query = `
+			SELECT id, user_id, pc_id, name, notation, label, advantage, disadvantage, created_at, updated_at
+			FROM dice_macros
+			WHERE user_id = $1 AND (pc_id IS NULL OR pc_id = $2)
+			ORDER BY pc_id NULLS FIRST, name
+		`
+		args = append(args, *pcID)
+	}
+
+	if err := p.DB.SelectContext(ctx, &macros, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to fetch dice macros for user %d: %w", userID, err)
+	}
+
+	return macros, nil
+}
+
+// GetDiceMacroByID retorna uma macro específica do usuário.
+// Retorna nil, nil se a macro não existir ou pertencer a outro usuário.
+func (p *PostgresDB) GetDiceMacroByID(ctx context.Context, id, userID int) (*models.DiceMacro, error) {
+	var macro models.DiceMacro
+	query := `
+		SELECT id, user_id, pc_id, name, notation, label, advantage, disadvantage, created_at, updated_at
+		FROM dice_macros
+		WHERE id = $1 AND user_id = $2
+	`
+
+	if err := p.DB.GetContext(ctx, &macro, query, id, userID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch dice macro with ID %d: %w", id, err)
+	}
+
+	return &macro, nil
+}
+
+// GetDiceMacroByName busca uma macro pelo nome (sem diferenciar maiúsculas).
+// Quando pcID é informado, a macro do PC tem prioridade sobre a do usuário.
+// Retorna nil, nil se nenhuma macro for encontrada.
+func (p *PostgresDB) GetDiceMacroByName(ctx context.Context, userID int, pcID *int, name string) (*models.DiceMacro, error) {
+	var macro models.DiceMacro
+	query := `
+		SELECT id, user_id, pc_id, name, notation, label, advantage, disadvantage, created_at, updated_at
+		FROM dice_macros
+		WHERE user_id = $1 AND LOWER(name) = LOWER($2)
+		  AND (pc_id IS NULL OR pc_id = $3)
+		ORDER BY pc_id NULLS LAST
+		LIMIT 1
+	`
+
+	if err := p.DB.GetContext(ctx, &macro, query, userID, name, pcID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch dice macro %q: %w", name, err)
+	}
+
+	return &macro, nil
+}
+
+// CreateDiceMacro salva uma nova macro
+func (p *PostgresDB) CreateDiceMacro(ctx context.Context, macro *models.DiceMacro) error {
+	query := `
+		INSERT INTO dice_macros (user_id, pc_id, name, notation, label, advantage, disadvantage, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id
+	`
+
+	now := time.Now()
+	macro.CreatedAt = now
+	macro.UpdatedAt = now
+
+	err := p.DB.QueryRowContext(ctx, query,
+		macro.UserID, macro.PCID, macro.Name, macro.Notation, macro.Label,
+		macro.Advantage, macro.Disadvantage, macro.CreatedAt, macro.UpdatedAt,
+	).Scan(&macro.ID)
+	if isUniqueViolation(err) {
+		return ErrDiceMacroNameTaken
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create dice macro: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateDiceMacro atualiza uma macro do usuário
+func (p *PostgresDB) UpdateDiceMacro(ctx context.Context, macro *models.DiceMacro) error {
+	query := `
+		UPDATE dice_macros SET
+		pc_id = $1, name = $2, notation = $3, label = $4, advantage = $5, disadvantage = $6, updated_at = $7
+		WHERE id = $8 AND user_id = $9
+	`
+
+	macro.UpdatedAt = time.Now()
+
+	result, err := p.DB.ExecContext(ctx, query,
+		macro.PCID, macro.Name, macro.Notation, macro.Label,
+		macro.Advantage, macro.Disadvantage, macro.UpdatedAt,
+		macro.ID, macro.UserID,
+	)
+	if isUniqueViolation(err) {
+		return ErrDiceMacroNameTaken
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update dice macro with ID %d: %w", macro.ID, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("dice macro not found or user not authorized")
+	}
+
+	return nil
+}
+
+// DeleteDiceMacro remove uma macro do usuário
+func (p *PostgresDB) DeleteDiceMacro(ctx context.Context, id, userID int) error {
+	query := `DELETE FROM dice_macros WHERE id = $1 AND user_id = $2`
+
+	result, err := p.DB.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete dice macro with ID %d: %w", id, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("dice macro not found or user not authorized")
+	}
+
+	return nil
+}
+
+// isUniqueViolation indica se o erro do PostgreSQL é de violação de índice único
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
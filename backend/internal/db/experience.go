@@ -0,0 +1,161 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"rpg-saas-backend/internal/models"
+)
+
+// ErrEncounterUnavailable indica encontro fora da campanha ou com XP já distribuído
+var ErrEncounterUnavailable = errors.New("encounter not found in campaign or XP already awarded")
+
+// AwardExperience divide o XP de um encontro da campanha ou uma quantia avulsa entre os
+// personagens escolhidos e marca quem atingiu o limiar do próximo nível. O XP de um encontro
+// só pode ser distribuído uma vez. Cada personagem ganha uma versão em nome de awardedBy.
+func (p *PostgresDB) AwardExperience(ctx context.Context, campaignID, awardedBy int, req models.AwardXPRequest) (*models.XPAwardResult, error) {
+	tx, err := p.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	total := req.Amount
+	if req.EncounterID != nil {
+		err := tx.QueryRowContext(ctx, `
+			UPDATE encounters SET xp_awarded = TRUE
+			WHERE id = $1 AND campaign_id = $2 AND NOT xp_awarded
+			RETURNING COALESCE(total_xp, 0)
+		`, *req.EncounterID, campaignID).Scan(&total)
+		if err == sql.ErrNoRows {
+			return nil, ErrEncounterUnavailable
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to claim encounter %d XP: %w", *req.EncounterID, err)
+		}
+	}
+
+	characters, err := lockProgressTx(ctx, tx, campaignID, req.CharacterIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	share := models.SplitXP(total, len(characters))
+	for i := range characters {
+		character := &characters[i]
+		character.XPGained = share
+		character.ExperiencePoints += share
+		character.LevelUpAvailable = models.CanLevelUpWithXP(character.Level, character.ExperiencePoints)
+		if next, ok := models.XPForLevel(character.Level + 1); ok {
+			character.NextLevelXP = &next
+		}
+
+		if err := recordBaselineVersionTx(ctx, tx, models.CharacterTypeCampaign, character.CharacterID); err != nil {
+			return nil, err
+		}
+		_, err := tx.ExecContext(ctx, `
+			UPDATE campaign_characters SET experience_points = $1, level_up_available = $2,
+			updated_at = CURRENT_TIMESTAMP
+			WHERE id = $3 AND campaign_id = $4
+		`, character.ExperiencePoints, character.LevelUpAvailable, character.CharacterID, campaignID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to award XP to character %d: %w", character.CharacterID, err)
+		}
+
+		if _, err := recordCampaignCharacterVersionTx(ctx, tx, character.CharacterID, &awardedBy, models.VersionActionXP); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit XP award: %w", err)
+	}
+
+	return &models.XPAwardResult{
+		LevelingMode: models.LevelingModeXP,
+		EncounterID:  req.EncounterID,
+		TotalXP:      total,
+		XPPerChar:    share,
+		Reason:       req.Reason,
+		Characters:   characters,
+	}, nil
+}
+
+// AwardMilestone libera a subida de nível dos personagens escolhidos (campanhas por marco).
+// Personagens no nível máximo não são marcados.
+func (p *PostgresDB) AwardMilestone(ctx context.Context, campaignID, awardedBy int, req models.AwardMilestoneRequest) (*models.XPAwardResult, error) {
+	tx, err := p.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	characters, err := lockProgressTx(ctx, tx, campaignID, req.CharacterIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range characters {
+		character := &characters[i]
+		character.LevelUpAvailable = character.Level < models.MaxCharacterLevel
+
+		if err := recordBaselineVersionTx(ctx, tx, models.CharacterTypeCampaign, character.CharacterID); err != nil {
+			return nil, err
+		}
+		_, err := tx.ExecContext(ctx, `
+			UPDATE campaign_characters SET level_up_available = $1, updated_at = CURRENT_TIMESTAMP
+			WHERE id = $2 AND campaign_id = $3
+		`, character.LevelUpAvailable, character.CharacterID, campaignID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to award milestone to character %d: %w", character.CharacterID, err)
+		}
+
+		if _, err := recordCampaignCharacterVersionTx(ctx, tx, character.CharacterID, &awardedBy, models.VersionActionXP); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit milestone: %w", err)
+	}
+
+	return &models.XPAwardResult{
+		LevelingMode: models.LevelingModeMilestone,
+		Reason:       req.Reason,
+		Characters:   characters,
+	}, nil
+}
+
+// lockProgressTx trava os personagens ativos da campanha; falha se algum id não for um deles
+func lockProgressTx(ctx context.Context, tx *sqlx.Tx, campaignID int, characterIDs []int) ([]models.CharacterProgress, error) {
+	unique := map[int]bool{}
+	ids := pq.Int64Array{}
+	for _, id := range characterIDs {
+		if !unique[id] {
+			unique[id] = true
+			ids = append(ids, int64(id))
+		}
+	}
+
+	characters := []models.CharacterProgress{}
+	err := tx.SelectContext(ctx, &characters, `
+		SELECT id, name, level, experience_points, level_up_available
+		FROM campaign_characters
+		WHERE campaign_id = $1 AND id = ANY($2) AND status = 'active'
+		ORDER BY id
+		FOR UPDATE
+	`, campaignID, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch campaign characters: %w", err)
+	}
+	if len(characters) != len(ids) {
+		return nil, fmt.Errorf("%w: some characters are not active in this campaign", ErrCharacterNotInCampaign)
+	}
+
+	return characters, nil
+}
@@ -0,0 +1,122 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+
+	"rpg-saas-backend/internal/models"
+)
+
+var progressCols = []string{"id", "name", "level", "experience_points", "level_up_available"}
+
+func TestAwardExperience_FromEncounter(t *testing.T) {
+	pdb, mock, cleanup := newMockCampaignDB(t)
+	defer cleanup()
+
+	encounterID := 4
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE encounters SET xp_awarded = TRUE`).WithArgs(4, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"total_xp"}).AddRow(1100))
+	mock.ExpectQuery(`FROM campaign_characters\s+WHERE campaign_id = \$1 AND id = ANY\(\$2\) AND status = 'active'`).
+		WithArgs(10, "{1,2}").
+		WillReturnRows(sqlmock.NewRows(progressCols).
+			AddRow(1, "Aria", 1, 0, false).
+			AddRow(2, "Brom", 2, 400, false))
+	expectVersionHistory(mock, models.CharacterTypeCampaign, 1)
+	mock.ExpectExec(`UPDATE campaign_characters SET experience_points = \$1, level_up_available = \$2`).
+		WithArgs(550, true, 1, 10).WillReturnResult(sqlmock.NewResult(0, 1))
+	expectCampaignCharacterVersion(mock, 1, 10, models.VersionActionXP)
+	expectVersionHistory(mock, models.CharacterTypeCampaign, 2)
+	mock.ExpectExec(`UPDATE campaign_characters SET experience_points = \$1, level_up_available = \$2`).
+		WithArgs(950, true, 2, 10).WillReturnResult(sqlmock.NewResult(0, 1))
+	expectCampaignCharacterVersion(mock, 2, 10, models.VersionActionXP)
+	mock.ExpectCommit()
+
+	result, err := pdb.AwardExperience(context.Background(), 10, 7, models.AwardXPRequest{
+		EncounterID:  &encounterID,
+		CharacterIDs: []int{1, 2, 2},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.TotalXP != 1100 || result.XPPerChar != 550 || len(result.Characters) != 2 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if next := result.Characters[1].NextLevelXP; next == nil || *next != 900 {
+		t.Fatalf("expected next level threshold 900, got %v", next)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestAwardExperience_EncounterAlreadyAwarded(t *testing.T) {
+	pdb, mock, cleanup := newMockCampaignDB(t)
+	defer cleanup()
+
+	encounterID := 4
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE encounters SET xp_awarded = TRUE`).WithArgs(4, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"total_xp"}))
+	mock.ExpectRollback()
+
+	_, err := pdb.AwardExperience(context.Background(), 10, 7, models.AwardXPRequest{EncounterID: &encounterID, CharacterIDs: []int{1}})
+	if !errors.Is(err, ErrEncounterUnavailable) {
+		t.Fatalf("expected ErrEncounterUnavailable, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestAwardExperience_CharacterOutsideCampaign(t *testing.T) {
+	pdb, mock, cleanup := newMockCampaignDB(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM campaign_characters`).WithArgs(10, "{1,9}").
+		WillReturnRows(sqlmock.NewRows(progressCols).AddRow(1, "Aria", 1, 0, false))
+	mock.ExpectRollback()
+
+	_, err := pdb.AwardExperience(context.Background(), 10, 7, models.AwardXPRequest{Amount: 200, CharacterIDs: []int{1, 9}})
+	if !errors.Is(err, ErrCharacterNotInCampaign) {
+		t.Fatalf("expected ErrCharacterNotInCampaign, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestAwardMilestone_SkipsMaxLevel(t *testing.T) {
+	pdb, mock, cleanup := newMockCampaignDB(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM campaign_characters`).WithArgs(10, "{1,2}").
+		WillReturnRows(sqlmock.NewRows(progressCols).
+			AddRow(1, "Aria", 5, 0, false).
+			AddRow(2, "Brom", 20, 0, false))
+	expectVersionHistory(mock, models.CharacterTypeCampaign, 1)
+	mock.ExpectExec(`UPDATE campaign_characters SET level_up_available = \$1`).WithArgs(true, 1, 10).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectCampaignCharacterVersion(mock, 1, 10, models.VersionActionXP)
+	expectVersionHistory(mock, models.CharacterTypeCampaign, 2)
+	mock.ExpectExec(`UPDATE campaign_characters SET level_up_available = \$1`).WithArgs(false, 2, 10).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectCampaignCharacterVersion(mock, 2, 10, models.VersionActionXP)
+	mock.ExpectCommit()
+
+	result, err := pdb.AwardMilestone(context.Background(), 10, 7, models.AwardMilestoneRequest{CharacterIDs: []int{1, 2}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Characters[0].LevelUpAvailable || result.Characters[1].LevelUpAvailable {
+		t.Fatalf("unexpected milestone result: %+v", result.Characters)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
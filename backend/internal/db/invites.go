@@ -0,0 +1,233 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"rpg-saas-backend/internal/models"
+)
+
+var (
+	// ErrInvalidInviteCode indica que o código não corresponde a nenhuma campanha ou convite
+	ErrInvalidInviteCode = errors.New("invalid invite code")
+	// ErrCampaignFull indica que a campanha atingiu max_players
+	ErrCampaignFull = errors.New("campaign is full")
+	// ErrJoinRequestNotFound indica que não há pedido pendente ou na lista de espera
+	ErrJoinRequestNotFound = errors.New("join request not found")
+)
+
+const inviteColumns = `
+	id, campaign_id, code, created_by, expires_at, max_uses, uses,
+	requires_approval, allow_waitlist, revoked, created_at
+`
+
+// GetCampaignInvites lista os convites da campanha, do mais recente ao mais antigo
+func (p *PostgresDB) GetCampaignInvites(ctx context.Context, campaignID int) ([]models.CampaignInvite, error) {
+	invites := []models.CampaignInvite{}
+	query := `SELECT ` + inviteColumns + ` FROM campaign_invites WHERE campaign_id = $1 ORDER BY created_at DESC`
+
+	if err := p.DB.SelectContext(ctx, &invites, query, campaignID); err != nil {
+		return nil, fmt.Errorf("failed to fetch invites for campaign %d: %w", campaignID, err)
+	}
+
+	return invites, nil
+}
+
+// CreateCampaignInvite cria um convite para a campanha
+func (p *PostgresDB) CreateCampaignInvite(ctx context.Context, invite *models.CampaignInvite) error {
+	query := `
+		INSERT INTO campaign_invites (campaign_id, code, created_by, expires_at, max_uses, requires_approval, allow_waitlist, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`
+
+	invite.CreatedAt = time.Now()
+
+	err := p.DB.QueryRowContext(ctx, query,
+		invite.CampaignID, invite.Code, invite.CreatedBy, invite.ExpiresAt, invite.MaxUses,
+		invite.RequiresApproval, invite.AllowWaitlist, invite.CreatedAt,
+	).Scan(&invite.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create invite: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeCampaignInvite invalida um convite da campanha
+func (p *PostgresDB) RevokeCampaignInvite(ctx context.Context, id, campaignID int) error {
+	result, err := p.DB.ExecContext(ctx, `UPDATE campaign_invites SET revoked = TRUE WHERE id = $1 AND campaign_id = $2`, id, campaignID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke invite: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("invite not found in campaign")
+	}
+
+	return nil
+}
+
+// RedeemCampaignInvite usa um convite para entrar na campanha. Dependendo do convite,
+// o jogador entra como ativo, pendente de aprovação ou na lista de espera.
+func (p *PostgresDB) RedeemCampaignInvite(ctx context.Context, code string, userID int) (*models.JoinCampaignResult, error) {
+	tx, err := p.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var invite models.CampaignInvite
+	query := `SELECT ` + inviteColumns + ` FROM campaign_invites WHERE code = $1 FOR UPDATE`
+	if err := tx.GetContext(ctx, &invite, query, code); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrInvalidInviteCode
+		}
+		return nil, fmt.Errorf("failed to fetch invite: %w", err)
+	}
+
+	switch {
+	case invite.Revoked:
+		return nil, fmt.Errorf("invite has been revoked")
+	case invite.IsExpired(time.Now()):
+		return nil, fmt.Errorf("invite has expired")
+	case invite.IsExhausted():
+		return nil, fmt.Errorf("invite has reached its usage limit")
+	}
+
+	var dmID, maxPlayers, playerCount int
+	capacityQuery := `
+		SELECT c.dm_id, c.max_players,
+		       (SELECT COUNT(*) FROM campaign_players cp WHERE cp.campaign_id = c.id AND cp.status = 'active')
+		FROM campaigns c
+		WHERE c.id = $1
+		FOR UPDATE
+	`
+	if err := tx.QueryRowContext(ctx, capacityQuery, invite.CampaignID).Scan(&dmID, &maxPlayers, &playerCount); err != nil {
+		return nil, fmt.Errorf("failed to check campaign capacity: %w", err)
+	}
+
+	if dmID == userID {
+		return nil, fmt.Errorf("DM cannot join their own campaign as a player")
+	}
+
+	var exists bool
+	checkQuery := `SELECT EXISTS(SELECT 1 FROM campaign_players WHERE campaign_id = $1 AND user_id = $2)`
+	if err := tx.GetContext(ctx, &exists, checkQuery, invite.CampaignID, userID); err != nil {
+		return nil, fmt.Errorf("failed to check if player exists: %w", err)
+	}
+	if exists {
+		if err := p.checkReturningPlayer(ctx, tx, invite.CampaignID, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Com aprovação, a lotação é verificada quando o DM aceita o pedido
+	result := &models.JoinCampaignResult{CampaignID: invite.CampaignID, Status: models.PlayerStatusActive}
+	switch {
+	case invite.RequiresApproval:
+		result.Status = models.PlayerStatusPending
+	case playerCount >= maxPlayers && invite.AllowWaitlist:
+		result.Status = models.PlayerStatusWaitlisted
+	case playerCount >= maxPlayers:
+		return nil, ErrCampaignFull
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO campaign_players (campaign_id, user_id, joined_at, status)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (campaign_id, user_id)
+		DO UPDATE SET status = EXCLUDED.status, role = 'player', joined_at = EXCLUDED.joined_at
+	`, invite.CampaignID, userID, time.Now(), result.Status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add player to campaign: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE campaign_invites SET uses = uses + 1 WHERE id = $1`, invite.ID); err != nil {
+		return nil, fmt.Errorf("failed to register invite use: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit invite use: %w", err)
+	}
+
+	return result, nil
+}
+
+// ApproveCampaignPlayer ativa um jogador pendente ou da lista de espera, respeitando max_players
+func (p *PostgresDB) ApproveCampaignPlayer(ctx context.Context, campaignID, userID int) error {
+	tx, err := p.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var status string
+	statusQuery := `SELECT status FROM campaign_players WHERE campaign_id = $1 AND user_id = $2 FOR UPDATE`
+	if err := tx.GetContext(ctx, &status, statusQuery, campaignID, userID); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrJoinRequestNotFound
+		}
+		return fmt.Errorf("failed to fetch join request: %w", err)
+	}
+	if status != models.PlayerStatusPending && status != models.PlayerStatusWaitlisted {
+		return ErrJoinRequestNotFound
+	}
+
+	var maxPlayers, playerCount int
+	capacityQuery := `
+		SELECT c.max_players,
+		       (SELECT COUNT(*) FROM campaign_players cp WHERE cp.campaign_id = c.id AND cp.status = 'active')
+		FROM campaigns c
+		WHERE c.id = $1
+		FOR UPDATE
+	`
+	if err := tx.QueryRowContext(ctx, capacityQuery, campaignID).Scan(&maxPlayers, &playerCount); err != nil {
+		return fmt.Errorf("failed to check campaign capacity: %w", err)
+	}
+	if playerCount >= maxPlayers {
+		return ErrCampaignFull
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE campaign_players SET status = 'active', joined_at = $1
+		WHERE campaign_id = $2 AND user_id = $3
+	`, time.Now(), campaignID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to approve player: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// RejectCampaignPlayer remove um pedido pendente ou da lista de espera
+func (p *PostgresDB) RejectCampaignPlayer(ctx context.Context, campaignID, userID int) error {
+	query := `
+		DELETE FROM campaign_players
+		WHERE campaign_id = $1 AND user_id = $2 AND status IN ('pending', 'waitlisted')
+	`
+
+	result, err := p.DB.ExecContext(ctx, query, campaignID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to reject player: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrJoinRequestNotFound
+	}
+
+	return nil
+}
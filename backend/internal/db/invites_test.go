@@ -0,0 +1,194 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+)
+
+var inviteCols = []string{
+	"id", "campaign_id", "code", "created_by", "expires_at", "max_uses", "uses",
+	"requires_approval", "allow_waitlist", "revoked", "created_at",
+}
+
+func expectInviteLookup(mock sqlmock.Sqlmock, expiresAt *time.Time, maxUses, uses int, approval, waitlist, revoked bool) {
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM campaign_invites WHERE code = \$1 FOR UPDATE`).WithArgs("WXYZ9876").
+		WillReturnRows(sqlmock.NewRows(inviteCols).
+			AddRow(3, 1, "WXYZ9876", 99, expiresAt, maxUses, uses, approval, waitlist, revoked, time.Now()))
+}
+
+func expectInviteCapacity(mock sqlmock.Sqlmock, playerCount int) {
+	mock.ExpectQuery(`SELECT c.dm_id, c.max_players`).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"dm_id", "max_players", "count"}).AddRow(99, 4, playerCount))
+	mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM campaign_players`).WithArgs(1, 7).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+}
+
+func TestRedeemCampaignInvite_Statuses(t *testing.T) {
+	tests := []struct {
+		name        string
+		approval    bool
+		waitlist    bool
+		playerCount int
+		wantStatus  string
+	}{
+		{"open seat joins immediately", false, false, 2, "active"},
+		{"approval creates pending row", true, false, 4, "pending"},
+		{"full campaign with waitlist", false, true, 4, "waitlisted"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pdb, mock, cleanup := newMockCampaignDB(t)
+			defer cleanup()
+
+			expectInviteLookup(mock, nil, 0, 0, tt.approval, tt.waitlist, false)
+			expectInviteCapacity(mock, tt.playerCount)
+			mock.ExpectExec(`INSERT INTO campaign_players`).WithArgs(1, 7, sqlmock.AnyArg(), tt.wantStatus).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			mock.ExpectExec(`UPDATE campaign_invites SET uses = uses \+ 1`).WithArgs(3).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+
+			result, err := pdb.RedeemCampaignInvite(context.Background(), "WXYZ9876", 7)
+			if err != nil {
+				t.Fatalf("expected redeem to succeed, got %v", err)
+			}
+			if result.Status != tt.wantStatus || result.CampaignID != 1 {
+				t.Fatalf("unexpected result: %+v", result)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatalf("sql expectations not met: %v", err)
+			}
+		})
+	}
+}
+
+func TestRedeemCampaignInvite_Rejections(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+
+	t.Run("expired", func(t *testing.T) {
+		pdb, mock, cleanup := newMockCampaignDB(t)
+		defer cleanup()
+
+		expectInviteLookup(mock, &past, 0, 0, false, false, false)
+		mock.ExpectRollback()
+
+		_, err := pdb.RedeemCampaignInvite(context.Background(), "WXYZ9876", 7)
+		if err == nil || err.Error() != "invite has expired" {
+			t.Fatalf("expected expiry error, got %v", err)
+		}
+	})
+
+	t.Run("usage limit", func(t *testing.T) {
+		pdb, mock, cleanup := newMockCampaignDB(t)
+		defer cleanup()
+
+		expectInviteLookup(mock, nil, 2, 2, false, false, false)
+		mock.ExpectRollback()
+
+		_, err := pdb.RedeemCampaignInvite(context.Background(), "WXYZ9876", 7)
+		if err == nil || err.Error() != "invite has reached its usage limit" {
+			t.Fatalf("expected usage limit error, got %v", err)
+		}
+	})
+
+	t.Run("revoked", func(t *testing.T) {
+		pdb, mock, cleanup := newMockCampaignDB(t)
+		defer cleanup()
+
+		expectInviteLookup(mock, nil, 0, 0, false, false, true)
+		mock.ExpectRollback()
+
+		_, err := pdb.RedeemCampaignInvite(context.Background(), "WXYZ9876", 7)
+		if err == nil || err.Error() != "invite has been revoked" {
+			t.Fatalf("expected revoked error, got %v", err)
+		}
+	})
+
+	t.Run("full without waitlist", func(t *testing.T) {
+		pdb, mock, cleanup := newMockCampaignDB(t)
+		defer cleanup()
+
+		expectInviteLookup(mock, nil, 0, 0, false, false, false)
+		expectInviteCapacity(mock, 4)
+		mock.ExpectRollback()
+
+		_, err := pdb.RedeemCampaignInvite(context.Background(), "WXYZ9876", 7)
+		if !errors.Is(err, ErrCampaignFull) {
+			t.Fatalf("expected ErrCampaignFull, got %v", err)
+		}
+	})
+
+	t.Run("unknown code", func(t *testing.T) {
+		pdb, mock, cleanup := newMockCampaignDB(t)
+		defer cleanup()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`FROM campaign_invites WHERE code = \$1`).WithArgs("WXYZ9876").
+			WillReturnRows(sqlmock.NewRows(inviteCols))
+		mock.ExpectRollback()
+
+		_, err := pdb.RedeemCampaignInvite(context.Background(), "WXYZ9876", 7)
+		if !errors.Is(err, ErrInvalidInviteCode) {
+			t.Fatalf("expected ErrInvalidInviteCode, got %v", err)
+		}
+	})
+}
+
+func TestApproveCampaignPlayer(t *testing.T) {
+	t.Run("activates waitlisted player", func(t *testing.T) {
+		pdb, mock, cleanup := newMockCampaignDB(t)
+		defer cleanup()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT status FROM campaign_players`).WithArgs(1, 7).
+			WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("waitlisted"))
+		mock.ExpectQuery(`SELECT c.max_players`).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"max_players", "count"}).AddRow(4, 3))
+		mock.ExpectExec(`UPDATE campaign_players SET status = 'active'`).WithArgs(sqlmock.AnyArg(), 1, 7).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		if err := pdb.ApproveCampaignPlayer(context.Background(), 1, 7); err != nil {
+			t.Fatalf("expected approval to succeed, got %v", err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sql expectations not met: %v", err)
+		}
+	})
+
+	t.Run("campaign full", func(t *testing.T) {
+		pdb, mock, cleanup := newMockCampaignDB(t)
+		defer cleanup()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT status FROM campaign_players`).WithArgs(1, 7).
+			WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("pending"))
+		mock.ExpectQuery(`SELECT c.max_players`).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"max_players", "count"}).AddRow(4, 4))
+		mock.ExpectRollback()
+
+		if err := pdb.ApproveCampaignPlayer(context.Background(), 1, 7); !errors.Is(err, ErrCampaignFull) {
+			t.Fatalf("expected ErrCampaignFull, got %v", err)
+		}
+	})
+
+	t.Run("already active", func(t *testing.T) {
+		pdb, mock, cleanup := newMockCampaignDB(t)
+		defer cleanup()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT status FROM campaign_players`).WithArgs(1, 7).
+			WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("active"))
+		mock.ExpectRollback()
+
+		if err := pdb.ApproveCampaignPlayer(context.Background(), 1, 7); !errors.Is(err, ErrJoinRequestNotFound) {
+			t.Fatalf("expected ErrJoinRequestNotFound, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,148 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"rpg-saas-backend/internal/models"
+)
+
+// LoadLevelUpRules resolve a progressão do próximo nível do PC na classe informada (uma classe
+// que o PC ainda não tem começa no nível 1). Classes do SRD usam dnd_classes.class_levels e as
+// características de dnd_features daquele nível; classes homebrew visíveis ao usuário usam
+// features indexado por nível. Se o PC for multiclasse depois da subida, carrega também as
+// regras de cada classe para os espaços de magia combinados. Retorna nil se a classe não for
+// encontrada em nenhuma das duas.
+func (p *PostgresDB) LoadLevelUpRules(ctx context.Context, pc *models.PC, className string, userID int) (*models.LevelUpRules, error) {
+	classes := pc.Classes()
+	level := 1
+	if entry := classes.Find(className); entry != nil {
+		level = entry.Level + 1
+	}
+
+	rules, classRules, err := p.loadClassLevelUpRules(ctx, className, level, userID)
+	if err != nil || rules == nil {
+		return rules, err
+	}
+
+	if classes.Find(className) == nil {
+		classes = append(classes, models.ClassLevel{Class: className})
+	}
+	if len(classes) > 1 {
+		rules.Classes = make([]models.ClassRules, len(classes))
+		for i, entry := range classes {
+			if strings.EqualFold(entry.Class, className) {
+				rules.Classes[i] = classRules
+				continue
+			}
+			if rules.Classes[i], err = p.loadClassRules(ctx, entry.Class, userID); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return rules, nil
+}
+
+func (p *PostgresDB) loadClassLevelUpRules(ctx context.Context, name string, level, userID int) (*models.LevelUpRules, models.ClassRules, error) {
+	var class models.DnDClass
+	err := p.DB.GetContext(ctx, &class, `
+		SELECT api_index, name, COALESCE(hit_die, 8) AS hit_die,
+		       COALESCE(class_levels, '[]'::jsonb) AS class_levels,
+		       COALESCE(proficiency_choices, '[]'::jsonb) AS proficiency_choices
+		FROM dnd_classes
+		WHERE api_index = LOWER($1) OR LOWER(name) = LOWER($1)
+		LIMIT 1
+	`, name)
+	if err == nil {
+		classRules := models.ClassRulesFromSRD(&class)
+		rules, err := p.loadSRDLevelUpRules(ctx, &class, level)
+		if err != nil {
+			return nil, classRules, err
+		}
+		rules.Index, rules.SkillOptions = class.APIIndex, classRules.SkillOptions
+		return rules, classRules, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ClassRules{}, fmt.Errorf("failed to fetch D&D class %q: %w", name, err)
+	}
+
+	var homebrew models.HomebrewClass
+	err = p.DB.GetContext(ctx, &homebrew, `
+		SELECT id, name, hit_die, saving_throws,
+		       COALESCE(features, '{}'::jsonb) AS features,
+		       COALESCE(skill_choices, '{}'::jsonb) AS skill_choices,
+		       COALESCE(spellcasting, '{}'::jsonb) AS spellcasting
+		FROM homebrew_classes
+		WHERE LOWER(name) = LOWER($1) AND (is_public = true OR user_id = $2)
+		ORDER BY (user_id = $2) DESC, id
+		LIMIT 1
+	`, name, userID)
+	if err == nil {
+		classRules := models.ClassRulesFromHomebrew(&homebrew)
+		progression, options, subclassLevel := models.HomebrewClassLevel(&homebrew, level)
+		return &models.LevelUpRules{
+			Class:           homebrew.Name,
+			Source:          models.RulesSourceHomebrew,
+			HitDie:          homebrew.HitDie,
+			Progression:     progression,
+			SubclassOptions: options,
+			SubclassLevel:   subclassLevel,
+			SkillOptions:    classRules.SkillOptions,
+		}, classRules, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ClassRules{}, fmt.Errorf("failed to fetch homebrew class %q: %w", name, err)
+	}
+
+	return nil, models.ClassRules{}, nil
+}
+
+func (p *PostgresDB) loadSRDLevelUpRules(ctx context.Context, class *models.DnDClass, level int) (*models.LevelUpRules, error) {
+	progression, _ := models.SRDClassLevel(class, level)
+
+	features := []models.DnDFeature{}
+	err := p.DB.SelectContext(ctx, &features, `
+		SELECT api_index, name, COALESCE(level, 0) AS level, COALESCE(subclass_name, '') AS subclass_name,
+		       COALESCE(description, '') AS description
+		FROM dnd_features
+		WHERE class_name = $1 AND level = $2
+		ORDER BY COALESCE(subclass_name, ''), api_index
+	`, class.Name, level)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s features for level %d: %w", class.Name, level, err)
+	}
+	progression.AddSRDFeatures(features)
+
+	// A subclasse é escolhida no primeiro nível que traz características de subclasse
+	subclasses := []struct {
+		Name  string `db:"subclass_name"`
+		Level int    `db:"level"`
+	}{}
+	err = p.DB.SelectContext(ctx, &subclasses, `
+		SELECT subclass_name, MIN(level) AS level
+		FROM dnd_features
+		WHERE class_name = $1 AND COALESCE(subclass_name, '') <> ''
+		GROUP BY subclass_name
+		ORDER BY subclass_name
+	`, class.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s subclasses: %w", class.Name, err)
+	}
+
+	rules := &models.LevelUpRules{
+		Class:       class.Name,
+		Source:      models.RulesSourceSRD,
+		HitDie:      class.HitDie,
+		Progression: progression,
+	}
+	for _, subclass := range subclasses {
+		rules.SubclassOptions = append(rules.SubclassOptions, subclass.Name)
+		if rules.SubclassLevel == 0 || subclass.Level < rules.SubclassLevel {
+			rules.SubclassLevel = subclass.Level
+		}
+	}
+	return rules, nil
+}
@@ -0,0 +1,52 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+
+	"rpg-saas-backend/internal/models"
+)
+
+func TestLoadLevelUpRules_Homebrew(t *testing.T) {
+	pdb, mock, cleanup := newMockPCDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery(`FROM dnd_classes`).WithArgs("Gunslinger").WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(`FROM homebrew_classes`).WithArgs("Gunslinger", 7).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "hit_die", "features", "spellcasting"}).
+			AddRow(4, "Gunslinger", 10,
+				[]byte(`{"3":[{"name":"Gunslinger Creed","subclass_options":["Deadeye","Trick Shot"]}],"4":[{"name":"Quick Draw"}]}`),
+				[]byte(`{}`)))
+
+	pc := &models.PC{Level: 3, Class: "Gunslinger"}
+	rules, err := pdb.LoadLevelUpRules(context.Background(), pc, "Gunslinger", 7)
+	if err != nil {
+		t.Fatalf("LoadLevelUpRules returned error: %v", err)
+	}
+	if rules == nil || rules.Source != models.RulesSourceHomebrew || rules.HitDie != 10 {
+		t.Fatalf("unexpected rules: %+v", rules)
+	}
+	if len(rules.Progression.Features) != 1 || rules.SubclassLevel != 3 {
+		t.Fatalf("unexpected progression: %+v", rules)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestLoadLevelUpRules_NotFound(t *testing.T) {
+	pdb, mock, cleanup := newMockPCDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery(`FROM dnd_classes`).WithArgs("Nobody").WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(`FROM homebrew_classes`).WithArgs("Nobody", 7).WillReturnError(sql.ErrNoRows)
+
+	rules, err := pdb.LoadLevelUpRules(context.Background(), &models.PC{Level: 1, Class: "Nobody"}, "Nobody", 7)
+	if err != nil || rules != nil {
+		t.Fatalf("expected nil rules, got %+v, %v", rules, err)
+	}
+}
@@ -0,0 +1,335 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"rpg-saas-backend/internal/models"
+)
+
+var (
+	// ErrTreasureUnavailable indica tesouro inexistente ou já atribuído a uma campanha
+	ErrTreasureUnavailable = errors.New("treasure not found or already assigned")
+	// ErrLootUnavailable indica item ou moedas insuficientes no inventário do grupo
+	ErrLootUnavailable = errors.New("not enough loot in the party inventory")
+	// ErrCharacterNotInCampaign indica personagem inexistente ou inativo na campanha
+	ErrCharacterNotInCampaign = errors.New("character not active in campaign")
+)
+
+const partyItemColumns = `
+	id, campaign_id, treasure_id, name, type, category, COALESCE(value, 0) AS value, rank, quantity, created_at
+`
+
+// GetPartyInventory retorna a bolsa de moedas e os itens ainda não distribuídos da campanha
+func (p *PostgresDB) GetPartyInventory(ctx context.Context, campaignID int) (*models.PartyInventory, error) {
+	inventory := &models.PartyInventory{CampaignID: campaignID, Coins: models.Coins{}, Items: []models.PartyInventoryItem{}}
+
+	err := p.DB.GetContext(ctx, &inventory.Coins, `SELECT coins FROM campaign_purses WHERE campaign_id = $1`, campaignID)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to fetch party purse: %w", err)
+	}
+
+	query := `SELECT ` + partyItemColumns + ` FROM campaign_inventory_items WHERE campaign_id = $1 AND quantity > 0 ORDER BY id`
+	if err := p.DB.SelectContext(ctx, &inventory.Items, query, campaignID); err != nil {
+		return nil, fmt.Errorf("failed to fetch party inventory: %w", err)
+	}
+
+	return inventory, nil
+}
+
+// GetLootLog lista o histórico de saque da campanha, do mais recente ao mais antigo
+func (p *PostgresDB) GetLootLog(ctx context.Context, campaignID int) ([]models.LootRecord, error) {
+	records := []models.LootRecord{}
+	query := `
+		SELECT id, campaign_id, action, treasure_id, character_id, character_name, item_name,
+			quantity, coins, performed_by, created_at
+		FROM campaign_loot_log
+		WHERE campaign_id = $1
+		ORDER BY created_at DESC, id DESC
+	`
+
+	if err := p.DB.SelectContext(ctx, &records, query, campaignID); err != nil {
+		return nil, fmt.Errorf("failed to fetch loot log: %w", err)
+	}
+
+	return records, nil
+}
+
+// AssignTreasureToCampaign move um tesouro gerado para o inventário do grupo: os itens de
+// todos os hoards viram itens do grupo e as moedas vão para a bolsa. Um tesouro só pode ser
+// atribuído uma vez.
+func (p *PostgresDB) AssignTreasureToCampaign(ctx context.Context, campaignID, treasureID, performedBy int) (*models.AssignTreasureResult, error) {
+	tx, err := p.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var treasureName string
+	err = tx.QueryRowContext(ctx, `
+		UPDATE treasures SET campaign_id = $1
+		WHERE id = $2 AND campaign_id IS NULL
+		RETURNING name
+	`, campaignID, treasureID).Scan(&treasureName)
+	if err == sql.ErrNoRows {
+		return nil, ErrTreasureUnavailable
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to assign treasure %d: %w", treasureID, err)
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO campaign_inventory_items (campaign_id, treasure_id, name, type, category, value, rank, quantity)
+		SELECT $1, h.treasure_id, i.name, i.type, COALESCE(i.category, ''), COALESCE(i.value, 0), COALESCE(i.rank, ''), 1
+		FROM items i
+		JOIN hoards h ON h.id = i.hoard_id
+		WHERE h.treasure_id = $2
+		ORDER BY i.id
+	`, campaignID, treasureID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add treasure items to party inventory: %w", err)
+	}
+	itemsAdded, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	hoardCoins := []models.JSONB{}
+	if err := tx.SelectContext(ctx, &hoardCoins, `SELECT COALESCE(coins, '{}') FROM hoards WHERE treasure_id = $1`, treasureID); err != nil {
+		return nil, fmt.Errorf("failed to fetch treasure coins: %w", err)
+	}
+	coinsAdded := models.Coins{}
+	for _, coins := range hoardCoins {
+		coinsAdded.Add(models.CoinsFromJSONB(coins))
+	}
+
+	if !coinsAdded.IsZero() {
+		purse, err := lockPurseTx(ctx, tx, campaignID)
+		if err != nil {
+			return nil, err
+		}
+		purse.Add(coinsAdded)
+		if err := savePurseTx(ctx, tx, campaignID, purse); err != nil {
+			return nil, err
+		}
+	}
+
+	err = insertLootRecordTx(ctx, tx, &models.LootRecord{
+		CampaignID:  campaignID,
+		Action:      models.LootActionAssigned,
+		TreasureID:  &treasureID,
+		ItemName:    treasureName,
+		Quantity:    int(itemsAdded),
+		Coins:       coinsAdded,
+		PerformedBy: &performedBy,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit treasure assignment: %w", err)
+	}
+
+	return &models.AssignTreasureResult{TreasureID: treasureID, ItemsAdded: int(itemsAdded), CoinsAdded: coinsAdded}, nil
+}
+
+// DistributeLoot entrega itens e moedas do inventário do grupo aos personagens, atualiza o
+// equipamento de cada um e registra o histórico. No modo even a divisão é calculada aqui,
+// dentro da transação, a partir do inventário travado. Tudo ou nada.
+func (p *PostgresDB) DistributeLoot(ctx context.Context, campaignID, performedBy int, req models.DistributeLootRequest) ([]models.LootRecord, error) {
+	tx, err := p.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	purse, err := lockPurseTx(ctx, tx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	allocations := req.Allocations
+	if req.Mode == models.LootModeEven {
+		items := []models.PartyInventoryItem{}
+		if len(req.ItemIDs) > 0 {
+			ids := make(pq.Int64Array, len(req.ItemIDs))
+			for i, id := range req.ItemIDs {
+				ids[i] = int64(id)
+			}
+			query := `SELECT ` + partyItemColumns + ` FROM campaign_inventory_items
+				WHERE campaign_id = $1 AND id = ANY($2) AND quantity > 0 ORDER BY id FOR UPDATE`
+			if err := tx.SelectContext(ctx, &items, query, campaignID, ids); err != nil {
+				return nil, fmt.Errorf("failed to fetch party items: %w", err)
+			}
+			if len(items) != len(req.ItemIDs) {
+				return nil, fmt.Errorf("%w: some items are not in the party inventory", ErrLootUnavailable)
+			}
+		}
+
+		coins := models.Coins{}
+		if req.SplitCoins {
+			coins = purse
+		}
+		allocations = models.PlanEvenSplit(req.CharacterIDs, items, coins)
+	}
+
+	records := []models.LootRecord{}
+	for _, allocation := range allocations {
+		allocationRecords, err := p.giveLootTx(ctx, tx, campaignID, performedBy, allocation, purse)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, allocationRecords...)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM campaign_inventory_items WHERE campaign_id = $1 AND quantity = 0`, campaignID); err != nil {
+		return nil, fmt.Errorf("failed to clean party inventory: %w", err)
+	}
+	if err := savePurseTx(ctx, tx, campaignID, purse); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit loot distribution: %w", err)
+	}
+
+	return records, nil
+}
+
+// giveLootTx entrega uma alocação a um personagem, descontando do inventário e da bolsa
+func (p *PostgresDB) giveLootTx(ctx context.Context, tx *sqlx.Tx, campaignID, performedBy int, allocation models.LootAllocation, purse models.Coins) ([]models.LootRecord, error) {
+	var character struct {
+		Name      string               `db:"name"`
+		Equipment models.JSONBFlexible `db:"equipment"`
+	}
+	err := tx.GetContext(ctx, &character, `
+		SELECT name, equipment FROM campaign_characters
+		WHERE id = $1 AND campaign_id = $2 AND status = 'active'
+		FOR UPDATE
+	`, allocation.CharacterID, campaignID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: %d", ErrCharacterNotInCampaign, allocation.CharacterID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch character %d: %w", allocation.CharacterID, err)
+	}
+
+	records := []models.LootRecord{}
+	newRecord := func() models.LootRecord {
+		return models.LootRecord{
+			CampaignID:    campaignID,
+			Action:        models.LootActionDistributed,
+			CharacterID:   &allocation.CharacterID,
+			CharacterName: character.Name,
+			Coins:         models.Coins{},
+			PerformedBy:   &performedBy,
+		}
+	}
+
+	for _, given := range allocation.Items {
+		var item models.PartyInventoryItem
+		err := tx.GetContext(ctx, &item, `
+			UPDATE campaign_inventory_items SET quantity = quantity - $1
+			WHERE id = $2 AND campaign_id = $3 AND quantity >= $1
+			RETURNING `+partyItemColumns, given.Quantity, given.ItemID, campaignID)
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: item %d", ErrLootUnavailable, given.ItemID)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to take item %d from party inventory: %w", given.ItemID, err)
+		}
+
+		character.Equipment = models.AddToEquipment(character.Equipment, item.Name, given.Quantity)
+
+		record := newRecord()
+		record.TreasureID = item.TreasureID
+		record.ItemName = item.Name
+		record.Quantity = given.Quantity
+		records = append(records, record)
+	}
+
+	if !allocation.Coins.IsZero() {
+		if err := purse.Subtract(allocation.Coins); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrLootUnavailable, err)
+		}
+		character.Equipment = models.AddCoinsToEquipment(character.Equipment, allocation.Coins)
+
+		record := newRecord()
+		record.Coins = allocation.Coins
+		records = append(records, record)
+	}
+
+	if len(records) == 0 {
+		return records, nil
+	}
+
+	if err := recordBaselineVersionTx(ctx, tx, models.CharacterTypeCampaign, allocation.CharacterID); err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE campaign_characters SET equipment = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`, character.Equipment, allocation.CharacterID); err != nil {
+		return nil, fmt.Errorf("failed to update equipment of character %d: %w", allocation.CharacterID, err)
+	}
+
+	if _, err := recordCampaignCharacterVersionTx(ctx, tx, allocation.CharacterID, &performedBy, models.VersionActionLoot); err != nil {
+		return nil, err
+	}
+
+	for i := range records {
+		if err := insertLootRecordTx(ctx, tx, &records[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return records, nil
+}
+
+// lockPurseTx trava e retorna a bolsa da campanha. A linha é criada vazia antes do lock para
+// que duas transações na primeira movimentação não sobrescrevam uma à outra.
+func lockPurseTx(ctx context.Context, tx *sqlx.Tx, campaignID int) (models.Coins, error) {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO campaign_purses (campaign_id) VALUES ($1)
+		ON CONFLICT (campaign_id) DO NOTHING
+	`, campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create party purse: %w", err)
+	}
+
+	coins := models.Coins{}
+	err = tx.GetContext(ctx, &coins, `SELECT coins FROM campaign_purses WHERE campaign_id = $1 FOR UPDATE`, campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock party purse: %w", err)
+	}
+	return coins, nil
+}
+
+// savePurseTx grava a bolsa já travada por lockPurseTx
+func savePurseTx(ctx context.Context, tx *sqlx.Tx, campaignID int, coins models.Coins) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE campaign_purses SET coins = $2, updated_at = NOW() WHERE campaign_id = $1
+	`, campaignID, coins)
+	if err != nil {
+		return fmt.Errorf("failed to save party purse: %w", err)
+	}
+	return nil
+}
+
+func insertLootRecordTx(ctx context.Context, tx *sqlx.Tx, record *models.LootRecord) error {
+	err := tx.QueryRowContext(ctx, `
+		INSERT INTO campaign_loot_log
+			(campaign_id, action, treasure_id, character_id, character_name, item_name, quantity, coins, performed_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at
+	`, record.CampaignID, record.Action, record.TreasureID, record.CharacterID, record.CharacterName,
+		record.ItemName, record.Quantity, record.Coins, record.PerformedBy,
+	).Scan(&record.ID, &record.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record loot: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+
+	"rpg-saas-backend/internal/models"
+)
+
+var partyItemCols = []string{"id", "campaign_id", "treasure_id", "name", "type", "category", "value", "rank", "quantity", "created_at"}
+
+// expectPurseCreated espera a criação da bolsa vazia que precede o lock
+func expectPurseCreated(mock sqlmock.Sqlmock, campaignID int) {
+	mock.ExpectExec(`INSERT INTO campaign_purses \(campaign_id\) VALUES \(\$1\)\s+ON CONFLICT \(campaign_id\) DO NOTHING`).
+		WithArgs(campaignID).WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+func TestAssignTreasureToCampaign_MovesItemsAndCoins(t *testing.T) {
+	pdb, mock, cleanup := newMockCampaignDB(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE treasures SET campaign_id = \$1`).WithArgs(10, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("Dragon Hoard"))
+	mock.ExpectExec(`INSERT INTO campaign_inventory_items`).WithArgs(10, 5).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectQuery(`SELECT COALESCE\(coins, '\{\}'\) FROM hoards`).WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"coins"}).AddRow([]byte(`{"gp":100}`)).AddRow([]byte(`{"gp":20,"sp":5}`)))
+	expectPurseCreated(mock, 10)
+	mock.ExpectQuery(`SELECT coins FROM campaign_purses WHERE campaign_id = \$1 FOR UPDATE`).WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"coins"}).AddRow([]byte(`{"gp":3}`)))
+	mock.ExpectExec(`UPDATE campaign_purses SET coins`).WithArgs(10, `{"gp":123,"sp":5}`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`INSERT INTO campaign_loot_log`).
+		WithArgs(10, models.LootActionAssigned, 5, nil, "", "Dragon Hoard", 2, `{"gp":120,"sp":5}`, 7).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(1, time.Now()))
+	mock.ExpectCommit()
+
+	result, err := pdb.AssignTreasureToCampaign(context.Background(), 10, 5, 7)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ItemsAdded != 2 || result.CoinsAdded["gp"] != 120 || result.CoinsAdded["sp"] != 5 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestAssignTreasureToCampaign_AlreadyAssigned(t *testing.T) {
+	pdb, mock, cleanup := newMockCampaignDB(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE treasures SET campaign_id`).WithArgs(10, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"name"}))
+	mock.ExpectRollback()
+
+	_, err := pdb.AssignTreasureToCampaign(context.Background(), 10, 5, 7)
+	if !errors.Is(err, ErrTreasureUnavailable) {
+		t.Fatalf("expected ErrTreasureUnavailable, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestDistributeLoot_ManualAllocation(t *testing.T) {
+	pdb, mock, cleanup := newMockCampaignDB(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	expectPurseCreated(mock, 10)
+	mock.ExpectQuery(`SELECT coins FROM campaign_purses`).WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"coins"}).AddRow([]byte(`{"gp":50}`)))
+	mock.ExpectQuery(`SELECT name, equipment FROM campaign_characters`).WithArgs(3, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "equipment"}).AddRow("Aria", []byte(`[]`)))
+	mock.ExpectQuery(`UPDATE campaign_inventory_items SET quantity = quantity - \$1`).WithArgs(2, 11, 10).
+		WillReturnRows(sqlmock.NewRows(partyItemCols).AddRow(11, 10, 5, "Potion of Healing", "potion", "", 50.0, "", 1, time.Now()))
+	expectVersionHistory(mock, models.CharacterTypeCampaign, 3)
+	mock.ExpectExec(`UPDATE campaign_characters SET equipment = \$1, updated_at = CURRENT_TIMESTAMP WHERE id = \$2`).
+		WithArgs(`[{"name":"Potion of Healing","quantity":2},{"name":"Gold Pieces","quantity":20}]`, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectCampaignCharacterVersion(mock, 3, 10, models.VersionActionLoot)
+	mock.ExpectQuery(`INSERT INTO campaign_loot_log`).
+		WithArgs(10, models.LootActionDistributed, 5, 3, "Aria", "Potion of Healing", 2, `{}`, 7).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(1, time.Now()))
+	mock.ExpectQuery(`INSERT INTO campaign_loot_log`).
+		WithArgs(10, models.LootActionDistributed, nil, 3, "Aria", "", 0, `{"gp":20}`, 7).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(2, time.Now()))
+	mock.ExpectExec(`DELETE FROM campaign_inventory_items WHERE campaign_id = \$1 AND quantity = 0`).WithArgs(10).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`UPDATE campaign_purses SET coins`).WithArgs(10, `{"gp":30}`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	records, err := pdb.DistributeLoot(context.Background(), 10, 7, models.DistributeLootRequest{
+		Mode: models.LootModeManual,
+		Allocations: []models.LootAllocation{{
+			CharacterID: 3,
+			Items:       []models.LootItemAllocation{{ItemID: 11, Quantity: 2}},
+			Coins:       models.Coins{"gp": 20},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected 2 loot records, got %d", len(records))
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
+
+func TestDistributeLoot_NotEnoughQuantity(t *testing.T) {
+	pdb, mock, cleanup := newMockCampaignDB(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	expectPurseCreated(mock, 10)
+	mock.ExpectQuery(`SELECT coins FROM campaign_purses`).WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"coins"}).AddRow([]byte(`{}`)))
+	mock.ExpectQuery(`SELECT name, equipment FROM campaign_characters`).WithArgs(3, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "equipment"}).AddRow("Aria", []byte(`[]`)))
+	mock.ExpectQuery(`UPDATE campaign_inventory_items SET quantity`).WithArgs(5, 11, 10).
+		WillReturnRows(sqlmock.NewRows(partyItemCols))
+	mock.ExpectRollback()
+
+	_, err := pdb.DistributeLoot(context.Background(), 10, 7, models.DistributeLootRequest{
+		Mode: models.LootModeManual,
+		Allocations: []models.LootAllocation{{
+			CharacterID: 3,
+			Items:       []models.LootItemAllocation{{ItemID: 11, Quantity: 5}},
+		}},
+	})
+	if !errors.Is(err, ErrLootUnavailable) {
+		t.Fatalf("expected ErrLootUnavailable, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations not met: %v", err)
+	}
+}
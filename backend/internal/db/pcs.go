@@ -6,6 +6,8 @@ import (
 	"log"
 	"time"
 
+	"github.com/jmoiron/sqlx"
+
 	"rpg-saas-backend/internal/models"
 )
 
@@ -15,7 +17,7 @@ func (p *PostgresDB) GetPCsByPlayer(ctx context.Context, playerID int, limit, of
 	log.Printf("Fetching PCs for player ID: %d with limit: %d and offset: %d", playerID, limit, offset)
 
 	query := `
-		SELECT id, name, description, level, race, class, background, alignment,
+		SELECT id, name, description, level, race, class, class_levels, background, alignment,
 		       attributes, abilities, equipment, hp, current_hp, ca, proficiency_bonus,
 		       inspiration, skills, attacks, spells, personality_traits, ideals, bonds,
 		       flaws, features, player_name, player_id, is_homebrew, is_unique, created_at
@@ -39,7 +41,7 @@ func (p *PostgresDB) GetPCsByPlayer(ctx context.Context, playerID int, limit, of
 func (p *PostgresDB) GetPCByIDAndPlayer(ctx context.Context, id, playerID int) (*models.PC, error) {
 	var pc models.PC
 	query := `
-		SELECT id, name, description, level, race, class, background, alignment,
+		SELECT id, name, description, level, race, class, class_levels, background, alignment,
 		       attributes, abilities, equipment, hp, current_hp, ca, proficiency_bonus,
 		       inspiration, skills, attacks, spells, personality_traits, ideals, bonds,
 		       flaws, features, player_name, player_id, is_homebrew, is_unique, created_at
@@ -55,48 +57,99 @@ func (p *PostgresDB) GetPCByIDAndPlayer(ctx context.Context, id, playerID int) (
 	return &pc, nil
 }
 
-// CreatePC cria um novo PC para um jogador
-func (p *PostgresDB) CreatePC(ctx context.Context, pc *models.PC) error {
+// CreatePC cria um novo PC para um jogador e registra a primeira versão na mesma transação
+func (p *PostgresDB) CreatePC(ctx context.Context, pc *models.PC, authorID int) error {
+	tx, err := p.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := insertPC(ctx, tx, pc); err != nil {
+		return err
+	}
+
+	if _, err := recordPCVersionTx(ctx, tx, pc.ID, &authorID, models.VersionActionCreate); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit PC creation: %w", err)
+	}
+	return nil
+}
+
+// insertPC grava o PC com o executor informado (conexão ou transação)
+func insertPC(ctx context.Context, q sqlx.QueryerContext, pc *models.PC) error {
 	query := `
 		INSERT INTO pcs
-		(name, description, level, race, class, background, alignment, attributes, abilities, equipment, hp, ca, player_name, player_id, is_homebrew, is_unique, created_at)
+		(name, description, level, race, class, background, alignment, attributes, abilities, equipment, hp, ca, proficiency_bonus, player_name, player_id, is_homebrew, is_unique, created_at, class_levels,
+		 skills, attacks, spells, features)
 		VALUES
-		($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+		($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19,
+		 COALESCE($20, '{}'::jsonb), COALESCE($21, '[]'::jsonb), COALESCE($22, '{"spell_slots": {}, "known_spells": []}'::jsonb), COALESCE($23, '{}'::text[]))
 		RETURNING id
 	`
 
 	now := time.Now()
 	pc.CreatedAt = now
 
-	row := p.DB.QueryRowContext(ctx, query,
+	row := q.QueryRowxContext(ctx, query,
 		pc.Name, pc.Description, pc.Level, pc.Race, pc.Class, pc.Background, pc.Alignment,
-		pc.Attributes, pc.Abilities, pc.Equipment, pc.HP, pc.CA, pc.PlayerName, pc.PlayerID, pc.IsHomebrew, pc.IsUnique, pc.CreatedAt,
+		pc.Attributes, pc.Abilities, pc.Equipment, pc.HP, pc.CA, pc.ProficiencyBonus, pc.PlayerName, pc.PlayerID, pc.IsHomebrew, pc.IsUnique, pc.CreatedAt,
+		pc.ClassLevels, pc.Skills, pc.Attacks, pc.Spells, pc.Features,
 	)
 
 	return row.Scan(&pc.ID)
 }
 
-// UpdatePC atualiza um PC existente (apenas se pertence ao jogador)
-func (p *PostgresDB) UpdatePC(ctx context.Context, pc *models.PC) error {
+// UpdatePC atualiza um PC existente (apenas se pertence ao jogador) e registra a versão com a
+// ação informada na mesma transação
+func (p *PostgresDB) UpdatePC(ctx context.Context, pc *models.PC, authorID int, action string) error {
+	tx, err := p.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := recordBaselineVersionTx(ctx, tx, models.CharacterTypePC, pc.ID); err != nil {
+		return err
+	}
+	if err := updatePCTx(ctx, tx, pc); err != nil {
+		return err
+	}
+
+	if _, err := recordPCVersionTx(ctx, tx, pc.ID, &authorID, action); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit PC update: %w", err)
+	}
+	return nil
+}
+
+// updatePCTx atualiza o PC pelo executor informado (banco ou transação)
+func updatePCTx(ctx context.Context, q sqlx.ExecerContext, pc *models.PC) error {
 	query := `
 		UPDATE pcs SET
 		name = $1, description = $2, level = $3, race = $4, class = $5, background = $6, alignment = $7,
 		attributes = $8, abilities = $9, equipment = $10, hp = $11, current_hp = $12, ca = $13,
 		proficiency_bonus = $14, inspiration = $15, skills = $16, attacks = $17, spells = $18,
 		personality_traits = $19, ideals = $20, bonds = $21, flaws = $22, features = $23, player_name = $24,
-		is_homebrew = $25, is_unique = $26
-		WHERE id = $27 AND player_id = $28
+		is_homebrew = $25, is_unique = $26, class_levels = $27
+		WHERE id = $28 AND player_id = $29
 	`
 
 	log.Printf("Executando UPDATE para PC ID: %d", pc.ID)
 	log.Printf("Spells being saved: %+v", pc.Spells)
 
-	result, err := p.DB.ExecContext(ctx, query,
+	result, err := q.ExecContext(ctx, query,
 		pc.Name, pc.Description, pc.Level, pc.Race, pc.Class, pc.Background, pc.Alignment,
 		pc.Attributes, pc.Abilities, pc.Equipment, pc.HP, pc.CurrentHP, pc.CA,
 		pc.ProficiencyBonus, pc.Inspiration, pc.Skills, pc.Attacks, pc.Spells,
 		pc.PersonalityTraits, pc.Ideals, pc.Bonds, pc.Flaws, pc.Features, pc.PlayerName,
-		pc.IsHomebrew, pc.IsUnique,
+		pc.IsHomebrew, pc.IsUnique, pc.ClassLevels,
 		pc.ID, pc.PlayerID,
 	)
 
@@ -123,7 +176,7 @@ func (p *PostgresDB) DeletePC(ctx context.Context, id, playerID int) error {
 	checkQuery := `
 		SELECT COUNT(*) 
 		FROM campaign_characters 
-		WHERE source_pc_id = $1 AND status IN ('active', 'inactive')
+		WHERE source_pc_id = $1 AND status IN ('active', 'inactive', 'unconscious', 'dead', 'resurrected')
 	`
 	err := p.DB.GetContext(ctx, &campaignCount, checkQuery, id)
 	if err != nil {
@@ -211,7 +264,7 @@ func (p *PostgresDB) CheckUniquePCInCampaign(ctx context.Context, pcID int) (boo
 		JOIN pcs p ON p.id = cc.source_pc_id
 		WHERE cc.source_pc_id = $1
 		  AND p.is_unique = true
-		  AND cc.status IN ('active', 'inactive')
+		  AND cc.status IN ('active', 'inactive', 'unconscious', 'dead', 'resurrected')
 		LIMIT 1
 	`
 
@@ -232,7 +285,7 @@ func (p *PostgresDB) CountPCCampaigns(ctx context.Context, pcID int) (int, error
 	query := `
 		SELECT COUNT(*)
 		FROM campaign_characters
-		WHERE source_pc_id = $1 AND status IN ('active', 'inactive')
+		WHERE source_pc_id = $1 AND status IN ('active', 'inactive', 'unconscious', 'dead', 'resurrected')
 	`
 
 	err := p.DB.GetContext(ctx, &count, query, pcID)
@@ -89,10 +89,14 @@ func TestCreatePC(t *testing.T) {
 	pdb, mock, cleanup := newMockPCDB(t)
 	defer cleanup()
 
+	mock.ExpectBegin()
 	mock.ExpectQuery(`INSERT INTO pcs`).
 		WithArgs("NewPC", "desc", 1, "dwarf", "cleric", "acolyte", "good",
-			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), 28, 15, "Player", 7, false, false, sqlmock.AnyArg()).
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), 28, 15, 0, "Player", 7, false, false, sqlmock.AnyArg(), "[]",
+			nil, nil, nil, nil).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+	expectPCVersion(mock, 10, models.VersionActionCreate)
+	mock.ExpectCommit()
 
 	pc := &models.PC{
 		Name:        "NewPC",
@@ -108,7 +112,7 @@ func TestCreatePC(t *testing.T) {
 		PlayerID:    7,
 	}
 
-	err := pdb.CreatePC(context.Background(), pc)
+	err := pdb.CreatePC(context.Background(), pc, 7)
 	if err != nil {
 		t.Fatalf("CreatePC error: %v", err)
 	}
@@ -125,12 +129,16 @@ func TestUpdatePC(t *testing.T) {
 	pdb, mock, cleanup := newMockPCDB(t)
 	defer cleanup()
 
+	mock.ExpectBegin()
+	expectVersionHistory(mock, models.CharacterTypePC, 10)
 	mock.ExpectExec(`UPDATE pcs SET`).
 		WithArgs("UpdatedPC", "new desc", 5, "elf", "wizard", "sage", "neutral",
 			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), 35, 30, 14, 3,
 			false, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
-			"traits", "ideals", "bonds", "flaws", sqlmock.AnyArg(), "Player", false, false, 10, 7).
+			"traits", "ideals", "bonds", "flaws", sqlmock.AnyArg(), "Player", false, false, "[]", 10, 7).
 		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectPCVersion(mock, 10, models.VersionActionUpdate)
+	mock.ExpectCommit()
 
 	pc := &models.PC{
 		ID:                 10,
@@ -154,7 +162,7 @@ func TestUpdatePC(t *testing.T) {
 		PlayerID:           7,
 	}
 
-	err := pdb.UpdatePC(context.Background(), pc)
+	err := pdb.UpdatePC(context.Background(), pc, 7, models.VersionActionUpdate)
 	if err != nil {
 		t.Fatalf("UpdatePC error: %v", err)
 	}
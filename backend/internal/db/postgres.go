@@ -51,9 +51,10 @@ func (p *PostgresDB) Close() error {
 // NPC OPERATIONS
 // ========================================
 
+// GetNPCs lista os NPCs globais; os de campanha são listados por GetCampaignNPCs
 func (p *PostgresDB) GetNPCs(ctx context.Context, limit, offset int) ([]models.NPC, error) {
 	npcs := []models.NPC{}
-	query := `SELECT * FROM npcs ORDER BY id LIMIT $1 OFFSET $2`
+	query := `SELECT * FROM npcs WHERE campaign_id IS NULL ORDER BY id LIMIT $1 OFFSET $2`
 
 	err := p.DB.SelectContext(ctx, &npcs, query, limit, offset)
 	if err != nil {
@@ -78,26 +79,28 @@ func (p *PostgresDB) GetNPCByID(ctx context.Context, id int) (*models.NPC, error
 func (p *PostgresDB) CreateNPC(ctx context.Context, npc *models.NPC) error {
 	query := `
 		INSERT INTO npcs 
-		(name, description, level, race, class, attributes, abilities, equipment, hp, ca, campaign_id) 
+		(name, description, level, race, class, attributes, abilities, equipment, hp, ca, campaign_id, revealed) 
 		VALUES 
-		($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 		RETURNING id, created_at
 	`
 
 	row := p.DB.QueryRowContext(ctx, query,
 		npc.Name, npc.Description, npc.Level, npc.Race, npc.Class,
-		npc.Attributes, npc.Abilities, npc.Equipment, npc.HP, npc.CA, nil, // campaign_id pode ser nil
+		npc.Attributes, npc.Abilities, npc.Equipment, npc.HP, npc.CA,
+		npc.CampaignID, npc.Revealed, // campaign_id pode ser nil
 	)
 
 	return row.Scan(&npc.ID, &npc.CreatedAt)
 }
 
+// UpdateNPC e DeleteNPC só alteram NPCs globais; NPCs de campanha passam pelas rotas da campanha
 func (p *PostgresDB) UpdateNPC(ctx context.Context, npc *models.NPC) error {
 	query := `
 		UPDATE npcs SET
 		name = $1, description = $2, level = $3, race = $4, class = $5,
 		attributes = $6, abilities = $7, equipment = $8, hp = $9, ca = $10
-		WHERE id = $11
+		WHERE id = $11 AND campaign_id IS NULL
 	`
 
 	_, err := p.DB.ExecContext(ctx, query,
@@ -113,7 +116,7 @@ func (p *PostgresDB) UpdateNPC(ctx context.Context, npc *models.NPC) error {
 }
 
 func (p *PostgresDB) DeleteNPC(ctx context.Context, id int) error {
-	query := `DELETE FROM npcs WHERE id = $1`
+	query := `DELETE FROM npcs WHERE id = $1 AND campaign_id IS NULL`
 
 	_, err := p.DB.ExecContext(ctx, query, id)
 	if err != nil {
@@ -127,9 +130,10 @@ func (p *PostgresDB) DeleteNPC(ctx context.Context, id int) error {
 // ENCOUNTER OPERATIONS
 // ========================================
 
+// GetEncounters lista os encontros globais; os de campanha são listados por GetCampaignEncounters
 func (p *PostgresDB) GetEncounters(ctx context.Context, limit, offset int) ([]models.Encounter, error) {
 	encounters := []models.Encounter{}
-	query := `SELECT * FROM encounters ORDER BY id LIMIT $1 OFFSET $2`
+	query := `SELECT * FROM encounters WHERE campaign_id IS NULL ORDER BY id LIMIT $1 OFFSET $2`
 
 	err := p.DB.SelectContext(ctx, &encounters, query, limit, offset)
 	if err != nil {
@@ -186,15 +190,16 @@ func (p *PostgresDB) CreateEncounter(ctx context.Context, encounter *models.Enco
 
 	query := `
 		INSERT INTO encounters 
-		(theme, difficulty, total_xp, player_level, player_count, campaign_id) 
+		(theme, difficulty, total_xp, player_level, player_count, campaign_id, revealed) 
 		VALUES 
-		($1, $2, $3, $4, $5, $6)
+		($1, $2, $3, $4, $5, $6, $7)
 		RETURNING id, created_at
 	`
 
 	row := tx.QueryRowContext(ctx, query,
 		encounter.Theme, encounter.Difficulty, encounter.TotalXP,
-		encounter.PlayerLevel, encounter.PlayerCount, nil, // campaign_id pode ser nil
+		encounter.PlayerLevel, encounter.PlayerCount,
+		encounter.CampaignID, encounter.Revealed, // campaign_id pode ser nil
 	)
 
 	err = row.Scan(&encounter.ID, &encounter.CreatedAt)
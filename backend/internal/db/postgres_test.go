@@ -84,7 +84,7 @@ func TestCreateUpdateDeleteNPC(t *testing.T) {
 	mock.ExpectQuery(`INSERT INTO npcs`).
 		WithArgs(
 			npc.Name, npc.Description, npc.Level, npc.Race, npc.Class,
-			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), npc.HP, npc.CA, nil,
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), npc.HP, npc.CA, nil, false,
 		).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(5, time.Now()))
 
@@ -177,7 +177,7 @@ func TestCreateEncounter(t *testing.T) {
 
 	mock.ExpectBegin()
 	mock.ExpectQuery(`INSERT INTO encounters`).
-		WithArgs(encounter.Theme, encounter.Difficulty, encounter.TotalXP, encounter.PlayerLevel, encounter.PlayerCount, nil).
+		WithArgs(encounter.Theme, encounter.Difficulty, encounter.TotalXP, encounter.PlayerLevel, encounter.PlayerCount, nil, false).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(10, time.Now()))
 	mock.ExpectQuery(`INSERT INTO encounter_monsters`).
 		WithArgs(10, "Orc", 100, 0.5).
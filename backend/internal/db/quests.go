@@ -0,0 +1,268 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"rpg-saas-backend/internal/models"
+)
+
+const questSelect = `
+	SELECT q.id, q.campaign_id, q.title, COALESCE(q.description, '') AS description,
+	       q.giver_npc_id, COALESCE(n.name, '') AS giver_npc_name, COALESCE(q.giver_name, '') AS giver_name,
+	       q.status, q.revealed, COALESCE(q.rewards, '{}') AS rewards, q.completed_at, q.created_at, q.updated_at
+	FROM quests q
+	LEFT JOIN npcs n ON q.giver_npc_id = n.id
+`
+
+const questObjectiveColumns = `id, quest_id, description, completed, sort_order, completed_at, created_at`
+
+// GetCampaignQuests lista as quests da campanha (revealedOnly filtra para a visão dos players)
+func (p *PostgresDB) GetCampaignQuests(ctx context.Context, campaignID int, revealedOnly bool, status string) ([]models.Quest, error) {
+	quests := []models.Quest{}
+	query := questSelect + ` WHERE q.campaign_id = $1`
+	args := []interface{}{campaignID}
+
+	if revealedOnly {
+		query += ` AND q.revealed = TRUE`
+	}
+
+	if status != "" {
+		args = append(args, status)
+		query += fmt.Sprintf(` AND q.status = $%d`, len(args))
+	}
+
+	query += ` ORDER BY CASE q.status WHEN 'active' THEN 0 ELSE 1 END, q.updated_at DESC`
+
+	if err := p.DB.SelectContext(ctx, &quests, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to fetch quests for campaign %d: %w", campaignID, err)
+	}
+
+	return quests, nil
+}
+
+// GetQuest retorna uma quest da campanha (nil, nil se não existir)
+func (p *PostgresDB) GetQuest(ctx context.Context, id, campaignID int) (*models.Quest, error) {
+	var quest models.Quest
+	query := questSelect + ` WHERE q.id = $1 AND q.campaign_id = $2`
+
+	if err := p.DB.GetContext(ctx, &quest, query, id, campaignID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch quest %d: %w", id, err)
+	}
+
+	return &quest, nil
+}
+
+// GetQuestObjectives lista os objetivos das quests informadas, em ordem
+func (p *PostgresDB) GetQuestObjectives(ctx context.Context, questIDs ...int) ([]models.QuestObjective, error) {
+	objectives := []models.QuestObjective{}
+	if len(questIDs) == 0 {
+		return objectives, nil
+	}
+
+	query := `SELECT ` + questObjectiveColumns + `
+		FROM quest_objectives
+		WHERE quest_id = ANY($1)
+		ORDER BY quest_id, sort_order, id
+	`
+
+	if err := p.DB.SelectContext(ctx, &objectives, query, pq.Array(questIDs)); err != nil {
+		return nil, fmt.Errorf("failed to fetch quest objectives: %w", err)
+	}
+
+	return objectives, nil
+}
+
+// GetQuestObjective retorna um objetivo da quest (nil, nil se não existir)
+func (p *PostgresDB) GetQuestObjective(ctx context.Context, id, questID int) (*models.QuestObjective, error) {
+	var objective models.QuestObjective
+	query := `SELECT ` + questObjectiveColumns + ` FROM quest_objectives WHERE id = $1 AND quest_id = $2`
+
+	if err := p.DB.GetContext(ctx, &objective, query, id, questID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch quest objective %d: %w", id, err)
+	}
+
+	return &objective, nil
+}
+
+// CreateQuest cria a quest e seus objetivos iniciais
+func (p *PostgresDB) CreateQuest(ctx context.Context, quest *models.Quest) error {
+	tx, err := p.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	quest.CreatedAt = now
+	quest.UpdatedAt = now
+	if quest.Status == "" {
+		quest.Status = models.QuestStatusActive
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO quests (campaign_id, title, description, giver_npc_id, giver_name, status, revealed, rewards, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id
+	`, quest.CampaignID, quest.Title, quest.Description, quest.GiverNPCID, quest.GiverName,
+		quest.Status, quest.Revealed, quest.Rewards, quest.CreatedAt, quest.UpdatedAt,
+	).Scan(&quest.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create quest: %w", err)
+	}
+
+	for i := range quest.Objectives {
+		objective := &quest.Objectives[i]
+		objective.QuestID = quest.ID
+		objective.CreatedAt = now
+
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO quest_objectives (quest_id, description, completed, sort_order, created_at)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id
+		`, objective.QuestID, objective.Description, objective.Completed, objective.SortOrder, objective.CreatedAt,
+		).Scan(&objective.ID)
+		if err != nil {
+			return fmt.Errorf("failed to create quest objective: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// UpdateQuest atualiza os dados da quest, incluindo status e revelação
+func (p *PostgresDB) UpdateQuest(ctx context.Context, quest *models.Quest) error {
+	query := `
+		UPDATE quests SET
+		title = $1, description = $2, giver_npc_id = $3, giver_name = $4, status = $5,
+		revealed = $6, rewards = $7, completed_at = $8, updated_at = $9
+		WHERE id = $10 AND campaign_id = $11
+	`
+
+	quest.UpdatedAt = time.Now()
+
+	result, err := p.DB.ExecContext(ctx, query,
+		quest.Title, quest.Description, quest.GiverNPCID, quest.GiverName, quest.Status,
+		quest.Revealed, quest.Rewards, quest.CompletedAt, quest.UpdatedAt, quest.ID, quest.CampaignID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update quest: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("quest not found in campaign")
+	}
+
+	return nil
+}
+
+// DeleteQuest remove a quest (os objetivos são removidos em cascata)
+func (p *PostgresDB) DeleteQuest(ctx context.Context, id, campaignID int) error {
+	result, err := p.DB.ExecContext(ctx, `DELETE FROM quests WHERE id = $1 AND campaign_id = $2`, id, campaignID)
+	if err != nil {
+		return fmt.Errorf("failed to delete quest: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("quest not found in campaign")
+	}
+
+	return nil
+}
+
+// CreateQuestObjective adiciona um objetivo ao final da quest quando sort_order não é informado
+func (p *PostgresDB) CreateQuestObjective(ctx context.Context, objective *models.QuestObjective) error {
+	query := `
+		INSERT INTO quest_objectives (quest_id, description, completed, sort_order, completed_at, created_at)
+		VALUES (
+			$1, $2, $3,
+			COALESCE($4, (SELECT COALESCE(MAX(sort_order), -1) + 1 FROM quest_objectives WHERE quest_id = $1)),
+			$5, $6
+		)
+		RETURNING id, sort_order
+	`
+
+	objective.CreatedAt = time.Now()
+
+	var sortOrder *int
+	if objective.SortOrder >= 0 {
+		sortOrder = &objective.SortOrder
+	}
+
+	err := p.DB.QueryRowContext(ctx, query,
+		objective.QuestID, objective.Description, objective.Completed, sortOrder,
+		objective.CompletedAt, objective.CreatedAt,
+	).Scan(&objective.ID, &objective.SortOrder)
+	if err != nil {
+		return fmt.Errorf("failed to create quest objective: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateQuestObjective atualiza descrição, ordem e conclusão do objetivo
+func (p *PostgresDB) UpdateQuestObjective(ctx context.Context, objective *models.QuestObjective) error {
+	query := `
+		UPDATE quest_objectives SET
+		description = $1, completed = $2, sort_order = $3, completed_at = $4
+		WHERE id = $5 AND quest_id = $6
+	`
+
+	result, err := p.DB.ExecContext(ctx, query,
+		objective.Description, objective.Completed, objective.SortOrder, objective.CompletedAt,
+		objective.ID, objective.QuestID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update quest objective: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("objective not found in quest")
+	}
+
+	return nil
+}
+
+// DeleteQuestObjective remove um objetivo da quest
+func (p *PostgresDB) DeleteQuestObjective(ctx context.Context, id, questID int) error {
+	result, err := p.DB.ExecContext(ctx, `DELETE FROM quest_objectives WHERE id = $1 AND quest_id = $2`, id, questID)
+	if err != nil {
+		return fmt.Errorf("failed to delete quest objective: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("objective not found in quest")
+	}
+
+	return nil
+}
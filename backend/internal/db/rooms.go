@@ -44,7 +44,7 @@ func (p *PostgresDB) CreateRoom(ctx context.Context, room *models.Room) (*models
 
 func (p *PostgresDB) GetRoomByID(ctx context.Context, roomID string) (*models.Room, error) {
 	query := `
-		SELECT id, name, owner_id, campaign_id, scene_state, metadata, created_at, updated_at
+		SELECT id, name, owner_id, campaign_id, scene_state, metadata, created_at, updated_at, archived_at
 		FROM rooms
 		WHERE id = $1
 	`
@@ -61,7 +61,7 @@ func (p *PostgresDB) GetRoomByID(ctx context.Context, roomID string) (*models.Ro
 
 func (p *PostgresDB) GetRoomByCampaignID(ctx context.Context, campaignID int) (*models.Room, error) {
 	query := `
-		SELECT id, name, owner_id, campaign_id, scene_state, metadata, created_at, updated_at
+		SELECT id, name, owner_id, campaign_id, scene_state, metadata, created_at, updated_at, archived_at
 		FROM rooms
 		WHERE campaign_id = $1
 		ORDER BY created_at DESC
@@ -138,7 +138,7 @@ func (p *PostgresDB) UpdateRoomScene(ctx context.Context, roomID string, scene m
 		SET scene_state = $1,
 		    metadata = $2,
 		    updated_at = NOW()
-		WHERE id = $3
+		WHERE id = $3 AND archived_at IS NULL
 		RETURNING id, name, owner_id, campaign_id, scene_state, metadata, created_at, updated_at
 	`
 
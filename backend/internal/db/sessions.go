@@ -0,0 +1,300 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"rpg-saas-backend/internal/models"
+)
+
+var (
+	// ErrSessionInProgress indica que a campanha já tem outra sessão em andamento
+	ErrSessionInProgress = errors.New("another session is already in progress")
+	// ErrSessionNotScheduled indica que a sessão deixou de estar agendada antes de iniciar
+	ErrSessionNotScheduled = errors.New("session is no longer scheduled")
+)
+
+const sessionColumns = `
+	id, campaign_id, session_number, title, scheduled_at, status, room_id,
+	started_at, ended_at, dm_notes, recap, created_at, updated_at
+`
+
+// GetCampaignSessions lista as sessões da campanha em ordem numérica
+func (p *PostgresDB) GetCampaignSessions(ctx context.Context, campaignID int) ([]models.CampaignSession, error) {
+	sessions := []models.CampaignSession{}
+	query := `SELECT ` + sessionColumns + `
+		FROM campaign_sessions
+		WHERE campaign_id = $1
+		ORDER BY session_number ASC
+	`
+
+	if err := p.DB.SelectContext(ctx, &sessions, query, campaignID); err != nil {
+		return nil, fmt.Errorf("failed to fetch sessions for campaign %d: %w", campaignID, err)
+	}
+
+	return sessions, nil
+}
+
+// GetCampaignSession retorna uma sessão da campanha (nil, nil se não existir)
+func (p *PostgresDB) GetCampaignSession(ctx context.Context, id, campaignID int) (*models.CampaignSession, error) {
+	var session models.CampaignSession
+	query := `SELECT ` + sessionColumns + `
+		FROM campaign_sessions
+		WHERE id = $1 AND campaign_id = $2
+	`
+
+	if err := p.DB.GetContext(ctx, &session, query, id, campaignID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch session %d: %w", id, err)
+	}
+
+	return &session, nil
+}
+
+// GetSessionAttendance lista a presença registrada na sessão
+func (p *PostgresDB) GetSessionAttendance(ctx context.Context, sessionID int) ([]models.SessionAttendance, error) {
+	attendance := []models.SessionAttendance{}
+	query := `
+		SELECT sa.session_id, sa.user_id, COALESCE(u.username, '') AS username,
+		       sa.status, sa.notes, sa.updated_at
+		FROM session_attendance sa
+		LEFT JOIN users u ON sa.user_id = u.id
+		WHERE sa.session_id = $1
+		ORDER BY sa.user_id
+	`
+
+	if err := p.DB.SelectContext(ctx, &attendance, query, sessionID); err != nil {
+		return nil, fmt.Errorf("failed to fetch attendance for session %d: %w", sessionID, err)
+	}
+
+	return attendance, nil
+}
+
+// CreateCampaignSession cria uma sessão; se SessionNumber for 0, usa o próximo número livre
+func (p *PostgresDB) CreateCampaignSession(ctx context.Context, session *models.CampaignSession) error {
+	query := `
+		INSERT INTO campaign_sessions (campaign_id, session_number, title, scheduled_at, status, created_at, updated_at)
+		VALUES (
+			$1,
+			COALESCE(NULLIF($2, 0), (SELECT COALESCE(MAX(session_number), 0) + 1 FROM campaign_sessions WHERE campaign_id = $1)),
+			$3, $4, $5, $6, $7
+		)
+		RETURNING id, session_number
+	`
+
+	now := time.Now()
+	session.CreatedAt = now
+	session.UpdatedAt = now
+	if session.Status == "" {
+		session.Status = models.SessionStatusScheduled
+	}
+
+	err := p.DB.QueryRowContext(ctx, query,
+		session.CampaignID, session.SessionNumber, session.Title, session.ScheduledAt,
+		session.Status, session.CreatedAt, session.UpdatedAt,
+	).Scan(&session.ID, &session.SessionNumber)
+	if err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateCampaignSession atualiza os dados editáveis da sessão
+func (p *PostgresDB) UpdateCampaignSession(ctx context.Context, session *models.CampaignSession) error {
+	query := `
+		UPDATE campaign_sessions SET
+		title = $1, scheduled_at = $2, status = $3, dm_notes = $4, recap = $5, updated_at = $6
+		WHERE id = $7 AND campaign_id = $8
+	`
+
+	session.UpdatedAt = time.Now()
+
+	result, err := p.DB.ExecContext(ctx, query,
+		session.Title, session.ScheduledAt, session.Status, session.DMNotes, session.Recap,
+		session.UpdatedAt, session.ID, session.CampaignID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update session: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("session not found in campaign")
+	}
+
+	return nil
+}
+
+// DeleteCampaignSession remove uma sessão da campanha
+func (p *PostgresDB) DeleteCampaignSession(ctx context.Context, id, campaignID int) error {
+	query := `DELETE FROM campaign_sessions WHERE id = $1 AND campaign_id = $2`
+
+	result, err := p.DB.ExecContext(ctx, query, id, campaignID)
+	if err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("session not found in campaign")
+	}
+
+	return nil
+}
+
+// hasSessionInProgressTx verifica se a campanha já tem uma sessão em andamento
+func hasSessionInProgressTx(ctx context.Context, q sqlx.QueryerContext, campaignID int) (bool, error) {
+	var exists bool
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM campaign_sessions WHERE campaign_id = $1 AND status = 'in_progress'
+		)
+	`
+
+	if err := q.QueryRowxContext(ctx, query, campaignID).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check sessions in progress: %w", err)
+	}
+
+	return exists, nil
+}
+
+// StartCampaignSession marca a sessão agendada como em andamento e avança current_session da
+// campanha. A campanha fica travada durante a transação, então dois inícios simultâneos não
+// deixam duas sessões em andamento.
+func (p *PostgresDB) StartCampaignSession(ctx context.Context, session *models.CampaignSession) error {
+	tx, err := p.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT id FROM campaigns WHERE id = $1 FOR UPDATE`, session.CampaignID); err != nil {
+		return fmt.Errorf("failed to lock campaign: %w", err)
+	}
+
+	inProgress, err := hasSessionInProgressTx(ctx, tx, session.CampaignID)
+	if err != nil {
+		return err
+	}
+	if inProgress {
+		return ErrSessionInProgress
+	}
+
+	now := time.Now()
+	startedAt := now
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE campaign_sessions SET
+		status = $1, started_at = $2, room_id = $3, updated_at = $4
+		WHERE id = $5 AND campaign_id = $6 AND status = 'scheduled'
+	`, models.SessionStatusInProgress, startedAt, session.RoomID, now, session.ID, session.CampaignID)
+	if err != nil {
+		return fmt.Errorf("failed to start session: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrSessionNotScheduled
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE campaigns SET
+		current_session = GREATEST(current_session, $1), updated_at = $2
+		WHERE id = $3
+	`, session.SessionNumber, now, session.CampaignID)
+	if err != nil {
+		return fmt.Errorf("failed to advance campaign session: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit session start: %w", err)
+	}
+
+	session.Status = models.SessionStatusInProgress
+	session.StartedAt = &startedAt
+	session.UpdatedAt = now
+	return nil
+}
+
+// EndCampaignSession encerra a sessão, registrando o recap
+func (p *PostgresDB) EndCampaignSession(ctx context.Context, session *models.CampaignSession) error {
+	now := time.Now()
+	session.Status = models.SessionStatusCompleted
+	session.EndedAt = &now
+	session.UpdatedAt = now
+
+	query := `
+		UPDATE campaign_sessions SET
+		status = $1, ended_at = $2, recap = $3, updated_at = $4
+		WHERE id = $5 AND campaign_id = $6
+	`
+
+	result, err := p.DB.ExecContext(ctx, query,
+		session.Status, session.EndedAt, session.Recap, session.UpdatedAt, session.ID, session.CampaignID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to end session: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("session not found in campaign")
+	}
+
+	return nil
+}
+
+// UpsertSessionAttendance registra o status de presença do jogador
+func (p *PostgresDB) UpsertSessionAttendance(ctx context.Context, sessionID, userID int, status string) error {
+	query := `
+		INSERT INTO session_attendance (session_id, user_id, status, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (session_id, user_id)
+		DO UPDATE SET status = EXCLUDED.status, updated_at = EXCLUDED.updated_at
+	`
+
+	if _, err := p.DB.ExecContext(ctx, query, sessionID, userID, status, time.Now()); err != nil {
+		return fmt.Errorf("failed to update attendance: %w", err)
+	}
+
+	return nil
+}
+
+// UpsertSessionNotes grava as notas do jogador na sessão
+func (p *PostgresDB) UpsertSessionNotes(ctx context.Context, sessionID, userID int, notes string) error {
+	query := `
+		INSERT INTO session_attendance (session_id, user_id, notes, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (session_id, user_id)
+		DO UPDATE SET notes = EXCLUDED.notes, updated_at = EXCLUDED.updated_at
+	`
+
+	if _, err := p.DB.ExecContext(ctx, query, sessionID, userID, notes, time.Now()); err != nil {
+		return fmt.Errorf("failed to update session notes: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,217 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"rpg-saas-backend/internal/models"
+)
+
+const wikiEntryColumns = `
+	id, campaign_id, entry_type, title, COALESCE(body, '') AS body,
+	COALESCE(tags, ARRAY[]::text[]) AS tags, visibility,
+	COALESCE(selected_players, ARRAY[]::integer[]) AS selected_players,
+	COALESCE(linked_entry_ids, ARRAY[]::integer[]) AS linked_entry_ids,
+	created_by, created_at, updated_at
+`
+
+// GetWikiEntries lista as entradas do wiki visíveis ao usuário. O DM vê todas;
+// players veem as públicas e as compartilhadas com eles.
+func (p *PostgresDB) GetWikiEntries(ctx context.Context, campaignID, userID int, isDM bool, filters models.WikiFilters) ([]models.WikiEntry, error) {
+	entries := []models.WikiEntry{}
+	baseQuery := `SELECT ` + wikiEntryColumns + ` FROM campaign_wiki_entries WHERE campaign_id = $1`
+	args := []interface{}{campaignID}
+	argIndex := 2
+
+	var conditions []string
+
+	if !isDM {
+		conditions = append(conditions, fmt.Sprintf(
+			"(visibility = 'all' OR (visibility = 'selected' AND $%d = ANY(selected_players)))", argIndex))
+		args = append(args, userID)
+		argIndex++
+	}
+
+	if filters.EntryType != "" {
+		conditions = append(conditions, fmt.Sprintf("entry_type = $%d", argIndex))
+		args = append(args, filters.EntryType)
+		argIndex++
+	}
+
+	if filters.Tag != "" {
+		conditions = append(conditions, fmt.Sprintf("$%d = ANY(tags)", argIndex))
+		args = append(args, strings.ToLower(filters.Tag))
+		argIndex++
+	}
+
+	if filters.Search != "" {
+		conditions = append(conditions, fmt.Sprintf("(title ILIKE $%d OR body ILIKE $%d)", argIndex, argIndex))
+		args = append(args, "%"+escapeLikePattern(filters.Search)+"%")
+	}
+
+	if len(conditions) > 0 {
+		baseQuery += " AND " + strings.Join(conditions, " AND ")
+	}
+
+	query := baseQuery + " ORDER BY entry_type, title"
+
+	if err := p.DB.SelectContext(ctx, &entries, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to fetch wiki entries for campaign %d: %w", campaignID, err)
+	}
+
+	return entries, nil
+}
+
+// likeEscaper protege os curingas do LIKE; no Postgres a barra invertida já é
+// o caractere de escape padrão
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLikePattern faz o termo de busca casar literalmente dentro de um ILIKE
+func escapeLikePattern(term string) string {
+	return likeEscaper.Replace(term)
+}
+
+// GetWikiEntry retorna uma entrada do wiki da campanha (nil, nil se não existir)
+func (p *PostgresDB) GetWikiEntry(ctx context.Context, id, campaignID int) (*models.WikiEntry, error) {
+	var entry models.WikiEntry
+	query := `SELECT ` + wikiEntryColumns + ` FROM campaign_wiki_entries WHERE id = $1 AND campaign_id = $2`
+
+	if err := p.DB.GetContext(ctx, &entry, query, id, campaignID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch wiki entry %d: %w", id, err)
+	}
+
+	return &entry, nil
+}
+
+// GetWikiEntriesByIDs retorna as entradas da campanha com os IDs informados
+func (p *PostgresDB) GetWikiEntriesByIDs(ctx context.Context, campaignID int, ids []int64) ([]models.WikiEntry, error) {
+	entries := []models.WikiEntry{}
+	if len(ids) == 0 {
+		return entries, nil
+	}
+
+	query := `SELECT ` + wikiEntryColumns + `
+		FROM campaign_wiki_entries
+		WHERE campaign_id = $1 AND id = ANY($2)
+		ORDER BY title
+	`
+
+	if err := p.DB.SelectContext(ctx, &entries, query, campaignID, pq.Int64Array(ids)); err != nil {
+		return nil, fmt.Errorf("failed to fetch linked wiki entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// GetWikiBacklinks retorna as entradas da campanha que apontam para a entrada informada
+func (p *PostgresDB) GetWikiBacklinks(ctx context.Context, campaignID, entryID int) ([]models.WikiEntry, error) {
+	entries := []models.WikiEntry{}
+	query := `SELECT ` + wikiEntryColumns + `
+		FROM campaign_wiki_entries
+		WHERE campaign_id = $1 AND $2 = ANY(linked_entry_ids)
+		ORDER BY title
+	`
+
+	if err := p.DB.SelectContext(ctx, &entries, query, campaignID, entryID); err != nil {
+		return nil, fmt.Errorf("failed to fetch wiki backlinks for entry %d: %w", entryID, err)
+	}
+
+	return entries, nil
+}
+
+// CreateWikiEntry cria uma entrada no wiki da campanha
+func (p *PostgresDB) CreateWikiEntry(ctx context.Context, entry *models.WikiEntry) error {
+	query := `
+		INSERT INTO campaign_wiki_entries (
+			campaign_id, entry_type, title, body, tags, visibility,
+			selected_players, linked_entry_ids, created_by, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id
+	`
+
+	now := time.Now()
+	entry.CreatedAt = now
+	entry.UpdatedAt = now
+
+	err := p.DB.QueryRowContext(ctx, query,
+		entry.CampaignID, entry.EntryType, entry.Title, entry.Body, entry.Tags, entry.Visibility,
+		entry.SelectedPlayers, entry.LinkedEntryIDs, entry.CreatedBy, entry.CreatedAt, entry.UpdatedAt,
+	).Scan(&entry.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create wiki entry: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateWikiEntry atualiza uma entrada do wiki
+func (p *PostgresDB) UpdateWikiEntry(ctx context.Context, entry *models.WikiEntry) error {
+	query := `
+		UPDATE campaign_wiki_entries SET
+		entry_type = $1, title = $2, body = $3, tags = $4, visibility = $5,
+		selected_players = $6, linked_entry_ids = $7, updated_at = $8
+		WHERE id = $9 AND campaign_id = $10
+	`
+
+	entry.UpdatedAt = time.Now()
+
+	result, err := p.DB.ExecContext(ctx, query,
+		entry.EntryType, entry.Title, entry.Body, entry.Tags, entry.Visibility,
+		entry.SelectedPlayers, entry.LinkedEntryIDs, entry.UpdatedAt, entry.ID, entry.CampaignID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update wiki entry: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("wiki entry not found in campaign")
+	}
+
+	return nil
+}
+
+// DeleteWikiEntry remove a entrada e os links que apontavam para ela
+func (p *PostgresDB) DeleteWikiEntry(ctx context.Context, id, campaignID int) error {
+	tx, err := p.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM campaign_wiki_entries WHERE id = $1 AND campaign_id = $2`, id, campaignID)
+	if err != nil {
+		return fmt.Errorf("failed to delete wiki entry: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("wiki entry not found in campaign")
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE campaign_wiki_entries SET linked_entry_ids = array_remove(linked_entry_ids, $1)
+		WHERE campaign_id = $2 AND $1 = ANY(linked_entry_ids)
+	`, id, campaignID)
+	if err != nil {
+		return fmt.Errorf("failed to remove wiki links: %w", err)
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Tipos de evento do feed de atividade da campanha (categoria.ação)
+const (
+	ActivityPlayerJoined           = "player.joined"
+	ActivityPlayerJoinRequested    = "player.join_requested" // Pendente de aprovação ou na lista de espera
+	ActivityPlayerLeft             = "player.left"
+	ActivityPlayerRemoved          = "player.removed" // Expulso ou banido pelo DM
+	ActivityInviteCreated          = "invite.created"
+	ActivityInviteCodeRegenerated  = "invite.code_regenerated"
+	ActivityCharacterAdded         = "character.added"
+	ActivityCharacterUpdated       = "character.updated"
+	ActivityCharacterHPChanged     = "character.hp_changed"
+	ActivityCharacterStatusChanged = "character.status_changed"
+	ActivitySceneUpdated           = "room.scene_updated"
+	ActivityCampaignUpdated        = "campaign.updated"
+	ActivityCampaignStatusChanged  = "campaign.status_changed"
+)
+
+// Tipos do alvo de um evento
+const (
+	ActivityTargetPlayer    = "player"
+	ActivityTargetInvite    = "invite"
+	ActivityTargetCharacter = "character"
+	ActivityTargetRoom      = "room"
+	ActivityTargetCampaign  = "campaign"
+)
+
+// CampaignActivity é um evento do feed de atividade (append-only) da campanha
+type CampaignActivity struct {
+	ID         int       `json:"id" db:"id"`
+	CampaignID int       `json:"campaign_id" db:"campaign_id"`
+	ActorID    *int      `json:"actor_id" db:"actor_id"`
+	ActorName  string    `json:"actor_name" db:"actor_name"`
+	EventType  string    `json:"event_type" db:"event_type"`
+	TargetType string    `json:"target_type,omitempty" db:"target_type"`
+	TargetID   string    `json:"target_id,omitempty" db:"target_id"` // Texto: salas usam IDs alfanuméricos
+	Summary    string    `json:"summary" db:"summary"`
+	Metadata   JSONB     `json:"metadata,omitempty" db:"metadata"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// ActivityFilter filtra o feed de atividade. Types aceita tipos exatos (character.hp_changed)
+// ou categorias (character).
+type ActivityFilter struct {
+	Types      []string
+	ActorID    *int
+	TargetType string
+	TargetID   string
+	Since      *time.Time
+	Until      *time.Time
+}
+
+// Matches indica se o evento passa pelo filtro (usado no stream ao vivo; a consulta ao
+// banco aplica os mesmos critérios em SQL)
+func (f ActivityFilter) Matches(activity CampaignActivity) bool {
+	if len(f.Types) > 0 {
+		category, _, _ := strings.Cut(activity.EventType, ".")
+		matched := false
+		for _, t := range f.Types {
+			if t == activity.EventType || t == category {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if f.ActorID != nil && (activity.ActorID == nil || *activity.ActorID != *f.ActorID) {
+		return false
+	}
+	if f.TargetType != "" && f.TargetType != activity.TargetType {
+		return false
+	}
+	if f.TargetID != "" && f.TargetID != activity.TargetID {
+		return false
+	}
+	if f.Since != nil && activity.CreatedAt.Before(*f.Since) {
+		return false
+	}
+	if f.Until != nil && activity.CreatedAt.After(*f.Until) {
+		return false
+	}
+	return true
+}
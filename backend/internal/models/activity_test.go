@@ -0,0 +1,43 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestActivityFilterMatches(t *testing.T) {
+	actor := 7
+	other := 8
+	activity := CampaignActivity{
+		CampaignID: 1,
+		ActorID:    &actor,
+		EventType:  ActivityCharacterHPChanged,
+		TargetType: ActivityTargetCharacter,
+		TargetID:   "5",
+		CreatedAt:  time.Now(),
+	}
+	past := activity.CreatedAt.Add(-time.Hour)
+
+	cases := []struct {
+		name   string
+		filter ActivityFilter
+		want   bool
+	}{
+		{"empty filter", ActivityFilter{}, true},
+		{"exact type", ActivityFilter{Types: []string{ActivityCharacterHPChanged}}, true},
+		{"category", ActivityFilter{Types: []string{"player", "character"}}, true},
+		{"other type", ActivityFilter{Types: []string{ActivityCharacterUpdated}}, false},
+		{"actor", ActivityFilter{ActorID: &actor}, true},
+		{"other actor", ActivityFilter{ActorID: &other}, false},
+		{"target", ActivityFilter{TargetType: ActivityTargetCharacter, TargetID: "5"}, true},
+		{"other target", ActivityFilter{TargetID: "6"}, false},
+		{"since", ActivityFilter{Since: &past}, true},
+		{"until", ActivityFilter{Until: &past}, false},
+	}
+
+	for _, tc := range cases {
+		if got := tc.filter.Matches(activity); got != tc.want {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
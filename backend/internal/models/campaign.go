@@ -16,6 +16,7 @@ type Campaign struct {
 	CurrentSession int                 `json:"current_session" db:"current_session"`
 	Status         string              `json:"status" db:"status"` // planning, active, paused, completed
 	AllowHomebrew  bool                `json:"allow_homebrew" db:"allow_homebrew"`
+	LevelingMode   string              `json:"leveling_mode" db:"leveling_mode"` // xp, milestone
 	InviteCode     string              `json:"invite_code" db:"invite_code"`
 	CreatedAt      time.Time           `json:"created_at" db:"created_at"`
 	UpdatedAt      time.Time           `json:"updated_at" db:"updated_at"`
@@ -24,13 +25,44 @@ type Campaign struct {
 	PlayerCount    int                 `json:"player_count,omitempty"`
 }
 
+// Modos de progressão de nível da campanha
+const (
+	LevelingModeXP        = "xp"        // Personagens sobem ao atingir os limiares de XP
+	LevelingModeMilestone = "milestone" // O DM decide quando o grupo sobe de nível
+)
+
+var LevelingModes = []string{LevelingModeXP, LevelingModeMilestone}
+
+// Status de um jogador em campaign_players
+const (
+	PlayerStatusActive     = "active"
+	PlayerStatusPending    = "pending"    // Aguardando aprovação do DM
+	PlayerStatusWaitlisted = "waitlisted" // Campanha cheia, na lista de espera
+	PlayerStatusRemoved    = "removed"    // Removido pelo DM, pode voltar por convite
+	PlayerStatusBanned     = "banned"     // Banido pelo DM, não pode voltar
+)
+
+var PlayerStatuses = []string{
+	PlayerStatusActive, PlayerStatusPending, PlayerStatusWaitlisted,
+	PlayerStatusRemoved, PlayerStatusBanned,
+}
+
+// Papéis de um jogador na campanha
+const (
+	PlayerRolePlayer = "player"
+	PlayerRoleCoDM   = "co_dm" // Pode editar a campanha, mas não excluir nem moderar jogadores
+)
+
+var PlayerRoles = []string{PlayerRolePlayer, PlayerRoleCoDM}
+
 type CampaignPlayer struct {
 	ID         int       `json:"id" db:"id"`
 	CampaignID int       `json:"campaign_id" db:"campaign_id"`
 	UserID     int       `json:"user_id" db:"user_id"`
 	User       *User     `json:"user,omitempty"`
 	JoinedAt   time.Time `json:"joined_at" db:"joined_at"`
-	Status     string    `json:"status" db:"status"` // active, inactive, removed
+	Status     string    `json:"status" db:"status"` // active, pending, waitlisted, inactive, removed, banned
+	Role       string    `json:"role" db:"role"`     // player, co_dm
 }
 
 // CampaignCharacter - Snapshot completo do PC para uma campanha específica
@@ -46,7 +78,8 @@ type CampaignCharacter struct {
 	Description       string         `json:"description" db:"description"`
 	Level             int            `json:"level" db:"level"`
 	Race              string         `json:"race" db:"race"`
-	Class             string         `json:"class" db:"class"`
+	Class             string         `json:"class" db:"class"` // Resumo de class_levels
+	ClassLevels       ClassLevels    `json:"class_levels" db:"class_levels"`
 	Background        string         `json:"background" db:"background"`
 	Alignment         string         `json:"alignment" db:"alignment"`
 	Attributes        JSONBFlexible  `json:"attributes" db:"attributes"`
@@ -72,6 +105,12 @@ type CampaignCharacter struct {
 	JoinedAt      time.Time  `json:"joined_at" db:"joined_at"`
 	LastSync      *time.Time `json:"last_sync" db:"last_sync"`
 	CampaignNotes string     `json:"campaign_notes" db:"campaign_notes"`
+	// Progressão: XP acumulado e se o personagem pode subir de nível
+	ExperiencePoints int  `json:"experience_points" db:"experience_points"`
+	LevelUpAvailable bool `json:"level_up_available" db:"level_up_available"`
+	// Preenchido quando a campanha é concluída; a ficha passa a ser somente leitura
+	FrozenAt *time.Time `json:"frozen_at,omitempty" db:"frozen_at"`
+	SyncBase JSONB      `json:"-" db:"sync_base"` // Estado compartilhado no último sync
 }
 
 type CreateCampaignRequest struct {
@@ -79,6 +118,7 @@ type CreateCampaignRequest struct {
 	Description   string `json:"description"`
 	MaxPlayers    int    `json:"max_players"`
 	AllowHomebrew bool   `json:"allow_homebrew"`
+	LevelingMode  string `json:"leveling_mode"` // xp (padrão) ou milestone
 }
 
 type UpdateCampaignRequest struct {
@@ -88,12 +128,21 @@ type UpdateCampaignRequest struct {
 	CurrentSession int    `json:"current_session"`
 	Status         string `json:"status"`
 	AllowHomebrew  *bool  `json:"allow_homebrew"`
+	LevelingMode   string `json:"leveling_mode"`
 }
 
 type JoinCampaignRequest struct {
 	InviteCode string `json:"invite_code" binding:"required"`
 }
 
+type UpdatePlayerRoleRequest struct {
+	Role string `json:"role"` // player, co_dm
+}
+
+type TransferCampaignRequest struct {
+	UserID int `json:"user_id"`
+}
+
 type CampaignInviteResponse struct {
 	InviteCode string `json:"invite_code"`
 	Message    string `json:"message"`
@@ -108,6 +157,8 @@ type UpdateCharacterStatusRequest struct {
 	TempAC    *int   `json:"temp_ac"`
 	Status    string `json:"status"`
 	Notes     string `json:"campaign_notes"`
+	Cause     string `json:"cause"`      // Causa da morte (status dead)
+	SessionID *int   `json:"session_id"` // Sessão da morte; padrão: a sessão em andamento
 }
 
 // Novo request para atualizar PC completo na campanha
@@ -117,6 +168,7 @@ type UpdateCampaignCharacterRequest struct {
 	Level             int           `json:"level"`
 	Race              string        `json:"race"`
 	Class             string        `json:"class"`
+	ClassLevels       ClassLevels   `json:"class_levels"`
 	Background        string        `json:"background"`
 	Alignment         string        `json:"alignment"`
 	Attributes        JSONBFlexible `json:"attributes"`
@@ -142,7 +194,9 @@ type UpdateCampaignCharacterRequest struct {
 
 // Request para sincronizar com o PC original
 type SyncCharacterRequest struct {
-	SyncToOtherCampaigns bool `json:"sync_to_other_campaigns"` // Se deve sincronizar com outras campanhas
+	SyncToOtherCampaigns bool   `json:"sync_to_other_campaigns"` // Se deve sincronizar com outras campanhas
+	Direction            string `json:"direction"`               // push (snapshot → PC, padrão) ou pull (PC → snapshot)
+	Force                bool   `json:"force"`                   // Sobrescreve mesmo havendo conflitos
 }
 
 type CampaignSummary struct {
@@ -151,6 +205,7 @@ type CampaignSummary struct {
 	Description    string    `json:"description" db:"description"`
 	Status         string    `json:"status" db:"status"`
 	AllowHomebrew  bool      `json:"allow_homebrew" db:"allow_homebrew"`
+	LevelingMode   string    `json:"leveling_mode" db:"leveling_mode"`
 	PlayerCount    int       `json:"player_count" db:"player_count"`
 	MaxPlayers     int       `json:"max_players" db:"max_players"`
 	CurrentSession int       `json:"current_session" db:"current_session"`
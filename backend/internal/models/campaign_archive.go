@@ -0,0 +1,149 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// CampaignArchiveVersion é a versão atual do formato de exportação de campanhas.
+// Incrementar sempre que um campo mudar de significado; campos novos e opcionais não exigem.
+const CampaignArchiveVersion = 1
+
+// CampaignArchive é o pacote portátil de uma campanha. IDs dentro do pacote são os da
+// instância de origem e servem apenas para ligar os registros entre si; usuários são
+// referenciados pelo username.
+type CampaignArchive struct {
+	Version    int                 `json:"version"`
+	ExportedAt time.Time           `json:"exported_at"`
+	Campaign   ArchivedCampaign    `json:"campaign"`
+	Players    []ArchivedPlayer    `json:"players"`
+	Characters []ArchivedCharacter `json:"characters"`
+	NPCs       []NPC               `json:"npcs"`
+	Encounters []Encounter         `json:"encounters"`
+	Homebrew   ArchivedHomebrew    `json:"homebrew"`
+	Scene      *ArchivedScene      `json:"scene,omitempty"`
+	Wiki       []WikiEntry         `json:"wiki,omitempty"`
+	Quests     []Quest             `json:"quests,omitempty"`
+}
+
+// AsTemplate remove jogadores e personagens e zera o progresso, deixando o pacote pronto
+// para começar a campanha do zero com outro grupo
+func (a *CampaignArchive) AsTemplate() {
+	a.Players = []ArchivedPlayer{}
+	a.Characters = []ArchivedCharacter{}
+	a.Campaign.Status = "planning"
+	a.Campaign.CurrentSession = 1
+
+	for i := range a.NPCs {
+		a.NPCs[i].Revealed = false
+	}
+	for i := range a.Encounters {
+		a.Encounters[i].Revealed = false
+		a.Encounters[i].XPAwarded = false
+	}
+
+	for i := range a.Wiki {
+		if a.Wiki[i].Visibility == WikiVisibilitySelected {
+			a.Wiki[i].Visibility = WikiVisibilityDMOnly
+		}
+		a.Wiki[i].SelectedPlayers = nil
+	}
+
+	for i := range a.Quests {
+		a.Quests[i].Status = QuestStatusActive
+		a.Quests[i].CompletedAt = nil
+		for j := range a.Quests[i].Objectives {
+			a.Quests[i].Objectives[j].Completed = false
+			a.Quests[i].Objectives[j].CompletedAt = nil
+		}
+	}
+}
+
+func (a CampaignArchive) Value() (driver.Value, error) {
+	data, err := json.Marshal(a)
+	return string(data), err
+}
+
+func (a *CampaignArchive) Scan(value any) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("failed to unmarshal campaign archive")
+	}
+	return json.Unmarshal(bytes, a)
+}
+
+type ArchivedCampaign struct {
+	Name           string `json:"name"`
+	Description    string `json:"description"`
+	DMUsername     string `json:"dm_username"`
+	MaxPlayers     int    `json:"max_players"`
+	CurrentSession int    `json:"current_session"`
+	Status         string `json:"status"`
+	AllowHomebrew  bool   `json:"allow_homebrew"`
+	LevelingMode   string `json:"leveling_mode,omitempty"`
+}
+
+type ArchivedPlayer struct {
+	ID       int    `json:"id"` // ID na instância de origem, usado em selected_players do wiki
+	Username string `json:"username"`
+	Status   string `json:"status"`
+	Role     string `json:"role"`
+}
+
+// ArchivedCharacter é o snapshot do personagem com o dono referenciado pelo username
+type ArchivedCharacter struct {
+	CampaignCharacter
+	Username string `json:"username"`
+}
+
+// ArchivedHomebrew reúne o conteúdo homebrew referenciado pelos personagens e NPCs
+type ArchivedHomebrew struct {
+	Races       []HomebrewRace       `json:"races"`
+	Classes     []HomebrewClass      `json:"classes"`
+	Backgrounds []HomebrewBackground `json:"backgrounds"`
+}
+
+// ArchivedScene guarda o estado da cena da sala da campanha
+type ArchivedScene struct {
+	RoomName   string        `json:"room_name"`
+	SceneState JSONBFlexible `json:"scene_state"`
+	Metadata   JSONB         `json:"metadata,omitempty"`
+}
+
+// UnresolvedReference descreve algo do pacote que não pôde ser ligado na instância de destino
+type UnresolvedReference struct {
+	Type      string `json:"type"`      // player, character, homebrew_race, ...
+	Reference string `json:"reference"` // username, nome do personagem etc.
+	Reason    string `json:"reason"`
+}
+
+// CampaignImportReport resume o que foi criado na importação
+type CampaignImportReport struct {
+	CampaignID int                    `json:"campaign_id"`
+	Players    int                    `json:"players"` // Pedidos pendentes criados para aprovação do DM
+	Characters int                    `json:"characters"`
+	NPCs       int                    `json:"npcs"`
+	Encounters int                    `json:"encounters"`
+	Homebrew   int                    `json:"homebrew_created"`
+	RoomID     string                 `json:"room_id,omitempty"`
+	IDMap      map[string]map[int]int `json:"id_map"` // tipo -> ID de origem -> ID novo
+	Unresolved []UnresolvedReference  `json:"unresolved"`
+}
+
+// MapID registra a correspondência entre o ID de origem e o ID criado
+func (r *CampaignImportReport) MapID(kind string, oldID, newID int) {
+	if r.IDMap == nil {
+		r.IDMap = map[string]map[int]int{}
+	}
+	if r.IDMap[kind] == nil {
+		r.IDMap[kind] = map[int]int{}
+	}
+	r.IDMap[kind][oldID] = newID
+}
+
+// AddUnresolved registra uma referência que não pôde ser resolvida
+func (r *CampaignImportReport) AddUnresolved(kind, reference, reason string) {
+	r.Unresolved = append(r.Unresolved, UnresolvedReference{Type: kind, Reference: reference, Reason: reason})
+}
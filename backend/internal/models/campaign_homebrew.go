@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+// Tipos de conteúdo de personagem verificados pela política de homebrew da campanha
+const (
+	ContentTypeRace       = "race"
+	ContentTypeClass      = "class"
+	ContentTypeBackground = "background"
+)
+
+// ContentTypes lista os tipos na ordem em que são verificados
+var ContentTypes = []string{ContentTypeRace, ContentTypeClass, ContentTypeBackground}
+
+// HomebrewWhitelistEntry é um conteúdo homebrew aprovado pelo DM para a campanha
+type HomebrewWhitelistEntry struct {
+	CampaignID  int       `json:"campaign_id" db:"campaign_id"`
+	ContentType string    `json:"content_type" db:"content_type"`
+	ContentID   int       `json:"content_id" db:"content_id"`
+	Name        string    `json:"name" db:"name"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// UpdateHomebrewWhitelistRequest substitui a lista de homebrew aprovado; listas vazias
+// liberam qualquer homebrew enquanto allow_homebrew estiver ativo
+type UpdateHomebrewWhitelistRequest struct {
+	Races       []int `json:"races"`
+	Classes     []int `json:"classes"`
+	Backgrounds []int `json:"backgrounds"`
+}
+
+// Entries converte a requisição em pares (tipo, ID), sem repetição
+func (r UpdateHomebrewWhitelistRequest) Entries() []HomebrewWhitelistEntry {
+	entries := []HomebrewWhitelistEntry{}
+	seen := map[string]map[int]bool{}
+	add := func(contentType string, ids []int) {
+		seen[contentType] = map[int]bool{}
+		for _, id := range ids {
+			if seen[contentType][id] {
+				continue
+			}
+			seen[contentType][id] = true
+			entries = append(entries, HomebrewWhitelistEntry{ContentType: contentType, ContentID: id})
+		}
+	}
+
+	add(ContentTypeRace, r.Races)
+	add(ContentTypeClass, r.Classes)
+	add(ContentTypeBackground, r.Backgrounds)
+	return entries
+}
+
+// ContentRejection explica por que a raça, classe ou antecedente de um personagem foi
+// recusado pela campanha
+type ContentRejection struct {
+	Field  string `json:"field"`
+	Value  string `json:"value"`
+	Code   string `json:"code"`
+	Reason string `json:"reason"`
+}
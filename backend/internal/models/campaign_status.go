@@ -0,0 +1,67 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Status de uma campanha (campaigns.status)
+const (
+	CampaignStatusPlanning  = "planning"
+	CampaignStatusActive    = "active"
+	CampaignStatusPaused    = "paused"    // Sala fechada até a campanha voltar a ficar ativa
+	CampaignStatusCompleted = "completed" // Final: sala arquivada e personagens congelados
+)
+
+var CampaignStatuses = []string{
+	CampaignStatusPlanning, CampaignStatusActive, CampaignStatusPaused, CampaignStatusCompleted,
+}
+
+// campaignStatusTransitions segue o fluxo planning → active ⇄ paused → completed
+var campaignStatusTransitions = map[string][]string{
+	CampaignStatusPlanning:  {CampaignStatusActive},
+	CampaignStatusActive:    {CampaignStatusPaused},
+	CampaignStatusPaused:    {CampaignStatusActive, CampaignStatusCompleted},
+	CampaignStatusCompleted: {},
+}
+
+// ValidateCampaignStatusTransition verifica se a campanha pode ir de from para to
+func ValidateCampaignStatusTransition(from, to string) error {
+	if !slices.Contains(CampaignStatuses, to) {
+		return fmt.Errorf("invalid status %q: must be one of %v", to, CampaignStatuses)
+	}
+	allowed, known := campaignStatusTransitions[from]
+	if !known {
+		// Status legados podem ir para qualquer status válido
+		return nil
+	}
+	if len(allowed) == 0 {
+		return fmt.Errorf("cannot change status from %s: the campaign is %s", from, from)
+	}
+	if !slices.Contains(allowed, to) {
+		return fmt.Errorf("cannot change status from %s to %s: allowed next statuses are %v", from, to, allowed)
+	}
+	return nil
+}
+
+// CampaignAcceptsChanges indica se o progresso dos personagens (XP, loot, fichas) ainda
+// pode ser alterado. Campanhas concluídas ficam congeladas.
+func CampaignAcceptsChanges(status string) bool {
+	return status != CampaignStatusCompleted
+}
+
+// CampaignStatusEvent descreve uma mudança de status para os hooks da transição
+type CampaignStatusEvent struct {
+	From      string
+	To        string
+	ChangedBy int
+}
+
+// CampaignStatusChange resume os efeitos colaterais de uma mudança de status da campanha
+type CampaignStatusChange struct {
+	From             string   `json:"from"`
+	To               string   `json:"to"`
+	RoomIDs          []string `json:"room_ids,omitempty"`          // Salas fechadas (pausa) ou arquivadas (conclusão)
+	FrozenCharacters int      `json:"frozen_characters,omitempty"` // Personagens congelados na conclusão
+	ClosedSockets    int      `json:"closed_sockets,omitempty"`    // Conexões ao vivo encerradas
+}
@@ -0,0 +1,28 @@
+package models
+
+import "testing"
+
+func TestValidateCampaignStatusTransition(t *testing.T) {
+	cases := []struct {
+		from, to string
+		ok       bool
+	}{
+		{CampaignStatusPlanning, CampaignStatusActive, true},
+		{CampaignStatusActive, CampaignStatusPaused, true},
+		{CampaignStatusPaused, CampaignStatusActive, true},
+		{CampaignStatusPaused, CampaignStatusCompleted, true},
+		{CampaignStatusPlanning, CampaignStatusCompleted, false},
+		{CampaignStatusActive, CampaignStatusCompleted, false},
+		{CampaignStatusActive, CampaignStatusPlanning, false},
+		{CampaignStatusCompleted, CampaignStatusActive, false},
+		{CampaignStatusActive, "archived", false},
+		{"", CampaignStatusActive, true},
+	}
+
+	for _, tc := range cases {
+		err := ValidateCampaignStatusTransition(tc.from, tc.to)
+		if (err == nil) != tc.ok {
+			t.Errorf("%s -> %s: expected ok=%v, got %v", tc.from, tc.to, tc.ok, err)
+		}
+	}
+}
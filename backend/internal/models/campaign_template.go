@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// CampaignTemplate guarda um pacote de campanha sem jogadores nem personagens, para ser
+// reaproveitado em novas campanhas
+type CampaignTemplate struct {
+	ID               int              `json:"id" db:"id"`
+	OwnerID          int              `json:"owner_id" db:"owner_id"`
+	Name             string           `json:"name" db:"name"`
+	Description      string           `json:"description" db:"description"`
+	SourceCampaignID *int             `json:"source_campaign_id,omitempty" db:"source_campaign_id"`
+	Archive          *CampaignArchive `json:"archive,omitempty" db:"archive"` // Omitido na listagem
+	CreatedAt        time.Time        `json:"created_at" db:"created_at"`
+}
+
+type SaveCampaignTemplateRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// CampaignCopyRequest cria uma campanha a partir de outra (clone) ou de um template;
+// campos vazios mantêm os valores de origem
+type CampaignCopyRequest struct {
+	Name        string  `json:"name"`
+	Description *string `json:"description"`
+	MaxPlayers  int     `json:"max_players"`
+}
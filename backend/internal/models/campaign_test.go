@@ -151,3 +151,30 @@ func TestSyncCharacterRequest(t *testing.T) {
 		t.Error("expected SyncToOtherCampaigns to be true")
 	}
 }
+
+func TestMergeSyncStates(t *testing.T) {
+	base := JSONB{"name": "PC", "level": float64(3)}
+	source := JSONB{"name": "PC", "level": float64(4)}
+	target := JSONB{"name": "Renamed", "level": float64(3)}
+
+	// Só o nível mudou na origem; o nome renomeado no destino é preservado
+	merge := MergeSyncStates(source, target, base, false)
+	if len(merge.Fields) != 1 || merge.Fields[0] != "level" || merge.Changes["level"] != float64(4) {
+		t.Fatalf("unexpected merge: %+v", merge)
+	}
+	if _, ok := merge.Changes["name"]; ok {
+		t.Fatalf("target-side rename must not be overwritten: %+v", merge.Changes)
+	}
+	if merge.Base["level"] != float64(4) || merge.Base["name"] != "PC" {
+		t.Fatalf("unexpected new base: %+v", merge.Base)
+	}
+
+	// Conflito só é sobrescrito com force
+	target["level"] = float64(5)
+	if merge := MergeSyncStates(source, target, base, false); len(merge.Fields) != 0 {
+		t.Fatalf("conflict must not be applied without force: %+v", merge)
+	}
+	if merge := MergeSyncStates(source, target, base, true); len(merge.Fields) != 1 || merge.Changes["level"] != float64(4) {
+		t.Fatalf("force must apply the source value: %+v", merge)
+	}
+}
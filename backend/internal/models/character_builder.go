@@ -0,0 +1,250 @@
+package models
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Métodos de geração dos atributos no construtor de personagens
+const (
+	AbilityMethodPointBuy      = "point_buy"
+	AbilityMethodStandardArray = "standard_array"
+	AbilityMethodRoll          = "roll" // 4d6 descartando o menor, rolado e gravado no servidor
+)
+
+// AbilityMethods lista os métodos aceitos
+var AbilityMethods = []string{AbilityMethodPointBuy, AbilityMethodStandardArray, AbilityMethodRoll}
+
+// PointBuyBudget é o total de pontos da compra de atributos
+const PointBuyBudget = 27
+
+// pointBuyCosts é o custo de cada valor na compra de atributos (8 a 15)
+var pointBuyCosts = map[int]int{8: 0, 9: 1, 10: 2, 11: 3, 12: 4, 13: 5, 14: 7, 15: 9}
+
+// StandardArray são os valores do conjunto padrão
+var StandardArray = []int{15, 14, 13, 12, 10, 8}
+
+// Quantidade de dados rolados por atributo e de atributos por rolagem
+const (
+	AbilityRollDice   = 4
+	AbilityRollScores = 6
+)
+
+// PointBuyCost retorna o custo de um valor na compra de atributos; ok é false fora de 8 a 15
+func PointBuyCost(score int) (cost int, ok bool) {
+	cost, ok = pointBuyCosts[score]
+	return cost, ok
+}
+
+// AbilityScoreRoll é uma rolagem de atributos feita pelo servidor. Dice guarda os 6 grupos de
+// 4 dados em ordem; cada rolagem pode criar um único PC.
+type AbilityScoreRoll struct {
+	ID        int           `json:"id" db:"id"`
+	UserID    int           `json:"user_id" db:"user_id"`
+	Dice      pq.Int64Array `json:"dice" db:"dice"`
+	Scores    pq.Int64Array `json:"scores" db:"scores"`
+	PCID      *int          `json:"pc_id,omitempty" db:"pc_id"`
+	UsedAt    *time.Time    `json:"used_at,omitempty" db:"used_at"`
+	CreatedAt time.Time     `json:"created_at" db:"created_at"`
+}
+
+// ScoresFromDice soma os 3 maiores de cada grupo de 4 dados
+func ScoresFromDice(dice []int64) []int64 {
+	scores := []int64{}
+	for start := 0; start+AbilityRollDice <= len(dice); start += AbilityRollDice {
+		group := slices.Clone(dice[start : start+AbilityRollDice])
+		slices.Sort(group)
+		total := int64(0)
+		for _, die := range group[1:] {
+			total += die
+		}
+		scores = append(scores, total)
+	}
+	return scores
+}
+
+// BackgroundRules são as proficiências concedidas pelo antecedente
+type BackgroundRules struct {
+	Name   string   `json:"name"`
+	Source string   `json:"source"`
+	Skills []string `json:"skills"` // Índices do SRD
+	Tools  []string `json:"tools,omitempty"`
+}
+
+// BackgroundRulesFromSRD extrai as proficiências de um antecedente do SRD
+// (starting_proficiencies: [{index: "skill-insight", name: "Skill: Insight"}])
+func BackgroundRulesFromSRD(background *DnDBackground) BackgroundRules {
+	rules := BackgroundRules{Name: background.Name, Source: RulesSourceSRD, Skills: []string{}}
+
+	proficiencies, _ := background.StartingProficiencies.Data.([]any)
+	for _, raw := range proficiencies {
+		entry, _ := raw.(map[string]any)
+		index, _ := entry["index"].(string)
+		if skill, ok := strings.CutPrefix(index, "skill-"); ok {
+			rules.Skills = append(rules.Skills, skill)
+		} else if name, _ := entry["name"].(string); name != "" {
+			rules.Tools = append(rules.Tools, name)
+		}
+	}
+
+	return rules
+}
+
+// BackgroundRulesFromHomebrew extrai as proficiências de um antecedente homebrew
+func BackgroundRulesFromHomebrew(background *HomebrewBackground) BackgroundRules {
+	rules := BackgroundRules{Name: background.Name, Source: RulesSourceHomebrew, Skills: []string{}}
+	for _, name := range background.SkillProficiencies {
+		if skill := FindSkill(name); skill != nil {
+			rules.Skills = append(rules.Skills, skill.Index)
+		}
+	}
+	rules.Tools = append(rules.Tools, background.ToolProficiencies...)
+	return rules
+}
+
+// CharacterDraft é o rascunho enviado ao construtor. AbilityScores são os valores antes dos
+// bônus raciais; com o método roll, devem ser os valores da rolagem RollID em qualquer ordem.
+type CharacterDraft struct {
+	Name          string         `json:"name"`
+	Description   string         `json:"description"`
+	Level         int            `json:"level"`
+	Race          string         `json:"race"`
+	Class         string         `json:"class"`
+	Background    string         `json:"background"`
+	Alignment     string         `json:"alignment"`
+	PlayerName    string         `json:"player_name"`
+	AbilityMethod string         `json:"ability_method"`
+	AbilityScores map[string]int `json:"ability_scores"`
+	RollID        *int           `json:"roll_id,omitempty"`
+	SkillChoices  []string       `json:"skill_choices"`
+}
+
+// BuilderRules reúne as regras resolvidas para validar um rascunho; Background e Roll são nil
+// quando não informados ou não encontrados
+type BuilderRules struct {
+	Race       RaceRules
+	Class      ClassRules
+	Background *BackgroundRules
+	Roll       *AbilityScoreRoll
+}
+
+// CharacterBuild é o resultado de um rascunho válido
+type CharacterBuild struct {
+	AbilityMethod      string           `json:"ability_method"`
+	PointsSpent        *int             `json:"points_spent,omitempty"`
+	BaseScores         map[string]int   `json:"base_scores"`
+	RacialBonuses      map[string]int   `json:"racial_bonuses"`
+	AbilityScores      map[string]int   `json:"ability_scores"`
+	SkillProficiencies []string         `json:"skill_proficiencies"` // Raça, classe e antecedente
+	ToolProficiencies  []string         `json:"tool_proficiencies"`
+	Race               RaceRules        `json:"race"`
+	Class              ClassRules       `json:"class"`
+	Background         *BackgroundRules `json:"background,omitempty"`
+}
+
+// NewCharacterBuild aplica os bônus raciais e junta as proficiências de raça, classe e
+// antecedente. O rascunho já deve ter sido validado.
+func NewCharacterBuild(draft CharacterDraft, rules BuilderRules) CharacterBuild {
+	build := CharacterBuild{
+		AbilityMethod:     draft.AbilityMethod,
+		BaseScores:        make(map[string]int, len(AbilityNames)),
+		RacialBonuses:     make(map[string]int, len(AbilityNames)),
+		AbilityScores:     make(map[string]int, len(AbilityNames)),
+		ToolProficiencies: []string{},
+		Race:              rules.Race,
+		Class:             rules.Class,
+		Background:        rules.Background,
+	}
+
+	spent := 0
+	for _, ability := range AbilityNames {
+		base := draft.AbilityScores[ability]
+		bonus := rules.Race.AbilityBonuses[ability]
+		build.BaseScores[ability] = base
+		build.RacialBonuses[ability] = bonus
+		build.AbilityScores[ability] = base + bonus
+		cost, _ := PointBuyCost(base)
+		spent += cost
+	}
+	if draft.AbilityMethod == AbilityMethodPointBuy {
+		build.PointsSpent = &spent
+	}
+
+	skills := map[string]bool{}
+	for _, skill := range rules.Race.Skills {
+		skills[skill] = true
+	}
+	for _, choice := range draft.SkillChoices {
+		if def := FindSkill(choice); def != nil {
+			skills[def.Index] = true
+		}
+	}
+	if rules.Background != nil {
+		for _, skill := range rules.Background.Skills {
+			skills[skill] = true
+		}
+		build.ToolProficiencies = append(build.ToolProficiencies, rules.Background.Tools...)
+	}
+	build.SkillProficiencies = make([]string, 0, len(skills))
+	for skill := range skills {
+		build.SkillProficiencies = append(build.SkillProficiencies, skill)
+	}
+	sort.Strings(build.SkillProficiencies)
+
+	return build
+}
+
+// ToPC monta o PC do rascunho: atributos finais e perícias proficientes, com as perícias
+// indexadas pelo rótulo do editor de fichas. Raça, classe ou antecedente homebrew marcam o PC
+// como homebrew.
+func (b CharacterBuild) ToPC(draft CharacterDraft) PC {
+	attributes := make(map[string]any, len(AbilityNames))
+	for ability, score := range b.AbilityScores {
+		attributes[ability] = score
+	}
+
+	skills := make(map[string]any, len(b.SkillProficiencies))
+	for _, index := range b.SkillProficiencies {
+		if def := FindSkill(index); def != nil {
+			skills[def.Label] = map[string]any{"proficient": true, "expertise": false, "bonus": 0}
+		}
+	}
+
+	level := draft.Level
+	if level < 1 {
+		level = 1
+	}
+
+	return PC{
+		Name:        draft.Name,
+		Description: draft.Description,
+		Level:       level,
+		Race:        draft.Race,
+		Class:       draft.Class,
+		ClassLevels: ClassLevels{{Class: draft.Class, Level: level}},
+		Background:  draft.Background,
+		Alignment:   draft.Alignment,
+		PlayerName:  draft.PlayerName,
+		Attributes:  JSONBFlexible{Data: attributes},
+		Abilities:   JSONBFlexible{Data: map[string]any{}},
+		Skills:      JSONBFlexible{Data: skills},
+		Attacks:     JSONBFlexible{Data: []any{}},
+		Spells: JSONBFlexible{Data: map[string]any{
+			"spell_slots":  map[string]any{},
+			"known_spells": []any{},
+		}},
+		Equipment:  JSONBFlexible{Data: []any{}},
+		IsHomebrew: b.usesHomebrew(),
+	}
+}
+
+func (b CharacterBuild) usesHomebrew() bool {
+	if b.Race.Source == RulesSourceHomebrew || b.Class.Source == RulesSourceHomebrew {
+		return true
+	}
+	return b.Background != nil && b.Background.Source == RulesSourceHomebrew
+}
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestScoresFromDice(t *testing.T) {
+	dice := []int64{6, 6, 6, 1, 1, 2, 3, 4, 5, 5, 5, 5}
+	scores := ScoresFromDice(dice)
+	if !slices.Equal(scores, []int64{18, 9, 15}) {
+		t.Fatalf("unexpected scores: %v", scores)
+	}
+}
+
+func TestPointBuyCost(t *testing.T) {
+	if cost, ok := PointBuyCost(15); !ok || cost != 9 {
+		t.Fatalf("expected 15 to cost 9, got %d %v", cost, ok)
+	}
+	if _, ok := PointBuyCost(16); ok {
+		t.Fatal("16 should not be purchasable")
+	}
+}
+
+func TestClassRulesFromSRD_SkillChoices(t *testing.T) {
+	class := ClassRulesFromSRD(&DnDClass{
+		Name: "Fighter",
+		ProficiencyChoices: JSONBFlexible{Data: []any{
+			map[string]any{"choose": float64(2), "from": map[string]any{"options": []any{
+				map[string]any{"item": map[string]any{"index": "skill-acrobatics"}},
+				map[string]any{"item": map[string]any{"index": "skill-athletics"}},
+				map[string]any{"item": map[string]any{"index": "skill-perception"}},
+			}}},
+		}},
+	})
+
+	if class.SkillChoiceCount != 2 || !slices.Equal(class.SkillOptions, []string{"acrobatics", "athletics", "perception"}) {
+		t.Fatalf("unexpected skill choices: %+v", class)
+	}
+}
+
+func TestBackgroundRules(t *testing.T) {
+	srd := BackgroundRulesFromSRD(&DnDBackground{
+		Name: "Acolyte",
+		StartingProficiencies: JSONBFlexible{Data: []any{
+			map[string]any{"index": "skill-insight", "name": "Skill: Insight"},
+			map[string]any{"index": "skill-religion", "name": "Skill: Religion"},
+		}},
+	})
+	if !slices.Equal(srd.Skills, []string{"insight", "religion"}) {
+		t.Fatalf("unexpected SRD background: %+v", srd)
+	}
+
+	homebrew := BackgroundRulesFromHomebrew(&HomebrewBackground{
+		Name:               "Smuggler",
+		SkillProficiencies: []string{"Stealth", "Furtividade", "Deception"},
+		ToolProficiencies:  []string{"Vehicles (water)"},
+	})
+	if homebrew.Source != RulesSourceHomebrew || len(homebrew.Skills) != 3 || homebrew.Tools[0] != "Vehicles (water)" {
+		t.Fatalf("unexpected homebrew background: %+v", homebrew)
+	}
+}
+
+func TestNewCharacterBuild(t *testing.T) {
+	draft := CharacterDraft{
+		Name:          "Tordek",
+		Level:         1,
+		Race:          "dwarf",
+		Class:         "fighter",
+		AbilityMethod: AbilityMethodPointBuy,
+		AbilityScores: map[string]int{"strength": 15, "dexterity": 12, "constitution": 15, "intelligence": 8, "wisdom": 10, "charisma": 8},
+		SkillChoices:  []string{"Athletics", "perception"},
+	}
+	rules := BuilderRules{
+		Race:       RaceRules{Name: "Dwarf", Source: RulesSourceSRD, AbilityBonuses: map[string]int{"constitution": 2}},
+		Class:      ClassRules{Name: "Fighter", Source: RulesSourceSRD},
+		Background: &BackgroundRules{Name: "Smuggler", Source: RulesSourceHomebrew, Skills: []string{"stealth"}, Tools: []string{"Vehicles (water)"}},
+	}
+
+	build := NewCharacterBuild(draft, rules)
+	if build.AbilityScores["constitution"] != 17 || build.RacialBonuses["constitution"] != 2 {
+		t.Fatalf("racial bonus not applied: %+v", build.AbilityScores)
+	}
+	if build.PointsSpent == nil || *build.PointsSpent != 24 {
+		t.Fatalf("expected 24 points spent, got %v", build.PointsSpent)
+	}
+	if !slices.Equal(build.SkillProficiencies, []string{"athletics", "perception", "stealth"}) {
+		t.Fatalf("unexpected skills: %v", build.SkillProficiencies)
+	}
+
+	pc := build.ToPC(draft)
+	attributes := pc.AttributeScores()
+	if attributes["constitution"] != 17 || !pc.IsHomebrew {
+		t.Fatalf("unexpected PC: %+v", pc)
+	}
+	skills := pc.Skills.Data.(map[string]any)
+	if _, ok := skills["Furtividade"]; !ok || len(skills) != 3 {
+		t.Fatalf("skills should be keyed by the editor label: %v", skills)
+	}
+}
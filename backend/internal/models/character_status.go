@@ -0,0 +1,104 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Status de um personagem na campanha (campaign_characters.status)
+const (
+	CharacterStatusActive      = "active"
+	CharacterStatusInactive    = "inactive"    // Fora das sessões por um tempo, mas ainda no grupo
+	CharacterStatusUnconscious = "unconscious" // Com 0 PV, fazendo testes contra a morte
+	CharacterStatusDead        = "dead"
+	CharacterStatusRetired     = "retired" // Saiu da campanha; libera o PC único para outras
+	CharacterStatusResurrected = "resurrected"
+)
+
+var CharacterStatuses = []string{
+	CharacterStatusActive, CharacterStatusInactive, CharacterStatusUnconscious,
+	CharacterStatusDead, CharacterStatusRetired, CharacterStatusResurrected,
+}
+
+// characterStatusTransitions lista os destinos permitidos a partir de cada status
+var characterStatusTransitions = map[string][]string{
+	CharacterStatusActive:      {CharacterStatusInactive, CharacterStatusUnconscious, CharacterStatusDead, CharacterStatusRetired},
+	CharacterStatusInactive:    {CharacterStatusActive, CharacterStatusRetired},
+	CharacterStatusUnconscious: {CharacterStatusActive, CharacterStatusDead},
+	CharacterStatusDead:        {CharacterStatusResurrected, CharacterStatusRetired},
+	CharacterStatusResurrected: {CharacterStatusActive, CharacterStatusInactive, CharacterStatusUnconscious, CharacterStatusDead, CharacterStatusRetired},
+	CharacterStatusRetired:     {CharacterStatusActive},
+}
+
+// dmOnlyCharacterStatuses só podem ser definidos pelo DM ou co-DM
+var dmOnlyCharacterStatuses = []string{CharacterStatusDead, CharacterStatusResurrected}
+
+// ValidateCharacterStatusTransition verifica se o personagem pode ir de from para to
+func ValidateCharacterStatusTransition(from, to string) error {
+	if !slices.Contains(CharacterStatuses, to) {
+		return fmt.Errorf("invalid status %q: must be one of %v", to, CharacterStatuses)
+	}
+	allowed, known := characterStatusTransitions[from]
+	if known && !slices.Contains(allowed, to) {
+		return fmt.Errorf("cannot change status from %s to %s", from, to)
+	}
+	// Status legados (ex.: removed) podem ir para qualquer status válido
+	return nil
+}
+
+// CharacterStatusRequiresDM indica se a transição é restrita ao DM: marcar a morte,
+// ressuscitar e trazer de volta um personagem aposentado
+func CharacterStatusRequiresDM(from, to string) bool {
+	return slices.Contains(dmOnlyCharacterStatuses, to) || from == CharacterStatusRetired
+}
+
+// CharacterStatusesInPlay ainda ocupam o PC de origem. Mortos continuam ocupando, pois podem
+// ser ressuscitados; só a aposentadoria libera um PC único. As consultas de disponibilidade
+// de PCs repetem esta lista em SQL.
+var CharacterStatusesInPlay = []string{
+	CharacterStatusActive, CharacterStatusInactive, CharacterStatusUnconscious,
+	CharacterStatusDead, CharacterStatusResurrected,
+}
+
+// CharacterStatusInPlay indica se o status ocupa o PC de origem
+func CharacterStatusInPlay(status string) bool {
+	return slices.Contains(CharacterStatusesInPlay, status)
+}
+
+// CanTakeHPUpdate indica se o PV atual do personagem pode ser alterado
+func CanTakeHPUpdate(status string) bool {
+	return status != CharacterStatusDead
+}
+
+// CharacterStatusEvent descreve uma mudança de status para os hooks da transição
+type CharacterStatusEvent struct {
+	From      string
+	To        string
+	ChangedBy int
+	Cause     string // Causa da morte, quando To = dead
+	SessionID *int   // Sessão da morte; nil usa a sessão em andamento, se houver
+	// VersionAction é a ação registrada na versão; vazio usa VersionActionStatus
+	VersionAction string
+}
+
+// CharacterDeath é o registro de uma morte no histórico da campanha
+type CharacterDeath struct {
+	ID            int       `json:"id" db:"id"`
+	CharacterID   int       `json:"character_id" db:"character_id"`
+	CampaignID    int       `json:"campaign_id" db:"campaign_id"`
+	CharacterName string    `json:"character_name" db:"character_name"`
+	Level         int       `json:"level" db:"level"`
+	SessionID     *int      `json:"session_id" db:"session_id"`
+	Cause         string    `json:"cause" db:"cause"`
+	RecordedBy    *int      `json:"recorded_by" db:"recorded_by"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
+
+// CharacterStatusChange resume o que os hooks fizeram numa mudança de status
+type CharacterStatusChange struct {
+	From         string          `json:"from"`
+	To           string          `json:"to"`
+	Death        *CharacterDeath `json:"death,omitempty"`
+	ReleasedPCID *int            `json:"released_pc_id,omitempty"` // PC único liberado pela aposentadoria
+}
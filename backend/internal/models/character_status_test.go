@@ -0,0 +1,42 @@
+package models
+
+import "testing"
+
+func TestValidateCharacterStatusTransition(t *testing.T) {
+	tests := []struct {
+		from, to string
+		valid    bool
+	}{
+		{CharacterStatusActive, CharacterStatusUnconscious, true},
+		{CharacterStatusUnconscious, CharacterStatusDead, true},
+		{CharacterStatusDead, CharacterStatusResurrected, true},
+		{CharacterStatusResurrected, CharacterStatusActive, true},
+		{CharacterStatusRetired, CharacterStatusActive, true},
+		{"removed", CharacterStatusActive, true},
+		{CharacterStatusDead, CharacterStatusActive, false},
+		{CharacterStatusInactive, CharacterStatusUnconscious, false},
+		{CharacterStatusActive, "zombie", false},
+	}
+
+	for _, tt := range tests {
+		err := ValidateCharacterStatusTransition(tt.from, tt.to)
+		if (err == nil) != tt.valid {
+			t.Errorf("%s -> %s: expected valid=%v, got %v", tt.from, tt.to, tt.valid, err)
+		}
+	}
+}
+
+func TestCharacterStatusRequiresDM(t *testing.T) {
+	if !CharacterStatusRequiresDM(CharacterStatusActive, CharacterStatusDead) {
+		t.Errorf("marking a character dead must require the DM")
+	}
+	if !CharacterStatusRequiresDM(CharacterStatusDead, CharacterStatusResurrected) {
+		t.Errorf("resurrecting must require the DM")
+	}
+	if !CharacterStatusRequiresDM(CharacterStatusRetired, CharacterStatusActive) {
+		t.Errorf("bringing a character back from retirement must require the DM")
+	}
+	if CharacterStatusRequiresDM(CharacterStatusActive, CharacterStatusRetired) {
+		t.Errorf("players may retire their own characters")
+	}
+}
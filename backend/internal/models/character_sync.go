@@ -0,0 +1,260 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// Direções de sincronização entre snapshot de campanha e PC original
+const (
+	SyncDirectionPush = "push" // snapshot da campanha → PC original
+	SyncDirectionPull = "pull" // PC original → snapshot da campanha
+)
+
+// CharacterSyncFields lista os campos compartilhados entre PC e snapshot.
+// Campos específicos da campanha (current_hp, status, notas) não são sincronizados.
+// class_levels é copiado junto, mas as diferenças aparecem em class e level, que o resumem.
+var CharacterSyncFields = []string{
+	"name", "description", "level", "race", "class", "background", "alignment",
+	"attributes", "abilities", "equipment", "hp", "ca", "proficiency_bonus", "inspiration",
+	"skills", "attacks", "spells", "personality_traits", "ideals", "bonds", "flaws",
+	"features", "player_name",
+}
+
+// SyncFieldDiff representa um campo com valores diferentes entre snapshot e PC
+type SyncFieldDiff struct {
+	Field    string `json:"field"`
+	Snapshot any    `json:"snapshot"`
+	PC       any    `json:"pc"`
+}
+
+// SyncConflict representa um campo alterado nos dois lados desde o último sync
+type SyncConflict struct {
+	Field    string `json:"field"`
+	Base     any    `json:"base"`
+	Snapshot any    `json:"snapshot"`
+	PC       any    `json:"pc"`
+}
+
+// CampaignSyncResult resume a propagação para outra campanha com o mesmo PC
+type CampaignSyncResult struct {
+	CampaignCharacterID int                `json:"campaign_character_id"`
+	CampaignID          int                `json:"campaign_id"`
+	Diffs               []SyncFieldDiff    `json:"diffs"`
+	Conflicts           []SyncConflict     `json:"conflicts"`
+	Synced              bool               `json:"synced"`
+	Frozen              bool               `json:"frozen,omitempty"`
+	Rejections          []ContentRejection `json:"rejections,omitempty"`    // Barrado pela política de homebrew da outra campanha
+	LevelBlocked        bool               `json:"level_blocked,omitempty"` // Subiria de nível sem level-up disponível
+}
+
+// SyncCharacterResponse é o resultado de SyncCampaignCharacter
+type SyncCharacterResponse struct {
+	Message   string               `json:"message"`
+	Direction string               `json:"direction"`
+	Diffs     []SyncFieldDiff      `json:"diffs"`
+	Conflicts []SyncConflict       `json:"conflicts"`
+	Applied   []string             `json:"applied"` // campos levados da origem para o destino
+	SyncCount int                  `json:"sync_count"`
+	Campaigns []CampaignSyncResult `json:"campaigns,omitempty"`
+	Success   bool                 `json:"success"`
+}
+
+// SyncedSnapshot é um snapshot gravado por um sync: Changed indica se recebeu campos da
+// origem; Base é o novo estado do último sync
+type SyncedSnapshot struct {
+	Character *CampaignCharacter
+	Changed   bool
+	Base      JSONB
+}
+
+// SyncMerge é o resultado do merge de três vias entre origem e destino de um sync
+type SyncMerge struct {
+	Fields  []string // campos levados da origem para o destino
+	Changes JSONB    // valores desses campos (com class_levels quando classe ou nível mudam)
+	Base    JSONB    // nova base do sync
+}
+
+// characterSyncState contém apenas os campos compartilhados, com as mesmas chaves JSON
+type characterSyncState struct {
+	Name              string        `json:"name"`
+	Description       string        `json:"description"`
+	Level             int           `json:"level"`
+	Race              string        `json:"race"`
+	Class             string        `json:"class"`
+	ClassLevels       ClassLevels   `json:"class_levels"`
+	Background        string        `json:"background"`
+	Alignment         string        `json:"alignment"`
+	Attributes        JSONBFlexible `json:"attributes"`
+	Abilities         JSONBFlexible `json:"abilities"`
+	Equipment         JSONBFlexible `json:"equipment"`
+	HP                int           `json:"hp"`
+	CA                int           `json:"ca"`
+	ProficiencyBonus  int           `json:"proficiency_bonus"`
+	Inspiration       bool          `json:"inspiration"`
+	Skills            JSONBFlexible `json:"skills"`
+	Attacks           JSONBFlexible `json:"attacks"`
+	Spells            JSONBFlexible `json:"spells"`
+	PersonalityTraits string        `json:"personality_traits"`
+	Ideals            string        `json:"ideals"`
+	Bonds             string        `json:"bonds"`
+	Flaws             string        `json:"flaws"`
+	Features          []string      `json:"features"`
+	PlayerName        string        `json:"player_name"`
+}
+
+// toJSONB normaliza o estado via JSON para que possa ser comparado com o que está salvo no banco
+func (s characterSyncState) toJSONB() JSONB {
+	if s.Features == nil {
+		s.Features = []string{}
+	}
+
+	state := JSONB{}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return state
+	}
+	_ = json.Unmarshal(data, &state)
+	return state
+}
+
+// SyncState retorna os campos compartilhados do snapshot
+func (c *CampaignCharacter) SyncState() JSONB {
+	return characterSyncState{
+		Name: c.Name, Description: c.Description, Level: c.Level, Race: c.Race, Class: c.Class,
+		ClassLevels: c.Classes(), Background: c.Background, Alignment: c.Alignment, Attributes: c.Attributes,
+		Abilities: c.Abilities, Equipment: c.Equipment, HP: c.HP, CA: c.CA,
+		ProficiencyBonus: c.ProficiencyBonus, Inspiration: c.Inspiration, Skills: c.Skills,
+		Attacks: c.Attacks, Spells: c.Spells, PersonalityTraits: c.PersonalityTraits,
+		Ideals: c.Ideals, Bonds: c.Bonds, Flaws: c.Flaws, Features: c.Features,
+		PlayerName: c.PlayerName,
+	}.toJSONB()
+}
+
+// SyncState retorna os campos compartilhados do PC
+func (pc *PC) SyncState() JSONB {
+	return characterSyncState{
+		Name: pc.Name, Description: pc.Description, Level: pc.Level, Race: pc.Race, Class: pc.Class,
+		ClassLevels: pc.Classes(), Background: pc.Background, Alignment: pc.Alignment, Attributes: pc.Attributes,
+		Abilities: pc.Abilities, Equipment: pc.Equipment, HP: pc.HP, CA: pc.CA,
+		ProficiencyBonus: pc.ProficiencyBonus, Inspiration: pc.Inspiration, Skills: pc.Skills,
+		Attacks: pc.Attacks, Spells: pc.Spells, PersonalityTraits: pc.PersonalityTraits,
+		Ideals: pc.Ideals, Bonds: pc.Bonds, Flaws: pc.Flaws, Features: pc.Features,
+		PlayerName: pc.PlayerName,
+	}.toJSONB()
+}
+
+// ApplySnapshot copia os campos compartilhados do snapshot para o PC
+func (pc *PC) ApplySnapshot(c *CampaignCharacter) {
+	pc.Name = c.Name
+	pc.Description = c.Description
+	pc.Level = c.Level
+	pc.Race = c.Race
+	pc.Class = c.Class
+	pc.ClassLevels = c.ClassLevels
+	pc.Background = c.Background
+	pc.Alignment = c.Alignment
+	pc.Attributes = c.Attributes
+	pc.Abilities = c.Abilities
+	pc.Equipment = c.Equipment
+	pc.HP = c.HP
+	pc.CA = c.CA
+	pc.ProficiencyBonus = c.ProficiencyBonus
+	pc.Inspiration = c.Inspiration
+	pc.Skills = c.Skills
+	pc.Attacks = c.Attacks
+	pc.Spells = c.Spells
+	pc.PersonalityTraits = c.PersonalityTraits
+	pc.Ideals = c.Ideals
+	pc.Bonds = c.Bonds
+	pc.Flaws = c.Flaws
+	pc.Features = c.Features
+	pc.PlayerName = c.PlayerName
+}
+
+// DiffSyncStates compara snapshot e PC campo a campo. Se base (estado do último
+// sync) for informado, campos alterados nos dois lados com valores diferentes
+// são reportados como conflitos.
+func DiffSyncStates(snapshot, pc, base JSONB) ([]SyncFieldDiff, []SyncConflict) {
+	diffs := []SyncFieldDiff{}
+	conflicts := []SyncConflict{}
+
+	for _, field := range CharacterSyncFields {
+		if reflect.DeepEqual(snapshot[field], pc[field]) {
+			continue
+		}
+
+		diffs = append(diffs, SyncFieldDiff{Field: field, Snapshot: snapshot[field], PC: pc[field]})
+
+		if base == nil {
+			continue
+		}
+
+		snapshotChanged := !reflect.DeepEqual(base[field], snapshot[field])
+		pcChanged := !reflect.DeepEqual(base[field], pc[field])
+		if snapshotChanged && pcChanged {
+			conflicts = append(conflicts, SyncConflict{
+				Field:    field,
+				Base:     base[field],
+				Snapshot: snapshot[field],
+				PC:       pc[field],
+			})
+		}
+	}
+
+	return diffs, conflicts
+}
+
+// MergeSyncStates faz o merge de três vias contra a base do último sync: só os campos
+// alterados na origem desde a base vão para o destino, preservando as edições feitas apenas
+// no destino. Sem base (primeiro sync), todo campo diferente vem da origem. Campos alterados
+// nos dois lados só são levados com force.
+//
+// Na nova base, os campos que ficaram iguais nos dois lados assumem o valor comum; os que
+// continuam diferentes mantêm a base anterior, para que a edição do destino siga visível
+// como alteração daquele lado no próximo sync.
+func MergeSyncStates(source, target, base JSONB, force bool) SyncMerge {
+	merge := SyncMerge{Fields: []string{}, Changes: JSONB{}, Base: JSONB{}}
+
+	for _, field := range CharacterSyncFields {
+		if reflect.DeepEqual(source[field], target[field]) {
+			merge.Base[field] = source[field]
+			continue
+		}
+
+		sourceChanged := base == nil || !reflect.DeepEqual(base[field], source[field])
+		targetChanged := base != nil && !reflect.DeepEqual(base[field], target[field])
+		if sourceChanged && (!targetChanged || force) {
+			merge.Fields = append(merge.Fields, field)
+			merge.Changes[field] = source[field]
+			merge.Base[field] = source[field]
+			continue
+		}
+
+		merge.Base[field] = base[field]
+	}
+
+	// class_levels acompanha class e level, que o resumem
+	_, classChanged := merge.Changes["class"]
+	_, levelChanged := merge.Changes["level"]
+	if classChanged || levelChanged {
+		merge.Changes["class_levels"] = source["class_levels"]
+	}
+	if reflect.DeepEqual(merge.Base["class"], source["class"]) && reflect.DeepEqual(merge.Base["level"], source["level"]) {
+		merge.Base["class_levels"] = source["class_levels"]
+	} else if base != nil {
+		merge.Base["class_levels"] = base["class_levels"]
+	}
+
+	return merge
+}
+
+// ApplySyncChanges copia para o snapshot apenas os campos trazidos pelo merge
+func (c *CampaignCharacter) ApplySyncChanges(changes JSONB) error {
+	return applyJSON(changes, c)
+}
+
+// ApplySyncChanges copia para o PC apenas os campos trazidos pelo merge
+func (pc *PC) ApplySyncChanges(changes JSONB) error {
+	return applyJSON(changes, pc)
+}
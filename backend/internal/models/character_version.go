@@ -0,0 +1,180 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// Tipos de personagem versionados
+const (
+	CharacterTypePC       = "pc"
+	CharacterTypeCampaign = "campaign_character"
+)
+
+// Origem de uma versão: o que alterou o personagem
+const (
+	VersionActionCreate   = "create"
+	VersionActionUpdate   = "update"
+	VersionActionSync     = "sync"
+	VersionActionRevert   = "revert"
+	VersionActionLoot     = "loot"
+	VersionActionXP       = "xp"
+	VersionActionRetire   = "retire"
+	VersionActionStatus   = "status"
+	VersionActionImport   = "import"
+	VersionActionFreeze   = "freeze"
+	VersionActionLevelUp  = "level_up"
+	VersionActionBaseline = "baseline" // Estado anterior de um personagem criado antes do histórico
+)
+
+// FieldChange é a alteração de um campo entre duas versões
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   any    `json:"old"`
+	New   any    `json:"new"`
+}
+
+// FieldChanges é a lista de alterações de uma versão, guardada em JSONB
+type FieldChanges []FieldChange
+
+func (c FieldChanges) Value() (driver.Value, error) {
+	if c == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal([]FieldChange(c))
+	return string(data), err
+}
+
+func (c *FieldChanges) Scan(value any) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		if value == nil {
+			*c = FieldChanges{}
+			return nil
+		}
+		return errors.New("failed to unmarshal field changes")
+	}
+	result := FieldChanges{}
+	if err := json.Unmarshal(bytes, &result); err != nil {
+		return err
+	}
+	*c = result
+	return nil
+}
+
+// CharacterVersion é o estado de um PC ou personagem de campanha após uma alteração.
+// A versão 1 guarda o estado inicial; as seguintes guardam também o diff para a anterior.
+type CharacterVersion struct {
+	ID            int          `json:"id" db:"id"`
+	CharacterType string       `json:"character_type" db:"character_type"`
+	CharacterID   int          `json:"character_id" db:"character_id"`
+	CampaignID    *int         `json:"campaign_id,omitempty" db:"campaign_id"`
+	Version       int          `json:"version" db:"version"`
+	Action        string       `json:"action" db:"action"`
+	AuthorID      *int         `json:"author_id" db:"author_id"`
+	AuthorName    string       `json:"author_name,omitempty" db:"author_name"`
+	CharacterName string       `json:"character_name" db:"character_name"`
+	Changes       FieldChanges `json:"changes" db:"changes"`
+	Snapshot      JSONB        `json:"snapshot,omitempty" db:"snapshot"`
+	CreatedAt     time.Time    `json:"created_at" db:"created_at"`
+}
+
+// VersionDiff compara duas versões quaisquer do mesmo personagem
+type VersionDiff struct {
+	CharacterType string        `json:"character_type"`
+	CharacterID   int           `json:"character_id"`
+	From          int           `json:"from"`
+	To            int           `json:"to"`
+	Changes       []FieldChange `json:"changes"`
+}
+
+// VersionState retorna o estado versionado do snapshot: campos compartilhados com o PC e
+// os específicos da campanha
+func (c *CampaignCharacter) VersionState() JSONB {
+	state := c.SyncState()
+	extra := struct {
+		CurrentHP        *int   `json:"current_hp"`
+		Status           string `json:"status"`
+		CampaignNotes    string `json:"campaign_notes"`
+		ExperiencePoints int    `json:"experience_points"`
+		LevelUpAvailable bool   `json:"level_up_available"`
+	}{c.CurrentHP, c.Status, c.CampaignNotes, c.ExperiencePoints, c.LevelUpAvailable}
+	mergeJSON(state, extra)
+	return state
+}
+
+// VersionState retorna o estado versionado do PC
+func (pc *PC) VersionState() JSONB {
+	state := pc.SyncState()
+	extra := struct {
+		CurrentHP *int `json:"current_hp"`
+		IsUnique  bool `json:"is_unique"`
+	}{pc.CurrentHP, pc.IsUnique}
+	mergeJSON(state, extra)
+	return state
+}
+
+// ApplyVersionState restaura no snapshot os campos de uma versão. Versões anteriores à
+// multiclasse não têm class_levels: as classes voltam a ser lidas de class/level.
+func (c *CampaignCharacter) ApplyVersionState(state JSONB) error {
+	if _, ok := state["class_levels"]; !ok {
+		c.ClassLevels = nil
+	}
+	return applyJSON(state, c)
+}
+
+// ApplyVersionState restaura no PC os campos de uma versão
+func (pc *PC) ApplyVersionState(state JSONB) error {
+	if _, ok := state["class_levels"]; !ok {
+		pc.ClassLevels = nil
+	}
+	return applyJSON(state, pc)
+}
+
+// DiffVersionStates compara dois estados campo a campo, em ordem alfabética
+func DiffVersionStates(from, to JSONB) []FieldChange {
+	fields := map[string]bool{}
+	for field := range from {
+		fields[field] = true
+	}
+	for field := range to {
+		fields[field] = true
+	}
+
+	names := make([]string, 0, len(fields))
+	for field := range fields {
+		names = append(names, field)
+	}
+	sort.Strings(names)
+
+	changes := []FieldChange{}
+	for _, field := range names {
+		if reflect.DeepEqual(from[field], to[field]) {
+			continue
+		}
+		changes = append(changes, FieldChange{Field: field, Old: from[field], New: to[field]})
+	}
+	return changes
+}
+
+// mergeJSON acrescenta a state os campos de extra, normalizados via JSON
+func mergeJSON(state JSONB, extra any) {
+	data, err := json.Marshal(extra)
+	if err != nil {
+		return
+	}
+	_ = json.Unmarshal(data, &state)
+}
+
+// applyJSON copia para target apenas os campos presentes em state (chaves = tags JSON)
+func applyJSON(state JSONB, target any) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
@@ -0,0 +1,44 @@
+package models
+
+import "testing"
+
+func TestDiffVersionStates(t *testing.T) {
+	from := JSONB{"name": "Aria", "level": 1.0, "features": []any{"Darkvision"}}
+	to := JSONB{"name": "Aria", "level": 2.0, "features": []any{"Darkvision", "Arcane Recovery"}, "status": "active"}
+
+	changes := DiffVersionStates(from, to)
+	if len(changes) != 3 {
+		t.Fatalf("expected 3 changes, got %+v", changes)
+	}
+	if changes[0].Field != "features" || changes[1].Field != "level" || changes[2].Field != "status" {
+		t.Fatalf("expected changes sorted by field, got %+v", changes)
+	}
+	if changes[2].Old != nil || changes[2].New != "active" {
+		t.Fatalf("expected new field to appear with nil old value, got %+v", changes[2])
+	}
+
+	if len(DiffVersionStates(from, from)) != 0 {
+		t.Fatalf("expected no changes between equal states")
+	}
+}
+
+func TestCampaignCharacterApplyVersionState(t *testing.T) {
+	hp := 8
+	original := CampaignCharacter{ID: 5, Name: "Aria", Level: 1, CurrentHP: &hp, Status: "active", ExperiencePoints: 100}
+	state := original.VersionState()
+
+	current := CampaignCharacter{ID: 5, PlayerID: 7, Name: "Aria the Bold", Level: 3, Status: "dead", ExperiencePoints: 1000}
+	if err := current.ApplyVersionState(state); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if current.Name != "Aria" || current.Level != 1 || current.Status != "active" || current.ExperiencePoints != 100 {
+		t.Fatalf("expected versioned fields restored, got %+v", current)
+	}
+	if current.CurrentHP == nil || *current.CurrentHP != 8 {
+		t.Fatalf("expected current HP restored, got %v", current.CurrentHP)
+	}
+	if current.ID != 5 || current.PlayerID != 7 {
+		t.Fatalf("identity fields must be kept, got id=%d player=%d", current.ID, current.PlayerID)
+	}
+}
@@ -0,0 +1,556 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Atributos na ordem da ficha
+var AbilityNames = []string{"strength", "dexterity", "constitution", "intelligence", "wisdom", "charisma"}
+
+// abilityAliases normaliza as formas como os atributos aparecem no SRD e nas tabelas homebrew
+// ("str", "STR", "Strength") para o nome usado nas fichas
+var abilityAliases = map[string]string{
+	"str": "strength", "dex": "dexterity", "con": "constitution",
+	"int": "intelligence", "wis": "wisdom", "cha": "charisma",
+}
+
+// Fontes das regras usadas no cálculo da ficha
+const (
+	RulesSourceSRD      = "srd"
+	RulesSourceHomebrew = "homebrew"
+	RulesSourceDefault  = "default" // Não encontrada: usa os valores padrão
+)
+
+// DefaultHitDie é o dado de vida usado quando a classe não é encontrada
+const DefaultHitDie = 8
+
+// SkillDefinition liga uma perícia do SRD ao atributo dela. Label é o nome usado pelo editor
+// de fichas, que também serve de chave em pc.skills.
+type SkillDefinition struct {
+	Index   string
+	Name    string
+	Label   string
+	Ability string
+}
+
+// Skills lista as perícias do SRD
+var Skills = []SkillDefinition{
+	{"acrobatics", "Acrobatics", "Acrobacia", "dexterity"},
+	{"animal-handling", "Animal Handling", "Lidar com Animais", "wisdom"},
+	{"arcana", "Arcana", "Arcanismo", "intelligence"},
+	{"athletics", "Athletics", "Atletismo", "strength"},
+	{"deception", "Deception", "Blefar", "charisma"},
+	{"history", "History", "História", "intelligence"},
+	{"insight", "Insight", "Intuição", "wisdom"},
+	{"intimidation", "Intimidation", "Intimidação", "charisma"},
+	{"investigation", "Investigation", "Investigação", "intelligence"},
+	{"medicine", "Medicine", "Medicina", "wisdom"},
+	{"nature", "Nature", "Natureza", "intelligence"},
+	{"perception", "Perception", "Percepção", "wisdom"},
+	{"performance", "Performance", "Atuação", "charisma"},
+	{"persuasion", "Persuasion", "Persuasão", "charisma"},
+	{"religion", "Religion", "Religião", "intelligence"},
+	{"sleight-of-hand", "Sleight of Hand", "Prestidigitação", "dexterity"},
+	{"stealth", "Stealth", "Furtividade", "dexterity"},
+	{"survival", "Survival", "Sobrevivência", "wisdom"},
+}
+
+// RaceRules são os dados da raça que entram no cálculo da ficha
+type RaceRules struct {
+	Name           string         `json:"name"`
+	Source         string         `json:"source"`
+	Speed          int            `json:"speed"`
+	AbilityBonuses map[string]int `json:"ability_bonuses,omitempty"` // Informativo: os atributos da ficha já os incluem
+	Skills         []string       `json:"skills,omitempty"`          // Perícias concedidas pela raça (índices do SRD)
+}
+
+// ClassRules são os dados da classe que entram no cálculo da ficha
+type ClassRules struct {
+	Name                string   `json:"name"`
+	Index               string   `json:"index,omitempty"` // api_index do SRD
+	Source              string   `json:"source"`
+	HitDie              int      `json:"hit_die"`
+	SavingThrows        []string `json:"saving_throws"`
+	SpellcastingAbility string   `json:"spellcasting_ability,omitempty"`
+	SkillChoiceCount    int      `json:"skill_choice_count,omitempty"` // Quantas perícias escolher
+	SkillOptions        []string `json:"skill_options,omitempty"`      // Índices do SRD
+	CasterProgression   string   `json:"caster_progression,omitempty"` // full, half, third ou pact
+}
+
+// ArmorRules é uma armadura (ou escudo) equipada, no formato de armor_class do SRD
+type ArmorRules struct {
+	Name     string `json:"name"`
+	Category string `json:"category"` // Light, Medium, Heavy ou Shield
+	Base     int    `json:"base"`
+	DexBonus bool   `json:"dex_bonus"`
+	MaxBonus *int   `json:"max_bonus,omitempty"`
+}
+
+// IsShield indica se a peça é um escudo, que soma à CA em vez de defini-la
+func (a ArmorRules) IsShield() bool {
+	return strings.EqualFold(a.Category, "shield")
+}
+
+// CharacterRules reúne as regras resolvidas para um personagem. Class é a classe inicial;
+// Classes tem as regras de cada entrada de pc.Classes(), na mesma ordem.
+type CharacterRules struct {
+	Race    RaceRules
+	Class   ClassRules
+	Classes []ClassRules
+	Armor   []ArmorRules
+}
+
+// SavingThrowBonus é o bônus de um teste de resistência
+type SavingThrowBonus struct {
+	Bonus      int  `json:"bonus"`
+	Proficient bool `json:"proficient"`
+}
+
+// SkillBonus é o bônus de uma perícia
+type SkillBonus struct {
+	Name       string `json:"name"`
+	Ability    string `json:"ability"`
+	Bonus      int    `json:"bonus"`
+	Proficient bool   `json:"proficient"`
+	Expertise  bool   `json:"expertise"`
+}
+
+// SpellcastingStats são a CD e o bônus de ataque de magia
+type SpellcastingStats struct {
+	Ability     string `json:"ability"`
+	SaveDC      int    `json:"save_dc"`
+	AttackBonus int    `json:"attack_bonus"`
+}
+
+// ComputedSheet é a ficha calculada a partir das regras. Os atributos são lidos como estão na
+// ficha, que já inclui os bônus raciais (o editor e o gerador gravam os valores finais).
+type ComputedSheet struct {
+	PCID              int                         `json:"pc_id"`
+	Level             int                         `json:"level"`
+	Race              RaceRules                   `json:"race"`
+	Class             ClassRules                  `json:"class"`
+	Attributes        map[string]int              `json:"attributes"`
+	AbilityModifiers  map[string]int              `json:"ability_modifiers"`
+	ProficiencyBonus  int                         `json:"proficiency_bonus"`
+	SavingThrows      map[string]SavingThrowBonus `json:"saving_throws"`
+	Skills            map[string]SkillBonus       `json:"skills"`
+	PassivePerception int                         `json:"passive_perception"`
+	ArmorClass        int                         `json:"armor_class"`
+	Armor             []string                    `json:"armor"`
+	MaxHP             int                         `json:"max_hp"`
+	Initiative        int                         `json:"initiative"`
+	Speed             int                         `json:"speed"`
+	Spellcasting      *SpellcastingStats          `json:"spellcasting,omitempty"`
+	// Só para multiclasse: classes, espaços de magia combinados e Magia de Pacto
+	Classes    ClassLevels    `json:"classes,omitempty"`
+	SpellSlots map[string]int `json:"spell_slots,omitempty"`
+	PactMagic  *PactMagic     `json:"pact_magic,omitempty"`
+}
+
+// NormalizeAbility converte "str", "STR" ou "Strength" no nome do atributo; retorna "" se
+// não reconhecer
+func NormalizeAbility(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if full, ok := abilityAliases[value]; ok {
+		return full
+	}
+	for _, name := range AbilityNames {
+		if value == name {
+			return name
+		}
+	}
+	return ""
+}
+
+// ComputeSheet calcula os valores derivados da ficha a partir das regras resolvidas
+func ComputeSheet(pc *PC, rules CharacterRules) ComputedSheet {
+	level := pc.Level
+	if level < 1 {
+		level = 1
+	}
+	proficiency := (&PC{Level: level}).GetProficiencyBonus()
+
+	sheet := ComputedSheet{
+		PCID:             pc.ID,
+		Level:            level,
+		Race:             rules.Race,
+		Class:            rules.Class,
+		Attributes:       pc.AttributeScores(),
+		AbilityModifiers: make(map[string]int, len(AbilityNames)),
+		ProficiencyBonus: proficiency,
+		SavingThrows:     make(map[string]SavingThrowBonus, len(AbilityNames)),
+		Skills:           make(map[string]SkillBonus, len(Skills)),
+		Armor:            []string{},
+		Speed:            rules.Race.Speed,
+	}
+
+	for _, ability := range AbilityNames {
+		sheet.AbilityModifiers[ability] = CalculateModifier(sheet.Attributes[ability])
+	}
+	mod := sheet.AbilityModifiers
+
+	for _, ability := range AbilityNames {
+		save := SavingThrowBonus{Bonus: mod[ability]}
+		for _, st := range rules.Class.SavingThrows {
+			if NormalizeAbility(st) == ability {
+				save.Proficient = true
+				save.Bonus += proficiency
+				break
+			}
+		}
+		sheet.SavingThrows[ability] = save
+	}
+
+	racial := make(map[string]bool, len(rules.Race.Skills))
+	for _, skill := range rules.Race.Skills {
+		racial[skill] = true
+	}
+	for _, def := range Skills {
+		proficient, expertise, extra := pc.skillEntry(def)
+		proficient = proficient || racial[def.Index]
+		skill := SkillBonus{
+			Name:       def.Name,
+			Ability:    def.Ability,
+			Bonus:      mod[def.Ability] + extra,
+			Proficient: proficient,
+			Expertise:  expertise,
+		}
+		if proficient || expertise {
+			skill.Bonus += proficiency
+		}
+		if expertise {
+			skill.Bonus += proficiency
+		}
+		sheet.Skills[def.Index] = skill
+	}
+	sheet.PassivePerception = 10 + sheet.Skills["perception"].Bonus
+
+	sheet.ArmorClass, sheet.Armor = armorClass(rules.Armor, mod["dexterity"])
+
+	hitDie := rules.Class.HitDie
+	if hitDie <= 0 {
+		hitDie = DefaultHitDie
+	}
+	sheet.MaxHP = MaxHitPoints(hitDie, level, mod["constitution"])
+
+	spellcastingAbility := rules.Class.SpellcastingAbility
+	if classes := pc.Classes(); len(classes) > 1 {
+		sheet.Classes = classes
+		sheet.MaxHP = MulticlassHitPoints(classes, rules.Classes, mod["constitution"])
+		sheet.SpellSlots, sheet.PactMagic = CombinedSpellSlots(classes, rules.Classes)
+		// A CD e o ataque de magia usam o atributo da primeira classe conjuradora
+		for _, class := range rules.Classes {
+			if spellcastingAbility != "" {
+				break
+			}
+			spellcastingAbility = class.SpellcastingAbility
+		}
+	}
+
+	sheet.Initiative = mod["dexterity"]
+
+	if ability := NormalizeAbility(spellcastingAbility); ability != "" {
+		sheet.Spellcasting = &SpellcastingStats{
+			Ability:     ability,
+			SaveDC:      8 + proficiency + mod[ability],
+			AttackBonus: proficiency + mod[ability],
+		}
+	}
+
+	return sheet
+}
+
+// MaxHitPoints calcula os PV máximos pelo dado de vida: valor máximo no 1º nível e a média
+// arredondada para cima nos seguintes, sempre somando o modificador de Constituição (mínimo
+// de 1 PV por nível)
+func MaxHitPoints(hitDie, level, conModifier int) int {
+	total := 0
+	for l := 1; l <= level; l++ {
+		gain := hitDie/2 + 1
+		if l == 1 {
+			gain = hitDie
+		}
+		total += max(gain+conModifier, 1)
+	}
+	return total
+}
+
+// armorClass escolhe a melhor armadura equipada e soma o escudo. Sem armadura, a CA é 10 + Des.
+func armorClass(pieces []ArmorRules, dexModifier int) (int, []string) {
+	best := 10 + dexModifier
+	var bestName string
+	shield := 0
+	var shieldName string
+
+	for _, piece := range pieces {
+		if piece.IsShield() {
+			if piece.Base > shield {
+				shield, shieldName = piece.Base, piece.Name
+			}
+			continue
+		}
+		ac := piece.Base
+		if piece.DexBonus {
+			dex := dexModifier
+			if piece.MaxBonus != nil && dex > *piece.MaxBonus {
+				dex = *piece.MaxBonus
+			}
+			ac += dex
+		}
+		if bestName == "" || ac > best {
+			best, bestName = ac, piece.Name
+		}
+	}
+
+	worn := []string{}
+	if bestName != "" {
+		worn = append(worn, bestName)
+	}
+	if shieldName != "" {
+		worn = append(worn, shieldName)
+	}
+	return best + shield, worn
+}
+
+// ApplyTo preenche na ficha os campos que o cálculo substitui: PV máximos, CA e bônus de
+// proficiência. Os PV atuais não passam do novo máximo.
+func (s ComputedSheet) ApplyTo(pc *PC) {
+	pc.HP = s.MaxHP
+	pc.CA = s.ArmorClass
+	pc.ProficiencyBonus = s.ProficiencyBonus
+	if pc.CurrentHP != nil && *pc.CurrentHP > pc.HP {
+		hp := pc.HP
+		pc.CurrentHP = &hp
+	}
+}
+
+// AttributeScores lê os atributos da ficha; os ausentes valem 10
+func (pc *PC) AttributeScores() map[string]int {
+	scores := make(map[string]int, len(AbilityNames))
+	attributes, _ := pc.Attributes.Data.(map[string]any)
+	for _, ability := range AbilityNames {
+		score, ok := jsonInt(attributes[ability])
+		if !ok {
+			score = 10
+		}
+		scores[ability] = score
+	}
+	return scores
+}
+
+// EquippedItemNames lista os nomes dos itens equipados ({name, equipped} em pc.equipment)
+func (pc *PC) EquippedItemNames() []string {
+	items, _ := pc.Equipment.Data.([]any)
+	names := []string{}
+	for _, raw := range items {
+		item, ok := raw.(map[string]any)
+		if !ok {
+			continue
+		}
+		equipped, _ := item["equipped"].(bool)
+		name, _ := item["name"].(string)
+		if equipped && strings.TrimSpace(name) != "" {
+			names = append(names, strings.TrimSpace(name))
+		}
+	}
+	return names
+}
+
+// skillEntry lê a perícia em pc.skills, aceitando como chave o índice do SRD, o nome em
+// inglês ou o rótulo do editor
+func (pc *PC) skillEntry(def SkillDefinition) (proficient, expertise bool, bonus int) {
+	skills, _ := pc.Skills.Data.(map[string]any)
+	for _, key := range []string{def.Index, def.Name, def.Label} {
+		entry, ok := skills[key].(map[string]any)
+		if !ok {
+			continue
+		}
+		proficient, _ = entry["proficient"].(bool)
+		expertise, _ = entry["expertise"].(bool)
+		bonus, _ = jsonInt(entry["bonus"])
+		return proficient, expertise, bonus
+	}
+	return false, false, 0
+}
+
+// RaceRulesFromSRD extrai as regras de uma raça do SRD (ability_bonuses e proficiencies no
+// formato da API: [{ability_score: {index}, bonus}] e [{index: "skill-perception"}])
+func RaceRulesFromSRD(race *DnDRace) RaceRules {
+	rules := RaceRules{Name: race.Name, Source: RulesSourceSRD, Speed: race.Speed, AbilityBonuses: map[string]int{}}
+
+	bonuses, _ := race.AbilityBonuses.Data.([]any)
+	for _, raw := range bonuses {
+		entry, _ := raw.(map[string]any)
+		score, _ := entry["ability_score"].(map[string]any)
+		index, _ := score["index"].(string)
+		bonus, ok := jsonInt(entry["bonus"])
+		if ability := NormalizeAbility(index); ability != "" && ok {
+			rules.AbilityBonuses[ability] += bonus
+		}
+	}
+
+	proficiencies, _ := race.Proficiencies.Data.([]any)
+	for _, raw := range proficiencies {
+		entry, _ := raw.(map[string]any)
+		index, _ := entry["index"].(string)
+		if skill, ok := strings.CutPrefix(index, "skill-"); ok {
+			rules.Skills = append(rules.Skills, skill)
+		}
+	}
+
+	return rules
+}
+
+// RaceRulesFromHomebrew extrai as regras de uma raça homebrew (abilities: {strength: 2},
+// proficiencies: {skills: [...]})
+func RaceRulesFromHomebrew(race *HomebrewRace) RaceRules {
+	rules := RaceRules{Name: race.Name, Source: RulesSourceHomebrew, Speed: race.Speed, AbilityBonuses: map[string]int{}}
+
+	abilities, _ := race.Abilities.Data.(map[string]any)
+	for key, value := range abilities {
+		bonus, ok := jsonInt(value)
+		if ability := NormalizeAbility(key); ability != "" && ok {
+			rules.AbilityBonuses[ability] += bonus
+		}
+	}
+
+	proficiencies, _ := race.Proficiencies.Data.(map[string]any)
+	skills, _ := proficiencies["skills"].([]any)
+	for _, raw := range skills {
+		name, _ := raw.(string)
+		if skill := FindSkill(name); skill != nil {
+			rules.Skills = append(rules.Skills, skill.Index)
+		}
+	}
+
+	return rules
+}
+
+// ClassRulesFromSRD extrai as regras de uma classe do SRD. O atributo de conjuração vem da
+// coluna spellcasting_ability ou, se vazia, de spellcasting.spellcasting_ability.index.
+func ClassRulesFromSRD(class *DnDClass) ClassRules {
+	rules := ClassRules{
+		Name:                class.Name,
+		Index:               class.APIIndex,
+		Source:              RulesSourceSRD,
+		HitDie:              class.HitDie,
+		SavingThrows:        normalizeAbilities(class.SavingThrows),
+		SpellcastingAbility: NormalizeAbility(class.SpellcastingAbility),
+		CasterProgression:   CasterProgressionFor(class.APIIndex),
+	}
+
+	if rules.SpellcastingAbility == "" {
+		spellcasting, _ := class.Spellcasting.Data.(map[string]any)
+		ability, _ := spellcasting["spellcasting_ability"].(map[string]any)
+		index, _ := ability["index"].(string)
+		rules.SpellcastingAbility = NormalizeAbility(index)
+	}
+
+	// A escolha de perícias é a de proficiency_choices cujas opções são skill-*
+	choices, _ := class.ProficiencyChoices.Data.([]any)
+	for _, raw := range choices {
+		choice, _ := raw.(map[string]any)
+		from, _ := choice["from"].(map[string]any)
+		options, _ := from["options"].([]any)
+		skills := []string{}
+		for _, rawOption := range options {
+			option, _ := rawOption.(map[string]any)
+			item, _ := option["item"].(map[string]any)
+			index, _ := item["index"].(string)
+			if skill, ok := strings.CutPrefix(index, "skill-"); ok {
+				skills = append(skills, skill)
+			}
+		}
+		if count, ok := jsonInt(choice["choose"]); ok && len(skills) > 0 {
+			rules.SkillChoiceCount, rules.SkillOptions = count, skills
+			break
+		}
+	}
+
+	return rules
+}
+
+// ClassRulesFromHomebrew extrai as regras de uma classe homebrew (spellcasting: {ability: "int",
+// progression: "full" | "half" | "third" | "pact"})
+func ClassRulesFromHomebrew(class *HomebrewClass) ClassRules {
+	rules := ClassRules{
+		Name:         class.Name,
+		Source:       RulesSourceHomebrew,
+		HitDie:       class.HitDie,
+		SavingThrows: normalizeAbilities(class.SavingThrows),
+	}
+
+	spellcasting, _ := class.Spellcasting.Data.(map[string]any)
+	ability, _ := spellcasting["ability"].(string)
+	rules.SpellcastingAbility = NormalizeAbility(ability)
+	progression, _ := spellcasting["progression"].(string)
+	switch progression = strings.ToLower(progression); progression {
+	case CasterFull, CasterHalf, CasterThird, CasterPact:
+		rules.CasterProgression = progression
+	}
+
+	choices, _ := class.SkillChoices.Data.(map[string]any)
+	options, _ := choices["options"].([]any)
+	for _, raw := range options {
+		name, _ := raw.(string)
+		if skill := FindSkill(name); skill != nil {
+			rules.SkillOptions = append(rules.SkillOptions, skill.Index)
+		}
+	}
+	rules.SkillChoiceCount, _ = jsonInt(choices["count"])
+
+	return rules
+}
+
+// ArmorRulesFromSRD extrai a CA de uma armadura do SRD (armor_class: {base, dex_bonus,
+// max_bonus}); ok é false se o item não for armadura
+func ArmorRulesFromSRD(item *DnDEquipment) (ArmorRules, bool) {
+	ac, _ := item.ArmorClass.Data.(map[string]any)
+	base, ok := jsonInt(ac["base"])
+	if item.ArmorCategory == "" || !ok {
+		return ArmorRules{}, false
+	}
+
+	armor := ArmorRules{Name: item.Name, Category: item.ArmorCategory, Base: base}
+	armor.DexBonus, _ = ac["dex_bonus"].(bool)
+	if maxBonus, ok := jsonInt(ac["max_bonus"]); ok {
+		armor.MaxBonus = &maxBonus
+	}
+	return armor, true
+}
+
+// FindSkill encontra a perícia pelo índice do SRD, nome em inglês ou rótulo do editor
+func FindSkill(name string) *SkillDefinition {
+	name = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(name)), "skill-")
+	for i, def := range Skills {
+		if name == def.Index || name == strings.ToLower(def.Name) || name == strings.ToLower(def.Label) {
+			return &Skills[i]
+		}
+	}
+	return nil
+}
+
+func normalizeAbilities(values []string) []string {
+	abilities := []string{}
+	for _, value := range values {
+		if ability := NormalizeAbility(value); ability != "" {
+			abilities = append(abilities, ability)
+		}
+	}
+	return abilities
+}
+
+// jsonInt lê um número vindo de JSON (float64) ou montado em Go (int)
+func jsonInt(value any) (int, bool) {
+	switch v := value.(type) {
+	case float64:
+		return int(v), true
+	case int:
+		return v, true
+	case json.Number:
+		n, err := v.Int64()
+		return int(n), err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,195 @@
+package models
+
+import "testing"
+
+func wizardPC() *PC {
+	return &PC{
+		ID:    1,
+		Level: 5,
+		Attributes: JSONBFlexible{Data: map[string]any{
+			"strength": 8, "dexterity": 14, "constitution": 13,
+			"intelligence": 18, "wisdom": 12, "charisma": 10,
+		}},
+		Skills: JSONBFlexible{Data: map[string]any{
+			"Arcanismo": map[string]any{"proficient": true, "expertise": false, "bonus": 0},
+			"history":   map[string]any{"proficient": true, "expertise": true, "bonus": 1},
+		}},
+	}
+}
+
+func TestComputeSheet(t *testing.T) {
+	rules := CharacterRules{
+		Race:  RaceRules{Name: "Elf", Speed: 30, Skills: []string{"perception"}},
+		Class: ClassRules{Name: "Wizard", HitDie: 6, SavingThrows: []string{"int", "WIS"}, SpellcastingAbility: "INT"},
+	}
+
+	sheet := ComputeSheet(wizardPC(), rules)
+
+	if sheet.ProficiencyBonus != 3 {
+		t.Fatalf("expected proficiency 3 at level 5, got %d", sheet.ProficiencyBonus)
+	}
+	if sheet.AbilityModifiers["strength"] != -1 || sheet.AbilityModifiers["intelligence"] != 4 {
+		t.Fatalf("unexpected modifiers: %+v", sheet.AbilityModifiers)
+	}
+	if save := sheet.SavingThrows["intelligence"]; !save.Proficient || save.Bonus != 7 {
+		t.Fatalf("expected proficient INT save +7, got %+v", save)
+	}
+	if save := sheet.SavingThrows["dexterity"]; save.Proficient || save.Bonus != 2 {
+		t.Fatalf("expected DEX save +2, got %+v", save)
+	}
+	if skill := sheet.Skills["arcana"]; !skill.Proficient || skill.Bonus != 7 {
+		t.Fatalf("expected arcana +7 from the editor label, got %+v", skill)
+	}
+	if skill := sheet.Skills["history"]; skill.Bonus != 11 {
+		t.Fatalf("expected history +11 with expertise and bonus, got %+v", skill)
+	}
+	// Percepção vem da raça: Sab +1 + proficiência 3
+	if sheet.PassivePerception != 14 {
+		t.Fatalf("expected passive perception 14, got %d", sheet.PassivePerception)
+	}
+	if sheet.ArmorClass != 12 || len(sheet.Armor) != 0 {
+		t.Fatalf("expected unarmored AC 12, got %d %v", sheet.ArmorClass, sheet.Armor)
+	}
+	// 6 + 4 × (4) com Con +1 em todos os níveis
+	if sheet.MaxHP != 27 {
+		t.Fatalf("expected 27 max HP, got %d", sheet.MaxHP)
+	}
+	if sheet.Initiative != 2 {
+		t.Fatalf("expected initiative +2, got %d", sheet.Initiative)
+	}
+	if sheet.Spellcasting == nil || sheet.Spellcasting.SaveDC != 15 || sheet.Spellcasting.AttackBonus != 7 {
+		t.Fatalf("unexpected spellcasting: %+v", sheet.Spellcasting)
+	}
+}
+
+func TestComputeSheet_Armor(t *testing.T) {
+	two := 2
+	pc := wizardPC()
+
+	cases := []struct {
+		name  string
+		armor []ArmorRules
+		want  int
+	}{
+		{"light", []ArmorRules{{Name: "Leather Armor", Category: "Light", Base: 11, DexBonus: true}}, 13},
+		{"medium caps dex", []ArmorRules{{Name: "Scale Mail", Category: "Medium", Base: 14, DexBonus: true, MaxBonus: &two}}, 16},
+		{"heavy ignores dex", []ArmorRules{{Name: "Chain Mail", Category: "Heavy", Base: 16}}, 16},
+		{"shield only", []ArmorRules{{Name: "Shield", Category: "Shield", Base: 2}}, 14},
+		{"best armor plus shield", []ArmorRules{
+			{Name: "Leather Armor", Category: "Light", Base: 11, DexBonus: true},
+			{Name: "Chain Mail", Category: "Heavy", Base: 16},
+			{Name: "Shield", Category: "Shield", Base: 2},
+		}, 18},
+	}
+
+	for _, tc := range cases {
+		sheet := ComputeSheet(pc, CharacterRules{Armor: tc.armor})
+		if sheet.ArmorClass != tc.want {
+			t.Fatalf("%s: expected AC %d, got %d", tc.name, tc.want, sheet.ArmorClass)
+		}
+	}
+}
+
+func TestComputeSheet_Defaults(t *testing.T) {
+	sheet := ComputeSheet(&PC{Level: 1}, CharacterRules{})
+
+	if sheet.MaxHP != DefaultHitDie || sheet.ArmorClass != 10 || sheet.Spellcasting != nil {
+		t.Fatalf("unexpected defaults: %+v", sheet)
+	}
+	if sheet.Attributes["wisdom"] != 10 || sheet.PassivePerception != 10 {
+		t.Fatalf("missing attributes should default to 10: %+v", sheet.Attributes)
+	}
+}
+
+func TestMaxHitPoints(t *testing.T) {
+	if got := MaxHitPoints(10, 1, 2); got != 12 {
+		t.Fatalf("expected 12 at level 1, got %d", got)
+	}
+	if got := MaxHitPoints(12, 3, 3); got != 15+10+10 {
+		t.Fatalf("expected 35 at level 3, got %d", got)
+	}
+	// Cada nível dá ao menos 1 PV
+	if got := MaxHitPoints(6, 2, -5); got != 2 {
+		t.Fatalf("expected minimum of 1 HP per level, got %d", got)
+	}
+}
+
+func TestComputedSheet_ApplyTo(t *testing.T) {
+	current := 40
+	pc := &PC{HP: 40, CurrentHP: &current, CA: 10}
+
+	ComputedSheet{MaxHP: 27, ArmorClass: 15, ProficiencyBonus: 3}.ApplyTo(pc)
+
+	if pc.HP != 27 || pc.CA != 15 || pc.ProficiencyBonus != 3 || *pc.CurrentHP != 27 {
+		t.Fatalf("unexpected PC after auto-fill: %+v", pc)
+	}
+}
+
+func TestRulesFromSources(t *testing.T) {
+	race := RaceRulesFromSRD(&DnDRace{
+		Name:  "Elf",
+		Speed: 30,
+		AbilityBonuses: JSONBFlexible{Data: []any{
+			map[string]any{"ability_score": map[string]any{"index": "dex"}, "bonus": float64(2)},
+		}},
+		Proficiencies: JSONBFlexible{Data: []any{map[string]any{"index": "skill-perception"}}},
+	})
+	if race.AbilityBonuses["dexterity"] != 2 || len(race.Skills) != 1 || race.Skills[0] != "perception" {
+		t.Fatalf("unexpected SRD race rules: %+v", race)
+	}
+
+	homebrewRace := RaceRulesFromHomebrew(&HomebrewRace{
+		Name:          "Sylvan",
+		Abilities:     JSONBFlexible{Data: map[string]any{"wisdom": float64(2)}},
+		Proficiencies: JSONBFlexible{Data: map[string]any{"skills": []any{"Stealth"}}},
+	})
+	if homebrewRace.Source != RulesSourceHomebrew || homebrewRace.AbilityBonuses["wisdom"] != 2 || homebrewRace.Skills[0] != "stealth" {
+		t.Fatalf("unexpected homebrew race rules: %+v", homebrewRace)
+	}
+
+	class := ClassRulesFromSRD(&DnDClass{
+		Name:         "Cleric",
+		HitDie:       8,
+		SavingThrows: []string{"wis", "cha"},
+		Spellcasting: JSONBFlexible{Data: map[string]any{"spellcasting_ability": map[string]any{"index": "wis"}}},
+	})
+	if class.SpellcastingAbility != "wisdom" || class.SavingThrows[1] != "charisma" {
+		t.Fatalf("unexpected SRD class rules: %+v", class)
+	}
+
+	homebrewClass := ClassRulesFromHomebrew(&HomebrewClass{
+		Name:         "Runesmith",
+		HitDie:       10,
+		SavingThrows: []string{"Strength", "Intelligence"},
+		Spellcasting: JSONBFlexible{Data: map[string]any{"ability": "int"}},
+	})
+	if homebrewClass.SpellcastingAbility != "intelligence" || homebrewClass.SavingThrows[0] != "strength" {
+		t.Fatalf("unexpected homebrew class rules: %+v", homebrewClass)
+	}
+
+	armor, ok := ArmorRulesFromSRD(&DnDEquipment{
+		Name:          "Scale Mail",
+		ArmorCategory: "Medium",
+		ArmorClass:    JSONBFlexible{Data: map[string]any{"base": float64(14), "dex_bonus": true, "max_bonus": float64(2)}},
+	})
+	if !ok || armor.Base != 14 || !armor.DexBonus || armor.MaxBonus == nil || *armor.MaxBonus != 2 {
+		t.Fatalf("unexpected armor rules: %+v", armor)
+	}
+	if _, ok := ArmorRulesFromSRD(&DnDEquipment{Name: "Rope"}); ok {
+		t.Fatal("non-armor items should be ignored")
+	}
+}
+
+func TestEquippedItemNames(t *testing.T) {
+	pc := &PC{Equipment: JSONBFlexible{Data: []any{
+		map[string]any{"name": "Chain Mail", "equipped": true},
+		map[string]any{"name": "Rope", "equipped": false},
+		map[string]any{"name": "Shield", "equipped": true},
+		"loose string",
+	}}}
+
+	names := pc.EquippedItemNames()
+	if len(names) != 2 || names[0] != "Chain Mail" || names[1] != "Shield" {
+		t.Fatalf("unexpected equipped items: %v", names)
+	}
+}
@@ -4,24 +4,24 @@ import "time"
 
 // DiceRollRequest representa uma requisição de rolagem de dados
 type DiceRollRequest struct {
-	Notation   string `json:"notation" binding:"required"` // "2d6+3", "1d20", etc
-	Label      string `json:"label,omitempty"`             // Descrição opcional da rolagem
-	Advantage  bool   `json:"advantage,omitempty"`         // Rolar com vantagem (2d20, pegar maior)
-	Disadvantage bool `json:"disadvantage,omitempty"`      // Rolar com desvantagem (2d20, pegar menor)
+	Notation     string `json:"notation" binding:"required"` // "2d6+3", "1d20", etc
+	Label        string `json:"label,omitempty"`             // Descrição opcional da rolagem
+	Advantage    bool   `json:"advantage,omitempty"`         // Rolar com vantagem (2d20, pegar maior)
+	Disadvantage bool   `json:"disadvantage,omitempty"`      // Rolar com desvantagem (2d20, pegar menor)
 }
 
 // DiceRollResponse representa o resultado de uma rolagem de dados
 type DiceRollResponse struct {
-	Notation     string    `json:"notation"`            // Notação original
-	Quantity     int       `json:"quantity"`            // Quantidade de dados
-	Sides        int       `json:"sides"`               // Número de lados do dado
-	Modifier     int       `json:"modifier"`            // Modificador (+/-)
-	Rolls        []int     `json:"rolls"`               // Resultado de cada dado individual
-	Total        int       `json:"total"`               // Total da rolagem (soma + modificador)
-	Timestamp    time.Time `json:"timestamp"`           // Quando foi rolado
-	Label        string    `json:"label,omitempty"`     // Label opcional
-	Advantage    bool      `json:"advantage,omitempty"` // Se foi rolado com vantagem
-	Disadvantage bool      `json:"disadvantage,omitempty"` // Se foi rolado com desvantagem
+	Notation     string    `json:"notation"`                // Notação original
+	Quantity     int       `json:"quantity"`                // Quantidade de dados
+	Sides        int       `json:"sides"`                   // Número de lados do dado
+	Modifier     int       `json:"modifier"`                // Modificador (+/-)
+	Rolls        []int     `json:"rolls"`                   // Resultado de cada dado individual
+	Total        int       `json:"total"`                   // Total da rolagem (soma + modificador)
+	Timestamp    time.Time `json:"timestamp"`               // Quando foi rolado
+	Label        string    `json:"label,omitempty"`         // Label opcional
+	Advantage    bool      `json:"advantage,omitempty"`     // Se foi rolado com vantagem
+	Disadvantage bool      `json:"disadvantage,omitempty"`  // Se foi rolado com desvantagem
 	DroppedRolls []int     `json:"dropped_rolls,omitempty"` // Dados descartados (vantagem/desvantagem)
 }
 
@@ -30,4 +30,77 @@ type ParsedDice struct {
 	Quantity int // Quantidade de dados
 	Sides    int // Lados do dado
 	Modifier int // Modificador
-}
\ No newline at end of file
+}
+
+// DiceMacro representa uma rolagem salva pelo usuário, opcionalmente vinculada a um PC
+type DiceMacro struct {
+	ID           int       `json:"id" db:"id"`
+	UserID       int       `json:"user_id" db:"user_id"`
+	PCID         *int      `json:"pc_id,omitempty" db:"pc_id"` // nil = macro do usuário
+	Name         string    `json:"name" db:"name"`
+	Notation     string    `json:"notation" db:"notation"`
+	Label        string    `json:"label,omitempty" db:"label"`
+	Advantage    bool      `json:"advantage" db:"advantage"`
+	Disadvantage bool      `json:"disadvantage" db:"disadvantage"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// DiceMacroRequest representa a criação ou atualização de uma macro
+type DiceMacroRequest struct {
+	Name         string `json:"name"`
+	Notation     string `json:"notation"`
+	Label        string `json:"label,omitempty"`
+	Advantage    bool   `json:"advantage,omitempty"`
+	Disadvantage bool   `json:"disadvantage,omitempty"`
+	PCID         *int   `json:"pc_id,omitempty"`
+}
+
+// ToRollRequest converte a macro em uma requisição de rolagem
+func (m *DiceMacro) ToRollRequest() DiceRollRequest {
+	label := m.Label
+	if label == "" {
+		label = m.Name
+	}
+	return DiceRollRequest{
+		Notation:     m.Notation,
+		Label:        label,
+		Advantage:    m.Advantage,
+		Disadvantage: m.Disadvantage,
+	}
+}
+
+// Regras de crítico suportadas pelo ataque
+const (
+	CritRuleDoubleDice  = "double_dice"  // RAW: rola os dados de dano duas vezes
+	CritRuleMaxDice     = "max_dice"     // um conjunto no máximo + um conjunto rolado
+	CritRuleDoubleTotal = "double_total" // dobra o total, incluindo o modificador
+)
+
+// CritRules lista as regras de crítico aceitas
+var CritRules = []string{CritRuleDoubleDice, CritRuleMaxDice, CritRuleDoubleTotal}
+
+// AttackRollRequest representa um ataque: rolagem de acerto + rolagem de dano
+type AttackRollRequest struct {
+	ToHit        string `json:"to_hit"`                 // "1d20+5" ou apenas o bônus ("+5")
+	Damage       string `json:"damage"`                 // "1d8+3"
+	Label        string `json:"label,omitempty"`        // Descrição opcional do ataque
+	Advantage    bool   `json:"advantage,omitempty"`    // Rolar o acerto com vantagem
+	Disadvantage bool   `json:"disadvantage,omitempty"` // Rolar o acerto com desvantagem
+	CritRule     string `json:"crit_rule,omitempty"`    // Padrão: double_dice
+	CritRange    int    `json:"crit_range,omitempty"`   // Menor d20 natural que causa crítico (padrão 20)
+	TargetAC     *int   `json:"target_ac,omitempty"`    // CA do alvo, se conhecida
+}
+
+// AttackRollResponse representa o resultado combinado do ataque
+type AttackRollResponse struct {
+	ToHit       DiceRollResponse `json:"to_hit"`
+	Damage      DiceRollResponse `json:"damage"`
+	NaturalRoll int              `json:"natural_roll"`    // Valor do d20 mantido
+	Critical    bool             `json:"critical"`        // Natural dentro da faixa de crítico
+	Fumble      bool             `json:"fumble"`          // 1 natural
+	Hit         *bool            `json:"hit,omitempty"`   // Só preenchido quando target_ac é informado
+	CritRule    string           `json:"crit_rule"`       // Regra aplicada ao dano
+	Label       string           `json:"label,omitempty"` // Label opcional
+	Timestamp   time.Time        `json:"timestamp"`       // Quando foi rolado
+}
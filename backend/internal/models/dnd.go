@@ -69,6 +69,7 @@ type DnDClass struct {
 	HitDie              int            `json:"hit_die" db:"hit_die"`
 	Proficiencies       JSONBFlexible  `json:"proficiencies" db:"proficiencies"`
 	SavingThrows        pq.StringArray `json:"saving_throws" db:"saving_throws"`
+	ProficiencyChoices  JSONBFlexible  `json:"proficiency_choices" db:"proficiency_choices"`
 	Spellcasting        JSONBFlexible  `json:"spellcasting" db:"spellcasting"`
 	SpellcastingAbility string         `json:"spellcasting_ability" db:"spellcasting_ability"`
 	ClassLevels         JSONBFlexible  `json:"class_levels" db:"class_levels"`
@@ -0,0 +1,78 @@
+package models
+
+// XPThresholds é o XP total necessário para cada nível (índice 0 = nível 1), conforme o SRD 5e
+var XPThresholds = []int{
+	0, 300, 900, 2700, 6500, 14000, 23000, 34000, 48000, 64000,
+	85000, 100000, 120000, 140000, 165000, 195000, 225000, 265000, 305000, 355000,
+}
+
+// MaxCharacterLevel é o nível máximo alcançável por XP ou marco
+const MaxCharacterLevel = 20
+
+// XPForLevel retorna o XP total necessário para alcançar level; false fora de 1..20
+func XPForLevel(level int) (int, bool) {
+	if level < 1 || level > MaxCharacterLevel {
+		return 0, false
+	}
+	return XPThresholds[level-1], true
+}
+
+// LevelForXP retorna o nível correspondente ao XP acumulado
+func LevelForXP(xp int) int {
+	level := 1
+	for i, threshold := range XPThresholds {
+		if xp >= threshold {
+			level = i + 1
+		}
+	}
+	return level
+}
+
+// CanLevelUpWithXP indica se o XP acumulado já basta para passar do nível atual
+func CanLevelUpWithXP(level, xp int) bool {
+	return level < MaxCharacterLevel && LevelForXP(xp) > level
+}
+
+// AwardXPRequest distribui o XP de um encontro da campanha (encounter_id) ou uma quantia
+// avulsa (amount) igualmente entre character_ids
+type AwardXPRequest struct {
+	EncounterID  *int   `json:"encounter_id"`
+	Amount       int    `json:"amount"`
+	CharacterIDs []int  `json:"character_ids"`
+	Reason       string `json:"reason"`
+}
+
+// AwardMilestoneRequest libera a subida de nível de character_ids em campanhas por marco
+type AwardMilestoneRequest struct {
+	CharacterIDs []int  `json:"character_ids"`
+	Reason       string `json:"reason"`
+}
+
+// CharacterProgress é o estado de progressão de um personagem após uma concessão
+type CharacterProgress struct {
+	CharacterID      int    `json:"character_id" db:"id"`
+	Name             string `json:"name" db:"name"`
+	Level            int    `json:"level" db:"level"`
+	XPGained         int    `json:"xp_gained"`
+	ExperiencePoints int    `json:"experience_points" db:"experience_points"`
+	NextLevelXP      *int   `json:"next_level_xp,omitempty"` // nil no nível máximo ou por marco
+	LevelUpAvailable bool   `json:"level_up_available" db:"level_up_available"`
+}
+
+// XPAwardResult resume uma concessão de XP ou marco
+type XPAwardResult struct {
+	LevelingMode string              `json:"leveling_mode"`
+	EncounterID  *int                `json:"encounter_id,omitempty"`
+	TotalXP      int                 `json:"total_xp"`
+	XPPerChar    int                 `json:"xp_per_character"`
+	Reason       string              `json:"reason,omitempty"`
+	Characters   []CharacterProgress `json:"characters"`
+}
+
+// SplitXP divide o XP igualmente; a sobra da divisão é descartada, como no SRD
+func SplitXP(total, characters int) int {
+	if characters <= 0 || total <= 0 {
+		return 0
+	}
+	return total / characters
+}
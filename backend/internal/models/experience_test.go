@@ -0,0 +1,39 @@
+package models
+
+import "testing"
+
+func TestLevelForXP(t *testing.T) {
+	cases := map[int]int{0: 1, 299: 1, 300: 2, 2699: 3, 2700: 4, 354999: 19, 355000: 20, 1000000: 20}
+	for xp, level := range cases {
+		if got := LevelForXP(xp); got != level {
+			t.Fatalf("LevelForXP(%d) = %d, want %d", xp, got, level)
+		}
+	}
+}
+
+func TestCanLevelUpWithXP(t *testing.T) {
+	if !CanLevelUpWithXP(1, 300) {
+		t.Fatalf("300 XP should allow level 2")
+	}
+	if CanLevelUpWithXP(2, 899) {
+		t.Fatalf("899 XP should not allow level 3")
+	}
+	if CanLevelUpWithXP(20, 999999) {
+		t.Fatalf("level 20 cannot level up")
+	}
+	if _, ok := XPForLevel(21); ok {
+		t.Fatalf("level 21 has no threshold")
+	}
+}
+
+func TestSplitXP(t *testing.T) {
+	if got := SplitXP(1100, 4); got != 275 {
+		t.Fatalf("expected 275, got %d", got)
+	}
+	if got := SplitXP(100, 3); got != 33 {
+		t.Fatalf("expected remainder to be dropped, got %d", got)
+	}
+	if got := SplitXP(100, 0); got != 0 {
+		t.Fatalf("expected 0 for no characters, got %d", got)
+	}
+}
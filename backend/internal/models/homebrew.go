@@ -105,6 +105,9 @@ type UpdateHomebrewRaceRequest struct {
 // REQUEST MODELS - CLASSES
 // ========================================
 
+// HomebrewHitDice são os dados de vida aceitos para classes homebrew
+var HomebrewHitDice = []int{6, 8, 10, 12}
+
 type CreateHomebrewClassRequest struct {
 	Name              string        `json:"name" binding:"required"`
 	Description       string        `json:"description" binding:"required"`
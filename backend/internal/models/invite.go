@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// CampaignInvite representa um convite com validade, limite de usos e aprovação opcional
+type CampaignInvite struct {
+	ID               int        `json:"id" db:"id"`
+	CampaignID       int        `json:"campaign_id" db:"campaign_id"`
+	Code             string     `json:"code" db:"code"`
+	CreatedBy        *int       `json:"created_by,omitempty" db:"created_by"`
+	ExpiresAt        *time.Time `json:"expires_at" db:"expires_at"` // nil = não expira
+	MaxUses          int        `json:"max_uses" db:"max_uses"`     // 0 = ilimitado
+	Uses             int        `json:"uses" db:"uses"`
+	RequiresApproval bool       `json:"requires_approval" db:"requires_approval"`
+	AllowWaitlist    bool       `json:"allow_waitlist" db:"allow_waitlist"`
+	Revoked          bool       `json:"revoked" db:"revoked"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+}
+
+// IsExpired indica se o convite já passou da validade
+func (i *CampaignInvite) IsExpired(now time.Time) bool {
+	return i.ExpiresAt != nil && !now.Before(*i.ExpiresAt)
+}
+
+// IsExhausted indica se o convite atingiu o limite de usos
+func (i *CampaignInvite) IsExhausted() bool {
+	return i.MaxUses > 0 && i.Uses >= i.MaxUses
+}
+
+type CreateInviteRequest struct {
+	ExpiresAt        *time.Time `json:"expires_at"`
+	ExpiresInHours   int        `json:"expires_in_hours"` // Alternativa a expires_at
+	MaxUses          int        `json:"max_uses"`
+	RequiresApproval bool       `json:"requires_approval"`
+	AllowWaitlist    bool       `json:"allow_waitlist"`
+}
+
+// JoinCampaignResult descreve o resultado de um pedido de entrada via convite
+type JoinCampaignResult struct {
+	CampaignID int    `json:"campaign_id"`
+	Status     string `json:"status"` // active, pending, waitlisted
+	Message    string `json:"message"`
+}
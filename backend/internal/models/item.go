@@ -9,6 +9,7 @@ type Treasure struct {
 	Level      int       `json:"level" db:"level"`
 	Name       string    `json:"name" db:"name"`
 	TotalValue int       `json:"total_value" db:"total_value"`
+	CampaignID *int      `json:"campaign_id,omitempty" db:"campaign_id"` // inventário do grupo que recebeu o tesouro
 	Hoards     []Hoard   `json:"hoards"`
 	CreatedAt  time.Time `json:"created_at" db:"created_at"`
 }
@@ -0,0 +1,513 @@
+package models
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Como os PV do novo nível são definidos
+const (
+	HPMethodRoll    = "roll"    // rola o dado de vida no servidor
+	HPMethodAverage = "average" // valor fixo: metade do dado + 1
+)
+
+// HPMethods lista os métodos aceitos em hp_method
+var HPMethods = []string{HPMethodRoll, HPMethodAverage}
+
+// Escolhas que um nível pode exigir
+const (
+	LevelUpChoiceASI      = "asi"      // aumento de atributo (+2 em um ou +1 em dois) ou talento
+	LevelUpChoiceSubclass = "subclass" // arquétipo da classe
+	LevelUpChoiceSkills   = "skills"   // perícias ganhas ao entrar em uma classe como multiclasse
+)
+
+// ASIPoints é o total de pontos de um aumento de atributo; MaxAbilityScore é o teto do SRD
+const (
+	ASIPoints       = 2
+	MaxAbilityScore = 20
+)
+
+// abilityScoreImprovement é o nome da característica de aumento de atributo no SRD
+const abilityScoreImprovement = "ability score improvement"
+
+// LevelFeature é uma característica ganha ao subir de nível
+type LevelFeature struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Subclass    string `json:"subclass,omitempty"`
+}
+
+// ClassLevelRules é a progressão da classe em um nível: bônus de proficiência, aumento de
+// atributo, espaços de magia (por círculo) e características, inclusive as de subclasses
+type ClassLevelRules struct {
+	Level                   int            `json:"level"`
+	ProficiencyBonus        int            `json:"proficiency_bonus"`
+	AbilityScoreImprovement bool           `json:"ability_score_improvement"`
+	SpellSlots              map[int]int    `json:"spell_slots,omitempty"`
+	Features                []LevelFeature `json:"features"`
+}
+
+// LevelUpRules reúne o que é preciso para subir um PC de nível em uma classe: dado de vida,
+// progressão do próximo nível da classe e as subclasses disponíveis (com o nível em que são
+// escolhidas). Para multiclasse, Classes tem as regras de cada classe do PC depois da subida,
+// na ordem de class_levels, usadas nos espaços de magia combinados.
+type LevelUpRules struct {
+	Class           string          `json:"class"`
+	Index           string          `json:"index,omitempty"` // api_index do SRD
+	Source          string          `json:"source"`
+	HitDie          int             `json:"hit_die"`
+	Progression     ClassLevelRules `json:"progression"`
+	SubclassOptions []string        `json:"subclass_options,omitempty"`
+	SubclassLevel   int             `json:"subclass_level,omitempty"`
+	SkillOptions    []string        `json:"skill_options,omitempty"` // Perícias da classe (índices do SRD)
+	Classes         []ClassRules    `json:"-"`
+}
+
+// LevelUpChoice é uma escolha que o jogador precisa fazer para subir de nível; Field é o campo
+// do corpo de POST /level-up que a responde
+type LevelUpChoice struct {
+	Type        string   `json:"type"`
+	Field       string   `json:"field"`
+	Description string   `json:"description"`
+	Options     []string `json:"options,omitempty"`
+	Points      int      `json:"points,omitempty"`
+}
+
+// LevelUpPlan é a prévia do próximo nível do PC. FromLevel e ToLevel são o nível total;
+// ClassLevel é o nível alcançado na classe e Classes, as classes do PC depois da subida.
+type LevelUpPlan struct {
+	PCID             int             `json:"pc_id"`
+	Class            string          `json:"class"`
+	ClassLevel       int             `json:"class_level"`
+	NewClass         bool            `json:"new_class,omitempty"` // Entrada em uma nova classe (multiclasse)
+	FromLevel        int             `json:"from_level"`
+	ToLevel          int             `json:"to_level"`
+	Classes          ClassLevels     `json:"classes"`
+	HitDie           int             `json:"hit_die"`
+	AverageHP        int             `json:"average_hp"`
+	ProficiencyBonus int             `json:"proficiency_bonus"`
+	Subclass         string          `json:"subclass,omitempty"`
+	Features         []LevelFeature  `json:"features"`
+	Proficiencies    []string        `json:"proficiencies,omitempty"` // Ganhas como multiclasse
+	SpellSlots       map[string]int  `json:"spell_slots,omitempty"`
+	PactMagic        *PactMagic      `json:"pact_magic,omitempty"`
+	Choices          []LevelUpChoice `json:"choices"`
+}
+
+// LevelUpRequest é o corpo de POST /api/pcs/{id}/level-up. class é a classe que ganha o nível
+// (obrigatória para multiclasse; uma classe nova faz o PC entrar nela); asi e feat respondem
+// à escolha de aumento de atributo (um ou outro); subclass e skills, às escolhas de subclasse
+// e de perícias.
+type LevelUpRequest struct {
+	Class    string         `json:"class"`
+	HPMethod string         `json:"hp_method"`
+	ASI      map[string]int `json:"asi"`
+	Feat     string         `json:"feat"`
+	Subclass string         `json:"subclass"`
+	Skills   []string       `json:"skills"`
+}
+
+// LevelUpResult é o resultado de uma subida de nível aplicada
+type LevelUpResult struct {
+	Plan     LevelUpPlan `json:"plan"`
+	HPMethod string      `json:"hp_method"`
+	HPRoll   *int        `json:"hp_roll,omitempty"`
+	HPGained int         `json:"hp_gained"`
+	PC       PC          `json:"pc"`
+}
+
+// Subclass retorna a subclasse da classe inicial do PC (de class_levels ou, em PCs antigos, de
+// abilities.subclass)
+func (pc *PC) Subclass() string {
+	if classes := pc.Classes(); len(classes) > 0 {
+		return classes[0].Subclass
+	}
+	return legacySubclass(pc.Abilities)
+}
+
+// NewLevelUpPlan monta a prévia do próximo nível do PC na classe de rules. subclass é a
+// subclasse que vale para as características do nível: a atual da classe ou, se ainda não
+// tiver, a escolhida no pedido.
+func NewLevelUpPlan(pc *PC, rules LevelUpRules, subclass string) LevelUpPlan {
+	classes := slices.Clone(pc.Classes())
+	index := slices.IndexFunc(classes, func(c ClassLevel) bool { return strings.EqualFold(c.Class, rules.Class) })
+	if index < 0 {
+		classes = append(classes, ClassLevel{Class: rules.Class})
+		index = len(classes) - 1
+	}
+	entry := &classes[index]
+	entry.Level++
+
+	current := entry.Subclass
+	if current != "" {
+		subclass = current
+	} else if i := slices.IndexFunc(rules.SubclassOptions, func(o string) bool { return strings.EqualFold(o, subclass) }); i >= 0 {
+		subclass = rules.SubclassOptions[i]
+	}
+
+	// Classes homebrew antigas podem ter hit_die 0; segue o padrão da ficha calculada
+	hitDie := rules.HitDie
+	if hitDie < 1 {
+		hitDie = DefaultHitDie
+	}
+
+	plan := LevelUpPlan{
+		PCID:             pc.ID,
+		Class:            rules.Class,
+		ClassLevel:       entry.Level,
+		NewClass:         entry.Level == 1 && len(classes) > 1,
+		FromLevel:        pc.Level,
+		ToLevel:          pc.Level + 1,
+		Classes:          classes,
+		HitDie:           hitDie,
+		AverageHP:        hitDie/2 + 1,
+		ProficiencyBonus: rules.Progression.ProficiencyBonus,
+		Subclass:         subclass,
+		Features:         []LevelFeature{},
+		Choices:          []LevelUpChoice{},
+	}
+	// A proficiência vem do nível total, não do nível na classe
+	if plan.ProficiencyBonus == 0 || len(classes) > 1 {
+		next := PC{Level: plan.ToLevel}
+		plan.ProficiencyBonus = next.GetProficiencyBonus()
+	}
+
+	for _, feature := range rules.Progression.Features {
+		if feature.Subclass == "" || strings.EqualFold(feature.Subclass, subclass) {
+			plan.Features = append(plan.Features, feature)
+		}
+	}
+
+	if current == "" && len(rules.SubclassOptions) > 0 && plan.ClassLevel >= rules.SubclassLevel {
+		plan.Choices = append(plan.Choices, LevelUpChoice{
+			Type:        LevelUpChoiceSubclass,
+			Field:       "subclass",
+			Description: fmt.Sprintf("Choose a %s subclass", rules.Class),
+			Options:     rules.SubclassOptions,
+		})
+		if slices.Contains(rules.SubclassOptions, subclass) {
+			entry.Subclass = subclass
+		}
+	}
+
+	if len(classes) > 1 {
+		plan.SpellSlots, plan.PactMagic = CombinedSpellSlots(classes, rules.Classes)
+	} else if len(rules.Progression.SpellSlots) > 0 {
+		plan.SpellSlots = make(map[string]int, len(rules.Progression.SpellSlots))
+		for circle, slots := range rules.Progression.SpellSlots {
+			plan.SpellSlots[strconv.Itoa(circle)] = slots
+		}
+	}
+
+	if plan.NewClass {
+		proficiency := MulticlassProficiencies[strings.ToLower(cmp.Or(rules.Index, rules.Class))]
+		plan.Proficiencies = proficiency.Proficiencies
+		if proficiency.Skills > 0 {
+			options := rules.SkillOptions
+			if proficiency.AnySkill {
+				options = make([]string, len(Skills))
+				for i, skill := range Skills {
+					options[i] = skill.Index
+				}
+			}
+			plan.Choices = append(plan.Choices, LevelUpChoice{
+				Type:        LevelUpChoiceSkills,
+				Field:       "skills",
+				Description: fmt.Sprintf("Choose %d skill proficiency from multiclassing into %s", proficiency.Skills, rules.Class),
+				Options:     options,
+				Points:      proficiency.Skills,
+			})
+		}
+	}
+	if rules.Progression.AbilityScoreImprovement {
+		plan.Choices = append(plan.Choices, LevelUpChoice{
+			Type:        LevelUpChoiceASI,
+			Field:       "asi",
+			Description: "Increase one ability score by 2 or two ability scores by 1 (maximum 20), or take a feat instead",
+			Points:      ASIPoints,
+		})
+	}
+
+	return plan
+}
+
+// Choice retorna a escolha do tipo informado exigida pelo plano, ou nil
+func (p LevelUpPlan) Choice(choiceType string) *LevelUpChoice {
+	for i := range p.Choices {
+		if p.Choices[i].Type == choiceType {
+			return &p.Choices[i]
+		}
+	}
+	return nil
+}
+
+// LevelHitPoints retorna os PV ganhos no novo nível com o resultado do dado de vida: mínimo de
+// 1, mais o ganho retroativo dos níveis anteriores se o modificador de Constituição subir
+func LevelHitPoints(dieResult, fromLevel, oldCon, newCon int) int {
+	oldMod, newMod := CalculateModifier(oldCon), CalculateModifier(newCon)
+	return max(1, dieResult+newMod) + (newMod-oldMod)*fromLevel
+}
+
+// ApplyLevelUp aplica o plano ao PC: nível e classes (com a subclasse), PV, bônus de
+// proficiência, aumento de atributo, talento, características, proficiências e perícias de
+// multiclasse e espaços de magia (mantendo os já gastos)
+func ApplyLevelUp(pc *PC, plan LevelUpPlan, req LevelUpRequest, hpGained int) {
+	pc.Level = plan.ToLevel
+	if len(plan.Classes) > 0 {
+		pc.ClassLevels = plan.Classes
+		pc.Class = plan.Classes.Summary()
+	}
+	pc.ProficiencyBonus = plan.ProficiencyBonus
+	pc.HP += hpGained
+	if pc.CurrentHP != nil {
+		current := *pc.CurrentHP + hpGained
+		pc.CurrentHP = &current
+	}
+
+	if len(req.ASI) > 0 {
+		attributes, _ := pc.Attributes.Data.(map[string]any)
+		if attributes == nil {
+			attributes = map[string]any{}
+		}
+		scores := pc.AttributeScores()
+		for ability, increase := range req.ASI {
+			attributes[ability] = scores[ability] + increase
+		}
+		pc.Attributes = JSONBFlexible{Data: attributes}
+	}
+
+	if len(plan.Proficiencies) > 0 {
+		abilities, _ := pc.Abilities.Data.(map[string]any)
+		if abilities == nil {
+			abilities = map[string]any{}
+		}
+		known, _ := abilities["proficiencies"].([]any)
+		for _, proficiency := range plan.Proficiencies {
+			if !slices.Contains(known, any(proficiency)) {
+				known = append(known, proficiency)
+			}
+		}
+		abilities["proficiencies"] = known
+		pc.Abilities = JSONBFlexible{Data: abilities}
+	}
+	if len(req.Skills) > 0 {
+		applySkillProficiencies(pc, req.Skills)
+	}
+
+	for _, feature := range plan.Features {
+		if !slices.Contains(pc.Features, feature.Name) {
+			pc.Features = append(pc.Features, feature.Name)
+		}
+	}
+	if req.Feat != "" && !slices.Contains(pc.Features, req.Feat) {
+		pc.Features = append(pc.Features, req.Feat)
+	}
+
+	if len(plan.SpellSlots) > 0 {
+		applySpellSlots(pc, plan.SpellSlots)
+	}
+	if plan.PactMagic != nil {
+		applyPactMagic(pc, *plan.PactMagic)
+	}
+}
+
+// applySkillProficiencies marca as perícias como proficientes em skills, que o editor indexa
+// pelo nome em português
+func applySkillProficiencies(pc *PC, names []string) {
+	skills, _ := pc.Skills.Data.(map[string]any)
+	if skills == nil {
+		skills = map[string]any{}
+	}
+	for _, name := range names {
+		def := FindSkill(name)
+		if def == nil {
+			continue
+		}
+		entry, _ := skills[def.Label].(map[string]any)
+		if entry == nil {
+			entry = map[string]any{"expertise": false, "bonus": 0}
+		}
+		entry["proficient"] = true
+		skills[def.Label] = entry
+	}
+	pc.Skills = JSONBFlexible{Data: skills}
+}
+
+// applyPactMagic grava os espaços de Magia de Pacto em spells.pact_slots ({slot_level, total,
+// used}), separados dos espaços da conjuração de multiclasse
+func applyPactMagic(pc *PC, pact PactMagic) {
+	spells, _ := pc.Spells.Data.(map[string]any)
+	if spells == nil {
+		spells = map[string]any{"spell_slots": map[string]any{}, "known_spells": []any{}}
+	}
+	used := 0
+	if entry, ok := spells["pact_slots"].(map[string]any); ok {
+		used, _ = jsonInt(entry["used"])
+	}
+	spells["pact_slots"] = map[string]any{"slot_level": pact.SlotLevel, "total": pact.Slots, "used": min(used, pact.Slots)}
+	pc.Spells = JSONBFlexible{Data: spells}
+}
+
+// applySpellSlots atualiza o total de cada círculo em spells.spell_slots ({total, used}),
+// limitando os espaços gastos ao novo total
+func applySpellSlots(pc *PC, totals map[string]int) {
+	spells, _ := pc.Spells.Data.(map[string]any)
+	if spells == nil {
+		spells = map[string]any{"known_spells": []any{}}
+	}
+	slots, _ := spells["spell_slots"].(map[string]any)
+	if slots == nil {
+		slots = map[string]any{}
+	}
+
+	for _, circle := range slices.Sorted(maps.Keys(totals)) {
+		total := totals[circle]
+		used := 0
+		if entry, ok := slots[circle].(map[string]any); ok {
+			used, _ = jsonInt(entry["used"])
+		}
+		slots[circle] = map[string]any{"total": total, "used": min(used, total)}
+	}
+
+	spells["spell_slots"] = slots
+	pc.Spells = JSONBFlexible{Data: spells}
+}
+
+// SRDClassLevel extrai a progressão de um nível de dnd_classes.class_levels (a lista de
+// /classes/{index}/levels da API: [{level, prof_bonus, ability_score_bonuses, features,
+// spellcasting: {spell_slots_level_1, ...}}]); entradas de subclasse são ignoradas. As
+// características vêm de dnd_features e são acrescentadas depois.
+func SRDClassLevel(class *DnDClass, level int) (ClassLevelRules, bool) {
+	rules := ClassLevelRules{Level: level, Features: []LevelFeature{}}
+
+	entries, _ := class.ClassLevels.Data.([]any)
+	var current, previous map[string]any
+	for _, raw := range entries {
+		entry, ok := raw.(map[string]any)
+		if !ok || entry["subclass"] != nil {
+			continue
+		}
+		switch n, _ := jsonInt(entry["level"]); n {
+		case level:
+			current = entry
+		case level - 1:
+			previous = entry
+		}
+	}
+	if current == nil {
+		return rules, false
+	}
+
+	rules.ProficiencyBonus, _ = jsonInt(current["prof_bonus"])
+
+	asi, _ := jsonInt(current["ability_score_bonuses"])
+	previousASI, _ := jsonInt(previous["ability_score_bonuses"])
+	rules.AbilityScoreImprovement = asi > previousASI
+
+	if spellcasting, ok := current["spellcasting"].(map[string]any); ok {
+		for circle := 1; circle <= 9; circle++ {
+			if slots, _ := jsonInt(spellcasting[fmt.Sprintf("spell_slots_level_%d", circle)]); slots > 0 {
+				if rules.SpellSlots == nil {
+					rules.SpellSlots = map[int]int{}
+				}
+				rules.SpellSlots[circle] = slots
+			}
+		}
+	}
+
+	return rules, true
+}
+
+// AddSRDFeatures acrescenta as características de dnd_features do nível (da classe e das
+// subclasses) e marca o aumento de atributo se uma delas for "Ability Score Improvement"
+func (r *ClassLevelRules) AddSRDFeatures(features []DnDFeature) {
+	for _, feature := range features {
+		if strings.EqualFold(feature.Name, abilityScoreImprovement) {
+			r.AbilityScoreImprovement = true
+			continue
+		}
+		r.Features = append(r.Features, LevelFeature{
+			Name:        feature.Name,
+			Description: feature.Description,
+			Subclass:    feature.SubclassName,
+		})
+	}
+}
+
+// HomebrewClassLevel extrai a progressão de um nível de uma classe homebrew. features é
+// indexado pelo nível: {"4": [{name, description, asi: true}], "3": [{name, subclass_options:
+// [...]}]}; uma característica "Ability Score Improvement" também conta como aumento de
+// atributo. spellcasting.spell_slots, se houver, segue o mesmo formato: {"3": {"1": 4, "2": 2}}.
+// Retorna também as opções de subclasse e o nível em que são escolhidas.
+func HomebrewClassLevel(class *HomebrewClass, level int) (ClassLevelRules, []string, int) {
+	rules := ClassLevelRules{Level: level, Features: []LevelFeature{}}
+	var subclassOptions []string
+	subclassLevel := 0
+
+	byLevel, _ := class.Features.Data.(map[string]any)
+	for _, key := range slices.Sorted(maps.Keys(byLevel)) {
+		featureLevel, err := strconv.Atoi(key)
+		if err != nil {
+			continue
+		}
+		entries, _ := byLevel[key].([]any)
+		for _, raw := range entries {
+			entry, ok := raw.(map[string]any)
+			if !ok {
+				continue
+			}
+			if options, ok := entry["subclass_options"].([]any); ok && subclassOptions == nil {
+				subclassOptions = stringList(options)
+				subclassLevel = featureLevel
+			}
+			if featureLevel != level {
+				continue
+			}
+
+			name, _ := entry["name"].(string)
+			asi, _ := entry["asi"].(bool)
+			if asi || strings.EqualFold(name, abilityScoreImprovement) {
+				rules.AbilityScoreImprovement = true
+				continue
+			}
+			if name == "" {
+				continue
+			}
+			description, _ := entry["description"].(string)
+			subclass, _ := entry["subclass"].(string)
+			rules.Features = append(rules.Features, LevelFeature{Name: name, Description: description, Subclass: subclass})
+		}
+	}
+
+	spellcasting, _ := class.Spellcasting.Data.(map[string]any)
+	progression, _ := spellcasting["spell_slots"].(map[string]any)
+	if slots, ok := progression[strconv.Itoa(level)].(map[string]any); ok {
+		for key, raw := range slots {
+			circle, err := strconv.Atoi(key)
+			count, ok := jsonInt(raw)
+			if err != nil || !ok || count <= 0 {
+				continue
+			}
+			if rules.SpellSlots == nil {
+				rules.SpellSlots = map[int]int{}
+			}
+			rules.SpellSlots[circle] = count
+		}
+	}
+
+	return rules, subclassOptions, subclassLevel
+}
+
+func stringList(values []any) []string {
+	list := make([]string, 0, len(values))
+	for _, value := range values {
+		if s, ok := value.(string); ok && strings.TrimSpace(s) != "" {
+			list = append(list, strings.TrimSpace(s))
+		}
+	}
+	return list
+}
@@ -0,0 +1,143 @@
+package models
+
+import (
+	"testing"
+)
+
+func wizardClass() *DnDClass {
+	return &DnDClass{
+		Name:   "Wizard",
+		HitDie: 6,
+		ClassLevels: JSONBFlexible{Data: []any{
+			map[string]any{"level": float64(3), "prof_bonus": float64(2), "ability_score_bonuses": float64(0),
+				"spellcasting": map[string]any{"spell_slots_level_1": float64(4), "spell_slots_level_2": float64(2)}},
+			map[string]any{"level": float64(4), "prof_bonus": float64(2), "ability_score_bonuses": float64(1),
+				"spellcasting": map[string]any{"spell_slots_level_1": float64(4), "spell_slots_level_2": float64(3)}},
+			map[string]any{"level": float64(4), "subclass": map[string]any{"index": "evocation"}},
+		}},
+	}
+}
+
+func TestSRDClassLevel(t *testing.T) {
+	rules, ok := SRDClassLevel(wizardClass(), 4)
+	if !ok {
+		t.Fatal("expected level 4 progression")
+	}
+	if !rules.AbilityScoreImprovement || rules.ProficiencyBonus != 2 || rules.SpellSlots[2] != 3 {
+		t.Fatalf("unexpected progression: %+v", rules)
+	}
+
+	rules, _ = SRDClassLevel(wizardClass(), 3)
+	if rules.AbilityScoreImprovement {
+		t.Fatal("level 3 should not grant an ability score improvement")
+	}
+	if _, ok := SRDClassLevel(wizardClass(), 5); ok {
+		t.Fatal("level 5 is missing from the progression")
+	}
+}
+
+func TestHomebrewClassLevel(t *testing.T) {
+	class := &HomebrewClass{
+		Name: "Gunslinger",
+		Features: JSONBFlexible{Data: map[string]any{
+			"3": []any{map[string]any{"name": "Gunslinger Creed", "subclass_options": []any{"Deadeye", "Trick Shot"}}},
+			"4": []any{
+				map[string]any{"name": "Ability Score Improvement"},
+				map[string]any{"name": "Quick Draw", "description": "Draw as a free action"},
+				map[string]any{"name": "Steady Aim", "subclass": "Deadeye"},
+			},
+		}},
+		Spellcasting: JSONBFlexible{Data: map[string]any{"spell_slots": map[string]any{"4": map[string]any{"1": float64(2)}}}},
+	}
+
+	rules, options, subclassLevel := HomebrewClassLevel(class, 4)
+	if !rules.AbilityScoreImprovement || len(rules.Features) != 2 || rules.SpellSlots[1] != 2 {
+		t.Fatalf("unexpected progression: %+v", rules)
+	}
+	if len(options) != 2 || subclassLevel != 3 {
+		t.Fatalf("unexpected subclass options: %v at %d", options, subclassLevel)
+	}
+}
+
+func TestNewLevelUpPlan(t *testing.T) {
+	pc := &PC{ID: 1, Level: 3, Class: "Gunslinger", Abilities: JSONBFlexible{Data: map[string]any{}}}
+	rules := LevelUpRules{
+		Class:  "Gunslinger",
+		HitDie: 10,
+		Progression: ClassLevelRules{
+			Level:                   4,
+			AbilityScoreImprovement: true,
+			Features: []LevelFeature{
+				{Name: "Quick Draw"},
+				{Name: "Steady Aim", Subclass: "Deadeye"},
+				{Name: "Ricochet", Subclass: "Trick Shot"},
+			},
+		},
+		SubclassOptions: []string{"Deadeye", "Trick Shot"},
+		SubclassLevel:   3,
+	}
+
+	plan := NewLevelUpPlan(pc, rules, "deadeye")
+	if plan.ToLevel != 4 || plan.ClassLevel != 4 || plan.AverageHP != 6 || plan.ProficiencyBonus != 2 || plan.Subclass != "Deadeye" {
+		t.Fatalf("unexpected plan: %+v", plan)
+	}
+	if len(plan.Features) != 2 || plan.Features[1].Name != "Steady Aim" {
+		t.Fatalf("expected class and Deadeye features, got %+v", plan.Features)
+	}
+	if plan.Choice(LevelUpChoiceSubclass) == nil || plan.Choice(LevelUpChoiceASI) == nil {
+		t.Fatalf("expected subclass and ASI choices, got %+v", plan.Choices)
+	}
+}
+
+func TestNewLevelUpPlan_DefaultHitDie(t *testing.T) {
+	pc := &PC{ID: 1, Level: 1, Class: "Gunslinger", Abilities: JSONBFlexible{Data: map[string]any{}}}
+
+	plan := NewLevelUpPlan(pc, LevelUpRules{Class: "Gunslinger"}, "")
+	if plan.HitDie != DefaultHitDie || plan.AverageHP != DefaultHitDie/2+1 {
+		t.Fatalf("expected the default hit die for a class without one, got %+v", plan)
+	}
+}
+
+func TestApplyLevelUp(t *testing.T) {
+	current := 10
+	pc := &PC{
+		Level:      3,
+		Class:      "Wizard",
+		HP:         20,
+		CurrentHP:  &current,
+		Attributes: JSONBFlexible{Data: map[string]any{"constitution": float64(15)}},
+		Abilities:  JSONBFlexible{Data: map[string]any{}},
+		Spells:     JSONBFlexible{Data: map[string]any{"spell_slots": map[string]any{"2": map[string]any{"total": float64(2), "used": float64(2)}}}},
+		Features:   []string{"Arcane Recovery"},
+	}
+	plan := LevelUpPlan{
+		FromLevel: 3, ToLevel: 4, ProficiencyBonus: 2, Subclass: "Evocation",
+		Classes:    ClassLevels{{Class: "Wizard", Level: 4, Subclass: "Evocation"}},
+		Features:   []LevelFeature{{Name: "Sculpt Spells"}},
+		SpellSlots: map[string]int{"1": 4, "2": 3},
+	}
+	req := LevelUpRequest{ASI: map[string]int{"constitution": 1, "intelligence": 1}}
+
+	// d6 médio 4 + Con 16 (+3), e +1 retroativo nos 3 níveis anteriores
+	hp := LevelHitPoints(4, 3, 15, 16)
+	if hp != 10 {
+		t.Fatalf("expected 10 HP, got %d", hp)
+	}
+
+	ApplyLevelUp(pc, plan, req, hp)
+	if pc.Level != 4 || pc.HP != 30 || *pc.CurrentHP != 20 {
+		t.Fatalf("unexpected level/HP: %d %d %d", pc.Level, pc.HP, *pc.CurrentHP)
+	}
+	scores := pc.AttributeScores()
+	if scores["constitution"] != 16 || scores["intelligence"] != 11 {
+		t.Fatalf("ASI not applied: %v", scores)
+	}
+	if pc.Subclass() != "Evocation" || len(pc.Features) != 2 {
+		t.Fatalf("unexpected subclass/features: %q %v", pc.Subclass(), pc.Features)
+	}
+	slots := pc.Spells.Data.(map[string]any)["spell_slots"].(map[string]any)
+	second := slots["2"].(map[string]any)
+	if second["total"] != 3 || second["used"] != 2 {
+		t.Fatalf("unexpected spell slots: %v", slots)
+	}
+}
@@ -0,0 +1,412 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ClassLevel é uma das classes do personagem, com o nível nela e a subclasse escolhida
+type ClassLevel struct {
+	Class    string `json:"class"`
+	Level    int    `json:"level"`
+	Subclass string `json:"subclass,omitempty"`
+}
+
+// ClassLevels são as classes do personagem na ordem em que foram adquiridas; a primeira é a
+// classe inicial (PV máximos no 1º nível e testes de resistência). Guardada em JSONB.
+type ClassLevels []ClassLevel
+
+func (c ClassLevels) Value() (driver.Value, error) {
+	if c == nil {
+		return "[]", nil
+	}
+	return json.Marshal(c)
+}
+
+func (c *ClassLevels) Scan(value any) error {
+	if value == nil {
+		*c = ClassLevels{}
+		return nil
+	}
+
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into ClassLevels", value)
+	}
+
+	return json.Unmarshal(bytes, c)
+}
+
+// TotalLevel é o nível do personagem: a soma dos níveis de todas as classes
+func (c ClassLevels) TotalLevel() int {
+	total := 0
+	for _, entry := range c {
+		total += entry.Level
+	}
+	return total
+}
+
+// Summary é o resumo gravado em class: o nome da classe para uma só classe ou
+// "Fighter 3 / Wizard 2" para multiclasse
+func (c ClassLevels) Summary() string {
+	if len(c) == 1 {
+		return c[0].Class
+	}
+	parts := make([]string, len(c))
+	for i, entry := range c {
+		parts[i] = fmt.Sprintf("%s %d", entry.Class, entry.Level)
+	}
+	return strings.Join(parts, " / ")
+}
+
+// Names retorna o nome de cada classe, na ordem em que foram adquiridas
+func (c ClassLevels) Names() []string {
+	names := make([]string, len(c))
+	for i, entry := range c {
+		names[i] = entry.Class
+	}
+	return names
+}
+
+// Find retorna a entrada da classe (pelo nome, sem diferenciar maiúsculas), ou nil
+func (c ClassLevels) Find(class string) *ClassLevel {
+	for i := range c {
+		if strings.EqualFold(c[i].Class, strings.TrimSpace(class)) {
+			return &c[i]
+		}
+	}
+	return nil
+}
+
+// classSummaryPattern reconhece um trecho "Fighter 3" do resumo de multiclasse
+var classSummaryPattern = regexp.MustCompile(`^(.+?)\s+(\d+)$`)
+
+// resolveClassLevels monta a lista de classes de um personagem. Com class_levels informado,
+// ele vale e class/level viram o resumo. Sem ele (clientes antigos), class é lido como uma
+// classe só ou como o resumo "Fighter 3 / Wizard 2" devolvido pela API, que precisa somar level.
+func resolveClassLevels(levels ClassLevels, class string, level int, subclass string) (ClassLevels, error) {
+	if len(levels) == 0 {
+		class = strings.TrimSpace(class)
+		if class == "" {
+			return ClassLevels{}, nil
+		}
+		if !strings.Contains(class, "/") {
+			levels = ClassLevels{{Class: class, Level: level, Subclass: subclass}}
+		} else {
+			for _, part := range strings.Split(class, "/") {
+				match := classSummaryPattern.FindStringSubmatch(strings.TrimSpace(part))
+				if match == nil {
+					return nil, fmt.Errorf("class %q is not a valid multiclass summary; send class_levels instead", class)
+				}
+				n, _ := strconv.Atoi(match[2])
+				levels = append(levels, ClassLevel{Class: match[1], Level: n})
+			}
+			if levels.TotalLevel() != level {
+				return nil, fmt.Errorf("level %d does not match the class levels in %q; send class_levels to change a multiclass character's level", level, class)
+			}
+		}
+	}
+
+	seen := map[string]bool{}
+	for i := range levels {
+		levels[i].Class = strings.TrimSpace(levels[i].Class)
+		levels[i].Subclass = strings.TrimSpace(levels[i].Subclass)
+		key := strings.ToLower(levels[i].Class)
+		switch {
+		case key == "":
+			return nil, fmt.Errorf("class_levels[%d]: class is required", i)
+		case levels[i].Level < 1:
+			return nil, fmt.Errorf("class_levels[%d]: level must be at least 1", i)
+		case seen[key]:
+			return nil, fmt.Errorf("class_levels[%d]: %s is listed more than once", i, levels[i].Class)
+		}
+		seen[key] = true
+	}
+	if total := levels.TotalLevel(); total > MaxCharacterLevel {
+		return nil, fmt.Errorf("total level %d is above the maximum of %d", total, MaxCharacterLevel)
+	}
+
+	return levels, nil
+}
+
+// Classes retorna as classes do PC. PCs gravados antes da multiclasse têm só class/level (e a
+// subclasse, se houver, em abilities.subclass).
+func (pc *PC) Classes() ClassLevels {
+	return classesOf(pc.ClassLevels, pc.Class, pc.Level, pc.Abilities)
+}
+
+// Classes retorna as classes do snapshot, como PC.Classes
+func (c *CampaignCharacter) Classes() ClassLevels {
+	return classesOf(c.ClassLevels, c.Class, c.Level, c.Abilities)
+}
+
+// NormalizeClassLevels preenche class_levels e recalcula class e level como resumo (sem
+// classe nenhuma, nada muda)
+func (pc *PC) NormalizeClassLevels() error {
+	levels, err := resolveClassLevels(pc.ClassLevels, pc.Class, pc.Level, legacySubclass(pc.Abilities))
+	if err != nil {
+		return err
+	}
+	pc.ClassLevels = levels
+	if len(levels) > 0 {
+		pc.Class, pc.Level = levels.Summary(), levels.TotalLevel()
+	}
+	return nil
+}
+
+// NormalizeClassLevels preenche class_levels do snapshot e recalcula class e level como resumo
+func (c *CampaignCharacter) NormalizeClassLevels() error {
+	levels, err := resolveClassLevels(c.ClassLevels, c.Class, c.Level, legacySubclass(c.Abilities))
+	if err != nil {
+		return err
+	}
+	c.ClassLevels = levels
+	if len(levels) > 0 {
+		c.Class, c.Level = levels.Summary(), levels.TotalLevel()
+	}
+	return nil
+}
+
+func classesOf(levels ClassLevels, class string, level int, abilities JSONBFlexible) ClassLevels {
+	if len(levels) > 0 {
+		return levels
+	}
+	if strings.TrimSpace(class) == "" {
+		return ClassLevels{}
+	}
+	if resolved, err := resolveClassLevels(nil, class, max(level, 1), legacySubclass(abilities)); err == nil {
+		return resolved
+	}
+	return ClassLevels{{Class: class, Level: max(level, 1), Subclass: legacySubclass(abilities)}}
+}
+
+// legacySubclass lê a subclasse gravada em abilities.subclass antes de class_levels
+func legacySubclass(abilities JSONBFlexible) string {
+	data, _ := abilities.Data.(map[string]any)
+	subclass, _ := data["subclass"].(string)
+	return subclass
+}
+
+// Progressão de conjuração de uma classe para a tabela de multiclasse
+const (
+	CasterFull  = "full"  // nível inteiro
+	CasterHalf  = "half"  // metade, arredondada para baixo
+	CasterThird = "third" // um terço, arredondado para baixo (só com a subclasse conjuradora)
+	CasterPact  = "pact"  // Magia de Pacto: espaços próprios, fora da tabela
+)
+
+// srdCasterProgression é a progressão de conjuração das classes do SRD
+var srdCasterProgression = map[string]string{
+	"bard": CasterFull, "cleric": CasterFull, "druid": CasterFull, "sorcerer": CasterFull, "wizard": CasterFull,
+	"paladin": CasterHalf, "ranger": CasterHalf,
+	"fighter": CasterThird, "rogue": CasterThird,
+	"warlock": CasterPact,
+}
+
+// thirdCasterSubclasses são as subclasses que tornam guerreiro e ladino conjuradores
+var thirdCasterSubclasses = map[string]string{
+	"fighter": "eldritch knight",
+	"rogue":   "arcane trickster",
+}
+
+// MulticlassSpellSlots é a tabela de espaços de magia do multiclasse conjurador: índice 0 =
+// nível de conjurador 1, cada linha com os espaços do 1º ao 9º círculo
+var MulticlassSpellSlots = [MaxCharacterLevel][9]int{
+	{2}, {3}, {4, 2}, {4, 3}, {4, 3, 2},
+	{4, 3, 3}, {4, 3, 3, 1}, {4, 3, 3, 2}, {4, 3, 3, 3, 1}, {4, 3, 3, 3, 2},
+	{4, 3, 3, 3, 2, 1}, {4, 3, 3, 3, 2, 1}, {4, 3, 3, 3, 2, 1, 1}, {4, 3, 3, 3, 2, 1, 1}, {4, 3, 3, 3, 2, 1, 1, 1},
+	{4, 3, 3, 3, 2, 1, 1, 1}, {4, 3, 3, 3, 2, 1, 1, 1, 1}, {4, 3, 3, 3, 3, 1, 1, 1, 1}, {4, 3, 3, 3, 3, 2, 1, 1, 1}, {4, 3, 3, 3, 3, 2, 2, 1, 1},
+}
+
+// PactMagic são os espaços de Magia de Pacto do bruxo, todos do mesmo círculo
+type PactMagic struct {
+	Slots     int `json:"slots"`
+	SlotLevel int `json:"slot_level"`
+}
+
+// PactMagicFor retorna os espaços de Magia de Pacto para o nível de bruxo
+func PactMagicFor(warlockLevel int) *PactMagic {
+	if warlockLevel < 1 {
+		return nil
+	}
+	slots := 2
+	switch {
+	case warlockLevel == 1:
+		slots = 1
+	case warlockLevel >= 17:
+		slots = 4
+	case warlockLevel >= 11:
+		slots = 3
+	}
+	return &PactMagic{Slots: slots, SlotLevel: min((warlockLevel+1)/2, 5)}
+}
+
+// CasterProgressionFor retorna a progressão de conjuração de uma classe do SRD pelo índice
+func CasterProgressionFor(index string) string {
+	return srdCasterProgression[strings.ToLower(index)]
+}
+
+// CasterLevel calcula o nível de conjurador do multiclasse: níveis inteiros dos conjuradores
+// completos, metade dos meio-conjuradores e um terço das subclasses conjuradoras de guerreiro
+// e ladino. classes e rules estão na mesma ordem.
+func CasterLevel(classes ClassLevels, rules []ClassRules) int {
+	full, half, third := 0, 0, 0
+	for i, entry := range classes {
+		if i >= len(rules) {
+			break
+		}
+		switch rules[i].CasterProgression {
+		case CasterFull:
+			full += entry.Level
+		case CasterHalf:
+			half += entry.Level
+		case CasterThird:
+			if subclass, ok := thirdCasterSubclasses[strings.ToLower(rules[i].Index)]; !ok || strings.EqualFold(entry.Subclass, subclass) {
+				third += entry.Level
+			}
+		}
+	}
+	return full + half/2 + third/3
+}
+
+// CombinedSpellSlots calcula os espaços de magia do multiclasse (por círculo, "1" a "9") e a
+// Magia de Pacto, que fica separada
+func CombinedSpellSlots(classes ClassLevels, rules []ClassRules) (map[string]int, *PactMagic) {
+	var pact *PactMagic
+	for i, entry := range classes {
+		if i < len(rules) && rules[i].CasterProgression == CasterPact {
+			pact = PactMagicFor(entry.Level)
+		}
+	}
+
+	casterLevel := CasterLevel(classes, rules)
+	if casterLevel < 1 {
+		return nil, pact
+	}
+	slots := map[string]int{}
+	for circle, count := range MulticlassSpellSlots[min(casterLevel, MaxCharacterLevel)-1] {
+		if count > 0 {
+			slots[strconv.Itoa(circle+1)] = count
+		}
+	}
+	return slots, pact
+}
+
+// MulticlassPrerequisite é o atributo mínimo para entrar (ou sair) de uma classe no
+// multiclasse. Com AnyOf, basta um dos atributos (o guerreiro aceita Força ou Destreza).
+type MulticlassPrerequisite struct {
+	Abilities []string `json:"abilities"`
+	AnyOf     bool     `json:"any_of,omitempty"`
+	Minimum   int      `json:"minimum"`
+}
+
+// MulticlassMinimumScore é o valor mínimo exigido pelos pré-requisitos do SRD
+const MulticlassMinimumScore = 13
+
+// MulticlassPrerequisites são os pré-requisitos de multiclasse do SRD pelo índice da classe
+var MulticlassPrerequisites = map[string]MulticlassPrerequisite{
+	"barbarian": {Abilities: []string{"strength"}, Minimum: MulticlassMinimumScore},
+	"bard":      {Abilities: []string{"charisma"}, Minimum: MulticlassMinimumScore},
+	"cleric":    {Abilities: []string{"wisdom"}, Minimum: MulticlassMinimumScore},
+	"druid":     {Abilities: []string{"wisdom"}, Minimum: MulticlassMinimumScore},
+	"fighter":   {Abilities: []string{"strength", "dexterity"}, AnyOf: true, Minimum: MulticlassMinimumScore},
+	"monk":      {Abilities: []string{"dexterity", "wisdom"}, Minimum: MulticlassMinimumScore},
+	"paladin":   {Abilities: []string{"strength", "charisma"}, Minimum: MulticlassMinimumScore},
+	"ranger":    {Abilities: []string{"dexterity", "wisdom"}, Minimum: MulticlassMinimumScore},
+	"rogue":     {Abilities: []string{"dexterity"}, Minimum: MulticlassMinimumScore},
+	"sorcerer":  {Abilities: []string{"charisma"}, Minimum: MulticlassMinimumScore},
+	"warlock":   {Abilities: []string{"charisma"}, Minimum: MulticlassMinimumScore},
+	"wizard":    {Abilities: []string{"intelligence"}, Minimum: MulticlassMinimumScore},
+}
+
+// Met indica se os atributos cumprem o pré-requisito
+func (p MulticlassPrerequisite) Met(scores map[string]int) bool {
+	for _, ability := range p.Abilities {
+		met := scores[ability] >= p.Minimum
+		if p.AnyOf && met {
+			return true
+		}
+		if !p.AnyOf && !met {
+			return false
+		}
+	}
+	return !p.AnyOf
+}
+
+// String descreve o pré-requisito, como "strength 13 or dexterity 13"
+func (p MulticlassPrerequisite) String() string {
+	parts := make([]string, len(p.Abilities))
+	for i, ability := range p.Abilities {
+		parts[i] = fmt.Sprintf("%s %d", ability, p.Minimum)
+	}
+	separator := " and "
+	if p.AnyOf {
+		separator = " or "
+	}
+	return strings.Join(parts, separator)
+}
+
+// UnmetMulticlassPrerequisites lista os pré-requisitos não cumpridos para entrar em newClass: o
+// da nova classe e os de todas as classes atuais. Classes fora do SRD não têm pré-requisitos.
+func UnmetMulticlassPrerequisites(classes ClassLevels, newClass string, scores map[string]int) []string {
+	unmet := []string{}
+	for _, class := range append(slices.Clone(classes), ClassLevel{Class: newClass}) {
+		if prerequisite, ok := MulticlassPrerequisites[strings.ToLower(class.Class)]; ok && !prerequisite.Met(scores) {
+			unmet = append(unmet, fmt.Sprintf("%s requires %s", class.Class, prerequisite))
+		}
+	}
+	return unmet
+}
+
+// MulticlassProficiency são as proficiências ganhas ao entrar em uma classe como multiclasse.
+// Skills é quantas perícias escolher: das opções da classe ou, com AnySkill, de qualquer uma.
+type MulticlassProficiency struct {
+	Proficiencies []string `json:"proficiencies"`
+	Skills        int      `json:"skills,omitempty"`
+	AnySkill      bool     `json:"any_skill,omitempty"`
+}
+
+// MulticlassProficiencies é a tabela de proficiências de multiclasse do SRD pelo índice da classe
+var MulticlassProficiencies = map[string]MulticlassProficiency{
+	"barbarian": {Proficiencies: []string{"Shields", "Simple weapons", "Martial weapons"}},
+	"bard":      {Proficiencies: []string{"Light armor", "One musical instrument of your choice"}, Skills: 1, AnySkill: true},
+	"cleric":    {Proficiencies: []string{"Light armor", "Medium armor", "Shields"}},
+	"druid":     {Proficiencies: []string{"Light armor", "Medium armor", "Shields"}},
+	"fighter":   {Proficiencies: []string{"Light armor", "Medium armor", "Shields", "Simple weapons", "Martial weapons"}},
+	"monk":      {Proficiencies: []string{"Simple weapons", "Shortswords"}},
+	"paladin":   {Proficiencies: []string{"Light armor", "Medium armor", "Shields", "Simple weapons", "Martial weapons"}},
+	"ranger":    {Proficiencies: []string{"Light armor", "Medium armor", "Shields", "Simple weapons", "Martial weapons"}, Skills: 1},
+	"rogue":     {Proficiencies: []string{"Light armor", "Thieves' tools"}, Skills: 1},
+	"sorcerer":  {Proficiencies: []string{}},
+	"warlock":   {Proficiencies: []string{"Light armor", "Simple weapons"}},
+	"wizard":    {Proficiencies: []string{}},
+}
+
+// MulticlassHitPoints calcula os PV máximos de um multiclasse: só o 1º nível da classe inicial
+// usa o valor máximo do dado; os demais níveis de todas as classes usam a média. classes e
+// rules estão na mesma ordem.
+func MulticlassHitPoints(classes ClassLevels, rules []ClassRules, conModifier int) int {
+	total := 0
+	for i, entry := range classes {
+		hitDie := DefaultHitDie
+		if i < len(rules) && rules[i].HitDie > 0 {
+			hitDie = rules[i].HitDie
+		}
+		if i == 0 {
+			total += MaxHitPoints(hitDie, entry.Level, conModifier)
+			continue
+		}
+		total += entry.Level * max(hitDie/2+1+conModifier, 1)
+	}
+	return total
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestNormalizeClassLevels(t *testing.T) {
+	pc := &PC{Class: "Fighter", Level: 3, Abilities: JSONBFlexible{Data: map[string]any{"subclass": "Champion"}}}
+	if err := pc.NormalizeClassLevels(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pc.ClassLevels) != 1 || pc.ClassLevels[0].Level != 3 || pc.ClassLevels[0].Subclass != "Champion" {
+		t.Fatalf("legacy class not converted: %+v", pc.ClassLevels)
+	}
+
+	pc = &PC{ClassLevels: ClassLevels{{Class: "Fighter", Level: 3}, {Class: "Wizard", Level: 2}}}
+	if err := pc.NormalizeClassLevels(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pc.Class != "Fighter 3 / Wizard 2" || pc.Level != 5 {
+		t.Fatalf("unexpected summary: %q level %d", pc.Class, pc.Level)
+	}
+
+	// O resumo devolvido pela API é aceito de volta se bater com o nível
+	pc = &PC{Class: "Fighter 3 / Wizard 2", Level: 5}
+	if err := pc.NormalizeClassLevels(); err != nil || len(pc.ClassLevels) != 2 {
+		t.Fatalf("summary not parsed: %+v, %v", pc.ClassLevels, err)
+	}
+	pc = &PC{Class: "Fighter 3 / Wizard 2", Level: 6}
+	if err := pc.NormalizeClassLevels(); err == nil {
+		t.Fatal("expected error for a summary that does not match level")
+	}
+
+	pc = &PC{ClassLevels: ClassLevels{{Class: "Fighter", Level: 3}, {Class: "fighter", Level: 2}}}
+	if err := pc.NormalizeClassLevels(); err == nil {
+		t.Fatal("expected error for a duplicated class")
+	}
+}
+
+func TestUnmetMulticlassPrerequisites(t *testing.T) {
+	scores := map[string]int{"strength": 8, "dexterity": 14, "intelligence": 12, "charisma": 13}
+	classes := ClassLevels{{Class: "Fighter", Level: 3}}
+
+	if unmet := UnmetMulticlassPrerequisites(classes, "Sorcerer", scores); len(unmet) != 0 {
+		t.Fatalf("fighter (dexterity) into sorcerer should be allowed, got %v", unmet)
+	}
+	unmet := UnmetMulticlassPrerequisites(classes, "Wizard", scores)
+	if len(unmet) != 1 || unmet[0] != "Wizard requires intelligence 13" {
+		t.Fatalf("unexpected unmet prerequisites: %v", unmet)
+	}
+	if unmet := UnmetMulticlassPrerequisites(classes, "Gunslinger", scores); len(unmet) != 0 {
+		t.Fatalf("homebrew classes have no prerequisites, got %v", unmet)
+	}
+}
+
+func TestCombinedSpellSlots(t *testing.T) {
+	classes := ClassLevels{{Class: "Paladin", Level: 5}, {Class: "Sorcerer", Level: 3}, {Class: "Warlock", Level: 3}}
+	rules := []ClassRules{
+		{Index: "paladin", CasterProgression: CasterHalf},
+		{Index: "sorcerer", CasterProgression: CasterFull},
+		{Index: "warlock", CasterProgression: CasterPact},
+	}
+
+	// Paladino 5 (2) + feiticeiro 3 = conjurador de nível 5
+	slots, pact := CombinedSpellSlots(classes, rules)
+	if slots["1"] != 4 || slots["2"] != 3 || slots["3"] != 2 || slots["4"] != 0 {
+		t.Fatalf("unexpected slots: %v", slots)
+	}
+	if pact == nil || pact.Slots != 2 || pact.SlotLevel != 2 {
+		t.Fatalf("unexpected pact magic: %+v", pact)
+	}
+
+	// Guerreiro só conjura como Cavaleiro Místico
+	classes = ClassLevels{{Class: "Fighter", Level: 6, Subclass: "Champion"}, {Class: "Wizard", Level: 1}}
+	rules = []ClassRules{{Index: "fighter", CasterProgression: CasterThird}, {Index: "wizard", CasterProgression: CasterFull}}
+	if slots, _ := CombinedSpellSlots(classes, rules); slots["1"] != 2 || len(slots) != 1 {
+		t.Fatalf("champion levels should not count, got %v", slots)
+	}
+}
+
+func TestMulticlassHitPoints(t *testing.T) {
+	classes := ClassLevels{{Class: "Fighter", Level: 2}, {Class: "Wizard", Level: 2}}
+	rules := []ClassRules{{HitDie: 10}, {HitDie: 6}}
+
+	// Guerreiro: 10 + 6 no 2º nível; mago: 4 + 4; Con +1 em cada nível
+	if hp := MulticlassHitPoints(classes, rules, 1); hp != 28 {
+		t.Fatalf("expected 28 HP, got %d", hp)
+	}
+}
@@ -17,9 +17,17 @@ type NPC struct {
 	Equipment   JSONB     `json:"equipment" db:"equipment"`
 	HP          int       `json:"hp" db:"hp"`
 	CA          int       `json:"ca" db:"ca"`
+	IsHomebrew  bool      `json:"is_homebrew" db:"is_homebrew"`
+	CampaignID  *int      `json:"campaign_id" db:"campaign_id"` // nil = NPC global
+	Revealed    bool      `json:"revealed" db:"revealed"`       // visível aos players da campanha
 	CreatedAt   time.Time `json:"created_at" db:"created_at"`
 }
 
+// RevealRequest revela ou esconde um NPC ou encontro da campanha para os players
+type RevealRequest struct {
+	Revealed bool `json:"revealed"`
+}
+
 // type PC struct {
 // 	ID          int       `json:"id" db:"id"`
 // 	Name        string    `json:"name" db:"name"`
@@ -58,6 +66,9 @@ type Encounter struct {
 	TotalXP     int       `json:"total_xp" db:"total_xp"`
 	PlayerLevel int       `json:"player_level" db:"player_level"`
 	PlayerCount int       `json:"player_count" db:"player_count"`
+	CampaignID  *int      `json:"campaign_id" db:"campaign_id"` // nil = encontro global
+	Revealed    bool      `json:"revealed" db:"revealed"`       // visível aos players da campanha
+	XPAwarded   bool      `json:"xp_awarded" db:"xp_awarded"`   // XP já distribuído aos personagens
 	CreatedAt   time.Time `json:"created_at" db:"created_at"`
 	Monsters    []Monster `json:"monsters,omitempty"`
 }
@@ -0,0 +1,293 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// Modos de divisão do saque
+const (
+	LootModeEven   = "even"   // divide igualmente entre os personagens escolhidos
+	LootModeManual = "manual" // o DM escolhe quem recebe o quê
+)
+
+var LootModes = []string{LootModeEven, LootModeManual}
+
+// Ações registradas no histórico de saque
+const (
+	LootActionAssigned    = "assigned"    // tesouro entrou no inventário do grupo
+	LootActionDistributed = "distributed" // item ou moedas entregues a um personagem
+)
+
+// CoinDenominations lista as moedas em ordem crescente de valor
+var CoinDenominations = []string{"cp", "sp", "ep", "gp", "pp"}
+
+// coinNames é o nome usado ao guardar moedas no equipamento do personagem
+var coinNames = map[string]string{
+	"cp": "Copper Pieces",
+	"sp": "Silver Pieces",
+	"ep": "Electrum Pieces",
+	"gp": "Gold Pieces",
+	"pp": "Platinum Pieces",
+}
+
+// Coins é uma bolsa de moedas por denominação ({"gp": 120, "sp": 30})
+type Coins map[string]int
+
+// CoinsFromJSONB converte as moedas de um hoard gerado
+func CoinsFromJSONB(j JSONB) Coins {
+	coins := Coins{}
+	for denomination, raw := range j {
+		switch amount := raw.(type) {
+		case float64:
+			coins[denomination] = int(math.Floor(amount))
+		case int:
+			coins[denomination] = amount
+		}
+	}
+	return coins
+}
+
+// Add soma as moedas de other
+func (c Coins) Add(other Coins) {
+	for denomination, amount := range other {
+		c[denomination] += amount
+	}
+}
+
+// Subtract retira as moedas de other; falha sem alterar nada se faltar alguma denominação
+func (c Coins) Subtract(other Coins) error {
+	for denomination, amount := range other {
+		if c[denomination] < amount {
+			return fmt.Errorf("not enough %s (have %d, need %d)", denomination, c[denomination], amount)
+		}
+	}
+	for denomination, amount := range other {
+		c[denomination] -= amount
+		if c[denomination] == 0 {
+			delete(c, denomination)
+		}
+	}
+	return nil
+}
+
+// IsZero indica se a bolsa está vazia
+func (c Coins) IsZero() bool {
+	for _, amount := range c {
+		if amount != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (c Coins) Value() (driver.Value, error) {
+	if c == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(map[string]int(c))
+	return string(data), err
+}
+
+func (c *Coins) Scan(value any) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		if value == nil {
+			*c = Coins{}
+			return nil
+		}
+		return errors.New("failed to unmarshal coins")
+	}
+	result := Coins{}
+	if err := json.Unmarshal(bytes, &result); err != nil {
+		return err
+	}
+	*c = result
+	return nil
+}
+
+// PartyInventoryItem é um item do inventário compartilhado ainda não entregue a ninguém
+type PartyInventoryItem struct {
+	ID         int       `json:"id" db:"id"`
+	CampaignID int       `json:"campaign_id" db:"campaign_id"`
+	TreasureID *int      `json:"treasure_id,omitempty" db:"treasure_id"`
+	Name       string    `json:"name" db:"name"`
+	Type       string    `json:"type" db:"type"`
+	Category   string    `json:"category,omitempty" db:"category"`
+	Value      float64   `json:"value" db:"value"`
+	Rank       string    `json:"rank,omitempty" db:"rank"`
+	Quantity   int       `json:"quantity" db:"quantity"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// PartyInventory é o inventário do grupo: itens e bolsa de moedas da campanha
+type PartyInventory struct {
+	CampaignID int                  `json:"campaign_id"`
+	Coins      Coins                `json:"coins"`
+	Items      []PartyInventoryItem `json:"items"`
+}
+
+// LootRecord é uma linha do histórico de saque (quem recebeu o quê)
+type LootRecord struct {
+	ID            int       `json:"id" db:"id"`
+	CampaignID    int       `json:"campaign_id" db:"campaign_id"`
+	Action        string    `json:"action" db:"action"`
+	TreasureID    *int      `json:"treasure_id,omitempty" db:"treasure_id"`
+	CharacterID   *int      `json:"character_id,omitempty" db:"character_id"`
+	CharacterName string    `json:"character_name,omitempty" db:"character_name"`
+	ItemName      string    `json:"item_name,omitempty" db:"item_name"`
+	Quantity      int       `json:"quantity" db:"quantity"`
+	Coins         Coins     `json:"coins" db:"coins"`
+	PerformedBy   *int      `json:"performed_by" db:"performed_by"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
+
+// AssignTreasureResult resume o que um tesouro acrescentou ao inventário do grupo
+type AssignTreasureResult struct {
+	TreasureID int   `json:"treasure_id"`
+	ItemsAdded int   `json:"items_added"`
+	CoinsAdded Coins `json:"coins_added"`
+}
+
+// DistributeLootRequest divide o inventário do grupo entre personagens da campanha.
+// No modo even, os itens em item_ids e (com split_coins) toda a bolsa são divididos entre
+// character_ids; sobras de moedas ficam na bolsa. No modo manual, vale allocations.
+type DistributeLootRequest struct {
+	Mode         string           `json:"mode"`
+	CharacterIDs []int            `json:"character_ids"`
+	ItemIDs      []int            `json:"item_ids"`
+	SplitCoins   bool             `json:"split_coins"`
+	Allocations  []LootAllocation `json:"allocations"`
+}
+
+// LootAllocation é o que um personagem recebe
+type LootAllocation struct {
+	CharacterID int                  `json:"character_id"`
+	Items       []LootItemAllocation `json:"items"`
+	Coins       Coins                `json:"coins"`
+}
+
+type LootItemAllocation struct {
+	ItemID   int `json:"item_id"`
+	Quantity int `json:"quantity"`
+}
+
+// PlanEvenSplit divide moedas e itens igualmente. Moedas são divididas por denominação e a
+// sobra fica na bolsa; unidades de itens são entregues em rodízio, continuando de onde o
+// item anterior parou para não favorecer o primeiro personagem.
+func PlanEvenSplit(characterIDs []int, items []PartyInventoryItem, coins Coins) []LootAllocation {
+	if len(characterIDs) == 0 {
+		return nil
+	}
+
+	allocations := make([]LootAllocation, len(characterIDs))
+	for i, characterID := range characterIDs {
+		allocations[i] = LootAllocation{CharacterID: characterID, Coins: Coins{}}
+	}
+
+	for denomination, amount := range coins {
+		share := amount / len(characterIDs)
+		if share <= 0 {
+			continue
+		}
+		for i := range allocations {
+			allocations[i].Coins[denomination] = share
+		}
+	}
+
+	next := 0
+	for _, item := range items {
+		given := make([]int, len(characterIDs))
+		for unit := 0; unit < item.Quantity; unit++ {
+			given[next]++
+			next = (next + 1) % len(characterIDs)
+		}
+		for i, quantity := range given {
+			if quantity > 0 {
+				allocations[i].Items = append(allocations[i].Items, LootItemAllocation{ItemID: item.ID, Quantity: quantity})
+			}
+		}
+	}
+
+	return allocations
+}
+
+// AddToEquipment acrescenta quantity unidades de name ao equipamento do personagem, somando
+// à entrada de mesmo nome se existir. Aceita tanto a lista de itens usada pela ficha quanto o
+// formato antigo {"items": [...]}.
+func AddToEquipment(equipment JSONBFlexible, name string, quantity int) JSONBFlexible {
+	var list []any
+	wrapper, isMap := equipment.Data.(map[string]any)
+	switch data := equipment.Data.(type) {
+	case []any:
+		list = data
+	case map[string]any:
+		list, _ = data["items"].([]any)
+	}
+
+	merged := false
+	for i, entry := range list {
+		item, ok := entry.(map[string]any)
+		if !ok || item["name"] != name {
+			continue
+		}
+		current, _ := item["quantity"].(float64)
+		if current <= 0 {
+			current = 1
+		}
+		item["quantity"] = current + float64(quantity)
+		list[i] = item
+		merged = true
+		break
+	}
+	if !merged {
+		list = append(list, map[string]any{"name": name, "quantity": float64(quantity)})
+	}
+
+	if isMap {
+		wrapper["items"] = list
+		return JSONBFlexible{Data: wrapper}
+	}
+	return JSONBFlexible{Data: list}
+}
+
+// AddCoinsToEquipment guarda as moedas no equipamento do personagem, uma entrada por denominação
+func AddCoinsToEquipment(equipment JSONBFlexible, coins Coins) JSONBFlexible {
+	for _, denomination := range coins.denominations() {
+		if coins[denomination] <= 0 {
+			continue
+		}
+		name := coinNames[denomination]
+		if name == "" {
+			name = denomination
+		}
+		equipment = AddToEquipment(equipment, name, coins[denomination])
+	}
+	return equipment
+}
+
+// denominations lista as denominações presentes: as conhecidas em ordem de valor, depois as demais
+func (c Coins) denominations() []string {
+	known := map[string]bool{}
+	result := []string{}
+	for _, denomination := range CoinDenominations {
+		known[denomination] = true
+		if _, ok := c[denomination]; ok {
+			result = append(result, denomination)
+		}
+	}
+
+	others := []string{}
+	for denomination := range c {
+		if !known[denomination] {
+			others = append(others, denomination)
+		}
+	}
+	sort.Strings(others)
+	return append(result, others...)
+}
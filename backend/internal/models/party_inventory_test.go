@@ -0,0 +1,87 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestPlanEvenSplit(t *testing.T) {
+	items := []PartyInventoryItem{
+		{ID: 1, Name: "Potion of Healing", Quantity: 3},
+		{ID: 2, Name: "Ruby", Quantity: 1},
+	}
+	coins := Coins{"gp": 101, "sp": 1}
+
+	allocations := PlanEvenSplit([]int{10, 20}, items, coins)
+	if len(allocations) != 2 {
+		t.Fatalf("expected 2 allocations, got %d", len(allocations))
+	}
+
+	for _, allocation := range allocations {
+		if allocation.Coins["gp"] != 50 {
+			t.Fatalf("expected 50 gp each, got %+v", allocation.Coins)
+		}
+		if _, ok := allocation.Coins["sp"]; ok {
+			t.Fatalf("1 sp cannot be split and should stay in the purse, got %+v", allocation.Coins)
+		}
+	}
+
+	// Poções: 10, 20, 10; o rubi continua o rodízio e vai para 20
+	first, second := allocations[0], allocations[1]
+	if len(first.Items) != 1 || first.Items[0] != (LootItemAllocation{ItemID: 1, Quantity: 2}) {
+		t.Fatalf("unexpected items for first character: %+v", first.Items)
+	}
+	if len(second.Items) != 2 || second.Items[0].Quantity != 1 || second.Items[1] != (LootItemAllocation{ItemID: 2, Quantity: 1}) {
+		t.Fatalf("unexpected items for second character: %+v", second.Items)
+	}
+}
+
+func TestCoinsSubtract(t *testing.T) {
+	purse := Coins{"gp": 10, "sp": 5}
+
+	if err := purse.Subtract(Coins{"gp": 11, "sp": 1}); err == nil {
+		t.Fatalf("expected error when not enough gold")
+	}
+	if purse["sp"] != 5 {
+		t.Fatalf("failed subtraction must not change the purse, got %+v", purse)
+	}
+
+	if err := purse.Subtract(Coins{"gp": 10, "sp": 2}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := purse["gp"]; ok || purse["sp"] != 3 {
+		t.Fatalf("unexpected purse after subtraction: %+v", purse)
+	}
+}
+
+func TestAddToEquipment(t *testing.T) {
+	var list []any
+	if err := json.Unmarshal([]byte(`[{"name":"Rope","quantity":1,"equipped":false}]`), &list); err != nil {
+		t.Fatalf("invalid fixture: %v", err)
+	}
+
+	equipment := AddToEquipment(JSONBFlexible{Data: list}, "Rope", 2)
+	equipment = AddCoinsToEquipment(equipment, Coins{"gp": 15})
+
+	items := equipment.Data.([]any)
+	if len(items) != 2 {
+		t.Fatalf("expected rope merged and gold appended, got %+v", items)
+	}
+	if items[0].(map[string]any)["quantity"] != 3.0 {
+		t.Fatalf("expected 3 ropes, got %+v", items[0])
+	}
+	if gold := items[1].(map[string]any); gold["name"] != "Gold Pieces" || gold["quantity"] != 15.0 {
+		t.Fatalf("unexpected gold entry: %+v", gold)
+	}
+
+	legacy := AddToEquipment(JSONBFlexible{Data: map[string]any{"items": []any{"Staff"}}}, "Ruby", 1)
+	wrapped := legacy.Data.(map[string]any)["items"].([]any)
+	if len(wrapped) != 2 {
+		t.Fatalf("expected legacy equipment to keep its format, got %+v", legacy.Data)
+	}
+
+	empty := AddToEquipment(JSONBFlexible{}, "Ruby", 1)
+	if len(empty.Data.([]any)) != 1 {
+		t.Fatalf("expected a new list for empty equipment, got %+v", empty.Data)
+	}
+}
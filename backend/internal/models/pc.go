@@ -13,7 +13,8 @@ type PC struct {
 	Description       string         `json:"description" db:"description"`
 	Level             int            `json:"level" db:"level"`
 	Race              string         `json:"race" db:"race"`
-	Class             string         `json:"class" db:"class"`
+	Class             string         `json:"class" db:"class"` // Resumo de class_levels
+	ClassLevels       ClassLevels    `json:"class_levels" db:"class_levels"`
 	Background        string         `json:"background" db:"background"`
 	Alignment         string         `json:"alignment" db:"alignment"`
 	Attributes        JSONBFlexible  `json:"attributes" db:"attributes"`
@@ -143,8 +144,11 @@ type PCStats struct {
 	Skills             map[string]interface{} `json:"skills,omitempty"`
 }
 
-// Funções auxiliares para calcular modificadores
+// Funções auxiliares para calcular modificadores (arredonda para baixo: 7 → -2)
 func CalculateModifier(score int) int {
+	if score < 10 {
+		return (score - 11) / 2
+	}
 	return (score - 10) / 2
 }
 
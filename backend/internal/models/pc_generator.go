@@ -0,0 +1,236 @@
+package models
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Origem da ficha gerada em /api/pcs/generate
+const (
+	PCGeneratorService  = "ai-service" // serviço Python de geração
+	PCGeneratorFallback = "fallback"   // gerador em Go, usado quando o serviço não responde
+)
+
+// GeneratedPC é o PC gerado e gravado, com o gerador que o montou
+type GeneratedPC struct {
+	PC        PC     `json:"pc"`
+	Generator string `json:"generator"`
+}
+
+// GenerationAbilityMethods traduz o attributes_method da geração ("rolagem", "array",
+// "compra") para os métodos do construtor de personagens
+var GenerationAbilityMethods = map[string]string{
+	"rolagem": AbilityMethodRoll,
+	"array":   AbilityMethodStandardArray,
+	"compra":  AbilityMethodPointBuy,
+}
+
+// Opções sorteadas pela geração automática (índices do SRD)
+var (
+	GenerationRaces   = []string{"dragonborn", "dwarf", "elf", "gnome", "half-elf", "half-orc", "halfling", "human", "tiefling"}
+	GenerationClasses = []string{
+		"barbarian", "bard", "cleric", "druid", "fighter", "monk",
+		"paladin", "ranger", "rogue", "sorcerer", "warlock", "wizard",
+	}
+	GenerationBackgrounds = []string{
+		"acolyte", "charlatan", "criminal", "entertainer", "folk-hero", "guild-artisan", "hermit",
+		"noble", "outlander", "sage", "sailor", "soldier", "urchin",
+	}
+)
+
+// GenerationPointBuy é a distribuição da compra de atributos na geração (27 pontos)
+var GenerationPointBuy = []int{15, 15, 13, 10, 10, 8}
+
+// classAbilityPriority são os dois atributos principais de cada classe do SRD
+var classAbilityPriority = map[string][]string{
+	"barbarian": {"strength", "constitution"},
+	"bard":      {"charisma", "dexterity"},
+	"cleric":    {"wisdom", "strength"},
+	"druid":     {"wisdom", "constitution"},
+	"fighter":   {"strength", "constitution"},
+	"monk":      {"dexterity", "wisdom"},
+	"paladin":   {"strength", "charisma"},
+	"ranger":    {"dexterity", "wisdom"},
+	"rogue":     {"dexterity", "intelligence"},
+	"sorcerer":  {"charisma", "constitution"},
+	"warlock":   {"charisma", "constitution"},
+	"wizard":    {"intelligence", "constitution"},
+}
+
+// AssignAbilityScores distribui os valores pela classe: os maiores vão para os atributos
+// principais, depois Constituição e os demais na ordem de AbilityNames
+func AssignAbilityScores(class string, values []int) map[string]int {
+	order := slices.Clone(classAbilityPriority[strings.ToLower(class)])
+	for _, ability := range append([]string{"constitution"}, AbilityNames...) {
+		if !slices.Contains(order, ability) {
+			order = append(order, ability)
+		}
+	}
+
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	slices.Reverse(sorted)
+
+	scores := make(map[string]int, len(AbilityNames))
+	for i, ability := range order {
+		score := 10
+		if i < len(sorted) {
+			score = sorted[i]
+		}
+		scores[ability] = score
+	}
+	return scores
+}
+
+// StartingItem é um item do equipamento inicial
+type StartingItem struct {
+	Name     string
+	Quantity int
+	Equipped bool
+}
+
+// classStartingEquipment é o equipamento inicial de cada classe, com nomes do SRD para que
+// armadura e escudo equipados entrem no cálculo da CA
+var classStartingEquipment = map[string][]StartingItem{
+	"barbarian": {{"Greataxe", 1, true}, {"Handaxe", 2, false}, {"Javelin", 4, false}, {"Explorer's Pack", 1, false}},
+	"bard":      {{"Leather Armor", 1, true}, {"Rapier", 1, true}, {"Dagger", 1, false}, {"Lute", 1, false}, {"Entertainer's Pack", 1, false}},
+	"cleric":    {{"Scale Mail", 1, true}, {"Shield", 1, true}, {"Mace", 1, true}, {"Light Crossbow", 1, false}, {"Crossbow bolt", 20, false}, {"Priest's Pack", 1, false}},
+	"druid":     {{"Leather Armor", 1, true}, {"Shield", 1, true}, {"Scimitar", 1, true}, {"Explorer's Pack", 1, false}},
+	"fighter":   {{"Chain Mail", 1, true}, {"Shield", 1, true}, {"Longsword", 1, true}, {"Light Crossbow", 1, false}, {"Crossbow bolt", 20, false}, {"Dungeoneer's Pack", 1, false}},
+	"monk":      {{"Shortsword", 1, true}, {"Dart", 10, false}, {"Explorer's Pack", 1, false}},
+	"paladin":   {{"Chain Mail", 1, true}, {"Shield", 1, true}, {"Longsword", 1, true}, {"Javelin", 5, false}, {"Priest's Pack", 1, false}},
+	"ranger":    {{"Scale Mail", 1, true}, {"Shortsword", 2, true}, {"Longbow", 1, false}, {"Arrow", 20, false}, {"Explorer's Pack", 1, false}},
+	"rogue":     {{"Leather Armor", 1, true}, {"Rapier", 1, true}, {"Shortbow", 1, false}, {"Arrow", 20, false}, {"Dagger", 2, false}, {"Thieves' Tools", 1, false}, {"Burglar's Pack", 1, false}},
+	"sorcerer":  {{"Light Crossbow", 1, true}, {"Crossbow bolt", 20, false}, {"Dagger", 2, false}, {"Component pouch", 1, false}, {"Dungeoneer's Pack", 1, false}},
+	"warlock":   {{"Leather Armor", 1, true}, {"Light Crossbow", 1, true}, {"Crossbow bolt", 20, false}, {"Dagger", 2, false}, {"Component pouch", 1, false}, {"Scholar's Pack", 1, false}},
+	"wizard":    {{"Quarterstaff", 1, true}, {"Component pouch", 1, false}, {"Spellbook", 1, false}, {"Scholar's Pack", 1, false}},
+}
+
+// defaultStartingEquipment vale para classes homebrew
+var defaultStartingEquipment = []StartingItem{{"Dagger", 1, true}, {"Explorer's Pack", 1, false}}
+
+// StartingEquipment monta o equipamento inicial da classe no formato de pc.equipment
+// ({name, quantity, equipped, description})
+func StartingEquipment(class string) []any {
+	items, ok := classStartingEquipment[strings.ToLower(class)]
+	if !ok {
+		items = defaultStartingEquipment
+	}
+
+	equipment := make([]any, 0, len(items))
+	for _, item := range items {
+		equipment = append(equipment, map[string]any{
+			"name":        item.Name,
+			"quantity":    item.Quantity,
+			"equipped":    item.Equipped,
+			"description": "",
+		})
+	}
+	return equipment
+}
+
+// ClassSpellSlots calcula os espaços de magia de uma única classe pela sua progressão:
+// meio-conjuradores conjuram a partir do 2º nível e as subclasses conjuradoras de guerreiro e
+// ladino a partir do 3º, ambos arredondando para cima na tabela de conjurador
+func ClassSpellSlots(rules ClassRules, entry ClassLevel) (map[string]int, *PactMagic) {
+	casterLevel := 0
+	switch rules.CasterProgression {
+	case CasterFull:
+		casterLevel = entry.Level
+	case CasterHalf:
+		if entry.Level >= 2 {
+			casterLevel = (entry.Level + 1) / 2
+		}
+	case CasterThird:
+		subclass, ok := thirdCasterSubclasses[strings.ToLower(rules.Index)]
+		if entry.Level >= 3 && (!ok || strings.EqualFold(entry.Subclass, subclass)) {
+			casterLevel = (entry.Level + 2) / 3
+		}
+	case CasterPact:
+		return nil, PactMagicFor(entry.Level)
+	}
+
+	if casterLevel < 1 {
+		return nil, nil
+	}
+	slots := map[string]int{}
+	for circle, count := range MulticlassSpellSlots[min(casterLevel, MaxCharacterLevel)-1] {
+		if count > 0 {
+			slots[strconv.Itoa(circle+1)] = count
+		}
+	}
+	return slots, nil
+}
+
+// GeneratedSpellCounts é quantos truques e magias com círculo a geração escolhe no nível
+func GeneratedSpellCounts(level int) (cantrips, spells int) {
+	switch {
+	case level >= 10:
+		cantrips = 5
+	case level >= 4:
+		cantrips = 4
+	default:
+		cantrips = 3
+	}
+	return cantrips, level + 1
+}
+
+// ApplyGeneratedSpells grava os espaços de magia e a Magia de Pacto da geração. Com known,
+// substitui as magias conhecidas; sem, mantém as que vieram na ficha.
+func ApplyGeneratedSpells(pc *PC, slots map[string]int, pact *PactMagic, known []DnDSpell) {
+	spells, _ := pc.Spells.Data.(map[string]any)
+	if spells == nil {
+		spells = map[string]any{"known_spells": []any{}}
+	}
+	if known != nil {
+		list := make([]any, 0, len(known))
+		for _, spell := range known {
+			list = append(list, map[string]any{
+				"name":     spell.Name,
+				"level":    spell.Level,
+				"school":   spell.School,
+				"prepared": true,
+			})
+		}
+		spells["known_spells"] = list
+	}
+	pc.Spells = JSONBFlexible{Data: spells}
+
+	applySpellSlots(pc, slots)
+	if pact != nil {
+		applyPactMagic(pc, *pact)
+	}
+}
+
+// MinGeneratedAbilityScore é o menor atributo que qualquer método de geração produz (4d6
+// descartando o menor)
+const MinGeneratedAbilityScore = 3
+
+// CompleteGeneratedPC preenche o que o serviço de geração não devolve: os campos JSONB
+// vazios e os espaços de magia pela progressão de conjuração da classe no SRD
+func CompleteGeneratedPC(pc *PC) {
+	if pc.Abilities.Data == nil {
+		pc.Abilities = JSONBFlexible{Data: map[string]any{}}
+	}
+	if pc.Skills.Data == nil {
+		pc.Skills = JSONBFlexible{Data: map[string]any{}}
+	}
+	if pc.Attacks.Data == nil {
+		pc.Attacks = JSONBFlexible{Data: []any{}}
+	}
+	if pc.Equipment.Data == nil {
+		pc.Equipment = JSONBFlexible{Data: []any{}}
+	}
+	if pc.ProficiencyBonus <= 0 {
+		pc.ProficiencyBonus = pc.GetProficiencyBonus()
+	}
+
+	classes := pc.Classes()
+	if len(classes) == 0 {
+		return
+	}
+	index := strings.ToLower(classes[0].Class)
+	slots, pact := ClassSpellSlots(ClassRules{Index: index, CasterProgression: CasterProgressionFor(index)}, classes[0])
+	ApplyGeneratedSpells(pc, slots, pact, nil)
+}
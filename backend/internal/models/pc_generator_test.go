@@ -0,0 +1,59 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestAssignAbilityScores(t *testing.T) {
+	scores := AssignAbilityScores("Wizard", StandardArray)
+	if scores["intelligence"] != 15 || scores["constitution"] != 14 || scores["strength"] != 13 || scores["charisma"] != 8 {
+		t.Fatalf("unexpected wizard scores: %v", scores)
+	}
+
+	// Classes homebrew priorizam Constituição
+	scores = AssignAbilityScores("Gunslinger", GenerationPointBuy)
+	if scores["constitution"] != 15 || scores["strength"] != 15 || scores["dexterity"] != 13 || len(scores) != 6 {
+		t.Fatalf("unexpected homebrew scores: %v", scores)
+	}
+}
+
+func TestClassSpellSlots(t *testing.T) {
+	paladin := ClassRules{Index: "paladin", CasterProgression: CasterHalf}
+	if slots, _ := ClassSpellSlots(paladin, ClassLevel{Class: "Paladin", Level: 1}); slots != nil {
+		t.Fatalf("paladins do not cast at level 1, got %v", slots)
+	}
+	if slots, _ := ClassSpellSlots(paladin, ClassLevel{Class: "Paladin", Level: 5}); slots["1"] != 4 || slots["2"] != 2 {
+		t.Fatalf("unexpected paladin slots: %v", slots)
+	}
+
+	fighter := ClassRules{Index: "fighter", CasterProgression: CasterThird}
+	if slots, _ := ClassSpellSlots(fighter, ClassLevel{Class: "Fighter", Level: 7, Subclass: "Champion"}); slots != nil {
+		t.Fatalf("champions do not cast, got %v", slots)
+	}
+	if slots, _ := ClassSpellSlots(fighter, ClassLevel{Class: "Fighter", Level: 7, Subclass: "Eldritch Knight"}); slots["1"] != 4 || slots["2"] != 2 {
+		t.Fatalf("unexpected eldritch knight slots: %v", slots)
+	}
+
+	slots, pact := ClassSpellSlots(ClassRules{Index: "warlock", CasterProgression: CasterPact}, ClassLevel{Class: "Warlock", Level: 5})
+	if slots != nil || pact == nil || pact.Slots != 2 || pact.SlotLevel != 3 {
+		t.Fatalf("unexpected warlock slots: %v %+v", slots, pact)
+	}
+}
+
+func TestCompleteGeneratedPC(t *testing.T) {
+	pc := &PC{
+		Class:  "Wizard",
+		Level:  3,
+		Spells: JSONBFlexible{Data: map[string]any{"known_spells": []any{map[string]any{"name": "Light"}}}},
+	}
+	CompleteGeneratedPC(pc)
+
+	spells := pc.Spells.Data.(map[string]any)
+	slots := spells["spell_slots"].(map[string]any)
+	if slots["2"].(map[string]any)["total"] != 2 || len(spells["known_spells"].([]any)) != 1 {
+		t.Fatalf("unexpected spells: %v", spells)
+	}
+	if pc.ProficiencyBonus != 2 || pc.Skills.Data == nil || pc.Equipment.Data == nil {
+		t.Fatalf("defaults not filled: %+v", pc)
+	}
+}
@@ -26,8 +26,11 @@ func TestCalculateModifier(t *testing.T) {
 	if CalculateModifier(10) != 0 {
 		t.Fatal("expected 0 for 10")
 	}
-	if CalculateModifier(7) != -1 {
-		t.Fatal("expected -1 for 7")
+	if CalculateModifier(7) != -2 {
+		t.Fatal("expected -2 for 7")
+	}
+	if CalculateModifier(8) != -1 {
+		t.Fatal("expected -1 for 8")
 	}
 	if CalculateModifier(18) != 4 {
 		t.Fatal("expected 4 for 18")
@@ -0,0 +1,70 @@
+package models
+
+import "time"
+
+// Status de uma quest
+const (
+	QuestStatusActive    = "active"
+	QuestStatusCompleted = "completed"
+	QuestStatusFailed    = "failed"
+)
+
+var QuestStatuses = []string{QuestStatusActive, QuestStatusCompleted, QuestStatusFailed}
+
+// Quest representa uma missão da campanha
+type Quest struct {
+	ID           int              `json:"id" db:"id"`
+	CampaignID   int              `json:"campaign_id" db:"campaign_id"`
+	Title        string           `json:"title" db:"title"`
+	Description  string           `json:"description" db:"description"`
+	GiverNPCID   *int             `json:"giver_npc_id,omitempty" db:"giver_npc_id"`
+	GiverNPCName string           `json:"giver_npc_name,omitempty" db:"giver_npc_name"`
+	GiverName    string           `json:"giver_name" db:"giver_name"` // Quest giver livre, sem NPC cadastrado
+	Status       string           `json:"status" db:"status"`         // active, completed, failed
+	Revealed     bool             `json:"revealed" db:"revealed"`     // Players só veem quests reveladas
+	Rewards      JSONB            `json:"rewards" db:"rewards"`
+	CompletedAt  *time.Time       `json:"completed_at,omitempty" db:"completed_at"`
+	CreatedAt    time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time        `json:"updated_at" db:"updated_at"`
+	Objectives   []QuestObjective `json:"objectives"`
+}
+
+// QuestObjective representa um objetivo de uma quest
+type QuestObjective struct {
+	ID          int        `json:"id" db:"id"`
+	QuestID     int        `json:"quest_id" db:"quest_id"`
+	Description string     `json:"description" db:"description"`
+	Completed   bool       `json:"completed" db:"completed"`
+	SortOrder   int        `json:"sort_order" db:"sort_order"`
+	CompletedAt *time.Time `json:"completed_at,omitempty" db:"completed_at"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+}
+
+type CreateQuestRequest struct {
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	GiverNPCID  *int     `json:"giver_npc_id"`
+	GiverName   string   `json:"giver_name"`
+	Revealed    bool     `json:"revealed"`
+	Rewards     JSONB    `json:"rewards"`
+	Objectives  []string `json:"objectives"` // Descrições dos objetivos iniciais
+}
+
+type UpdateQuestRequest struct {
+	Title       string  `json:"title"`
+	Description *string `json:"description"`
+	GiverNPCID  *int    `json:"giver_npc_id"` // 0 remove o NPC
+	GiverName   *string `json:"giver_name"`
+	Revealed    *bool   `json:"revealed"`
+	Rewards     JSONB   `json:"rewards"`
+}
+
+type UpdateQuestStatusRequest struct {
+	Status string `json:"status"`
+}
+
+type QuestObjectiveRequest struct {
+	Description string `json:"description"`
+	Completed   *bool  `json:"completed"`
+	SortOrder   *int   `json:"sort_order"`
+}
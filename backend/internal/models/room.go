@@ -11,6 +11,7 @@ type Room struct {
 	SceneState JSONBFlexible `json:"scene_state,omitempty" db:"scene_state"`
 	CreatedAt  time.Time     `json:"created_at" db:"created_at"`
 	UpdatedAt  time.Time     `json:"updated_at" db:"updated_at"`
+	ArchivedAt *time.Time    `json:"archived_at,omitempty" db:"archived_at"` // Somente leitura após a conclusão da campanha
 	Members    []RoomMember  `json:"members,omitempty"`
 	Metadata   JSONB         `json:"metadata,omitempty" db:"metadata"`
 }
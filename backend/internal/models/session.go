@@ -0,0 +1,81 @@
+package models
+
+import "time"
+
+// Status de uma sessão de campanha
+const (
+	SessionStatusScheduled  = "scheduled"
+	SessionStatusInProgress = "in_progress"
+	SessionStatusCompleted  = "completed"
+	SessionStatusCancelled  = "cancelled"
+)
+
+// Status de presença de um jogador na sessão
+const (
+	AttendancePending   = "pending"
+	AttendanceConfirmed = "confirmed"
+	AttendanceDeclined  = "declined"
+	AttendanceAttended  = "attended"
+	AttendanceAbsent    = "absent"
+)
+
+// AttendanceRSVPStatuses são os status que o próprio jogador pode informar
+var AttendanceRSVPStatuses = []string{AttendancePending, AttendanceConfirmed, AttendanceDeclined}
+
+// AttendanceStatuses são todos os status de presença (o DM pode usar qualquer um)
+var AttendanceStatuses = []string{AttendancePending, AttendanceConfirmed, AttendanceDeclined, AttendanceAttended, AttendanceAbsent}
+
+// CampaignSession representa uma sessão numerada de uma campanha
+type CampaignSession struct {
+	ID            int                 `json:"id" db:"id"`
+	CampaignID    int                 `json:"campaign_id" db:"campaign_id"`
+	SessionNumber int                 `json:"session_number" db:"session_number"`
+	Title         string              `json:"title" db:"title"`
+	ScheduledAt   *time.Time          `json:"scheduled_at" db:"scheduled_at"`
+	Status        string              `json:"status" db:"status"` // scheduled, in_progress, completed, cancelled
+	RoomID        *string             `json:"room_id,omitempty" db:"room_id"`
+	StartedAt     *time.Time          `json:"started_at,omitempty" db:"started_at"`
+	EndedAt       *time.Time          `json:"ended_at,omitempty" db:"ended_at"`
+	DMNotes       string              `json:"dm_notes,omitempty" db:"dm_notes"` // Visível apenas para o DM
+	Recap         string              `json:"recap" db:"recap"`
+	CreatedAt     time.Time           `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time           `json:"updated_at" db:"updated_at"`
+	Attendance    []SessionAttendance `json:"attendance,omitempty"`
+}
+
+// SessionAttendance representa a presença e as notas de um jogador na sessão
+type SessionAttendance struct {
+	SessionID int       `json:"session_id" db:"session_id"`
+	UserID    int       `json:"user_id" db:"user_id"`
+	Username  string    `json:"username,omitempty" db:"username"`
+	Status    string    `json:"status" db:"status"`         // pending, confirmed, declined, attended, absent
+	Notes     string    `json:"notes,omitempty" db:"notes"` // Notas do jogador
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type CreateSessionRequest struct {
+	Title         string     `json:"title"`
+	SessionNumber int        `json:"session_number"` // 0 = próximo número disponível
+	ScheduledAt   *time.Time `json:"scheduled_at"`
+}
+
+type UpdateSessionRequest struct {
+	Title       string     `json:"title"`
+	ScheduledAt *time.Time `json:"scheduled_at"`
+	Status      string     `json:"status"`
+	DMNotes     *string    `json:"dm_notes"`
+	Recap       *string    `json:"recap"`
+}
+
+type UpdateAttendanceRequest struct {
+	UserID int    `json:"user_id"` // Apenas o DM pode informar outro usuário
+	Status string `json:"status"`
+}
+
+type UpdateSessionNotesRequest struct {
+	Notes string `json:"notes"`
+}
+
+type EndSessionRequest struct {
+	Recap string `json:"recap"`
+}
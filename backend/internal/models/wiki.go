@@ -0,0 +1,93 @@
+package models
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Tipos de entrada do wiki da campanha
+const (
+	WikiEntryLocation = "location"
+	WikiEntryFaction  = "faction"
+	WikiEntryLore     = "lore"
+	WikiEntryItem     = "item"
+)
+
+var WikiEntryTypes = []string{WikiEntryLocation, WikiEntryFaction, WikiEntryLore, WikiEntryItem}
+
+// Visibilidade de uma entrada do wiki
+const (
+	WikiVisibilityDMOnly   = "dm_only"
+	WikiVisibilityAll      = "all"
+	WikiVisibilitySelected = "selected"
+)
+
+var WikiVisibilities = []string{WikiVisibilityDMOnly, WikiVisibilityAll, WikiVisibilitySelected}
+
+// WikiEntry representa uma entrada do diário/wiki da campanha
+type WikiEntry struct {
+	ID              int                `json:"id" db:"id"`
+	CampaignID      int                `json:"campaign_id" db:"campaign_id"`
+	EntryType       string             `json:"entry_type" db:"entry_type"` // location, faction, lore, item
+	Title           string             `json:"title" db:"title"`
+	Body            string             `json:"body" db:"body"` // Markdown
+	Tags            pq.StringArray     `json:"tags" db:"tags"`
+	Visibility      string             `json:"visibility" db:"visibility"` // dm_only, all, selected
+	SelectedPlayers pq.Int64Array      `json:"selected_players,omitempty" db:"selected_players"`
+	LinkedEntryIDs  pq.Int64Array      `json:"linked_entry_ids" db:"linked_entry_ids"`
+	CreatedBy       *int               `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt       time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at" db:"updated_at"`
+	LinkedEntries   []WikiEntrySummary `json:"linked_entries,omitempty"`
+	Backlinks       []WikiEntrySummary `json:"backlinks,omitempty"`
+}
+
+// WikiEntrySummary é a forma resumida usada nos links entre entradas
+type WikiEntrySummary struct {
+	ID        int    `json:"id"`
+	EntryType string `json:"entry_type"`
+	Title     string `json:"title"`
+}
+
+// CanView indica se o usuário pode ver a entrada (o DM vê todas)
+func (e *WikiEntry) CanView(userID int, isDM bool) bool {
+	if isDM {
+		return true
+	}
+
+	switch e.Visibility {
+	case WikiVisibilityAll:
+		return true
+	case WikiVisibilitySelected:
+		for _, id := range e.SelectedPlayers {
+			if int(id) == userID {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Summary retorna a forma resumida da entrada
+func (e *WikiEntry) Summary() WikiEntrySummary {
+	return WikiEntrySummary{ID: e.ID, EntryType: e.EntryType, Title: e.Title}
+}
+
+type WikiEntryRequest struct {
+	EntryType       string   `json:"entry_type"`
+	Title           string   `json:"title"`
+	Body            string   `json:"body"`
+	Tags            []string `json:"tags"`
+	Visibility      string   `json:"visibility"`       // padrão: dm_only
+	SelectedPlayers []int    `json:"selected_players"` // obrigatório quando visibility = selected
+	LinkedEntryIDs  []int    `json:"linked_entry_ids"`
+}
+
+// WikiFilters são os filtros da listagem do wiki
+type WikiFilters struct {
+	EntryType string
+	Tag       string
+	Search    string
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+func TestWikiEntry_CanView(t *testing.T) {
+	tests := []struct {
+		name       string
+		visibility string
+		selected   pq.Int64Array
+		userID     int
+		isDM       bool
+		want       bool
+	}{
+		{"dm sees dm_only", WikiVisibilityDMOnly, nil, 1, true, true},
+		{"player cannot see dm_only", WikiVisibilityDMOnly, nil, 2, false, false},
+		{"player sees all", WikiVisibilityAll, nil, 2, false, true},
+		{"selected player", WikiVisibilitySelected, pq.Int64Array{2, 3}, 3, false, true},
+		{"unselected player", WikiVisibilitySelected, pq.Int64Array{2, 3}, 4, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := WikiEntry{Visibility: tt.visibility, SelectedPlayers: tt.selected}
+			if got := entry.CanView(tt.userID, tt.isDM); got != tt.want {
+				t.Errorf("CanView() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
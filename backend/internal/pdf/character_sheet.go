@@ -0,0 +1,542 @@
+package pdf
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"rpg-saas-backend/internal/models"
+)
+
+// Margens e área útil da ficha
+const (
+	margin       = 36.0
+	contentWidth = PageWidth - 2*margin
+	pageBottom   = PageHeight - margin
+	lineHeight   = 12.0
+)
+
+// CharacterSheet renderiza a ficha 5e do personagem a partir dos campos gravados e dos
+// valores calculados pelas regras. A primeira página traz atributos, testes de resistência,
+// perícias, ataques e equipamento; a segunda, quando há conteúdo, características, magias,
+// personalidade e o que não coube na primeira.
+func CharacterSheet(pc *models.PC, sheet models.ComputedSheet) ([]byte, error) {
+	doc := NewDocument()
+	page := doc.AddPage()
+
+	writeHeader(page, pc)
+	writeCombatStats(page, pc, sheet)
+
+	const top = 166.0
+	left := &column{page: page, x: margin, y: top, width: 140, bottom: pageBottom}
+	writeAbilities(left, sheet)
+	writeSavingThrows(left, sheet)
+	writeSpellcastingStats(left, sheet)
+
+	middle := &column{page: page, x: margin + 150, y: top, width: 170, bottom: pageBottom}
+	writeSkills(middle, sheet)
+	proficiencies := middle.list("PROFICIENCIES & LANGUAGES", Regular, proficiencyLines(pc))
+
+	right := &column{page: page, x: margin + 330, y: top, width: contentWidth - 330, bottom: pageBottom}
+	attacks := writeAttacks(right, storedAttacks(pc))
+	equipment := right.list("EQUIPMENT", Regular, equipmentLines(pc))
+
+	more := &flow{doc: doc, title: pc.Name}
+	writeFeatures(more, pc)
+	writeSpells(more, pc, sheet)
+	writePersonality(more, pc)
+	more.list("ATTACKS (CONTINUED)", attacks)
+	more.list("PROFICIENCIES & LANGUAGES (CONTINUED)", proficiencies)
+	more.list("EQUIPMENT (CONTINUED)", equipment)
+
+	return doc.Bytes()
+}
+
+// writeHeader escreve nome, jogador, raça, classes, antecedente e tendência
+func writeHeader(page *Page, pc *models.PC) {
+	page.Text(margin, 56, Bold, 20, Truncate(Bold, 20, pc.Name, contentWidth-160))
+	if pc.PlayerName != "" {
+		page.TextRight(PageWidth-margin, 52, Regular, 9, Truncate(Regular, 9, "Player: "+pc.PlayerName, 150))
+	}
+
+	class := pc.Class
+	if classes := pc.Classes(); len(classes) > 0 {
+		class = classes.Summary()
+	}
+	page.Text(margin, 74, Regular, 11, Truncate(Regular, 11, fmt.Sprintf("Level %d %s %s", pc.Level, pc.Race, class), contentWidth))
+
+	details := []string{}
+	for _, field := range []struct{ label, value string }{
+		{"Subclass", pc.Subclass()},
+		{"Background", pc.Background},
+		{"Alignment", pc.Alignment},
+	} {
+		if strings.TrimSpace(field.value) != "" {
+			details = append(details, field.label+": "+field.value)
+		}
+	}
+	page.Text(margin, 88, Regular, 9, Truncate(Regular, 9, strings.Join(details, "   ·   "), contentWidth))
+	page.Line(margin, 96, PageWidth-margin, 96, 1)
+}
+
+// writeCombatStats escreve a faixa de CA, PV, iniciativa, deslocamento, proficiência,
+// percepção passiva e inspiração
+func writeCombatStats(page *Page, pc *models.PC, sheet models.ComputedSheet) {
+	hp := strconv.Itoa(pc.HP)
+	if pc.CurrentHP != nil {
+		hp = fmt.Sprintf("%d / %d", *pc.CurrentHP, pc.HP)
+	}
+	inspiration := "No"
+	if pc.Inspiration {
+		inspiration = "Yes"
+	}
+	proficiency := pc.ProficiencyBonus
+	if proficiency <= 0 {
+		proficiency = sheet.ProficiencyBonus
+	}
+
+	boxes := []struct{ label, value string }{
+		{"ARMOR CLASS", strconv.Itoa(pc.CA)},
+		{"HIT POINTS", hp},
+		{"INITIATIVE", signed(sheet.Initiative)},
+		{"SPEED", fmt.Sprintf("%d ft", sheet.Speed)},
+		{"PROFICIENCY", signed(proficiency)},
+		{"PASSIVE PERC.", strconv.Itoa(sheet.PassivePerception)},
+		{"INSPIRATION", inspiration},
+	}
+
+	const gap, y, height = 6.0, 106.0, 46.0
+	width := (contentWidth - gap*float64(len(boxes)-1)) / float64(len(boxes))
+	for i, box := range boxes {
+		x := margin + float64(i)*(width+gap)
+		page.FillRect(x, y, width, 13, 0.88)
+		page.Rect(x, y, width, height, 0.8)
+		page.TextCenter(x+width/2, y+9.5, Bold, 7, box.label)
+		page.TextCenter(x+width/2, y+36, Bold, 15, Truncate(Bold, 15, box.value, width-6))
+	}
+}
+
+// writeAbilities escreve os seis atributos com modificador e valor
+func writeAbilities(c *column, sheet models.ComputedSheet) {
+	const height, gap = 50.0, 6.0
+	for _, ability := range models.AbilityNames {
+		c.page.Rect(c.x, c.y, c.width, height, 0.8)
+		c.page.TextCenter(c.x+c.width/2, c.y+11, Bold, 8, strings.ToUpper(ability))
+		c.page.TextCenter(c.x+c.width/2, c.y+33, Bold, 18, signed(sheet.AbilityModifiers[ability]))
+		c.page.TextCenter(c.x+c.width/2, c.y+45, Regular, 9, strconv.Itoa(sheet.Attributes[ability]))
+		c.y += height + gap
+	}
+	c.y += 6
+}
+
+// writeSavingThrows escreve os testes de resistência, marcando os proficientes
+func writeSavingThrows(c *column, sheet models.ComputedSheet) {
+	c.title("SAVING THROWS")
+	for _, ability := range models.AbilityNames {
+		save := sheet.SavingThrows[ability]
+		c.bonusRow(save.Proficient, false, save.Bonus, capitalize(ability))
+	}
+	c.y += 8
+}
+
+// writeSpellcastingStats escreve atributo, CD e ataque de magia dos conjuradores
+func writeSpellcastingStats(c *column, sheet models.ComputedSheet) {
+	if sheet.Spellcasting == nil {
+		return
+	}
+	c.title("SPELLCASTING")
+	c.line(Regular, 9, "Ability: "+capitalize(sheet.Spellcasting.Ability))
+	c.line(Regular, 9, fmt.Sprintf("Spell save DC: %d", sheet.Spellcasting.SaveDC))
+	c.line(Regular, 9, "Spell attack: "+signed(sheet.Spellcasting.AttackBonus))
+	c.y += 8
+}
+
+// writeSkills escreve as 18 perícias com o atributo e o bônus, marcando proficiência e
+// especialização
+func writeSkills(c *column, sheet models.ComputedSheet) {
+	c.title("SKILLS")
+	for _, def := range models.Skills {
+		skill := sheet.Skills[def.Index]
+		name := fmt.Sprintf("%s (%s)", def.Name, abbreviation(def.Ability))
+		c.bonusRow(skill.Proficient, skill.Expertise, skill.Bonus, name)
+	}
+	c.y += 8
+}
+
+// writeAttacks escreve a tabela de ataques e devolve as linhas que não couberam
+func writeAttacks(c *column, attacks []attack) []string {
+	if len(attacks) == 0 {
+		return nil
+	}
+	c.title("ATTACKS")
+
+	damageX := c.x + c.width - 78
+	for i, atk := range attacks {
+		if !c.fits() {
+			rest := []string{}
+			for _, atk := range attacks[i:] {
+				rest = append(rest, atk.String())
+			}
+			c.more(len(rest))
+			return rest
+		}
+		c.page.Text(c.x+2, c.y+9, Regular, 9, Truncate(Regular, 9, atk.Name, damageX-c.x-34))
+		c.page.TextRight(damageX-6, c.y+9, Bold, 9, atk.Bonus)
+		c.page.Text(damageX, c.y+9, Regular, 9, Truncate(Regular, 9, atk.Damage, c.x+c.width-damageX))
+		c.y += lineHeight
+	}
+	c.y += 8
+	return nil
+}
+
+// writeFeatures lista as características de classe, raça e talentos
+func writeFeatures(f *flow, pc *models.PC) {
+	features := slices.DeleteFunc(slices.Clone([]string(pc.Features)), func(feature string) bool {
+		return strings.TrimSpace(feature) == ""
+	})
+	f.list("FEATURES & TRAITS", features)
+}
+
+// writeSpells escreve os espaços de magia, a Magia de Pacto e as magias conhecidas por
+// círculo
+func writeSpells(f *flow, pc *models.PC, sheet models.ComputedSheet) {
+	spells, _ := pc.Spells.Data.(map[string]any)
+
+	slots := []string{}
+	stored, _ := spells["spell_slots"].(map[string]any)
+	for circle := 1; circle <= 9; circle++ {
+		entry, _ := stored[strconv.Itoa(circle)].(map[string]any)
+		total, _ := intValue(entry["total"])
+		if total <= 0 {
+			continue
+		}
+		slot := fmt.Sprintf("%s: %d", ordinal(circle), total)
+		if used, _ := intValue(entry["used"]); used > 0 {
+			slot += fmt.Sprintf(" (%d used)", used)
+		}
+		slots = append(slots, slot)
+	}
+
+	pact := ""
+	if entry, ok := spells["pact_slots"].(map[string]any); ok {
+		total, _ := intValue(entry["total"])
+		level, _ := intValue(entry["slot_level"])
+		if total > 0 {
+			pact = fmt.Sprintf("%d slots of %s level", total, ordinal(level))
+		}
+	}
+
+	byLevel := map[int][]string{}
+	known, _ := spells["known_spells"].([]any)
+	for _, raw := range known {
+		entry, _ := raw.(map[string]any)
+		name, _ := entry["name"].(string)
+		if strings.TrimSpace(name) == "" {
+			continue
+		}
+		level, _ := intValue(entry["level"])
+		byLevel[level] = append(byLevel[level], name)
+	}
+
+	if len(slots) == 0 && pact == "" && len(byLevel) == 0 {
+		return
+	}
+
+	f.section("SPELLS")
+	if sheet.Spellcasting != nil {
+		f.paragraph(Regular, 9, fmt.Sprintf("Ability: %s · Save DC: %d · Spell attack: %s",
+			capitalize(sheet.Spellcasting.Ability), sheet.Spellcasting.SaveDC, signed(sheet.Spellcasting.AttackBonus)))
+	}
+	if len(slots) > 0 {
+		f.labeled("Spell slots", strings.Join(slots, " · "))
+	}
+	if pact != "" {
+		f.labeled("Pact Magic", pact)
+	}
+	for level := 0; level <= 9; level++ {
+		names := byLevel[level]
+		if len(names) == 0 {
+			continue
+		}
+		slices.Sort(names)
+		label := "Cantrips"
+		if level > 0 {
+			label = ordinal(level) + " level"
+		}
+		f.labeled(label, strings.Join(names, ", "))
+	}
+	f.y += 6
+}
+
+// writePersonality escreve traços, ideais, vínculos, defeitos e descrição
+func writePersonality(f *flow, pc *models.PC) {
+	fields := []struct{ label, value string }{
+		{"Personality traits", pc.PersonalityTraits},
+		{"Ideals", pc.Ideals},
+		{"Bonds", pc.Bonds},
+		{"Flaws", pc.Flaws},
+		{"Description", pc.Description},
+	}
+	titled := false
+	for _, field := range fields {
+		if strings.TrimSpace(field.value) == "" {
+			continue
+		}
+		if !titled {
+			f.section("PERSONALITY")
+			titled = true
+		}
+		f.labeled(field.label, field.value)
+	}
+	if titled {
+		f.y += 6
+	}
+}
+
+// column é uma coluna de largura fixa da primeira página
+type column struct {
+	page                *Page
+	x, y, width, bottom float64
+}
+
+// fits indica se cabe mais uma linha, reservando espaço para o aviso de continuação
+func (c *column) fits() bool {
+	return c.y+2*lineHeight <= c.bottom
+}
+
+func (c *column) title(text string) {
+	c.page.FillRect(c.x, c.y, c.width, 14, 0.88)
+	c.page.Text(c.x+4, c.y+10, Bold, 8, text)
+	c.y += 18
+}
+
+func (c *column) line(font Font, size float64, text string) {
+	c.page.Text(c.x+2, c.y+9, font, size, Truncate(font, size, text, c.width-4))
+	c.y += lineHeight
+}
+
+// bonusRow escreve uma linha com o marcador de proficiência, o bônus e o nome
+func (c *column) bonusRow(proficient, expertise bool, bonus int, name string) {
+	c.marker(c.x+2, proficient)
+	if expertise {
+		c.marker(c.x+10, true)
+	}
+	c.page.TextRight(c.x+40, c.y+9, Bold, 9, signed(bonus))
+	c.page.Text(c.x+46, c.y+9, Regular, 9, Truncate(Regular, 9, name, c.width-48))
+	c.y += lineHeight + 1
+}
+
+func (c *column) marker(x float64, filled bool) {
+	if filled {
+		c.page.FillRect(x, c.y+2.5, 6, 6, 0)
+		return
+	}
+	c.page.Rect(x, c.y+2.5, 6, 6, 0.6)
+}
+
+// more avisa quantos itens continuam na página seguinte
+func (c *column) more(count int) {
+	c.page.Text(c.x+2, c.y+9, Bold, 8, fmt.Sprintf("+ %d more on the next page", count))
+	c.y += lineHeight
+}
+
+// list escreve uma lista com título e devolve os itens que não couberam
+func (c *column) list(title string, font Font, items []string) []string {
+	if len(items) == 0 || !c.fits() {
+		return items
+	}
+	c.title(title)
+	for i, item := range items {
+		if !c.fits() {
+			c.more(len(items) - i)
+			return items[i:]
+		}
+		c.line(font, 9, item)
+	}
+	c.y += 8
+	return nil
+}
+
+// flow escreve seções em largura total a partir da segunda página, abrindo novas páginas
+// quando necessário
+type flow struct {
+	doc   *Document
+	page  *Page
+	title string
+	y     float64
+}
+
+// ensure garante espaço para height pontos, abrindo uma nova página com o nome do
+// personagem no topo
+func (f *flow) ensure(height float64) {
+	if f.page != nil && f.y+height <= pageBottom {
+		return
+	}
+	f.page = f.doc.AddPage()
+	f.page.Text(margin, 50, Bold, 12, Truncate(Bold, 12, f.title, contentWidth-60))
+	f.page.TextRight(PageWidth-margin, 50, Regular, 9, fmt.Sprintf("Page %d", f.doc.PageCount()))
+	f.page.Line(margin, 58, PageWidth-margin, 58, 1)
+	f.y = 70
+}
+
+func (f *flow) section(title string) {
+	f.ensure(18 + 2*lineHeight)
+	f.page.FillRect(margin, f.y, contentWidth, 14, 0.88)
+	f.page.Text(margin+4, f.y+10, Bold, 8, title)
+	f.y += 20
+}
+
+func (f *flow) paragraph(font Font, size float64, text string) {
+	for _, line := range WrapText(font, size, text, contentWidth-4) {
+		f.ensure(lineHeight)
+		f.page.Text(margin+2, f.y+9, font, size, line)
+		f.y += lineHeight
+	}
+}
+
+// labeled escreve um rótulo em negrito com o texto nas linhas seguintes
+func (f *flow) labeled(label, text string) {
+	f.ensure(2 * lineHeight)
+	f.page.Text(margin+2, f.y+9, Bold, 9, label)
+	f.y += lineHeight
+	f.paragraph(Regular, 9, text)
+	f.y += 3
+}
+
+// list escreve uma lista com marcadores
+func (f *flow) list(title string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	f.section(title)
+	for _, item := range items {
+		f.paragraph(Regular, 9, "• "+item)
+	}
+	f.y += 6
+}
+
+// attack é um ataque gravado em pc.attacks ({name, bonus, damage, type, range})
+type attack struct {
+	Name, Bonus, Damage string
+}
+
+func (a attack) String() string {
+	return strings.TrimSpace(fmt.Sprintf("%s %s %s", a.Name, a.Bonus, a.Damage))
+}
+
+func storedAttacks(pc *models.PC) []attack {
+	list, _ := pc.Attacks.Data.([]any)
+	attacks := []attack{}
+	for _, raw := range list {
+		entry, _ := raw.(map[string]any)
+		name, _ := entry["name"].(string)
+		if strings.TrimSpace(name) == "" {
+			continue
+		}
+		atk := attack{Name: name}
+		if bonus, ok := intValue(entry["bonus"]); ok {
+			atk.Bonus = signed(bonus)
+		}
+		damage, _ := entry["damage"].(string)
+		if kind, _ := entry["type"].(string); kind != "" {
+			damage = strings.TrimSpace(damage + " " + kind)
+		}
+		atk.Damage = damage
+		attacks = append(attacks, atk)
+	}
+	return attacks
+}
+
+// equipmentLines lista pc.equipment, com a quantidade e os itens equipados marcados; aceita
+// também o formato antigo de nomes soltos ({"items": [...]})
+func equipmentLines(pc *models.PC) []string {
+	list, _ := pc.Equipment.Data.([]any)
+	if wrapper, ok := pc.Equipment.Data.(map[string]any); ok {
+		list, _ = wrapper["items"].([]any)
+	}
+
+	lines := []string{}
+	for _, raw := range list {
+		switch item := raw.(type) {
+		case string:
+			if strings.TrimSpace(item) != "" {
+				lines = append(lines, item)
+			}
+		case map[string]any:
+			name, _ := item["name"].(string)
+			if strings.TrimSpace(name) == "" {
+				continue
+			}
+			if quantity, _ := intValue(item["quantity"]); quantity > 1 {
+				name = fmt.Sprintf("%s (%d)", name, quantity)
+			}
+			if equipped, _ := item["equipped"].(bool); equipped {
+				name += " [equipped]"
+			}
+			lines = append(lines, name)
+		}
+	}
+	return lines
+}
+
+// proficiencyLines lista as proficiências e idiomas gravados em abilities
+func proficiencyLines(pc *models.PC) []string {
+	abilities, _ := pc.Abilities.Data.(map[string]any)
+	lines := []string{}
+	for _, key := range []string{"proficiencies", "languages"} {
+		values, _ := abilities[key].([]any)
+		for _, raw := range values {
+			if value, ok := raw.(string); ok && strings.TrimSpace(value) != "" {
+				lines = append(lines, value)
+			}
+		}
+	}
+	return lines
+}
+
+// intValue lê um número do JSON (float64), de int ou de texto
+func intValue(value any) (int, bool) {
+	switch v := value.(type) {
+	case float64:
+		return int(v), true
+	case int:
+		return v, true
+	case string:
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		return n, err == nil
+	}
+	return 0, false
+}
+
+func signed(n int) string {
+	return fmt.Sprintf("%+d", n)
+}
+
+func capitalize(text string) string {
+	if text == "" {
+		return text
+	}
+	return strings.ToUpper(text[:1]) + text[1:]
+}
+
+// abbreviation abrevia o atributo como na ficha impressa ("Dex")
+func abbreviation(ability string) string {
+	if len(ability) < 3 {
+		return capitalize(ability)
+	}
+	return capitalize(ability[:3])
+}
+
+// ordinal escreve o círculo de magia em inglês ("1st", "2nd", "3rd", "4th"...)
+func ordinal(n int) string {
+	switch n {
+	case 1:
+		return "1st"
+	case 2:
+		return "2nd"
+	case 3:
+		return "3rd"
+	}
+	return strconv.Itoa(n) + "th"
+}
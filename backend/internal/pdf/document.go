@@ -0,0 +1,192 @@
+// Package pdf gera documentos PDF simples em Go puro, com as fontes Helvetica padrão do
+// formato (sem fontes embutidas) e texto em WinAnsiEncoding
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Tamanho da página A4 em pontos
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+// Font é uma das fontes padrão disponíveis
+type Font int
+
+const (
+	Regular Font = iota // Helvetica
+	Bold                // Helvetica-Bold
+)
+
+var fontNames = map[Font]string{Regular: "Helvetica", Bold: "Helvetica-Bold"}
+
+// Document é um PDF em construção
+type Document struct {
+	pages []*Page
+}
+
+// NewDocument cria um documento vazio
+func NewDocument() *Document {
+	return &Document{}
+}
+
+// AddPage acrescenta uma página A4 em branco
+func (d *Document) AddPage() *Page {
+	page := &Page{}
+	d.pages = append(d.pages, page)
+	return page
+}
+
+// PageCount retorna o número de páginas
+func (d *Document) PageCount() int {
+	return len(d.pages)
+}
+
+// Page é uma página do documento. As coordenadas têm origem no canto superior esquerdo e
+// são dadas em pontos; y do texto é a linha de base.
+type Page struct {
+	content bytes.Buffer
+}
+
+// Text escreve o texto a partir de x
+func (p *Page) Text(x, y float64, font Font, size float64, text string) {
+	if text == "" {
+		return
+	}
+	fmt.Fprintf(&p.content, "BT /F%d %s Tf %s %s Td (%s) Tj ET\n",
+		font+1, number(size), number(x), number(PageHeight-y), escape(encodeWinAnsi(text)))
+}
+
+// TextCenter escreve o texto centralizado em cx
+func (p *Page) TextCenter(cx, y float64, font Font, size float64, text string) {
+	p.Text(cx-TextWidth(font, size, text)/2, y, font, size, text)
+}
+
+// TextRight escreve o texto terminando em right
+func (p *Page) TextRight(right, y float64, font Font, size float64, text string) {
+	p.Text(right-TextWidth(font, size, text), y, font, size, text)
+}
+
+// Line traça uma linha com a espessura informada
+func (p *Page) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(&p.content, "%s w %s %s m %s %s l S\n",
+		number(width), number(x1), number(PageHeight-y1), number(x2), number(PageHeight-y2))
+}
+
+// Rect traça o contorno de um retângulo com canto superior esquerdo em (x, y)
+func (p *Page) Rect(x, y, w, h, lineWidth float64) {
+	fmt.Fprintf(&p.content, "%s w %s %s %s %s re S\n",
+		number(lineWidth), number(x), number(PageHeight-y-h), number(w), number(h))
+}
+
+// FillRect preenche um retângulo em tons de cinza (0 = preto, 1 = branco)
+func (p *Page) FillRect(x, y, w, h, gray float64) {
+	fmt.Fprintf(&p.content, "q %s g %s %s %s %s re f Q\n",
+		number(gray), number(x), number(PageHeight-y-h), number(w), number(h))
+}
+
+// Bytes monta o arquivo PDF: catálogo, árvore de páginas, as duas fontes e, para cada
+// página, o objeto da página e seu conteúdo comprimido
+func (d *Document) Bytes() ([]byte, error) {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	var out bytes.Buffer
+	offsets := []int{}
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Objetos 1 a 4: catálogo, páginas e fontes; depois, página e conteúdo alternados
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	for _, font := range []Font{Regular, Bold} {
+		object(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", fontNames[font]))
+	}
+
+	for i, page := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			number(PageWidth), number(PageHeight), 6+2*i))
+
+		var compressed bytes.Buffer
+		zw := zlib.NewWriter(&compressed)
+		if _, err := zw.Write(page.content.Bytes()); err != nil {
+			return nil, fmt.Errorf("failed to compress page %d: %w", i+1, err)
+		}
+		if err := zw.Close(); err != nil {
+			return nil, fmt.Errorf("failed to compress page %d: %w", i+1, err)
+		}
+		object(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", compressed.Len(), compressed.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return out.Bytes(), nil
+}
+
+// number formata coordenadas com no máximo duas casas decimais
+func number(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
+
+// escape protege parênteses e barras invertidas em strings literais do PDF
+func escape(text string) string {
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		switch c := text[i]; c {
+		case '(', ')', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// winAnsiExtras são os caracteres do WinAnsiEncoding fora do Latin-1
+var winAnsiExtras = map[rune]byte{
+	'€': 0x80, '‚': 0x82, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87, '‰': 0x89,
+	'‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '™': 0x99,
+}
+
+// encodeWinAnsi converte o texto UTF-8 para WinAnsiEncoding; caracteres sem equivalente
+// viram "?" e quebras de linha viram espaço
+func encodeWinAnsi(text string) string {
+	out := make([]byte, 0, len(text))
+	for _, r := range text {
+		switch {
+		case r == '\n' || r == '\r' || r == '\t':
+			out = append(out, ' ')
+		case r >= 0x20 && r < 0x7f, r >= 0xa0 && r <= 0xff:
+			out = append(out, byte(r))
+		default:
+			if b, ok := winAnsiExtras[r]; ok {
+				out = append(out, b)
+			} else {
+				out = append(out, '?')
+			}
+		}
+	}
+	return string(out)
+}
@@ -0,0 +1,71 @@
+package pdf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/lib/pq"
+
+	"rpg-saas-backend/internal/models"
+)
+
+func TestDocumentBytes(t *testing.T) {
+	doc := NewDocument()
+	doc.AddPage().Text(36, 50, Bold, 12, "Ação (teste)")
+	doc.AddPage()
+
+	data, err := doc.Bytes()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.HasPrefix(data, []byte("%PDF-1.4")) || !bytes.HasSuffix(data, []byte("%%EOF\n")) {
+		t.Fatalf("invalid PDF envelope")
+	}
+	if !bytes.Contains(data, []byte("/Count 2")) {
+		t.Fatalf("expected 2 pages in the page tree")
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	lines := WrapText(Regular, 10, "Darkvision lets you see in dim light within 60 feet\nas if it were bright light", 120)
+	if len(lines) < 3 {
+		t.Fatalf("expected wrapped lines, got %q", lines)
+	}
+	for _, line := range lines {
+		if TextWidth(Regular, 10, line) > 120 {
+			t.Fatalf("line %q exceeds the width", line)
+		}
+	}
+	if got := Truncate(Bold, 10, strings.Repeat("Longsword ", 10), 60); TextWidth(Bold, 10, got) > 60 || !strings.HasSuffix(got, "…") {
+		t.Fatalf("unexpected truncation: %q", got)
+	}
+}
+
+func TestCharacterSheet(t *testing.T) {
+	pc := &models.PC{
+		Name: "Brom", Level: 3, Race: "Dwarf", Class: "Cleric", HP: 27, CA: 16,
+		Attacks: models.JSONBFlexible{Data: []any{map[string]any{"name": "Warhammer", "bonus": float64(4), "damage": "1d8+2", "type": "bludgeoning"}}},
+		Spells: models.JSONBFlexible{Data: map[string]any{
+			"spell_slots":  map[string]any{"1": map[string]any{"total": float64(4), "used": float64(1)}},
+			"known_spells": []any{map[string]any{"name": "Sacred Flame", "level": float64(0)}, map[string]any{"name": "Bless", "level": float64(1)}},
+		}},
+		Features: pq.StringArray{"Darkvision", "Channel Divinity"},
+		Ideals:   "Faith",
+	}
+	sheet := models.ComputedSheet{Spellcasting: &models.SpellcastingStats{Ability: "wisdom", SaveDC: 13, AttackBonus: 5}}
+
+	data, err := CharacterSheet(pc, sheet)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Características, magias e personalidade vão para a segunda página
+	if !bytes.Contains(data, []byte("/Count 2")) {
+		t.Fatalf("expected a two-page sheet")
+	}
+
+	data, err = CharacterSheet(&models.PC{Name: "Empty", Level: 1}, models.ComputedSheet{})
+	if err != nil || !bytes.Contains(data, []byte("/Count 1")) {
+		t.Fatalf("expected a one-page sheet, err=%v", err)
+	}
+}
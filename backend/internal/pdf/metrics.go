@@ -0,0 +1,100 @@
+package pdf
+
+import (
+	"strings"
+)
+
+// Larguras dos caracteres ASCII 32 a 126 em milésimos do tamanho da fonte (métricas AFM)
+var (
+	helveticaWidths = [95]int{
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	}
+	helveticaBoldWidths = [95]int{
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	}
+)
+
+// TextWidth mede o texto em pontos. Caracteres fora do ASCII (acentuados, aspas
+// tipográficas) usam a largura média de uma letra.
+func TextWidth(font Font, size float64, text string) float64 {
+	widths := &helveticaWidths
+	if font == Bold {
+		widths = &helveticaBoldWidths
+	}
+
+	total := 0
+	for _, r := range text {
+		if r >= 32 && r <= 126 {
+			total += widths[r-32]
+		} else {
+			total += 556
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// WrapText quebra o texto em linhas que cabem na largura, respeitando as quebras de linha
+// do texto. Palavras maiores que a largura são cortadas.
+func WrapText(font Font, size float64, text string, width float64) []string {
+	lines := []string{}
+	for _, paragraph := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			for TextWidth(font, size, word) > width {
+				cut := fitPrefix(font, size, word, width)
+				if line != "" {
+					lines = append(lines, line)
+					line = ""
+				}
+				lines = append(lines, word[:cut])
+				word = word[cut:]
+			}
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if TextWidth(font, size, candidate) <= width {
+				line = candidate
+				continue
+			}
+			lines = append(lines, line)
+			line = word
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// Truncate encurta o texto com reticências para caber na largura
+func Truncate(font Font, size float64, text string, width float64) string {
+	if TextWidth(font, size, text) <= width {
+		return text
+	}
+	const ellipsis = "…"
+	cut := fitPrefix(font, size, text, width-TextWidth(font, size, ellipsis))
+	return strings.TrimSpace(text[:cut]) + ellipsis
+}
+
+// fitPrefix retorna quantos bytes do início do texto cabem na largura, sem partir um
+// caractere UTF-8 (ao menos um caractere)
+func fitPrefix(font Font, size float64, text string, width float64) int {
+	end := 0
+	for i, r := range text {
+		next := i + len(string(r))
+		if end > 0 && TextWidth(font, size, text[:next]) > width {
+			break
+		}
+		end = next
+	}
+	return end
+}
@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"time"
 )
@@ -26,6 +28,27 @@ func NewClient(baseURL string, timeout time.Duration) *Client {
 	}
 }
 
+// APIError é a resposta do serviço Python com status diferente de 200
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API returned non-200 status: %d, body: %s", e.StatusCode, e.Body)
+}
+
+// IsUnavailable indica se o erro é de indisponibilidade do serviço: falha de conexão,
+// timeout ou resposta 5xx. Respostas 4xx e corpos inválidos não contam.
+func IsUnavailable(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= http.StatusInternalServerError
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded)
+}
+
 // makeRequest é um método auxiliar para fazer requisições HTTP
 func (c *Client) makeRequest(ctx context.Context, method, endpoint string, body interface{}, response interface{}) error {
 	// Prepara o corpo da requisição, se houver
@@ -60,7 +83,7 @@ func (c *Client) makeRequest(ctx context.Context, method, endpoint string, body
 	if resp.StatusCode != http.StatusOK {
 		bodyBytes, _ := io.ReadAll(resp.Body)
 		fmt.Printf("Erro da API (%s): %s\n", url, string(bodyBytes))
-		return fmt.Errorf("API returned non-200 status: %d, body: %s", resp.StatusCode, string(bodyBytes))
+		return &APIError{StatusCode: resp.StatusCode, Body: string(bodyBytes)}
 	}
 
 	// Decodifica a resposta
@@ -92,6 +92,49 @@ func TestGenerateNPC(t *testing.T) {
 	}
 }
 
+func TestGeneratePC(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/generate-pc" {
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+
+		var req PCRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if req.Level != 3 || req.Race != "" || req.PlayerName != "Ana" {
+			t.Fatalf("unexpected request: %+v", req)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"name":"Elf Wizard","level":3,"race":"Elf","class":"Wizard","background":"Sage",
+			"attributes":{"intelligence":16},"abilities":["Arcane Recovery"],
+			"equipment":["Quarterstaff","Dagger (2)"],"hp":14,"ac":12,
+			"spells":{"level_1":["Magic Missile"],"level_0":["Light"]},"player_name":"Ana"
+		}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL, time.Second)
+	pc, err := client.GeneratePC(context.Background(), models.GeneratePCRequest{Level: 3, Race: "elf", PlayerName: "Ana"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if pc.Name != "Elf Wizard" || pc.HP != 14 || pc.CA != 12 || pc.PlayerName != "Ana" || len(pc.Features) != 1 {
+		t.Fatalf("unexpected pc: %+v", pc)
+	}
+	items := pc.Equipment.Data.([]any)
+	if dagger := items[1].(map[string]any); dagger["name"] != "Dagger" || dagger["quantity"] != 2 {
+		t.Fatalf("unexpected equipment: %v", items)
+	}
+	known := pc.Spells.Data.(map[string]any)["known_spells"].([]any)
+	if len(known) != 2 || known[0].(map[string]any)["name"] != "Light" {
+		t.Fatalf("unexpected known spells: %v", known)
+	}
+}
+
 func TestGenerateEncounter(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/generate-encounter" {
@@ -207,3 +250,30 @@ func TestGenerateTreasure(t *testing.T) {
 		t.Fatalf("unexpected items: %+v", hoard.Items)
 	}
 }
+
+func TestIsUnavailable(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/slow":
+			time.Sleep(50 * time.Millisecond)
+		case "/bad-request":
+			w.WriteHeader(http.StatusBadRequest)
+		default:
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	client := NewClient(srv.URL, 10*time.Millisecond)
+
+	cases := map[string]bool{"/slow": true, "/bad-request": false, "/down": true}
+	for endpoint, want := range cases {
+		err := client.makeRequest(context.Background(), http.MethodGet, endpoint, nil, nil)
+		if got := IsUnavailable(err); got != want {
+			t.Fatalf("%s: expected unavailable=%v, got %v (%v)", endpoint, want, got, err)
+		}
+	}
+
+	srv.Close()
+	if err := client.makeRequest(context.Background(), http.MethodGet, "/", nil, nil); !IsUnavailable(err) {
+		t.Fatalf("expected a connection error to count as unavailable, got %v", err)
+	}
+}
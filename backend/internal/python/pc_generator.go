@@ -0,0 +1,117 @@
+package python
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"rpg-saas-backend/internal/models"
+)
+
+// PCRequest define o corpo da requisição de geração de PC ao serviço Python
+type PCRequest struct {
+	Level            int    `json:"level"`
+	AttributesMethod string `json:"attributes_method,omitempty"`
+	Manual           bool   `json:"manual"`
+	Race             string `json:"race,omitempty"`
+	Class            string `json:"class,omitempty"`
+	Background       string `json:"background,omitempty"`
+	PlayerName       string `json:"player_name,omitempty"`
+}
+
+// PCResponse é a ficha devolvida pelo serviço; magias vêm agrupadas por círculo ("level_0", "level_1"...)
+type PCResponse struct {
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	Level       int                 `json:"level"`
+	Race        string              `json:"race"`
+	Class       string              `json:"class"`
+	Background  string              `json:"background"`
+	Attributes  map[string]int      `json:"attributes"`
+	Abilities   []string            `json:"abilities"`
+	Equipment   []string            `json:"equipment"`
+	HP          int                 `json:"hp"`
+	CA          int                 `json:"ac"`
+	Spells      map[string][]string `json:"spells,omitempty"`
+	PlayerName  string              `json:"player_name,omitempty"`
+}
+
+// equipmentQuantity separa itens no formato "Handaxe (2)"
+var equipmentQuantity = regexp.MustCompile(`^(.+?)\s*\((\d+)\)$`)
+
+// GeneratePC chama o serviço Python para gerar um PC e converte a resposta para o formato da ficha
+func (c *Client) GeneratePC(ctx context.Context, request models.GeneratePCRequest) (*models.PC, error) {
+	body := PCRequest{
+		Level:            request.Level,
+		AttributesMethod: request.AttributesMethod,
+		Manual:           request.Manual,
+		PlayerName:       request.PlayerName,
+	}
+	if request.Manual {
+		body.Race = request.Race
+		body.Class = request.Class
+		body.Background = request.Background
+	}
+
+	var response PCResponse
+	if err := c.makeRequest(ctx, http.MethodPost, "/generate-pc", body, &response); err != nil {
+		return nil, fmt.Errorf("failed to generate PC: %w", err)
+	}
+
+	attributes := map[string]any{}
+	for k, v := range response.Attributes {
+		attributes[k] = v
+	}
+
+	items := make([]any, 0, len(response.Equipment))
+	for _, entry := range response.Equipment {
+		name, quantity := entry, 1
+		if m := equipmentQuantity.FindStringSubmatch(entry); m != nil {
+			name = m[1]
+			quantity, _ = strconv.Atoi(m[2])
+		}
+		items = append(items, map[string]any{"name": name, "quantity": quantity, "equipped": false, "description": ""})
+	}
+
+	// Ordena os círculos para que a lista de magias conhecidas seja estável
+	levels := make([]string, 0, len(response.Spells))
+	for key := range response.Spells {
+		levels = append(levels, key)
+	}
+	sort.Strings(levels)
+
+	known := []any{}
+	for _, key := range levels {
+		level, err := strconv.Atoi(strings.TrimPrefix(key, "level_"))
+		if err != nil {
+			continue
+		}
+		for _, name := range response.Spells[key] {
+			known = append(known, map[string]any{"name": name, "level": level, "school": "", "prepared": level == 0})
+		}
+	}
+
+	pc := &models.PC{
+		Name:        response.Name,
+		Description: response.Description,
+		Level:       response.Level,
+		Race:        response.Race,
+		Class:       response.Class,
+		Background:  response.Background,
+		Attributes:  models.JSONBFlexible{Data: attributes},
+		Abilities:   models.JSONBFlexible{Data: map[string]any{}},
+		Equipment:   models.JSONBFlexible{Data: items},
+		HP:          response.HP,
+		CA:          response.CA,
+		Spells:      models.JSONBFlexible{Data: map[string]any{"spell_slots": map[string]any{}, "known_spells": known}},
+		Features:    response.Abilities,
+		PlayerName:  response.PlayerName,
+	}
+	pc.ProficiencyBonus = pc.GetProficiencyBonus()
+
+	return pc, nil
+}
@@ -18,9 +18,10 @@ type PaginatedResponse struct {
 
 // ErrorResponse represents an error API response
 type ErrorResponse struct {
-	Error   string `json:"error"`
-	Code    string `json:"code,omitempty"`
-	Details string `json:"details,omitempty"`
+	Error   string           `json:"error"`
+	Code    string           `json:"code,omitempty"`
+	Details string           `json:"details,omitempty"`
+	Fields  ValidationErrors `json:"fields,omitempty"` // Per-field validation errors
 }
 
 // SuccessResponse represents a success API response
@@ -132,6 +133,15 @@ func (rh *ResponseHandler) SendValidationError(w http.ResponseWriter, message st
 	rh.SendError(w, message, http.StatusUnprocessableEntity)
 }
 
+// SendValidationErrors sends a 422 Unprocessable Entity error listing each invalid field
+func (rh *ResponseHandler) SendValidationErrors(w http.ResponseWriter, errs ValidationErrors) {
+	rh.SendJSON(w, ErrorResponse{
+		Error:  errs.Error(),
+		Code:   "validation_failed",
+		Fields: errs,
+	}, http.StatusUnprocessableEntity)
+}
+
 // HandleDBError handles database errors and sends appropriate HTTP response
 func (rh *ResponseHandler) HandleDBError(w http.ResponseWriter, err error, operation string) {
 	log.Printf("Database error during %s: %v", operation, err)
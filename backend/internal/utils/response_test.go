@@ -111,6 +111,25 @@ func TestResponseHandlerErrors(t *testing.T) {
 	}
 }
 
+func TestSendValidationErrors(t *testing.T) {
+	rh := NewResponseHandler()
+	rr := httptest.NewRecorder()
+
+	rh.SendValidationErrors(rr, ValidationErrors{
+		{Field: "name", Message: "name is required", Code: "required"},
+		{Field: "level", Message: "level must be between 1 and 20", Code: "invalid_range"},
+	})
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d", rr.Code)
+	}
+
+	var body ErrorResponse
+	decodeBody(t, rr, &body)
+	if body.Code != "validation_failed" || len(body.Fields) != 2 || body.Fields[1].Field != "level" {
+		t.Fatalf("unexpected body: %+v", body)
+	}
+}
+
 func TestHandleDBError(t *testing.T) {
 	rh := NewResponseHandler()
 
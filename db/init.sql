@@ -13,6 +13,24 @@ DROP VIEW IF EXISTS v_dnd_spells_full CASCADE;
 DROP VIEW IF EXISTS v_dnd_class_features CASCADE;
 DROP VIEW IF EXISTS v_dnd_subraces_with_races CASCADE;
 
+DROP TABLE IF EXISTS ability_score_rolls CASCADE;
+DROP TABLE IF EXISTS campaign_activity CASCADE;
+DROP TABLE IF EXISTS character_deaths CASCADE;
+DROP TABLE IF EXISTS room_members CASCADE;
+DROP TABLE IF EXISTS rooms CASCADE;
+DROP TABLE IF EXISTS character_versions CASCADE;
+DROP TABLE IF EXISTS campaign_loot_log CASCADE;
+DROP TABLE IF EXISTS campaign_purses CASCADE;
+DROP TABLE IF EXISTS campaign_inventory_items CASCADE;
+DROP TABLE IF EXISTS campaign_homebrew_whitelist CASCADE;
+DROP TABLE IF EXISTS campaign_templates CASCADE;
+DROP TABLE IF EXISTS campaign_invites CASCADE;
+DROP TABLE IF EXISTS quest_objectives CASCADE;
+DROP TABLE IF EXISTS quests CASCADE;
+DROP TABLE IF EXISTS campaign_wiki_entries CASCADE;
+DROP TABLE IF EXISTS session_attendance CASCADE;
+DROP TABLE IF EXISTS campaign_sessions CASCADE;
+DROP TABLE IF EXISTS dice_macros CASCADE;
 DROP TABLE IF EXISTS campaign_characters CASCADE;
 DROP TABLE IF EXISTS campaign_players CASCADE;
 DROP TABLE IF EXISTS campaigns CASCADE;
@@ -128,9 +146,10 @@ CREATE TABLE dnd_classes (
     hit_die INTEGER,
     proficiencies JSONB,
     saving_throws TEXT[],
+    proficiency_choices JSONB, -- [{choose, from: {options: [{item: {index: "skill-..."}}]}}]
     spellcasting JSONB,
     spellcasting_ability VARCHAR(20),
-    class_levels JSONB,
+    class_levels JSONB, -- [{level, prof_bonus, ability_score_bonuses, spellcasting}] de /classes/{index}/levels
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     api_version VARCHAR(20) DEFAULT '2014'
@@ -515,6 +534,7 @@ CREATE TABLE campaigns (
     status VARCHAR(20) DEFAULT 'planning', -- planning, active, paused, completed
     invite_code VARCHAR(10) UNIQUE NOT NULL,
     allow_homebrew BOOLEAN NOT NULL DEFAULT TRUE,
+    leveling_mode VARCHAR(20) NOT NULL DEFAULT 'xp' CHECK (leveling_mode IN ('xp', 'milestone')),
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
@@ -525,7 +545,8 @@ CREATE TABLE campaign_players (
     campaign_id INTEGER NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
     user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
     joined_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    status VARCHAR(20) DEFAULT 'active', -- active, inactive, removed
+    status VARCHAR(20) DEFAULT 'active', -- active, pending, waitlisted, inactive, removed, banned
+    role VARCHAR(20) NOT NULL DEFAULT 'player', -- player, co_dm (edita, mas não exclui)
     UNIQUE(campaign_id, user_id)
 );
 
@@ -536,7 +557,8 @@ CREATE TABLE pcs (
     description TEXT,
     level INTEGER NOT NULL DEFAULT 1,
     race VARCHAR(100),
-    class VARCHAR(100),
+    class VARCHAR(100), -- resumo de class_levels ("Fighter 3 / Wizard 2")
+    class_levels JSONB DEFAULT '[]', -- [{class, level, subclass}], na ordem em que foram adquiridas
     background VARCHAR(100),
     alignment VARCHAR(50),
     attributes JSONB,
@@ -572,7 +594,8 @@ CREATE TABLE campaign_characters (
     description TEXT,
     level INTEGER NOT NULL DEFAULT 1,
     race VARCHAR(100),
-    class VARCHAR(100),
+    class VARCHAR(100), -- resumo de class_levels ("Fighter 3 / Wizard 2")
+    class_levels JSONB DEFAULT '[]', -- [{class, level, subclass}], na ordem em que foram adquiridas
     background VARCHAR(100),
     alignment VARCHAR(50),
     attributes JSONB,
@@ -592,10 +615,15 @@ CREATE TABLE campaign_characters (
     flaws TEXT DEFAULT '',
     features TEXT[] DEFAULT '{}',
     player_name VARCHAR(100),
-    status VARCHAR(20) DEFAULT 'active', -- active, inactive, dead, retired, removed
+    status VARCHAR(20) DEFAULT 'active', -- active, inactive, unconscious, dead, resurrected, retired
     joined_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     last_sync TIMESTAMP NULL,
+    sync_base JSONB NULL, -- estado compartilhado no último sync (detecção de conflitos)
     campaign_notes TEXT,
+    experience_points INTEGER NOT NULL DEFAULT 0 CHECK (experience_points >= 0),
+    level_up_available BOOLEAN NOT NULL DEFAULT FALSE, -- atingiu o limiar de XP ou recebeu um marco
+    frozen_at TIMESTAMP NULL, -- snapshot congelado ao concluir a campanha
     UNIQUE(campaign_id, source_pc_id)
 );
 
@@ -615,7 +643,8 @@ CREATE TABLE npcs (
     ca INTEGER,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     is_homebrew BOOLEAN DEFAULT FALSE,
-    campaign_id INTEGER REFERENCES campaigns(id) ON DELETE SET NULL
+    campaign_id INTEGER REFERENCES campaigns(id) ON DELETE CASCADE,
+    revealed BOOLEAN NOT NULL DEFAULT FALSE -- visível aos players da campanha
 );
 
 -- ENCOUNTERS
@@ -626,7 +655,9 @@ CREATE TABLE encounters (
     total_xp INTEGER,
     player_level INTEGER,
     player_count INTEGER,
-    campaign_id INTEGER REFERENCES campaigns(id) ON DELETE SET NULL,
+    campaign_id INTEGER REFERENCES campaigns(id) ON DELETE CASCADE,
+    revealed BOOLEAN NOT NULL DEFAULT FALSE, -- visível aos players da campanha
+    xp_awarded BOOLEAN NOT NULL DEFAULT FALSE, -- XP já distribuído aos personagens
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
@@ -645,6 +676,7 @@ CREATE TABLE treasures (
     level INTEGER DEFAULT 1,
     name VARCHAR(100) NOT NULL,
     total_value INTEGER DEFAULT 0,
+    campaign_id INTEGER REFERENCES campaigns(id) ON DELETE SET NULL, -- atribuído ao inventário do grupo
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
@@ -678,6 +710,237 @@ CREATE TABLE maps (
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
+-- MACROS DE DADOS (por usuário ou por PC)
+CREATE TABLE dice_macros (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    pc_id INTEGER REFERENCES pcs(id) ON DELETE CASCADE, -- NULL = macro do usuário
+    name VARCHAR(100) NOT NULL,
+    notation VARCHAR(50) NOT NULL,
+    label VARCHAR(255) DEFAULT '',
+    advantage BOOLEAN NOT NULL DEFAULT FALSE,
+    disadvantage BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- SESSÕES DE CAMPANHA
+CREATE TABLE campaign_sessions (
+    id SERIAL PRIMARY KEY,
+    campaign_id INTEGER NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    session_number INTEGER NOT NULL,
+    title VARCHAR(255) DEFAULT '',
+    scheduled_at TIMESTAMP NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'scheduled', -- scheduled, in_progress, completed, cancelled
+    room_id VARCHAR(64) NULL, -- sala da campanha usada na sessão
+    started_at TIMESTAMP NULL,
+    ended_at TIMESTAMP NULL,
+    dm_notes TEXT DEFAULT '',
+    recap TEXT DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(campaign_id, session_number)
+);
+
+-- PRESENÇA E NOTAS DOS JOGADORES POR SESSÃO
+CREATE TABLE session_attendance (
+    session_id INTEGER NOT NULL REFERENCES campaign_sessions(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, confirmed, declined, attended, absent
+    notes TEXT DEFAULT '',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (session_id, user_id)
+);
+
+-- WIKI / DIÁRIO DA CAMPANHA (locais, facções, lore e itens)
+CREATE TABLE campaign_wiki_entries (
+    id SERIAL PRIMARY KEY,
+    campaign_id INTEGER NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    entry_type VARCHAR(20) NOT NULL, -- location, faction, lore, item
+    title VARCHAR(255) NOT NULL,
+    body TEXT DEFAULT '', -- Markdown
+    tags TEXT[] DEFAULT '{}',
+    visibility VARCHAR(20) NOT NULL DEFAULT 'dm_only', -- dm_only, all, selected
+    selected_players INTEGER[] DEFAULT '{}', -- user_ids quando visibility = selected
+    linked_entry_ids INTEGER[] DEFAULT '{}', -- links para outras entradas da mesma campanha
+    created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- QUESTS DA CAMPANHA
+CREATE TABLE quests (
+    id SERIAL PRIMARY KEY,
+    campaign_id INTEGER NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    title VARCHAR(255) NOT NULL,
+    description TEXT DEFAULT '',
+    giver_npc_id INTEGER REFERENCES npcs(id) ON DELETE SET NULL, -- quest giver cadastrado como NPC
+    giver_name VARCHAR(255) DEFAULT '', -- quest giver livre (quando não há NPC)
+    status VARCHAR(20) NOT NULL DEFAULT 'active', -- active, completed, failed
+    revealed BOOLEAN NOT NULL DEFAULT FALSE, -- players só veem quests reveladas
+    rewards JSONB DEFAULT '{}', -- ex: {"xp": 300, "gold": 50, "items": ["..."]}
+    completed_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- OBJETIVOS DAS QUESTS
+CREATE TABLE quest_objectives (
+    id SERIAL PRIMARY KEY,
+    quest_id INTEGER NOT NULL REFERENCES quests(id) ON DELETE CASCADE,
+    description TEXT NOT NULL,
+    completed BOOLEAN NOT NULL DEFAULT FALSE,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    completed_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- CONVITES DE CAMPANHA (além do invite_code permanente da campanha)
+CREATE TABLE campaign_invites (
+    id SERIAL PRIMARY KEY,
+    campaign_id INTEGER NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    code VARCHAR(20) UNIQUE NOT NULL,
+    created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    expires_at TIMESTAMP NULL, -- NULL = não expira
+    max_uses INTEGER NOT NULL DEFAULT 0, -- 0 = ilimitado
+    uses INTEGER NOT NULL DEFAULT 0,
+    requires_approval BOOLEAN NOT NULL DEFAULT FALSE, -- cria campaign_players pendente
+    allow_waitlist BOOLEAN NOT NULL DEFAULT FALSE, -- campanha cheia entra na lista de espera
+    revoked BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- TEMPLATES DE CAMPANHA (pacote de exportação sem jogadores nem personagens)
+CREATE TABLE campaign_templates (
+    id SERIAL PRIMARY KEY,
+    owner_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    source_campaign_id INTEGER REFERENCES campaigns(id) ON DELETE SET NULL,
+    archive JSONB NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- HOMEBREW APROVADO PELO DM (vazio = qualquer homebrew, quando allow_homebrew)
+CREATE TABLE campaign_homebrew_whitelist (
+    campaign_id INTEGER NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    content_type VARCHAR(20) NOT NULL CHECK (content_type IN ('race', 'class', 'background')),
+    content_id INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (campaign_id, content_type, content_id)
+);
+
+-- INVENTÁRIO DO GRUPO (itens ainda não entregues a nenhum personagem)
+CREATE TABLE campaign_inventory_items (
+    id SERIAL PRIMARY KEY,
+    campaign_id INTEGER NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    treasure_id INTEGER REFERENCES treasures(id) ON DELETE SET NULL,
+    name VARCHAR(100) NOT NULL,
+    type VARCHAR(50) NOT NULL DEFAULT '',
+    category VARCHAR(50) NOT NULL DEFAULT '',
+    value DECIMAL(12,2) DEFAULT 0,
+    rank VARCHAR(20) NOT NULL DEFAULT '',
+    quantity INTEGER NOT NULL DEFAULT 1 CHECK (quantity >= 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- BOLSA DE MOEDAS DO GRUPO
+CREATE TABLE campaign_purses (
+    campaign_id INTEGER PRIMARY KEY REFERENCES campaigns(id) ON DELETE CASCADE,
+    coins JSONB NOT NULL DEFAULT '{}', -- {"gp": 120, "sp": 30}
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- HISTÓRICO DE SAQUE (tesouros atribuídos e quem recebeu o quê)
+CREATE TABLE campaign_loot_log (
+    id SERIAL PRIMARY KEY,
+    campaign_id INTEGER NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    action VARCHAR(20) NOT NULL CHECK (action IN ('assigned', 'distributed')),
+    treasure_id INTEGER REFERENCES treasures(id) ON DELETE SET NULL,
+    character_id INTEGER REFERENCES campaign_characters(id) ON DELETE SET NULL,
+    character_name VARCHAR(100) NOT NULL DEFAULT '', -- preservado se o personagem sair
+    item_name VARCHAR(100) NOT NULL DEFAULT '',
+    quantity INTEGER NOT NULL DEFAULT 0,
+    coins JSONB NOT NULL DEFAULT '{}',
+    performed_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- HISTÓRICO DE VERSÕES DE PERSONAGENS (pcs e campaign_characters)
+-- character_id não tem FK por ser polimórfico; o histórico sobrevive à exclusão do PC
+CREATE TABLE character_versions (
+    id SERIAL PRIMARY KEY,
+    character_type VARCHAR(20) NOT NULL CHECK (character_type IN ('pc', 'campaign_character')),
+    character_id INTEGER NOT NULL,
+    campaign_id INTEGER REFERENCES campaigns(id) ON DELETE CASCADE, -- só para campaign_character
+    version INTEGER NOT NULL,
+    action VARCHAR(20) NOT NULL, -- create, update, sync, revert, loot, xp, retire, status, import, freeze, level_up
+    author_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    character_name VARCHAR(100) NOT NULL DEFAULT '',
+    snapshot JSONB NOT NULL,
+    changes JSONB NOT NULL DEFAULT '[]', -- diff por campo para a versão anterior
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(character_type, character_id, version)
+);
+
+-- MORTES DE PERSONAGENS (registradas ao marcar o status dead)
+CREATE TABLE character_deaths (
+    id SERIAL PRIMARY KEY,
+    character_id INTEGER NOT NULL REFERENCES campaign_characters(id) ON DELETE CASCADE,
+    campaign_id INTEGER NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    character_name VARCHAR(100) NOT NULL DEFAULT '',
+    level INTEGER NOT NULL DEFAULT 1,
+    session_id INTEGER REFERENCES campaign_sessions(id) ON DELETE SET NULL,
+    cause TEXT NOT NULL DEFAULT '',
+    recorded_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- SALAS DE JOGO (websocket: presença, chat, cena e dados)
+CREATE TABLE rooms (
+    id VARCHAR(64) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    owner_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    campaign_id INTEGER REFERENCES campaigns(id) ON DELETE CASCADE,
+    scene_state JSONB DEFAULT '{}',
+    metadata JSONB DEFAULT '{}',
+    archived_at TIMESTAMP NULL, -- arquivada ao concluir a campanha (somente leitura)
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE room_members (
+    room_id VARCHAR(64) NOT NULL REFERENCES rooms(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL DEFAULT 'player', -- gm (dono da sala), player
+    joined_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (room_id, user_id)
+);
+
+-- FEED DE ATIVIDADE DA CAMPANHA (append-only; gravado pelos handlers)
+CREATE TABLE campaign_activity (
+    id SERIAL PRIMARY KEY,
+    campaign_id INTEGER NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    actor_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    event_type VARCHAR(50) NOT NULL, -- categoria.ação: player.joined, character.hp_changed, room.scene_updated...
+    target_type VARCHAR(20) NOT NULL DEFAULT '', -- player, invite, character, room, campaign
+    target_id VARCHAR(64) NOT NULL DEFAULT '',
+    summary TEXT NOT NULL DEFAULT '',
+    metadata JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- ROLAGENS DE ATRIBUTOS DO CONSTRUTOR (4d6 descartando o menor, feitas no servidor)
+CREATE TABLE ability_score_rolls (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    dice INTEGER[] NOT NULL, -- 6 grupos de 4 dados, em ordem
+    scores INTEGER[] NOT NULL, -- soma dos 3 maiores de cada grupo
+    pc_id INTEGER REFERENCES pcs(id) ON DELETE SET NULL, -- PC criado com a rolagem
+    used_at TIMESTAMP, -- cada rolagem cria um único PC
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
 -- =====================================================================
 -- =========================== 6. ÍNDICES ==============================
 -- =====================================================================
@@ -774,6 +1037,34 @@ CREATE INDEX idx_items_hoard_id ON items(hoard_id);
 CREATE INDEX idx_items_type ON items(type);
 CREATE INDEX idx_items_category ON items(category);
 
+CREATE INDEX idx_dice_macros_user ON dice_macros(user_id);
+CREATE INDEX idx_dice_macros_pc ON dice_macros(pc_id);
+CREATE UNIQUE INDEX idx_dice_macros_unique_name ON dice_macros(user_id, COALESCE(pc_id, 0), LOWER(name));
+
+CREATE INDEX idx_campaign_sessions_campaign ON campaign_sessions(campaign_id);
+CREATE INDEX idx_campaign_sessions_status ON campaign_sessions(campaign_id, status);
+
+CREATE INDEX idx_campaign_wiki_campaign ON campaign_wiki_entries(campaign_id, entry_type);
+CREATE INDEX idx_campaign_wiki_tags ON campaign_wiki_entries USING GIN(tags);
+CREATE INDEX idx_campaign_wiki_links ON campaign_wiki_entries USING GIN(linked_entry_ids);
+
+CREATE INDEX idx_quests_campaign ON quests(campaign_id, status);
+CREATE INDEX idx_quest_objectives_quest ON quest_objectives(quest_id, sort_order);
+
+CREATE INDEX idx_campaign_invites_campaign ON campaign_invites(campaign_id);
+
+CREATE INDEX idx_campaign_templates_owner ON campaign_templates(owner_id);
+
+CREATE INDEX idx_campaign_inventory_items_campaign ON campaign_inventory_items(campaign_id);
+CREATE INDEX idx_campaign_loot_log_campaign ON campaign_loot_log(campaign_id, created_at DESC);
+CREATE INDEX idx_treasures_campaign_id ON treasures(campaign_id);
+CREATE INDEX idx_character_versions_campaign ON character_versions(campaign_id, created_at DESC);
+CREATE INDEX idx_character_deaths_campaign ON character_deaths(campaign_id, created_at DESC);
+CREATE INDEX idx_rooms_campaign ON rooms(campaign_id, created_at DESC);
+CREATE INDEX idx_campaign_activity_campaign ON campaign_activity(campaign_id, created_at DESC);
+CREATE INDEX idx_campaign_activity_type ON campaign_activity(campaign_id, event_type);
+CREATE INDEX idx_ability_score_rolls_user ON ability_score_rolls(user_id, created_at DESC);
+
 -- MAPS
 -- (se quiser buscas por nome)
 CREATE INDEX idx_maps_name ON maps(name);